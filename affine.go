@@ -0,0 +1,123 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+// Affine is a 2D affine transformation of the coefficients [a, b, c, d, e, f], mapping
+// the point (x, y) to (a*x + b*y + c, d*x + e*y + f).
+type Affine [6]float64
+
+// Identity is the affine transformation leaving points unchanged.
+var Identity = Affine{1, 0, 0, 0, 1, 0}
+
+// FitAffine computes the affine transformation mapping the src control points to the dst ones.
+// Two control points define a similarity (translation, rotation and uniform scale), while
+// three or more define a general affine transformation, fitted by least squares.
+func FitAffine(src []geometry.Point, dst []geometry.Point) (Affine, error) {
+	if len(src) != len(dst) {
+		return Affine{}, fmt.Errorf("control points mismatch: %d source and %d destination points", len(src), len(dst))
+	}
+
+	switch len(src) {
+	case 0, 1:
+		return Affine{}, errors.New("at least two control points are required")
+	case 2:
+		return fitSimilarity(src, dst)
+	}
+
+	// normal equations of the least squares fit, shared by both output coordinates
+	var n [3][3]float64
+	var bx, by [3]float64
+	for i, p := range src {
+		row := [3]float64{p.X, p.Y, 1}
+		for j := range row {
+			for k := range row {
+				n[j][k] += row[j] * row[k]
+			}
+			bx[j] += row[j] * dst[i].X
+			by[j] += row[j] * dst[i].Y
+		}
+	}
+
+	abc, ok := solve3(n, bx)
+	if !ok {
+		return Affine{}, errors.New("control points must not be collinear")
+	}
+	def, _ := solve3(n, by)
+
+	return Affine{abc[0], abc[1], abc[2], def[0], def[1], def[2]}, nil
+}
+
+// fitSimilarity computes the similarity mapping two control points.
+func fitSimilarity(src []geometry.Point, dst []geometry.Point) (Affine, error) {
+	sx, sy := src[1].X-src[0].X, src[1].Y-src[0].Y
+	dx, dy := dst[1].X-dst[0].X, dst[1].Y-dst[0].Y
+
+	l := sx*sx + sy*sy
+	if l == 0 {
+		return Affine{}, errors.New("control points must not coincide")
+	}
+
+	// complex division (dx + i dy) / (sx + i sy) gives the scaled rotation
+	a := (dx*sx + dy*sy) / l
+	b := (dy*sx - dx*sy) / l
+
+	return Affine{
+		a, -b, dst[0].X - (a*src[0].X - b*src[0].Y),
+		b, a, dst[0].Y - (b*src[0].X + a*src[0].Y),
+	}, nil
+}
+
+// solve3 solves a 3x3 linear system by Cramer's rule.
+func solve3(m [3][3]float64, b [3]float64) ([3]float64, bool) {
+	det := func(m [3][3]float64) float64 {
+		return m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+			m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+			m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+	}
+
+	d := det(m)
+	if math.Abs(d) < 1e-12 {
+		return [3]float64{}, false
+	}
+
+	var x [3]float64
+	for i := range x {
+		mi := m
+		for r := range mi {
+			mi[r][i] = b[r]
+		}
+		x[i] = det(mi) / d
+	}
+
+	return x, true
+}
+
+// Apply transforms a point.
+func (t Affine) Apply(p geometry.Point) geometry.Point {
+	return geometry.Point{
+		X: t[0]*p.X + t[1]*p.Y + t[2],
+		Y: t[3]*p.X + t[4]*p.Y + t[5],
+	}
+}
+
+// Invert returns the inverse transformation.
+func (t Affine) Invert() (Affine, error) {
+	det := t[0]*t[4] - t[1]*t[3]
+	if det == 0 {
+		return Affine{}, errors.New("affine transformation is not invertible")
+	}
+
+	return Affine{
+		t[4] / det, -t[1] / det, (t[1]*t[5] - t[4]*t[2]) / det,
+		-t[3] / det, t[0] / det, (t[3]*t[2] - t[0]*t[5]) / det,
+	}, nil
+}
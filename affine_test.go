@@ -0,0 +1,74 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+func TestFitAffine(t *testing.T) {
+	// rotation of 90°, scale of 2 and translation of (10, 20)
+	want := Affine{0, -2, 10, 2, 0, 20}
+
+	tests := []struct {
+		name string
+		src  []geometry.Point
+	}{
+		{name: "similarity", src: []geometry.Point{{X: 0, Y: 0}, {X: 1, Y: 0}}},
+		{name: "affine", src: []geometry.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}}},
+		{name: "least squares", src: []geometry.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 3, Y: 5}}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dst := make([]geometry.Point, len(tc.src))
+			for i, p := range tc.src {
+				dst[i] = want.Apply(p)
+			}
+
+			got, err := FitAffine(tc.src, dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := range want {
+				if math.Abs(got[i]-want[i]) > 1e-9 {
+					t.Fatalf("expected %v, got %v", want, got)
+				}
+			}
+
+			inv, err := got.Invert()
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := geometry.Point{X: 7, Y: -3}
+			if q := inv.Apply(got.Apply(p)); math.Abs(q.X-p.X) > 1e-9 || math.Abs(q.Y-p.Y) > 1e-9 {
+				t.Errorf("expected inverse to map back to %v, got %v", p, q)
+			}
+		})
+	}
+}
+
+func TestFitAffineErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		src, dst []geometry.Point
+	}{
+		{name: "single point", src: []geometry.Point{{}}, dst: []geometry.Point{{}}},
+		{name: "mismatch", src: []geometry.Point{{}, {X: 1}}, dst: []geometry.Point{{}}},
+		{name: "coincident", src: []geometry.Point{{}, {}}, dst: []geometry.Point{{}, {X: 1}}},
+		{name: "collinear", src: []geometry.Point{{}, {X: 1}, {X: 2}}, dst: []geometry.Point{{}, {X: 1}, {X: 2}}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := FitAffine(tc.src, tc.dst); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
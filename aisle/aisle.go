@@ -0,0 +1,358 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package aisle models the aisles and corridors of a site, such as a warehouse, as a
+// graph of lines. Location streams are snapped to the graph by map-matching, for
+// cleaner paths and aisle-level analytics, and shortest routes between positions
+// follow the aisles, such as to optimize pick paths.
+package aisle
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/internal/geo"
+)
+
+// joinTolerance is the distance in meters within which aisles are joined, such as an
+// aisle ending next to a corridor.
+const joinTolerance = 0.05
+
+// Aisle is a walkable line of a site, such as an aisle between racks or a corridor.
+type Aisle struct {
+	// ID identifies the aisle in matches.
+	ID string
+
+	// Name is an optional textual representation of the aisle.
+	Name string
+
+	// Path are the vertices of the aisle line, in order.
+	Path []geometry.Point
+}
+
+// Validate checks the aisle definition.
+func (a Aisle) Validate() error {
+	if a.ID == "" {
+		return fmt.Errorf("aisle must have an id")
+	}
+	if len(a.Path) < 2 {
+		return fmt.Errorf("aisle '%s' must have at least two points", a.ID)
+	}
+	return nil
+}
+
+// Graph is the network of the aisles of a site. Aisles crossing or touching each other
+// are joined, so they are connected in the graph. It is safe for concurrent use.
+type Graph struct {
+	crs    string
+	aisles []Aisle
+	proj   projection
+
+	// nodes are the positions of the vertices and junctions of the aisles, in meters.
+	nodes []vec
+
+	// segments are the lines between consecutive nodes of the aisles.
+	segments []segment
+
+	// edges are the segments of each node.
+	edges [][]int
+
+	// connected are the aisles joined to each aisle.
+	connected []map[int]bool
+}
+
+// segment is a line between two nodes of an aisle.
+type segment struct {
+	aisle int
+	a, b  int
+
+	// offset is the distance in meters along the aisle to the first node.
+	offset float64
+}
+
+// NewGraph returns the graph of the aisles, with their paths in the crs.
+// WGS84 (EPSG:4326) paths are in longitude and latitude, other systems are assumed to be in meters.
+func NewGraph(crs string, aisles ...Aisle) (*Graph, error) {
+	if crs == "" {
+		crs = omlox.CrsLocal
+	}
+
+	ids := make(map[string]bool, len(aisles))
+	for _, a := range aisles {
+		if err := a.Validate(); err != nil {
+			return nil, err
+		}
+		if ids[a.ID] {
+			return nil, fmt.Errorf("duplicated aisle '%s'", a.ID)
+		}
+		ids[a.ID] = true
+	}
+	if len(aisles) == 0 {
+		return nil, fmt.Errorf("graph must have at least one aisle")
+	}
+
+	g := &Graph{
+		crs:       crs,
+		aisles:    aisles,
+		proj:      newProjection(crs, aisles[0].Path[0]),
+		connected: make([]map[int]bool, len(aisles)),
+	}
+
+	paths := make([][]vec, len(aisles))
+	for i, a := range aisles {
+		for _, p := range a.Path {
+			paths[i] = append(paths[i], g.proj.forward(p))
+		}
+		g.connected[i] = make(map[int]bool)
+	}
+
+	// junctions are the positions along the segments of each aisle where other aisles
+	// cross or touch it, by aisle and segment index
+	junctions := make([][][]float64, len(paths))
+	for i := range paths {
+		junctions[i] = make([][]float64, len(paths[i])-1)
+	}
+	for i := range paths {
+		for j := i + 1; j < len(paths); j++ {
+			for si := 0; si < len(paths[i])-1; si++ {
+				for sj := 0; sj < len(paths[j])-1; sj++ {
+					ti, tj, ok := intersect(paths[i][si], paths[i][si+1], paths[j][sj], paths[j][sj+1])
+					if !ok {
+						continue
+					}
+					junctions[i][si] = append(junctions[i][si], ti)
+					junctions[j][sj] = append(junctions[j][sj], tj)
+				}
+			}
+		}
+	}
+
+	grid := make(map[[2]int64][]int)
+	for i, path := range paths {
+		last, offset := -1, 0.0
+		for si := 0; si < len(path)-1; si++ {
+			a, b := path[si], path[si+1]
+
+			ts := append([]float64{0}, junctions[i][si]...)
+			sort.Float64s(ts)
+			if si == len(path)-2 {
+				ts = append(ts, 1)
+			}
+
+			for _, t := range ts {
+				n := g.node(grid, a.lerp(b, t))
+				if last >= 0 && n != last {
+					g.segments = append(g.segments, segment{aisle: i, a: last, b: n, offset: offset})
+					offset += g.nodes[last].dist(g.nodes[n])
+				}
+				last = n
+			}
+		}
+	}
+
+	g.edges = make([][]int, len(g.nodes))
+	for i, s := range g.segments {
+		g.edges[s.a] = append(g.edges[s.a], i)
+		g.edges[s.b] = append(g.edges[s.b], i)
+	}
+
+	// aisles sharing a node are connected
+	for _, edges := range g.edges {
+		for _, a := range edges {
+			for _, b := range edges {
+				if i, j := g.segments[a].aisle, g.segments[b].aisle; i != j {
+					g.connected[i][j] = true
+				}
+			}
+		}
+	}
+
+	return g, nil
+}
+
+// node returns the node at a position, adding it unless a node is within the join tolerance.
+func (g *Graph) node(grid map[[2]int64][]int, p vec) int {
+	cell := [2]int64{int64(math.Floor(p.x / joinTolerance)), int64(math.Floor(p.y / joinTolerance))}
+	for dx := int64(-1); dx <= 1; dx++ {
+		for dy := int64(-1); dy <= 1; dy++ {
+			for _, n := range grid[[2]int64{cell[0] + dx, cell[1] + dy}] {
+				if g.nodes[n].dist(p) <= joinTolerance {
+					return n
+				}
+			}
+		}
+	}
+
+	g.nodes = append(g.nodes, p)
+	grid[cell] = append(grid[cell], len(g.nodes)-1)
+	return len(g.nodes) - 1
+}
+
+// Crs returns the coordinate reference system of the aisle paths.
+func (g *Graph) Crs() string {
+	return g.crs
+}
+
+// Aisles returns the aisles of the graph sorted by id.
+func (g *Graph) Aisles() []Aisle {
+	aisles := append([]Aisle(nil), g.aisles...)
+	sort.Slice(aisles, func(i, j int) bool { return aisles[i].ID < aisles[j].ID })
+	return aisles
+}
+
+// Connected reports whether two aisles cross or touch each other.
+func (g *Graph) Connected(a, b string) bool {
+	i, j := g.index(a), g.index(b)
+	return i >= 0 && j >= 0 && g.connected[i][j]
+}
+
+// index returns the index of an aisle, or -1 if not found.
+func (g *Graph) index(id string) int {
+	for i, a := range g.aisles {
+		if a.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// FromGeoJSON returns the graph of the aisles of a GeoJSON feature collection, from its LineString
+// features. The "id" and "name" feature properties define the aisle, with the feature id used when
+// there is no "id" property. Aisles are in WGS84, as GeoJSON coordinates are in longitude and latitude.
+func FromGeoJSON(data []byte) (*Graph, error) {
+	type feature struct {
+		ID       any `json:"id"`
+		Geometry struct {
+			Type        string          `json:"type"`
+			Coordinates json.RawMessage `json:"coordinates"`
+		} `json:"geometry"`
+		Properties struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"properties"`
+	}
+
+	var doc struct {
+		Type     string    `json:"type"`
+		Features []feature `json:"features"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("not a GeoJSON feature collection")
+	}
+
+	var aisles []Aisle
+	for _, f := range doc.Features {
+		if f.Geometry.Type != "LineString" {
+			continue
+		}
+
+		a := Aisle{
+			ID:   f.Properties.ID,
+			Name: f.Properties.Name,
+		}
+		if a.ID == "" && f.ID != nil {
+			a.ID = fmt.Sprint(f.ID)
+		}
+
+		var coordinates [][]float64
+		if err := json.Unmarshal(f.Geometry.Coordinates, &coordinates); err != nil {
+			return nil, fmt.Errorf("aisle '%s' has invalid coordinates: %w", a.ID, err)
+		}
+
+		for _, c := range coordinates {
+			if len(c) < 2 {
+				return nil, fmt.Errorf("aisle '%s' has an invalid position", a.ID)
+			}
+			a.Path = append(a.Path, geometry.Point{X: c[0], Y: c[1]})
+		}
+
+		aisles = append(aisles, a)
+	}
+
+	return NewGraph(omlox.CrsWGS84, aisles...)
+}
+
+// vec is a position in meters.
+type vec struct {
+	x, y float64
+}
+
+func (v vec) dist(u vec) float64 {
+	return math.Hypot(u.x-v.x, u.y-v.y)
+}
+
+func (v vec) lerp(u vec, t float64) vec {
+	return vec{v.x + t*(u.x-v.x), v.y + t*(u.y-v.y)}
+}
+
+// closest returns the position along the segment from a to b closest to v, as a fraction
+// of the segment, and its distance to v.
+func (v vec) closest(a, b vec) (t float64, distance float64) {
+	dx, dy := b.x-a.x, b.y-a.y
+	if length := dx*dx + dy*dy; length > 0 {
+		t = math.Max(0, math.Min(1, ((v.x-a.x)*dx+(v.y-a.y)*dy)/length))
+	}
+	return t, v.dist(a.lerp(b, t))
+}
+
+// intersect returns where the segments from a to b and from c to d cross or touch
+// within the join tolerance, as fractions of each segment.
+func intersect(a, b, c, d vec) (t float64, u float64, ok bool) {
+	rx, ry := b.x-a.x, b.y-a.y
+	sx, sy := d.x-c.x, d.y-c.y
+
+	if denom := rx*sy - ry*sx; denom != 0 {
+		t = ((c.x-a.x)*sy - (c.y-a.y)*sx) / denom
+		u = ((c.x-a.x)*ry - (c.y-a.y)*rx) / denom
+		if t >= 0 && t <= 1 && u >= 0 && u <= 1 {
+			return t, u, true
+		}
+	}
+
+	// an end of a segment next to the other segment
+	if f, distance := a.closest(c, d); distance <= joinTolerance {
+		return 0, f, true
+	}
+	if f, distance := b.closest(c, d); distance <= joinTolerance {
+		return 1, f, true
+	}
+	if f, distance := c.closest(a, b); distance <= joinTolerance {
+		return f, 0, true
+	}
+	if f, distance := d.closest(a, b); distance <= joinTolerance {
+		return f, 1, true
+	}
+
+	return 0, 0, false
+}
+
+// projection converts the positions of a crs to meters around an origin.
+type projection struct {
+	origin geometry.Point
+	sx, sy float64
+}
+
+// newProjection returns the projection of the crs around the origin. WGS84 positions are
+// scaled to meters, other coordinate reference systems are assumed to be in meters.
+func newProjection(crs string, origin geometry.Point) projection {
+	if crs != omlox.CrsWGS84 {
+		return projection{sx: 1, sy: 1}
+	}
+	sx, sy := geo.Scale(origin.Y)
+	return projection{origin: origin, sx: sx, sy: sy}
+}
+
+func (p projection) forward(q geometry.Point) vec {
+	return vec{(q.X - p.origin.X) * p.sx, (q.Y - p.origin.Y) * p.sy}
+}
+
+func (p projection) inverse(v vec) geometry.Point {
+	return geometry.Point{X: p.origin.X + v.x/p.sx, Y: p.origin.Y + v.y/p.sy}
+}
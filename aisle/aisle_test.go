@@ -0,0 +1,153 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package aisle
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+// warehouse is a main corridor going 20m east, with two parallel aisles 2m apart going north from it.
+func warehouse(t *testing.T) *Graph {
+	t.Helper()
+
+	g, err := NewGraph(omlox.CrsLocal,
+		Aisle{ID: "main", Path: []geometry.Point{{X: 0, Y: 0}, {X: 20, Y: 0}}},
+		Aisle{ID: "a1", Path: []geometry.Point{{X: 5, Y: 0}, {X: 5, Y: 10}}},
+		Aisle{ID: "a2", Path: []geometry.Point{{X: 7, Y: -0.02}, {X: 7, Y: 10}}},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return g
+}
+
+func location(provider string, x, y float64) omlox.Location {
+	return omlox.Location{
+		Position:   *omlox.NewPoint(geometry.Point{X: x, Y: y}),
+		ProviderID: provider,
+		Crs:        omlox.CrsLocal,
+	}
+}
+
+func TestGraph(t *testing.T) {
+	g := warehouse(t)
+
+	if !g.Connected("main", "a1") || !g.Connected("a2", "main") {
+		t.Error("expected the aisles to be connected to the main corridor")
+	}
+	if g.Connected("a1", "a2") {
+		t.Error("expected parallel aisles not to be connected")
+	}
+
+	// the main corridor is split at both aisles
+	if len(g.segments) != 5 {
+		t.Errorf("expected 5 segments, got %d", len(g.segments))
+	}
+
+	if _, err := NewGraph(omlox.CrsLocal, Aisle{ID: "a"}, Aisle{ID: "a"}); err == nil {
+		t.Error("expected error for invalid aisles")
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	m := NewMatcher(warehouse(t))
+
+	tests := []struct {
+		name     string
+		loc      omlox.Location
+		aisle    string
+		position geometry.Point
+		progress float64
+	}{
+		{name: "main corridor", loc: location("p1", 10, 0.5), aisle: "main", position: geometry.Point{X: 10}, progress: 10},
+		{name: "entering aisle", loc: location("p1", 5.2, 2), aisle: "a1", position: geometry.Point{X: 5, Y: 2}, progress: 2},
+		{name: "along aisle", loc: location("p1", 5.9, 5), aisle: "a1", position: geometry.Point{X: 5, Y: 5}, progress: 5},
+		// closer to the parallel aisle, but staying in the current one
+		{name: "noisy location", loc: location("p1", 6.2, 6), aisle: "a1", position: geometry.Point{X: 5, Y: 6}, progress: 6},
+		// the start of the aisle is joined to the main corridor
+		{name: "other provider", loc: location("p2", 6.2, 6), aisle: "a2", position: geometry.Point{X: 7, Y: 6}, progress: 6},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			match, ok := m.Match(tc.loc)
+			if !ok {
+				t.Fatal("expected a match")
+			}
+			if match.AisleID != tc.aisle {
+				t.Errorf("expected aisle %s, got %s", tc.aisle, match.AisleID)
+			}
+			if math.Abs(match.Position.X-tc.position.X) > 1e-9 || math.Abs(match.Position.Y-tc.position.Y) > 1e-9 {
+				t.Errorf("expected position %v, got %v", tc.position, match.Position)
+			}
+			if math.Abs(match.Progress-tc.progress) > 1e-9 {
+				t.Errorf("expected progress %v, got %v", tc.progress, match.Progress)
+			}
+		})
+	}
+
+	if _, ok := m.Match(location("p1", 15, 8)); ok {
+		t.Error("expected no match away from the aisles")
+	}
+
+	wgs84 := location("p1", 5, 5)
+	wgs84.Crs = omlox.CrsWGS84
+	if _, ok := m.Match(wgs84); ok {
+		t.Error("expected no match in a different crs")
+	}
+}
+
+func TestMatcherSnap(t *testing.T) {
+	m := NewMatcher(warehouse(t))
+
+	snapped := m.Snap([]omlox.Location{location("p1", 1, 0.3), location("p1", 15, 8)})
+	if p := snapped[0].Position.Base(); p != (geometry.Point{X: 1}) {
+		t.Errorf("expected snapped position, got %v", p)
+	}
+	if p := snapped[1].Position.Base(); p != (geometry.Point{X: 15, Y: 8}) {
+		t.Errorf("expected unmatched position unchanged, got %v", p)
+	}
+}
+
+func TestFromGeoJSON(t *testing.T) {
+	data := []byte(`{
+		"type": "FeatureCollection",
+		"features": [
+			{"type": "Feature", "id": 1, "geometry": {"type": "LineString", "coordinates": [[7.8157, 48.1302], [7.8160, 48.1302]]}, "properties": {"name": "Main"}},
+			{"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[7.8158, 48.1302], [7.8158, 48.1304]]}, "properties": {"id": "aisle-1"}},
+			{"type": "Feature", "geometry": {"type": "Point", "coordinates": [7.8158, 48.1302]}, "properties": {"id": "dock"}}
+		]
+	}`)
+
+	g, err := FromGeoJSON(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if g.Crs() != omlox.CrsWGS84 {
+		t.Errorf("expected WGS84 graph, got %s", g.Crs())
+	}
+	if aisles := g.Aisles(); len(aisles) != 2 || aisles[0].ID != "1" || aisles[0].Name != "Main" || aisles[1].ID != "aisle-1" {
+		t.Errorf("unexpected aisles %+v", aisles)
+	}
+	if !g.Connected("1", "aisle-1") {
+		t.Error("expected the aisles to be connected")
+	}
+
+	// about 1.1m east of the aisle
+	loc := location("p1", 7.815815, 48.1303)
+	loc.Crs = omlox.CrsWGS84
+
+	match, ok := NewMatcher(g).Match(loc)
+	if !ok || match.AisleID != "aisle-1" {
+		t.Fatalf("expected match on aisle-1, got %+v", match)
+	}
+	if math.Abs(match.Distance-1.11) > 0.01 || math.Abs(match.Position.X-7.8158) > 1e-9 {
+		t.Errorf("unexpected match %+v", match)
+	}
+}
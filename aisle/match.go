@@ -0,0 +1,162 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package aisle
+
+import (
+	"math"
+	"sync"
+
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+// DefaultMaxDistance is the default distance in meters within which locations are matched to aisles.
+const DefaultMaxDistance = 3.0
+
+// DefaultSwitchPenalty is the default penalty in meters of matching a provider to an aisle
+// not connected to its previous one.
+const DefaultSwitchPenalty = 1.0
+
+// MatcherOption is a configuration option of a matcher.
+type MatcherOption func(*Matcher)
+
+// WithMaxDistance sets the distance in meters within which locations are matched to aisles.
+// Default: 3 meters
+func WithMaxDistance(d float64) MatcherOption {
+	return func(m *Matcher) {
+		m.maxDistance = d
+	}
+}
+
+// WithSwitchPenalty sets the penalty in meters added to the distance to the aisles not
+// connected to the previous aisle of a provider, so noisy locations between parallel aisles
+// do not jump from one to the other.
+// Default: 1 meter
+func WithSwitchPenalty(p float64) MatcherOption {
+	return func(m *Matcher) {
+		m.switchPenalty = p
+	}
+}
+
+// Match is a location matched to an aisle.
+type Match struct {
+	// AisleID is the id of the aisle.
+	AisleID string
+
+	// Position is the position on the aisle closest to the location, in the crs of the graph.
+	Position geometry.Point
+
+	// Distance is the distance in meters from the location to the aisle.
+	Distance float64
+
+	// Progress is the distance in meters along the aisle of the position.
+	Progress float64
+}
+
+// Matcher matches the locations of providers to the aisles of a graph, keeping the
+// previous aisle of each provider to favour continuous paths.
+// It is safe for concurrent use.
+type Matcher struct {
+	graph *Graph
+
+	maxDistance   float64
+	switchPenalty float64
+
+	mu   sync.Mutex
+	last map[string]int
+}
+
+// NewMatcher returns a matcher of locations to the aisles of the graph.
+func NewMatcher(g *Graph, opts ...MatcherOption) *Matcher {
+	m := &Matcher{
+		graph:         g,
+		maxDistance:   DefaultMaxDistance,
+		switchPenalty: DefaultSwitchPenalty,
+		last:          make(map[string]int),
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// Match matches a location to the aisle its provider is most likely on: the closest aisle
+// within the maximum distance, with aisles not connected to the previous aisle of the
+// provider penalized. Locations in a different crs than the graph are not matched.
+func (m *Matcher) Match(loc omlox.Location) (Match, bool) {
+	crs := loc.Crs
+	if crs == "" {
+		crs = omlox.CrsLocal
+	}
+	if crs != m.graph.crs {
+		return Match{}, false
+	}
+
+	g := m.graph
+	p := g.proj.forward(loc.Position.Base())
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	last, known := m.last[loc.ProviderID]
+
+	best, bestCost := -1, math.Inf(1)
+	var bestT, bestDistance float64
+	for i, s := range g.segments {
+		t, distance := p.closest(g.nodes[s.a], g.nodes[s.b])
+		if distance > m.maxDistance {
+			continue
+		}
+
+		cost := distance
+		if known && s.aisle != last && !g.connected[last][s.aisle] {
+			cost += m.switchPenalty
+		}
+
+		if cost < bestCost {
+			best, bestCost, bestT, bestDistance = i, cost, t, distance
+		}
+	}
+	if best < 0 {
+		return Match{}, false
+	}
+
+	s := g.segments[best]
+	m.last[loc.ProviderID] = s.aisle
+
+	a, b := g.nodes[s.a], g.nodes[s.b]
+	return Match{
+		AisleID:  g.aisles[s.aisle].ID,
+		Position: g.proj.inverse(a.lerp(b, bestT)),
+		Distance: bestDistance,
+		Progress: s.offset + bestT*a.dist(b),
+	}, true
+}
+
+// Snap returns the locations with their positions snapped to the aisles they are matched
+// to, in order. Unmatched locations are returned unchanged.
+func (m *Matcher) Snap(locations []omlox.Location) []omlox.Location {
+	snapped := make([]omlox.Location, len(locations))
+	for i, loc := range locations {
+		if match, ok := m.Match(loc); ok {
+			if z := loc.Position.Z(); z != 0 {
+				loc.Position = *omlox.NewPointZ(match.Position, z)
+			} else {
+				loc.Position = *omlox.NewPoint(match.Position)
+			}
+		}
+		snapped[i] = loc
+	}
+	return snapped
+}
+
+// Reset forgets the previous aisle of a provider, such as when its trackable leaves the site.
+func (m *Matcher) Reset(providerID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.last, providerID)
+}
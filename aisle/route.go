@@ -0,0 +1,169 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package aisle
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+// ErrNoRoute is returned when two positions are not connected by the aisles of a graph.
+var ErrNoRoute = errors.New("no route between the positions")
+
+// Path is the shortest route between two positions along the aisles of a graph.
+type Path struct {
+	// Line are the vertices of the route line, in the crs of the graph, from the start
+	// position to the destination.
+	Line []geometry.Point
+
+	// Distance is the length in meters of the route line.
+	Distance float64
+
+	// Aisles are the ids of the aisles along the route, in order.
+	Aisles []string
+}
+
+// Route returns the shortest route between two positions in the crs of the graph, walking
+// from each position to the closest point of the aisles, and along the aisles in between.
+// ErrNoRoute is returned when the closest aisles are not connected.
+func (g *Graph) Route(from, to geometry.Point) (Path, error) {
+	start, end := g.proj.forward(from), g.proj.forward(to)
+	s1, t1 := g.nearest(start)
+	s2, t2 := g.nearest(end)
+
+	// the start is on the first segment, connected to both of its nodes
+	seg1, seg2 := g.segments[s1], g.segments[s2]
+	len1 := g.nodes[seg1.a].dist(g.nodes[seg1.b])
+	len2 := g.nodes[seg2.a].dist(g.nodes[seg2.b])
+
+	dist := make([]float64, len(g.nodes))
+	prev := make([]int, len(g.nodes))
+	for i := range dist {
+		dist[i], prev[i] = math.Inf(1), -1
+	}
+
+	q := &nodeQueue{}
+	for _, n := range []struct {
+		node int
+		cost float64
+	}{{seg1.a, t1 * len1}, {seg1.b, (1 - t1) * len1}} {
+		if n.cost < dist[n.node] {
+			dist[n.node] = n.cost
+			heap.Push(q, queued{n.node, n.cost})
+		}
+	}
+
+	for q.Len() > 0 {
+		c := heap.Pop(q).(queued)
+		if c.cost > dist[c.node] {
+			continue
+		}
+		for _, s := range g.edges[c.node] {
+			seg := g.segments[s]
+			next := seg.a
+			if next == c.node {
+				next = seg.b
+			}
+			if cost := c.cost + g.nodes[seg.a].dist(g.nodes[seg.b]); cost < dist[next] {
+				dist[next], prev[next] = cost, s
+				heap.Push(q, queued{next, cost})
+			}
+		}
+	}
+
+	// the destination is reached through either node of the last segment, or directly
+	// along the segment of the start
+	last, distance := -1, math.Inf(1)
+	if d := dist[seg2.a] + t2*len2; d < distance {
+		last, distance = seg2.a, d
+	}
+	if d := dist[seg2.b] + (1-t2)*len2; d < distance {
+		last, distance = seg2.b, d
+	}
+	if s1 == s2 && math.Abs(t2-t1)*len1 <= distance {
+		last, distance = -1, math.Abs(t2-t1)*len1
+	}
+	if math.IsInf(distance, 1) {
+		return Path{}, ErrNoRoute
+	}
+
+	// walk back the nodes from the destination to the start
+	var (
+		nodes []vec
+		segs  = []int{s2}
+	)
+	for n := last; n >= 0; {
+		nodes = append(nodes, g.nodes[n])
+		s := prev[n]
+		if s < 0 {
+			break
+		}
+		segs = append(segs, s)
+		if seg := g.segments[s]; seg.a == n {
+			n = seg.b
+		} else {
+			n = seg.a
+		}
+	}
+	segs = append(segs, s1)
+
+	entry, exit := g.nodes[seg1.a].lerp(g.nodes[seg1.b], t1), g.nodes[seg2.a].lerp(g.nodes[seg2.b], t2)
+	line := []vec{start, entry}
+	for i := len(nodes) - 1; i >= 0; i-- {
+		line = append(line, nodes[i])
+	}
+	line = append(line, exit, end)
+
+	p := Path{Distance: start.dist(entry) + distance + exit.dist(end)}
+	for i, v := range line {
+		if i > 0 && v.dist(line[i-1]) == 0 {
+			continue
+		}
+		p.Line = append(p.Line, g.proj.inverse(v))
+	}
+	for i := len(segs) - 1; i >= 0; i-- {
+		id := g.aisles[g.segments[segs[i]].aisle].ID
+		if len(p.Aisles) == 0 || p.Aisles[len(p.Aisles)-1] != id {
+			p.Aisles = append(p.Aisles, id)
+		}
+	}
+
+	return p, nil
+}
+
+// nearest returns the segment closest to a position, and the position along it of the
+// closest point, as a fraction of the segment.
+func (g *Graph) nearest(p vec) (segment int, t float64) {
+	best := math.Inf(1)
+	for i, s := range g.segments {
+		if f, distance := p.closest(g.nodes[s.a], g.nodes[s.b]); distance < best {
+			segment, t, best = i, f, distance
+		}
+	}
+	return segment, t
+}
+
+// queued is a node reached at a cost.
+type queued struct {
+	node int
+	cost float64
+}
+
+// nodeQueue is a min-heap of nodes by cost.
+type nodeQueue []queued
+
+func (q nodeQueue) Len() int           { return len(q) }
+func (q nodeQueue) Less(i, j int) bool { return q[i].cost < q[j].cost }
+func (q nodeQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *nodeQueue) Push(x any)        { *q = append(*q, x.(queued)) }
+
+func (q *nodeQueue) Pop() any {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
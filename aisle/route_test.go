@@ -0,0 +1,96 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package aisle
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+func TestRoute(t *testing.T) {
+	g := warehouse(t)
+
+	tests := []struct {
+		name     string
+		from, to geometry.Point
+		line     []geometry.Point
+		distance float64
+		aisles   []string
+	}{
+		{
+			name:     "between parallel aisles",
+			from:     geometry.Point{X: 5.5, Y: 8},
+			to:       geometry.Point{X: 6.5, Y: 8},
+			line:     []geometry.Point{{X: 5.5, Y: 8}, {X: 5, Y: 8}, {X: 5, Y: 0}, {X: 7, Y: 0}, {X: 7, Y: 8}, {X: 6.5, Y: 8}},
+			distance: 19,
+			aisles:   []string{"a1", "main", "a2"},
+		},
+		{
+			name:     "along a segment",
+			from:     geometry.Point{X: 1, Y: 1},
+			to:       geometry.Point{X: 3, Y: -1},
+			line:     []geometry.Point{{X: 1, Y: 1}, {X: 1, Y: 0}, {X: 3, Y: 0}, {X: 3, Y: -1}},
+			distance: 4,
+			aisles:   []string{"main"},
+		},
+		{
+			name:     "on the aisles",
+			from:     geometry.Point{X: 20, Y: 0},
+			to:       geometry.Point{X: 7, Y: 10},
+			line:     []geometry.Point{{X: 20, Y: 0}, {X: 7, Y: 0}, {X: 7, Y: 10}},
+			distance: 23,
+			aisles:   []string{"main", "a2"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := g.Route(tc.from, tc.to)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !sameLine(p.Line, tc.line) {
+				t.Errorf("expected line %v, got %v", tc.line, p.Line)
+			}
+			if math.Abs(p.Distance-tc.distance) > 1e-9 {
+				t.Errorf("expected distance %v, got %v", tc.distance, p.Distance)
+			}
+			if !reflect.DeepEqual(p.Aisles, tc.aisles) {
+				t.Errorf("expected aisles %v, got %v", tc.aisles, p.Aisles)
+			}
+		})
+	}
+}
+
+// sameLine reports whether two lines have the same vertices, within rounding errors.
+func sameLine(a, b []geometry.Point) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i].X-b[i].X) > 1e-9 || math.Abs(a[i].Y-b[i].Y) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRouteDisconnected(t *testing.T) {
+	g, err := NewGraph(omlox.CrsLocal,
+		Aisle{ID: "a", Path: []geometry.Point{{X: 0, Y: 0}, {X: 10, Y: 0}}},
+		Aisle{ID: "b", Path: []geometry.Point{{X: 100, Y: 0}, {X: 110, Y: 0}}},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := g.Route(geometry.Point{X: 1, Y: 0}, geometry.Point{X: 105, Y: 1}); !errors.Is(err, ErrNoRoute) {
+		t.Errorf("expected ErrNoRoute, got %v", err)
+	}
+}
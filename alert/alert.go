@@ -0,0 +1,38 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package alert provides stateful alerting rules on top of omlox™ events.
+//
+// The omlox™ Hub only emits edge-triggered events (e.g. a fence entry or exit).
+// Conditions that depend on how long something has been true, such as
+// "trackable inside fence X for more than N minutes", need state to be kept
+// on the client side. This package keeps that state and reports alerts when
+// a rule is satisfied.
+//
+// Rules can be restricted to time windows, such as working shifts or
+// maintenance windows loaded from an iCalendar file.
+package alert
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Alert is emitted when a rule is satisfied.
+type Alert struct {
+	// Rule is the name of the rule that fired.
+	Rule string `json:"rule"`
+
+	// TrackableID is the trackable that triggered the rule.
+	TrackableID uuid.UUID `json:"trackable_id"`
+
+	// FenceID is the fence related to the alert, if any.
+	FenceID uuid.UUID `json:"fence_id,omitempty"`
+
+	// Since is the time from which the rule condition has been true.
+	Since time.Time `json:"since"`
+
+	// At is the time the rule fired.
+	At time.Time `json:"at"`
+}
@@ -0,0 +1,419 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package alert
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Calendar is a set of calendar events, such as maintenance windows.
+type Calendar struct {
+	Events []CalendarEvent
+}
+
+var _ Schedule = (*Calendar)(nil)
+
+// Contains implements the Schedule interface.
+// It reports true if any of the calendar events contains t.
+func (c *Calendar) Contains(t time.Time) bool {
+	for _, e := range c.Events {
+		if e.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// CalendarEvent is a single or recurring calendar event.
+type CalendarEvent struct {
+	// Summary is a short description of the event.
+	Summary string
+
+	// Start of the first occurrence of the event.
+	Start time.Time
+
+	// End of the first occurrence of the event.
+	End time.Time
+
+	// Recurrence is the optional recurrence rule of the event.
+	Recurrence *Recurrence
+}
+
+var _ Schedule = (*CalendarEvent)(nil)
+
+// Contains implements the Schedule interface.
+func (e CalendarEvent) Contains(t time.Time) bool {
+	length := e.End.Sub(e.Start)
+	if length <= 0 {
+		return false
+	}
+
+	if e.Recurrence == nil {
+		return !t.Before(e.Start) && t.Before(e.End)
+	}
+
+	loc := e.Start.Location()
+	t = t.In(loc)
+
+	// occurrences containing t must have started between t-length and t
+	first := midnight(t.Add(-length))
+	for day := midnight(t); !day.Before(first); day = day.AddDate(0, 0, -1) {
+		y, m, d := day.Date()
+		begin := time.Date(y, m, d, e.Start.Hour(), e.Start.Minute(), e.Start.Second(), e.Start.Nanosecond(), loc)
+
+		if !e.Recurrence.occurs(e.Start, begin) {
+			continue
+		}
+
+		if !t.Before(begin) && t.Before(begin.Add(length)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Frequency of a recurrence rule.
+type Frequency int
+
+// Defines values for Frequency.
+const (
+	FrequencyDaily Frequency = iota
+	FrequencyWeekly
+)
+
+// Recurrence is the subset of the iCalendar recurrence rule (RFC 5545) supported
+// for time windows: daily and weekly frequencies with interval, week days and end date.
+type Recurrence struct {
+	// Frequency of the recurrence.
+	Frequency Frequency
+
+	// Interval between occurrences, in units of the frequency. Zero means 1.
+	Interval int
+
+	// Days restricts occurrences to the given week days.
+	// For weekly recurrences, it defaults to the week day of the first occurrence.
+	Days []time.Weekday
+
+	// Until is the inclusive upper bound for the start of occurrences.
+	// If zero, the event recurs forever.
+	Until time.Time
+}
+
+// occurs reports if there is an occurrence starting at begin
+// for an event whose first occurrence starts at first.
+func (r *Recurrence) occurs(first, begin time.Time) bool {
+	if begin.Before(first) {
+		return false
+	}
+	if !r.Until.IsZero() && begin.After(r.Until) {
+		return false
+	}
+
+	interval := r.Interval
+	if interval <= 0 {
+		interval = 1
+	}
+
+	days := daysBetween(first, begin)
+
+	switch r.Frequency {
+	case FrequencyDaily:
+		return days%interval == 0 && (len(r.Days) == 0 || hasWeekday(r.Days, begin.Weekday()))
+	case FrequencyWeekly:
+		weekdays := r.Days
+		if len(weekdays) == 0 {
+			weekdays = []time.Weekday{first.Weekday()}
+		}
+
+		// weeks start on monday (iCalendar default)
+		weeks := (days + mondayOffset(first.Weekday())) / 7
+		return weeks%interval == 0 && hasWeekday(weekdays, begin.Weekday())
+	}
+
+	return false
+}
+
+// LoadICal loads the events of an iCalendar (RFC 5545) stream.
+// Only VEVENT components are considered, and recurrence rules are restricted
+// to the subset described by Recurrence.
+func LoadICal(r io.Reader) (*Calendar, error) {
+	lines, err := unfoldICal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		cal      Calendar
+		event    *CalendarEvent
+		duration time.Duration
+		allDay   bool
+	)
+
+	for n, line := range lines {
+		name, params, value, ok := parseICalLine(line)
+		if !ok {
+			return nil, fmt.Errorf("ical line %d: malformed content line", n+1)
+		}
+
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			event, duration, allDay = &CalendarEvent{}, 0, false
+			continue
+		case name == "END" && value == "VEVENT":
+			if event == nil {
+				return nil, fmt.Errorf("ical line %d: unexpected end of event", n+1)
+			}
+			if event.Start.IsZero() {
+				return nil, fmt.Errorf("ical line %d: event without start", n+1)
+			}
+			if event.End.IsZero() {
+				switch {
+				case duration > 0:
+					event.End = event.Start.Add(duration)
+				case allDay:
+					event.End = event.Start.AddDate(0, 0, 1)
+				default:
+					return nil, fmt.Errorf("ical line %d: event without end", n+1)
+				}
+			}
+			cal.Events = append(cal.Events, *event)
+			event = nil
+			continue
+		case event == nil:
+			continue
+		}
+
+		switch name {
+		case "SUMMARY":
+			event.Summary = value
+		case "DTSTART":
+			event.Start, allDay, err = parseICalTime(value, params)
+		case "DTEND":
+			event.End, _, err = parseICalTime(value, params)
+		case "DURATION":
+			duration, err = parseICalDuration(value)
+		case "RRULE":
+			event.Recurrence, err = parseICalRecurrence(value)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("ical line %d: %w", n+1, err)
+		}
+	}
+
+	return &cal, nil
+}
+
+// unfoldICal reads content lines, joining folded lines.
+func unfoldICal(r io.Reader) ([]string, error) {
+	var lines []string
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimRight(s.Text(), "\r")
+
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines, s.Err()
+}
+
+// parseICalLine splits a content line into name, parameters and value.
+func parseICalLine(line string) (string, map[string]string, string, bool) {
+	head, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", nil, "", false
+	}
+
+	parts := strings.Split(head, ";")
+	params := make(map[string]string, len(parts)-1)
+	for _, p := range parts[1:] {
+		k, v, _ := strings.Cut(p, "=")
+		params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+
+	return strings.ToUpper(parts[0]), params, value, true
+}
+
+// parseICalTime parses a DATE or DATE-TIME value and reports if it was a DATE.
+func parseICalTime(value string, params map[string]string) (time.Time, bool, error) {
+	loc := time.UTC
+	if tzid, ok := params["TZID"]; ok {
+		l, err := time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("unknown time zone '%s'", tzid)
+		}
+		loc = l
+	}
+
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, loc)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+// parseICalDuration parses a duration value such as P1DT2H30M or P2W.
+func parseICalDuration(value string) (time.Duration, error) {
+	s := strings.TrimPrefix(value, "+")
+	if !strings.HasPrefix(s, "P") {
+		return 0, fmt.Errorf("invalid duration '%s'", value)
+	}
+	s = s[1:]
+
+	var (
+		d      time.Duration
+		inTime bool
+		num    string
+	)
+
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			num += string(c)
+			continue
+		case c == 'T':
+			inTime = true
+			continue
+		}
+
+		n, err := strconv.Atoi(num)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration '%s'", value)
+		}
+		num = ""
+
+		switch {
+		case c == 'W' && !inTime:
+			d += time.Duration(n) * 7 * 24 * time.Hour
+		case c == 'D' && !inTime:
+			d += time.Duration(n) * 24 * time.Hour
+		case c == 'H' && inTime:
+			d += time.Duration(n) * time.Hour
+		case c == 'M' && inTime:
+			d += time.Duration(n) * time.Minute
+		case c == 'S' && inTime:
+			d += time.Duration(n) * time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration '%s'", value)
+		}
+	}
+
+	if num != "" {
+		return 0, fmt.Errorf("invalid duration '%s'", value)
+	}
+
+	return d, nil
+}
+
+// parseICalRecurrence parses a RRULE value.
+func parseICalRecurrence(value string) (*Recurrence, error) {
+	var (
+		r    Recurrence
+		freq bool
+	)
+
+	for _, part := range strings.Split(value, ";") {
+		k, v, _ := strings.Cut(part, "=")
+
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			switch v {
+			case "DAILY":
+				r.Frequency = FrequencyDaily
+			case "WEEKLY":
+				r.Frequency = FrequencyWeekly
+			default:
+				return nil, fmt.Errorf("unsupported recurrence frequency '%s'", v)
+			}
+			freq = true
+		case "INTERVAL":
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid recurrence interval '%s'", v)
+			}
+			r.Interval = n
+		case "BYDAY":
+			for _, name := range strings.Split(v, ",") {
+				d, ok := icalWeekdays[name]
+				if !ok {
+					return nil, fmt.Errorf("unsupported recurrence week day '%s'", name)
+				}
+				r.Days = append(r.Days, d)
+			}
+		case "UNTIL":
+			t, date, err := parseICalTime(v, nil)
+			if err != nil {
+				return nil, err
+			}
+			if date {
+				t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+			}
+			r.Until = t
+		case "WKST":
+			// weeks always start on monday
+		default:
+			return nil, fmt.Errorf("unsupported recurrence rule part '%s'", k)
+		}
+	}
+
+	if !freq {
+		return nil, fmt.Errorf("recurrence rule without frequency")
+	}
+
+	return &r, nil
+}
+
+var icalWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// daysBetween returns the number of calendar days from a to b.
+func daysBetween(a, b time.Time) int {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	da := time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)
+	db := time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da).Hours() / 24)
+}
+
+// mondayOffset returns the number of days since monday.
+func mondayOffset(d time.Weekday) int {
+	return (int(d) + 6) % 7
+}
+
+func hasWeekday(days []time.Weekday, d time.Weekday) bool {
+	for _, day := range days {
+		if day == d {
+			return true
+		}
+	}
+	return false
+}
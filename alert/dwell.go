@@ -0,0 +1,281 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+// DwellRule fires when a trackable stays inside a fence for longer than Duration.
+type DwellRule struct {
+	// Name identifies the rule in emitted alerts.
+	Name string `json:"name"`
+
+	// FenceID restricts the rule to a single fence.
+	// If it is the zero value, the rule applies to every fence.
+	FenceID uuid.UUID `json:"fence_id,omitempty"`
+
+	// Duration is the minimum time a trackable must remain inside the fence.
+	Duration time.Duration `json:"duration"`
+
+	// Window restricts when the rule is active. If nil, the rule is always active.
+	Window *Window `json:"-"`
+}
+
+// applies reports if the rule applies to the given fence.
+func (r DwellRule) applies(fenceID uuid.UUID) bool {
+	return r.FenceID == uuid.Nil || r.FenceID == fenceID
+}
+
+// DwellState is the state of a trackable in respect to a fence.
+type DwellState int
+
+// Defines values for DwellState.
+const (
+	// The trackable is not inside the fence.
+	DwellStateOutside DwellState = iota
+
+	// The trackable is inside the fence, but no rule has fired yet.
+	DwellStateInside
+
+	// The trackable is inside the fence and at least one rule has fired.
+	DwellStateDwelling
+)
+
+// String return a text representation.
+func (s DwellState) String() string {
+	states := [...]string{
+		"outside",
+		"inside",
+		"dwelling",
+	}
+
+	if int(s) < 0 || len(states) <= int(s) {
+		return ""
+	}
+
+	return states[s]
+}
+
+// membershipKey identifies the membership of a trackable in a fence.
+type membershipKey struct {
+	TrackableID uuid.UUID `json:"trackable_id"`
+	FenceID     uuid.UUID `json:"fence_id"`
+}
+
+// membership holds the state machine of a trackable inside a fence.
+type membership struct {
+	membershipKey
+
+	// Since is the time the trackable entered the fence.
+	Since time.Time `json:"since"`
+
+	// Fired holds the names of the rules which already fired during this stay.
+	Fired map[string]bool `json:"fired,omitempty"`
+}
+
+func (m *membership) state() DwellState {
+	if len(m.Fired) > 0 {
+		return DwellStateDwelling
+	}
+	return DwellStateInside
+}
+
+// Dwell evaluates dwell rules using a per-trackable fence membership state machine.
+//
+// Fence entries and exits must be fed through Enter and Exit, and Evaluate must
+// be called periodically to check for elapsed dwell durations. Each rule fires
+// at most once per stay of a trackable inside a fence.
+//
+// The state can be persisted with Save and restored with Load, so ongoing stays
+// survive restarts of the process.
+type Dwell struct {
+	mu sync.Mutex
+
+	rules   []DwellRule
+	members map[membershipKey]*membership
+
+	clock clock.Clock
+}
+
+// NewDwell returns a new dwell evaluator for the given rules.
+func NewDwell(rules ...DwellRule) (*Dwell, error) {
+	names := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		if r.Name == "" {
+			return nil, fmt.Errorf("dwell rule must have a name")
+		}
+		if names[r.Name] {
+			return nil, fmt.Errorf("duplicated dwell rule '%s'", r.Name)
+		}
+		if r.Duration <= 0 {
+			return nil, fmt.Errorf("dwell rule '%s' duration must be positive", r.Name)
+		}
+		names[r.Name] = true
+	}
+
+	return &Dwell{
+		rules:   rules,
+		members: make(map[membershipKey]*membership),
+		clock:   clock.System,
+	}, nil
+}
+
+// SetClock sets the source of time of Watch.
+func (d *Dwell) SetClock(clk clock.Clock) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.clock = clock.Or(clk)
+}
+
+// Enter records a trackable entering a fence.
+// Entering a fence the trackable is already in keeps the original entry time.
+func (d *Dwell) Enter(trackableID, fenceID uuid.UUID, at time.Time) {
+	key := membershipKey{TrackableID: trackableID, FenceID: fenceID}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.members[key]; ok {
+		return
+	}
+
+	d.members[key] = &membership{
+		membershipKey: key,
+		Since:         at,
+	}
+}
+
+// Exit records a trackable leaving a fence.
+func (d *Dwell) Exit(trackableID, fenceID uuid.UUID) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.members, membershipKey{TrackableID: trackableID, FenceID: fenceID})
+}
+
+// State returns the state of a trackable in respect to a fence.
+func (d *Dwell) State(trackableID, fenceID uuid.UUID) DwellState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	m, ok := d.members[membershipKey{TrackableID: trackableID, FenceID: fenceID}]
+	if !ok {
+		return DwellStateOutside
+	}
+
+	return m.state()
+}
+
+// Evaluate checks all ongoing stays against the rules and
+// returns the alerts for rules that are satisfied at the given time.
+func (d *Dwell) Evaluate(now time.Time) []Alert {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var alerts []Alert
+
+	for _, m := range d.members {
+		for _, r := range d.rules {
+			if !r.applies(m.FenceID) || m.Fired[r.Name] {
+				continue
+			}
+
+			if now.Sub(m.Since) < r.Duration || !r.Window.Active(now) {
+				continue
+			}
+
+			if m.Fired == nil {
+				m.Fired = make(map[string]bool)
+			}
+			m.Fired[r.Name] = true
+
+			alerts = append(alerts, Alert{
+				Rule:        r.Name,
+				TrackableID: m.TrackableID,
+				FenceID:     m.FenceID,
+				Since:       m.Since,
+				At:          now,
+			})
+		}
+	}
+
+	return alerts
+}
+
+// Watch evaluates the rules at every interval of the clock, emitting the alerts
+// on the returned channel. The channel is closed when the context is done.
+func (d *Dwell) Watch(ctx context.Context, interval time.Duration) <-chan Alert {
+	d.mu.Lock()
+	ticker := d.clock.NewTicker(interval)
+	d.mu.Unlock()
+
+	out := make(chan Alert, 64)
+
+	go func() {
+		defer close(out)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C():
+				for _, a := range d.Evaluate(now) {
+					select {
+					case out <- a:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// Save writes the current state in JSON format to w.
+func (d *Dwell) Save(w io.Writer) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	members := make([]*membership, 0, len(d.members))
+	for _, m := range d.members {
+		members = append(members, m)
+	}
+
+	return json.NewEncoder(w).Encode(members)
+}
+
+// Load replaces the current state with one previously written by Save.
+func (d *Dwell) Load(r io.Reader) error {
+	var members []*membership
+	if err := json.NewDecoder(r).Decode(&members); err != nil {
+		return fmt.Errorf("could not decode dwell state: %w", err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	loaded := make(map[membershipKey]*membership, len(members))
+	for i, m := range members {
+		if m == nil {
+			return fmt.Errorf("could not decode dwell state: null membership at index %d", i)
+		}
+		loaded[m.membershipKey] = m
+	}
+	d.members = loaded
+
+	return nil
+}
@@ -0,0 +1,179 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package alert
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+var (
+	trackableID = uuid.MustParse("9b59961e-2a6a-4712-86e7-aba5a3e8be1f")
+	fenceID     = uuid.MustParse("497f6eca-6276-4993-bfeb-53cbbbba6f08")
+	otherFence  = uuid.MustParse("a5865271-2e84-40d0-8f8f-e6f7ea15d103")
+	epoch       = time.Date(2023, 10, 17, 11, 0, 0, 0, time.UTC)
+)
+
+func TestDwellEvaluate(t *testing.T) {
+	d, err := NewDwell(
+		DwellRule{Name: "loading-bay", FenceID: fenceID, Duration: 10 * time.Minute},
+		DwellRule{Name: "any", Duration: 30 * time.Minute},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d.Enter(trackableID, fenceID, epoch)
+	d.Enter(trackableID, otherFence, epoch)
+
+	cases := []struct {
+		name  string
+		now   time.Time
+		rules []string
+		state DwellState
+	}{
+		{"not-elapsed", epoch.Add(5 * time.Minute), nil, DwellStateInside},
+		{"elapsed", epoch.Add(10 * time.Minute), []string{"loading-bay"}, DwellStateDwelling},
+		{"fires-once", epoch.Add(15 * time.Minute), nil, DwellStateDwelling},
+		{"any-fence", epoch.Add(30 * time.Minute), []string{"any", "any"}, DwellStateDwelling},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			alerts := d.Evaluate(tc.now)
+			if len(alerts) != len(tc.rules) {
+				t.Fatalf("expected %d alerts, got %d: %v", len(tc.rules), len(alerts), alerts)
+			}
+
+			for i, a := range alerts {
+				if a.Rule != tc.rules[i] {
+					t.Errorf("expected rule %s, got %s", tc.rules[i], a.Rule)
+				}
+				if !a.Since.Equal(epoch) {
+					t.Errorf("expected since %v, got %v", epoch, a.Since)
+				}
+			}
+
+			if s := d.State(trackableID, fenceID); s != tc.state {
+				t.Errorf("expected state %v, got %v", tc.state, s)
+			}
+		})
+	}
+
+	d.Exit(trackableID, fenceID)
+	if s := d.State(trackableID, fenceID); s != DwellStateOutside {
+		t.Errorf("expected state %v, got %v", DwellStateOutside, s)
+	}
+
+	// re-entering starts a new stay
+	d.Enter(trackableID, fenceID, epoch.Add(time.Hour))
+	if alerts := d.Evaluate(epoch.Add(time.Hour + 10*time.Minute)); len(alerts) != 1 {
+		t.Errorf("expected 1 alert after re-entry, got %d", len(alerts))
+	}
+}
+
+func TestDwellWatch(t *testing.T) {
+	d, err := NewDwell(DwellRule{Name: "loading-bay", FenceID: fenceID, Duration: 10 * time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clk := clock.NewFake(epoch)
+	d.SetClock(clk)
+	d.Enter(trackableID, fenceID, epoch)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	alerts := d.Watch(ctx, time.Minute)
+
+	// time is advanced until the evaluation following the dwell duration fires the rule
+	for {
+		select {
+		case a := <-alerts:
+			if a.Rule != "loading-bay" || a.At.Before(epoch.Add(10*time.Minute)) {
+				t.Errorf("unexpected alert: %+v", a)
+			}
+			return
+		default:
+			clk.Advance(time.Minute)
+		}
+	}
+}
+
+func TestDwellSaveLoad(t *testing.T) {
+	rule := DwellRule{Name: "loading-bay", FenceID: fenceID, Duration: 10 * time.Minute}
+
+	d, err := NewDwell(rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Enter(trackableID, fenceID, epoch)
+
+	var buf bytes.Buffer
+	if err := d.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := NewDwell(rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := restored.Load(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if s := restored.State(trackableID, fenceID); s != DwellStateInside {
+		t.Fatalf("expected state %v, got %v", DwellStateInside, s)
+	}
+
+	alerts := restored.Evaluate(epoch.Add(10 * time.Minute))
+	if len(alerts) != 1 || !alerts[0].Since.Equal(epoch) {
+		t.Fatalf("expected a single alert since %v, got %v", epoch, alerts)
+	}
+}
+
+func TestDwellLoadInvalid(t *testing.T) {
+	d, err := NewDwell(DwellRule{Name: "loading-bay", FenceID: fenceID, Duration: 10 * time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Enter(trackableID, fenceID, epoch)
+
+	for _, state := range []string{`[null]`, `{}`} {
+		if err := d.Load(strings.NewReader(state)); err == nil {
+			t.Errorf("%s: expected error", state)
+		}
+	}
+
+	// the current state is kept when loading fails
+	if s := d.State(trackableID, fenceID); s != DwellStateInside {
+		t.Errorf("expected state %v, got %v", DwellStateInside, s)
+	}
+}
+
+func TestNewDwellInvalid(t *testing.T) {
+	cases := []struct {
+		name  string
+		rules []DwellRule
+	}{
+		{"no-name", []DwellRule{{Duration: time.Minute}}},
+		{"no-duration", []DwellRule{{Name: "a"}}},
+		{"duplicated", []DwellRule{{Name: "a", Duration: time.Minute}, {Name: "a", Duration: time.Minute}}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewDwell(tc.rules...); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
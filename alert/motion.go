@@ -0,0 +1,138 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package alert
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+// MotionRule fires when a trackable starts moving faster than Speed while
+// the rule is active. For example, a forklift moving outside of shift hours.
+type MotionRule struct {
+	// Name identifies the rule in emitted alerts.
+	Name string `json:"name"`
+
+	// Trackables restricts the rule to the given trackables.
+	// If empty, the rule applies to every trackable.
+	Trackables []uuid.UUID `json:"trackables,omitempty"`
+
+	// Speed is the minimum speed in meters per second considered as moving.
+	Speed float64 `json:"speed"`
+
+	// Window restricts when the rule is active. If nil, the rule is always active.
+	Window *Window `json:"-"`
+}
+
+// applies reports if the rule applies to the given trackable.
+func (r MotionRule) applies(trackableID uuid.UUID) bool {
+	if len(r.Trackables) == 0 {
+		return true
+	}
+	for _, id := range r.Trackables {
+		if id == trackableID {
+			return true
+		}
+	}
+	return false
+}
+
+// motionKey identifies a rule being satisfied by a trackable.
+type motionKey struct {
+	rule        string
+	trackableID uuid.UUID
+}
+
+// Motion evaluates motion rules over location updates.
+// A rule fires once when a trackable starts moving, and is re-armed when the trackable stops.
+type Motion struct {
+	mu sync.Mutex
+
+	rules  []MotionRule
+	moving map[motionKey]bool
+
+	clock clock.Clock
+}
+
+// NewMotion returns a new motion evaluator for the given rules.
+func NewMotion(rules ...MotionRule) (*Motion, error) {
+	names := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		if r.Name == "" {
+			return nil, fmt.Errorf("motion rule must have a name")
+		}
+		if names[r.Name] {
+			return nil, fmt.Errorf("duplicated motion rule '%s'", r.Name)
+		}
+		if r.Speed < 0 {
+			return nil, fmt.Errorf("motion rule '%s' speed must not be negative", r.Name)
+		}
+		names[r.Name] = true
+	}
+
+	return &Motion{
+		rules:  rules,
+		moving: make(map[motionKey]bool),
+		clock:  clock.System,
+	}, nil
+}
+
+// SetClock sets the source of time of the locations without timestamp.
+func (m *Motion) SetClock(clk clock.Clock) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.clock = clock.Or(clk)
+}
+
+// Observe evaluates a location update and returns the alerts for the rules
+// satisfied by it. Locations without speed are ignored.
+func (m *Motion) Observe(loc omlox.Location) []Alert {
+	if loc.Speed == nil {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	at := m.clock.Now()
+	if loc.TimestampGenerated != nil {
+		at = *loc.TimestampGenerated
+	}
+
+	var alerts []Alert
+
+	for _, id := range loc.Trackables {
+		for _, r := range m.rules {
+			if !r.applies(id) {
+				continue
+			}
+
+			key := motionKey{rule: r.Name, trackableID: id}
+
+			if *loc.Speed <= r.Speed {
+				delete(m.moving, key)
+				continue
+			}
+
+			if m.moving[key] || !r.Window.Active(at) {
+				continue
+			}
+			m.moving[key] = true
+
+			alerts = append(alerts, Alert{
+				Rule:        r.Name,
+				TrackableID: id,
+				Since:       at,
+				At:          at,
+			})
+		}
+	}
+
+	return alerts
+}
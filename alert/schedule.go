@@ -0,0 +1,100 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package alert
+
+import (
+	"time"
+)
+
+// Schedule reports whether a point in time falls within a time window.
+type Schedule interface {
+	Contains(t time.Time) bool
+}
+
+// Window restricts the times at which a rule is active.
+// A nil window is always active.
+type Window struct {
+	// Schedule is the time window the rule refers to.
+	Schedule Schedule
+
+	// Outside makes the rule active outside of the schedule instead of inside.
+	// For example, a rule only active outside of shift hours.
+	Outside bool
+}
+
+// Active reports if a rule restricted by the window is active at the given time.
+func (w *Window) Active(t time.Time) bool {
+	if w == nil || w.Schedule == nil {
+		return true
+	}
+	return w.Schedule.Contains(t) != w.Outside
+}
+
+// Shift is a weekly recurring time window, such as working hours.
+type Shift struct {
+	// Days the shift starts on. If empty, the shift starts every day.
+	Days []time.Weekday
+
+	// Start is the offset from midnight at which the shift starts.
+	Start time.Duration
+
+	// End is the offset from midnight at which the shift ends.
+	// If End is not after Start, the shift ends on the following day.
+	End time.Duration
+
+	// Location is the time zone of the shift. If nil, UTC is assumed.
+	Location *time.Location
+}
+
+var _ Schedule = (*Shift)(nil)
+
+// Contains implements the Schedule interface.
+func (s Shift) Contains(t time.Time) bool {
+	loc := s.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+
+	length := s.End - s.Start
+	if length <= 0 {
+		length += 24 * time.Hour
+	}
+
+	// a shift crossing midnight might have started on the previous day
+	for _, day := range []time.Time{midnight(t), midnight(t.AddDate(0, 0, -1))} {
+		if len(s.Days) > 0 && !hasWeekday(s.Days, day.Weekday()) {
+			continue
+		}
+
+		begin := day.Add(s.Start)
+		if !t.Before(begin) && t.Before(begin.Add(length)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Schedules is a union of schedules.
+type Schedules []Schedule
+
+var _ Schedule = (Schedules)(nil)
+
+// Contains implements the Schedule interface.
+// It reports true if any of the schedules contains t.
+func (s Schedules) Contains(t time.Time) bool {
+	for _, sch := range s {
+		if sch.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// midnight returns the start of the day of t in its location.
+func midnight(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,178 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package alert
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+func TestShiftContains(t *testing.T) {
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+	day := Shift{Days: weekdays, Start: 8 * time.Hour, End: 16 * time.Hour}
+	night := Shift{Days: weekdays, Start: 22 * time.Hour, End: 6 * time.Hour}
+
+	cases := []struct {
+		name  string
+		shift Shift
+		t     time.Time
+		in    bool
+	}{
+		{"day-start", day, time.Date(2023, 10, 16, 8, 0, 0, 0, time.UTC), true},
+		{"day-end", day, time.Date(2023, 10, 16, 16, 0, 0, 0, time.UTC), false},
+		{"day-weekend", day, time.Date(2023, 10, 15, 10, 0, 0, 0, time.UTC), false},
+		{"night-before-midnight", night, time.Date(2023, 10, 16, 23, 0, 0, 0, time.UTC), true},
+		{"night-after-midnight", night, time.Date(2023, 10, 17, 5, 59, 0, 0, time.UTC), true},
+		{"night-friday-into-saturday", night, time.Date(2023, 10, 21, 2, 0, 0, 0, time.UTC), true},
+		{"night-sunday-into-monday", night, time.Date(2023, 10, 16, 2, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if in := tc.shift.Contains(tc.t); in != tc.in {
+				t.Errorf("expected %v, got %v", tc.in, in)
+			}
+		})
+	}
+}
+
+const maintenanceICal = `BEGIN:VCALENDAR
+VERSION:2.0
+BEGIN:VEVENT
+SUMMARY:Weekly maintenance
+DTSTART:20231017T060000Z
+DTEND:20231017T080000Z
+RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;UNTIL=20231130
+END:VEVENT
+BEGIN:VEVENT
+SUMMARY:Plant shutdown
+DTSTART;VALUE=DATE:20231225
+DURATION:P2D
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestLoadICal(t *testing.T) {
+	cal, err := LoadICal(strings.NewReader(maintenanceICal))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cal.Events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(cal.Events))
+	}
+
+	cases := []struct {
+		name string
+		t    time.Time
+		in   bool
+	}{
+		{"first-occurrence", time.Date(2023, 10, 17, 7, 0, 0, 0, time.UTC), true},
+		{"same-week-by-day", time.Date(2023, 10, 19, 6, 0, 0, 0, time.UTC), true},
+		{"off-week", time.Date(2023, 10, 24, 7, 0, 0, 0, time.UTC), false},
+		{"next-interval", time.Date(2023, 10, 31, 7, 59, 0, 0, time.UTC), true},
+		{"outside-hours", time.Date(2023, 10, 31, 8, 0, 0, 0, time.UTC), false},
+		{"before-start", time.Date(2023, 10, 3, 7, 0, 0, 0, time.UTC), false},
+		{"after-until", time.Date(2023, 12, 12, 7, 0, 0, 0, time.UTC), false},
+		{"all-day-event", time.Date(2023, 12, 26, 13, 0, 0, 0, time.UTC), true},
+		{"after-all-day-event", time.Date(2023, 12, 27, 0, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if in := cal.Contains(tc.t); in != tc.in {
+				t.Errorf("expected %v, got %v", tc.in, in)
+			}
+		})
+	}
+}
+
+func TestMotionOutsideShift(t *testing.T) {
+	forklift := uuid.MustParse("9b59961e-2a6a-4712-86e7-aba5a3e8be1f")
+
+	m, err := NewMotion(MotionRule{
+		Name:       "forklift-off-shift",
+		Trackables: []uuid.UUID{forklift},
+		Speed:      0.5,
+		Window: &Window{
+			Schedule: Shift{Start: 8 * time.Hour, End: 16 * time.Hour},
+			Outside:  true,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	location := func(hour int, speed float64) omlox.Location {
+		ts := time.Date(2023, 10, 17, hour, 0, 0, 0, time.UTC)
+		return omlox.Location{
+			Trackables:         []uuid.UUID{forklift},
+			TimestampGenerated: &ts,
+			Speed:              &speed,
+		}
+	}
+
+	cases := []struct {
+		name   string
+		loc    omlox.Location
+		alerts int
+	}{
+		{"moving-in-shift", location(10, 2), 0},
+		{"stopped", location(17, 0), 0},
+		{"moving-off-shift", location(18, 2), 1},
+		{"still-moving", location(19, 2), 0},
+		{"stopped-again", location(20, 0), 0},
+		{"moving-again", location(21, 2), 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if alerts := m.Observe(tc.loc); len(alerts) != tc.alerts {
+				t.Errorf("expected %d alerts, got %d", tc.alerts, len(alerts))
+			}
+		})
+	}
+}
+
+func TestMotionClock(t *testing.T) {
+	forklift := uuid.MustParse("9b59961e-2a6a-4712-86e7-aba5a3e8be1f")
+
+	m, err := NewMotion(MotionRule{
+		Name:       "forklift-off-shift",
+		Trackables: []uuid.UUID{forklift},
+		Speed:      0.5,
+		Window: &Window{
+			Schedule: Shift{Start: 8 * time.Hour, End: 16 * time.Hour},
+			Outside:  true,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clk := clock.NewFake(time.Date(2023, 10, 17, 10, 0, 0, 0, time.UTC))
+	m.SetClock(clk)
+
+	// locations without timestamp are evaluated at the time of the clock
+	speed := 2.0
+	loc := omlox.Location{Trackables: []uuid.UUID{forklift}, Speed: &speed}
+	if alerts := m.Observe(loc); len(alerts) != 0 {
+		t.Fatalf("expected no alerts in shift, got %d", len(alerts))
+	}
+
+	clk.Advance(8 * time.Hour)
+	alerts := m.Observe(loc)
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert off shift, got %d", len(alerts))
+	}
+	if want := clk.Now(); !alerts[0].At.Equal(want) || !alerts[0].Since.Equal(want) {
+		t.Errorf("expected alert at %v, got %v since %v", want, alerts[0].At, alerts[0].Since)
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package analytics provides helpers to analyse omlox™ location data,
+// such as location histories retrieved from the Hub.
+package analytics
+
+import (
+	"math"
+
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/internal/geo"
+)
+
+// degreesPerMeter returns the approximate size in degrees of one meter
+// along the longitude and latitude axis at the given latitude.
+func degreesPerMeter(lat float64) (lon float64, latd float64) {
+	latd = 1 / geo.MetersPerDegree
+
+	cos := math.Cos(lat * math.Pi / 180)
+	if cos < 1e-9 {
+		return latd, latd
+	}
+
+	return latd / cos, latd
+}
+
+// Distance returns the distance in meters between two positions in the given crs.
+// WGS84 positions use the great-circle distance, other coordinate reference
+// systems are assumed to be cartesian and in meters.
+func Distance(a, b geometry.Point, crs string) float64 {
+	if crs != omlox.CrsWGS84 {
+		return math.Hypot(b.X-a.X, b.Y-a.Y)
+	}
+	return geo.Distance(a, b)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package analytics
+
+import (
+	"time"
+
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+// CompactOptions configures how location streams are compacted. Zero values disable
+// the corresponding reduction.
+type CompactOptions struct {
+	// Interval keeps one location per interval of each track.
+	Interval time.Duration
+
+	// Distance keeps one location per distance in meters of each track.
+	Distance float64
+
+	// Tolerance simplifies each track within the tolerance in meters.
+	Tolerance float64
+
+	// Simplification is the algorithm simplifying the tracks.
+	// Default: DouglasPeucker
+	Simplification Simplification
+}
+
+// Compact downsamples a location stream to control its storage while preserving the
+// shape of the paths. The locations are split in tracks by provider, source, crs and
+// floor, and a location is kept when the interval or the distance since the last kept
+// location of its track is reached, then the tracks are simplified. The first and last
+// locations of each track are always kept, and kept locations remain in stream order.
+//
+// Locations without timestamp always reach the interval.
+func Compact(locations []omlox.Location, opts CompactOptions) []omlox.Location {
+	type trackKey struct {
+		provider string
+		source   string
+		crs      string
+		floor    float64
+	}
+
+	tracks := make(map[trackKey][]int)
+	for i, loc := range locations {
+		k := trackKey{provider: loc.ProviderID, source: loc.Source, crs: loc.Crs, floor: loc.Floor}
+		tracks[k] = append(tracks[k], i)
+	}
+
+	keep := make([]bool, len(locations))
+	for _, track := range tracks {
+		track = downsample(locations, track, opts)
+		track = simplify(locations, track, opts.Tolerance, opts.Simplification)
+		for _, i := range track {
+			keep[i] = true
+		}
+	}
+
+	compacted := make([]omlox.Location, 0, len(locations))
+	for i, loc := range locations {
+		if keep[i] {
+			compacted = append(compacted, loc)
+		}
+	}
+	return compacted
+}
+
+// downsample returns the locations of a track, by index, reaching the interval or
+// distance of the options since the last kept one.
+func downsample(locations []omlox.Location, track []int, opts CompactOptions) []int {
+	if len(track) < 3 || (opts.Interval <= 0 && opts.Distance <= 0) {
+		return track
+	}
+
+	kept := []int{track[0]}
+	for _, i := range track[1 : len(track)-1] {
+		last, loc := locations[kept[len(kept)-1]], locations[i]
+
+		reached := false
+		if opts.Interval > 0 {
+			reached = last.TimestampGenerated == nil || loc.TimestampGenerated == nil ||
+				loc.TimestampGenerated.Sub(*last.TimestampGenerated) >= opts.Interval
+		}
+		if opts.Distance > 0 && !reached {
+			reached = Distance(last.Position.Base(), loc.Position.Base(), loc.Crs) >= opts.Distance
+		}
+
+		if reached {
+			kept = append(kept, i)
+		}
+	}
+
+	return append(kept, track[len(track)-1])
+}
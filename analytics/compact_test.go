@@ -0,0 +1,115 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+func TestCompact(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// a location per second along x, turning along y after 10m
+	track := func(provider string, n int) []omlox.Location {
+		var locs []omlox.Location
+		for i := 0; i < n; i++ {
+			x, y := float64(i), 0.0
+			if i > 10 {
+				x, y = 10, float64(i-10)
+			}
+
+			loc := localLocation(x, y, 0)
+			loc.ProviderID = provider
+			ts := start.Add(time.Duration(i) * time.Second)
+			loc.TimestampGenerated = &ts
+			locs = append(locs, loc)
+		}
+		return locs
+	}
+
+	positions := func(locs []omlox.Location) [][2]float64 {
+		var ps [][2]float64
+		for _, loc := range locs {
+			p := loc.Position.Base()
+			ps = append(ps, [2]float64{p.X, p.Y})
+		}
+		return ps
+	}
+
+	tests := []struct {
+		name string
+		opts CompactOptions
+		want [][2]float64
+	}{
+		{
+			name: "interval",
+			opts: CompactOptions{Interval: 5 * time.Second},
+			want: [][2]float64{{0, 0}, {5, 0}, {10, 0}, {10, 5}, {10, 8}},
+		},
+		{
+			name: "distance",
+			opts: CompactOptions{Distance: 4},
+			want: [][2]float64{{0, 0}, {4, 0}, {8, 0}, {10, 4}, {10, 8}},
+		},
+		{
+			name: "tolerance",
+			opts: CompactOptions{Tolerance: 0.5},
+			want: [][2]float64{{0, 0}, {10, 0}, {10, 8}},
+		},
+		{
+			name: "none",
+			opts: CompactOptions{},
+			want: positions(track("a", 19)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := positions(Compact(track("a", 19), tt.opts))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected positions (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("tracks", func(t *testing.T) {
+		// interleaved tracks are compacted independently and kept in stream order
+		var locs []omlox.Location
+		a, b := track("a", 19), track("b", 19)
+		for i := range a {
+			locs = append(locs, a[i], b[i])
+		}
+
+		got := Compact(locs, CompactOptions{Tolerance: 0.5})
+		var providers string
+		for _, loc := range got {
+			providers += loc.ProviderID
+		}
+		if providers != "ababab" {
+			t.Errorf("expected providers ababab, got %s", providers)
+		}
+	})
+}
+
+func TestCompactWGS84(t *testing.T) {
+	// 1e-5 degrees of latitude are about 1.1m
+	var locs []omlox.Location
+	for _, lat := range []float64{0, 1e-5, 2e-5, 3e-5} {
+		loc := localLocation(0, lat, 0)
+		loc.Crs = omlox.CrsWGS84
+		locs = append(locs, loc)
+	}
+	locs[1].Position = localLocation(2e-5, 1e-5, 0).Position // about 2.2m off the path
+
+	if got := Compact(locs, CompactOptions{Tolerance: 2}); len(got) != 3 {
+		t.Errorf("expected 3 locations, got %d", len(got))
+	}
+	if got := Compact(locs, CompactOptions{Tolerance: 3}); len(got) != 2 {
+		t.Errorf("expected 2 locations, got %d", len(got))
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package analytics
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+// CoverageOptions configures the coverage analysis.
+type CoverageOptions struct {
+	// CellSize is the size of the square grid cells in meters.
+	CellSize float64
+
+	// MaxRange is the maximum range of the anchors in meters.
+	// A zero range considers every anchor in range.
+	MaxRange float64
+}
+
+// CoverageCell is the trilateration quality of a grid cell, evaluated at its center.
+type CoverageCell struct {
+	// Cell is the index of the grid cell.
+	Cell
+
+	// Anchors is the number of anchors in range.
+	Anchors int
+
+	// HDOP is the horizontal dilution of precision, infinite when the cell is not covered.
+	HDOP float64
+}
+
+// Quality returns a qualitative rating of the cell trilateration quality:
+// "excellent", "good", "moderate", "poor", or "none" when the cell is not covered.
+func (c CoverageCell) Quality() string {
+	switch {
+	case math.IsInf(c.HDOP, 1):
+		return "none"
+	case c.HDOP <= 2:
+		return "excellent"
+	case c.HDOP <= 4:
+		return "good"
+	case c.HDOP <= 6:
+		return "moderate"
+	default:
+		return "poor"
+	}
+}
+
+// CoverageMap is the coverage of an area by a set of anchors, on a regular grid.
+// Cell (0, 0) has its lower-left corner on the origin of the coordinate system.
+type CoverageMap struct {
+	// CellSize is the size of each cell in meters.
+	CellSize float64
+
+	// Cells are the cells with their center inside the area, ordered by row and column.
+	Cells []CoverageCell
+}
+
+// Coverage estimates the trilateration quality of anchors over an area, such as the
+// polygon of a zone, helping to plan UWB infrastructure before installation.
+// Positions are cartesian and in meters, e.g. local zone coordinates.
+func Coverage(anchors []geometry.Point, area *geometry.Poly, opts CoverageOptions) (*CoverageMap, error) {
+	if opts.CellSize <= 0 {
+		return nil, fmt.Errorf("coverage cell size must be positive")
+	}
+	if opts.MaxRange < 0 {
+		return nil, fmt.Errorf("anchor range must not be negative")
+	}
+	if area == nil || area.Exterior == nil {
+		return nil, fmt.Errorf("coverage area must not be empty")
+	}
+
+	m := &CoverageMap{CellSize: opts.CellSize}
+
+	rect := area.Rect()
+	lo := Cell{X: int(math.Floor(rect.Min.X / opts.CellSize)), Y: int(math.Floor(rect.Min.Y / opts.CellSize))}
+	hi := Cell{X: int(math.Floor(rect.Max.X / opts.CellSize)), Y: int(math.Floor(rect.Max.Y / opts.CellSize))}
+
+	for y := lo.Y; y <= hi.Y; y++ {
+		for x := lo.X; x <= hi.X; x++ {
+			c := Cell{X: x, Y: y}
+			center := m.center(c)
+			if !area.ContainsPoint(center) {
+				continue
+			}
+
+			cell := CoverageCell{Cell: c, HDOP: HDOP(anchors, center, opts.MaxRange)}
+			for _, a := range anchors {
+				if opts.MaxRange == 0 || math.Hypot(a.X-center.X, a.Y-center.Y) <= opts.MaxRange {
+					cell.Anchors++
+				}
+			}
+
+			m.Cells = append(m.Cells, cell)
+		}
+	}
+
+	return m, nil
+}
+
+// center returns the center of a cell.
+func (m *CoverageMap) center(c Cell) geometry.Point {
+	return geometry.Point{
+		X: (float64(c.X) + 0.5) * m.CellSize,
+		Y: (float64(c.Y) + 0.5) * m.CellSize,
+	}
+}
+
+// Covered returns the ratio of cells with a HDOP at or below the given maximum.
+func (m *CoverageMap) Covered(maxHDOP float64) float64 {
+	if len(m.Cells) == 0 {
+		return 0
+	}
+
+	n := 0
+	for _, c := range m.Cells {
+		if c.HDOP <= maxHDOP {
+			n++
+		}
+	}
+
+	return float64(n) / float64(len(m.Cells))
+}
+
+// GeoJSON returns the cells as a GeoJSON feature collection overlay. Each feature is a cell
+// polygon with the "anchors", "hdop" (null when not covered) and "quality" properties.
+// Coordinates are converted with the project function, if not nil (e.g. from local zone
+// coordinates to WGS84).
+func (m *CoverageMap) GeoJSON(project func(geometry.Point) geometry.Point) ([]byte, error) {
+	type polygon struct {
+		Type        string         `json:"type"`
+		Coordinates [][][2]float64 `json:"coordinates"`
+	}
+	type feature struct {
+		Type       string         `json:"type"`
+		Geometry   polygon        `json:"geometry"`
+		Properties map[string]any `json:"properties"`
+	}
+
+	features := make([]feature, 0, len(m.Cells))
+
+	for _, c := range m.Cells {
+		x0, y0 := float64(c.X)*m.CellSize, float64(c.Y)*m.CellSize
+		x1, y1 := x0+m.CellSize, y0+m.CellSize
+
+		ring := [][2]float64{{x0, y0}, {x1, y0}, {x1, y1}, {x0, y1}, {x0, y0}}
+		if project != nil {
+			for i, p := range ring {
+				q := project(geometry.Point{X: p[0], Y: p[1]})
+				ring[i] = [2]float64{q.X, q.Y}
+			}
+		}
+
+		var hdop any
+		if !math.IsInf(c.HDOP, 1) {
+			hdop = c.HDOP
+		}
+
+		features = append(features, feature{
+			Type:     "Feature",
+			Geometry: polygon{Type: "Polygon", Coordinates: [][][2]float64{ring}},
+			Properties: map[string]any{
+				"anchors": c.Anchors,
+				"hdop":    hdop,
+				"quality": c.Quality(),
+			},
+		})
+	}
+
+	return json.Marshal(struct {
+		Type     string    `json:"type"`
+		Features []feature `json:"features"`
+	}{
+		Type:     "FeatureCollection",
+		Features: features,
+	})
+}
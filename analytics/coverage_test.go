@@ -0,0 +1,85 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package analytics
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+func TestCoverage(t *testing.T) {
+	anchors := []geometry.Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}
+
+	// L-shaped area, extending beyond the anchors range
+	area := geometry.NewPoly([]geometry.Point{
+		{X: 0, Y: 0}, {X: 40, Y: 0}, {X: 40, Y: 5}, {X: 10, Y: 5}, {X: 10, Y: 10}, {X: 0, Y: 10}, {X: 0, Y: 0},
+	}, nil, nil)
+
+	m, err := Coverage(anchors, area, CoverageOptions{CellSize: 5, MaxRange: 15})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 2x2 cells of the square and 6 cells of the corridor
+	if len(m.Cells) != 10 {
+		t.Fatalf("expected 10 cells, got %d", len(m.Cells))
+	}
+
+	quality := make(map[Cell]string)
+	for _, c := range m.Cells {
+		quality[c.Cell] = c.Quality()
+	}
+
+	if q := quality[Cell{X: 0, Y: 0}]; q == "none" || q == "poor" {
+		t.Errorf("expected cell between anchors to be covered, got %s", q)
+	}
+	if q := quality[Cell{X: 7, Y: 0}]; q != "none" {
+		t.Errorf("expected cell out of range to be uncovered, got %s", q)
+	}
+	if r := m.Covered(6); r <= 0 || r >= 1 {
+		t.Errorf("expected partial coverage, got %v", r)
+	}
+
+	b, err := m.GeoJSON(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fc struct {
+		Features []struct {
+			Properties struct {
+				Anchors int      `json:"anchors"`
+				HDOP    *float64 `json:"hdop"`
+				Quality string   `json:"quality"`
+			} `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(b, &fc); err != nil {
+		t.Fatal(err)
+	}
+	if len(fc.Features) != len(m.Cells) {
+		t.Fatalf("expected %d features, got %d", len(m.Cells), len(fc.Features))
+	}
+	for i, f := range fc.Features {
+		if (f.Properties.HDOP == nil) != (m.Cells[i].Quality() == "none") {
+			t.Errorf("unexpected hdop of feature %d: %v", i, f.Properties.HDOP)
+		}
+	}
+}
+
+func TestCoverageOptions(t *testing.T) {
+	area := geometry.NewPoly([]geometry.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 0}}, nil, nil)
+
+	if _, err := Coverage(nil, area, CoverageOptions{}); err == nil {
+		t.Error("expected error for zero cell size")
+	}
+	if _, err := Coverage(nil, area, CoverageOptions{CellSize: 1, MaxRange: -1}); err == nil {
+		t.Error("expected error for negative range")
+	}
+	if _, err := Coverage(nil, nil, CoverageOptions{CellSize: 1}); err == nil {
+		t.Error("expected error for missing area")
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package analytics
+
+import (
+	"math"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+// minAnchors is the number of anchors in range required for an unambiguous 2D position.
+const minAnchors = 3
+
+// HDOP returns the horizontal dilution of precision of ranging positioning at the
+// given point, from the anchors within the maximum range in meters (or all of
+// them for a range of zero). Positions are cartesian and in meters.
+//
+// Lower values mean a better anchor geometry: below 2 is excellent, above 6 is poor.
+// Points seen by fewer than three anchors are not covered and have an infinite HDOP.
+func HDOP(anchors []geometry.Point, at geometry.Point, maxRange float64) float64 {
+	// normal matrix of the unit line-of-sight vectors, symmetric
+	var xx, xy, yy float64
+	n := 0
+
+	for _, a := range anchors {
+		dx, dy := a.X-at.X, a.Y-at.Y
+		r := math.Hypot(dx, dy)
+		if r == 0 || (maxRange > 0 && r > maxRange) {
+			continue
+		}
+
+		ux, uy := dx/r, dy/r
+		xx += ux * ux
+		xy += ux * uy
+		yy += uy * uy
+		n++
+	}
+
+	if n < minAnchors {
+		return math.Inf(1)
+	}
+
+	det := xx*yy - xy*xy
+	if det < 1e-9 {
+		return math.Inf(1)
+	}
+
+	// trace of the inverse normal matrix
+	return math.Sqrt((xx + yy) / det)
+}
@@ -0,0 +1,42 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package analytics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+func TestHDOP(t *testing.T) {
+	square := []geometry.Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}
+
+	tests := []struct {
+		name    string
+		anchors []geometry.Point
+		at      geometry.Point
+		rng     float64
+		want    float64
+	}{
+		{name: "center of square", anchors: square, at: geometry.Point{X: 5, Y: 5}, want: 1},
+		{name: "out of range", anchors: square, at: geometry.Point{X: 5, Y: 5}, rng: 5, want: math.Inf(1)},
+		{name: "two anchors", anchors: square[:2], at: geometry.Point{X: 5, Y: 5}, want: math.Inf(1)},
+		{name: "collinear", anchors: []geometry.Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 20, Y: 0}}, at: geometry.Point{X: 30, Y: 0}, want: math.Inf(1)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := HDOP(tc.anchors, tc.at, tc.rng)
+			if math.Abs(got-tc.want) > 1e-9 && !(math.IsInf(got, 1) && math.IsInf(tc.want, 1)) {
+				t.Errorf("expected hdop %v, got %v", tc.want, got)
+			}
+		})
+	}
+
+	// the geometry degrades outside of the anchors
+	if inside, outside := HDOP(square, geometry.Point{X: 5, Y: 5}, 0), HDOP(square, geometry.Point{X: 40, Y: 5}, 0); outside <= inside {
+		t.Errorf("expected hdop outside (%v) to be worse than inside (%v)", outside, inside)
+	}
+}
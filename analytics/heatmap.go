@@ -0,0 +1,285 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package analytics
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+// Heatmap bins locations into a regular grid to measure occupancy density.
+// Locations are grouped into layers by source (e.g. zone), crs and floor.
+type Heatmap struct {
+	// cell size in meters
+	cellSize float64
+
+	layers map[layerKey]*HeatmapLayer
+}
+
+// layerKey identifies a heatmap layer.
+type layerKey struct {
+	source string
+	crs    string
+	floor  float64
+}
+
+// NewHeatmap returns a new heatmap with square cells of the given size in meters.
+func NewHeatmap(cellSize float64) (*Heatmap, error) {
+	if cellSize <= 0 {
+		return nil, fmt.Errorf("heatmap cell size must be positive")
+	}
+
+	return &Heatmap{
+		cellSize: cellSize,
+		layers:   make(map[layerKey]*HeatmapLayer),
+	}, nil
+}
+
+// Add bins the given locations into the heatmap.
+//
+// Locations in WGS84 (EPSG:4326) have the cell size converted from meters to
+// degrees at the latitude of the first location of their layer. Other
+// coordinate reference systems are assumed to be in meters.
+func (h *Heatmap) Add(locations ...omlox.Location) {
+	for _, loc := range locations {
+		crs := loc.Crs
+		if crs == "" {
+			crs = omlox.CrsLocal
+		}
+
+		key := layerKey{source: loc.Source, crs: crs, floor: loc.Floor}
+		p := loc.Position.Base()
+
+		layer, ok := h.layers[key]
+		if !ok {
+			layer = &HeatmapLayer{
+				Source:     loc.Source,
+				Crs:        crs,
+				Floor:      loc.Floor,
+				CellWidth:  h.cellSize,
+				CellHeight: h.cellSize,
+				Counts:     make(map[Cell]int),
+			}
+
+			if crs == omlox.CrsWGS84 {
+				lon, lat := degreesPerMeter(p.Y)
+				layer.CellWidth, layer.CellHeight = h.cellSize*lon, h.cellSize*lat
+			}
+
+			h.layers[key] = layer
+		}
+
+		layer.add(p.X, p.Y)
+	}
+}
+
+// Layers returns the heatmap layers sorted by source, crs and floor.
+func (h *Heatmap) Layers() []*HeatmapLayer {
+	layers := make([]*HeatmapLayer, 0, len(h.layers))
+	for _, l := range h.layers {
+		layers = append(layers, l)
+	}
+
+	sort.Slice(layers, func(i, j int) bool {
+		a, b := layers[i], layers[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Crs != b.Crs {
+			return a.Crs < b.Crs
+		}
+		return a.Floor < b.Floor
+	})
+
+	return layers
+}
+
+// Cell is the index of a heatmap grid cell.
+// Cell (0, 0) has its lower-left corner on the origin of the coordinate system.
+type Cell struct {
+	X, Y int
+}
+
+// HeatmapLayer is the grid of location counts of a single source, crs and floor.
+type HeatmapLayer struct {
+	// Source is the source of the locations (e.g. the zone id).
+	Source string
+
+	// Crs is the coordinate reference system of the locations.
+	Crs string
+
+	// Floor of the locations.
+	Floor float64
+
+	// CellWidth and CellHeight are the size of each cell in crs units.
+	CellWidth  float64
+	CellHeight float64
+
+	// Counts holds the number of locations per cell.
+	Counts map[Cell]int
+}
+
+func (l *HeatmapLayer) add(x, y float64) {
+	c := Cell{
+		X: int(math.Floor(x / l.CellWidth)),
+		Y: int(math.Floor(y / l.CellHeight)),
+	}
+	l.Counts[c]++
+}
+
+// Max returns the highest count of the layer cells.
+func (l *HeatmapLayer) Max() int {
+	peak := 0
+	for _, n := range l.Counts {
+		peak = max(peak, n)
+	}
+	return peak
+}
+
+// bounds returns the minimum and maximum cell indexes.
+func (l *HeatmapLayer) bounds() (Cell, Cell) {
+	var lo, hi Cell
+	first := true
+
+	for c := range l.Counts {
+		if first {
+			lo, hi, first = c, c, false
+			continue
+		}
+		lo.X, lo.Y = min(lo.X, c.X), min(lo.Y, c.Y)
+		hi.X, hi.Y = max(hi.X, c.X), max(hi.Y, c.Y)
+	}
+
+	return lo, hi
+}
+
+// cells returns the occupied cells in a stable order.
+func (l *HeatmapLayer) cells() []Cell {
+	cells := make([]Cell, 0, len(l.Counts))
+	for c := range l.Counts {
+		cells = append(cells, c)
+	}
+
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].Y != cells[j].Y {
+			return cells[i].Y < cells[j].Y
+		}
+		return cells[i].X < cells[j].X
+	})
+
+	return cells
+}
+
+// GeoJSON returns the occupied cells of the layer as a GeoJSON feature collection.
+// Each feature is a cell polygon with the location "count" and normalized "density" properties.
+func (l *HeatmapLayer) GeoJSON() ([]byte, error) {
+	type geometry struct {
+		Type        string         `json:"type"`
+		Coordinates [][][2]float64 `json:"coordinates"`
+	}
+	type feature struct {
+		Type       string         `json:"type"`
+		Geometry   geometry       `json:"geometry"`
+		Properties map[string]any `json:"properties"`
+	}
+
+	peak := float64(l.Max())
+	features := make([]feature, 0, len(l.Counts))
+
+	for _, c := range l.cells() {
+		x0, y0 := float64(c.X)*l.CellWidth, float64(c.Y)*l.CellHeight
+		x1, y1 := x0+l.CellWidth, y0+l.CellHeight
+
+		n := l.Counts[c]
+		features = append(features, feature{
+			Type: "Feature",
+			Geometry: geometry{
+				Type:        "Polygon",
+				Coordinates: [][][2]float64{{{x0, y0}, {x1, y0}, {x1, y1}, {x0, y1}, {x0, y0}}},
+			},
+			Properties: map[string]any{
+				"count":   n,
+				"density": float64(n) / peak,
+			},
+		})
+	}
+
+	return json.Marshal(struct {
+		Type     string    `json:"type"`
+		Features []feature `json:"features"`
+	}{
+		Type:     "FeatureCollection",
+		Features: features,
+	})
+}
+
+// WritePNG encodes the layer as a PNG image to w, using the given number of
+// pixels per cell side. North is up, and cells without locations are transparent.
+func (l *HeatmapLayer) WritePNG(w io.Writer, pixelsPerCell int) error {
+	if pixelsPerCell <= 0 {
+		return fmt.Errorf("pixels per cell must be positive")
+	}
+	if len(l.Counts) == 0 {
+		return fmt.Errorf("heatmap layer is empty")
+	}
+
+	lo, hi := l.bounds()
+	width := (hi.X - lo.X + 1) * pixelsPerCell
+	height := (hi.Y - lo.Y + 1) * pixelsPerCell
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	peak := float64(l.Max())
+
+	for c, n := range l.Counts {
+		col := heatColor(float64(n) / peak)
+
+		// image y axis grows downwards
+		px := (c.X - lo.X) * pixelsPerCell
+		py := (hi.Y - c.Y) * pixelsPerCell
+
+		for y := py; y < py+pixelsPerCell; y++ {
+			for x := px; x < px+pixelsPerCell; x++ {
+				img.SetNRGBA(x, y, col)
+			}
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+// heatColors is the color ramp from low to high density.
+var heatColors = [...]color.NRGBA{
+	{R: 0, G: 0, B: 255, A: 160},
+	{R: 0, G: 255, B: 255, A: 180},
+	{R: 0, G: 255, B: 0, A: 200},
+	{R: 255, G: 255, B: 0, A: 220},
+	{R: 255, G: 0, B: 0, A: 255},
+}
+
+// heatColor interpolates the color ramp for a density between 0 and 1.
+func heatColor(density float64) color.NRGBA {
+	density = math.Max(0, math.Min(1, density))
+
+	pos := density * float64(len(heatColors)-1)
+	i := int(pos)
+	if i >= len(heatColors)-1 {
+		return heatColors[len(heatColors)-1]
+	}
+
+	a, b := heatColors[i], heatColors[i+1]
+	f := pos - float64(i)
+	lerp := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*f)
+	}
+
+	return color.NRGBA{R: lerp(a.R, b.R), G: lerp(a.G, b.G), B: lerp(a.B, b.B), A: lerp(a.A, b.A)}
+}
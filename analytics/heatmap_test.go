@@ -0,0 +1,109 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package analytics
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+
+	"github.com/nsf/jsondiff"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+func localLocation(x, y, floor float64) omlox.Location {
+	return omlox.Location{
+		Position: *omlox.NewPoint(geometry.Point{X: x, Y: y}),
+		Source:   "zone-a",
+		Crs:      omlox.CrsLocal,
+		Floor:    floor,
+	}
+}
+
+func TestHeatmap(t *testing.T) {
+	h, err := NewHeatmap(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h.Add(
+		localLocation(0.5, 0.5, 0),
+		localLocation(1.5, 1.9, 0),
+		localLocation(2.5, 0.5, 0),
+		localLocation(-0.5, 0.5, 0),
+		localLocation(0.5, 0.5, 1),
+	)
+
+	layers := h.Layers()
+	if len(layers) != 2 {
+		t.Fatalf("expected 2 layers, got %d", len(layers))
+	}
+
+	ground := layers[0]
+	if ground.Floor != 0 {
+		t.Fatalf("expected ground floor layer first, got floor %v", ground.Floor)
+	}
+
+	expected := map[Cell]int{{0, 0}: 2, {1, 0}: 1, {-1, 0}: 1}
+	for c, n := range expected {
+		if ground.Counts[c] != n {
+			t.Errorf("cell %v: expected %d locations, got %d", c, n, ground.Counts[c])
+		}
+	}
+
+	t.Run("geojson", func(t *testing.T) {
+		data, err := ground.GeoJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []byte(`{"type":"FeatureCollection","features":[` +
+			`{"type":"Feature","geometry":{"type":"Polygon","coordinates":[[[-2,0],[0,0],[0,2],[-2,2],[-2,0]]]},"properties":{"count":1,"density":0.5}},` +
+			`{"type":"Feature","geometry":{"type":"Polygon","coordinates":[[[0,0],[2,0],[2,2],[0,2],[0,0]]]},"properties":{"count":2,"density":1}},` +
+			`{"type":"Feature","geometry":{"type":"Polygon","coordinates":[[[2,0],[4,0],[4,2],[2,2],[2,0]]]},"properties":{"count":1,"density":0.5}}]}`)
+
+		opts := jsondiff.DefaultConsoleOptions()
+		if r, diff := jsondiff.Compare(want, data, &opts); r != jsondiff.FullMatch {
+			t.Fatalf("%s", diff)
+		}
+	})
+
+	t.Run("png", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := ground.WritePNG(&buf, 4); err != nil {
+			t.Fatal(err)
+		}
+
+		img, err := png.Decode(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if b := img.Bounds(); b.Dx() != 12 || b.Dy() != 4 {
+			t.Errorf("expected 12x4 image, got %dx%d", b.Dx(), b.Dy())
+		}
+	})
+}
+
+func TestHeatmapWGS84(t *testing.T) {
+	h, err := NewHeatmap(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loc := localLocation(7.815694, 48.130216, 0)
+	loc.Crs = omlox.CrsWGS84
+	h.Add(loc)
+
+	layer := h.Layers()[0]
+
+	// 10 meters are roughly 0.00009 degrees of latitude
+	if layer.CellHeight < 0.00008 || layer.CellHeight > 0.0001 {
+		t.Errorf("unexpected cell height in degrees: %v", layer.CellHeight)
+	}
+	if layer.CellWidth <= layer.CellHeight {
+		t.Errorf("expected cell width in degrees to be larger than height away from the equator")
+	}
+}
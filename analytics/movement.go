@@ -0,0 +1,229 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package analytics
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+// DefaultIdleSpeed is the speed in meters per second below which a trackable is considered idle.
+const DefaultIdleSpeed = 0.2
+
+// Period is the length of the time intervals a report is grouped by.
+type Period int
+
+// Defines values for Period.
+const (
+	PeriodHour Period = iota
+	PeriodDay
+	PeriodWeek
+)
+
+// FromString assigs itself from period name.
+func (p *Period) FromString(name string) error {
+	v, ok := map[string]Period{
+		PeriodHour.String(): PeriodHour,
+		PeriodDay.String():  PeriodDay,
+		PeriodWeek.String(): PeriodWeek,
+	}[name]
+
+	if !ok {
+		return fmt.Errorf("period %s not supported", name)
+	}
+
+	*p = v
+	return nil
+}
+
+// String return a text representation.
+func (p Period) String() string {
+	periods := [...]string{
+		"hour",
+		"day",
+		"week",
+	}
+
+	if int(p) < 0 || len(periods) <= int(p) {
+		return ""
+	}
+
+	return periods[p]
+}
+
+// Start returns the start of the period containing t, in the location of t.
+// Weeks start on Monday.
+func (p Period) Start(t time.Time) time.Time {
+	switch p {
+	case PeriodHour:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	case PeriodWeek:
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	}
+}
+
+// next returns the start of the period following the one starting at start.
+func (p Period) next(start time.Time) time.Time {
+	switch p {
+	case PeriodHour:
+		return start.Add(time.Hour)
+	case PeriodWeek:
+		return start.AddDate(0, 0, 7)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// MovementOptions configures how movement statistics are computed.
+type MovementOptions struct {
+	// Period groups the statistics by hour, day or week.
+	Period Period
+
+	// IdleSpeed is the speed in meters per second below which the trackable is
+	// considered idle. If zero, DefaultIdleSpeed is used.
+	IdleSpeed float64
+
+	// MaxGap is the maximum time between two consecutive locations for the
+	// trackable to be considered tracked in between. If zero, there is no limit.
+	MaxGap time.Duration
+
+	// Location is the time zone of the period boundaries. If nil, UTC is used.
+	Location *time.Location
+}
+
+// Movement holds the movement statistics of a trackable during a period.
+type Movement struct {
+	// TrackableID is the id of the trackable.
+	TrackableID uuid.UUID `json:"trackable_id"`
+
+	// Name is the name of the trackable.
+	Name string `json:"name,omitempty"`
+
+	// Start is the start of the period.
+	Start time.Time `json:"start"`
+
+	// Distance is the distance traveled in meters.
+	Distance float64 `json:"distance"`
+
+	// Moving is the time spent moving.
+	Moving time.Duration `json:"moving"`
+
+	// Idle is the time spent idle.
+	Idle time.Duration `json:"idle"`
+
+	// FenceCrossings is the number of fence entries and exits.
+	FenceCrossings int `json:"fence_crossings"`
+}
+
+// Movements computes the movement statistics of a trackable per period, from
+// its location history and fence events. Only periods with data are returned,
+// sorted by start time.
+//
+// Locations are expected to be the most significant locations of the trackable.
+// Locations without timestamp are ignored, and no distance is accounted between
+// locations of different sources, crs or floors. Time between two locations is
+// split proportionally between the periods it spans.
+func Movements(trackable omlox.Trackable, locations []omlox.Location, events []omlox.FenceEvent, opts MovementOptions) ([]Movement, error) {
+	if opts.IdleSpeed < 0 {
+		return nil, fmt.Errorf("idle speed must not be negative")
+	}
+	if opts.IdleSpeed == 0 {
+		opts.IdleSpeed = DefaultIdleSpeed
+	}
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+
+	periods := make(map[time.Time]*Movement)
+	period := func(t time.Time) *Movement {
+		start := opts.Period.Start(t.In(opts.Location))
+		m, ok := periods[start]
+		if !ok {
+			m = &Movement{TrackableID: trackable.ID, Name: trackable.Name, Start: start}
+			periods[start] = m
+		}
+		return m
+	}
+
+	locs := make([]omlox.Location, 0, len(locations))
+	for _, loc := range locations {
+		if loc.TimestampGenerated != nil {
+			locs = append(locs, loc)
+		}
+	}
+	sort.SliceStable(locs, func(i, j int) bool {
+		return locs[i].TimestampGenerated.Before(*locs[j].TimestampGenerated)
+	})
+
+	for i := 1; i < len(locs); i++ {
+		a, b := locs[i-1], locs[i]
+		if a.Source != b.Source || a.Crs != b.Crs || a.Floor != b.Floor {
+			continue
+		}
+
+		t0, t1 := *a.TimestampGenerated, *b.TimestampGenerated
+		dt := t1.Sub(t0)
+		if dt <= 0 || (opts.MaxGap > 0 && dt > opts.MaxGap) {
+			continue
+		}
+
+		d := Distance(a.Position.Base(), b.Position.Base(), a.Crs)
+		moving := d/dt.Seconds() >= opts.IdleSpeed
+
+		for t0.Before(t1) {
+			m := period(t0)
+
+			end := opts.Period.next(m.Start)
+			if end.After(t1) {
+				end = t1
+			}
+
+			span := end.Sub(t0)
+			m.Distance += d * float64(span) / float64(dt)
+			if moving {
+				m.Moving += span
+			} else {
+				m.Idle += span
+			}
+
+			t0 = end
+		}
+	}
+
+	for _, e := range events {
+		if involves(e, trackable.ID) && !e.Time().IsZero() {
+			period(e.Time()).FenceCrossings++
+		}
+	}
+
+	movements := make([]Movement, 0, len(periods))
+	for _, m := range periods {
+		movements = append(movements, *m)
+	}
+	sort.Slice(movements, func(i, j int) bool {
+		return movements[i].Start.Before(movements[j].Start)
+	})
+
+	return movements, nil
+}
+
+// involves reports whether the fence event was triggered by the trackable.
+func involves(e omlox.FenceEvent, trackableID uuid.UUID) bool {
+	if e.TrackableID != nil {
+		return *e.TrackableID == trackableID
+	}
+	for _, id := range e.Trackables {
+		if id == trackableID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package analytics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+func TestMovements(t *testing.T) {
+	var (
+		id    = uuid.MustParse("11111111-1111-1111-1111-111111111111")
+		other = uuid.MustParse("22222222-2222-2222-2222-222222222222")
+		fence = uuid.MustParse("a0ac3bd3-a41b-4a1c-b0c8-2a4ab3f5a6e3")
+
+		day = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	at := func(loc omlox.Location, ts time.Time) omlox.Location {
+		loc.TimestampGenerated = &ts
+		return loc
+	}
+
+	locations := []omlox.Location{
+		// moving 100m in 100s, on the first day
+		at(localLocation(0, 0, 0), day.Add(10*time.Hour)),
+		at(localLocation(100, 0, 0), day.Add(10*time.Hour+100*time.Second)),
+		// idle for 10 minutes
+		at(localLocation(101, 0, 0), day.Add(10*time.Hour+700*time.Second)),
+		// untracked for hours, moving 20m over midnight, split evenly between both days
+		at(localLocation(0, 0, 0), day.Add(24*time.Hour-10*time.Second)),
+		at(localLocation(20, 0, 0), day.Add(24*time.Hour+10*time.Second)),
+		// different floor, not accounted
+		at(localLocation(20, 0, 1), day.Add(24*time.Hour+20*time.Second)),
+		// without timestamp, ignored
+		localLocation(500, 0, 0),
+	}
+
+	entry := day.Add(11 * time.Hour)
+	exit := day.Add(25 * time.Hour)
+	events := []omlox.FenceEvent{
+		{FenceID: fence, TrackableID: &id, EventType: omlox.FenceEventTypeRegionEntry, EntryTime: &entry},
+		{FenceID: fence, Trackables: []uuid.UUID{id}, EventType: omlox.FenceEventTypeRegionExit, EntryTime: &entry, ExitTime: &exit},
+		{FenceID: fence, TrackableID: &other, EventType: omlox.FenceEventTypeRegionEntry, EntryTime: &entry},
+	}
+
+	movements, err := Movements(omlox.Trackable{ID: id, Name: "forklift"}, locations, events, MovementOptions{Period: PeriodDay, MaxGap: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Movement{
+		{
+			TrackableID:    id,
+			Name:           "forklift",
+			Start:          day,
+			Distance:       111,
+			Moving:         100*time.Second + 10*time.Second,
+			Idle:           600 * time.Second,
+			FenceCrossings: 1,
+		},
+		{
+			TrackableID:    id,
+			Name:           "forklift",
+			Start:          day.Add(24 * time.Hour),
+			Distance:       10,
+			Moving:         10 * time.Second,
+			FenceCrossings: 1,
+		},
+	}
+
+	approx := cmp.Comparer(func(a, b float64) bool { return math.Abs(a-b) < 1e-9 })
+	if diff := cmp.Diff(want, movements, approx); diff != "" {
+		t.Errorf("unexpected movements (-want +got):\n%s", diff)
+	}
+}
+
+func TestPeriodStart(t *testing.T) {
+	ts := time.Date(2024, 1, 4, 15, 30, 10, 0, time.UTC) // thursday
+
+	tests := []struct {
+		period Period
+		want   time.Time
+	}{
+		{PeriodHour, time.Date(2024, 1, 4, 15, 0, 0, 0, time.UTC)},
+		{PeriodDay, time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{PeriodWeek, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.period.String(), func(t *testing.T) {
+			if got := tt.period.Start(ts); !got.Equal(tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDistanceWGS84(t *testing.T) {
+	// one degree of latitude is roughly 111km
+	a := geometry.Point{X: 7.8, Y: 48}
+	b := geometry.Point{X: 7.8, Y: 49}
+
+	if d := Distance(a, b, omlox.CrsWGS84); math.Abs(d-111319) > 1 {
+		t.Errorf("unexpected distance: %v", d)
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package analytics
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+// Simplification is an algorithm simplifying trajectories.
+type Simplification int
+
+// Defines values for Simplification.
+const (
+	// DouglasPeucker keeps the locations farther than the tolerance from the simplified path.
+	DouglasPeucker Simplification = iota
+
+	// Visvalingam drops the locations whose effective area, the area of the triangle they
+	// form with their neighbours, is below the square of the tolerance. It preserves the
+	// overall shape of the path better than DouglasPeucker, at the expense of its extremes.
+	Visvalingam
+)
+
+// Simplifications returns a list of the string representation of the simplifications.
+func Simplifications() []string {
+	return []string{DouglasPeucker.String(), Visvalingam.String()}
+}
+
+// FromString assigs itself from simplification name.
+func (s *Simplification) FromString(name string) error {
+	v, ok := map[string]Simplification{
+		DouglasPeucker.String(): DouglasPeucker,
+		Visvalingam.String():    Visvalingam,
+	}[name]
+
+	if !ok {
+		return fmt.Errorf("simplification %s not supported", name)
+	}
+
+	*s = v
+	return nil
+}
+
+// String return a text representation.
+func (s Simplification) String() string {
+	simplifications := [...]string{
+		"douglas-peucker",
+		"visvalingam",
+	}
+
+	if int(s) < 0 || len(simplifications) <= int(s) {
+		return ""
+	}
+
+	return simplifications[s]
+}
+
+// Simplify simplifies a trajectory within the tolerance in meters, such as to render
+// long tracks. The first and last locations are always kept, and kept locations remain
+// in order. Locations are expected to be in chronological order and in the same crs:
+// WGS84 positions are projected to meters around the first one, other coordinate
+// reference systems are assumed to be cartesian and in meters.
+func Simplify(locations []omlox.Location, tolerance float64, s Simplification) []omlox.Location {
+	track := make([]int, len(locations))
+	for i := range track {
+		track[i] = i
+	}
+
+	track = simplify(locations, track, tolerance, s)
+
+	simplified := make([]omlox.Location, len(track))
+	for j, i := range track {
+		simplified[j] = locations[i]
+	}
+	return simplified
+}
+
+// simplify returns the locations of a track, by index, kept by the simplification
+// with the tolerance in meters.
+func simplify(locations []omlox.Location, track []int, tolerance float64, s Simplification) []int {
+	if len(track) < 3 || tolerance <= 0 {
+		return track
+	}
+
+	// WGS84 positions are projected to meters around the first position
+	origin := locations[track[0]].Position.Base()
+	sx, sy := 1.0, 1.0
+	if locations[track[0]].Crs == omlox.CrsWGS84 {
+		lon, lat := degreesPerMeter(origin.Y)
+		sx, sy = 1/lon, 1/lat
+	}
+
+	xs, ys := make([]float64, len(track)), make([]float64, len(track))
+	for j, i := range track {
+		p := locations[i].Position.Base()
+		xs[j], ys[j] = (p.X-origin.X)*sx, (p.Y-origin.Y)*sy
+	}
+
+	var keep []bool
+	switch s {
+	case Visvalingam:
+		keep = visvalingam(xs, ys, tolerance*tolerance)
+	default:
+		keep = douglasPeucker(xs, ys, tolerance)
+	}
+
+	kept := make([]int, 0, len(track))
+	for j, i := range track {
+		if keep[j] {
+			kept = append(kept, i)
+		}
+	}
+	return kept
+}
+
+// douglasPeucker reports the points of a path kept by the Douglas-Peucker algorithm.
+func douglasPeucker(xs, ys []float64, tolerance float64) []bool {
+	keep := make([]bool, len(xs))
+	keep[0], keep[len(xs)-1] = true, true
+
+	stack := [][2]int{{0, len(xs) - 1}}
+	for len(stack) > 0 {
+		first, last := stack[len(stack)-1][0], stack[len(stack)-1][1]
+		stack = stack[:len(stack)-1]
+
+		farthest, distance := -1, tolerance
+		for j := first + 1; j < last; j++ {
+			if d := segmentDistance(xs[j], ys[j], xs[first], ys[first], xs[last], ys[last]); d > distance {
+				farthest, distance = j, d
+			}
+		}
+
+		if farthest >= 0 {
+			keep[farthest] = true
+			stack = append(stack, [2]int{first, farthest}, [2]int{farthest, last})
+		}
+	}
+
+	return keep
+}
+
+// visvalingam reports the points of a path kept by the Visvalingam-Whyatt algorithm
+// with the minimum effective area.
+func visvalingam(xs, ys []float64, minArea float64) []bool {
+	n := len(xs)
+
+	keep := make([]bool, n)
+	prev, next := make([]int, n), make([]int, n)
+	for i := range keep {
+		keep[i], prev[i], next[i] = true, i-1, i+1
+	}
+
+	area := func(i int) float64 {
+		a, b := prev[i], next[i]
+		return math.Abs((xs[a]-xs[i])*(ys[b]-ys[i])-(xs[b]-xs[i])*(ys[a]-ys[i])) / 2
+	}
+
+	h := &areaHeap{pos: make([]int, n)}
+	for i := 1; i < n-1; i++ {
+		heap.Push(h, vertex{i, area(i)})
+	}
+
+	for h.Len() > 0 {
+		v := heap.Pop(h).(vertex)
+		if v.area >= minArea {
+			break
+		}
+
+		keep[v.index] = false
+		a, b := prev[v.index], next[v.index]
+		next[a], prev[b] = b, a
+
+		// the areas of the neighbours never decrease below the area of the removed point,
+		// so points are removed in the order of their effective areas
+		for _, i := range []int{a, b} {
+			if i > 0 && i < n-1 {
+				h.update(i, math.Max(area(i), v.area))
+			}
+		}
+	}
+
+	return keep
+}
+
+// vertex is a point of a path with its effective area.
+type vertex struct {
+	index int
+	area  float64
+}
+
+// areaHeap is a min-heap of vertices by effective area.
+type areaHeap struct {
+	vertices []vertex
+	pos      []int
+}
+
+func (h *areaHeap) Len() int           { return len(h.vertices) }
+func (h *areaHeap) Less(i, j int) bool { return h.vertices[i].area < h.vertices[j].area }
+
+func (h *areaHeap) Swap(i, j int) {
+	h.vertices[i], h.vertices[j] = h.vertices[j], h.vertices[i]
+	h.pos[h.vertices[i].index], h.pos[h.vertices[j].index] = i, j
+}
+
+func (h *areaHeap) Push(x any) {
+	v := x.(vertex)
+	h.pos[v.index] = len(h.vertices)
+	h.vertices = append(h.vertices, v)
+}
+
+func (h *areaHeap) Pop() any {
+	v := h.vertices[len(h.vertices)-1]
+	h.vertices = h.vertices[:len(h.vertices)-1]
+	return v
+}
+
+// update sets the area of a vertex in the heap.
+func (h *areaHeap) update(index int, area float64) {
+	i := h.pos[index]
+	h.vertices[i].area = area
+	heap.Fix(h, i)
+}
+
+// segmentDistance returns the distance from the point p to the segment from a to b.
+func segmentDistance(px, py, ax, ay, bx, by float64) float64 {
+	dx, dy := bx-ax, by-ay
+
+	t := 0.0
+	if length := dx*dx + dy*dy; length > 0 {
+		t = math.Max(0, math.Min(1, ((px-ax)*dx+(py-ay)*dy)/length))
+	}
+
+	return math.Hypot(px-(ax+t*dx), py-(ay+t*dy))
+}
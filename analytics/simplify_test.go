@@ -0,0 +1,66 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package analytics
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+func TestSimplify(t *testing.T) {
+	var locations []omlox.Location
+	for _, p := range [][2]float64{{0, 0}, {1, 0.1}, {2, 0}, {3, 0}, {3, 3}, {3.1, 4}, {3, 6}} {
+		locations = append(locations, localLocation(p[0], p[1], 0))
+	}
+
+	positions := func(locs []omlox.Location) [][2]float64 {
+		var ps [][2]float64
+		for _, loc := range locs {
+			p := loc.Position.Base()
+			ps = append(ps, [2]float64{p.X, p.Y})
+		}
+		return ps
+	}
+
+	tests := []struct {
+		simplification Simplification
+		tolerance      float64
+		want           [][2]float64
+	}{
+		{DouglasPeucker, 0.5, [][2]float64{{0, 0}, {3, 0}, {3, 6}}},
+		{DouglasPeucker, 0.05, [][2]float64{{0, 0}, {1, 0.1}, {3, 0}, {3, 3}, {3.1, 4}, {3, 6}}},
+		{Visvalingam, 1, [][2]float64{{0, 0}, {3, 0}, {3, 6}}},
+		// the effective area of 0.3 of the sixth point is above 0.5*0.5
+		{Visvalingam, 0.5, [][2]float64{{0, 0}, {3, 0}, {3.1, 4}, {3, 6}}},
+		{Visvalingam, 0, positions(locations)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.simplification.String(), func(t *testing.T) {
+			got := positions(Simplify(locations, tt.tolerance, tt.simplification))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected positions with tolerance %v (-want +got):\n%s", tt.tolerance, diff)
+			}
+		})
+	}
+}
+
+func TestSimplificationFromString(t *testing.T) {
+	for _, name := range Simplifications() {
+		var s Simplification
+		if err := s.FromString(name); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if s.String() != name {
+			t.Errorf("expected %s, got %s", name, s)
+		}
+	}
+
+	var s Simplification
+	if err := s.FromString("radial"); err == nil {
+		t.Error("expected error for unknown simplification")
+	}
+}
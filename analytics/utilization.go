@@ -0,0 +1,175 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+// FenceUtilization holds the occupancy statistics of a fence over a time interval.
+type FenceUtilization struct {
+	// FenceID is the id of the fence.
+	FenceID uuid.UUID `json:"fence_id"`
+
+	// Name is the name of the fence.
+	Name string `json:"name,omitempty"`
+
+	// Visits is the number of fence entries.
+	Visits int `json:"visits"`
+
+	// AvgOccupancy is the time-weighted average number of trackables inside the fence.
+	AvgOccupancy float64 `json:"avg_occupancy"`
+
+	// MaxOccupancy is the maximum number of trackables inside the fence at the same time.
+	MaxOccupancy int `json:"max_occupancy"`
+
+	// Dwell is the distribution of completed stays inside the fence.
+	Dwell DwellDistribution `json:"dwell"`
+}
+
+// DwellDistribution summarizes a set of dwell times.
+type DwellDistribution struct {
+	// Count is the number of completed stays.
+	Count int `json:"count"`
+
+	Mean   time.Duration `json:"mean"`
+	Median time.Duration `json:"median"`
+	P90    time.Duration `json:"p90"`
+	Max    time.Duration `json:"max"`
+}
+
+// Utilization computes the occupancy statistics of the given fences from
+// their fence events within the time interval [from, to).
+//
+// Trackables are assumed to be outside of every fence at the start of the
+// interval, unless an exit event without entry is found. Stays that are still
+// ongoing at the end of the interval count towards occupancy, but not towards
+// the dwell distribution.
+func Utilization(fences []omlox.Fence, events []omlox.FenceEvent, from time.Time, to time.Time) ([]FenceUtilization, error) {
+	if !from.Before(to) {
+		return nil, fmt.Errorf("start time must be before end time")
+	}
+
+	byFence := make(map[uuid.UUID][]omlox.FenceEvent, len(fences))
+	for _, e := range events {
+		byFence[e.FenceID] = append(byFence[e.FenceID], e)
+	}
+
+	stats := make([]FenceUtilization, 0, len(fences))
+	for _, f := range fences {
+		u := fenceUtilization(byFence[f.ID], from, to)
+		u.FenceID = f.ID
+		u.Name = f.Name
+		stats = append(stats, u)
+	}
+
+	return stats, nil
+}
+
+// fenceUtilization computes the occupancy statistics from the events of a single fence.
+func fenceUtilization(events []omlox.FenceEvent, from time.Time, to time.Time) FenceUtilization {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time().Before(events[j].Time())
+	})
+
+	var (
+		u      FenceUtilization
+		dwells []time.Duration
+		area   float64 // integral of the occupancy over time, in occupancy-nanoseconds
+		last   = from
+		inside = make(map[string]time.Time)
+	)
+
+	advance := func(t time.Time) {
+		if t.After(last) {
+			area += float64(len(inside)) * float64(t.Sub(last))
+			last = t
+		}
+	}
+
+	for _, e := range events {
+		ts := e.Time()
+		if ts.Before(from) || !ts.Before(to) {
+			continue
+		}
+
+		advance(ts)
+		key := occupantKey(e)
+
+		switch e.EventType {
+		case omlox.FenceEventTypeRegionEntry:
+			if _, ok := inside[key]; !ok {
+				inside[key] = ts
+				u.Visits++
+			}
+		case omlox.FenceEventTypeRegionExit:
+			entry, ok := inside[key]
+			if !ok {
+				// the stay started before the interval, so its
+				// occupancy must be accounted from the start
+				area += float64(ts.Sub(from))
+				continue
+			}
+
+			delete(inside, key)
+			dwells = append(dwells, ts.Sub(entry))
+		}
+
+		u.MaxOccupancy = max(u.MaxOccupancy, len(inside))
+	}
+
+	advance(to)
+
+	u.AvgOccupancy = area / float64(to.Sub(from))
+	u.Dwell = distribution(dwells)
+
+	return u
+}
+
+// occupantKey identifies who triggered a fence event: the trackable if
+// known, otherwise the location provider.
+func occupantKey(e omlox.FenceEvent) string {
+	if e.TrackableID != nil {
+		return e.TrackableID.String()
+	}
+	if len(e.Trackables) == 1 {
+		return e.Trackables[0].String()
+	}
+	return e.ProviderID
+}
+
+// distribution summarizes the given dwell times.
+func distribution(dwells []time.Duration) DwellDistribution {
+	if len(dwells) == 0 {
+		return DwellDistribution{}
+	}
+
+	sort.Slice(dwells, func(i, j int) bool { return dwells[i] < dwells[j] })
+
+	var total time.Duration
+	for _, d := range dwells {
+		total += d
+	}
+
+	return DwellDistribution{
+		Count:  len(dwells),
+		Mean:   total / time.Duration(len(dwells)),
+		Median: percentile(dwells, 0.5),
+		P90:    percentile(dwells, 0.9),
+		Max:    dwells[len(dwells)-1],
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted values.
+func percentile[T any](sorted []T, p float64) T {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	rank = max(0, min(rank, len(sorted)-1))
+	return sorted[rank]
+}
@@ -0,0 +1,97 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+func fenceEvent(fenceID uuid.UUID, trackable string, typ omlox.FenceEventType, at time.Time) omlox.FenceEvent {
+	id := uuid.MustParse(trackable)
+	e := omlox.FenceEvent{
+		FenceID:     fenceID,
+		TrackableID: &id,
+		EventType:   typ,
+		EntryTime:   &at,
+	}
+	if typ == omlox.FenceEventTypeRegionExit {
+		e.ExitTime = &at
+	}
+	return e
+}
+
+func TestUtilization(t *testing.T) {
+	var (
+		fenceA = uuid.MustParse("a0ac3bd3-a41b-4a1c-b0c8-2a4ab3f5a6e3")
+		fenceB = uuid.MustParse("b0ac3bd3-a41b-4a1c-b0c8-2a4ab3f5a6e3")
+
+		t1 = "11111111-1111-1111-1111-111111111111"
+		t2 = "22222222-2222-2222-2222-222222222222"
+		t3 = "33333333-3333-3333-3333-333333333333"
+
+		from = time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+		to   = from.Add(10 * time.Minute)
+		at   = func(m int) time.Time { return from.Add(time.Duration(m) * time.Minute) }
+	)
+
+	fences := []omlox.Fence{
+		{ID: fenceA, Name: "dock"},
+		{ID: fenceB, Name: "storage"},
+	}
+
+	events := []omlox.FenceEvent{
+		// out of order on purpose
+		fenceEvent(fenceA, t1, omlox.FenceEventTypeRegionExit, at(4)),
+		fenceEvent(fenceA, t1, omlox.FenceEventTypeRegionEntry, at(0)),
+		fenceEvent(fenceA, t2, omlox.FenceEventTypeRegionEntry, at(2)),
+		fenceEvent(fenceA, t2, omlox.FenceEventTypeRegionExit, at(8)),
+		// still inside at the end of the interval
+		fenceEvent(fenceA, t3, omlox.FenceEventTypeRegionEntry, at(6)),
+		// outside of the interval
+		fenceEvent(fenceA, t1, omlox.FenceEventTypeRegionEntry, at(20)),
+		// entered before the interval
+		fenceEvent(fenceB, t1, omlox.FenceEventTypeRegionExit, at(5)),
+	}
+
+	stats, err := Utilization(fences, events, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []FenceUtilization{
+		{
+			FenceID: fenceA,
+			Name:    "dock",
+			Visits:  3,
+			// (4 + 6 + 4) occupied minutes over 10 minutes
+			AvgOccupancy: 1.4,
+			MaxOccupancy: 2,
+			Dwell: DwellDistribution{
+				Count:  2,
+				Mean:   5 * time.Minute,
+				Median: 4 * time.Minute,
+				P90:    6 * time.Minute,
+				Max:    6 * time.Minute,
+			},
+		},
+		{
+			FenceID:      fenceB,
+			Name:         "storage",
+			AvgOccupancy: 0.5,
+		},
+	}
+
+	if diff := cmp.Diff(want, stats); diff != "" {
+		t.Errorf("unexpected utilization (-want +got):\n%s", diff)
+	}
+
+	if _, err := Utilization(fences, events, to, from); err == nil {
+		t.Errorf("expected error for invalid time interval")
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// anchorProperty is the location provider property holding the anchor details.
+const anchorProperty = "anchor"
+
+// Anchor defines model for a fixed UWB anchor of the positioning infrastructure.
+//
+// The omlox™ Hub API has no dedicated infrastructure endpoints, so anchors are
+// registered as UWB location providers with the anchor details in their properties.
+//
+//easyjson:json
+type Anchor struct {
+	// The anchor unique identifier, e.g. the mac address of the UWB anchor.
+	ID string `json:"id"`
+
+	// An optional name for the anchor.
+	Name string `json:"name,omitempty"`
+
+	// The surveyed position of the anchor, interpreted according to crs.
+	Position Point `json:"position"`
+
+	// A projection identifier defining the projection of the position, either a valid EPSG identifier or 'local'.
+	// If the crs field is not present, 'local' MUST be assumed as the default.
+	Crs string `json:"crs,omitempty"`
+
+	// The id of the zone of the anchor, required to interpret local positions.
+	ZoneID string `json:"zone_id,omitempty"`
+
+	// The building floor of the anchor. Floor 0 represents the floor designated as 'ground'.
+	Floor float64 `json:"floor,omitempty"`
+
+	// The air interface parameters of the anchor.
+	AirInterface AirInterface `json:"air_interface"`
+}
+
+// AirInterface defines model for the UWB air interface parameters of an anchor.
+// Zero values are left to the defaults of the positioning system.
+//
+//easyjson:json
+type AirInterface struct {
+	// The UWB channel (e.g. 5 or 9).
+	Channel int `json:"channel,omitempty"`
+
+	// The pulse repetition frequency in MHz, either 16 or 64.
+	PRF int `json:"prf,omitempty"`
+
+	// The preamble length in symbols.
+	PreambleLength int `json:"preamble_length,omitempty"`
+
+	// The preamble code.
+	PreambleCode int `json:"preamble_code,omitempty"`
+
+	// The data rate in kbps, either 110, 850 or 6800.
+	DataRate int `json:"data_rate,omitempty"`
+
+	// The transmit power in dBm.
+	TxPower *float64 `json:"tx_power,omitempty"`
+}
+
+// Validate checks the air interface parameters are valid IEEE 802.15.4 UWB parameters.
+func (a AirInterface) Validate() error {
+	if a.Channel != 0 && !slices.Contains([]int{1, 2, 3, 4, 5, 7, 8, 9, 10, 11, 12, 13, 14}, a.Channel) {
+		return fmt.Errorf("invalid uwb channel %d", a.Channel)
+	}
+	if a.PRF != 0 && a.PRF != 16 && a.PRF != 64 {
+		return fmt.Errorf("invalid pulse repetition frequency %d MHz", a.PRF)
+	}
+	if a.PreambleLength != 0 && !slices.Contains([]int{16, 24, 32, 48, 64, 128, 256, 512, 1024, 1536, 2048, 4096}, a.PreambleLength) {
+		return fmt.Errorf("invalid preamble length %d", a.PreambleLength)
+	}
+	if a.PreambleCode < 0 || a.PreambleCode > 32 {
+		return fmt.Errorf("invalid preamble code %d", a.PreambleCode)
+	}
+	if a.DataRate != 0 && a.DataRate != 110 && a.DataRate != 850 && a.DataRate != 6800 {
+		return fmt.Errorf("invalid data rate %d kbps", a.DataRate)
+	}
+
+	return nil
+}
+
+// Validate checks the anchor can be registered.
+func (a Anchor) Validate() error {
+	if a.ID == "" {
+		return fmt.Errorf("anchor id must not be empty")
+	}
+	if (a.Crs == "" || a.Crs == CrsLocal) && a.ZoneID == "" {
+		return fmt.Errorf("anchor %s with local position requires a zone", a.ID)
+	}
+
+	if err := a.AirInterface.Validate(); err != nil {
+		return fmt.Errorf("anchor %s: %w", a.ID, err)
+	}
+
+	return nil
+}
+
+// Provider returns the location provider registering the anchor, updating the given provider.
+// Other properties of the provider are preserved.
+func (a Anchor) Provider(base LocationProvider) (LocationProvider, error) {
+	props := make(map[string]json.RawMessage)
+	if len(base.Properties) != 0 {
+		if err := json.Unmarshal(base.Properties, &props); err != nil {
+			return base, fmt.Errorf("could not decode properties of provider %s: %w", base.ID, err)
+		}
+	}
+
+	anchor, err := json.Marshal(a)
+	if err != nil {
+		return base, err
+	}
+	props[anchorProperty] = anchor
+
+	base.Properties, err = json.Marshal(props)
+	if err != nil {
+		return base, err
+	}
+
+	base.ID = a.ID
+	base.Type = LocationProviderTypeUwb
+	if a.Name != "" {
+		base.Name = a.Name
+	}
+
+	return base, nil
+}
+
+// AnchorOf returns the anchor registered by a location provider.
+// It reports whether the provider is an anchor.
+func AnchorOf(p LocationProvider) (*Anchor, bool) {
+	var props struct {
+		Anchor *Anchor `json:"anchor"`
+	}
+	if len(p.Properties) == 0 || json.Unmarshal(p.Properties, &props) != nil || props.Anchor == nil {
+		return nil, false
+	}
+
+	props.Anchor.ID = p.ID
+	if props.Anchor.Name == "" {
+		props.Anchor.Name = p.Name
+	}
+
+	return props.Anchor, true
+}
@@ -0,0 +1,114 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// AnchorsAPI is a wrapper around the location provider requests to commission UWB anchors.
+type AnchorsAPI struct {
+	client *Client
+}
+
+// List lists all registered anchors.
+func (c *AnchorsAPI) List(ctx context.Context) ([]Anchor, error) {
+	providers, err := c.client.Providers.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var anchors []Anchor
+	for _, p := range providers {
+		if a, ok := AnchorOf(p); ok {
+			anchors = append(anchors, *a)
+		}
+	}
+
+	return anchors, nil
+}
+
+// Get gets a registered anchor.
+func (c *AnchorsAPI) Get(ctx context.Context, id string) (*Anchor, error) {
+	p, err := c.client.Providers.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	a, ok := AnchorOf(*p)
+	if !ok {
+		return nil, fmt.Errorf("location provider %s is not an anchor", id)
+	}
+
+	return a, nil
+}
+
+// Register registers an anchor, creating its location provider or updating the existing one.
+// It reports whether the anchor was created.
+func (c *AnchorsAPI) Register(ctx context.Context, anchor Anchor) (bool, error) {
+	if err := anchor.Validate(); err != nil {
+		return false, err
+	}
+
+	existing, err := c.client.Providers.Get(ctx, anchor.ID)
+	if err != nil && !isNotFound(err) {
+		return false, err
+	}
+
+	if existing == nil {
+		p, err := anchor.Provider(LocationProvider{})
+		if err != nil {
+			return false, err
+		}
+
+		_, err = c.client.Providers.Create(ctx, p)
+		return err == nil, err
+	}
+
+	p, err := anchor.Provider(*existing)
+	if err != nil {
+		return false, err
+	}
+
+	return false, c.client.Providers.Update(ctx, p, anchor.ID)
+}
+
+// Tune updates the air interface parameters of a registered anchor.
+func (c *AnchorsAPI) Tune(ctx context.Context, id string, params AirInterface) error {
+	if err := params.Validate(); err != nil {
+		return fmt.Errorf("anchor %s: %w", id, err)
+	}
+
+	p, err := c.client.Providers.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	a, ok := AnchorOf(*p)
+	if !ok {
+		return fmt.Errorf("location provider %s is not an anchor", id)
+	}
+	a.AirInterface = params
+
+	updated, err := a.Provider(*p)
+	if err != nil {
+		return err
+	}
+
+	return c.client.Providers.Update(ctx, updated, id)
+}
+
+// Delete deletes a registered anchor.
+func (c *AnchorsAPI) Delete(ctx context.Context, id string) error {
+	return c.client.Providers.Delete(ctx, id)
+}
+
+// isNotFound reports whether the error is a not found response of the Hub.
+func isNotFound(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Code == http.StatusNotFound
+}
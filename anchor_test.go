@@ -0,0 +1,123 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+func TestAnchorProvider(t *testing.T) {
+	anchor := Anchor{
+		ID:       "00:00:00:00:00:00:a1:01",
+		Name:     "hall-a1",
+		Position: *NewPointZ(geometry.Point{X: 12.5, Y: 3}, 4.2),
+		ZoneID:   "hall",
+		AirInterface: AirInterface{
+			Channel:  5,
+			PRF:      64,
+			DataRate: 6800,
+		},
+	}
+
+	base := LocationProvider{
+		ID:         anchor.ID,
+		Type:       LocationProviderTypeUnknown,
+		Properties: json.RawMessage(`{"org.wavecom.whereis":{"eid":"A1"}}`),
+	}
+
+	p, err := anchor.Provider(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	JSONMarshalOK(t, p, []byte(`{"id":"00:00:00:00:00:00:a1:01","type":"uwb","name":"hall-a1","properties":{"org.wavecom.whereis":{"eid":"A1"},"anchor":{"id":"00:00:00:00:00:00:a1:01","name":"hall-a1","position":{"type":"Point","coordinates":[12.5,3,4.2]},"zone_id":"hall","air_interface":{"channel":5,"prf":64,"data_rate":6800}}}}`))
+
+	got, ok := AnchorOf(p)
+	if !ok {
+		t.Fatal("expected provider to be an anchor")
+	}
+	if got.ID != anchor.ID || got.ZoneID != anchor.ZoneID || got.AirInterface != anchor.AirInterface || !got.Position.Equal(anchor.Position) {
+		t.Errorf("unexpected anchor: %+v", got)
+	}
+
+	if _, ok := AnchorOf(base); ok {
+		t.Error("expected provider without anchor property not to be an anchor")
+	}
+}
+
+func TestAnchorValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		anchor Anchor
+		ok     bool
+	}{
+		{name: "valid", anchor: Anchor{ID: "a1", ZoneID: "hall", AirInterface: AirInterface{Channel: 9, PreambleLength: 128}}, ok: true},
+		{name: "wgs84 without zone", anchor: Anchor{ID: "a1", Crs: CrsWGS84}, ok: true},
+		{name: "missing id", anchor: Anchor{ZoneID: "hall"}},
+		{name: "local without zone", anchor: Anchor{ID: "a1"}},
+		{name: "invalid channel", anchor: Anchor{ID: "a1", ZoneID: "hall", AirInterface: AirInterface{Channel: 6}}},
+		{name: "invalid prf", anchor: Anchor{ID: "a1", ZoneID: "hall", AirInterface: AirInterface{PRF: 32}}},
+		{name: "invalid data rate", anchor: Anchor{ID: "a1", ZoneID: "hall", AirInterface: AirInterface{DataRate: 1000}}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.anchor.Validate(); (err == nil) != tc.ok {
+				t.Errorf("unexpected validation result: %v", err)
+			}
+		})
+	}
+}
+
+func TestAnchorsRegister(t *testing.T) {
+	var requests []string
+
+	httpClient := &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+
+			if r.Method == http.MethodGet {
+				return &http.Response{
+					StatusCode: http.StatusNotFound,
+					Body:       io.NopCloser(strings.NewReader(`{"type":"not found","code":404,"message":"provider does not exist"}`)),
+				}, nil
+			}
+
+			body, _ := io.ReadAll(r.Body)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(string(body))),
+			}, nil
+		}),
+	}
+
+	c, err := New("http://localhost:8081/v2", WithHTTPClient(httpClient))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := c.Anchors.Register(context.Background(), Anchor{
+		ID:       "a1",
+		Position: *NewPoint(geometry.Point{X: 1, Y: 2}),
+		ZoneID:   "hall",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created {
+		t.Error("expected anchor to be created")
+	}
+
+	want := []string{"GET /v2/providers/a1", "POST /v2/providers"}
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected requests:\n%s\nwanted:\n%s", strings.Join(requests, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package anomaly detects data-quality anomalies in omlox™ location streams,
+// such as teleports, GPS drift and frozen location providers.
+package anomaly
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+// Detector detects anomalies in location updates.
+// Implementations must be safe for concurrent use.
+type Detector interface {
+	// Observe evaluates a location update and returns the anomalies found in it.
+	Observe(loc omlox.Location) []Event
+
+	// Check returns the anomalies that depend on the passage of time,
+	// such as providers that stopped sending updates.
+	Check(now time.Time) []Event
+}
+
+// Event is an anomaly found in a location stream.
+type Event struct {
+	// Kind is the kind of anomaly.
+	Kind Kind `json:"kind"`
+
+	// ProviderID is the id of the location provider with the anomaly.
+	ProviderID string `json:"provider_id"`
+
+	// Trackables are the ids of the trackables the provider is assigned to.
+	Trackables []uuid.UUID `json:"trackables,omitempty"`
+
+	// Location is the location update which triggered the anomaly, if any.
+	Location *omlox.Location `json:"location,omitempty"`
+
+	// At is the time the anomaly was detected.
+	At time.Time `json:"at"`
+
+	// Detail is a human-readable description of the anomaly.
+	Detail string `json:"detail"`
+}
+
+// Kind is the kind of an anomaly.
+type Kind int
+
+// Defines values for Kind.
+const (
+	// KindTeleport is a jump between two locations at an impossible speed.
+	KindTeleport Kind = iota
+
+	// KindDrift is a stationary provider whose position wanders away.
+	KindDrift
+
+	// KindFrozen is a provider repeating the same position, or not sending updates at all.
+	KindFrozen
+)
+
+// FromString assigs itself from kind name.
+func (k *Kind) FromString(name string) error {
+	v, ok := map[string]Kind{
+		KindTeleport.String(): KindTeleport,
+		KindDrift.String():    KindDrift,
+		KindFrozen.String():   KindFrozen,
+	}[name]
+
+	if !ok {
+		return fmt.Errorf("anomaly of kind %s not supported", name)
+	}
+
+	*k = v
+	return nil
+}
+
+// String return a text representation.
+func (k Kind) String() string {
+	kinds := [...]string{
+		"teleport",
+		"drift",
+		"frozen",
+	}
+
+	if int(k) < 0 || len(kinds) <= int(k) {
+		return ""
+	}
+
+	return kinds[k]
+}
+
+// MarshalJSON encodes kind in to JSON.
+func (k Kind) MarshalJSON() ([]byte, error) {
+	return json.Marshal(k.String())
+}
+
+// UnmarshalJSON decodes kind from JSON.
+func (k *Kind) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	return k.FromString(s)
+}
+
+// WatchOption is a configuration option of Watch.
+type WatchOption func(*watchOptions)
+
+type watchOptions struct {
+	clock clock.Clock
+}
+
+// WithClock sets the source of time of the time-dependent checks.
+//
+// Default: clock.System
+func WithClock(clk clock.Clock) WatchOption {
+	return func(o *watchOptions) {
+		o.clock = clk
+	}
+}
+
+// Watch runs the detector over a stream of location updates, such as the
+// channel returned by omlox.ReceiveAs[omlox.Location], and emits the anomalies
+// found on the returned channel. Time-dependent anomalies are checked every interval.
+//
+// The returned channel is closed when the locations channel is closed or the context is done.
+func Watch(ctx context.Context, d Detector, locations <-chan *omlox.Location, interval time.Duration, opts ...WatchOption) <-chan Event {
+	var o watchOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.clock = clock.Or(o.clock)
+
+	out := make(chan Event, 64)
+
+	go func() {
+		defer close(out)
+
+		ticker := o.clock.NewTicker(interval)
+		defer ticker.Stop()
+
+		emit := func(events []Event) bool {
+			for _, e := range events {
+				select {
+				case out <- e:
+				case <-ctx.Done():
+					return false
+				}
+			}
+			return true
+		}
+
+		for {
+			var events []Event
+
+			select {
+			case <-ctx.Done():
+				return
+			case loc, ok := <-locations:
+				if !ok {
+					return
+				}
+				if loc != nil {
+					events = d.Observe(*loc)
+				}
+			case now := <-ticker.C():
+				events = d.Check(now)
+			}
+
+			if !emit(events) {
+				return
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,209 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package anomaly
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/analytics"
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+// Config configures the heuristic detector. A zero value disables the related check.
+type Config struct {
+	// MaxSpeed is the maximum plausible speed in meters per second.
+	// Jumps between consecutive locations above it are reported as teleports.
+	MaxSpeed float64
+
+	// DriftRadius is the distance in meters a provider reporting itself as
+	// stationary may wander before it is reported as drifting.
+	DriftRadius float64
+
+	// StationarySpeed is the reported speed in meters per second at or below
+	// which a provider is considered stationary.
+	StationarySpeed float64
+
+	// FrozenAfter is the time after which a provider repeating the exact same
+	// position, or not sending updates at all, is reported as frozen.
+	FrozenAfter time.Duration
+
+	// Clock is the source of time of the locations without timestamp.
+	// If nil, clock.System is used.
+	Clock clock.Clock
+}
+
+// DefaultConfig returns a default configuration for the heuristic detector,
+// suitable for people and industrial vehicles.
+func DefaultConfig() Config {
+	return Config{
+		MaxSpeed:        15,
+		DriftRadius:     5,
+		StationarySpeed: 0.1,
+		FrozenAfter:     5 * time.Minute,
+	}
+}
+
+// providerState is the state of a location provider as seen by the detector.
+type providerState struct {
+	last     omlox.Location
+	lastSeen time.Time
+
+	// anchor is the position where the provider became stationary
+	anchor   *geometry.Point
+	drifting bool
+
+	// unchanged is the time since the position has not changed
+	unchanged time.Time
+	stuck     bool
+	silent    bool
+}
+
+// Heuristic is the default Detector, flagging teleports, GPS drift and frozen
+// providers based on simple thresholds. Anomalies are reported once, and re-armed
+// when the provider behaves normally again.
+type Heuristic struct {
+	mu sync.Mutex
+
+	cfg       Config
+	providers map[string]*providerState
+}
+
+var _ Detector = (*Heuristic)(nil)
+
+// NewHeuristic returns a new heuristic detector with the given configuration.
+func NewHeuristic(cfg Config) (*Heuristic, error) {
+	if cfg.MaxSpeed < 0 || cfg.DriftRadius < 0 || cfg.StationarySpeed < 0 || cfg.FrozenAfter < 0 {
+		return nil, fmt.Errorf("anomaly detector thresholds must not be negative")
+	}
+
+	cfg.Clock = clock.Or(cfg.Clock)
+
+	return &Heuristic{
+		cfg:       cfg,
+		providers: make(map[string]*providerState),
+	}, nil
+}
+
+// Observe evaluates a location update and returns the anomalies found in it.
+// Locations without timestamp are evaluated at the current time.
+func (h *Heuristic) Observe(loc omlox.Location) []Event {
+	at := h.cfg.Clock.Now()
+	if loc.TimestampGenerated != nil {
+		at = *loc.TimestampGenerated
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	pos := loc.Position.Base()
+
+	st, ok := h.providers[loc.ProviderID]
+	if !ok {
+		st = &providerState{last: loc, lastSeen: at, unchanged: at}
+		h.stationary(st, loc, pos)
+		h.providers[loc.ProviderID] = st
+		return nil
+	}
+
+	var events []Event
+	event := func(kind Kind, format string, args ...any) {
+		l := loc
+		events = append(events, Event{
+			Kind:       kind,
+			ProviderID: loc.ProviderID,
+			Trackables: loc.Trackables,
+			Location:   &l,
+			At:         at,
+			Detail:     fmt.Sprintf(format, args...),
+		})
+	}
+
+	last := st.last.Position.Base()
+	same := st.last.Source == loc.Source && st.last.Crs == loc.Crs && st.last.Floor == loc.Floor
+
+	if same && h.cfg.MaxSpeed > 0 {
+		if dt := at.Sub(st.lastSeen).Seconds(); dt > 0 {
+			d := analytics.Distance(last, pos, loc.Crs)
+			if speed := d / dt; speed > h.cfg.MaxSpeed {
+				event(KindTeleport, "jumped %.1fm in %.1fs (%.1fm/s)", d, dt, speed)
+			}
+		}
+	}
+
+	if h.cfg.DriftRadius > 0 && h.stationary(st, loc, pos) && !st.drifting {
+		if d := analytics.Distance(*st.anchor, pos, loc.Crs); d > h.cfg.DriftRadius {
+			st.drifting = true
+			event(KindDrift, "drifted %.1fm while stationary", d)
+		}
+	}
+
+	if same && last == pos {
+		if h.cfg.FrozenAfter > 0 && !st.stuck && at.Sub(st.unchanged) >= h.cfg.FrozenAfter {
+			st.stuck = true
+			event(KindFrozen, "position unchanged for %v", at.Sub(st.unchanged))
+		}
+	} else {
+		st.unchanged = at
+		st.stuck = false
+	}
+
+	st.last = loc
+	st.lastSeen = at
+	st.silent = false
+
+	return events
+}
+
+// stationary updates the drift anchor of the provider and reports whether it is stationary.
+func (h *Heuristic) stationary(st *providerState, loc omlox.Location, pos geometry.Point) bool {
+	if loc.Speed == nil || *loc.Speed > h.cfg.StationarySpeed {
+		st.anchor = nil
+		st.drifting = false
+		return false
+	}
+
+	if st.anchor == nil {
+		st.anchor = &pos
+	}
+
+	return true
+}
+
+// Check returns the providers which have not sent updates for longer than the frozen threshold.
+func (h *Heuristic) Check(now time.Time) []Event {
+	if h.cfg.FrozenAfter <= 0 {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var events []Event
+	for id, st := range h.providers {
+		silence := now.Sub(st.lastSeen)
+		if st.silent || silence < h.cfg.FrozenAfter {
+			continue
+		}
+
+		st.silent = true
+		events = append(events, Event{
+			Kind:       KindFrozen,
+			ProviderID: id,
+			Trackables: st.last.Trackables,
+			At:         now,
+			Detail:     fmt.Sprintf("no updates for %v", silence.Round(time.Second)),
+		})
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ProviderID < events[j].ProviderID
+	})
+
+	return events
+}
@@ -0,0 +1,180 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package anomaly
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+var start = time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+
+func location(provider string, x, y float64, after time.Duration, speed *float64) omlox.Location {
+	ts := start.Add(after)
+	return omlox.Location{
+		Position:           *omlox.NewPoint(geometry.Point{X: x, Y: y}),
+		Source:             "zone-a",
+		ProviderID:         provider,
+		TimestampGenerated: &ts,
+		Speed:              speed,
+	}
+}
+
+func kinds(events []Event) []Kind {
+	var ks []Kind
+	for _, e := range events {
+		ks = append(ks, e.Kind)
+	}
+	return ks
+}
+
+func TestHeuristic(t *testing.T) {
+	stationary := 0.0
+
+	tests := []struct {
+		name      string
+		locations []omlox.Location
+		want      []Kind
+	}{
+		{
+			name: "normal",
+			locations: []omlox.Location{
+				location("a", 0, 0, 0, nil),
+				location("a", 1, 0, time.Second, nil),
+				location("a", 2, 0, 2*time.Second, nil),
+			},
+		},
+		{
+			name: "teleport",
+			locations: []omlox.Location{
+				location("a", 0, 0, 0, nil),
+				location("a", 100, 0, time.Second, nil),
+				// other providers do not interfere
+				location("b", 0, 0, time.Second, nil),
+			},
+			want: []Kind{KindTeleport},
+		},
+		{
+			name: "drift",
+			locations: []omlox.Location{
+				location("a", 0, 0, 0, &stationary),
+				location("a", 3, 0, time.Minute, &stationary),
+				location("a", 6, 0, 2*time.Minute, &stationary),
+				// reported once
+				location("a", 7, 0, 3*time.Minute, &stationary),
+			},
+			want: []Kind{KindDrift},
+		},
+		{
+			name: "stuck",
+			locations: []omlox.Location{
+				location("a", 1, 1, 0, nil),
+				location("a", 1, 1, 3*time.Minute, nil),
+				location("a", 1, 1, 6*time.Minute, nil),
+				// reported once
+				location("a", 1, 1, 9*time.Minute, nil),
+			},
+			want: []Kind{KindFrozen},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := NewHeuristic(DefaultConfig())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []Event
+			for _, loc := range tt.locations {
+				got = append(got, d.Observe(loc)...)
+			}
+
+			if ks := kinds(got); !slices.Equal(ks, tt.want) {
+				t.Errorf("expected anomalies %v, got %v", tt.want, ks)
+			}
+		})
+	}
+}
+
+func TestHeuristicCheck(t *testing.T) {
+	d, err := NewHeuristic(DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d.Observe(location("a", 0, 0, 0, nil))
+	d.Observe(location("b", 0, 0, 4*time.Minute, nil))
+
+	events := d.Check(start.Add(6 * time.Minute))
+	if len(events) != 1 || events[0].ProviderID != "a" || events[0].Kind != KindFrozen {
+		t.Fatalf("expected provider 'a' to be frozen, got %+v", events)
+	}
+
+	if events := d.Check(start.Add(7 * time.Minute)); len(events) != 0 {
+		t.Errorf("expected frozen provider to be reported once, got %+v", events)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	d, err := NewHeuristic(DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	locations := make(chan *omlox.Location, 2)
+	a, b := location("a", 0, 0, 0, nil), location("a", 100, 0, time.Second, nil)
+	locations <- &a
+	locations <- &b
+	close(locations)
+
+	var got []Event
+	for e := range Watch(context.Background(), d, locations, time.Hour) {
+		got = append(got, e)
+	}
+
+	if len(got) != 1 || got[0].Kind != KindTeleport {
+		t.Errorf("expected a teleport anomaly, got %+v", got)
+	}
+}
+
+func TestWatchCheck(t *testing.T) {
+	d, err := NewHeuristic(DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clk := clock.NewFake(start)
+
+	locations := make(chan *omlox.Location, 1)
+	a := location("a", 0, 0, 0, nil)
+	locations <- &a
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := Watch(ctx, d, locations, time.Minute, WithClock(clk))
+
+	// time is advanced until the check following the threshold reports the provider frozen
+	for {
+		select {
+		case e := <-events:
+			if e.Kind != KindFrozen || e.ProviderID != "a" {
+				t.Errorf("expected provider 'a' to be frozen, got %+v", e)
+			}
+			if e.At.Before(start.Add(5 * time.Minute)) {
+				t.Errorf("expected frozen provider after the threshold, got %v", e.At)
+			}
+			return
+		default:
+			clk.Advance(time.Minute)
+		}
+	}
+}
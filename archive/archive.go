@@ -0,0 +1,232 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package archive persists streams, such as the events of a subscription, to files
+// rotated by size and age. Rotated files are compressed, and the oldest ones are
+// deleted according to a retention policy, so continuous location capture does
+// not fill the disks of edge devices.
+package archive
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression is the compression of the rotated files.
+type Compression string
+
+// Defines values for Compression.
+const (
+	None Compression = "none"
+	Gzip Compression = "gzip"
+	Zstd Compression = "zstd"
+)
+
+// Compressions returns a list of the string representation of the compressions.
+func Compressions() []string {
+	return []string{None.String(), Gzip.String(), Zstd.String()}
+}
+
+// String returns the string representation of the Compression.
+func (c Compression) String() string {
+	return string(c)
+}
+
+// ParseCompression takes a raw string and returns the matching Compression.
+func ParseCompression(s string) (Compression, error) {
+	switch Compression(s) {
+	case "":
+		return None, nil
+	case None, Gzip, Zstd:
+		return Compression(s), nil
+	}
+	return "", fmt.Errorf("invalid compression %q: expected one of %v", s, Compressions())
+}
+
+// Ext returns the file extension of the compression.
+func (c Compression) Ext() string {
+	switch c {
+	case Gzip:
+		return ".gz"
+	case Zstd:
+		return ".zst"
+	}
+	return ""
+}
+
+// Compress compresses a file, replacing it by the compressed file named with the
+// extension of the compression, and returns the path of the compressed file.
+func (c Compression) Compress(path string) (string, error) {
+	if c.Ext() == "" {
+		return path, nil
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	dst := path + c.Ext()
+	tmp, err := os.Create(dst + ".tmp")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	w, err := c.writer(tmp)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(w, src); err != nil {
+		w.Close()
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	if err := tmp.Sync(); err != nil {
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return "", err
+	}
+
+	return dst, os.Remove(path)
+}
+
+// writer returns a writer compressing to w.
+func (c Compression) writer(w io.Writer) (io.WriteCloser, error) {
+	switch c {
+	case Gzip:
+		return gzip.NewWriter(w), nil
+	case Zstd:
+		return zstd.NewWriter(w)
+	}
+	return nopCloser{w}, nil
+}
+
+// nopCloser is a writer without compression.
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// Rewrite rewrites a file with fn, which reads the decompressed content and writes the
+// new one, compressed as the original. The file is only replaced once fn succeeds.
+func Rewrite(path string, fn func(r io.Reader, w io.Writer) error) error {
+	r, err := Open(path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	w, err := compressionOf(path).writer(tmp)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(r, w); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// compressionOf returns the compression of a file according to its extension.
+func compressionOf(path string) Compression {
+	for _, c := range []Compression{Gzip, Zstd} {
+		if strings.HasSuffix(path, c.Ext()) {
+			return c
+		}
+	}
+	return None
+}
+
+// Open opens a file, decompressing it according to its extension.
+func Open(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case strings.HasSuffix(path, Gzip.Ext()):
+		r, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return readCloser{r, func() { r.Close(); f.Close() }}, nil
+	case strings.HasSuffix(path, Zstd.Ext()):
+		r, err := zstd.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return readCloser{r, func() { r.Close(); f.Close() }}, nil
+	}
+
+	return f, nil
+}
+
+// readCloser closes a decompressing reader and its file.
+type readCloser struct {
+	io.Reader
+	close func()
+}
+
+func (r readCloser) Close() error {
+	r.close()
+	return nil
+}
+
+// TrimExt returns the name of a file without the extension of its compression.
+func TrimExt(name string) string {
+	for _, c := range []Compression{Gzip, Zstd} {
+		if n, ok := strings.CutSuffix(name, c.Ext()); ok {
+			return n
+		}
+	}
+	return name
+}
@@ -0,0 +1,58 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package s3 uploads the files of the archive package to a bucket of an S3-compatible
+// object storage, such as AWS S3 or MinIO.
+//
+// Objects are uploaded with the MinIO Go client. The package is a module of its own,
+// so the client of the hub does not depend on minio-go.
+package s3
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/wavecomtech/omlox-client-go/archive"
+)
+
+// Store is an archive.Store of a bucket of an S3-compatible object storage.
+type Store struct {
+	client *minio.Client
+	bucket string
+}
+
+var _ archive.Store = (*Store)(nil)
+
+// NewStore returns a store of the bucket, uploading objects with the client.
+func NewStore(client *minio.Client, bucket string) *Store {
+	return &Store{
+		client: client,
+		bucket: bucket,
+	}
+}
+
+// Put implements archive.Store. Objects are uploaded in a single request along with
+// their SHA-256 checksum, which the object storage verifies.
+func (s *Store) Put(ctx context.Context, key string, body io.ReadSeeker, size int64, checksum string) error {
+	sum, err := hex.DecodeString(checksum)
+	if err != nil {
+		return fmt.Errorf("put %s: invalid checksum: %w", key, err)
+	}
+
+	_, err = s.client.PutObject(ctx, s.bucket, key, body, size, minio.PutObjectOptions{
+		ContentType: "application/octet-stream",
+		UserMetadata: map[string]string{
+			"x-amz-checksum-sha256": base64.StdEncoding.EncodeToString(sum),
+		},
+		// the checksum is the one of the whole object, not of its parts
+		DisableMultipart: true,
+	})
+	if err != nil {
+		return fmt.Errorf("put %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JournalName is the name of the file listing the uploaded files of a directory.
+const JournalName = ".uploaded.jsonl"
+
+// Store is an object storage the archived files are uploaded to.
+type Store interface {
+	// Put uploads an object of size bytes with the hex encoded SHA-256 checksum.
+	Put(ctx context.Context, key string, body io.ReadSeeker, size int64, checksum string) error
+}
+
+// Object is an uploaded file.
+type Object struct {
+	// Key is the key of the object in the store.
+	Key string `json:"key"`
+
+	// Size is the size of the object in bytes.
+	Size int64 `json:"size"`
+
+	// SHA256 is the hex encoded SHA-256 checksum of the object.
+	SHA256 string `json:"sha256"`
+
+	// Modified is the modification time of the file.
+	Modified time.Time `json:"modified"`
+}
+
+// Manifest lists the objects of an upload. It is uploaded after them, so consumers
+// of the store, such as a data lake, only pick up complete uploads.
+type Manifest struct {
+	Created time.Time `json:"created"`
+	Objects []Object  `json:"objects"`
+}
+
+// Uploader uploads archived files to a store, with a manifest per upload.
+// The uploaded files are recorded in the journal of their directory, so they
+// are not uploaded again.
+type Uploader struct {
+	Store Store
+
+	// Prefix is prepended to the keys of the objects, such as site-1/locations.
+	Prefix string
+
+	// Delete deletes the local files once uploaded.
+	Delete bool
+
+	mu sync.Mutex
+}
+
+// Upload uploads the files and their manifest, under prefix/manifests.
+func (u *Uploader) Upload(ctx context.Context, paths ...string) (*Manifest, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if len(paths) == 0 {
+		return &Manifest{Created: time.Now().UTC()}, nil
+	}
+
+	m := &Manifest{}
+	for _, p := range paths {
+		o, err := u.put(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		m.Objects = append(m.Objects, o)
+	}
+
+	m.Created = time.Now().UTC()
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	key := path.Join(u.Prefix, "manifests", m.Created.Format("20060102T150405.000000000Z")+".json")
+	if err := u.Store.Put(ctx, key, bytes.NewReader(data), int64(len(data)), hex.EncodeToString(sum[:])); err != nil {
+		return nil, err
+	}
+
+	for i, p := range paths {
+		if err := journal(filepath.Dir(p), m.Objects[i]); err != nil {
+			return m, err
+		}
+		if u.Delete {
+			if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+				return m, err
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// put uploads a file with its checksum.
+func (u *Uploader) put(ctx context.Context, p string) (Object, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return Object{}, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return Object{}, err
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return Object{}, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return Object{}, err
+	}
+
+	o := Object{
+		Key:      path.Join(u.Prefix, filepath.Base(p)),
+		Size:     info.Size(),
+		SHA256:   hex.EncodeToString(h.Sum(nil)),
+		Modified: info.ModTime().UTC(),
+	}
+
+	return o, u.Store.Put(ctx, o.Key, f, o.Size, o.SHA256)
+}
+
+// Pending returns the files of a directory not uploaded yet, by name. Files of a
+// writer are only complete once rotated, so directories being written to without
+// compression should not be uploaded.
+func Pending(dir string) ([]string, error) {
+	uploaded := make(map[string]bool)
+
+	f, err := os.Open(filepath.Join(dir, JournalName))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		defer f.Close()
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var o Object
+			if json.Unmarshal(scanner.Bytes(), &o) == nil {
+				uploaded[path.Base(o.Key)] = true
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []string
+	for _, e := range entries {
+		name := e.Name()
+		if !e.Type().IsRegular() || strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".tmp") || uploaded[name] {
+			continue
+		}
+		pending = append(pending, filepath.Join(dir, name))
+	}
+	sort.Strings(pending)
+
+	return pending, nil
+}
+
+// journal records an uploaded file in the journal of its directory.
+func journal(dir string, o Object) error {
+	f, err := os.OpenFile(filepath.Join(dir, JournalName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(o)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,93 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package archive
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// memStore is a store in memory, verifying the checksums of the objects.
+type memStore map[string][]byte
+
+func (s memStore) Put(ctx context.Context, key string, body io.ReadSeeker, size int64, checksum string) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != checksum || int64(len(data)) != size {
+		return io.ErrUnexpectedEOF
+	}
+
+	s[key] = data
+	return nil
+}
+
+func TestUploader(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"events-00000001.jsonl.zst", "events-00000002.jsonl.zst"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0o644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	store := memStore{}
+	u := &Uploader{Store: store, Prefix: "site-1"}
+
+	pending, err := Pending(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pending) != 2 {
+		t.Fatalf("expected 2 files pending, got %v", pending)
+	}
+
+	m, err := u.Upload(context.Background(), pending[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(m.Objects) != 1 || m.Objects[0].Key != "site-1/events-00000001.jsonl.zst" {
+		t.Fatalf("unexpected manifest %+v", m)
+	}
+
+	var keys []string
+	for k := range store {
+		keys = append(keys, k)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("expected the file and the manifest uploaded, got %v", keys)
+	}
+	for _, k := range keys {
+		if !strings.HasPrefix(k, "site-1/manifests/") {
+			continue
+		}
+
+		var uploaded Manifest
+		if err := json.Unmarshal(store[k], &uploaded); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(uploaded.Objects, m.Objects) {
+			t.Errorf("expected manifest objects %+v, got %+v", m.Objects, uploaded.Objects)
+		}
+	}
+
+	// uploaded files are not pending anymore
+	pending, err = Pending(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{filepath.Join(dir, "events-00000002.jsonl.zst")}; !reflect.DeepEqual(pending, want) {
+		t.Errorf("expected %v pending, got %v", want, pending)
+	}
+}
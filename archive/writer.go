@@ -0,0 +1,315 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package archive
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+// Retention is the policy deleting the oldest rotated files. Zero values do not limit.
+type Retention struct {
+	// MaxAge is the age after which rotated files are deleted.
+	MaxAge time.Duration
+
+	// MaxSize is the total size of the rotated files, in bytes, above which the
+	// oldest ones are deleted.
+	MaxSize int64
+
+	// MaxFiles is the number of rotated files above which the oldest ones are deleted.
+	MaxFiles int
+}
+
+// Option is a configuration option of a writer.
+type Option func(*Writer)
+
+// WithCompression sets the compression of the rotated files.
+// Default: None
+func WithCompression(c Compression) Option {
+	return func(w *Writer) {
+		w.compression = c
+	}
+}
+
+// WithMaxSize sets the size, in bytes, after which the current file is rotated.
+// Zero does not rotate by size.
+// Default: 64 MiB
+func WithMaxSize(n int64) Option {
+	return func(w *Writer) {
+		w.maxSize = n
+	}
+}
+
+// WithMaxAge sets the age after which the current file is rotated, on the next write.
+// Zero does not rotate by age.
+func WithMaxAge(d time.Duration) Option {
+	return func(w *Writer) {
+		w.maxAge = d
+	}
+}
+
+// WithRetention sets the policy deleting the oldest rotated files.
+func WithRetention(r Retention) Option {
+	return func(w *Writer) {
+		w.retention = r
+	}
+}
+
+// OnRotate registers a hook called with the path of each rotated, and compressed,
+// file. It is called synchronously, so long work such as uploads should not block it.
+func OnRotate(hook func(path string)) Option {
+	return func(w *Writer) {
+		w.hooks = append(w.hooks, hook)
+	}
+}
+
+// WithClock sets the source of time of the rotations by age and of the retention.
+func WithClock(clk clock.Clock) Option {
+	return func(w *Writer) {
+		w.clock = clk
+	}
+}
+
+// DefaultMaxSize is the default size after which the current file is rotated.
+const DefaultMaxSize = 64 << 20
+
+// Writer writes a stream to the numbered files of a directory, such as events-00000001.jsonl
+// for the name events.jsonl, rotating them by size and age.
+// It is safe for concurrent use.
+type Writer struct {
+	dir  string
+	base string
+	ext  string
+
+	compression Compression
+	maxSize     int64
+	maxAge      time.Duration
+	retention   Retention
+	hooks       []func(string)
+	clock       clock.Clock
+
+	mu     sync.Mutex
+	f      *os.File
+	size   int64
+	opened time.Time
+	seq    int
+}
+
+// NewWriter returns a writer of the files of a directory named after name, creating
+// the directory if needed. Files left uncompressed by a previous run are rotated.
+func NewWriter(dir string, name string, opts ...Option) (*Writer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	ext := filepath.Ext(name)
+	w := &Writer{
+		dir:         dir,
+		base:        strings.TrimSuffix(name, ext),
+		ext:         ext,
+		compression: None,
+		maxSize:     DefaultMaxSize,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+	w.clock = clock.Or(w.clock)
+
+	files, err := w.files()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		w.seq = max(w.seq, f.seq)
+		if w.compression.Ext() != "" && filepath.Base(f.path) == w.name(f.seq) {
+			if err := w.seal(f.path); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return w, w.prune()
+}
+
+// Write writes p to the current file, rotating it first if it reached its maximum
+// size or age. p is never split across files, so it should hold whole records.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f != nil && w.full() {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	if w.f == nil {
+		w.seq++
+		f, err := os.OpenFile(filepath.Join(w.dir, w.name(w.seq)), os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o644)
+		if err != nil {
+			return 0, err
+		}
+		w.f, w.size, w.opened = f, 0, w.clock.Now()
+	}
+
+	n, err := w.f.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Rotate rotates the current file, if any.
+func (w *Writer) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.rotate()
+}
+
+// Close rotates the current file and closes the writer.
+func (w *Writer) Close() error {
+	return w.Rotate()
+}
+
+func (w *Writer) full() bool {
+	if w.maxSize > 0 && w.size >= w.maxSize {
+		return true
+	}
+	return w.maxAge > 0 && w.clock.Now().Sub(w.opened) >= w.maxAge
+}
+
+// rotate closes, compresses and hands the current file to the hooks, then applies
+// the retention policy.
+func (w *Writer) rotate() error {
+	if w.f == nil {
+		return nil
+	}
+
+	path := w.f.Name()
+	err := w.f.Close()
+	w.f = nil
+	if err != nil {
+		return err
+	}
+
+	if err := w.seal(path); err != nil {
+		return err
+	}
+
+	return w.prune()
+}
+
+// seal compresses a file, or deletes it if empty, and hands it to the hooks.
+func (w *Writer) seal(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.Size() == 0 {
+		return os.Remove(path)
+	}
+
+	compressed, err := w.compression.Compress(path)
+	if err != nil {
+		return fmt.Errorf("compress %s: %w", path, err)
+	}
+
+	for _, hook := range w.hooks {
+		hook(compressed)
+	}
+
+	return nil
+}
+
+// prune deletes the oldest rotated files according to the retention policy.
+func (w *Writer) prune() error {
+	r := w.retention
+	if r == (Retention{}) {
+		return nil
+	}
+
+	files, err := w.files()
+	if err != nil {
+		return err
+	}
+
+	var (
+		size int64
+		kept int
+		now  = w.clock.Now()
+	)
+	for i := len(files) - 1; i >= 0; i-- {
+		f := files[i]
+		if w.f != nil && f.path == w.f.Name() {
+			continue
+		}
+
+		size += f.size
+		kept++
+
+		expired := r.MaxAge > 0 && now.Sub(f.modTime) > r.MaxAge
+		if expired || (r.MaxSize > 0 && size > r.MaxSize) || (r.MaxFiles > 0 && kept > r.MaxFiles) {
+			if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// name returns the name of the uncompressed file of a sequence number.
+func (w *Writer) name(seq int) string {
+	return fmt.Sprintf("%s-%08d%s", w.base, seq, w.ext)
+}
+
+// file is a file of the writer.
+type file struct {
+	path    string
+	seq     int
+	size    int64
+	modTime time.Time
+}
+
+// files returns the files of the writer in the directory, by sequence number.
+func (w *Writer) files() ([]file, error) {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []file
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(TrimExt(e.Name()), w.ext)
+		if !ok || e.IsDir() {
+			continue
+		}
+		name, ok = strings.CutPrefix(name, w.base+"-")
+		if !ok {
+			continue
+		}
+		seq, err := strconv.Atoi(name)
+		if err != nil {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file{path: filepath.Join(w.dir, e.Name()), seq: seq, size: info.Size(), modTime: info.ModTime()})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].seq < files[j].seq })
+	return files, nil
+}
@@ -0,0 +1,194 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package archive
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+// names returns the names of the files of a directory.
+func names(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestWriter(t *testing.T) {
+	for _, c := range []Compression{None, Gzip, Zstd} {
+		t.Run(c.String(), func(t *testing.T) {
+			dir := t.TempDir()
+
+			var rotated []string
+			w, err := NewWriter(dir, "events.jsonl",
+				WithCompression(c),
+				WithMaxSize(10),
+				WithRetention(Retention{MaxFiles: 2}),
+				OnRotate(func(path string) { rotated = append(rotated, filepath.Base(path)) }),
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, line := range []string{"{\"n\":1}\n", "{\"n\":2}\n", "{\"n\":3}\n", "{\"n\":4}\n"} {
+				if _, err := w.Write([]byte(line)); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// the lines are smaller than the maximum size: two lines per file
+			want := []string{"events-00000001.jsonl" + c.Ext(), "events-00000002.jsonl" + c.Ext()}
+			if !reflect.DeepEqual(rotated, want) {
+				t.Errorf("expected %v rotated, got %v", want, rotated)
+			}
+			if got := names(t, dir); !reflect.DeepEqual(got, want) {
+				t.Errorf("expected files %v, got %v", want, got)
+			}
+
+			r, err := Open(filepath.Join(dir, want[1]))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer r.Close()
+
+			data, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(data) != "{\"n\":3}\n{\"n\":4}\n" {
+				t.Errorf("unexpected content %q", data)
+			}
+		})
+	}
+}
+
+func TestWriterRetention(t *testing.T) {
+	dir := t.TempDir()
+	clk := clock.NewFake(time.Now())
+
+	w, err := NewWriter(dir, "events.jsonl",
+		WithMaxSize(0),
+		WithMaxAge(time.Hour),
+		WithRetention(Retention{MaxAge: 2 * time.Hour, MaxSize: 20}),
+		WithClock(clk),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// a file per hour
+	for i := 0; i < 4; i++ {
+		if _, err := w.Write([]byte(strings.Repeat("x", 8))); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		os.Chtimes(filepath.Join(dir, w.name(w.seq)), clk.Now(), clk.Now())
+		clk.Advance(time.Hour)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the first file is older than the maximum age, the second above the maximum size
+	want := []string{"events-00000003.jsonl", "events-00000004.jsonl"}
+	if got := names(t, dir); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected files %v, got %v", want, got)
+	}
+}
+
+func TestWriterRecover(t *testing.T) {
+	dir := t.TempDir()
+
+	// a file left uncompressed by a crash
+	if err := os.WriteFile(filepath.Join(dir, "events-00000007.jsonl"), []byte("{}\n"), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	w, err := NewWriter(dir, "events.jsonl", WithCompression(Zstd))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := w.Write([]byte("{}\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"events-00000007.jsonl.zst", "events-00000008.jsonl"}
+	if got := names(t, dir); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected files %v, got %v", want, got)
+	}
+	w.Close()
+}
+
+func TestParseCompression(t *testing.T) {
+	if c, err := ParseCompression(""); err != nil || c != None {
+		t.Errorf("expected none, got %v, %v", c, err)
+	}
+	if _, err := ParseCompression("lz4"); err == nil {
+		t.Error("expected error for unknown compression")
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	for _, c := range []Compression{None, Gzip, Zstd} {
+		t.Run(c.String(), func(t *testing.T) {
+			dir := t.TempDir()
+
+			path := filepath.Join(dir, "events.jsonl")
+			if err := os.WriteFile(path, []byte("a\nb\n"), 0o600); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			path, err := c.Compress(path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err = Rewrite(path, func(r io.Reader, w io.Writer) error {
+				data, err := io.ReadAll(r)
+				if err != nil {
+					return err
+				}
+				_, err = w.Write([]byte(strings.ToUpper(string(data))))
+				return err
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			r, err := Open(path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer r.Close()
+
+			data, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(data) != "A\nB\n" {
+				t.Errorf("unexpected content %q", data)
+			}
+			if got := names(t, dir); len(got) != 1 {
+				t.Errorf("expected only the rewritten file, got %v", got)
+			}
+		})
+	}
+}
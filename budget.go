@@ -0,0 +1,88 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+// Budget splits the deadline of a context across the requests of a composite operation,
+// such as the attempts of a retried request or the requests of GetMany and exports, so the
+// operation respects the total timeout of the caller instead of giving each request the
+// whole of it: a single slow request fails with time left for the others, and their retries.
+//
+// Each request gets an even share of the time left, given the requests left and how many
+// run concurrently, so requests returning early leave their time to the next ones. The last
+// requests get the whole time left. Contexts without deadline are not split. Budgets with a
+// request timeout give each request the whole timeout instead, within the time left, so a
+// slow request that fits the timeout is not cut short for the requests after it. Budget is
+// safe for concurrent use.
+type Budget struct {
+	ctx         context.Context
+	concurrency int
+
+	mu      sync.Mutex
+	left    int
+	timeout time.Duration
+	clock   clock.Clock
+}
+
+// NewBudget returns a budget splitting the deadline of a context across the given number of
+// requests, run with the given concurrency.
+func NewBudget(ctx context.Context, requests int, concurrency int) *Budget {
+	return &Budget{
+		ctx:         ctx,
+		concurrency: max(concurrency, 1),
+		left:        requests,
+		clock:       clock.System,
+	}
+}
+
+// SetClock sets the source of time measuring the time left before the deadline.
+func (b *Budget) SetClock(clk clock.Clock) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.clock = clock.Or(clk)
+}
+
+// SetRequestTimeout sets the timeout of each request, within the deadline of the budget,
+// given instead of an even share of the time left. A zero timeout splits the time left.
+func (b *Budget) SetRequestTimeout(timeout time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.timeout = max(timeout, 0)
+}
+
+// Next returns the context of the next request, derived from the given context, such as the
+// context of an errgroup of the requests, with the deadline of its share of the time left.
+// The cancel function must be called once the request is done.
+func (b *Budget) Next(ctx context.Context) (context.Context, context.CancelFunc) {
+	b.mu.Lock()
+	rounds := (b.left + b.concurrency - 1) / b.concurrency
+	if b.left > 0 {
+		b.left--
+	}
+	now, timeout := b.clock.Now(), b.timeout
+	b.mu.Unlock()
+
+	deadline, ok := b.ctx.Deadline()
+	switch {
+	case timeout > 0:
+		if !ok || now.Add(timeout).Before(deadline) {
+			deadline = now.Add(timeout)
+		}
+		return context.WithDeadline(ctx, deadline)
+	case !ok || rounds <= 1:
+		return context.WithCancel(ctx)
+	}
+
+	share := deadline.Sub(now) / time.Duration(rounds)
+	return context.WithDeadline(ctx, now.Add(share))
+}
@@ -0,0 +1,107 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+func TestBudget(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	tests := []struct {
+		name        string
+		requests    int
+		concurrency int
+		want        []time.Duration
+	}{
+		{name: "sequential", requests: 3, concurrency: 1, want: []time.Duration{20 * time.Second, 30 * time.Second, time.Minute, time.Minute}},
+		{name: "concurrent", requests: 4, concurrency: 2, want: []time.Duration{30 * time.Second, 30 * time.Second, time.Minute, time.Minute}},
+		{name: "single", requests: 1, concurrency: 0, want: []time.Duration{time.Minute}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBudget(ctx, tt.requests, tt.concurrency)
+			for i, want := range tt.want {
+				rctx, cancel := b.Next(ctx)
+				deadline, _ := rctx.Deadline()
+				cancel()
+
+				// requests returning immediately leave all their time to the next ones
+				if got := time.Until(deadline); got > want || got < want-time.Second {
+					t.Errorf("request %d: expected a deadline in %v, got %v", i, want, got)
+				}
+			}
+		})
+	}
+
+	// contexts without deadline are not split
+	rctx, cancel := NewBudget(context.Background(), 10, 1).Next(context.Background())
+	defer cancel()
+	if _, ok := rctx.Deadline(); ok {
+		t.Errorf("expected no deadline")
+	}
+}
+
+func TestBudgetClock(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC))
+	ctx, cancel := context.WithDeadline(context.Background(), clk.Now().Add(time.Minute))
+	defer cancel()
+
+	b := NewBudget(ctx, 3, 1)
+	b.SetClock(clk)
+
+	clk.Advance(30 * time.Second)
+	rctx, cancel := b.Next(ctx)
+	defer cancel()
+
+	// the time left is measured by the clock of the budget
+	deadline, _ := rctx.Deadline()
+	if want := clk.Now().Add(10 * time.Second); !deadline.Equal(want) {
+		t.Errorf("expected a deadline at %v, got %v", want, deadline)
+	}
+}
+
+func TestBudgetRequestTimeout(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name     string
+		deadline time.Duration
+		want     time.Duration
+	}{
+		// the default timeout of 60s is not split across the 3 attempts
+		{name: "without deadline", want: time.Minute},
+		{name: "later deadline", deadline: 2 * time.Minute, want: time.Minute},
+		{name: "earlier deadline", deadline: 25 * time.Second, want: 25 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.deadline > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithDeadline(ctx, clk.Now().Add(tt.deadline))
+				defer cancel()
+			}
+
+			b := NewBudget(ctx, 3, 1)
+			b.SetClock(clk)
+			b.SetRequestTimeout(time.Minute)
+
+			rctx, cancel := b.Next(ctx)
+			defer cancel()
+			deadline, _ := rctx.Deadline()
+			if want := clk.Now().Add(tt.want); !deadline.Equal(want) {
+				t.Errorf("expected a deadline at %v, got %v", want, deadline)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+)
+
+// PanicError is the error reported for a panic of a user callback, such as an event
+// handler, a payload migration or the connection state handler. The panic is recovered
+// so that it does not take down the websocket reader and the other subscribers.
+type PanicError struct {
+	// Callback is the kind of callback which panicked.
+	Callback string
+
+	// Topic is the topic of the subscription of the callback, if any.
+	Topic Topic
+
+	// Value is the value the callback panicked with.
+	Value any
+
+	// Stack is the stack trace of the goroutine at the panic.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	if e.Topic != "" {
+		return fmt.Sprintf("%s of %s panicked: %v", e.Callback, e.Topic, e.Value)
+	}
+	return fmt.Sprintf("%s panicked: %v", e.Callback, e.Value)
+}
+
+// Unwrap returns the value the callback panicked with, if an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// protect runs a user callback, recovering its panic as a *PanicError.
+func protect(callback string, topic Topic, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Callback: callback, Topic: topic, Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	return fn()
+}
+
+// reportError logs the error of a user callback, and reports it to the error handler
+// of the configuration, if any.
+func (c *Client) reportError(ctx context.Context, err error) {
+	attrs := []slog.Attr{slog.Any("err", err)}
+	var p *PanicError
+	if errors.As(err, &p) {
+		attrs = append(attrs, slog.String("stack", string(p.Stack)))
+	}
+	slog.LogAttrs(ctx, slog.LevelError, "callback failed", attrs...)
+
+	h := c.configuration.ErrorHandler
+	if h == nil {
+		return
+	}
+	// the error handler is not reported to itself
+	if err := protect("error handler", "", func() error { h(err); return nil }); err != nil {
+		slog.LogAttrs(ctx, slog.LevelError, "callback failed", slog.Any("err", err))
+	}
+}
+
+// protectMigrations returns the payload migrations of a topic, with their panics
+// recovered as errors, which drop the payloads, and reported.
+func (c *Client) protectMigrations(ctx context.Context, topic Topic, migrations []PayloadMigration) []PayloadMigration {
+	protected := make([]PayloadMigration, 0, len(migrations))
+	for _, migrate := range migrations {
+		migrate := migrate
+		protected = append(protected, func(version string, payload json.RawMessage) (out json.RawMessage, err error) {
+			err = protect("payload migration", topic, func() error {
+				out, err = migrate(version, payload)
+				return err
+			})
+
+			var p *PanicError
+			if errors.As(err, &p) {
+				c.reportError(ctx, err)
+			}
+			return out, err
+		})
+	}
+	return protected
+}
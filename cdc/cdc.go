@@ -0,0 +1,285 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package cdc captures the changes of the configuration of an omlox™ hub, such as
+// trackables and fences, as a stream of upserts and deletes, to keep external
+// databases continuously in sync with the hub.
+//
+// The hub does not publish configuration changes, so the resources are polled and
+// compared with the previous poll. Each change has a sequence number, so sinks can
+// record the last change applied.
+package cdc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/forward"
+)
+
+// TopicChanges is the topic of the changes delivered to forward sinks by Forward.
+const TopicChanges omlox.Topic = "resource_changes"
+
+// Resource is a kind of resource of a hub.
+type Resource string
+
+// Resources of a hub.
+const (
+	ResourceTrackables Resource = "trackables"
+	ResourceFences     Resource = "fences"
+	ResourceProviders  Resource = "providers"
+)
+
+// Op is the operation of a change.
+type Op string
+
+// Operations of changes.
+const (
+	OpUpsert Op = "upsert"
+	OpDelete Op = "delete"
+)
+
+// Change is a change of a resource.
+type Change struct {
+	// Seq is the sequence number of the change, increasing by one for each change.
+	Seq uint64 `json:"seq"`
+
+	// Resource is the kind of the resource changed.
+	Resource Resource `json:"resource"`
+
+	// Op is the operation of the change.
+	Op Op `json:"op"`
+
+	// ID is the id of the resource.
+	ID string `json:"id"`
+
+	// Payload is the resource, for upserts.
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// Decode decodes the payload of an upsert into a resource, such as an omlox.Trackable.
+func (c Change) Decode(v any) error {
+	if c.Op != OpUpsert {
+		return fmt.Errorf("%s of %s %s has no payload", c.Op, c.Resource, c.ID)
+	}
+	return json.Unmarshal(c.Payload, v)
+}
+
+// Sink applies changes.
+type Sink interface {
+	// Apply applies a change. Changes failing to be applied are applied again on the
+	// next poll.
+	Apply(ctx context.Context, c Change) error
+}
+
+// SinkFunc is an adapter to use ordinary functions as sinks.
+type SinkFunc func(ctx context.Context, c Change) error
+
+// Apply implements Sink.
+func (f SinkFunc) Apply(ctx context.Context, c Change) error {
+	return f(ctx, c)
+}
+
+// Forward returns a sink delivering the changes to a sink of the forward package, such
+// as a message broker, as events of the TopicChanges topic keyed by resource and id.
+func Forward(sink forward.Sink) Sink {
+	return SinkFunc(func(ctx context.Context, c Change) error {
+		payload, err := json.Marshal(c)
+		if err != nil {
+			return err
+		}
+		return sink.Deliver(ctx, forward.Event{Seq: c.Seq, Topic: TopicChanges, Key: string(c.Resource) + ":" + c.ID, Payload: payload})
+	})
+}
+
+// Option is a configuration option of a capture.
+type Option func(*Capture)
+
+// WithResources sets the resources captured.
+//
+// Default: trackables, fences and providers
+func WithResources(resources ...Resource) Option {
+	return func(c *Capture) {
+		c.resources = resources
+	}
+}
+
+// WithSeq sets the sequence number of the last change, such as the last change applied
+// by the sink before a restart.
+//
+// Default: 0
+func WithSeq(seq uint64) Option {
+	return func(c *Capture) {
+		c.seq = seq
+	}
+}
+
+// Capture captures the changes of the resources of a hub into a sink.
+//
+// The first poll captures all resources as upserts, so sinks are first brought in sync
+// with the hub. Resources deleted while no capture was running are not captured as
+// deletes. Capture is safe for concurrent use, polls are run one at a time.
+type Capture struct {
+	client    *omlox.Client
+	sink      Sink
+	resources []Resource
+
+	mu  sync.Mutex
+	seq uint64
+
+	// snapshot of the resources applied, by resource and id
+	snapshot map[Resource]map[string]json.RawMessage
+}
+
+// New returns a capture of the changes of the resources of a client into a sink.
+func New(client *omlox.Client, sink Sink, opts ...Option) *Capture {
+	c := &Capture{
+		client:    client,
+		sink:      sink,
+		resources: []Resource{ResourceTrackables, ResourceFences, ResourceProviders},
+		snapshot:  make(map[Resource]map[string]json.RawMessage),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Seq returns the sequence number of the last change applied.
+func (c *Capture) Seq() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.seq
+}
+
+// list lists the resources of a kind, by id.
+func (c *Capture) list(ctx context.Context, r Resource) (map[string]json.RawMessage, error) {
+	items := make(map[string]json.RawMessage)
+	add := func(id string, v any) error {
+		payload, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", r, id, err)
+		}
+		items[id] = payload
+		return nil
+	}
+
+	switch r {
+	case ResourceTrackables:
+		trackables, err := c.client.Trackables.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range trackables {
+			if err := add(t.ID.String(), t); err != nil {
+				return nil, err
+			}
+		}
+	case ResourceFences:
+		fences, err := c.client.Fences.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range fences {
+			if err := add(f.ID.String(), f); err != nil {
+				return nil, err
+			}
+		}
+	case ResourceProviders:
+		providers, err := c.client.Providers.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range providers {
+			if err := add(p.ID, p); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported resource '%s'", r)
+	}
+	return items, nil
+}
+
+// Poll lists the resources and applies their changes since the previous poll, in order
+// of resource and id. It returns the first error listing the resources or applying a
+// change; the changes not applied are applied again on the next poll.
+func (c *Capture) Poll(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, r := range c.resources {
+		items, err := c.list(ctx, r)
+		if err != nil {
+			return fmt.Errorf("list %s: %w", r, err)
+		}
+
+		applied := c.snapshot[r]
+		if applied == nil {
+			applied = make(map[string]json.RawMessage)
+			c.snapshot[r] = applied
+		}
+
+		ids := make([]string, 0, len(items)+len(applied))
+		for id := range items {
+			ids = append(ids, id)
+		}
+		for id := range applied {
+			if _, ok := items[id]; !ok {
+				ids = append(ids, id)
+			}
+		}
+		slices.Sort(ids)
+
+		for _, id := range ids {
+			change := Change{Seq: c.seq + 1, Resource: r, ID: id}
+			payload, ok := items[id]
+			switch {
+			case !ok:
+				change.Op = OpDelete
+			case string(payload) != string(applied[id]):
+				change.Op, change.Payload = OpUpsert, payload
+			default:
+				continue
+			}
+
+			if err := c.sink.Apply(ctx, change); err != nil {
+				return fmt.Errorf("%s of %s %s: %w", change.Op, r, id, err)
+			}
+			c.seq++
+			if ok {
+				applied[id] = payload
+			} else {
+				delete(applied, id)
+			}
+		}
+	}
+	return nil
+}
+
+// Run polls at each interval until the context is done. Poll errors are returned.
+func (c *Capture) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Poll(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
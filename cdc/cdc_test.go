@@ -0,0 +1,125 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package cdc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/forward"
+	"github.com/wavecomtech/omlox-client-go/omloxtest"
+)
+
+var (
+	forklift = uuid.MustParse("6c5d2f0e-8f6a-4b7e-9a51-0d6f1a2b3c41")
+	pallet   = uuid.MustParse("9b8a7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d")
+)
+
+// change is the comparable part of a change.
+type change struct {
+	Seq uint64
+	Op  Op
+	ID  string
+}
+
+func TestCapture(t *testing.T) {
+	hub := omloxtest.NewServer()
+	defer hub.Close()
+
+	c, err := omlox.New(hub.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	for _, tr := range []omlox.Trackable{{ID: forklift, Type: omlox.TrackableTypeVirtual, Name: "forklift"}, {ID: pallet, Type: omlox.TrackableTypeVirtual, Name: "pallet"}} {
+		if err := hub.Put("trackables", tr.ID.String(), tr); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var changes []change
+	var fail error
+	sink := SinkFunc(func(_ context.Context, ch Change) error {
+		if fail != nil {
+			return fail
+		}
+		if ch.Resource != ResourceTrackables {
+			t.Errorf("unexpected resource %s", ch.Resource)
+		}
+		if ch.Op == OpUpsert {
+			var tr omlox.Trackable
+			if err := ch.Decode(&tr); err != nil || tr.ID.String() != ch.ID {
+				t.Errorf("unexpected payload of %s: %v", ch.ID, err)
+			}
+		}
+		changes = append(changes, change{ch.Seq, ch.Op, ch.ID})
+		return nil
+	})
+	capture := New(c, sink, WithResources(ResourceTrackables), WithSeq(10))
+
+	// resources are first upserted
+	if err := capture.Poll(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []change{{11, OpUpsert, forklift.String()}, {12, OpUpsert, pallet.String()}}
+	if diff := cmp.Diff(want, changes); diff != "" {
+		t.Errorf("unexpected changes (-want +got):\n%s", diff)
+	}
+
+	// changes failing to be applied are applied again
+	if err := hub.Put("trackables", forklift.String(), omlox.Trackable{ID: forklift, Type: omlox.TrackableTypeVirtual, Name: "forklift 2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Trackables.Delete(ctx, pallet); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fail = errors.New("database unavailable")
+	if err := capture.Poll(ctx); !errors.Is(err, fail) {
+		t.Fatalf("expected the sink error, got %v", err)
+	}
+
+	fail, changes = nil, nil
+	for i := 0; i < 2; i++ {
+		if err := capture.Poll(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	want = []change{{13, OpUpsert, forklift.String()}, {14, OpDelete, pallet.String()}}
+	if diff := cmp.Diff(want, changes); diff != "" {
+		t.Errorf("unexpected changes (-want +got):\n%s", diff)
+	}
+	if seq := capture.Seq(); seq != 14 {
+		t.Errorf("expected seq 14, got %d", seq)
+	}
+}
+
+func TestForward(t *testing.T) {
+	var events []forward.Event
+	sink := Forward(forward.SinkFunc(func(_ context.Context, e forward.Event) error {
+		events = append(events, e)
+		return nil
+	}))
+
+	ch := Change{Seq: 3, Resource: ResourceFences, Op: OpDelete, ID: "dock"}
+	if err := sink.Apply(context.Background(), ch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0].Seq != 3 || events[0].Topic != TopicChanges || events[0].Key != "fences:dock" {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+
+	var got Change
+	if err := json.Unmarshal(events[0].Payload, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(ch, got); diff != "" {
+		t.Errorf("unexpected change (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,476 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package clickhouse ingests high volumes of locations into ClickHouse, for movement
+// analytics over millions of rows per hour.
+//
+// Locations are written in columnar batches through the HTTP interface of ClickHouse,
+// gzip compressed in the JSONColumns format, with asynchronous inserts so the server
+// merges the batches of several writers into fewer parts.
+package clickhouse
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/forward"
+)
+
+// Defaults of the writer configuration.
+const (
+	DefaultDatabase      = "omlox"
+	DefaultTable         = "locations"
+	DefaultBatchSize     = 10000
+	DefaultFlushInterval = time.Second
+	DefaultTimeout       = 30 * time.Second
+)
+
+// ErrClosed is returned by the writes to a closed writer.
+var ErrClosed = errors.New("clickhouse: writer closed")
+
+// timeFormat is the format of DateTime64(3) values.
+const timeFormat = "2006-01-02 15:04:05.000"
+
+// Error is an error returned by the ClickHouse server.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	return fmt.Sprintf("clickhouse: %d: %s", e.StatusCode, e.Message)
+}
+
+// Option is a configuration option of a writer.
+type Option func(*Writer)
+
+// WithDatabase sets the database of the table.
+//
+// Default: DefaultDatabase
+func WithDatabase(database string) Option {
+	return func(w *Writer) {
+		w.database = database
+	}
+}
+
+// WithTable sets the table of the locations.
+//
+// Default: DefaultTable
+func WithTable(table string) Option {
+	return func(w *Writer) {
+		w.table = table
+	}
+}
+
+// WithCredentials sets the user and password of the requests.
+func WithCredentials(user, password string) Option {
+	return func(w *Writer) {
+		w.user = user
+		w.password = password
+	}
+}
+
+// WithHTTPClient sets the HTTP client of the requests.
+//
+// Default: http.DefaultClient
+func WithHTTPClient(client *http.Client) Option {
+	return func(w *Writer) {
+		w.httpClient = client
+	}
+}
+
+// WithBatchSize sets the number of rows inserted at once.
+//
+// Default: DefaultBatchSize
+func WithBatchSize(n int) Option {
+	return func(w *Writer) {
+		w.batchSize = n
+	}
+}
+
+// WithFlushInterval sets the maximum time rows wait for their batch to be inserted.
+//
+// Default: DefaultFlushInterval
+func WithFlushInterval(d time.Duration) Option {
+	return func(w *Writer) {
+		w.flushInterval = d
+	}
+}
+
+// WithAsyncInsert sets whether batches are inserted with asynchronous inserts. Writes
+// still wait for the rows to be flushed by the server.
+//
+// Default: true
+func WithAsyncInsert(enabled bool) Option {
+	return func(w *Writer) {
+		w.asyncInsert = enabled
+	}
+}
+
+// WithTimeout sets the timeout of the inserts of batches.
+//
+// Default: DefaultTimeout
+func WithTimeout(d time.Duration) Option {
+	return func(w *Writer) {
+		w.timeout = d
+	}
+}
+
+// columns are the columns of a batch of rows, in the JSONColumns format.
+type columns struct {
+	Key          []string   `json:"key"`
+	TrackableID  []*string  `json:"trackable_id"`
+	ProviderID   []string   `json:"provider_id"`
+	ProviderType []string   `json:"provider_type"`
+	Crs          []string   `json:"crs"`
+	X            []float64  `json:"x"`
+	Y            []float64  `json:"y"`
+	Z            []float64  `json:"z"`
+	Floor        []float64  `json:"floor"`
+	Accuracy     []*float64 `json:"accuracy"`
+	At           []string   `json:"at"`
+}
+
+// batch is a batch of rows inserted at once.
+type batch struct {
+	columns
+	n     int
+	timer *time.Timer
+	once  sync.Once
+	done  chan struct{}
+	err   error
+}
+
+// Writer writes locations into a ClickHouse table in batches. It is safe for
+// concurrent use.
+//
+// Each location is a row per trackable, keyed by trackable id, or by provider id for
+// locations without trackables. The table is created by CreateTable.
+//
+// Writes wait for the batch of their rows to be inserted, so concurrent writes are
+// grouped into batches of up to the batch size, inserted at the latest after the flush
+// interval. Writer is a sink of the forward package, writing the location updates
+// forwarded and ignoring other events; raise the concurrency of the forwarder to fill
+// the batches.
+type Writer struct {
+	endpoint      *url.URL
+	database      string
+	table         string
+	user          string
+	password      string
+	httpClient    *http.Client
+	batchSize     int
+	flushInterval time.Duration
+	asyncInsert   bool
+	timeout       time.Duration
+
+	mu      sync.Mutex
+	current *batch
+	closed  bool
+
+	// batches not inserted yet
+	pending sync.WaitGroup
+}
+
+var _ forward.Sink = (*Writer)(nil)
+
+// New returns a writer to the HTTP interface of a ClickHouse server, such as
+// http://clickhouse.local:8123.
+func New(endpoint string, opts ...Option) (*Writer, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported scheme '%s'", u.Scheme)
+	}
+
+	w := &Writer{
+		endpoint:      u,
+		database:      DefaultDatabase,
+		table:         DefaultTable,
+		httpClient:    http.DefaultClient,
+		batchSize:     DefaultBatchSize,
+		flushInterval: DefaultFlushInterval,
+		asyncInsert:   true,
+		timeout:       DefaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w, nil
+}
+
+// quoteIdentifier quotes an identifier, such as a table name.
+func quoteIdentifier(name string) string {
+	return "`" + strings.NewReplacer(`\`, `\\`, "`", "\\`").Replace(name) + "`"
+}
+
+// tableName returns the quoted name of the table.
+func (w *Writer) tableName() string {
+	return quoteIdentifier(w.database) + "." + quoteIdentifier(w.table)
+}
+
+// CreateTable creates the database and the table of the locations, if they do not
+// exist. Rows are partitioned by day and sorted by key and time.
+func (w *Writer) CreateTable(ctx context.Context) error {
+	if err := w.exec(ctx, `CREATE DATABASE IF NOT EXISTS `+quoteIdentifier(w.database), nil, nil); err != nil {
+		return err
+	}
+	return w.exec(ctx, `CREATE TABLE IF NOT EXISTS `+w.tableName()+` (
+		key String,
+		trackable_id Nullable(UUID),
+		provider_id String,
+		provider_type LowCardinality(String),
+		crs LowCardinality(String),
+		x Float64,
+		y Float64,
+		z Float64,
+		floor Float64,
+		accuracy Nullable(Float64),
+		at DateTime64(3, 'UTC') CODEC(Delta, ZSTD)
+	)
+	ENGINE = MergeTree
+	PARTITION BY toYYYYMMDD(at)
+	ORDER BY (key, at)`, nil, nil)
+}
+
+// Deliver implements forward.Sink.
+func (w *Writer) Deliver(ctx context.Context, e forward.Event) error {
+	if e.Topic != omlox.TopicLocationUpdates {
+		return nil
+	}
+
+	var loc omlox.Location
+	if err := json.Unmarshal(e.Payload, &loc); err != nil {
+		return fmt.Errorf("invalid location update: %w", err)
+	}
+	return w.Write(ctx, loc)
+}
+
+// Write writes the rows of locations, once per trackable, and waits for their batch to
+// be inserted. If the context is done first, the rows may still be inserted; rows of
+// contexts done before the write are not written.
+func (w *Writer) Write(ctx context.Context, locations ...omlox.Location) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var batches []*batch
+
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return ErrClosed
+	}
+	for _, loc := range locations {
+		crs := loc.Crs
+		if crs == "" {
+			crs = omlox.CrsLocal
+		}
+		at := time.Now()
+		if loc.TimestampGenerated != nil {
+			at = *loc.TimestampGenerated
+		}
+		base := loc.Position.Base()
+
+		for _, id := range loc.Trackables {
+			trackable := id.String()
+			batches = w.addRow(batches, trackable, &trackable, &loc, crs, base.X, base.Y, at)
+		}
+		if len(loc.Trackables) == 0 {
+			batches = w.addRow(batches, loc.ProviderID, nil, &loc, crs, base.X, base.Y, at)
+		}
+	}
+	w.mu.Unlock()
+
+	for _, b := range batches {
+		select {
+		case <-b.done:
+			if b.err != nil {
+				return b.err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// addRow appends a row to the current batch, flushing it when it is full, and returns
+// the batches of the rows written. It is called with the lock held.
+func (w *Writer) addRow(batches []*batch, key string, trackable *string, loc *omlox.Location, crs string, x, y float64, at time.Time) []*batch {
+	b := w.current
+	if b == nil {
+		b = &batch{done: make(chan struct{})}
+		w.pending.Add(1)
+		b.timer = time.AfterFunc(w.flushInterval, func() { w.flush(b) })
+		w.current = b
+	}
+
+	b.Key = append(b.Key, key)
+	b.TrackableID = append(b.TrackableID, trackable)
+	b.ProviderID = append(b.ProviderID, loc.ProviderID)
+	b.ProviderType = append(b.ProviderType, loc.ProviderType.String())
+	b.Crs = append(b.Crs, crs)
+	b.X = append(b.X, x)
+	b.Y = append(b.Y, y)
+	b.Z = append(b.Z, loc.Position.Z())
+	b.Floor = append(b.Floor, loc.Floor)
+	b.Accuracy = append(b.Accuracy, loc.Accuracy)
+	b.At = append(b.At, at.UTC().Format(timeFormat))
+	b.n++
+
+	if len(batches) == 0 || batches[len(batches)-1] != b {
+		batches = append(batches, b)
+	}
+	if b.n >= w.batchSize {
+		w.current = nil
+		b.timer.Stop()
+		go w.flush(b)
+	}
+	return batches
+}
+
+// Flush inserts the rows written and not inserted yet. If the context is done first,
+// the insert goes on in the background.
+func (w *Writer) Flush(ctx context.Context) error {
+	w.mu.Lock()
+	b := w.current
+	w.mu.Unlock()
+	if b == nil {
+		return nil
+	}
+
+	go w.flush(b)
+	select {
+	case <-b.done:
+		return b.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close flushes the rows written and waits for all the batches to be inserted, or for
+// the context to be done. Writes to a closed writer fail with ErrClosed.
+func (w *Writer) Close(ctx context.Context) error {
+	w.mu.Lock()
+	w.closed = true
+	w.mu.Unlock()
+
+	err := w.Flush(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		w.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// flush inserts a batch, once.
+func (w *Writer) flush(b *batch) {
+	b.once.Do(func() {
+		defer w.pending.Done()
+
+		w.mu.Lock()
+		if w.current == b {
+			w.current = nil
+		}
+		w.mu.Unlock()
+		b.timer.Stop()
+
+		ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+		defer cancel()
+
+		b.err = w.insert(ctx, &b.columns)
+		close(b.done)
+	})
+}
+
+// insert inserts the rows of columns.
+func (w *Writer) insert(ctx context.Context, c *columns) error {
+	var body bytes.Buffer
+	zw := gzip.NewWriter(&body)
+	if err := json.NewEncoder(zw).Encode(c); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	settings := url.Values{}
+	if w.asyncInsert {
+		settings.Set("async_insert", "1")
+		settings.Set("wait_for_async_insert", "1")
+	}
+	header := http.Header{"Content-Encoding": {"gzip"}}
+	return w.exec(ctx, `INSERT INTO `+w.tableName()+` FORMAT JSONColumns`, settings, &requestBody{header, &body})
+}
+
+// requestBody is the body of a request, with its headers.
+type requestBody struct {
+	header http.Header
+	r      io.Reader
+}
+
+// exec executes a query, with settings and the data of the query, if any.
+func (w *Writer) exec(ctx context.Context, query string, settings url.Values, data *requestBody) error {
+	u := *w.endpoint
+	q := u.Query()
+	for k, v := range settings {
+		q[k] = v
+	}
+
+	var body io.Reader = strings.NewReader(query)
+	if data != nil {
+		q.Set("query", query)
+		body = data.r
+	}
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), body)
+	if err != nil {
+		return err
+	}
+	if data != nil {
+		for k, v := range data.header {
+			req.Header[k] = v
+		}
+	}
+	if w.user != "" {
+		req.Header.Set("X-ClickHouse-User", w.user)
+		req.Header.Set("X-ClickHouse-Key", w.password)
+	}
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &Error{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(msg))}
+	}
+	_, err = io.Copy(io.Discard, resp.Body)
+	return err
+}
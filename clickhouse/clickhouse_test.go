@@ -0,0 +1,275 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package clickhouse
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/forward"
+)
+
+var (
+	forklift = uuid.MustParse("6c5d2f0e-8f6a-4b7e-9a51-0d6f1a2b3c41")
+	pallet   = uuid.MustParse("9b8a7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d")
+)
+
+// server is a fake ClickHouse server recording the queries and inserted batches.
+type server struct {
+	mu      sync.Mutex
+	queries []string
+	batches []columns
+	fail    bool
+}
+
+func newServer(t *testing.T) (*server, string) {
+	t.Helper()
+
+	srv := &server{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-ClickHouse-User") != "ingest" || r.Header.Get("X-ClickHouse-Key") != "secret" {
+			http.Error(w, "Code: 516. DB::Exception: Authentication failed", http.StatusUnauthorized)
+			return
+		}
+
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+
+		if srv.fail {
+			http.Error(w, "Code: 60. DB::Exception: Table omlox.locations does not exist", http.StatusNotFound)
+			return
+		}
+
+		query := r.URL.Query().Get("query")
+		if query == "" {
+			body, _ := io.ReadAll(r.Body)
+			srv.queries = append(srv.queries, string(body))
+			return
+		}
+
+		srv.queries = append(srv.queries, query)
+		if r.URL.Query().Get("async_insert") != "1" || r.Header.Get("Content-Encoding") != "gzip" {
+			http.Error(w, "expected a compressed async insert", http.StatusBadRequest)
+			return
+		}
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var c columns
+		if err := json.NewDecoder(zr).Decode(&c); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		srv.batches = append(srv.batches, c)
+	}))
+	t.Cleanup(ts.Close)
+
+	return srv, ts.URL
+}
+
+func location(provider string, x, y float64, trackables ...uuid.UUID) omlox.Location {
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	return omlox.Location{
+		Position:           *omlox.NewPoint(geometry.Point{X: x, Y: y}),
+		ProviderID:         provider,
+		ProviderType:       omlox.LocationProviderTypeUwb,
+		Trackables:         trackables,
+		TimestampGenerated: &at,
+	}
+}
+
+func TestWriterBatches(t *testing.T) {
+	srv, endpoint := newServer(t)
+	w, err := New(endpoint, WithCredentials("ingest", "secret"), WithBatchSize(3), WithFlushInterval(time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := w.CreateTable(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// writes wait for their batch, filled by concurrent writes
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		errs <- w.Write(ctx, location("tag-1", 3, 4, forklift, pallet))
+	}()
+	go func() {
+		defer wg.Done()
+		payload := json.RawMessage(`{"position":{"type":"Point","coordinates":[1,2]},"provider_id":"tag-2","provider_type":"uwb","source":"tag-2","accuracy":0.5,"timestamp_generated":"2024-05-01T10:00:00Z"}`)
+		errs <- w.Deliver(ctx, forward.Event{Topic: omlox.TopicLocationUpdates, Payload: payload})
+	}()
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	if len(srv.queries) != 3 || srv.queries[2] != "INSERT INTO `omlox`.`locations` FORMAT JSONColumns" {
+		t.Fatalf("unexpected queries: %q", srv.queries)
+	}
+	if len(srv.batches) != 1 {
+		t.Fatalf("expected 1 batch, got %d", len(srv.batches))
+	}
+	b := srv.batches[0]
+	if len(b.Key) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(b.Key))
+	}
+	if diff := cmp.Diff([]string{"2024-05-01 10:00:00.000", "2024-05-01 10:00:00.000", "2024-05-01 10:00:00.000"}, b.At); diff != "" {
+		t.Errorf("unexpected times (-want +got):\n%s", diff)
+	}
+	for i, key := range b.Key {
+		switch key {
+		case forklift.String(), pallet.String():
+			if b.TrackableID[i] == nil || *b.TrackableID[i] != key || b.X[i] != 3 || b.Accuracy[i] != nil {
+				t.Errorf("unexpected row of %s", key)
+			}
+		case "tag-2":
+			if b.TrackableID[i] != nil || b.Crs[i] != omlox.CrsLocal || b.ProviderType[i] != "uwb" || *b.Accuracy[i] != 0.5 {
+				t.Errorf("unexpected row of %s", key)
+			}
+		default:
+			t.Errorf("unexpected key %s", key)
+		}
+	}
+}
+
+func TestWriterFlush(t *testing.T) {
+	srv, endpoint := newServer(t)
+	w, err := New(endpoint, WithCredentials("ingest", "secret"), WithFlushInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	// batches are inserted after the flush interval
+	if err := w.Write(ctx, location("tag-1", 3, 4)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Flush(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	srv.mu.Lock()
+	if len(srv.batches) != 1 || srv.batches[0].Key[0] != "tag-1" {
+		t.Errorf("unexpected batches: %+v", srv.batches)
+	}
+	srv.fail = true
+	srv.mu.Unlock()
+
+	// rows of writes done before their batch are flushed by Flush
+	w, err = New(endpoint, WithCredentials("ingest", "secret"), WithFlushInterval(time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Millisecond)
+	defer cancel()
+	if err := w.Write(ctx, location("tag-1", 3, 4)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline error, got %v", err)
+	}
+	var chErr *Error
+	if err := w.Flush(context.Background()); !errors.As(err, &chErr) || chErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected a server error, got %v", err)
+	}
+}
+
+func TestWriterShutdown(t *testing.T) {
+	srv, endpoint := newServer(t)
+	transport := &http.Transport{}
+	w, err := New(endpoint, WithCredentials("ingest", "secret"), WithHTTPClient(&http.Client{Transport: transport}), WithBatchSize(7), WithFlushInterval(time.Millisecond))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	goroutines := runtime.NumGoroutine()
+
+	// concurrent writes, some canceled before or while waiting for their batch
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		written = make(map[string]bool)
+	)
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(i%5)*time.Millisecond)
+			defer cancel()
+
+			provider := fmt.Sprintf("tag-%d", i)
+			if err := w.Write(ctx, location(provider, 1, 2)); err == nil {
+				mu.Lock()
+				written[provider] = true
+				mu.Unlock()
+			} else if !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, ErrClosed) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(i)
+	}
+
+	time.Sleep(2 * time.Millisecond)
+	if err := w.Close(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wg.Wait()
+
+	if err := w.Write(context.Background(), location("tag-closed", 1, 2)); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected a closed error, got %v", err)
+	}
+
+	// written rows are inserted once, and no insert is in flight after Close
+	srv.mu.Lock()
+	inserted := make(map[string]int)
+	for _, b := range srv.batches {
+		for _, key := range b.Key {
+			inserted[key]++
+		}
+	}
+	srv.mu.Unlock()
+	for key, n := range inserted {
+		if n != 1 {
+			t.Errorf("expected %s inserted once, got %d", key, n)
+		}
+	}
+	for key := range written {
+		if inserted[key] != 1 {
+			t.Errorf("expected written %s to be inserted", key)
+		}
+	}
+
+	// idle connections are not leaked goroutines of the writer
+	transport.CloseIdleConnections()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > goroutines && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > goroutines {
+		t.Errorf("expected %d goroutines after Close, got %d", goroutines, n)
+	}
+}
@@ -12,7 +12,9 @@ import (
 	"net/http"
 	"net/url"
 	"sync"
+	"sync/atomic"
 
+	"github.com/mailru/easyjson"
 	"golang.org/x/sync/errgroup"
 	"nhooyr.io/websocket"
 )
@@ -28,9 +30,27 @@ type Client struct {
 
 	client *http.Client
 
+	// tokens authenticating the requests, if any
+	tokens *cachedTokenSource
+
 	Trackables TrackablesAPI
 	Providers  ProvidersAPI
 	Fences     FencesAPI
+	Zones      ZonesAPI
+	History    HistoryAPI
+	Sensors    SensorsAPI
+	Quality    QualityAPI
+	Anchors    AnchorsAPI
+	Analytics  AnalyticsAPI
+	Webhooks   WebhooksAPI
+
+	// hub information, fetched on demand for feature gating
+	infoMu sync.Mutex
+	info   *HubInfo
+
+	// features found missing, replaced by fallbacks
+	degradedMu sync.Mutex
+	degraded   map[Feature]degradation
 
 	// websockets client fields
 
@@ -38,8 +58,15 @@ type Client struct {
 	cancel context.CancelFunc
 
 	// websockets connection
-	conn   *websocket.Conn
-	closed bool
+	conn      *websocket.Conn
+	closed    bool
+	connState ConnState
+
+	// closing is set while the client closes the connection, which is not a drop
+	closing atomic.Bool
+
+	// writes in flight on the connection
+	writes sync.WaitGroup
 
 	// reconnect lifecycle
 	reconnectCancel context.CancelFunc
@@ -78,6 +105,31 @@ func newClient(addr string, configuration ClientConfiguration) (*Client, error)
 		return nil, err
 	}
 
+	if configuration.BasicAuth != nil && configuration.TokenSource != nil {
+		return nil, fmt.Errorf("basic authentication and token source are exclusive")
+	}
+
+	if configuration.Proxy != nil {
+		configuration.HTTPClient, err = withProxy(configuration.HTTPClient, configuration.Proxy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if configuration.TLSConfig != nil {
+		configuration.HTTPClient, err = withTLSConfig(configuration.HTTPClient, configuration.TLSConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if configuration.TLSSessionCacheSize > 0 {
+		configuration.HTTPClient, err = withTLSSessionCache(configuration.HTTPClient, configuration.TLSSessionCacheSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	c := Client{
 		configuration: configuration,
 
@@ -94,21 +146,84 @@ func newClient(addr string, configuration ClientConfiguration) (*Client, error)
 		subs: make(map[int]*Subcription),
 	}
 
+	if configuration.TokenSource != nil {
+		c.tokens = &cachedTokenSource{src: configuration.TokenSource, clock: c.timeSource()}
+	}
+
 	c.Trackables = TrackablesAPI{
-		client: &c,
+		client:  &c,
+		service: NewService[Trackable](&c, "/trackables"),
 	}
 
 	c.Providers = ProvidersAPI{
-		client: &c,
+		client:  &c,
+		service: NewService[LocationProvider](&c, "/providers"),
 	}
 
 	c.Fences = FencesAPI{
+		client:  &c,
+		service: NewService[Fence](&c, "/fences"),
+	}
+
+	c.Zones = ZonesAPI{
+		client:  &c,
+		service: NewService[Zone](&c, "/zones"),
+	}
+
+	c.History = HistoryAPI{
 		client: &c,
 	}
 
+	c.Sensors = SensorsAPI{
+		client: &c,
+	}
+
+	c.Quality = QualityAPI{
+		client: &c,
+		stats:  newQualityStats(),
+	}
+
+	c.Anchors = AnchorsAPI{
+		client: &c,
+	}
+
+	c.Analytics = AnalyticsAPI{
+		client: &c,
+	}
+
+	c.Webhooks = WebhooksAPI{
+		client:  &c,
+		service: NewService[Webhook](&c, "/webhooks"),
+	}
+
 	return &c, nil
 }
 
+// Do sends a request to the Hub at the given path, encoding the body, if not nil, and
+// decoding the response into out, if not nil. It is meant for vendor extensions and
+// other endpoints not covered by the API groups of the client.
+func (c *Client) Do(ctx context.Context, method string, path string, body any, out any) error {
+	var (
+		resp *json.RawMessage
+		err  error
+	)
+
+	if body != nil {
+		resp, err = sendStructuredRequestParseResponse[json.RawMessage](ctx, c, method, path, body, nil, nil)
+	} else {
+		resp, err = sendRequestParseResponse[json.RawMessage](ctx, c, method, path, nil, nil, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	if out == nil || resp == nil {
+		return nil
+	}
+
+	return json.Unmarshal(*resp, out)
+}
+
 // sendStructuredRequestParseResponse constructs a structured request, sends it, and parses the response
 func sendStructuredRequestParseResponse[ResponseT any](
 	ctx context.Context,
@@ -121,7 +236,12 @@ func sendStructuredRequestParseResponse[ResponseT any](
 ) (*ResponseT, error) {
 	var buf bytes.Buffer
 
-	if err := json.NewEncoder(&buf).Encode(body); err != nil {
+	// generated encoders are preferred, as bodies such as location updates are on hot paths
+	if m, ok := body.(easyjson.Marshaler); ok {
+		if _, err := easyjson.MarshalToWriter(m, &buf); err != nil {
+			return nil, fmt.Errorf("could not encode request body: %w", err)
+		}
+	} else if err := json.NewEncoder(&buf).Encode(body); err != nil {
 		return nil, fmt.Errorf("could not encode request body: %w", err)
 	}
 
@@ -146,30 +266,14 @@ func sendRequestParseResponse[ResponseT any](
 	parameters url.Values,
 	headers http.Header,
 ) (*ResponseT, error) {
-	// apply the client-level request timeout, if set
-	if client.configuration.RequestTimeout > 0 {
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, client.configuration.RequestTimeout)
-		defer cancel()
-	}
-
-	// TODO: set User-Agent and Content-Type headers
+	// TODO: set Content-Type headers
 
-	req, err := client.newRequest(ctx, method, path, body, parameters, headers)
+	resp, err := client.do(ctx, method, path, body, parameters, headers)
 	if err != nil {
 		return nil, err
 	}
-
-	resp, err := client.send(ctx, req)
-	if err != nil || resp == nil {
-		return nil, err
-	}
 	defer resp.Body.Close()
 
-	if err := isResponseError(resp); err != nil {
-		return nil, err
-	}
-
 	return parseResponse[ResponseT](resp.Body)
 }
 
@@ -183,30 +287,14 @@ func sendRequestParseResponseList[ResponseT any](
 	parameters url.Values,
 	headers http.Header,
 ) ([]ResponseT, error) {
-	// apply the client-level request timeout, if set
-	if client.configuration.RequestTimeout > 0 {
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, client.configuration.RequestTimeout)
-		defer cancel()
-	}
+	// TODO: set Content-Type headers
 
-	// TODO: set User-Agent and Content-Type headers
-
-	req, err := client.newRequest(ctx, method, path, body, parameters, headers)
+	resp, err := client.do(ctx, method, path, body, parameters, headers)
 	if err != nil {
 		return nil, err
 	}
-
-	resp, err := client.send(ctx, req)
-	if err != nil || resp == nil {
-		return nil, err
-	}
 	defer resp.Body.Close()
 
-	if err := isResponseError(resp); err != nil {
-		return nil, err
-	}
-
 	return parseResponseList[ResponseT](resp.Body)
 }
 
@@ -232,14 +320,31 @@ func (c *Client) newRequest(
 		return nil, fmt.Errorf("could not create '%s %s' request: %w", method, url.String(), err)
 	}
 
-	// populate request headers
-	if headers != nil {
-		req.Header = headers
+	// populate request headers, over the base headers
+	req.Header = c.baseHeader()
+	for k, v := range headers {
+		req.Header[k] = append([]string(nil), v...)
+	}
+
+	if err := c.authenticate(ctx, req.Header); err != nil {
+		return nil, err
 	}
 
 	return req, nil
 }
 
+// baseHeader returns a copy of the headers sent with every request, including the User-Agent.
+func (c *Client) baseHeader() http.Header {
+	header := c.configuration.BaseHeaders.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	if c.configuration.UserAgent != "" && header.Get("User-Agent") == "" {
+		header.Set("User-Agent", c.configuration.UserAgent)
+	}
+	return header
+}
+
 // send sends the given request to Omlox.
 func (c *Client) send(ctx context.Context, req *http.Request) (*http.Response, error) {
 	// block on the rate limiter, if set
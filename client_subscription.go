@@ -5,6 +5,10 @@ package omlox
 
 import (
 	"encoding/json"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mailru/easyjson"
 )
 
 const (
@@ -18,7 +22,40 @@ type Subcription struct {
 	topic  Topic
 	params Parameters
 
+	// filter applied by the client to the received payloads, if any
+	filter *SubscriptionFilter
+
+	// schema version of the Hub the subscription was made to, refreshed on resubscription
+	version atomic.Pointer[string]
+
+	// migrations of the payloads of the topic
+	migrations []PayloadMigration
+
+	// quality metrics accounting the decoded location updates, if enabled
+	quality *QualityAPI
+
 	mch chan *WrapperObject
+
+	// closing of mch, once no message is being delivered to it
+	closeOnce sync.Once
+	closeMu   sync.RWMutex
+	closed    bool
+	doneOnce  sync.Once
+	done      chan struct{}
+}
+
+// PayloadMigration converts an event payload sent by a Hub of a schema version into
+// the payload expected by the typed structs. Payloads which need no conversion are returned as is.
+// The version is empty when the Hub does not expose its information.
+type PayloadMigration func(version string, payload json.RawMessage) (json.RawMessage, error)
+
+// Versioned is an event tagged with the schema version of the Hub which sent it.
+type Versioned[T any] struct {
+	// SchemaVersion is the omlox™ Hub API version of the Hub, empty if unknown.
+	SchemaVersion string
+
+	// Value is the decoded event.
+	Value *T
 }
 
 func ReceiveAs[T any](sub *Subcription) <-chan *T {
@@ -29,12 +66,12 @@ func ReceiveAs[T any](sub *Subcription) <-chan *T {
 
 		for msg := range sub.mch {
 			for _, payload := range msg.Payload {
-				var v T
-				if err := json.Unmarshal(payload, &v); err != nil {
+				v, _, err := decodePayload[T](sub, payload)
+				if err != nil {
 					continue
 				}
 
-				out <- &v
+				out <- v
 			}
 		}
 	}()
@@ -42,10 +79,183 @@ func ReceiveAs[T any](sub *Subcription) <-chan *T {
 	return out
 }
 
-func (s Subcription) ReceiveRaw() <-chan *WrapperObject {
+// ReceiveVersioned is like ReceiveAs, but tags the events with the schema version of the Hub.
+func ReceiveVersioned[T any](sub *Subcription) <-chan *Versioned[T] {
+	out := make(chan *Versioned[T], receiveChanSize)
+
+	go func() {
+		defer close(out)
+
+		for msg := range sub.mch {
+			for _, payload := range msg.Payload {
+				v, version, err := decodePayload[T](sub, payload)
+				if err != nil {
+					continue
+				}
+
+				out <- &Versioned[T]{SchemaVersion: version, Value: v}
+			}
+		}
+	}()
+
+	return out
+}
+
+// TopicEvent is an event received on a topic, decoded into the type of the topic.
+type TopicEvent struct {
+	// Topic is the topic of the event.
+	Topic Topic
+
+	// SchemaVersion is the omlox™ Hub API version of the Hub, empty if unknown.
+	SchemaVersion string
+
+	// Value is the decoded event: a *Location for location updates, a *FenceEvent for
+	// fence events, a *CollisionEvent for collision events, and the json.RawMessage
+	// payload for other topics, such as the GeoJSON ones. Invalid events are dropped.
+	Value any
+}
+
+// receiveTopics delivers the events of several subscriptions on a single channel, which
+// is closed once all the subscriptions are closed.
+func receiveTopics(subs []*Subcription) <-chan *TopicEvent {
+	out := make(chan *TopicEvent, receiveChanSize)
+
+	var wg sync.WaitGroup
+	wg.Add(len(subs))
+	for _, sub := range subs {
+		sub := sub
+		go func() {
+			defer wg.Done()
+
+			for msg := range sub.mch {
+				for _, payload := range msg.Payload {
+					v, version, err := decodeTopicPayload(sub, payload)
+					if err != nil {
+						continue
+					}
+
+					out <- &TopicEvent{Topic: sub.topic, SchemaVersion: version, Value: v}
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+// decodeTopicPayload decodes a payload into the type of the topic of the subscription.
+func decodeTopicPayload(sub *Subcription, payload json.RawMessage) (any, string, error) {
+	switch sub.topic {
+	case TopicLocationUpdates:
+		return decodePayload[Location](sub, payload)
+	case TopicFenceEvents:
+		return decodePayload[FenceEvent](sub, payload)
+	case TopicCollisionEvents:
+		return decodePayload[CollisionEvent](sub, payload)
+	default:
+		v, version, err := decodePayload[json.RawMessage](sub, payload)
+		if err != nil {
+			return nil, version, err
+		}
+		return *v, version, nil
+	}
+}
+
+// decodePayload migrates and decodes a payload, returning the schema version it was decoded from.
+func decodePayload[T any](sub *Subcription, payload json.RawMessage) (*T, string, error) {
+	var v T
+	version, err := decodePayloadInto(sub, payload, &v)
+	if err != nil {
+		return nil, version, err
+	}
+
+	return &v, version, nil
+}
+
+// decodePayloadInto migrates and decodes a payload into a value.
+func decodePayloadInto[T any](sub *Subcription, payload json.RawMessage, v *T) (string, error) {
+	version := sub.SchemaVersion()
+
+	var err error
+	for _, migrate := range sub.migrations {
+		if payload, err = migrate(version, payload); err != nil {
+			return version, err
+		}
+	}
+
+	if u, ok := any(v).(easyjson.Unmarshaler); ok {
+		err = easyjson.Unmarshal(payload, u)
+	} else {
+		err = json.Unmarshal(payload, v)
+	}
+	if err != nil {
+		return version, err
+	}
+
+	// events are decoded leniently, and dropped from subscriptions when invalid
+	switch e := any(v).(type) {
+	case *FenceEvent:
+		err = e.Validate()
+	case *CollisionEvent:
+		err = e.Validate()
+	case *Location:
+		if sub.quality != nil {
+			sub.quality.observe(e)
+		}
+	}
+
+	return version, err
+}
+
+// Topic returns the topic of the subscription.
+func (s *Subcription) Topic() Topic {
+	return s.topic
+}
+
+func (s *Subcription) ReceiveRaw() <-chan *WrapperObject {
 	return s.mch
 }
 
+// SchemaVersion returns the omlox™ Hub API version of the Hub the subscription
+// is made to, or an empty string if unknown.
+func (s *Subcription) SchemaVersion() string {
+	if v := s.version.Load(); v != nil {
+		return *v
+	}
+	return ""
+}
+
+func (s *Subcription) setSchemaVersion(info *HubInfo) {
+	var version string
+	if info != nil {
+		version = info.Version
+	}
+	s.version.Store(&version)
+}
+
+// doneC returns the channel closed when the subscription is closed.
+func (s *Subcription) doneC() chan struct{} {
+	s.doneOnce.Do(func() {
+		s.done = make(chan struct{})
+	})
+	return s.done
+}
+
+// close closes the channel of the subscription. Deliveries waiting on the channel
+// give up, and later deliveries are ignored.
 func (s *Subcription) close() {
-	close(s.mch)
+	s.closeOnce.Do(func() {
+		close(s.doneC())
+
+		s.closeMu.Lock()
+		defer s.closeMu.Unlock()
+
+		s.closed = true
+		close(s.mch)
+	})
 }
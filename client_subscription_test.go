@@ -0,0 +1,135 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestReceiveVersioned(t *testing.T) {
+	// hubs of version 1.0 named the provider id field differently
+	migration := func(version string, payload json.RawMessage) (json.RawMessage, error) {
+		if version != "1.0.0" {
+			return payload, nil
+		}
+		return bytes.Replace(payload, []byte(`"provider":`), []byte(`"provider_id":`), 1), nil
+	}
+
+	cfg := DefaultConfiguration()
+	if err := WithPayloadMigration(TopicLocationUpdates, migration)(&cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := WithPayloadMigration(TopicLocationUpdates, nil)(&cfg); err == nil {
+		t.Error("expected an error for a nil migration")
+	}
+
+	tests := []struct {
+		name    string
+		version string
+		payload string
+	}{
+		{name: "older", version: "1.0.0", payload: `{"provider":"p1","provider_type":"uwb","source":"s","position":{"type":"Point","coordinates":[1,2]}}`},
+		{name: "current", version: "2.0.0", payload: `{"provider_id":"p1","provider_type":"uwb","source":"s","position":{"type":"Point","coordinates":[1,2]}}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sub := &Subcription{
+				topic:      TopicLocationUpdates,
+				migrations: cfg.PayloadMigrations[TopicLocationUpdates],
+				mch:        make(chan *WrapperObject, 1),
+			}
+			sub.setSchemaVersion(&HubInfo{Version: tc.version})
+
+			sub.mch <- &WrapperObject{Event: EventMsg, Topic: TopicLocationUpdates, Payload: []json.RawMessage{json.RawMessage(tc.payload)}}
+			sub.close()
+
+			var got []*Versioned[Location]
+			for v := range ReceiveVersioned[Location](sub) {
+				got = append(got, v)
+			}
+
+			if len(got) != 1 {
+				t.Fatalf("expected 1 event, got %d", len(got))
+			}
+			if got[0].SchemaVersion != tc.version {
+				t.Errorf("expected schema version %s, got %s", tc.version, got[0].SchemaVersion)
+			}
+			if got[0].Value.ProviderID != "p1" {
+				t.Errorf("expected provider id p1, got %q", got[0].Value.ProviderID)
+			}
+		})
+	}
+}
+
+func TestReceivePooled(t *testing.T) {
+	sub := &Subcription{
+		topic: TopicLocationUpdates,
+		mch:   make(chan *WrapperObject, 1),
+	}
+	sub.setSchemaVersion(&HubInfo{Version: "2.0.0"})
+
+	sub.mch <- &WrapperObject{Event: EventMsg, Topic: TopicLocationUpdates, Payload: []json.RawMessage{
+		json.RawMessage(`{"provider_id":"p1","provider_type":"uwb","source":"s","accuracy":0.5,"trackables":["30c4e3a6-2ae8-4f7b-8a7e-3d1e1e7a6f1a"],"position":{"type":"Point","coordinates":[1,2]}}`),
+		json.RawMessage(`{"provider_id":"p2","provider_type":"uwb","source":"s","position":{"type":"Point","coordinates":[3,4]}}`),
+	}}
+	sub.close()
+
+	var got []string
+	for p := range ReceivePooled[Location](sub) {
+		if p.SchemaVersion != "2.0.0" {
+			t.Errorf("expected schema version 2.0.0, got %s", p.SchemaVersion)
+		}
+
+		// fields of released events never leak into the next ones
+		if p.Value.ProviderID == "p2" && (p.Value.Accuracy != nil || len(p.Value.Trackables) != 0) {
+			t.Errorf("unexpected fields from a previous event: %+v", p.Value)
+		}
+
+		got = append(got, p.Value.ProviderID)
+		p.Release()
+		p.Release()
+	}
+
+	if len(got) != 2 || got[0] != "p1" || got[1] != "p2" {
+		t.Errorf("unexpected events: %v", got)
+	}
+}
+
+func TestDeliverWaits(t *testing.T) {
+	c := &Client{}
+	sub := &Subcription{
+		topic: TopicLocationUpdates,
+		mch:   make(chan *WrapperObject, 1),
+	}
+
+	// messages are delivered to a slow receiver rather than dropped
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 3; i++ {
+			c.deliver(context.Background(), sub, &WrapperObject{Event: EventMsg, Topic: TopicLocationUpdates, SubscriptionID: i})
+		}
+	}()
+
+	for i := 0; i < 3; i++ {
+		time.Sleep(150 * time.Millisecond)
+		if msg := <-sub.mch; msg.SubscriptionID != i {
+			t.Fatalf("expected message %d, got %d", i, msg.SubscriptionID)
+		}
+	}
+	<-done
+
+	// while deliveries waiting on a closed subscription give up
+	sub.mch <- &WrapperObject{}
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		sub.close()
+	}()
+	c.deliver(context.Background(), sub, &WrapperObject{})
+}
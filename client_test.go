@@ -0,0 +1,76 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithToken(t *testing.T) {
+	var auth string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL, WithToken("secret"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := c.Do(context.Background(), http.MethodGet, "/info", nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("expected bearer token, got %q", auth)
+	}
+}
+
+func TestClientOptions(t *testing.T) {
+	var header http.Header
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	// the client trusts the certificate of the test server through the TLS configuration only
+	tlsConfig := srv.Client().Transport.(*http.Transport).TLSClientConfig
+	c, err := New(srv.URL,
+		WithTLSConfig(tlsConfig),
+		WithTimeout(time.Second),
+		WithUserAgent("dock-service/1.0"),
+		WithBaseHeaders(http.Header{"x-tenant": {"plant-1"}, "X-Request-Source": {"service"}}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.configuration.RequestTimeout != time.Second {
+		t.Errorf("expected a timeout of 1s, got %v", c.configuration.RequestTimeout)
+	}
+
+	// headers of a request take precedence over the base headers
+	_, err = sendRequestParseResponse[json.RawMessage](context.Background(), c, http.MethodGet, "/info", nil, nil, http.Header{"X-Request-Source": {"request"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{"User-Agent": "dock-service/1.0", "X-Tenant": "plant-1", "X-Request-Source": "request"}
+	for k, v := range want {
+		if got := header.Get(k); got != v {
+			t.Errorf("expected header %s %q, got %q", k, v, got)
+		}
+	}
+
+	if _, err := New(srv.URL, WithTLSConfig(nil)); err == nil {
+		t.Errorf("expected an error for a nil tls config")
+	}
+}
@@ -12,16 +12,18 @@ import (
 	"log/slog"
 	"math/rand"
 	"net"
+	"net/http"
 	"net/url"
 	"time"
 
 	"golang.org/x/sync/errgroup"
 	"nhooyr.io/websocket"
-	"nhooyr.io/websocket/wsjson"
 )
 
 const (
-	chanSendTimeout = 100 * time.Millisecond
+	// time allowed to write a message whose publisher gave up, so the frame is not
+	// interrupted half-written.
+	writeTimeout = 10 * time.Second
 
 	// time allowed to read the next pong message from the peer.
 	pongWait = 10 * time.Second
@@ -77,6 +79,8 @@ func (c *Client) Connect(ctx context.Context) error {
 	if err := c.dial(ctx); err != nil {
 		return err
 	}
+	c.closing.Store(false)
+	c.setConnState(ctx, ConnStateChange{State: ConnStateConnected})
 
 	if c.configuration.Reconnect != nil {
 		rctx, rcancel := context.WithCancel(ctx)
@@ -109,13 +113,32 @@ func (c *Client) dial(ctx context.Context) error {
 		return err
 	}
 
+	httpClient, err := c.websocketClient(c.baseAddress)
+	if err != nil {
+		return err
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	errg, ctx := errgroup.WithContext(ctx)
 
-	conn, _, err := websocket.Dial(ctx, wsURL.String(), &websocket.DialOptions{
-		HTTPClient: c.client,
-	})
-	if err != nil {
+	var conn *websocket.Conn
+	for refreshed := false; ; refreshed = true {
+		header := c.baseHeader()
+		if err := c.authenticate(ctx, header); err != nil {
+			cancel()
+			return err
+		}
+
+		var resp *http.Response
+		conn, resp, err = dialWebsocket(ctx, wsURL.String(), httpClient, header)
+		if err == nil {
+			break
+		}
+
+		// tokens rejected before their expiry are refreshed once
+		if !refreshed && resp != nil && resp.StatusCode == http.StatusUnauthorized && c.refreshAuthorization(nil, header) {
+			continue
+		}
 		cancel()
 		return err
 	}
@@ -137,7 +160,15 @@ func (c *Client) dial(ctx context.Context) error {
 	c.mu.Unlock()
 
 	c.errg.Go(func() error {
-		return c.readLoop(ctx)
+		// the connection is gone once reading ends, stop the ping loop
+		// so that a reconnection does not wait for the next ping to fail
+		defer cancel()
+
+		err := c.readLoop(ctx)
+		if !c.closing.Load() {
+			c.setConnState(ctx, ConnStateChange{State: ConnStateDisconnected, Err: err})
+		}
+		return err
 	})
 
 	c.errg.Go(func() error {
@@ -154,24 +185,30 @@ func (c *Client) reconnectLoop(ctx context.Context, reconnectCtx context.Context
 		// block until the current connection's goroutines finish
 		c.errg.Wait()
 
-		var attempt int
+		var (
+			attempt int
+			lastErr error
+		)
 		for {
 			delay := backoff(
 				c.configuration.Reconnect.MinWait,
 				c.configuration.Reconnect.MaxWait,
 				attempt,
 			)
-			timer := time.NewTimer(delay)
+			timer := c.timeSource().NewTimer(delay)
 
 			select {
 			case <-reconnectCtx.Done():
 				timer.Stop()
 				return
-			case <-timer.C:
+			case <-timer.C():
 			}
 
+			c.setConnState(reconnectCtx, ConnStateChange{State: ConnStateReconnecting, Attempt: attempt + 1, Err: lastErr})
+
 			if err := c.dial(ctx); err != nil {
 				attempt++
+				lastErr = err
 				slog.LogAttrs(ctx, slog.LevelWarn, "reconnect dial failed",
 					slog.Any("err", err),
 					slog.Int("attempt", attempt),
@@ -179,8 +216,17 @@ func (c *Client) reconnectLoop(ctx context.Context, reconnectCtx context.Context
 				continue
 			}
 
+			// the client was closed while dialing
+			if reconnectCtx.Err() != nil {
+				return
+			}
+
 			slog.LogAttrs(reconnectCtx, slog.LevelInfo, "reconnected successfully")
+
+			// the hub may have been upgraded while disconnected
+			c.resetInfo()
 			c.resubscribe(reconnectCtx)
+			c.setConnState(reconnectCtx, ConnStateChange{State: ConnStateConnected, Attempt: attempt + 1})
 			break
 		}
 	}
@@ -220,11 +266,64 @@ func (c *Client) publish(ctx context.Context, wrObj *WrapperObject) (err error)
 		return net.ErrClosed
 	}
 
-	// TODO @dvcorreia: use the easyjson marshal method.
-	return wsjson.Write(ctx, c.conn, wrObj)
+	buf := getEncodeBuffer()
+	if *buf, err = appendEasyJSON(*buf, wrObj); err != nil {
+		putEncodeBuffer(buf)
+		return err
+	}
+
+	return c.write(ctx, buf)
+}
+
+// write writes a message on the connection, and releases its buffer once written.
+//
+// Canceling the context of a write returns immediately, but does not interrupt it: a
+// frame interrupted half-written would corrupt the connection, which the websocket
+// library closes instead. The write goes on in the background for up to writeTimeout,
+// and is waited for by Close.
+func (c *Client) write(ctx context.Context, buf *[]byte) error {
+	if err := ctx.Err(); err != nil {
+		putEncodeBuffer(buf)
+		return err
+	}
+
+	c.mu.RLock()
+	conn, closed := c.conn, c.closed
+	if !closed {
+		c.writes.Add(1)
+	}
+	c.mu.RUnlock()
+
+	if closed {
+		putEncodeBuffer(buf)
+		return net.ErrClosed
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		defer c.writes.Done()
+		defer putEncodeBuffer(buf)
+
+		wctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), writeTimeout)
+		defer cancel()
+
+		done <- conn.Write(wctx, websocket.MessageText, *buf)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // Subscribe to a topic in Omlox Hub.
+//
+// Events are delivered on the channel of the subscription in arrival order, which is not
+// necessarily the order of their timestamps. The websocket reader waits for each event to
+// be received, so a channel not being drained holds back the events of every subscription
+// of the client.
 func (c *Client) Subscribe(ctx context.Context, topic Topic, params ...Parameter) (*Subcription, error) {
 	parameters := make(Parameters)
 	for _, param := range params {
@@ -236,6 +335,81 @@ func (c *Client) Subscribe(ctx context.Context, topic Topic, params ...Parameter
 	return c.subscribe(ctx, topic, parameters)
 }
 
+// SubscribeTopics subscribes to several topics in Omlox Hub, and delivers their events on
+// a single channel, decoded into the type of their topic (see TopicEvent). The channel is
+// closed once the client is closed. It fails if any of the subscriptions fails.
+func (c *Client) SubscribeTopics(ctx context.Context, topics ...Topic) (<-chan *TopicEvent, error) {
+	subs := make([]*Subcription, 0, len(topics))
+	for _, topic := range topics {
+		sub, err := c.Subscribe(ctx, topic)
+		if err != nil {
+			// subscriptions made are ended on the hub, and their channels closed
+			c.unsubscribe(context.WithoutCancel(ctx), subs...)
+
+			return nil, fmt.Errorf("subscribe to %s: %w", topic, err)
+		}
+		subs = append(subs, sub)
+	}
+
+	return receiveTopics(subs), nil
+}
+
+// SubscribeFunc subscribes to a topic, and calls the handler with each event, decoded into
+// the type of the topic as by SubscribeTopics, until the subscription is closed.
+//
+// Events of different trackables are handled concurrently by the number of workers of
+// WithHandlerWorkers, while the events of the same trackable are handled one at a time, in
+// arrival order. Events of several trackables, such as collisions, are handled after the
+// events received before them for each of their trackables, and events without trackables
+// are ordered with the events of their location provider. Events older than the last event
+// handled for one of their trackables are dropped with WithDropOutOfOrder.
+//
+// Up to the number of events of WithHandlerQueue are queued for each trackable. While a
+// queue is full, the subscription stops receiving events, holding back the websocket reader
+// and thus the events of every subscription of the client. No event is dropped meanwhile,
+// but a handler blocking for longer than the ping timeout of the connection (10s) gets the
+// connection closed and reconnected, losing the events sent by the Hub in between.
+//
+// The handler runs on goroutines of the subscription. Its panics are recovered, and reported
+// along with its errors to the ErrorHandler of the configuration, so a buggy handler does not
+// take down the websocket reader nor the other subscribers.
+func (c *Client) SubscribeFunc(ctx context.Context, topic Topic, handler func(*TopicEvent) error, params ...Parameter) (*Subcription, error) {
+	sub, err := c.Subscribe(ctx, topic, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	rctx := context.WithoutCancel(ctx)
+	d := newDispatcher(c.configuration.HandlerWorkers, c.configuration.HandlerQueue, c.configuration.DropOutOfOrder, func(event *TopicEvent) {
+		err := protect("event handler", topic, func() error {
+			if err := handler(event); err != nil {
+				return fmt.Errorf("event handler of %s: %w", topic, err)
+			}
+			return nil
+		})
+		if err != nil {
+			c.reportError(rctx, err)
+		}
+	})
+
+	go func() {
+		defer d.close()
+
+		for msg := range sub.mch {
+			for _, payload := range msg.Payload {
+				v, version, err := decodeTopicPayload(sub, payload)
+				if err != nil {
+					continue
+				}
+
+				d.dispatch(rctx, &TopicEvent{Topic: topic, SchemaVersion: version, Value: v}, payload)
+			}
+		}
+	}()
+
+	return sub, nil
+}
+
 // Sends a subscription message and handles the confirmation from the server.
 //
 // The subscription will be attributed an ID that can used for futher context.
@@ -243,17 +417,30 @@ func (c *Client) Subscribe(ctx context.Context, topic Topic, params ...Parameter
 // Subsequent subscriptions will wait while the pending one is waiting for an ID from the server.
 // Since each subscription on a topic can have a distinct parameters, we must synchronisly wait to match each one to its ID.
 func (c *Client) subscribe(ctx context.Context, topic Topic, params Parameters) (*Subcription, error) {
-	_, err := c.sendSubscribe(ctx, topic, params) // BUG: use returned sid when bug is fixed
+	filter, err := NewSubscriptionFilter(params)
+	if err != nil {
+		return nil, err
+	}
+
+	// filters are always applied by the client, and also by the Hub when supported
+	params = c.hubParameters(ctx, params)
+
+	sid, err := c.sendSubscribe(ctx, topic, params)
 	if err != nil {
 		return nil, err
 	}
 
 	sub := &Subcription{
-		// sid:    sid,
-		sid:    0, // BUG: deephub doesn't return the sid in subsequent messages (NEEDS FIX!)
-		topic:  topic,
-		params: params,
-		mch:    make(chan *WrapperObject, 1),
+		sid:        sid,
+		topic:      topic,
+		params:     params,
+		filter:     filter,
+		migrations: c.protectMigrations(context.WithoutCancel(ctx), topic, c.configuration.PayloadMigrations[topic]),
+		mch:        make(chan *WrapperObject, 1),
+	}
+	sub.setSchemaVersion(c.hubInfo(ctx))
+	if c.configuration.ProviderQuality && topic == TopicLocationUpdates {
+		sub.quality = &c.Quality
 	}
 
 	// promote a pending subcription
@@ -312,6 +499,29 @@ func (c *Client) sendSubscribe(ctx context.Context, topic Topic, params Paramete
 	return r.sid, nil
 }
 
+// unsubscribe ends subscriptions: their messages are no longer routed, unsubscribe
+// messages are sent to the hub and their channels are closed. Failures to send the
+// messages are logged, the subscriptions ending with the connection anyway.
+func (c *Client) unsubscribe(ctx context.Context, subs ...*Subcription) {
+	c.mu.Lock()
+	for _, sub := range subs {
+		delete(c.subs, sub.sid)
+	}
+	c.mu.Unlock()
+
+	for _, sub := range subs {
+		err := c.publish(ctx, &WrapperObject{
+			Event:          EventUnsubscribe,
+			Topic:          sub.topic,
+			SubscriptionID: sub.sid,
+		})
+		if err != nil {
+			slog.LogAttrs(ctx, slog.LevelWarn, "unsubscribe failed", slog.Int("sid", sub.sid), slog.Any("err", err))
+		}
+		sub.close()
+	}
+}
+
 // resubscribe sends subscribe messages for all existing subscriptions after
 // a successful reconnection, reusing the existing Subcription objects so that
 // callers continue to receive messages on their existing channels.
@@ -339,14 +549,16 @@ func (c *Client) resubscribeSub(ctx context.Context, sub *Subcription) error {
 	ctx, cancel := context.WithTimeout(ctx, SubscriptionTimeout)
 	defer cancel()
 
-	_, err := c.sendSubscribe(ctx, sub.topic, sub.params) // BUG: use returned sid when bug is fixed
+	sid, err := c.sendSubscribe(ctx, sub.topic, sub.params)
 	if err != nil {
 		return err
 	}
 
+	sub.setSchemaVersion(c.hubInfo(ctx))
+
 	c.mu.Lock()
 	delete(c.subs, sub.sid)
-	sub.sid = 0 // BUG: deephub doesn't return the sid in subsequent messages (NEEDS FIX!)
+	sub.sid = sid
 	c.subs[sub.sid] = sub
 	c.mu.Unlock()
 
@@ -355,20 +567,20 @@ func (c *Client) resubscribeSub(ctx context.Context, sub *Subcription) error {
 
 // ping pong loop that manages the websocket connection health.
 func (c *Client) pingLoop(ctx context.Context) error {
-	t := time.NewTicker(pingPeriod)
+	t := c.timeSource().NewTicker(pingPeriod)
 	defer t.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return nil
-		case <-t.C:
+		case <-t.C():
 		}
 
 		ctx, cancel := context.WithTimeout(ctx, pongWait)
 		defer cancel()
 
-		begin := time.Now()
+		begin := c.timeSource().Now()
 		err := c.conn.Ping(ctx)
 
 		if err != nil {
@@ -386,7 +598,7 @@ func (c *Client) pingLoop(ctx context.Context) error {
 			return err
 		}
 
-		slog.Debug("heartbeat", slog.Duration("latency", time.Since(begin)))
+		slog.Debug("heartbeat", slog.Duration("latency", c.timeSource().Now().Sub(begin)))
 	}
 }
 
@@ -490,28 +702,49 @@ func (c *Client) handleError(ctx context.Context, msg *wrapperObject) {
 }
 
 // routeMessage sends the message to the its respective subscription.
+//
+// Hubs such as DeepHub send messages without the subscription id, which are
+// sent to the subscriptions of their topic instead.
 func (c *Client) routeMessage(ctx context.Context, msg *WrapperObject) {
+
 	// retrive subcription if exists
 	c.mu.RLock()
-	sub := c.subs[msg.SubscriptionID]
+	var subs []*Subcription
+	if sub := c.subs[msg.SubscriptionID]; sub != nil {
+		subs = append(subs, sub)
+	} else {
+		for _, sub := range c.subs {
+			if sub.topic == msg.Topic || (msg.Topic == "" && len(c.subs) == 1) {
+				subs = append(subs, sub)
+			}
+		}
+	}
 	c.mu.RUnlock()
 
-	if sub == nil {
-		// TODO @dvcorreia: handle unknown subscription IDs
+	for _, sub := range subs {
+		c.deliver(ctx, sub, msg)
+	}
+}
+
+// deliver sends the message to a subscription, if it passes its filter. It waits until
+// the message is received, the subscription closed or the context done.
+func (c *Client) deliver(ctx context.Context, sub *Subcription, msg *WrapperObject) {
+	if msg = sub.filter.Apply(msg); msg == nil {
+		return
+	}
+
+	sub.closeMu.RLock()
+	defer sub.closeMu.RUnlock()
+	if sub.closed {
 		return
 	}
 
+	// a subscription not being received from holds back the websocket reader, and
+	// the events of the other subscriptions, rather than losing its events
 	select {
 	case <-ctx.Done():
-		return
-	case sub.mch <- msg: // TODO @dvcorreia: this will block other messages
-	case <-time.After(chanSendTimeout):
-		slog.LogAttrs(
-			context.Background(),
-			slog.LevelWarn,
-			"timeout sending to subscription channel",
-			slog.Any("event", msg),
-		)
+	case <-sub.doneC():
+	case sub.mch <- msg:
 	}
 }
 
@@ -544,16 +777,38 @@ func (c *Client) drainPending() {
 // Close releases any resources held by the client,
 // such as connections, memory and goroutines.
 func (c *Client) Close() error {
-	// stop the reconnect loop
+	c.closing.Store(true)
+
+	// stop the reconnect loop from dialing again
 	if c.reconnectCancel != nil {
 		c.reconnectCancel()
 	}
 
+	// the reconnect loop waits for the connection to end before exiting
+	err := c.closeConn()
+
 	// wait for reconnect goroutine to fully exit
 	if c.reconnectDone != nil {
 		<-c.reconnectDone
+
+		// a reconnection may have completed meanwhile
+		if !c.isClosed() {
+			err = c.closeConn()
+		}
+	}
+
+	// writes given up by their publishers end once the connection is closed
+	c.writes.Wait()
+	c.clearSubs()
+
+	if state := c.ConnState(); state != 0 && state != ConnStateClosed {
+		c.setConnState(context.Background(), ConnStateChange{State: ConnStateClosed})
 	}
+	return err
+}
 
+// closeConn closes the websocket connection and waits for its goroutines to finish.
+func (c *Client) closeConn() error {
 	if !c.isClosed() {
 		err := c.conn.Close(websocket.StatusNormalClosure, "")
 		if err != nil {
@@ -567,10 +822,7 @@ func (c *Client) Close() error {
 	c.mu.RUnlock()
 
 	cancel()
-	err := errg.Wait()
-
-	c.clearSubs()
-	return err
+	return errg.Wait()
 }
 
 // isClosed reports if the client closed.
@@ -0,0 +1,483 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package clip implements the boolean operations of polygons: union, intersection and
+// difference, such as to adjust the boundaries of adjacent fences.
+//
+// Polygons are overlaid by splitting their edges where they cross or touch, keeping
+// the edges bounding the result, and linking them back into rings. Shared and
+// overlapping edges, touching vertices and holes are supported. Points closer than a
+// tolerance relative to the extent of the polygons are merged, so results of previous
+// operations can be combined again.
+package clip
+
+import (
+	"math"
+	"sort"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+// relativeTolerance is the tolerance of the operations, relative to the extent of the polygons.
+const relativeTolerance = 1e-9
+
+// Polygon is a polygon with an exterior ring and holes, as GeoJSON polygons.
+// Input rings may be in any orientation, and may repeat their first point at the end.
+// Polygons of the same set must not overlap.
+type Polygon struct {
+	Exterior []geometry.Point
+	Holes    [][]geometry.Point
+}
+
+// Union returns the polygons of the area within a or b.
+func Union(a, b []Polygon) []Polygon {
+	return overlay(a, b, opUnion)
+}
+
+// Intersection returns the polygons of the area within both a and b.
+func Intersection(a, b []Polygon) []Polygon {
+	return overlay(a, b, opIntersection)
+}
+
+// Difference returns the polygons of the area within a but not within b.
+func Difference(a, b []Polygon) []Polygon {
+	return overlay(a, b, opDifference)
+}
+
+// op is a boolean operation.
+type op int
+
+const (
+	opUnion op = iota
+	opIntersection
+	opDifference
+)
+
+// class is the position of an edge relative to the polygons of the other operand.
+type class int
+
+const (
+	outside class = iota
+	inside
+	// sharedSame are edges shared with the other operand in the same direction, with both
+	// polygons on the same side.
+	sharedSame
+	// sharedOpposite are edges shared with the other operand in the opposite direction,
+	// with the polygons on either side.
+	sharedOpposite
+)
+
+// edge is a directed edge between two nodes, with the polygon on its left.
+type edge struct {
+	from, to int
+	owner    int
+}
+
+// overlay returns the polygons of the operation on a and b. The returned rings are
+// closed, exteriors counterclockwise and holes clockwise.
+func overlay(a, b []Polygon, o op) []Polygon {
+	operands := [2][][]geometry.Point{rings(a), rings(b)}
+	g := &graph{eps: tolerance(operands), cells: make(map[[2]int64][]int)}
+	edges := g.split(operands)
+
+	// edges of b shared with a, by direction
+	shared := make(map[[2]int]bool)
+	for _, e := range edges {
+		if e.owner == 1 {
+			shared[[2]int{e.from, e.to}] = true
+		}
+	}
+	inA := make(map[[2]int]bool)
+	for _, e := range edges {
+		if e.owner == 0 {
+			inA[[2]int{e.from, e.to}] = true
+			inA[[2]int{e.to, e.from}] = true
+		}
+	}
+
+	var selected []edge
+	for _, e := range edges {
+		c := outside
+		switch {
+		case e.owner == 1 && inA[[2]int{e.from, e.to}]:
+			// shared edges are selected once, as edges of a
+			continue
+		case e.owner == 0 && shared[[2]int{e.from, e.to}]:
+			c = sharedSame
+		case e.owner == 0 && shared[[2]int{e.to, e.from}]:
+			c = sharedOpposite
+		case contains(operands[1-e.owner], g.nodes[e.from].lerp(g.nodes[e.to], 0.5)):
+			c = inside
+		}
+
+		switch {
+		case o == opUnion && (c == outside || c == sharedSame),
+			o == opIntersection && (c == inside || c == sharedSame),
+			o == opDifference && e.owner == 0 && (c == outside || c == sharedOpposite):
+			selected = append(selected, e)
+		case o == opDifference && e.owner == 1 && c == inside:
+			selected = append(selected, edge{from: e.to, to: e.from, owner: 1})
+		}
+	}
+
+	return g.polygons(g.link(selected))
+}
+
+// rings returns the rings of the polygons without their closing points, exteriors
+// counterclockwise and holes clockwise. Empty rings, and polygons with an empty
+// exterior, are skipped.
+func rings(polys []Polygon) [][]geometry.Point {
+	var all [][]geometry.Point
+	for _, p := range polys {
+		ext := clean(p.Exterior)
+		if ext == nil {
+			continue
+		}
+		all = append(all, orient(ext, true))
+		for _, h := range p.Holes {
+			if h = clean(h); h != nil {
+				all = append(all, orient(h, false))
+			}
+		}
+	}
+	return all
+}
+
+// clean returns the ring without repeated points, nor its closing point, or nil if
+// the ring has no area.
+func clean(ring []geometry.Point) []geometry.Point {
+	var pts []geometry.Point
+	for _, p := range ring {
+		if len(pts) == 0 || p != pts[len(pts)-1] {
+			pts = append(pts, p)
+		}
+	}
+	for len(pts) > 1 && pts[0] == pts[len(pts)-1] {
+		pts = pts[:len(pts)-1]
+	}
+	if len(pts) < 3 || area(pts) == 0 {
+		return nil
+	}
+	return pts
+}
+
+// orient returns the ring counterclockwise, or clockwise.
+func orient(ring []geometry.Point, ccw bool) []geometry.Point {
+	if (area(ring) > 0) == ccw {
+		return ring
+	}
+	reversed := make([]geometry.Point, len(ring))
+	for i, p := range ring {
+		reversed[len(ring)-1-i] = p
+	}
+	return reversed
+}
+
+// area returns the area of a ring without its closing point, positive if counterclockwise.
+func area(ring []geometry.Point) float64 {
+	sum := 0.0
+	for i, p := range ring {
+		q := ring[(i+1)%len(ring)]
+		sum += p.X*q.Y - q.X*p.Y
+	}
+	return sum / 2
+}
+
+// tolerance returns the distance under which points are merged.
+func tolerance(operands [2][][]geometry.Point) float64 {
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, rs := range operands {
+		for _, r := range rs {
+			for _, p := range r {
+				minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+				minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+			}
+		}
+	}
+
+	extent := math.Max(maxX-minX, maxY-minY)
+	if math.IsInf(extent, 0) || extent == 0 {
+		return relativeTolerance
+	}
+	return extent * relativeTolerance
+}
+
+// contains reports whether a point is within the rings, by the even-odd rule.
+func contains(rings [][]geometry.Point, p vec) bool {
+	in := false
+	for _, r := range rings {
+		for i, a := range r {
+			b := r[(i+1)%len(r)]
+			if (a.Y > p.y) != (b.Y > p.y) && p.x < a.X+(p.y-a.Y)*(b.X-a.X)/(b.Y-a.Y) {
+				in = !in
+			}
+		}
+	}
+	return in
+}
+
+// vec is a point of the plane.
+type vec struct {
+	x, y float64
+}
+
+func fromPoint(p geometry.Point) vec {
+	return vec{p.X, p.Y}
+}
+
+func (v vec) point() geometry.Point {
+	return geometry.Point{X: v.x, Y: v.y}
+}
+
+func (v vec) sub(u vec) vec {
+	return vec{v.x - u.x, v.y - u.y}
+}
+
+func (v vec) cross(u vec) float64 {
+	return v.x*u.y - v.y*u.x
+}
+
+func (v vec) dot(u vec) float64 {
+	return v.x*u.x + v.y*u.y
+}
+
+func (v vec) norm() float64 {
+	return math.Hypot(v.x, v.y)
+}
+
+func (v vec) dist(u vec) float64 {
+	return v.sub(u).norm()
+}
+
+func (v vec) lerp(u vec, t float64) vec {
+	return vec{v.x + t*(u.x-v.x), v.y + t*(u.y-v.y)}
+}
+
+// closest returns the position along the segment from a to b closest to v, as a fraction
+// of the segment, and its distance to v.
+func (v vec) closest(a, b vec) (t float64, distance float64) {
+	ab := b.sub(a)
+	if length := ab.dot(ab); length > 0 {
+		t = math.Max(0, math.Min(1, v.sub(a).dot(ab)/length))
+	}
+	return t, v.dist(a.lerp(b, t))
+}
+
+// graph are the nodes of the overlaid polygons, merged within the tolerance.
+type graph struct {
+	eps   float64
+	nodes []vec
+	cells map[[2]int64][]int
+}
+
+// node returns the node of a point, merging the points closer than the tolerance.
+func (g *graph) node(p vec) int {
+	cx, cy := int64(math.Floor(p.x/g.eps)), int64(math.Floor(p.y/g.eps))
+	for dx := int64(-1); dx <= 1; dx++ {
+		for dy := int64(-1); dy <= 1; dy++ {
+			for _, n := range g.cells[[2]int64{cx + dx, cy + dy}] {
+				if g.nodes[n].dist(p) <= g.eps {
+					return n
+				}
+			}
+		}
+	}
+
+	g.nodes = append(g.nodes, p)
+	g.cells[[2]int64{cx, cy}] = append(g.cells[[2]int64{cx, cy}], len(g.nodes)-1)
+	return len(g.nodes) - 1
+}
+
+// segment is an edge of a ring, with the positions along it where it is split.
+type segment struct {
+	a, b  vec
+	owner int
+	split []float64
+}
+
+// split returns the edges of the rings of both operands, split where they cross or
+// touch the edges of the other operand.
+func (g *graph) split(operands [2][][]geometry.Point) []edge {
+	var segs [2][]*segment
+	for owner, rs := range operands {
+		for _, r := range rs {
+			for i, p := range r {
+				segs[owner] = append(segs[owner], &segment{
+					a:     fromPoint(p),
+					b:     fromPoint(r[(i+1)%len(r)]),
+					owner: owner,
+					split: []float64{0, 1},
+				})
+			}
+		}
+	}
+
+	for _, s := range segs[0] {
+		for _, u := range segs[1] {
+			g.intersect(s, u)
+		}
+	}
+
+	var edges []edge
+	for _, ss := range segs {
+		for _, s := range ss {
+			sort.Float64s(s.split)
+			prev := -1
+			for _, t := range s.split {
+				n := g.node(s.a.lerp(s.b, t))
+				if prev >= 0 && n != prev {
+					edges = append(edges, edge{from: prev, to: n, owner: s.owner})
+				}
+				prev = n
+			}
+		}
+	}
+	return edges
+}
+
+// intersect adds the positions where two segments cross or touch to their splits.
+func (g *graph) intersect(s, u *segment) {
+	if math.Max(s.a.x, s.b.x)+g.eps < math.Min(u.a.x, u.b.x) || math.Max(u.a.x, u.b.x)+g.eps < math.Min(s.a.x, s.b.x) ||
+		math.Max(s.a.y, s.b.y)+g.eps < math.Min(u.a.y, u.b.y) || math.Max(u.a.y, u.b.y)+g.eps < math.Min(s.a.y, s.b.y) {
+		return
+	}
+
+	// endpoints on the other segment, including overlaps of collinear segments
+	for _, p := range []vec{u.a, u.b} {
+		if t, d := p.closest(s.a, s.b); d <= g.eps {
+			s.split = append(s.split, t)
+		}
+	}
+	for _, p := range []vec{s.a, s.b} {
+		if t, d := p.closest(u.a, u.b); d <= g.eps {
+			u.split = append(u.split, t)
+		}
+	}
+
+	// crossings inside both segments, from the signed distances of the endpoints to the
+	// line of the other segment
+	ls, lu := s.b.sub(s.a).norm(), u.b.sub(u.a).norm()
+	if ls == 0 || lu == 0 {
+		return
+	}
+	d1, d2 := u.b.sub(u.a).cross(s.a.sub(u.a))/lu, u.b.sub(u.a).cross(s.b.sub(u.a))/lu
+	d3, d4 := s.b.sub(s.a).cross(u.a.sub(s.a))/ls, s.b.sub(s.a).cross(u.b.sub(s.a))/ls
+	if opposite(d1, d2, g.eps) && opposite(d3, d4, g.eps) {
+		s.split = append(s.split, d1/(d1-d2))
+		u.split = append(u.split, d3/(d3-d4))
+	}
+}
+
+// opposite reports whether two signed distances are on opposite sides, beyond the tolerance.
+func opposite(a, b, eps float64) bool {
+	return (a > eps && b < -eps) || (a < -eps && b > eps)
+}
+
+// link links the edges into rings of nodes, turning as much to the left as possible
+// where several edges leave a node, and splitting the rings where they touch themselves.
+func (g *graph) link(edges []edge) [][]int {
+	out := make(map[int][]int)
+	for i, e := range edges {
+		out[e.from] = append(out[e.from], i)
+	}
+
+	used := make([]bool, len(edges))
+	next := func(in edge) int {
+		dir := g.nodes[in.to].sub(g.nodes[in.from])
+		best, bestTurn := -1, math.Inf(-1)
+		for _, i := range out[in.to] {
+			if used[i] {
+				continue
+			}
+			d := g.nodes[edges[i].to].sub(g.nodes[edges[i].from])
+			turn := math.Atan2(dir.cross(d), dir.dot(d))
+			if edges[i].to == in.from {
+				// going back is the last resort
+				turn = -2 * math.Pi
+			}
+			if turn > bestTurn {
+				best, bestTurn = i, turn
+			}
+		}
+		return best
+	}
+
+	var rings [][]int
+	for start := range edges {
+		if used[start] {
+			continue
+		}
+
+		path := []int{edges[start].from}
+		pos := map[int]int{edges[start].from: 0}
+		for e := start; e >= 0; e = next(edges[e]) {
+			used[e] = true
+			n := edges[e].to
+
+			k, ok := pos[n]
+			if !ok {
+				pos[n] = len(path)
+				path = append(path, n)
+				continue
+			}
+
+			// back to a node of the path: the loop since is a ring
+			rings = append(rings, append([]int(nil), path[k:]...))
+			for _, m := range path[k+1:] {
+				delete(pos, m)
+			}
+			path = path[:k+1]
+		}
+	}
+	return rings
+}
+
+// polygons returns the polygons of the rings: counterclockwise rings are exteriors, and
+// clockwise rings are holes of the smallest exterior containing them.
+func (g *graph) polygons(rings [][]int) []Polygon {
+	var (
+		exteriors [][]geometry.Point
+		holes     [][]geometry.Point
+	)
+	for _, r := range rings {
+		if len(r) < 3 {
+			continue
+		}
+		pts := make([]geometry.Point, 0, len(r)+1)
+		for _, n := range r {
+			pts = append(pts, g.nodes[n].point())
+		}
+
+		a := area(pts)
+		if math.Abs(a) <= g.eps*g.eps {
+			continue
+		}
+		pts = append(pts, pts[0])
+		if a > 0 {
+			exteriors = append(exteriors, pts)
+		} else {
+			holes = append(holes, pts)
+		}
+	}
+
+	polys := make([]Polygon, len(exteriors))
+	for i, ext := range exteriors {
+		polys[i].Exterior = ext
+	}
+
+	for _, h := range holes {
+		p := fromPoint(h[0]).lerp(fromPoint(h[1]), 0.5)
+		best, bestArea := -1, math.Inf(1)
+		for i, ext := range exteriors {
+			ring := ext[:len(ext)-1]
+			if a := area(ring); a < bestArea && contains([][]geometry.Point{ring}, p) {
+				best, bestArea = i, a
+			}
+		}
+		if best >= 0 {
+			polys[best].Holes = append(polys[best].Holes, h)
+		}
+	}
+
+	return polys
+}
@@ -0,0 +1,99 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package clip
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+func square(minX, minY, maxX, maxY float64) Polygon {
+	return Polygon{Exterior: []geometry.Point{{X: minX, Y: minY}, {X: maxX, Y: minY}, {X: maxX, Y: maxY}, {X: minX, Y: maxY}}}
+}
+
+// polygonArea returns the area of a polygon, minus its holes.
+func polygonArea(p Polygon) float64 {
+	a := math.Abs(area(p.Exterior[:len(p.Exterior)-1]))
+	for _, h := range p.Holes {
+		a -= math.Abs(area(h[:len(h)-1]))
+	}
+	return a
+}
+
+func TestOverlay(t *testing.T) {
+	// a square of 4x4 with a hole of 1x1
+	frame := square(0, 0, 4, 4)
+	frame.Holes = [][]geometry.Point{{{X: 1, Y: 1}, {X: 1, Y: 2}, {X: 2, Y: 2}, {X: 2, Y: 1}, {X: 1, Y: 1}}}
+
+	tests := []struct {
+		name  string
+		fn    func(a, b []Polygon) []Polygon
+		a, b  Polygon
+		areas []float64
+		holes int
+	}{
+		{name: "union of overlapping squares", fn: Union, a: square(0, 0, 2, 2), b: square(1, 1, 3, 3), areas: []float64{7}},
+		{name: "intersection of overlapping squares", fn: Intersection, a: square(0, 0, 2, 2), b: square(1, 1, 3, 3), areas: []float64{1}},
+		{name: "difference of overlapping squares", fn: Difference, a: square(0, 0, 2, 2), b: square(1, 1, 3, 3), areas: []float64{3}},
+		{name: "union of adjacent squares", fn: Union, a: square(0, 0, 1, 1), b: square(1, 0, 2, 1), areas: []float64{2}},
+		{name: "union of partially adjacent squares", fn: Union, a: square(0, 0, 2, 2), b: square(2, 1, 3, 4), areas: []float64{7}},
+		{name: "intersection of adjacent squares", fn: Intersection, a: square(0, 0, 1, 1), b: square(1, 0, 2, 1)},
+		{name: "difference of adjacent squares", fn: Difference, a: square(0, 0, 1, 1), b: square(1, 0, 2, 1), areas: []float64{1}},
+		{name: "union of disjoint squares", fn: Union, a: square(0, 0, 1, 1), b: square(2, 2, 3, 3), areas: []float64{1, 1}},
+		{name: "union of squares touching at a corner", fn: Union, a: square(0, 0, 1, 1), b: square(1, 1, 2, 2), areas: []float64{1, 1}},
+		{name: "difference making a hole", fn: Difference, a: square(0, 0, 4, 4), b: square(1, 1, 2, 2), areas: []float64{15}, holes: 1},
+		{name: "difference splitting in two", fn: Difference, a: square(0, 0, 3, 1), b: square(1, -1, 2, 2), areas: []float64{1, 1}},
+		{name: "union filling a hole", fn: Union, a: frame, b: square(1, 1, 2, 2), areas: []float64{16}},
+		{name: "union covering a hole", fn: Union, a: frame, b: square(0.5, 0.5, 2.5, 2.5), areas: []float64{16}},
+		{name: "intersection with a hole", fn: Intersection, a: frame, b: square(0, 0, 3, 3), areas: []float64{8}, holes: 1},
+		{name: "difference of a hole", fn: Difference, a: square(0, 0, 3, 3), b: frame, areas: []float64{1}},
+		{name: "union of identical squares", fn: Union, a: square(0, 0, 1, 1), b: square(0, 0, 1, 1), areas: []float64{1}},
+		{name: "intersection of identical squares", fn: Intersection, a: square(0, 0, 1, 1), b: square(0, 0, 1, 1), areas: []float64{1}},
+		{name: "difference of identical squares", fn: Difference, a: square(0, 0, 1, 1), b: square(0, 0, 1, 1)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			polys := tc.fn([]Polygon{tc.a}, []Polygon{tc.b})
+			if len(polys) != len(tc.areas) {
+				t.Fatalf("expected %d polygons, got %d: %v", len(tc.areas), len(polys), polys)
+			}
+
+			holes := 0
+			for i, p := range polys {
+				if a := polygonArea(p); math.Abs(a-tc.areas[i]) > 1e-9 {
+					t.Errorf("expected polygon %d area %v, got %v", i, tc.areas[i], a)
+				}
+				if first, last := p.Exterior[0], p.Exterior[len(p.Exterior)-1]; first != last {
+					t.Errorf("expected closed exterior, got %v", p.Exterior)
+				}
+				if area(p.Exterior[:len(p.Exterior)-1]) < 0 {
+					t.Errorf("expected counterclockwise exterior, got %v", p.Exterior)
+				}
+				holes += len(p.Holes)
+			}
+			if holes != tc.holes {
+				t.Errorf("expected %d holes, got %d", tc.holes, holes)
+			}
+		})
+	}
+}
+
+func TestOverlayTolerance(t *testing.T) {
+	// the second square is adjacent to the first one, within the tolerance
+	a := square(0, 0, 1, 1)
+	b := square(1+1e-12, 0, 2, 1)
+
+	if polys := Union([]Polygon{a}, []Polygon{b}); len(polys) != 1 || math.Abs(polygonArea(polys[0])-2) > 1e-9 {
+		t.Errorf("expected adjacent squares merged, got %v", polys)
+	}
+
+	// merging the result again with an overlapping square
+	polys := Union(Union([]Polygon{a}, []Polygon{b}), []Polygon{square(1.5, 0.5, 3, 2)})
+	if len(polys) != 1 || math.Abs(polygonArea(polys[0])-(2+2.25-0.25)) > 1e-9 {
+		t.Errorf("unexpected union %v", polys)
+	}
+}
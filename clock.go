@@ -0,0 +1,15 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import "github.com/wavecomtech/omlox-client-go/clock"
+
+// timeSource returns the clock of the client, or the system clock for APIs
+// used without a client.
+func (c *Client) timeSource() clock.Clock {
+	if c == nil {
+		return clock.System
+	}
+	return clock.Or(c.configuration.Clock)
+}
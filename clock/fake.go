@@ -0,0 +1,238 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake is a clock whose time only moves when advanced, for tests.
+// Timers, tickers and functions scheduled with AfterFunc fire in order
+// as Advance moves the time past them.
+type Fake struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	now    time.Time
+	timers []*fakeTimer
+}
+
+var _ Clock = (*Fake)(nil)
+
+// NewFake returns a fake clock set at the given time.
+func NewFake(now time.Time) *Fake {
+	f := &Fake{now: now}
+	f.cond = sync.NewCond(&f.mu)
+	return f
+}
+
+// Now returns the time of the clock.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+// NewTimer creates a timer firing once the clock is advanced by d.
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	return f.schedule(d, 0, nil)
+}
+
+// NewTicker creates a ticker firing every time the clock is advanced by d.
+// It panics if d is not positive, as time.NewTicker.
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for clock.Fake.NewTicker")
+	}
+	return fakeTicker{f.schedule(d, d, nil)}
+}
+
+// AfterFunc calls fn in its own goroutine once the clock is advanced by d.
+func (f *Fake) AfterFunc(d time.Duration, fn func()) Timer {
+	return f.schedule(d, 0, fn)
+}
+
+// Advance moves the clock forward by d, firing the timers due in order.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	target := f.now.Add(d)
+	f.mu.Unlock()
+
+	f.advanceTo(target)
+}
+
+// Set moves the clock forward to t, firing the timers due in order.
+// Setting a time before the current one only fires the timers already due.
+func (f *Fake) Set(t time.Time) {
+	f.advanceTo(t)
+}
+
+// Waiters returns the number of timers, tickers and scheduled functions pending.
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.timers)
+}
+
+// BlockUntil blocks until at least n timers, tickers or scheduled functions are
+// pending. It synchronizes tests with the goroutines waiting on the clock,
+// before advancing it.
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for len(f.timers) < n {
+		f.cond.Wait()
+	}
+}
+
+func (f *Fake) advanceTo(target time.Time) {
+	for {
+		f.mu.Lock()
+
+		next := f.next(target)
+		if next == nil {
+			if target.After(f.now) {
+				f.now = target
+			}
+			f.mu.Unlock()
+			return
+		}
+
+		if next.when.After(f.now) {
+			f.now = next.when
+		}
+		now := f.now
+
+		if next.period > 0 {
+			next.when = next.when.Add(next.period)
+		} else {
+			f.remove(next)
+		}
+
+		f.mu.Unlock()
+
+		next.fire(now)
+	}
+}
+
+// next returns the earliest timer due at target, or nil.
+// Must be called with the lock held.
+func (f *Fake) next(target time.Time) *fakeTimer {
+	var next *fakeTimer
+	for _, t := range f.timers {
+		if t.when.After(target) {
+			continue
+		}
+		if next == nil || t.when.Before(next.when) {
+			next = t
+		}
+	}
+	return next
+}
+
+func (f *Fake) schedule(d, period time.Duration, fn func()) *fakeTimer {
+	t := &fakeTimer{
+		f:      f,
+		period: period,
+		fn:     fn,
+	}
+	if fn == nil {
+		t.c = make(chan time.Time, 1)
+	}
+
+	f.mu.Lock()
+	t.when = f.now.Add(d)
+	f.add(t)
+	f.mu.Unlock()
+
+	// timers already due fire right away, as in the time package
+	if d <= 0 {
+		f.advanceTo(f.Now())
+	}
+
+	return t
+}
+
+// add registers a pending timer. Must be called with the lock held.
+func (f *Fake) add(t *fakeTimer) {
+	f.timers = append(f.timers, t)
+	f.cond.Broadcast()
+}
+
+// remove unregisters a timer, reporting whether it was pending.
+// Must be called with the lock held.
+func (f *Fake) remove(t *fakeTimer) bool {
+	for i, p := range f.timers {
+		if p == t {
+			f.timers = append(f.timers[:i], f.timers[i+1:]...)
+			f.cond.Broadcast()
+			return true
+		}
+	}
+	return false
+}
+
+// fakeTimer is a timer, ticker or scheduled function of a fake clock.
+type fakeTimer struct {
+	f *Fake
+
+	when   time.Time
+	period time.Duration
+	fn     func()
+	c      chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.f.mu.Lock()
+	defer t.f.mu.Unlock()
+
+	return t.f.remove(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.f.mu.Lock()
+	active := t.f.remove(t)
+	t.when = t.f.now.Add(d)
+	if t.period > 0 {
+		t.period = d
+	}
+	t.f.add(t)
+	t.f.mu.Unlock()
+
+	if d <= 0 {
+		t.f.advanceTo(t.f.Now())
+	}
+
+	return active
+}
+
+// fakeTicker adapts a periodic timer to the Ticker interface.
+type fakeTicker struct {
+	*fakeTimer
+}
+
+func (t fakeTicker) Stop() {
+	t.fakeTimer.Stop()
+}
+
+func (t *fakeTimer) fire(now time.Time) {
+	if t.fn != nil {
+		go t.fn()
+		return
+	}
+
+	// as the time package, ticks are dropped for slow receivers
+	select {
+	case t.c <- now:
+	default:
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+
+// fired reports the time sent on the channel, if any.
+func fired(c <-chan time.Time) (time.Time, bool) {
+	select {
+	case t := <-c:
+		return t, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestFakeTimer(t *testing.T) {
+	clk := NewFake(epoch)
+
+	timer := clk.NewTimer(time.Minute)
+	stopped := clk.NewTimer(time.Minute)
+
+	clk.Advance(30 * time.Second)
+	if _, ok := fired(timer.C()); ok {
+		t.Fatal("timer fired before its time")
+	}
+
+	if !stopped.Stop() {
+		t.Error("expected pending timer to be stopped")
+	}
+
+	clk.Advance(30 * time.Second)
+	if at, ok := fired(timer.C()); !ok || !at.Equal(epoch.Add(time.Minute)) {
+		t.Errorf("expected timer to fire at %v, got %v (fired %v)", epoch.Add(time.Minute), at, ok)
+	}
+	if _, ok := fired(stopped.C()); ok {
+		t.Error("stopped timer fired")
+	}
+
+	// reset timers fire again
+	if timer.Reset(time.Second) {
+		t.Error("expected fired timer to be inactive")
+	}
+	clk.Advance(time.Second)
+	if _, ok := fired(timer.C()); !ok {
+		t.Error("expected reset timer to fire")
+	}
+
+	if clk.Waiters() != 0 {
+		t.Errorf("expected no waiters, got %d", clk.Waiters())
+	}
+}
+
+func TestFakeTicker(t *testing.T) {
+	clk := NewFake(epoch)
+
+	ticker := clk.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for i := 1; i <= 3; i++ {
+		clk.Advance(time.Minute)
+		if at, ok := fired(ticker.C()); !ok || !at.Equal(epoch.Add(time.Duration(i)*time.Minute)) {
+			t.Fatalf("tick %d: got %v (fired %v)", i, at, ok)
+		}
+	}
+
+	// as the time package, ticks are dropped for slow receivers
+	clk.Advance(3 * time.Minute)
+	if at, ok := fired(ticker.C()); !ok || !at.Equal(epoch.Add(4*time.Minute)) {
+		t.Errorf("expected the first pending tick, got %v (fired %v)", at, ok)
+	}
+	if _, ok := fired(ticker.C()); ok {
+		t.Error("expected later ticks to be dropped")
+	}
+}
+
+func TestFakeAfterFunc(t *testing.T) {
+	clk := NewFake(epoch)
+
+	order := make(chan int, 2)
+	clk.AfterFunc(2*time.Second, func() { order <- 2 })
+
+	// functions may be scheduled from other goroutines
+	go clk.AfterFunc(time.Second, func() { order <- 1 })
+	clk.BlockUntil(2)
+
+	clk.Advance(time.Second)
+	if n := <-order; n != 1 {
+		t.Fatalf("expected first function to run first, got %d", n)
+	}
+
+	clk.Advance(time.Second)
+	if n := <-order; n != 2 {
+		t.Fatalf("expected second function to run, got %d", n)
+	}
+
+	if now := clk.Now(); !now.Equal(epoch.Add(2 * time.Second)) {
+		t.Errorf("expected clock at %v, got %v", epoch.Add(2*time.Second), now)
+	}
+}
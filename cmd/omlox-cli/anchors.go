@@ -0,0 +1,25 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+)
+
+func newAnchorsCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "anchors",
+		Short: "Commission UWB anchor infrastructure",
+	}
+
+	cmd.AddCommand(
+		newAnchorsPlaceCmd(settings, out),
+		newAnchorsCoverageCmd(settings, out),
+	)
+
+	return cmd
+}
@@ -0,0 +1,144 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"github.com/tidwall/geojson"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/analytics"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+)
+
+const anchorsCoverageHelp = `
+This command estimates the trilateration quality of planned or installed
+anchors over an area, and writes it as a GeoJSON overlay of grid cells.
+
+The anchors are read from a survey file, in the format of 'anchors place'.
+The area is a GeoJSON Polygon file in local zone coordinates in meters.
+Without area, the rectangle enclosing the anchors is analysed.
+
+Each cell has the number of anchors in range, the horizontal dilution of
+precision (hdop) and a quality rating: excellent (hdop <= 2), good (<= 4),
+moderate (<= 6), poor, or none when seen by fewer than three anchors.
+
+The analysis runs offline, with the overlay in local coordinates. Given a
+zone, the overlay is converted to WGS84 with the ground control points of
+the zone fetched from the Hub.
+`
+
+func newAnchorsCoverageCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	var (
+		file     string
+		areaFile string
+		zone     string
+		maxRange float64
+		maxDOP   float64
+		cellSize float64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "coverage",
+		Short: "Estimates the coverage of anchors as a GeoJSON overlay",
+		Long:  anchorsCoverageHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			anchors, err := readSurvey(file)
+			if err != nil {
+				return err
+			}
+
+			points := anchorPoints(anchors)
+
+			var area *geometry.Poly
+			if areaFile != "" {
+				if area, err = readArea(areaFile); err != nil {
+					return err
+				}
+			} else {
+				area = boundingPoly(points)
+			}
+
+			m, err := analytics.Coverage(points, area, analytics.CoverageOptions{CellSize: cellSize, MaxRange: maxRange})
+			if err != nil {
+				return err
+			}
+
+			var project func(geometry.Point) geometry.Point
+			if zone != "" {
+				zoneID, err := uuid.Parse(zone)
+				if err != nil {
+					return fmt.Errorf("invalid zone id: %w", err)
+				}
+
+				c, err := newOmloxClient(&settings)
+				if err != nil {
+					return err
+				}
+
+				var z omlox.Zone
+				if err := c.Do(context.Background(), http.MethodGet, "/zones/"+zoneID.String(), nil, &z); err != nil {
+					return fmt.Errorf("could not get zone %s: %w", zoneID, err)
+				}
+
+				georef, err := z.Georeference()
+				if err != nil {
+					return err
+				}
+				project = georef.ToWGS84
+			}
+
+			writeCoverageWarnings(cmd.ErrOrStderr(), m, maxRange, maxDOP)
+			fmt.Fprintf(cmd.ErrOrStderr(), "coverage: %.0f%% of %d cells with hdop <= %g\n", 100*m.Covered(maxDOP), len(m.Cells), maxDOP)
+
+			b, err := m.GeoJSON(project)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(out, string(b))
+			return err
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&file, "file", "f", "", "The survey CSV file with the anchor positions in local zone coordinates")
+	f.StringVar(&areaFile, "area", "", "GeoJSON Polygon file of the area to analyse, in local zone coordinates")
+	f.StringVar(&zone, "zone", "", "Zone id to convert the overlay to WGS84")
+	f.Float64Var(&maxRange, "range", 30, "Maximum range of the anchors in meters")
+	f.Float64Var(&maxDOP, "max-dop", 6, "Maximum acceptable horizontal dilution of precision")
+	f.Float64Var(&cellSize, "cell-size", 1, "Size in meters of the grid cells")
+
+	cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+// readArea reads a GeoJSON Polygon file.
+func readArea(name string) (*geometry.Poly, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := geojson.Parse(string(b), geojson.DefaultParseOptions)
+	if err != nil {
+		return nil, fmt.Errorf("invalid area file '%s': %w", name, err)
+	}
+
+	poly, ok := o.(*geojson.Polygon)
+	if !ok {
+		return nil, fmt.Errorf("invalid area file '%s': not a GeoJSON polygon", name)
+	}
+
+	return poly.Base(), nil
+}
@@ -0,0 +1,320 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/analytics"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+)
+
+const anchorsPlaceHelp = `
+This command creates or updates the UWB anchors of a zone from a survey file.
+
+The survey file is a CSV file with a header row. The 'id', 'x' and 'y'
+columns are required, with the anchor positions in local zone coordinates
+in meters. The optional columns are 'name', 'z', 'floor', and the air
+interface parameters 'channel', 'prf', 'preamble_length', 'preamble_code',
+'data_rate' and 'tx_power'.
+
+    id,name,x,y,z,channel
+    00:00:00:00:00:00:a1:01,hall-a1,0,0,4.2,5
+    00:00:00:00:00:00:a1:02,hall-a2,30,0,4.2,5
+
+Local positions are converted to WGS84 using the ground control points of
+the zone. Zones without ground control points keep local positions.
+
+Before registering the anchors, the geometric dilution of precision (HDOP)
+is estimated over the area covered by the anchors. Gaps, seen by fewer than
+three anchors in range, and areas with a poor anchor geometry are reported
+as warnings.
+`
+
+func newAnchorsPlaceCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	var (
+		file       string
+		zone       string
+		maxRange   float64
+		maxDOP     float64
+		resolution float64
+		dryRun     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "place",
+		Short: "Creates or updates anchors from a survey file",
+		Long:  anchorsPlaceHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			zoneID, err := uuid.Parse(zone)
+			if err != nil {
+				return fmt.Errorf("invalid zone id: %w", err)
+			}
+
+			anchors, err := readSurvey(file)
+			if err != nil {
+				return err
+			}
+
+			if err := warnCoverage(cmd.ErrOrStderr(), anchors, maxRange, maxDOP, resolution); err != nil {
+				return err
+			}
+
+			c, err := newOmloxClient(&settings)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+
+			var z omlox.Zone
+			if err := c.Do(ctx, http.MethodGet, "/zones/"+zoneID.String(), nil, &z); err != nil {
+				return fmt.Errorf("could not get zone %s: %w", zoneID, err)
+			}
+
+			georef, err := z.Georeference()
+			if err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "warning: keeping local positions: %v\n", err)
+			}
+
+			progress, err := settings.ProgressReporter(cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			progress.Start("place anchors", len(anchors))
+
+			for _, a := range anchors {
+				a.ZoneID = zoneID.String()
+
+				if georef != nil {
+					p := georef.ToWGS84(a.Position.Base())
+					if z := a.Position.Z(); z != 0 {
+						a.Position = *omlox.NewPointZ(p, z)
+					} else {
+						a.Position = *omlox.NewPoint(p)
+					}
+					a.Crs = omlox.CrsWGS84
+				}
+
+				if dryRun {
+					err := a.Validate()
+					progress.Step(err)
+					if err != nil {
+						return err
+					}
+					fmt.Fprintf(out, "valid: %v %v\n", a.ID, a.Name)
+					continue
+				}
+
+				created, err := c.Anchors.Register(ctx, a)
+				progress.Step(err)
+				if err != nil {
+					return err
+				}
+
+				if created {
+					fmt.Fprintf(out, "created: %v %v\n", a.ID, a.Name)
+				} else {
+					fmt.Fprintf(out, "updated: %v %v\n", a.ID, a.Name)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&file, "file", "f", "", "The survey CSV file with the anchor positions in local zone coordinates")
+	f.StringVar(&zone, "zone", "", "Zone id of the anchors")
+	f.Float64Var(&maxRange, "range", 30, "Maximum range of the anchors in meters, for the coverage validation")
+	f.Float64Var(&maxDOP, "max-dop", 6, "Maximum acceptable horizontal dilution of precision")
+	f.Float64Var(&resolution, "resolution", 1, "Size in meters of the cells of the coverage validation grid")
+	f.BoolVar(&dryRun, "dry-run", false, "Validate the survey without registering the anchors")
+
+	cmd.MarkFlagRequired("file")
+	cmd.MarkFlagRequired("zone")
+
+	return cmd
+}
+
+// parseSurvey parses the anchors of a survey CSV file.
+func parseSurvey(r io.Reader) ([]omlox.Anchor, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("could not read header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"id", "x", "y"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing '%s' column", name)
+		}
+	}
+
+	var anchors []omlox.Anchor
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		var errs []error
+		number := func(name string) float64 {
+			s := field(name)
+			if s == "" {
+				return 0
+			}
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s '%s'", name, s))
+			}
+			return v
+		}
+
+		a := omlox.Anchor{
+			ID:    field("id"),
+			Name:  field("name"),
+			Floor: number("floor"),
+			AirInterface: omlox.AirInterface{
+				Channel:        int(number("channel")),
+				PRF:            int(number("prf")),
+				PreambleLength: int(number("preamble_length")),
+				PreambleCode:   int(number("preamble_code")),
+				DataRate:       int(number("data_rate")),
+			},
+		}
+
+		p := geometry.Point{X: number("x"), Y: number("y")}
+		if field("z") != "" {
+			a.Position = *omlox.NewPointZ(p, number("z"))
+		} else {
+			a.Position = *omlox.NewPoint(p)
+		}
+
+		if field("tx_power") != "" {
+			txPower := number("tx_power")
+			a.AirInterface.TxPower = &txPower
+		}
+
+		if a.ID == "" {
+			errs = append(errs, fmt.Errorf("missing id"))
+		}
+		if err := errors.Join(errs...); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		anchors = append(anchors, a)
+	}
+
+	if len(anchors) == 0 {
+		return nil, fmt.Errorf("no anchors")
+	}
+
+	return anchors, nil
+}
+
+// warnCoverage warns about gaps and poor anchor geometry over the area covered by the anchors.
+func warnCoverage(w io.Writer, anchors []omlox.Anchor, maxRange float64, maxDOP float64, resolution float64) error {
+	points := anchorPoints(anchors)
+
+	m, err := analytics.Coverage(points, boundingPoly(points), analytics.CoverageOptions{CellSize: resolution, MaxRange: maxRange})
+	if err != nil {
+		return err
+	}
+
+	writeCoverageWarnings(w, m, maxRange, maxDOP)
+
+	return nil
+}
+
+// writeCoverageWarnings warns about the gaps and cells with poor anchor geometry of a coverage map.
+func writeCoverageWarnings(w io.Writer, m *analytics.CoverageMap, maxRange float64, maxDOP float64) {
+	var gaps, poor int
+	worst := 0.0
+	for _, c := range m.Cells {
+		switch {
+		case math.IsInf(c.HDOP, 1):
+			gaps++
+		case c.HDOP > maxDOP:
+			poor++
+			worst = math.Max(worst, c.HDOP)
+		}
+	}
+
+	cells := float64(len(m.Cells))
+	if gaps > 0 {
+		fmt.Fprintf(w, "warning: %d of %d cells (%.0f%%) are seen by fewer than 3 anchors within %gm\n", gaps, len(m.Cells), 100*float64(gaps)/cells, maxRange)
+	}
+	if poor > 0 {
+		fmt.Fprintf(w, "warning: %d of %d cells (%.0f%%) have a poor anchor geometry (hdop up to %.1f)\n", poor, len(m.Cells), 100*float64(poor)/cells, worst)
+	}
+}
+
+// boundingPoly returns the rectangle enclosing the points as a polygon.
+func boundingPoly(points []geometry.Point) *geometry.Poly {
+	rect := geometry.Rect{Min: points[0], Max: points[0]}
+	for _, p := range points {
+		rect.Min.X, rect.Min.Y = math.Min(rect.Min.X, p.X), math.Min(rect.Min.Y, p.Y)
+		rect.Max.X, rect.Max.Y = math.Max(rect.Max.X, p.X), math.Max(rect.Max.Y, p.Y)
+	}
+
+	return geometry.NewPoly([]geometry.Point{
+		rect.Min, {X: rect.Max.X, Y: rect.Min.Y}, rect.Max, {X: rect.Min.X, Y: rect.Max.Y}, rect.Min,
+	}, nil, nil)
+}
+
+// anchorPoints returns the positions of the anchors.
+func anchorPoints(anchors []omlox.Anchor) []geometry.Point {
+	points := make([]geometry.Point, len(anchors))
+	for i, a := range anchors {
+		points[i] = a.Position.Base()
+	}
+	return points
+}
+
+// readSurvey reads the anchors of a survey CSV file.
+func readSurvey(name string) ([]omlox.Anchor, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	anchors, err := parseSurvey(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid survey file '%s': %w", name, err)
+	}
+
+	return anchors, nil
+}
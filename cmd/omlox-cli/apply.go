@@ -0,0 +1,241 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/output"
+	"github.com/wavecomtech/omlox-client-go/hubfile"
+	"github.com/wavecomtech/omlox-client-go/signing"
+)
+
+const applyHelp = `
+This command applies a hubfile to the Omlox Hub: a YAML document describing
+the zones, location providers, fences and trackables of the Hub.
+
+Resources of the hubfile are created, or updated when they differ from the
+Hub. With --prune, resources of the Hub missing from the hubfile are deleted,
+among the resources managed by the hubfile: applied resources are marked with
+the owner of the hubfile in their "managed_by" property, so resources created
+by operators, or by hubfiles of other owners, are never pruned.
+
+Zones, fences and trackables without id get an id derived from their name,
+so applying the same hubfile again only applies what changed.
+
+References to variables, as ${name} or ${name:-default}, are substituted with
+the values given with --set or the environment variables, so the same hubfile
+can be applied to staging and production hubs.
+
+The changes are planned first, as shown by the plan command, and only applied
+once confirmed, unless --auto-approve is given.
+
+With --verify-key, or the OMLOX_VERIFY_KEY environment variable, only hubfiles
+signed by the key, with the sign command, are applied: the signature is read
+from the hubfile with the .minisig suffix, unless --signature is given.
+`
+
+// appliedActions are the past tense of the actions, as printed once applied.
+var appliedActions = map[hubfile.Action]string{
+	hubfile.ActionCreate: "created",
+	hubfile.ActionUpdate: "updated",
+	hubfile.ActionDelete: "deleted",
+}
+
+// errApplyCanceled is returned when the plan is not confirmed.
+var errApplyCanceled = errors.New("apply canceled")
+
+// hubfileOptions are the options to plan the changes of a hubfile.
+type hubfileOptions struct {
+	file    string
+	prune   bool
+	noColor bool
+	vars    varsOptions
+
+	verifyKey string
+	signature string
+}
+
+func (o *hubfileOptions) addFlags(f *pflag.FlagSet) {
+	f.StringVarP(&o.file, "file", "f", "", "The hubfile to apply, or - for the standard input")
+	f.BoolVar(&o.prune, "prune", false, "Delete the resources managed by the hubfile owner missing from the hubfile")
+	f.BoolVar(&o.noColor, "no-color", false, "Disable the colors of the plan")
+	o.vars.addFlags(f)
+	f.StringVar(&o.verifyKey, "verify-key", os.Getenv("OMLOX_VERIFY_KEY"), "The public key verifying the signature of the hubfile")
+	f.StringVar(&o.signature, "signature", "", "The signature of the hubfile (default is the hubfile with the .minisig suffix)")
+}
+
+// verify verifies the signature of the hubfile content, when a key is given.
+func (o *hubfileOptions) verify(content []byte) error {
+	if o.verifyKey == "" {
+		return nil
+	}
+
+	name := o.signature
+	if name == "" {
+		if o.file == "-" {
+			return fmt.Errorf("--signature is required to verify a hubfile from the standard input")
+		}
+		name = o.file + signatureSuffix
+	}
+
+	data, err := os.ReadFile(o.verifyKey)
+	if err != nil {
+		return err
+	}
+	key, err := signing.ParsePublicKey(data)
+	if err != nil {
+		return err
+	}
+
+	sig, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	if _, err := signing.Verify(key, content, sig); err != nil {
+		return fmt.Errorf("hubfile %s: %w", o.file, err)
+	}
+
+	return nil
+}
+
+// plan loads the hubfile, verified and with its variables substituted, and plans
+// its changes.
+func (o *hubfileOptions) plan(ctx context.Context, cmd *cobra.Command, settings *cli.EnvSettings) (*omlox.Client, *hubfile.Plan, error) {
+	var (
+		content []byte
+		err     error
+	)
+	if o.file == "-" {
+		content, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		content, err = os.ReadFile(o.file)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := o.verify(content); err != nil {
+		return nil, nil, err
+	}
+
+	variables, err := o.vars.variables()
+	if err != nil {
+		return nil, nil, err
+	}
+	if content, err = variables.Expand(content); err != nil {
+		return nil, nil, fmt.Errorf("hubfile %s: %w", o.file, err)
+	}
+
+	f, err := hubfile.Load(bytes.NewReader(content))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c, err := newOmloxClient(settings)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	plan, err := hubfile.Make(ctx, c, f, hubfile.Options{Prune: o.prune})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return c, plan, nil
+}
+
+func newApplyCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	var (
+		opts        hubfileOptions
+		autoApprove bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Apply a hubfile to the hub",
+		Long:  applyHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.file == "-" && !autoApprove {
+				return fmt.Errorf("--auto-approve is required to apply a hubfile from the standard input")
+			}
+
+			progress, err := settings.ProgressReporter(cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+
+			c, plan, err := opts.plan(ctx, cmd, &settings)
+			if err != nil {
+				return err
+			}
+
+			if plan.Empty() {
+				fmt.Fprintln(out, "hub is up to date")
+				return nil
+			}
+
+			if !autoApprove {
+				pf := &output.PlanFormater{Plan: plan, Color: colorEnabled(out, opts.noColor)}
+				if err := pf.WriteTable(out); err != nil {
+					return err
+				}
+
+				ok, err := confirm(cmd.InOrStdin(), out, "\nApply these changes? Only 'yes' will be accepted: ")
+				if err != nil {
+					return err
+				}
+				if !ok {
+					cmd.SilenceUsage = true
+					return errApplyCanceled
+				}
+			}
+
+			progress.Start("apply", len(plan.Changes))
+
+			err = plan.Apply(ctx, c, func(ch hubfile.Change) {
+				progress.Step(nil)
+				fmt.Fprintf(out, "%s: %s %s %s\n", appliedActions[ch.Action], ch.Kind, ch.ID, ch.Name)
+			})
+			if err != nil {
+				progress.Step(err)
+			}
+			return err
+		},
+	}
+
+	f := cmd.Flags()
+	opts.addFlags(f)
+	f.BoolVar(&autoApprove, "auto-approve", false, "Apply the changes without confirmation")
+
+	cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+// confirm asks a question, reporting whether it was answered with "yes".
+func confirm(in io.Reader, out io.Writer, question string) (bool, error) {
+	fmt.Fprint(out, question)
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+
+	return strings.TrimSpace(answer) == "yes", nil
+}
@@ -0,0 +1,385 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/analytics"
+	"github.com/wavecomtech/omlox-client-go/archive"
+	"github.com/wavecomtech/omlox-client-go/archive/s3"
+)
+
+const archiveUploadHelp = `
+This command uploads the files of an archive directory, such as recorded
+with 'omlox sub --record', to an S3-compatible object storage. The SHA-256
+checksum of each file is verified by the storage, and a manifest listing the
+uploaded files is written under <prefix>/manifests once they are all
+uploaded. Uploaded files are recorded in the directory, so they are not
+uploaded again.
+
+The credentials are read from the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY
+and AWS_SESSION_TOKEN environment variables, and the region from AWS_REGION.
+Other object storages, such as MinIO, are set with --s3-endpoint or
+AWS_ENDPOINT_URL.
+
+    $ omlox archive upload -d /var/lib/omlox/locations --to s3://lake/site-1/locations
+
+Directories being recorded to without compression should not be uploaded, as
+the file being recorded is only complete once rotated.
+`
+
+const archiveCompactHelp = `
+This command compacts the location streams of an archive directory, such as
+recorded with 'omlox sub location_updates --record', to control their storage
+while preserving the shape of the paths. Each track, the locations of a
+provider, keeps a location per --interval or per --distance in meters, and is
+simplified within --tolerance meters, with the Douglas-Peucker algorithm by
+default.
+
+    $ omlox archive compact -d /var/lib/omlox/locations --interval 5s --tolerance 0.5
+
+Files are compacted in place, keeping their compression. Directories being
+recorded to without compression should not be compacted, as the file being
+recorded is only complete once rotated.
+`
+
+// uploadOptions are the options of the uploads to an object storage.
+type uploadOptions struct {
+	to       string
+	endpoint string
+	region   string
+	delete   bool
+}
+
+func (o *uploadOptions) addFlags(f *pflag.FlagSet, name string, usage string) {
+	f.StringVar(&o.to, name, "", usage)
+	f.StringVar(&o.endpoint, "s3-endpoint", os.Getenv("AWS_ENDPOINT_URL"), "URL of the S3-compatible object storage (default AWS S3)")
+	f.StringVar(&o.region, "s3-region", os.Getenv("AWS_REGION"), "Region of the bucket (default us-east-1)")
+	f.BoolVar(&o.delete, "delete-uploaded", false, "Delete the local files once uploaded")
+}
+
+// uploader returns the uploader to the s3://bucket/prefix URL.
+func (o *uploadOptions) uploader() (*archive.Uploader, error) {
+	u, err := url.Parse(o.to)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "s3" || u.Host == "" {
+		return nil, fmt.Errorf("invalid upload destination %q: expected s3://bucket/prefix", o.to)
+	}
+
+	id, secret := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if id == "" || secret == "" {
+		return nil, fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required to upload to %s", o.to)
+	}
+
+	region := o.region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	endpoint := &url.URL{Scheme: "https", Host: "s3." + region + ".amazonaws.com"}
+	if o.endpoint != "" {
+		if endpoint, err = url.Parse(o.endpoint); err != nil {
+			return nil, fmt.Errorf("invalid S3 endpoint %q: %w", o.endpoint, err)
+		}
+	}
+
+	client, err := minio.New(endpoint.Host, &minio.Options{
+		Creds:  credentials.NewStaticV4(id, secret, os.Getenv("AWS_SESSION_TOKEN")),
+		Secure: endpoint.Scheme != "http",
+		Region: region,
+	})
+	if err != nil {
+		return nil, err
+	}
+	store := s3.NewStore(client, u.Host)
+
+	return &archive.Uploader{Store: store, Prefix: strings.Trim(u.Path, "/"), Delete: o.delete}, nil
+}
+
+// recordOptions are the options of the recording of a stream to an archive directory.
+type recordOptions struct {
+	dir         string
+	compress    string
+	rotateSize  int64
+	rotateAge   time.Duration
+	retainAge   time.Duration
+	retainSize  int64
+	retainFiles int
+	upload      uploadOptions
+}
+
+func (o *recordOptions) addFlags(f *pflag.FlagSet) {
+	f.StringVar(&o.dir, "record", "", "Directory to record the events to, instead of writing them")
+	f.StringVar(&o.compress, "compress", archive.Zstd.String(), fmt.Sprintf("Compression of the recorded files. One of: %v.", archive.Compressions()))
+	f.Int64Var(&o.rotateSize, "rotate-size", archive.DefaultMaxSize>>20, "Size in MiB after which the recorded file is rotated, 0 to not rotate by size")
+	f.DurationVar(&o.rotateAge, "rotate-age", 0, "Age after which the recorded file is rotated, 0 to not rotate by age")
+	f.DurationVar(&o.retainAge, "retain-age", 0, "Age after which the recorded files are deleted, 0 to keep them")
+	f.Int64Var(&o.retainSize, "retain-size", 0, "Total size in MiB of the recorded files above which the oldest are deleted, 0 to not limit")
+	f.IntVar(&o.retainFiles, "retain-files", 0, "Number of recorded files above which the oldest are deleted, 0 to not limit")
+	o.upload.addFlags(f, "upload", "Upload the recorded files, once rotated, to s3://bucket/prefix")
+}
+
+// recorder records a stream to an archive directory, uploading the rotated files.
+type recorder struct {
+	*archive.Writer
+
+	dir      string
+	uploader *archive.Uploader
+	uploads  chan string
+	done     chan struct{}
+	errOut   io.Writer
+}
+
+// open opens the recorder of the stream of the given name.
+func (o *recordOptions) open(name string, errOut io.Writer) (*recorder, error) {
+	compression, err := archive.ParseCompression(o.compress)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &recorder{dir: o.dir, errOut: errOut}
+
+	opts := []archive.Option{
+		archive.WithCompression(compression),
+		archive.WithMaxSize(o.rotateSize << 20),
+		archive.WithMaxAge(o.rotateAge),
+		archive.WithRetention(archive.Retention{
+			MaxAge:   o.retainAge,
+			MaxSize:  o.retainSize << 20,
+			MaxFiles: o.retainFiles,
+		}),
+	}
+
+	if o.upload.to != "" {
+		if r.uploader, err = o.upload.uploader(); err != nil {
+			return nil, err
+		}
+
+		r.uploads, r.done = make(chan string, 64), make(chan struct{})
+		go r.upload()
+
+		// files not queued are uploaded when the recording stops
+		opts = append(opts, archive.OnRotate(func(path string) {
+			select {
+			case r.uploads <- path:
+			default:
+			}
+		}))
+	}
+
+	name = strings.ReplaceAll(name, ":", "_") + ".jsonl"
+	if r.Writer, err = archive.NewWriter(o.dir, name, opts...); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// upload uploads the rotated files in the background. Failed uploads are retried
+// when the recording stops.
+func (r *recorder) upload() {
+	defer close(r.done)
+
+	for path := range r.uploads {
+		if _, err := r.uploader.Upload(context.Background(), path); err != nil {
+			fmt.Fprintf(r.errOut, "upload %s: %v\n", path, err)
+		}
+	}
+}
+
+// Close rotates the recorded file and uploads the files not uploaded yet.
+func (r *recorder) Close() error {
+	if err := r.Writer.Close(); err != nil {
+		return err
+	}
+	if r.uploader == nil {
+		return nil
+	}
+
+	close(r.uploads)
+	<-r.done
+
+	pending, err := archive.Pending(r.dir)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	_, err = r.uploader.Upload(ctx, pending...)
+	return err
+}
+
+func newArchiveCmd(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "archive",
+		Short: "Manage archived data",
+	}
+
+	cmd.AddCommand(newArchiveUploadCmd(out))
+	cmd.AddCommand(newArchiveCompactCmd(out))
+
+	return cmd
+}
+
+func newArchiveUploadCmd(out io.Writer) *cobra.Command {
+	var (
+		dir    string
+		upload uploadOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "upload",
+		Short: "Upload archived files to an S3-compatible object storage",
+		Long:  archiveUploadHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			u, err := upload.uploader()
+			if err != nil {
+				return err
+			}
+
+			pending, err := archive.Pending(dir)
+			if err != nil {
+				return err
+			}
+			if len(pending) == 0 {
+				fmt.Fprintln(out, "no files to upload")
+				return nil
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			m, err := u.Upload(ctx, pending...)
+			if err != nil {
+				return err
+			}
+
+			for _, o := range m.Objects {
+				fmt.Fprintf(out, "uploaded: %s (%d bytes, sha256 %s)\n", o.Key, o.Size, o.SHA256)
+			}
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&dir, "dir", "d", "", "Directory of the archived files")
+	upload.addFlags(f, "to", "Destination of the files, as s3://bucket/prefix")
+
+	cmd.MarkFlagRequired("dir")
+	cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+func newArchiveCompactCmd(out io.Writer) *cobra.Command {
+	var (
+		dir            string
+		simplification string
+		opts           analytics.CompactOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "compact",
+		Short: "Downsample archived location streams",
+		Long:  archiveCompactHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts == (analytics.CompactOptions{}) {
+				return fmt.Errorf("at least one of --interval, --distance or --tolerance is required")
+			}
+			if err := opts.Simplification.FromString(simplification); err != nil {
+				return err
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				return err
+			}
+
+			for _, e := range entries {
+				name := e.Name()
+				if !e.Type().IsRegular() || strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".tmp") {
+					continue
+				}
+
+				var before, after int
+				err := archive.Rewrite(filepath.Join(dir, name), func(r io.Reader, w io.Writer) error {
+					locations, err := readLocations(r)
+					if err != nil {
+						return err
+					}
+
+					compacted := analytics.Compact(locations, opts)
+					before, after = len(locations), len(compacted)
+
+					e := json.NewEncoder(w)
+					for _, loc := range compacted {
+						if err := e.Encode(loc); err != nil {
+							return err
+						}
+					}
+					return nil
+				})
+				if err != nil {
+					return fmt.Errorf("compact %s: %w", name, err)
+				}
+
+				fmt.Fprintf(out, "compacted: %s (%d to %d locations)\n", name, before, after)
+			}
+
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&dir, "dir", "d", "", "Directory of the archived files")
+	f.DurationVar(&opts.Interval, "interval", 0, "Keep a location per interval of each track")
+	f.Float64Var(&opts.Distance, "distance", 0, "Keep a location per distance in meters of each track")
+	f.Float64Var(&opts.Tolerance, "tolerance", 0, "Simplify the tracks within the tolerance in meters")
+	f.StringVar(&simplification, "simplification", analytics.DouglasPeucker.String(), fmt.Sprintf("Algorithm simplifying the tracks. One of: %v.", analytics.Simplifications()))
+
+	cmd.MarkFlagRequired("dir")
+
+	return cmd
+}
+
+// readLocations reads a stream of JSON encoded locations.
+func readLocations(r io.Reader) ([]omlox.Location, error) {
+	var locations []omlox.Location
+
+	d := json.NewDecoder(r)
+	for n := 1; ; n++ {
+		var loc omlox.Location
+		if err := d.Decode(&loc); err == io.EOF {
+			return locations, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("location %d: %w", n, err)
+		}
+		if loc.ProviderID == "" {
+			return nil, fmt.Errorf("location %d: not a location: missing provider_id", n)
+		}
+
+		locations = append(locations, loc)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+)
+
+const authCanIHelp = `
+This command reports whether the token authenticating with the Omlox Hub is
+permitted an action on a resource, printing yes or no:
+
+  omlox auth can-i create trackables
+  omlox auth can-i delete fences
+
+The actions are get, create, update and delete, and the resources trackables,
+providers, fences and zones. The permissions are probed without side effects:
+requests are sent with invalid bodies, or for missing resources, so the Hub
+rejects them after checking the permissions.
+`
+
+// canIMethods are the methods of the probed actions.
+var canIMethods = map[string]string{
+	"get":    http.MethodGet,
+	"create": http.MethodPost,
+	"update": http.MethodPut,
+	"delete": http.MethodDelete,
+}
+
+// canIResources are the API paths of the probed resources.
+var canIResources = map[string]string{
+	"trackables": "/trackables",
+	"providers":  "/providers",
+	"fences":     "/fences",
+	"zones":      "/zones",
+}
+
+func newAuthCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Inspect the authorization of the hub token",
+	}
+
+	cmd.AddCommand(newAuthCanICmd(settings, out))
+
+	return cmd
+}
+
+func newAuthCanICmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	return &cobra.Command{
+		Use:   "can-i action resource",
+		Short: "Check whether an action is permitted",
+		Long:  authCanIHelp,
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			method, ok := canIMethods[args[0]]
+			if !ok {
+				return fmt.Errorf("unknown action %q: expected get, create, update or delete", args[0])
+			}
+			path, ok := canIResources[args[1]]
+			if !ok {
+				return fmt.Errorf("unknown resource %q: expected trackables, providers, fences or zones", args[1])
+			}
+
+			// updates and deletions target a missing resource
+			if method == http.MethodPut || method == http.MethodDelete {
+				path += "/" + uuid.NewString()
+			}
+
+			c, err := newOmloxClient(&settings)
+			if err != nil {
+				return err
+			}
+
+			allowed, err := c.Can(context.Background(), method, path)
+			if err != nil {
+				return err
+			}
+
+			if allowed {
+				fmt.Fprintln(out, "yes")
+			} else {
+				fmt.Fprintln(out, "no")
+			}
+			return nil
+		},
+	}
+}
+
+// errorHint returns a hint on how to solve the error, if any.
+func errorHint(err error) string {
+	switch {
+	case errors.Is(err, omlox.ErrUnauthorized):
+		return "the hub rejected the credentials: set a valid token with OMLOX_HUB_TOKEN or 'omlox config set token'"
+	case errors.Is(err, omlox.ErrForbidden):
+		return "the token is not permitted this action: check its permissions with 'omlox auth can-i'"
+	}
+	return ""
+}
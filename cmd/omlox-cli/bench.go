@@ -0,0 +1,320 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/output"
+	"github.com/wavecomtech/omlox-client-go/omloxtest"
+	"golang.org/x/time/rate"
+)
+
+const benchHelp = `
+This command drives synthetic location updates through the client against
+an Omlox Hub and reports the achieved throughput and the latency percentiles.
+
+Virtual location providers are created for the duration of the run and
+deleted afterwards. Each provider moves along a circle in local coordinates.
+
+Location updates are sent through the REST API, where the latency is the
+request round trip, or through the websocket, where the latency is the time
+to write the message to the connection.
+
+With --fake, the load is driven against an in-process fake hub, which is
+useful to measure the overhead of the client itself. Faults can be injected
+on the endpoints of the fake hub with --fault, as <endpoint>:<key>=<value>,...
+where the keys are latency, status, drop, malformed, after, every and times.
+
+Examples:
+
+	omlox-cli bench --locations-per-sec 5000 --duration 60s
+	omlox-cli bench --transport websocket --fake -o json
+	omlox-cli bench --fake --fault "PUT /providers/*/location:status=503,every=10"
+`
+
+// Transports supported by the bench command.
+const (
+	benchTransportREST      = "rest"
+	benchTransportWebsocket = "websocket"
+)
+
+func newBenchCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	var (
+		format      string
+		lps         int
+		duration    time.Duration
+		providers   int
+		concurrency int
+		transport   string
+		fake        bool
+		faults      []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Load test a hub with synthetic location updates",
+		Long:  benchHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o, err := output.ParseFormat(format)
+			if err != nil {
+				return err
+			}
+
+			if lps <= 0 || providers <= 0 || concurrency <= 0 || duration <= 0 {
+				return errors.New("--locations-per-sec, --duration, --providers and --concurrency must be positive")
+			}
+			if transport != benchTransportREST && transport != benchTransportWebsocket {
+				return fmt.Errorf("unsupported transport %q", transport)
+			}
+
+			if len(faults) > 0 && !fake {
+				return errors.New("--fault requires --fake")
+			}
+
+			if fake {
+				srv := omloxtest.NewServer()
+				defer srv.Close()
+
+				for _, s := range faults {
+					endpoint, fault, err := omloxtest.ParseFault(s)
+					if err != nil {
+						return err
+					}
+					if err := srv.InjectFault(endpoint, fault); err != nil {
+						return err
+					}
+				}
+
+				settings.OmloxHubAPI = srv.URL
+			}
+
+			c, err := newOmloxClient(&settings)
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			keepSessionAlive(ctx, c, settings)
+
+			ids, err := createBenchProviders(ctx, c, providers)
+			defer deleteBenchProviders(c, ids)
+			if err != nil {
+				return err
+			}
+
+			if transport == benchTransportWebsocket {
+				if err := c.Connect(ctx); err != nil {
+					return err
+				}
+				defer c.Close()
+			}
+
+			b := bench{
+				client:      c,
+				transport:   transport,
+				providers:   ids,
+				limiter:     rate.NewLimiter(rate.Limit(lps), max(1, lps/100)),
+				concurrency: concurrency,
+			}
+
+			result := b.run(ctx, duration)
+			result.TargetRate = float64(lps)
+
+			return o.Write(out, &output.BenchFormater{Result: result})
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&format, "output", "o", output.Table.String(), fmt.Sprintf("Output format. One of: %v.", output.TabularFormats()))
+	f.IntVar(&lps, "locations-per-sec", 5000, "Target rate of location updates per second")
+	f.DurationVar(&duration, "duration", time.Minute, "Duration of the load test")
+	f.IntVar(&providers, "providers", 100, "Number of virtual location providers to simulate")
+	f.IntVar(&concurrency, "concurrency", 32, "Number of concurrent senders")
+	f.StringVar(&transport, "transport", benchTransportREST, fmt.Sprintf("Transport of the location updates. One of: %v.", []string{benchTransportREST, benchTransportWebsocket}))
+	f.BoolVar(&fake, "fake", false, "Run against an in-process fake hub instead of --addr")
+	f.StringArrayVar(&faults, "fault", []string{}, "Fault to inject on an endpoint of the fake hub (e.g. \"PUT /providers/*/location:latency=5ms\")")
+
+	return cmd
+}
+
+// createBenchProviders creates the virtual location providers of a run.
+// The ids of the providers created are returned even on error, for cleanup.
+func createBenchProviders(ctx context.Context, c *omlox.Client, n int) ([]string, error) {
+	ids := make([]string, 0, n)
+
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("omlox-bench-%04d", i)
+
+		_, err := c.Providers.Create(ctx, omlox.LocationProvider{
+			ID:   id,
+			Type: omlox.LocationProviderTypeVirtual,
+			Name: "omlox-cli bench",
+		})
+		if err != nil {
+			return ids, fmt.Errorf("could not create provider %s: %w", id, err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// deleteBenchProviders deletes the providers of a run, on a best effort basis.
+func deleteBenchProviders(c *omlox.Client, ids []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for _, id := range ids {
+		c.Providers.Delete(ctx, id)
+	}
+}
+
+// bench drives location updates at a limited rate from concurrent senders.
+type bench struct {
+	client      *omlox.Client
+	transport   string
+	providers   []string
+	limiter     *rate.Limiter
+	concurrency int
+
+	// sequence number of the next location update
+	seq atomic.Int64
+}
+
+// run sends location updates until the duration elapses or ctx is canceled.
+func (b *bench) run(ctx context.Context, duration time.Duration) output.BenchResult {
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	var (
+		wg        sync.WaitGroup
+		latencies = make([][]time.Duration, b.concurrency)
+		errs      = make([]int, b.concurrency)
+	)
+
+	start := time.Now()
+
+	for i := 0; i < b.concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			for {
+				// fails once the next token is past the deadline
+				if err := b.limiter.Wait(ctx); err != nil {
+					return
+				}
+
+				loc := b.next()
+
+				t := time.Now()
+				if err := b.send(ctx, loc); err != nil {
+					if ctx.Err() != nil {
+						return
+					}
+					errs[i]++
+					continue
+				}
+				latencies[i] = append(latencies[i], time.Since(t))
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	var all []time.Duration
+	for _, l := range latencies {
+		all = append(all, l...)
+	}
+
+	result := output.BenchResult{
+		Transport:  b.transport,
+		Duration:   elapsed,
+		Sent:       len(all),
+		Throughput: float64(len(all)) / elapsed.Seconds(),
+		Latency:    summarizeLatencies(all),
+	}
+	for _, n := range errs {
+		result.Errors += n
+	}
+
+	return result
+}
+
+// next returns the next synthetic location update. Providers are updated in
+// turns, each moving along its own circle.
+func (b *bench) next() omlox.Location {
+	n := b.seq.Add(1) - 1
+	i := int(n % int64(len(b.providers)))
+	step := float64(n / int64(len(b.providers)))
+
+	phase := 2 * math.Pi * float64(i) / float64(len(b.providers))
+	angle := phase + step*0.05
+
+	now := time.Now().UTC()
+	return omlox.Location{
+		Position:           *omlox.NewPoint(geometry.Point{X: 50 + 40*math.Cos(angle), Y: 50 + 40*math.Sin(angle)}),
+		Source:             "omlox-bench",
+		ProviderType:       omlox.LocationProviderTypeVirtual,
+		ProviderID:         b.providers[i],
+		TimestampGenerated: &now,
+		Crs:                omlox.CrsLocal,
+	}
+}
+
+func (b *bench) send(ctx context.Context, loc omlox.Location) error {
+	if b.transport == benchTransportWebsocket {
+		return b.client.PublishLocations(ctx, loc)
+	}
+	return b.client.Providers.UpdateLocation(ctx, loc, loc.ProviderID)
+}
+
+// summarizeLatencies returns the distribution of the latencies, sorting them in place.
+func summarizeLatencies(latencies []time.Duration) output.LatencySummary {
+	if len(latencies) == 0 {
+		return output.LatencySummary{}
+	}
+
+	slices.Sort(latencies)
+
+	var total time.Duration
+	for _, l := range latencies {
+		total += l
+	}
+
+	return output.LatencySummary{
+		Min:  latencies[0],
+		Mean: total / time.Duration(len(latencies)),
+		P50:  percentile(latencies, 0.5),
+		P90:  percentile(latencies, 0.9),
+		P99:  percentile(latencies, 0.99),
+		Max:  latencies[len(latencies)-1],
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted values.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(0, min(rank, len(sorted)-1))]
+}
@@ -0,0 +1,210 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/config"
+)
+
+const configHelp = `
+This command manages the configuration file of the CLI, holding the hub API
+endpoint and the token authenticating with it. The file is given by the
+OMLOX_CONFIG environment variable, by default config.yaml in the omlox
+directory of the user configuration directory.
+
+The settings of the configuration file apply unless given by the environment
+variables or the flags.
+`
+
+const configSetHelp = `
+This command sets a setting of the configuration file:
+
+  addr           the hub API endpoint
+  token          the bearer token authenticating with the hub, read from the
+                 standard input unless given, so it is kept off the shell history
+  token_url      the OAuth2 token endpoint issuing tokens to the client
+                 credentials, refreshed before they expire, instead of the token
+  client_id      the client id of the token endpoint
+  client_secret  the client secret of the token endpoint, read from the standard
+                 input unless given
+
+The token and the client secret are encrypted when the OMLOX_CONFIG_PASSPHRASE
+environment variable is set.
+`
+
+const configEncryptHelp = `
+This command encrypts the plaintext secrets of the configuration file with the
+passphrase of the OMLOX_CONFIG_PASSPHRASE environment variable, so the file
+doesn't hold plaintext credentials on shared hosts.
+
+Secrets are encrypted with age (https://age-encryption.org), with a key derived
+from the passphrase by scrypt, and stored as armored age files. They are
+decrypted when needed with the passphrase of the OMLOX_CONFIG_PASSPHRASE
+environment variable, and can also be decrypted with 'age -d'.
+`
+
+func newConfigCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the configuration file",
+		Long:  configHelp,
+	}
+
+	cmd.AddCommand(newConfigViewCmd(settings, out))
+	cmd.AddCommand(newConfigSetCmd(settings, out))
+	cmd.AddCommand(newConfigEncryptCmd(settings, out))
+
+	return cmd
+}
+
+func newConfigViewCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	return &cobra.Command{
+		Use:   "view",
+		Short: "Show the configuration, with its secrets redacted",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := config.Load(settings.ConfigFile)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(out, "file:          %s\n", settings.ConfigFile)
+			fmt.Fprintf(out, "addr:          %s\n", c.Addr)
+			fmt.Fprintf(out, "token:         %s\n", c.Token)
+			fmt.Fprintf(out, "token_url:     %s\n", c.TokenURL)
+			fmt.Fprintf(out, "client_id:     %s\n", c.ClientID)
+			fmt.Fprintf(out, "client_secret: %s\n", c.ClientSecret)
+			return nil
+		},
+	}
+}
+
+func newConfigSetCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	return &cobra.Command{
+		Use:       "set key [value]",
+		Short:     "Set a setting of the configuration",
+		Long:      configSetHelp,
+		Args:      cobra.RangeArgs(1, 2),
+		ValidArgs: []string{"addr", "token", "token_url", "client_id", "client_secret"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := config.Load(settings.ConfigFile)
+			if err != nil {
+				return err
+			}
+
+			switch args[0] {
+			case "addr":
+				if c.Addr, err = argValue(args); err != nil {
+					return err
+				}
+			case "token_url":
+				if c.TokenURL, err = argValue(args); err != nil {
+					return err
+				}
+			case "client_id":
+				if c.ClientID, err = argValue(args); err != nil {
+					return err
+				}
+			case "token":
+				if c.Token, err = readSecret(cmd.InOrStdin(), args); err != nil {
+					return err
+				}
+			case "client_secret":
+				if c.ClientSecret, err = readSecret(cmd.InOrStdin(), args); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("unknown setting %q: expected addr, token, token_url, client_id or client_secret", args[0])
+			}
+
+			if err := c.Save(settings.ConfigFile); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(out, "set: %s\n", args[0])
+			return nil
+		},
+	}
+}
+
+func newConfigEncryptCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	return &cobra.Command{
+		Use:   "encrypt",
+		Short: "Encrypt the plaintext secrets of the configuration",
+		Long:  configEncryptHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			passphrase := os.Getenv(config.PassphraseEnv)
+			if passphrase == "" {
+				return fmt.Errorf("%s is required to encrypt the secrets", config.PassphraseEnv)
+			}
+
+			c, err := config.Load(settings.ConfigFile)
+			if err != nil {
+				return err
+			}
+
+			n, err := c.Encrypt(passphrase)
+			if err != nil {
+				return err
+			}
+			if n > 0 {
+				if err := c.Save(settings.ConfigFile); err != nil {
+					return err
+				}
+			}
+
+			fmt.Fprintf(out, "encrypted: %d secrets\n", n)
+			return nil
+		},
+	}
+}
+
+// argValue returns the value of the arguments.
+func argValue(args []string) (string, error) {
+	if len(args) != 2 {
+		return "", fmt.Errorf("missing %s value", args[0])
+	}
+	return args[1], nil
+}
+
+// readSecret returns the secret value of the arguments, or the first line of the
+// input, encrypted if the passphrase of the configuration is set.
+func readSecret(in io.Reader, args []string) (config.Secret, error) {
+	value, err := readValue(in, args)
+	if err != nil {
+		return "", err
+	}
+
+	if passphrase := os.Getenv(config.PassphraseEnv); passphrase != "" {
+		return config.Encrypt(value, passphrase)
+	}
+	return config.Secret(value), nil
+}
+
+// readValue returns the value of the arguments, or the first line of the input.
+func readValue(in io.Reader, args []string) (string, error) {
+	if len(args) == 2 {
+		return args[1], nil
+	}
+
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	value := strings.TrimSpace(line)
+	if value == "" {
+		return "", fmt.Errorf("missing %s value", args[0])
+	}
+	return value, nil
+}
@@ -4,12 +4,53 @@
 package main
 
 import (
+	"fmt"
 	"io"
 
+	"github.com/google/uuid"
 	"github.com/spf13/cobra"
-	"github.com/wavecomtech/omlox-client-go/internal/cli"
+	"github.com/spf13/pflag"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
 )
 
+// idOptions configure the generation of ids for the created resources.
+type idOptions struct {
+	strategy  string
+	namespace string
+}
+
+func (o *idOptions) addFlags(f *pflag.FlagSet) {
+	f.StringVar(&o.strategy, "id-strategy", "none", "Generate ids for resources without one: none (assigned by the Hub), v4 (random), v7 (time-ordered) or v5 (from the name)")
+	f.StringVar(&o.namespace, "id-namespace", "", "The UUID namespace of v5 ids (default is the client namespace)")
+}
+
+// clientOptions returns the client options generating the ids.
+func (o *idOptions) clientOptions() ([]omlox.ClientOption, error) {
+	namespace := uuid.Nil
+	if o.namespace != "" {
+		var err error
+		if namespace, err = uuid.Parse(o.namespace); err != nil {
+			return nil, fmt.Errorf("invalid id namespace: %w", err)
+		}
+	}
+
+	strategy, err := omlox.ParseIDStrategy(o.strategy, namespace, nil)
+	if err != nil || strategy == nil {
+		return nil, err
+	}
+
+	return []omlox.ClientOption{omlox.WithIDStrategy(strategy)}, nil
+}
+
+// createdSuffix marks the output of resources with generated ids.
+func createdSuffix(generated bool) string {
+	if generated {
+		return " (generated id)"
+	}
+	return ""
+}
+
 func newCreateCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "create",
@@ -17,8 +58,15 @@ func newCreateCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
 		Short:   "Create hub resources",
 	}
 
-	cmd.AddCommand(newCreateTrackablesCmd(settings, out))
-	cmd.AddCommand(newCreateProvidersCmd(settings, out))
+	var (
+		ids idOptions
+		res resourceOptions
+	)
+	ids.addFlags(cmd.PersistentFlags())
+	res.addFlags(cmd.PersistentFlags())
+
+	cmd.AddCommand(newCreateTrackablesCmd(settings, &ids, &res, out))
+	cmd.AddCommand(newCreateProvidersCmd(settings, &ids, &res, out))
 
 	return cmd
 }
@@ -7,19 +7,19 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"os"
 
+	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 	"github.com/wavecomtech/omlox-client-go"
-	"github.com/wavecomtech/omlox-client-go/internal/cli"
-	"github.com/wavecomtech/omlox-client-go/internal/cli/resource"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/resource"
 )
 
 const createTrackableHelp = `
 This command creates trackables in the Omlox Hub.
 `
 
-func newCreateTrackablesCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+func newCreateTrackablesCmd(settings cli.EnvSettings, ids *idOptions, res *resourceOptions, out io.Writer) *cobra.Command {
 	var files []string
 
 	cmd := &cobra.Command{
@@ -28,43 +28,39 @@ func newCreateTrackablesCmd(settings cli.EnvSettings, out io.Writer) *cobra.Comm
 		Long:  createTrackableHelp,
 		Args:  cobra.ExactArgs(0),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var in []io.Reader
-
-			if len(files) > 0 {
-				for _, name := range files {
-					f, err := os.OpenFile(name, os.O_RDONLY, 0444)
-					if err != nil {
-						return err
-					}
-					defer f.Close()
-
-					in = append(in, f)
-				}
-			} else {
-				in = append(in, cmd.InOrStdin())
-			}
-
 			loader := resource.Loader[omlox.Trackable]{
 				Resources: make([]omlox.Trackable, 0),
 			}
-			for _, r := range in {
-				if err := loader.LoadJSON(r); err != nil {
-					return err
-				}
+			if err := loadResources(cmd, files, res, &loader); err != nil {
+				return err
 			}
 
-			c, err := newOmloxClient(&settings)
+			opts, err := ids.clientOptions()
 			if err != nil {
 				return err
 			}
 
+			c, err := newOmloxClient(&settings, opts...)
+			if err != nil {
+				return err
+			}
+
+			progress, err := settings.ProgressReporter(cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			progress.Start("create trackables", len(loader.Resources))
+
 			for _, t := range loader.Resources {
+				generated := len(opts) > 0 && t.ID == uuid.Nil
+
 				rt, err := c.Trackables.Create(context.Background(), t)
+				progress.Step(err)
 				if err != nil {
 					return err
 				}
 
-				fmt.Fprintf(out, "created: %v %v\n", rt.ID, rt.Name)
+				fmt.Fprintf(out, "created: %v %v%s\n", rt.ID, rt.Name, createdSuffix(generated))
 			}
 
 			return nil
@@ -72,7 +68,7 @@ func newCreateTrackablesCmd(settings cli.EnvSettings, out io.Writer) *cobra.Comm
 	}
 
 	f := cmd.Flags()
-	f.StringArrayVarP(&files, "file", "f", []string{}, "The files that contain the trackables to create")
+	f.StringArrayVarP(&files, "file", "f", []string{}, "The files, or directories of json files, that contain the trackables to create")
 
 	return cmd
 }
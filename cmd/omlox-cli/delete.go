@@ -7,7 +7,7 @@ import (
 	"io"
 
 	"github.com/spf13/cobra"
-	"github.com/wavecomtech/omlox-client-go/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
 )
 
 func newDeleteCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
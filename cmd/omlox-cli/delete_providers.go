@@ -9,7 +9,7 @@ import (
 	"io"
 
 	"github.com/wavecomtech/omlox-client-go"
-	"github.com/wavecomtech/omlox-client-go/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
 
 	"github.com/spf13/cobra"
 )
@@ -53,8 +53,15 @@ func newDeleteProvidersCmd(settings cli.EnvSettings, out io.Writer) *cobra.Comma
 				return c.Providers.DeleteAll(context.Background())
 			}
 
+			progress, err := settings.ProgressReporter(cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			progress.Start("delete providers", len(args))
+
 			for _, arg := range args {
 				err := c.Providers.Delete(context.Background(), arg)
+				progress.Step(err)
 				if err != nil {
 					return err
 				}
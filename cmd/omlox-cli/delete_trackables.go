@@ -8,7 +8,7 @@ import (
 	"io"
 
 	"github.com/wavecomtech/omlox-client-go"
-	"github.com/wavecomtech/omlox-client-go/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
 
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
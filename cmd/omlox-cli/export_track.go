@@ -0,0 +1,181 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/aisle"
+	"github.com/wavecomtech/omlox-client-go/analytics"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/export"
+)
+
+const exportTrackHelp = `
+This command exports the trajectory of trackables from the Omlox Hub location history.
+The Hub must support the optional location history API.
+
+Tracks can be exported as GPX, KML (e.g. for Google Earth) or GeoJSON.
+GeoJSON exports include the uncertainty ellipses of the locations, if known.
+Long tracks can be simplified within a tolerance in meters with --simplify,
+so they render quickly. Tracks in warehouses can be snapped to the aisles and
+corridors of a GeoJSON file of LineStrings with --aisles, for cleaner paths.
+When a directory is given, one file per trackable is written to it.
+Otherwise, all tracks are written to the standard output.
+`
+
+// trackWriters maps the supported track formats to their writers.
+var trackWriters = map[string]func(w io.Writer, tracks ...export.Track) error{
+	"gpx":     export.WriteGPX,
+	"kml":     export.WriteKML,
+	"geojson": export.WriteGeoJSON,
+}
+
+func newExportTrackCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	var (
+		format string
+		from   string
+		to     string
+		dir    string
+
+		tolerance      float64
+		simplification string
+		aisles         string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "track <trackable-id>...",
+		Short: "Exports trackables trajectories",
+		Long:  exportTrackHelp,
+		Args:  cobra.MinimumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return compListTrackables(toComplete, args, settings)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			write, ok := trackWriters[format]
+			if !ok {
+				return fmt.Errorf("unsupported track format '%s'", format)
+			}
+
+			var method analytics.Simplification
+			if err := method.FromString(simplification); err != nil {
+				return err
+			}
+
+			var matcher *aisle.Matcher
+			if aisles != "" {
+				data, err := os.ReadFile(aisles)
+				if err != nil {
+					return err
+				}
+				g, err := aisle.FromGeoJSON(data)
+				if err != nil {
+					return fmt.Errorf("aisles %s: %w", aisles, err)
+				}
+				matcher = aisle.NewMatcher(g)
+			}
+
+			locale, err := settings.Locale()
+			if err != nil {
+				return err
+			}
+
+			begin, end, err := parseTimeRange(from, to, locale.Location)
+			if err != nil {
+				return err
+			}
+
+			c, err := newOmloxClient(&settings)
+			if err != nil {
+				return err
+			}
+
+			progress, err := settings.ProgressReporter(cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			progress.Start("export tracks", len(args))
+
+			ctx := context.Background()
+			tracks := make([]export.Track, 0, len(args))
+
+			for _, arg := range args {
+				id, err := uuid.Parse(arg)
+				if err != nil {
+					return err
+				}
+
+				locations, err := c.History.TrackableLocations(ctx, id, begin, end)
+				progress.Step(err)
+				if err != nil {
+					return err
+				}
+
+				if matcher != nil {
+					locations = matcher.Snap(locations)
+				}
+
+				tracks = append(tracks, export.Track{
+					Name:           trackableName(ctx, c, id),
+					Locations:      locations,
+					Tolerance:      tolerance,
+					Simplification: method,
+				})
+			}
+
+			if dir == "" {
+				return write(out, tracks...)
+			}
+
+			for i, t := range tracks {
+				name := filepath.Join(dir, args[i]+"."+format)
+
+				f, err := os.Create(name)
+				if err != nil {
+					return err
+				}
+
+				if err := write(f, t); err != nil {
+					f.Close()
+					return err
+				}
+
+				if err := f.Close(); err != nil {
+					return err
+				}
+
+				fmt.Fprintf(out, "exported: %v\n", name)
+			}
+
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&format, "format", "gpx", "Track format. One of: [gpx kml geojson].")
+	f.StringVar(&from, "from", "", "Start of the time interval in RFC3339 format, or local to --timezone (default 24h before --to)")
+	f.StringVar(&to, "to", "", "End of the time interval in RFC3339 format, or local to --timezone (default now)")
+	f.StringVarP(&dir, "dir", "d", "", "Directory to write one file per trackable")
+	f.StringVar(&aisles, "aisles", "", "GeoJSON file of the aisles, as LineStrings, to snap the tracks to")
+	f.Float64Var(&tolerance, "simplify", 0, "Simplify the tracks within the tolerance in meters, 0 to export all the locations")
+	f.StringVar(&simplification, "simplification", analytics.DouglasPeucker.String(), fmt.Sprintf("Algorithm simplifying the tracks. One of: %v.", analytics.Simplifications()))
+
+	return cmd
+}
+
+// trackableName returns the trackable name, falling back to its ID.
+func trackableName(ctx context.Context, c *omlox.Client, id uuid.UUID) string {
+	t, err := c.Trackables.Get(ctx, id)
+	if err != nil || t == nil || t.Name == "" {
+		return id.String()
+	}
+	return t.Name
+}
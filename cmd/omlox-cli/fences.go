@@ -0,0 +1,116 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/output"
+)
+
+func newFencesCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fences",
+		Short: "Edit and generate the geometry of fences",
+	}
+
+	cmd.AddCommand(
+		newFencesMergeCmd(settings, out),
+		newFencesSubtractCmd(settings, out),
+		newFencesTileCmd(settings, out),
+	)
+
+	return cmd
+}
+
+// fenceEditOptions are the options of the commands editing fences of the Hub.
+type fenceEditOptions struct {
+	dryRun bool
+	format string
+}
+
+func (o *fenceEditOptions) addFlags(f *pflag.FlagSet) {
+	f.BoolVar(&o.dryRun, "dry-run", false, "Print the resulting fences without changing the Hub")
+	f.StringVarP(&o.format, "output", "o", output.JSON.String(), fmt.Sprintf("Output format of --dry-run. One of: %v.", output.Formats()))
+}
+
+// selectFences returns the fences of the Hub selected by id or name, in order. Each fence
+// may only be selected once.
+func selectFences(ctx context.Context, c *omlox.Client, selection []string) ([]omlox.Fence, error) {
+	fences, err := c.Fences.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make([]omlox.Fence, 0, len(selection))
+	seen := make(map[uuid.UUID]bool, len(selection))
+	for _, s := range selection {
+		var matches []omlox.Fence
+		for _, f := range fences {
+			if f.ID.String() == s || f.Name == s {
+				matches = append(matches, f)
+			}
+		}
+
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Errorf("fence %q not found", s)
+		case 1:
+			if seen[matches[0].ID] {
+				return nil, fmt.Errorf("fence %q is given more than once", s)
+			}
+			seen[matches[0].ID] = true
+			selected = append(selected, matches[0])
+		default:
+			return nil, fmt.Errorf("fence name %q is ambiguous: use the fence id", s)
+		}
+	}
+
+	return selected, nil
+}
+
+// applyFences writes the edited fences to the Hub: the first fence updates the fence of
+// its id, other fences without id are created, and the removed fences are deleted. With
+// --dry-run, the fences are printed instead.
+func (o *fenceEditOptions) apply(ctx context.Context, c *omlox.Client, out io.Writer, fences []omlox.Fence, removed []omlox.Fence) error {
+	if o.dryRun {
+		format, err := output.ParseFormat(o.format)
+		if err != nil {
+			return err
+		}
+		return format.Write(out, &output.FenceFormater{Fences: fences})
+	}
+
+	for _, f := range fences {
+		if f.ID == uuid.Nil {
+			created, err := c.Fences.Create(ctx, f)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "created: %v %v\n", created.ID, created.Name)
+			continue
+		}
+
+		if err := c.Fences.Update(ctx, f, f.ID); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "updated: %v %v\n", f.ID, f.Name)
+	}
+
+	for _, f := range removed {
+		if err := c.Fences.Delete(ctx, f.ID); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "deleted: %v %v\n", f.ID, f.Name)
+	}
+
+	return nil
+}
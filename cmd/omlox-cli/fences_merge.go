@@ -0,0 +1,60 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+)
+
+const fencesMergeHelp = `
+This command merges fences of the Omlox Hub, given by id or name, into the
+first one: its region is updated to the union of the regions of the fences,
+and the other fences are deleted.
+
+The fences must have the same crs and zone. Point fences are merged as the
+circles of their radius. When the fences don't overlap nor touch, the union
+has several polygons: the first one updates the first fence, and the others
+are created as new fences with its properties.
+
+With --dry-run, the resulting fences are printed without changing the Hub.
+`
+
+func newFencesMergeCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	var opts fenceEditOptions
+
+	cmd := &cobra.Command{
+		Use:   "merge FENCE FENCE...",
+		Short: "Merges fences into the first one",
+		Long:  fencesMergeHelp,
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newOmloxClient(&settings)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+
+			fences, err := selectFences(ctx, c, args)
+			if err != nil {
+				return err
+			}
+
+			merged, err := fences[0].Union(fences[1:]...)
+			if err != nil {
+				return err
+			}
+
+			return opts.apply(ctx, c, out, merged, fences[1:])
+		},
+	}
+
+	opts.addFlags(cmd.Flags())
+
+	return cmd
+}
@@ -0,0 +1,65 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+)
+
+const fencesSubtractHelp = `
+This command subtracts fences of the Omlox Hub from the first one, given by
+id or name: the region of the first fence is updated to the area outside the
+other fences, which are left unchanged. This removes the overlap of a fence
+with its neighbours, so a location is within a single fence.
+
+The fences must have the same crs and zone. Point fences are subtracted as
+the circles of their radius. When the remaining area is split in several
+polygons, the first one updates the first fence, and the others are created
+as new fences with its properties.
+
+With --dry-run, the resulting fences are printed without changing the Hub.
+`
+
+func newFencesSubtractCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	var opts fenceEditOptions
+
+	cmd := &cobra.Command{
+		Use:   "subtract FENCE FENCE...",
+		Short: "Subtracts fences from the first one",
+		Long:  fencesSubtractHelp,
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newOmloxClient(&settings)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+
+			fences, err := selectFences(ctx, c, args)
+			if err != nil {
+				return err
+			}
+
+			remaining, err := fences[0].Difference(fences[1:]...)
+			if err != nil {
+				return err
+			}
+			if len(remaining) == 0 {
+				return fmt.Errorf("fence %v would be empty: delete it instead", fences[0].ID)
+			}
+
+			return opts.apply(ctx, c, out, remaining, nil)
+		},
+	}
+
+	opts.addFlags(cmd.Flags())
+
+	return cmd
+}
@@ -0,0 +1,123 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+)
+
+const fencesTileHelp = `
+This command creates fences tiling an area in a grid or honeycomb of cells,
+such as to fence every storage bay of a warehouse.
+
+The area is a fence of the Hub, given by id or name, such as the outline of
+a storage hall, or a rectangle of local zone coordinates in meters, given
+with --rect and --zone. The area fence itself is left unchanged.
+
+Cells start at the minimum coordinates of the area, with rows going north
+(or along the y axis) and columns going east (or along the x axis). Cells
+along the edges are clipped to the area, and skipped when less than
+--min-coverage of the cell is within the area.
+
+The fences are named after the --names template:
+
+  {row} {col}  the 1-based row and column of the cell
+  {ROW} {COL}  the row and column as letters: A, B, ... Z, AA, ...
+  {n}          the number of the fence
+
+Numbers are padded with zeros to the width given after a colon: the default
+template "{ROW}-{col:02}" names the cells A-01, A-02, ... B-01, ...
+
+With --dry-run, the fences are printed without creating them.
+`
+
+func newFencesTileCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	var (
+		opts        fenceEditOptions
+		tiling      string
+		width       float64
+		height      float64
+		names       string
+		minCoverage float64
+		rect        []float64
+		zone        string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "tile [AREA]",
+		Short: "Creates fences tiling an area",
+		Long:  fencesTileHelp,
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var t omlox.Tiling
+			if err := t.FromString(tiling); err != nil {
+				return err
+			}
+			if height == 0 {
+				height = width
+			}
+
+			c, err := newOmloxClient(&settings)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+
+			var area omlox.Fence
+			switch {
+			case len(rect) > 0 && len(args) > 0:
+				return fmt.Errorf("either an area fence or --rect is required, not both")
+			case len(rect) > 0:
+				if len(rect) != 4 || zone == "" {
+					return fmt.Errorf("--rect requires the minimum and maximum coordinates, as x1,y1,x2,y2, and --zone")
+				}
+				f, err := omlox.NewRectangleFence(geometry.Point{X: rect[0], Y: rect[1]}, geometry.Point{X: rect[2], Y: rect[3]}, omlox.CrsLocal)
+				if err != nil {
+					return err
+				}
+				area, area.ZoneID = *f, zone
+			case len(args) > 0:
+				fences, err := selectFences(ctx, c, args)
+				if err != nil {
+					return err
+				}
+				area = fences[0]
+			default:
+				return fmt.Errorf("an area fence or --rect is required")
+			}
+
+			fences, err := omlox.TileFence(area, t, width, height, omlox.WithTileNames(names), omlox.WithMinCoverage(minCoverage))
+			if err != nil {
+				return err
+			}
+			if len(fences) == 0 {
+				return fmt.Errorf("no cells within the area: reduce the cell size or --min-coverage")
+			}
+
+			return opts.apply(ctx, c, out, fences, nil)
+		},
+	}
+
+	f := cmd.Flags()
+	opts.addFlags(f)
+	f.StringVar(&tiling, "tiling", omlox.TilingGrid.String(), fmt.Sprintf("Shape of the cells. One of: %v.", omlox.Tilings()))
+	f.Float64Var(&width, "width", 0, "Width of the cells in meters")
+	f.Float64Var(&height, "height", 0, "Height of the cells in meters (default is the width)")
+	f.StringVar(&names, "names", omlox.DefaultTileNames, "Naming template of the fences")
+	f.Float64Var(&minCoverage, "min-coverage", omlox.DefaultMinCoverage, "Fraction of a cell within the area under which the cell is skipped")
+	f.Float64SliceVar(&rect, "rect", nil, "Rectangle of local zone coordinates to tile instead of an area fence, as x1,y1,x2,y2")
+	f.StringVar(&zone, "zone", "", "Zone id of the --rect coordinates")
+
+	cmd.MarkFlagRequired("width")
+
+	return cmd
+}
@@ -0,0 +1,250 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/analytics"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/output"
+)
+
+const fsckHelp = `
+This command checks the consistency of the resources of the Omlox Hub and
+reports the inconsistencies found:
+
+  dangling-providers  trackables referencing location providers which do not exist
+  fence-zones         fences outside any zone: local fences of unknown zones, or
+                      WGS84 fences away from the position and radius of every zone
+  unused-providers    location providers not used by any trackable (anchors excluded)
+
+The --fix flag cleans up the issues of the given checks:
+
+  dangling-providers  removes the references from the trackables
+  unused-providers    deletes the providers
+
+Fences outside any zone are only reported, as they can't be fixed automatically.
+`
+
+// fsck checks, in reporting order.
+const (
+	fsckDanglingProviders = "dangling-providers"
+	fsckFenceZones        = "fence-zones"
+	fsckUnusedProviders   = "unused-providers"
+)
+
+// fsckFixable are the checks with an automatic fix.
+var fsckFixable = []string{fsckDanglingProviders, fsckUnusedProviders}
+
+func newFsckCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	var (
+		format string
+		fix    []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "fsck",
+		Short: "Check the consistency of the hub resources",
+		Long:  fsckHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o, err := output.ParseFormat(format)
+			if err != nil {
+				return err
+			}
+
+			for _, check := range fix {
+				if !slices.Contains(fsckFixable, check) {
+					return fmt.Errorf("invalid fix %q: expected one of %v", check, fsckFixable)
+				}
+			}
+
+			c, err := newOmloxClient(&settings)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+
+			state, err := loadFsckState(ctx, c)
+			if err != nil {
+				return err
+			}
+
+			issues := state.check()
+
+			for i := range issues {
+				if !slices.Contains(fix, issues[i].Check) {
+					continue
+				}
+				if err := state.fix(ctx, c, issues[i]); err != nil {
+					return fmt.Errorf("fix %s %s: %w", issues[i].Kind, issues[i].ID, err)
+				}
+				issues[i].Fixed = true
+			}
+
+			return o.Write(out, &output.FsckFormater{Issues: issues})
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&format, "output", "o", output.Table.String(), fmt.Sprintf("Output format. One of: %v.", output.TabularFormats()))
+	f.StringSliceVar(&fix, "fix", nil, fmt.Sprintf("Clean up the issues of the given checks. Any of: %v.", fsckFixable))
+
+	return cmd
+}
+
+// fsckState are the resources of the Hub being checked.
+type fsckState struct {
+	*hubResources
+
+	// ids of the existing providers
+	providerIDs map[string]bool
+}
+
+func loadFsckState(ctx context.Context, c *omlox.Client) (*fsckState, error) {
+	res, err := loadHubResources(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &fsckState{
+		hubResources: res,
+		providerIDs:  make(map[string]bool, len(res.providers)),
+	}
+	for _, p := range s.providers {
+		s.providerIDs[p.ID] = true
+	}
+
+	return s, nil
+}
+
+// check returns the inconsistencies of the resources.
+func (s *fsckState) check() []output.FsckIssue {
+	var issues []output.FsckIssue
+
+	for _, t := range s.trackables {
+		var missing []string
+		for _, id := range t.LocationProviders {
+			if !s.providerIDs[id] {
+				missing = append(missing, id)
+			}
+		}
+		if len(missing) > 0 {
+			issues = append(issues, output.FsckIssue{
+				Check:  fsckDanglingProviders,
+				Kind:   "trackable",
+				ID:     t.ID.String(),
+				Name:   t.Name,
+				Detail: "unknown providers " + strings.Join(missing, ", "),
+			})
+		}
+	}
+
+	zones := make(map[string]bool, len(s.zones))
+	for _, z := range s.zones {
+		zones[z.ID.String()] = true
+	}
+
+	for _, f := range s.fences {
+		if detail := s.fenceOutsideZones(f, zones); detail != "" {
+			issues = append(issues, output.FsckIssue{
+				Check:  fsckFenceZones,
+				Kind:   "fence",
+				ID:     f.ID.String(),
+				Name:   f.Name,
+				Detail: detail,
+			})
+		}
+	}
+
+	used := omlox.TrackablesByProvider(s.trackables)
+	for _, p := range s.providers {
+		if _, ok := used[p.ID]; ok {
+			continue
+		}
+		if _, anchor := omlox.AnchorOf(p); anchor {
+			continue
+		}
+		issues = append(issues, output.FsckIssue{
+			Check:  fsckUnusedProviders,
+			Kind:   "provider",
+			ID:     p.ID,
+			Name:   p.Name,
+			Detail: "not used by any trackable",
+		})
+	}
+
+	return issues
+}
+
+// fenceOutsideZones describes why the fence is outside any zone, or returns an
+// empty string if it is within a zone, or that can't be told.
+func (s *fsckState) fenceOutsideZones(f omlox.Fence, zones map[string]bool) string {
+	if f.Crs == omlox.CrsLocal {
+		switch {
+		case f.ZoneID == "":
+			return "local fence without zone"
+		case !zones[f.ZoneID]:
+			return "unknown zone " + f.ZoneID
+		}
+		return ""
+	}
+
+	if (f.Crs != "" && f.Crs != omlox.CrsWGS84) || f.Region == nil {
+		return ""
+	}
+
+	// WGS84 fences are within the zones covering their center
+	center := f.Region.Center()
+	located := false
+	for _, z := range s.zones {
+		if z.Position == nil || z.Radius <= 0 {
+			continue
+		}
+		located = true
+		if analytics.Distance(center, z.Position.Base(), omlox.CrsWGS84) <= z.Radius {
+			return ""
+		}
+	}
+
+	if !located {
+		return ""
+	}
+
+	return "outside the radius of every zone"
+}
+
+// fix cleans up an issue.
+func (s *fsckState) fix(ctx context.Context, c *omlox.Client, issue output.FsckIssue) error {
+	switch issue.Check {
+	case fsckDanglingProviders:
+		for _, t := range s.trackables {
+			if t.ID.String() != issue.ID {
+				continue
+			}
+
+			kept := make([]string, 0, len(t.LocationProviders))
+			for _, id := range t.LocationProviders {
+				if s.providerIDs[id] {
+					kept = append(kept, id)
+				}
+			}
+			t.LocationProviders = kept
+
+			return c.Trackables.Update(ctx, t, t.ID)
+		}
+	case fsckUnusedProviders:
+		return c.Providers.Delete(ctx, issue.ID)
+	}
+
+	return nil
+}
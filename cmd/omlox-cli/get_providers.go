@@ -9,8 +9,8 @@ import (
 	"io"
 
 	"github.com/spf13/cobra"
-	"github.com/wavecomtech/omlox-client-go/internal/cli"
-	"github.com/wavecomtech/omlox-client-go/internal/cli/output"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/output"
 )
 
 const getProvidersHelp = `
@@ -8,8 +8,8 @@ import (
 	"fmt"
 	"io"
 
-	"github.com/wavecomtech/omlox-client-go/internal/cli"
-	"github.com/wavecomtech/omlox-client-go/internal/cli/output"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/output"
 
 	"github.com/spf13/cobra"
 )
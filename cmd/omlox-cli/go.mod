@@ -0,0 +1,52 @@
+module github.com/wavecomtech/omlox-client-go/cmd/omlox-cli
+
+go 1.21
+
+require (
+	filippo.io/age v1.2.1
+	github.com/google/uuid v1.6.0
+	github.com/minio/minio-go/v7 v7.0.70
+	github.com/nsf/jsondiff v0.0.0-20230430225905-43f6cf3098c1
+	github.com/spf13/cobra v1.8.0
+	github.com/spf13/pflag v1.0.5
+	github.com/tidwall/geojson v1.4.3
+	github.com/wavecomtech/omlox-client-go v0.0.0-00010101000000-000000000000
+	github.com/wavecomtech/omlox-client-go/archive/s3 v0.0.0-00010101000000-000000000000
+	golang.org/x/sync v0.10.0
+	golang.org/x/time v0.4.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/cpuguy83/go-md2man/v2 v2.0.3 // indirect
+	github.com/dustin/go-humanize v1.0.1 // indirect
+	github.com/goccy/go-json v0.10.2 // indirect
+	github.com/hashicorp/go-cleanhttp v0.5.2 // indirect
+	github.com/inconshreveable/mousetrap v1.1.0 // indirect
+	github.com/josharian/intern v1.0.0 // indirect
+	github.com/klauspost/compress v1.17.11 // indirect
+	github.com/klauspost/cpuid/v2 v2.2.6 // indirect
+	github.com/mailru/easyjson v0.7.7 // indirect
+	github.com/minio/md5-simd v1.1.2 // indirect
+	github.com/rs/xid v1.5.0 // indirect
+	github.com/russross/blackfriday/v2 v2.1.0 // indirect
+	github.com/tidwall/cities v0.1.0 // indirect
+	github.com/tidwall/geoindex v1.4.4 // indirect
+	github.com/tidwall/gjson v1.12.1 // indirect
+	github.com/tidwall/lotsa v1.0.2 // indirect
+	github.com/tidwall/match v1.1.1 // indirect
+	github.com/tidwall/pretty v1.2.0 // indirect
+	github.com/tidwall/rtree v1.3.1 // indirect
+	github.com/tidwall/sjson v1.2.4 // indirect
+	golang.org/x/crypto v0.31.0 // indirect
+	golang.org/x/net v0.23.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+	gopkg.in/ini.v1 v1.67.0 // indirect
+	nhooyr.io/websocket v1.8.10 // indirect
+)
+
+replace (
+	github.com/wavecomtech/omlox-client-go => ../../
+	github.com/wavecomtech/omlox-client-go/archive/s3 => ../../archive/s3
+)
@@ -0,0 +1,131 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/spf13/cobra"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/output"
+)
+
+const graphHelp = `
+This command emits the relationships among the zones, fences, trackables
+and location providers of the Omlox Hub, as a Graphviz DOT or Mermaid graph,
+for documentation and reviews of large installations:
+
+  fence -> zone          the zone of a local fence
+  trackable -> provider  the location providers of a trackable
+  provider -> zone       the zone of an anchor
+
+Resources referenced but missing from the Hub are drawn dashed.
+
+For example, to render the graph with Graphviz:
+
+  omlox graph -o dot | dot -Tsvg > hub.svg
+`
+
+func newGraphCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Export the dependency graph of the hub resources",
+		Long:  graphHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o := output.Format(format)
+			if !slices.Contains(output.GraphFormats(), format) {
+				return output.ErrInvalidFormatType
+			}
+
+			c, err := newOmloxClient(&settings)
+			if err != nil {
+				return err
+			}
+
+			res, err := loadHubResources(context.Background(), c)
+			if err != nil {
+				return err
+			}
+
+			return res.graph().Write(out, o)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&format, "output", "o", output.DOT.String(), fmt.Sprintf("Output format. One of: %v.", output.GraphFormats()))
+
+	return cmd
+}
+
+// graph returns the dependency graph of the resources.
+func (r *hubResources) graph() *output.GraphFormater {
+	g := &output.GraphFormater{}
+
+	nodes := make(map[string]bool)
+	addNode := func(kind, id, name string) string {
+		key := kind + ":" + id
+		if !nodes[key] {
+			nodes[key] = true
+			g.Nodes = append(g.Nodes, output.GraphNode{ID: key, Kind: kind, Label: labelOr(name, id)})
+		}
+		return key
+	}
+
+	// references to missing resources are added once all resources are known
+	type reference struct {
+		from, kind, id, label string
+	}
+	var refs []reference
+
+	for _, z := range r.zones {
+		addNode("zone", z.ID.String(), z.Name)
+	}
+
+	for _, f := range r.fences {
+		key := addNode("fence", f.ID.String(), f.Name)
+		if f.ZoneID != "" {
+			refs = append(refs, reference{from: key, kind: "zone", id: f.ZoneID, label: "in"})
+		}
+	}
+
+	for _, p := range r.providers {
+		key := addNode("provider", p.ID, p.Name)
+		if a, ok := omlox.AnchorOf(p); ok && a.ZoneID != "" {
+			refs = append(refs, reference{from: key, kind: "zone", id: a.ZoneID, label: "anchors"})
+		}
+	}
+
+	for _, t := range r.trackables {
+		key := addNode("trackable", t.ID.String(), t.Name)
+		for _, id := range t.LocationProviders {
+			refs = append(refs, reference{from: key, kind: "provider", id: id, label: "uses"})
+		}
+	}
+
+	for _, ref := range refs {
+		to := ref.kind + ":" + ref.id
+		if !nodes[to] {
+			nodes[to] = true
+			g.Nodes = append(g.Nodes, output.GraphNode{ID: to, Kind: ref.kind, Label: ref.id, Missing: true})
+		}
+		g.Edges = append(g.Edges, output.GraphEdge{From: ref.from, To: to, Label: ref.label})
+	}
+
+	return g
+}
+
+// labelOr returns the name of a resource, or its id if unnamed.
+func labelOr(name, id string) string {
+	if name == "" {
+		return id
+	}
+	return name
+}
@@ -0,0 +1,125 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/output"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/snapshot"
+)
+
+const importHelp = `
+This command imports the resources of a directory exported with
+'omlox export --all' into the hub: zones, providers, fences and trackables,
+in this order. A report of the outcome of each resource is written.
+
+Resources already in the hub, with the same id, are conflicts, resolved with
+the --on-conflict strategy:
+
+  skip       keep the resource of the hub
+  overwrite  replace the resource of the hub by the imported one
+  merge      update the resource of the hub with the fields of the imported
+             one, deep-merging their properties
+  fail       stop the import (default)
+
+For example, to restore a backup without losing the properties added since:
+
+    $ omlox import -d backup --on-conflict merge
+
+A template dataset, such as the zones, fences and providers of a production
+line, is imported several times by renaming the prefixes of the names and of
+the ids other than UUIDs, and by regenerating the UUID ids. The references
+between the imported resources follow their new ids:
+
+    $ omlox import -d line-template --rename-prefix line1-=line2- --regenerate-ids
+`
+
+func newImportCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	var (
+		dir           string
+		onConflict    string
+		renames       []string
+		regenerateIDs bool
+		format        string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import exported resources into the hub",
+		Long:  importHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o, err := output.ParseFormat(format)
+			if err != nil {
+				return err
+			}
+
+			strategy, err := snapshot.ParseStrategy(onConflict)
+			if err != nil {
+				return err
+			}
+
+			var prefixes []snapshot.Rename
+			for _, r := range renames {
+				rename, err := snapshot.ParseRename(r)
+				if err != nil {
+					return err
+				}
+				prefixes = append(prefixes, rename)
+			}
+
+			progress, err := settings.ProgressReporter(cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+
+			c, err := newOmloxClient(&settings)
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			im := &snapshot.Importer{
+				Client:        c,
+				Dir:           dir,
+				OnConflict:    strategy,
+				Renames:       prefixes,
+				RegenerateIDs: regenerateIDs,
+				Progress:      progress,
+			}
+			results, err := im.Import(ctx)
+
+			if werr := o.Write(out, &output.ImportFormater{Results: results}); werr != nil && err == nil {
+				err = werr
+			}
+			if errors.Is(err, snapshot.ErrConflict) {
+				return fmt.Errorf("%w; choose how to resolve the conflicts with --on-conflict", err)
+			}
+			return err
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&dir, "dir", "d", "", "Directory of the exported resources")
+	f.StringVar(&onConflict, "on-conflict", snapshot.Fail.String(), fmt.Sprintf("Resolution of the resources already in the hub. One of: %v.", snapshot.Strategies()))
+	f.StringArrayVar(&renames, "rename-prefix", nil, "Prefix of the names and ids to rename, as old=new. Can be repeated.")
+	f.BoolVar(&regenerateIDs, "regenerate-ids", false, "Give new UUIDs to the resources with UUID ids")
+	f.StringVarP(&format, "output", "o", output.Table.String(), fmt.Sprintf("Output format of the report. One of: %v.", output.TabularFormats()))
+
+	cmd.MarkFlagRequired("dir")
+
+	cmd.AddCommand(newImportDXFCmd(settings, out))
+
+	return cmd
+}
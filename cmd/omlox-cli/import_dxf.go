@@ -0,0 +1,171 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/dxf"
+)
+
+const importDXFHelp = `
+This command creates zones and fences from the closed polylines of the layers
+of a floorplan, exported from CAD software as an ASCII DXF file. DWG files
+must be exported as DXF first.
+
+The closed polylines of the --zone-layer layers become zones, and those of the
+--fence-layer layers become WGS84 fences. They are named after the text within
+them, such as a room name, or else after their layer and number in the layer.
+Without layers, the layers of the drawing are listed.
+
+The drawing is placed on the earth with at least two ground control points,
+given in drawing units and WGS84 as x,y=lon,lat. The drawing units are read
+from the drawing, and the local coordinates of the zones are the drawing
+coordinates in meters. For example:
+
+    $ omlox import dxf hall.dxf --zone-layer Hall --fence-layer Rooms \
+        --gcp 0,0=7.8157,48.1302 --gcp 20000,0=7.81597,48.1302
+
+With --dry-run, the zones and fences are printed without creating them.
+`
+
+func newImportDXFCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	var (
+		zoneLayers  []string
+		fenceLayers []string
+		gcps        []string
+		zoneType    string
+		dryRun      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "dxf FILE",
+		Short: "Import zones and fences from a DXF floorplan",
+		Long:  importDXFHelp,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			d, err := dxf.Parse(f)
+			if err != nil {
+				return err
+			}
+
+			if len(zoneLayers) == 0 && len(fenceLayers) == 0 {
+				for _, l := range d.Layers() {
+					fmt.Fprintln(out, l)
+				}
+				return nil
+			}
+
+			var typ omlox.LocationProviderType
+			if err := typ.FromString(zoneType); err != nil {
+				return err
+			}
+
+			points := make([]omlox.GroundControlPoint, len(gcps))
+			for i, s := range gcps {
+				if points[i], err = parseGCP(s); err != nil {
+					return err
+				}
+			}
+			g, err := dxf.NewGeoreference(d, points)
+			if err != nil {
+				return err
+			}
+
+			zones := d.Zones(g, typ, zoneLayers...)
+			fences := d.Fences(g, fenceLayers...)
+			if len(zones) == 0 && len(fences) == 0 {
+				return fmt.Errorf("no closed polylines in the layers")
+			}
+
+			if dryRun {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(struct {
+					Zones  []omlox.Zone  `json:"zones"`
+					Fences []omlox.Fence `json:"fences"`
+				}{zones, fences})
+			}
+
+			c, err := newOmloxClient(&settings)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+
+			for _, z := range zones {
+				var created omlox.Zone
+				if err := c.Do(ctx, http.MethodPost, "/zones", z, &created); err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "created: %v %v\n", created.ID, created.Name)
+			}
+			for _, f := range fences {
+				created, err := c.Fences.Create(ctx, f)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "created: %v %v\n", created.ID, created.Name)
+			}
+
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringArrayVar(&zoneLayers, "zone-layer", nil, "Layer of the polylines of zones. Can be repeated.")
+	f.StringArrayVar(&fenceLayers, "fence-layer", nil, "Layer of the polylines of fences. Can be repeated.")
+	f.StringArrayVar(&gcps, "gcp", nil, "Ground control point, as x,y=lon,lat in drawing units and WGS84. Can be repeated.")
+	f.StringVar(&zoneType, "zone-type", omlox.LocationProviderTypeUwb.String(), "Location provider type of the zones")
+	f.BoolVar(&dryRun, "dry-run", false, "Print the zones and fences without creating them")
+
+	return cmd
+}
+
+// parseGCP parses a ground control point given as x,y=lon,lat.
+func parseGCP(s string) (omlox.GroundControlPoint, error) {
+	local, wgs84, ok := strings.Cut(s, "=")
+	if !ok {
+		return omlox.GroundControlPoint{}, fmt.Errorf("invalid ground control point %q: expected x,y=lon,lat", s)
+	}
+
+	parse := func(coords string) (geometry.Point, error) {
+		xs, ys, ok := strings.Cut(coords, ",")
+		x, xerr := strconv.ParseFloat(strings.TrimSpace(xs), 64)
+		y, yerr := strconv.ParseFloat(strings.TrimSpace(ys), 64)
+		if !ok || xerr != nil || yerr != nil {
+			return geometry.Point{}, fmt.Errorf("invalid ground control point %q: expected x,y=lon,lat", s)
+		}
+		return geometry.Point{X: x, Y: y}, nil
+	}
+
+	l, err := parse(local)
+	if err != nil {
+		return omlox.GroundControlPoint{}, err
+	}
+	w, err := parse(wgs84)
+	if err != nil {
+		return omlox.GroundControlPoint{}, err
+	}
+
+	return omlox.GroundControlPoint{Local: *omlox.NewPoint(l), WGS84: *omlox.NewPoint(w)}, nil
+}
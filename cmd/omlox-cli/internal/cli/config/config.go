@@ -0,0 +1,108 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package config implements the configuration file of the CLI, holding the Hub
+// address and the credentials authenticating with it. Secrets can be stored
+// encrypted with a passphrase, so the file can be kept on shared hosts.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PassphraseEnv is the environment variable holding the passphrase of the
+// encrypted secrets.
+const PassphraseEnv = "OMLOX_CONFIG_PASSPHRASE"
+
+// Config is the configuration of the CLI.
+type Config struct {
+	// Addr is the Hub API endpoint.
+	Addr string `yaml:"addr,omitempty"`
+
+	// Token is the bearer token authenticating with the Hub.
+	Token Secret `yaml:"token,omitempty"`
+
+	// TokenURL is the OAuth2 token endpoint issuing the tokens authenticating
+	// with the Hub to the client credentials, instead of a fixed token.
+	TokenURL string `yaml:"token_url,omitempty"`
+
+	// ClientID and ClientSecret are the client credentials of the token endpoint.
+	ClientID     string `yaml:"client_id,omitempty"`
+	ClientSecret Secret `yaml:"client_secret,omitempty"`
+}
+
+// DefaultFile returns the default configuration file, in the user configuration
+// directory, or an empty name if there is none.
+func DefaultFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "omlox", "config.yaml")
+}
+
+// Load reads the named configuration file. A missing file is an empty configuration.
+func Load(name string) (*Config, error) {
+	c := &Config{}
+	if name == "" {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", name, err)
+	}
+
+	return c, nil
+}
+
+// Save writes the configuration to the named file, readable only by its owner.
+func (c *Config) Save(name string) error {
+	if name == "" {
+		return fmt.Errorf("no config file")
+	}
+
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(name), 0700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(name, data, 0600)
+}
+
+// Encrypt encrypts the plaintext secrets of the configuration, returning how many
+// were encrypted.
+func (c *Config) Encrypt(passphrase string) (int, error) {
+	n := 0
+	for _, secret := range []*Secret{&c.Token, &c.ClientSecret} {
+		if *secret == "" || secret.Encrypted() {
+			continue
+		}
+
+		encrypted, err := Encrypt(string(*secret), passphrase)
+		if err != nil {
+			return n, err
+		}
+		*secret = encrypted
+		n++
+	}
+
+	return n, nil
+}
@@ -0,0 +1,57 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEncrypt(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "omlox", "config.yaml")
+
+	c, err := Load(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.Addr = "https://hub:8081/v2"
+	c.Token = "secret"
+	c.ClientSecret = "client-secret"
+
+	n, err := c.Encrypt("passphrase")
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 encrypted secrets, got %d (%v)", n, err)
+	}
+	if err := c.Save(name); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if c, err = Load(name); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !c.Token.Encrypted() || strings.Contains(string(c.Token), "secret") {
+		t.Fatalf("expected encrypted token, got %q", c.Token)
+	}
+	if !c.ClientSecret.Encrypted() {
+		t.Fatalf("expected encrypted client secret, got %q", c.ClientSecret)
+	}
+
+	// encrypted secrets are not encrypted twice
+	if n, _ := c.Encrypt("passphrase"); n != 0 {
+		t.Errorf("expected no encrypted secret, got %d", n)
+	}
+
+	token, err := c.Token.Reveal("passphrase")
+	if err != nil || token != "secret" {
+		t.Errorf("expected revealed token, got %q (%v)", token, err)
+	}
+	if _, err := c.Token.Reveal("other"); !errors.Is(err, ErrPassphrase) {
+		t.Errorf("expected wrong passphrase, got %v", err)
+	}
+	if _, err := c.Token.Reveal(""); err == nil {
+		t.Errorf("expected error without passphrase")
+	}
+}
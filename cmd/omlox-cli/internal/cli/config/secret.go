@@ -0,0 +1,105 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// ErrPassphrase is returned when decrypting a secret without the passphrase it
+// was encrypted with.
+var ErrPassphrase = errors.New("wrong passphrase")
+
+// Secret is a secret of the configuration, either in plaintext or encrypted.
+//
+// Encrypted secrets are age files encrypted with a passphrase, in the armored
+// format, so they can also be decrypted with 'age -d'.
+type Secret string
+
+// Encrypted reports whether the secret is encrypted.
+func (s Secret) Encrypted() bool {
+	return strings.HasPrefix(string(s), armor.Header)
+}
+
+// Reveal returns the plaintext of the secret, decrypting it with the passphrase
+// if it is encrypted.
+func (s Secret) Reveal(passphrase string) (string, error) {
+	if !s.Encrypted() {
+		return string(s), nil
+	}
+	if passphrase == "" {
+		return "", fmt.Errorf("encrypted secret: %s is required", PassphraseEnv)
+	}
+
+	identity, err := age.NewScryptIdentity(passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	r, err := age.Decrypt(armor.NewReader(strings.NewReader(string(s))), identity)
+	if err != nil {
+		var nomatch *age.NoIdentityMatchError
+		if errors.As(err, &nomatch) {
+			return "", ErrPassphrase
+		}
+		return "", fmt.Errorf("invalid encrypted secret: %w", err)
+	}
+
+	plaintext, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted secret: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// String redacts the secret, so it is never printed by mistake.
+func (s Secret) String() string {
+	switch {
+	case s == "":
+		return ""
+	case s.Encrypted():
+		return "<encrypted>"
+	default:
+		return "<plaintext>"
+	}
+}
+
+// Encrypt encrypts a secret with the passphrase, into an armored age file.
+func Encrypt(plaintext, passphrase string) (Secret, error) {
+	if passphrase == "" {
+		return "", fmt.Errorf("empty passphrase")
+	}
+
+	recipient, err := age.NewScryptRecipient(passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	aw := armor.NewWriter(&buf)
+
+	w, err := age.Encrypt(aw, recipient)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.WriteString(w, plaintext); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	if err := aw.Close(); err != nil {
+		return "", err
+	}
+
+	return Secret(buf.String()), nil
+}
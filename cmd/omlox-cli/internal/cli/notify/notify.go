@@ -0,0 +1,78 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package notify raises desktop notifications with the native tools of the
+// platform: notify-send on Linux, osascript on macOS and the toast notifications
+// of PowerShell on Windows, so no extra library is needed.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrUnsupported is returned on platforms without desktop notifications.
+var ErrUnsupported = errors.New("desktop notifications not supported")
+
+// The title and message are given to the scripts in environment variables, so
+// they are never interpreted by the scripts.
+const (
+	titleEnv   = "OMLOX_NOTIFY_TITLE"
+	messageEnv = "OMLOX_NOTIFY_MESSAGE"
+)
+
+const appName = "omlox"
+
+const darwinScript = `display notification (system attribute "` + messageEnv + `") with title (system attribute "` + titleEnv + `")`
+
+const windowsScript = `
+[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null
+$t = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $t.GetElementsByTagName('text')
+$text.Item(0).AppendChild($t.CreateTextNode($env:` + titleEnv + `)) | Out-Null
+$text.Item(1).AppendChild($t.CreateTextNode($env:` + messageEnv + `)) | Out-Null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('` + appName + `').Show([Windows.UI.Notifications.ToastNotification]::new($t))
+`
+
+// Notification is a desktop notification.
+type Notification struct {
+	Title   string
+	Message string
+}
+
+// Send raises the notification on the desktop of the user.
+func Send(ctx context.Context, n Notification) error {
+	cmd, err := command(ctx, runtime.GOOS, n)
+	if err != nil {
+		return err
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", cmd.Path, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// command returns the command raising the notification on the platform.
+func command(ctx context.Context, goos string, n Notification) (*exec.Cmd, error) {
+	var cmd *exec.Cmd
+
+	switch goos {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		cmd = exec.CommandContext(ctx, "notify-send", "--app-name="+appName, "--", n.Title, n.Message)
+	case "darwin":
+		cmd = exec.CommandContext(ctx, "osascript", "-e", darwinScript)
+	case "windows":
+		cmd = exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", windowsScript)
+	default:
+		return nil, fmt.Errorf("%w on %s", ErrUnsupported, goos)
+	}
+
+	cmd.Env = append(os.Environ(), titleEnv+"="+n.Title, messageEnv+"="+n.Message)
+	return cmd, nil
+}
@@ -0,0 +1,49 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package notify
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	n := Notification{Title: "Fence entry: Lab", Message: `"; rm -rf / #`}
+
+	tests := []struct {
+		goos string
+		name string
+		arg  string
+	}{
+		{goos: "linux", name: "notify-send", arg: n.Message},
+		{goos: "darwin", name: "osascript", arg: darwinScript},
+		{goos: "windows", name: "powershell", arg: windowsScript},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			cmd, err := command(context.Background(), tt.goos, n)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !strings.HasSuffix(cmd.Args[0], tt.name) {
+				t.Errorf("expected %s, got %s", tt.name, cmd.Args[0])
+			}
+			if !slices.Contains(cmd.Args, tt.arg) {
+				t.Errorf("expected argument %q in %q", tt.arg, cmd.Args)
+			}
+			if !slices.Contains(cmd.Env, messageEnv+"="+n.Message) {
+				t.Errorf("expected message in environment")
+			}
+		})
+	}
+
+	if _, err := command(context.Background(), "plan9", n); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expected unsupported platform, got %v", err)
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// BenchResult summarizes a load test run.
+type BenchResult struct {
+	Transport string
+	Duration  time.Duration
+
+	// Target and achieved rates, in locations per second.
+	TargetRate float64
+	Throughput float64
+
+	Sent   int
+	Errors int
+
+	// Latency distribution of the successful sends.
+	Latency LatencySummary
+}
+
+// LatencySummary is a distribution of request latencies.
+type LatencySummary struct {
+	Min  time.Duration
+	Mean time.Duration
+	P50  time.Duration
+	P90  time.Duration
+	P99  time.Duration
+	Max  time.Duration
+}
+
+type BenchFormater struct {
+	Result BenchResult
+}
+
+var (
+	_ Writer    = (*BenchFormater)(nil)
+	_ CSVWriter = (*BenchFormater)(nil)
+)
+
+func (bf *BenchFormater) WriteTable(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 10, 1, 3, ' ', 0)
+
+	header := "%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t\n"
+	if _, err := fmt.Fprintf(w, header, "TRANSPORT", "DURATION", "SENT", "ERRORS", "RATE (LOC/S)", "LATENCY MIN", "P50", "P90", "P99", "MAX"); err != nil {
+		return err
+	}
+
+	r, l := bf.Result, bf.Result.Latency
+	format := "%v\t%v\t%v\t%v\t%.1f\t%v\t%v\t%v\t%v\t%v\t\n"
+	if _, err := fmt.Fprintf(w, format,
+		r.Transport, roundDuration(r.Duration), r.Sent, r.Errors, r.Throughput,
+		roundLatency(l.Min), roundLatency(l.P50), roundLatency(l.P90), roundLatency(l.P99), roundLatency(l.Max),
+	); err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+// benchJSON is the JSON representation of a BenchResult, with durations in milliseconds.
+type benchJSON struct {
+	Transport  string      `json:"transport"`
+	Duration   float64     `json:"duration_s"`
+	TargetRate float64     `json:"target_rate"`
+	Throughput float64     `json:"throughput"`
+	Sent       int         `json:"sent"`
+	Errors     int         `json:"errors"`
+	Latency    latencyJSON `json:"latency_ms"`
+}
+
+type latencyJSON struct {
+	Min  float64 `json:"min"`
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+}
+
+func (bf *BenchFormater) WriteJSON(out io.Writer) error {
+	r, l := bf.Result, bf.Result.Latency
+	return json.NewEncoder(out).Encode(benchJSON{
+		Transport:  r.Transport,
+		Duration:   r.Duration.Seconds(),
+		TargetRate: r.TargetRate,
+		Throughput: r.Throughput,
+		Sent:       r.Sent,
+		Errors:     r.Errors,
+		Latency: latencyJSON{
+			Min:  milliseconds(l.Min),
+			Mean: milliseconds(l.Mean),
+			P50:  milliseconds(l.P50),
+			P90:  milliseconds(l.P90),
+			P99:  milliseconds(l.P99),
+			Max:  milliseconds(l.Max),
+		},
+	})
+}
+
+// WriteCSV writes a single row, with the duration in seconds and latencies in milliseconds.
+func (bf *BenchFormater) WriteCSV(out io.Writer) error {
+	w := csv.NewWriter(out)
+
+	header := []string{"transport", "duration", "target_rate", "throughput", "sent", "errors", "latency_min", "latency_mean", "latency_p50", "latency_p90", "latency_p99", "latency_max"}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	r, l := bf.Result, bf.Result.Latency
+	record := []string{
+		r.Transport,
+		seconds(r.Duration),
+		strconv.FormatFloat(r.TargetRate, 'f', -1, 64),
+		strconv.FormatFloat(r.Throughput, 'f', -1, 64),
+		strconv.Itoa(r.Sent),
+		strconv.Itoa(r.Errors),
+		strconv.FormatFloat(milliseconds(l.Min), 'f', -1, 64),
+		strconv.FormatFloat(milliseconds(l.Mean), 'f', -1, 64),
+		strconv.FormatFloat(milliseconds(l.P50), 'f', -1, 64),
+		strconv.FormatFloat(milliseconds(l.P90), 'f', -1, 64),
+		strconv.FormatFloat(milliseconds(l.P99), 'f', -1, 64),
+		strconv.FormatFloat(milliseconds(l.Max), 'f', -1, 64),
+	}
+	if err := w.Write(record); err != nil {
+		return err
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// roundLatency rounds a latency to microseconds for human-readable output.
+func roundLatency(d time.Duration) time.Duration {
+	return d.Round(time.Microsecond)
+}
+
+// milliseconds converts a duration to a decimal number of milliseconds.
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
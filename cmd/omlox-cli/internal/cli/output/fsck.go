@@ -0,0 +1,84 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+)
+
+// FsckIssue is an inconsistency found between the resources of the Hub.
+type FsckIssue struct {
+	// Check which found the issue (e.g. dangling-providers).
+	Check string `json:"check"`
+
+	// Kind of the inconsistent resource (e.g. trackable).
+	Kind string `json:"kind"`
+
+	// ID and name of the inconsistent resource.
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+
+	// Detail describes the inconsistency.
+	Detail string `json:"detail"`
+
+	// Fixed reports whether the issue was cleaned up.
+	Fixed bool `json:"fixed"`
+}
+
+type FsckFormater struct {
+	Issues []FsckIssue
+}
+
+var (
+	_ Writer    = (*FsckFormater)(nil)
+	_ CSVWriter = (*FsckFormater)(nil)
+)
+
+func (ff *FsckFormater) WriteTable(out io.Writer) error {
+	if len(ff.Issues) == 0 {
+		_, err := fmt.Fprintln(out, "no inconsistencies found")
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 10, 1, 3, ' ', 0)
+
+	format := "%s\t%s\t%s\t%s\t%s\t%v\t\n"
+	if _, err := fmt.Fprintf(w, format, "CHECK", "KIND", "ID", "NAME", "DETAIL", "FIXED"); err != nil {
+		return err
+	}
+
+	for _, i := range ff.Issues {
+		if _, err := fmt.Fprintf(w, format, i.Check, i.Kind, i.ID, i.Name, i.Detail, i.Fixed); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+func (ff *FsckFormater) WriteJSON(out io.Writer) error {
+	return json.NewEncoder(out).Encode(ff.Issues)
+}
+
+func (ff *FsckFormater) WriteCSV(out io.Writer) error {
+	w := csv.NewWriter(out)
+
+	if err := w.Write([]string{"check", "kind", "id", "name", "detail", "fixed"}); err != nil {
+		return err
+	}
+
+	for _, i := range ff.Issues {
+		if err := w.Write([]string{i.Check, i.Kind, i.ID, i.Name, i.Detail, strconv.FormatBool(i.Fixed)}); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,147 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Graph output formats.
+const (
+	DOT     Format = "dot"
+	Mermaid Format = "mermaid"
+)
+
+// GraphFormats returns a list of the string representation of the formats
+// supported by the graph writer.
+func GraphFormats() []string {
+	return []string{DOT.String(), Mermaid.String(), JSON.String()}
+}
+
+// GraphNode is a resource of the Hub.
+type GraphNode struct {
+	// Unique id of the node, the resource kind and id (e.g. "zone:<uuid>").
+	ID string `json:"id"`
+
+	// Kind of the resource (e.g. zone).
+	Kind string `json:"kind"`
+
+	// Label is the name of the resource, or its id if unnamed.
+	Label string `json:"label"`
+
+	// Missing reports nodes referenced by other resources which do not exist.
+	Missing bool `json:"missing,omitempty"`
+}
+
+// GraphEdge is a relationship between two resources.
+type GraphEdge struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Label string `json:"label"`
+}
+
+type GraphFormater struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// Write the graph in the given format to the io.Writer.
+func (gf *GraphFormater) Write(out io.Writer, o Format) error {
+	switch o {
+	case DOT:
+		return gf.WriteDOT(out)
+	case Mermaid:
+		return gf.WriteMermaid(out)
+	case JSON:
+		return json.NewEncoder(out).Encode(gf)
+	}
+	return ErrInvalidFormatType
+}
+
+// graphShapes are the DOT node shapes of each resource kind.
+var graphShapes = map[string]string{
+	"zone":      "folder",
+	"fence":     "box",
+	"trackable": "ellipse",
+	"provider":  "note",
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language.
+func (gf *GraphFormater) WriteDOT(out io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("digraph omlox {\n\trankdir=LR;\n")
+
+	for _, n := range gf.Nodes {
+		attrs := fmt.Sprintf("label=%s", dotQuote(n.Kind+"\n"+n.Label))
+		if shape, ok := graphShapes[n.Kind]; ok {
+			attrs += ", shape=" + shape
+		}
+		if n.Missing {
+			attrs += ", style=dashed, color=red"
+		}
+		fmt.Fprintf(&b, "\t%s [%s];\n", dotQuote(n.ID), attrs)
+	}
+
+	for _, e := range gf.Edges {
+		fmt.Fprintf(&b, "\t%s -> %s [label=%s];\n", dotQuote(e.From), dotQuote(e.To), dotQuote(e.Label))
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart.
+func (gf *GraphFormater) WriteMermaid(out io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("flowchart LR\n")
+
+	// mermaid ids are restricted to plain identifiers
+	ids := make(map[string]string, len(gf.Nodes))
+	for i, n := range gf.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+
+		label := mermaidQuote(n.Kind + ": " + n.Label)
+		switch n.Kind {
+		case "zone":
+			fmt.Fprintf(&b, "\t%s[[%s]]\n", ids[n.ID], label)
+		case "trackable":
+			fmt.Fprintf(&b, "\t%s([%s])\n", ids[n.ID], label)
+		default:
+			fmt.Fprintf(&b, "\t%s[%s]\n", ids[n.ID], label)
+		}
+	}
+
+	for _, e := range gf.Edges {
+		fmt.Fprintf(&b, "\t%s -->|%s| %s\n", ids[e.From], mermaidQuote(e.Label), ids[e.To])
+	}
+
+	for _, n := range gf.Nodes {
+		if n.Missing {
+			fmt.Fprintf(&b, "\tstyle %s stroke:#f00,stroke-dasharray:5\n", ids[n.ID])
+		}
+	}
+
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// dotQuote quotes a DOT identifier.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// mermaidQuote quotes a Mermaid label.
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
@@ -0,0 +1,66 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/snapshot"
+)
+
+type ImportFormater struct {
+	Results []snapshot.Result
+}
+
+var (
+	_ Writer    = (*ImportFormater)(nil)
+	_ CSVWriter = (*ImportFormater)(nil)
+)
+
+func (imf *ImportFormater) WriteTable(out io.Writer) error {
+	if len(imf.Results) == 0 {
+		_, err := fmt.Fprintln(out, "no resources imported")
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 10, 1, 3, ' ', 0)
+
+	format := "%s\t%s\t%s\t%s\t%s\t\n"
+	if _, err := fmt.Fprintf(w, format, "COLLECTION", "ID", "NAME", "OUTCOME", "ERROR"); err != nil {
+		return err
+	}
+
+	for _, r := range imf.Results {
+		if _, err := fmt.Fprintf(w, format, r.Collection, r.ID, r.Name, r.Outcome, r.Error); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+func (imf *ImportFormater) WriteJSON(out io.Writer) error {
+	return json.NewEncoder(out).Encode(imf.Results)
+}
+
+func (imf *ImportFormater) WriteCSV(out io.Writer) error {
+	w := csv.NewWriter(out)
+
+	if err := w.Write([]string{"collection", "id", "name", "outcome", "error"}); err != nil {
+		return err
+	}
+
+	for _, r := range imf.Results {
+		if err := w.Write([]string{r.Collection, r.ID, r.Name, string(r.Outcome), r.Error}); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,70 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+type LintFormater struct {
+	Issues []omlox.LintIssue
+}
+
+var (
+	_ Writer    = (*LintFormater)(nil)
+	_ CSVWriter = (*LintFormater)(nil)
+)
+
+func (lf *LintFormater) WriteTable(out io.Writer) error {
+	if len(lf.Issues) == 0 {
+		_, err := fmt.Fprintln(out, "no issues found")
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 10, 1, 3, ' ', 0)
+
+	format := "%s\t%s\t%s\t%s\t%s\t\n"
+	if _, err := fmt.Fprintf(w, format, "SEVERITY", "CHECK", "FENCE", "NAME", "DETAIL"); err != nil {
+		return err
+	}
+
+	for _, i := range lf.Issues {
+		if _, err := fmt.Fprintf(w, format, i.Severity, i.Check, i.FenceID, i.FenceName, i.Detail); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+func (lf *LintFormater) WriteJSON(out io.Writer) error {
+	return json.NewEncoder(out).Encode(lf.Issues)
+}
+
+func (lf *LintFormater) WriteCSV(out io.Writer) error {
+	w := csv.NewWriter(out)
+
+	if err := w.Write([]string{"severity", "check", "fence_id", "fence_name", "other_id", "detail"}); err != nil {
+		return err
+	}
+
+	for _, i := range lf.Issues {
+		other := ""
+		if i.OtherID != nil {
+			other = i.OtherID.String()
+		}
+		if err := w.Write([]string{i.Severity.String(), i.Check, i.FenceID.String(), i.FenceName, other, i.Detail}); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
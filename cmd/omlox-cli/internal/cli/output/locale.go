@@ -0,0 +1,125 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package output
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Unit is the unit of the distances of the human-readable output.
+type Unit string
+
+const (
+	Meters Unit = "meters"
+	Feet   Unit = "feet"
+)
+
+// metersPerFoot is the length of the international foot.
+const metersPerFoot = 0.3048
+
+// Units returns a list of the string representation of the supported units.
+func Units() []string {
+	return []string{Meters.String(), Feet.String()}
+}
+
+// String returns the string representation of the Unit.
+func (u Unit) String() string {
+	return string(u)
+}
+
+// Symbol returns the symbol of the unit, such as "m".
+func (u Unit) Symbol() string {
+	if u == Feet {
+		return "ft"
+	}
+	return "m"
+}
+
+// ParseUnit takes a raw string and returns the matching Unit.
+func ParseUnit(s string) (Unit, error) {
+	switch strings.ToLower(s) {
+	case "meters", "metres", "m":
+		return Meters, nil
+	case "feet", "ft":
+		return Feet, nil
+	}
+	return "", fmt.Errorf("invalid unit %q: expected one of %v", s, Units())
+}
+
+// timeFormats are the named time formats, besides Go layouts.
+var timeFormats = map[string]string{
+	"rfc3339":  time.RFC3339,
+	"rfc1123":  time.RFC1123,
+	"datetime": time.DateTime,
+	"date":     time.DateOnly,
+	"kitchen":  time.Kitchen,
+}
+
+// Locale localizes the timestamps and distances of the human-readable output,
+// the table and CSV formats. JSON output keeps RFC3339 timestamps and meters.
+type Locale struct {
+	// TimeFormat is the layout of the timestamps. Default: time.RFC3339
+	TimeFormat string
+
+	// Location is the time zone of the timestamps. Default: nil, the time zone
+	// of each timestamp is kept
+	Location *time.Location
+
+	// Unit of the distances. Default: Meters
+	Unit Unit
+}
+
+// NewLocale returns the locale of a time format, either a name such as rfc3339
+// or datetime or a Go time layout, an IANA time zone name, "Local" or "UTC",
+// and a unit name.
+func NewLocale(timeFormat, timezone, unit string) (Locale, error) {
+	l := Locale{TimeFormat: timeFormat}
+	if layout, ok := timeFormats[strings.ToLower(timeFormat)]; ok {
+		l.TimeFormat = layout
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return Locale{}, fmt.Errorf("invalid timezone: %w", err)
+	}
+	l.Location = loc
+
+	if l.Unit, err = ParseUnit(unit); err != nil {
+		return Locale{}, err
+	}
+
+	return l, nil
+}
+
+// In returns the time in the time zone of the locale.
+func (l Locale) In(t time.Time) time.Time {
+	if l.Location == nil {
+		return t
+	}
+	return t.In(l.Location)
+}
+
+// Time formats the time in the time zone and format of the locale.
+func (l Locale) Time(t time.Time) string {
+	layout := l.TimeFormat
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	return l.In(t).Format(layout)
+}
+
+// Distance converts a distance in meters to the unit of the locale.
+func (l Locale) Distance(meters float64) float64 {
+	if l.Unit == Feet {
+		return meters / metersPerFoot
+	}
+	return meters
+}
+
+// Symbol returns the symbol of the distance unit of the locale.
+func (l Locale) Symbol() string {
+	return l.Unit.Symbol()
+}
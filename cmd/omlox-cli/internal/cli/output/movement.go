@@ -0,0 +1,88 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/wavecomtech/omlox-client-go/analytics"
+)
+
+type MovementFormater struct {
+	Movements []analytics.Movement
+
+	// Locale of the periods and distances of the table and CSV formats.
+	Locale Locale
+}
+
+var (
+	_ Writer    = (*MovementFormater)(nil)
+	_ CSVWriter = (*MovementFormater)(nil)
+)
+
+func (mf *MovementFormater) WriteTable(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 10, 1, 3, ' ', 0)
+
+	header := "%v\t%s\t%v\t%v\t%v\t%v\t%v\t\n"
+	distance := fmt.Sprintf("DISTANCE (%s)", strings.ToUpper(mf.Locale.Symbol()))
+	if _, err := fmt.Fprintf(w, header, "TRACKABLE", "NAME", "PERIOD", distance, "MOVING", "IDLE", "FENCE CROSSINGS"); err != nil {
+		return err
+	}
+
+	format := "%v\t%s\t%v\t%.1f\t%v\t%v\t%v\t\n"
+	for _, m := range mf.Movements {
+		if _, err := fmt.Fprintf(w, format,
+			m.TrackableID, m.Name, mf.Locale.Time(m.Start), mf.Locale.Distance(m.Distance),
+			roundDuration(m.Moving), roundDuration(m.Idle), m.FenceCrossings,
+		); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+func (mf *MovementFormater) WriteJSON(out io.Writer) error {
+	return json.NewEncoder(out).Encode(mf.Movements)
+}
+
+// WriteCSV writes one row per trackable and period, with times in seconds.
+// Distances in feet are written in a distance_ft column.
+func (mf *MovementFormater) WriteCSV(out io.Writer) error {
+	w := csv.NewWriter(out)
+
+	distance := "distance"
+	if mf.Locale.Unit == Feet {
+		distance = "distance_ft"
+	}
+
+	header := []string{"trackable_id", "name", "start", distance, "moving", "idle", "fence_crossings"}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	for _, m := range mf.Movements {
+		record := []string{
+			m.TrackableID.String(),
+			m.Name,
+			mf.Locale.Time(m.Start),
+			strconv.FormatFloat(mf.Locale.Distance(m.Distance), 'f', -1, 64),
+			seconds(m.Moving),
+			seconds(m.Idle),
+			strconv.Itoa(m.FenceCrossings),
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
@@ -13,6 +13,7 @@ type Format string
 const (
 	Table Format = "table"
 	JSON  Format = "json"
+	CSV   Format = "csv"
 )
 
 // Formats returns a list of the string representation of the supported formats
@@ -20,12 +21,19 @@ func Formats() []string {
 	return []string{Table.String(), JSON.String()}
 }
 
+// TabularFormats returns a list of the string representation of the formats
+// supported by writers that also implement CSVWriter
+func TabularFormats() []string {
+	return []string{Table.String(), CSV.String(), JSON.String()}
+}
+
 // FormatsWithDesc returns a list of the string representation of the supported formats
 // including a description
 func FormatsWithDesc() map[string]string {
 	return map[string]string{
 		Table.String(): "Output result in human-readable format",
 		JSON.String():  "Output result in JSON format",
+		CSV.String():   "Output result in CSV format",
 	}
 }
 
@@ -45,6 +53,10 @@ func (o Format) Write(out io.Writer, w Writer) error {
 		return w.WriteTable(out)
 	case JSON:
 		return w.WriteJSON(out)
+	case CSV:
+		if cw, ok := w.(CSVWriter); ok {
+			return cw.WriteCSV(out)
+		}
 	}
 	return ErrInvalidFormatType
 }
@@ -57,6 +69,8 @@ func ParseFormat(s string) (out Format, err error) {
 		out, err = Table, nil
 	case JSON.String():
 		out, err = JSON, nil
+	case CSV.String():
+		out, err = CSV, nil
 	default:
 		out, err = "", ErrInvalidFormatType
 	}
@@ -72,3 +86,11 @@ type Writer interface {
 	// returning an error if any occur
 	WriteJSON(out io.Writer) error
 }
+
+// CSVWriter is an interface that writers can optionally implement to
+// support the CSV format
+type CSVWriter interface {
+	// WriteCSV will write CSV formatted output into the given io.Writer,
+	// returning an error if any occur
+	WriteCSV(out io.Writer) error
+}
@@ -0,0 +1,119 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nsf/jsondiff"
+	"github.com/wavecomtech/omlox-client-go/hubfile"
+)
+
+// ANSI escape sequences of the colorized output.
+const (
+	ansiReset  = "\033[0m"
+	ansiBold   = "\033[1m"
+	ansiGreen  = "\033[0;32m"
+	ansiYellow = "\033[0;33m"
+	ansiRed    = "\033[0;31m"
+)
+
+// planSymbols are the symbols and colors of the actions of a plan.
+var planSymbols = map[hubfile.Action]struct{ symbol, color string }{
+	hubfile.ActionCreate: {"+", ansiGreen},
+	hubfile.ActionUpdate: {"~", ansiYellow},
+	hubfile.ActionDelete: {"-", ansiRed},
+}
+
+type PlanFormater struct {
+	Plan *hubfile.Plan
+
+	// Color highlights the output with ANSI escape sequences.
+	Color bool
+}
+
+var _ Writer = (*PlanFormater)(nil)
+
+// WriteTable writes a line per change, followed by the differences of the
+// updated resources, and a summary of the number of changes per action.
+func (pf *PlanFormater) WriteTable(out io.Writer) error {
+	var b strings.Builder
+
+	counts := make(map[hubfile.Action]int)
+	for _, ch := range pf.Plan.Changes {
+		counts[ch.Action]++
+
+		s := planSymbols[ch.Action]
+		fmt.Fprintf(&b, "  %s %s %s %s\n", pf.colorize(s.color, s.symbol), ch.Kind, ch.ID, ch.Name)
+
+		if ch.Action == hubfile.ActionUpdate {
+			b.WriteString(pf.diff(ch.Current, ch.Desired))
+		}
+	}
+
+	if len(pf.Plan.Changes) > 0 {
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "%s %s to create, %s to update, %s to delete.\n",
+		pf.colorize(ansiBold, "Plan:"),
+		pf.colorize(ansiGreen, fmt.Sprint(counts[hubfile.ActionCreate])),
+		pf.colorize(ansiYellow, fmt.Sprint(counts[hubfile.ActionUpdate])),
+		pf.colorize(ansiRed, fmt.Sprint(counts[hubfile.ActionDelete])),
+	)
+
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// WriteJSON writes the changes, with the current and desired resources.
+func (pf *PlanFormater) WriteJSON(out io.Writer) error {
+	type change struct {
+		Action  hubfile.Action  `json:"action"`
+		Kind    hubfile.Kind    `json:"kind"`
+		ID      string          `json:"id"`
+		Name    string          `json:"name,omitempty"`
+		Current json.RawMessage `json:"current,omitempty"`
+		Desired json.RawMessage `json:"desired,omitempty"`
+	}
+
+	changes := make([]change, 0, len(pf.Plan.Changes))
+	for _, ch := range pf.Plan.Changes {
+		changes = append(changes, change(ch))
+	}
+
+	return json.NewEncoder(out).Encode(changes)
+}
+
+// diff returns the indented differences between two versions of a resource.
+func (pf *PlanFormater) diff(current, desired json.RawMessage) string {
+	opts := jsondiff.DefaultConsoleOptions()
+	if !pf.Color {
+		opts.Added = jsondiff.Tag{Begin: "+", End: ""}
+		opts.Removed = jsondiff.Tag{Begin: "-", End: ""}
+		opts.Changed = jsondiff.Tag{}
+		opts.Skipped = jsondiff.Tag{}
+	}
+	opts.SkipMatches = true
+
+	_, diff := jsondiff.Compare(current, desired, &opts)
+
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+		b.WriteString("      " + line + "\n")
+	}
+
+	return b.String()
+}
+
+// colorize wraps the text in the color escape sequence, if the output is colorized.
+func (pf *PlanFormater) colorize(color, s string) string {
+	if !pf.Color {
+		return s
+	}
+	return color + s + ansiReset
+}
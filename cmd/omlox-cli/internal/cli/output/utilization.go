@@ -0,0 +1,93 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/wavecomtech/omlox-client-go/analytics"
+)
+
+type UtilizationFormater struct {
+	Stats []analytics.FenceUtilization
+}
+
+var (
+	_ Writer    = (*UtilizationFormater)(nil)
+	_ CSVWriter = (*UtilizationFormater)(nil)
+)
+
+func (uf *UtilizationFormater) WriteTable(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 10, 1, 3, ' ', 0)
+
+	header := "%v\t%s\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t\n"
+	if _, err := fmt.Fprintf(w, header, "FENCE", "NAME", "VISITS", "AVG", "MAX", "DWELL MEAN", "DWELL P50", "DWELL P90", "DWELL MAX"); err != nil {
+		return err
+	}
+
+	format := "%v\t%s\t%v\t%.2f\t%v\t%v\t%v\t%v\t%v\t\n"
+	for _, s := range uf.Stats {
+		d := s.Dwell
+		if _, err := fmt.Fprintf(w, format,
+			s.FenceID, s.Name, s.Visits, s.AvgOccupancy, s.MaxOccupancy,
+			roundDuration(d.Mean), roundDuration(d.Median), roundDuration(d.P90), roundDuration(d.Max),
+		); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+func (uf *UtilizationFormater) WriteJSON(out io.Writer) error {
+	return json.NewEncoder(out).Encode(uf.Stats)
+}
+
+// WriteCSV writes one row per fence, with dwell times in seconds.
+func (uf *UtilizationFormater) WriteCSV(out io.Writer) error {
+	w := csv.NewWriter(out)
+
+	header := []string{"fence_id", "name", "visits", "avg_occupancy", "max_occupancy", "dwell_count", "dwell_mean", "dwell_median", "dwell_p90", "dwell_max"}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	for _, s := range uf.Stats {
+		d := s.Dwell
+		record := []string{
+			s.FenceID.String(),
+			s.Name,
+			strconv.Itoa(s.Visits),
+			strconv.FormatFloat(s.AvgOccupancy, 'f', -1, 64),
+			strconv.Itoa(s.MaxOccupancy),
+			strconv.Itoa(d.Count),
+			seconds(d.Mean),
+			seconds(d.Median),
+			seconds(d.P90),
+			seconds(d.Max),
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// roundDuration rounds a duration to seconds for human-readable output.
+func roundDuration(d time.Duration) time.Duration {
+	return d.Round(time.Second)
+}
+
+// seconds formats a duration as a decimal number of seconds.
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+}
@@ -0,0 +1,122 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package progress reports the progress of long CLI operations as structured
+// records, so CI systems and wrappers can display it without parsing the human
+// output of the commands.
+package progress
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Format is the format of the progress records.
+type Format string
+
+const (
+	// None disables the progress records.
+	None Format = "none"
+
+	// JSON writes a JSON object per line for each record.
+	JSON Format = "json"
+)
+
+// Formats returns a list of the string representation of the supported formats.
+func Formats() []string {
+	return []string{None.String(), JSON.String()}
+}
+
+// String returns the string representation of the Format.
+func (f Format) String() string {
+	return string(f)
+}
+
+// ParseFormat takes a raw string and returns the matching Format.
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case None, "":
+		return None, nil
+	case JSON:
+		return JSON, nil
+	}
+	return "", fmt.Errorf("invalid progress format %q: expected one of %v", s, Formats())
+}
+
+// Record is the progress of a stage of an operation.
+type Record struct {
+	// Time the record was emitted.
+	Time time.Time `json:"time"`
+
+	// Stage of the operation, such as "create trackables".
+	Stage string `json:"stage"`
+
+	// Done is the number of items processed, including the failed ones.
+	Done int `json:"done"`
+
+	// Total is the number of items of the stage, zero if unknown.
+	Total int `json:"total"`
+
+	// Errors is the number of items which failed.
+	Errors int `json:"errors"`
+
+	// Error is the error of the last failed item, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// Reporter emits the progress records of an operation. The zero Reporter, and
+// a nil one, discard the records.
+type Reporter struct {
+	mu     sync.Mutex
+	enc    *json.Encoder
+	record Record
+}
+
+// New returns a reporter writing the records in the format to w.
+func New(format Format, w io.Writer) *Reporter {
+	if format != JSON {
+		return &Reporter{}
+	}
+	return &Reporter{enc: json.NewEncoder(w)}
+}
+
+// Start starts a stage of total items, emitting its first record.
+func (r *Reporter) Start(stage string, total int) {
+	if r == nil || r.enc == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.record = Record{Stage: stage, Total: total}
+	r.emit()
+}
+
+// Step records an item of the current stage as processed, failed if err is
+// not nil, emitting a record.
+func (r *Reporter) Step(err error) {
+	if r == nil || r.enc == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.record.Done++
+	r.record.Error = ""
+	if err != nil {
+		r.record.Errors++
+		r.record.Error = err.Error()
+	}
+	r.emit()
+}
+
+func (r *Reporter) emit() {
+	r.record.Time = time.Now().UTC()
+	// progress is best effort: failing to report it doesn't fail the operation
+	_ = r.enc.Encode(r.record)
+}
@@ -0,0 +1,62 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package progress
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestReporter(t *testing.T) {
+	var buf bytes.Buffer
+
+	r := New(JSON, &buf)
+	r.Start("create trackables", 2)
+	r.Step(nil)
+	r.Step(errors.New("conflict"))
+
+	var records []Record
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var rec Record
+		if err := dec.Decode(&rec); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		records = append(records, rec)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(records))
+	}
+
+	last := records[2]
+	if last.Stage != "create trackables" || last.Done != 2 || last.Total != 2 || last.Errors != 1 || last.Error != "conflict" {
+		t.Errorf("unexpected last record %+v", last)
+	}
+	if records[1].Error != "" || records[1].Errors != 0 {
+		t.Errorf("unexpected error in record %+v", records[1])
+	}
+}
+
+func TestReporterDisabled(t *testing.T) {
+	var buf bytes.Buffer
+
+	r := New(None, &buf)
+	r.Start("apply", 1)
+	r.Step(nil)
+
+	var nilReporter *Reporter
+	nilReporter.Start("apply", 1)
+	nilReporter.Step(nil)
+
+	if buf.Len() != 0 {
+		t.Errorf("expected no records, got %q", buf.String())
+	}
+
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Errorf("expected error for unknown format")
+	}
+}
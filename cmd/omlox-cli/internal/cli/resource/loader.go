@@ -0,0 +1,105 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package resource
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+type Loader[T any] struct {
+	Resources []T
+
+	// Variables, if not nil, are substituted in the content before decoding it.
+	Variables *Variables
+
+	// Key is the field identifying the resources patched by overlays. Default: "id"
+	Key string
+}
+
+const (
+	tokenArrayStart = '['
+)
+
+// LoadJSON decode the provider reader stream in json format.
+// The content can be an array or single object.
+func (loader *Loader[T]) LoadJSON(r io.Reader) error {
+	content, err := loader.read(r)
+	if err != nil {
+		return err
+	}
+
+	resources, err := decodeJSON[T](content)
+	if err != nil {
+		return err
+	}
+
+	loader.Resources = append(loader.Resources, resources...)
+	return nil
+}
+
+// LoadDir decodes the json files of a directory, in the order of their names.
+func (loader *Loader[T]) LoadDir(dir string) error {
+	names, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if err := loader.LoadFile(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LoadFile decodes the named json file.
+func (loader *Loader[T]) LoadFile(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := loader.LoadJSON(f); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// read reads the content of r, with its variables substituted.
+func (loader *Loader[T]) read(r io.Reader) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(r); err != nil {
+		return nil, err
+	}
+
+	if loader.Variables == nil {
+		return buf.Bytes(), nil
+	}
+	return loader.Variables.Expand(buf.Bytes())
+}
+
+// decodeJSON decodes a json array or single object.
+func decodeJSON[T any](content []byte) ([]T, error) {
+	if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && trimmed[0] == tokenArrayStart {
+		var resources []T
+		if err := json.Unmarshal(content, &resources); err != nil {
+			return nil, err
+		}
+		return resources, nil
+	}
+
+	var resource T
+	if err := json.Unmarshal(content, &resource); err != nil {
+		return nil, err
+	}
+
+	return []T{resource}, nil
+}
@@ -0,0 +1,129 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+)
+
+// defaultKey is the field identifying the resources patched by overlays.
+const defaultKey = "id"
+
+// Patch merges the overlay patches of r, in json format, into the loaded
+// resources, so site-specific tweaks don't require duplicating whole definitions.
+// The content can be an array or single object.
+//
+// Each patch is merged into the resource with the same key (see Loader.Key),
+// as a JSON merge patch (RFC 7396): objects are merged recursively, null
+// removes a field and other values replace the field of the resource.
+func (loader *Loader[T]) Patch(r io.Reader) error {
+	content, err := loader.read(r)
+	if err != nil {
+		return err
+	}
+
+	patches, err := decodeJSON[map[string]any](content)
+	if err != nil {
+		return err
+	}
+
+	for _, patch := range patches {
+		if err := loader.patch(patch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PatchFile merges the overlay patches of the named file into the loaded resources.
+func (loader *Loader[T]) PatchFile(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := loader.Patch(f); err != nil {
+		return fmt.Errorf("overlay %s: %w", name, err)
+	}
+	return nil
+}
+
+func (loader *Loader[T]) patch(patch map[string]any) error {
+	key := loader.Key
+	if key == "" {
+		key = defaultKey
+	}
+
+	id, ok := patch[key]
+	if !ok {
+		return fmt.Errorf("patch without %s", key)
+	}
+
+	for i, resource := range loader.Resources {
+		target, err := toMap(resource)
+		if err != nil {
+			return err
+		}
+		if !reflect.DeepEqual(target[key], id) {
+			continue
+		}
+
+		data, err := json.Marshal(mergePatch(target, patch))
+		if err != nil {
+			return err
+		}
+
+		var patched T
+		if err := json.Unmarshal(data, &patched); err != nil {
+			return fmt.Errorf("patch of %s %v: %w", key, id, err)
+		}
+		loader.Resources[i] = patched
+
+		return nil
+	}
+
+	return fmt.Errorf("patch of %s %v: no such resource", key, id)
+}
+
+// toMap converts a resource to its generic json representation.
+func toMap(resource any) (map[string]any, error) {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// mergePatch applies a JSON merge patch to the target.
+func mergePatch(target, patch map[string]any) map[string]any {
+	if target == nil {
+		target = make(map[string]any, len(patch))
+	}
+
+	for k, v := range patch {
+		switch v := v.(type) {
+		case nil:
+			delete(target, k)
+		case map[string]any:
+			t, _ := target[k].(map[string]any)
+			target[k] = mergePatch(t, v)
+		default:
+			target[k] = v
+		}
+	}
+
+	return target
+}
@@ -0,0 +1,71 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package resource
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testResource struct {
+	ID         string         `json:"id"`
+	Name       string         `json:"name"`
+	Radius     float64        `json:"radius,omitempty"`
+	Properties map[string]any `json:"properties,omitempty"`
+}
+
+func TestLoaderPatch(t *testing.T) {
+	loader := Loader[testResource]{}
+
+	base := `[
+		{"id": "a", "name": "hall", "properties": {"site": "base", "floor": 1}},
+		{"id": "b", "name": "dock", "radius": 2}
+	]`
+	if err := loader.LoadJSON(strings.NewReader(base)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	overlay := `[
+		{"id": "a", "properties": {"site": "plant-1", "floor": null}},
+		{"id": "b", "radius": 5}
+	]`
+	if err := loader.Patch(strings.NewReader(overlay)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []testResource{
+		{ID: "a", Name: "hall", Properties: map[string]any{"site": "plant-1"}},
+		{ID: "b", Name: "dock", Radius: 5},
+	}
+	if !reflect.DeepEqual(loader.Resources, want) {
+		t.Errorf("expected %+v, got %+v", want, loader.Resources)
+	}
+
+	if err := loader.Patch(strings.NewReader(`{"id": "c", "name": "yard"}`)); err == nil {
+		t.Errorf("expected error patching an unknown resource")
+	}
+	if err := loader.Patch(strings.NewReader(`{"name": "yard"}`)); err == nil {
+		t.Errorf("expected error patching without id")
+	}
+}
+
+func TestLoaderPatchKey(t *testing.T) {
+	type location struct {
+		ProviderID string `json:"provider_id"`
+		Floor      int    `json:"floor"`
+	}
+
+	loader := Loader[location]{Key: "provider_id"}
+	if err := loader.LoadJSON(strings.NewReader(`{"provider_id": "tag-1", "floor": 1}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := loader.Patch(strings.NewReader(`{"provider_id": "tag-1", "floor": 2}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := loader.Resources[0]; got.Floor != 2 {
+		t.Errorf("expected patched floor, got %+v", got)
+	}
+}
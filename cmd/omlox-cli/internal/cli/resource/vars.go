@@ -0,0 +1,89 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package resource
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// variablePattern matches the "${NAME}" and "${NAME:-default}" references, and
+// the "$${" escape of a literal "${".
+var variablePattern = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// Variables substitutes the "${NAME}" references of resource files, so the same
+// definitions can be loaded with different values (e.g. for staging and production).
+//
+// Values given with Set take precedence over the environment. References to
+// undefined variables fail, unless they give a default as in "${NAME:-default}".
+// A literal "${" is written "$${".
+type Variables struct {
+	// Set are the variables given explicitly, such as with --set name=value.
+	Set map[string]string
+
+	// Lookup looks up the other variables. Default: os.LookupEnv
+	Lookup func(name string) (string, bool)
+}
+
+// ParseVariables parses variables given as "name=value".
+func ParseVariables(pairs []string) (*Variables, error) {
+	vars := &Variables{Set: make(map[string]string, len(pairs))}
+
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid variable %q: expected name=value", pair)
+		}
+		vars.Set[name] = value
+	}
+
+	return vars, nil
+}
+
+// Expand substitutes the variable references of the content.
+func (v *Variables) Expand(content []byte) ([]byte, error) {
+	lookup := v.Lookup
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+
+	undefined := make(map[string]bool)
+
+	expanded := variablePattern.ReplaceAllFunc(content, func(ref []byte) []byte {
+		if string(ref) == "$${" {
+			return []byte("${")
+		}
+
+		m := variablePattern.FindSubmatch(ref)
+		name := string(m[1])
+
+		if value, ok := v.Set[name]; ok {
+			return []byte(value)
+		}
+		if value, ok := lookup(name); ok {
+			return []byte(value)
+		}
+		if m[2] != nil {
+			return m[3]
+		}
+
+		undefined[name] = true
+		return ref
+	})
+
+	if len(undefined) > 0 {
+		names := make([]string, 0, len(undefined))
+		for name := range undefined {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		return nil, fmt.Errorf("undefined variables: %s", strings.Join(names, ", "))
+	}
+
+	return expanded, nil
+}
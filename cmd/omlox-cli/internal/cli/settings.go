@@ -0,0 +1,128 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/pflag"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/config"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/output"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/progress"
+)
+
+const (
+	DefaultOmloxHubAPI = "localhost:8081" // Flowcate's Deephub default API endpoint
+)
+
+type EnvSettings struct {
+	// Omlox Hub API endpoint.
+	OmloxHubAPI string
+
+	// Debug indicates whether or not the Omlox Client is running in Debug mode.
+	Debug bool
+
+	// ConfigFile is the configuration file of the CLI.
+	ConfigFile string
+
+	// Token authenticating with the Omlox Hub, possibly encrypted.
+	Token config.Secret
+
+	// TokenURL is the OAuth2 token endpoint refreshing the tokens with the
+	// client credentials, instead of the fixed Token.
+	TokenURL string
+
+	// Client credentials of the token endpoint, the secret possibly encrypted.
+	ClientID     string
+	ClientSecret config.Secret
+
+	// TimeFormat, Timezone and Units localize the human-readable output.
+	TimeFormat string
+	Timezone   string
+	Units      string
+
+	// Progress is the format of the progress records of long operations.
+	Progress string
+}
+
+// New creates a new environment settings loading the environment variables.
+func New() *EnvSettings {
+	env := &EnvSettings{
+		OmloxHubAPI: envOr("OMLOX_HUB_API", DefaultOmloxHubAPI),
+		ConfigFile:  envOr("OMLOX_CONFIG", config.DefaultFile()),
+		Token:       config.Secret(os.Getenv("OMLOX_HUB_TOKEN")),
+
+		TokenURL:     os.Getenv("OMLOX_HUB_TOKEN_URL"),
+		ClientID:     os.Getenv("OMLOX_HUB_CLIENT_ID"),
+		ClientSecret: config.Secret(os.Getenv("OMLOX_HUB_CLIENT_SECRET")),
+
+		TimeFormat: "rfc3339",
+		Timezone:   "Local",
+		Units:      output.Meters.String(),
+
+		Progress: progress.None.String(),
+	}
+
+	return env
+}
+
+// LoadConfig loads the configuration file, for the settings not given by the
+// environment variables.
+func (s *EnvSettings) LoadConfig() error {
+	c, err := config.Load(s.ConfigFile)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := os.LookupEnv("OMLOX_HUB_API"); !ok && c.Addr != "" {
+		s.OmloxHubAPI = c.Addr
+	}
+	if s.Token == "" {
+		s.Token = c.Token
+	}
+	if s.TokenURL == "" {
+		s.TokenURL = c.TokenURL
+	}
+	if s.ClientID == "" {
+		s.ClientID = c.ClientID
+	}
+	if s.ClientSecret == "" {
+		s.ClientSecret = c.ClientSecret
+	}
+
+	return nil
+}
+
+func (s *EnvSettings) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.OmloxHubAPI, "addr", s.OmloxHubAPI, "omlox hub API endpoint")
+	fs.BoolVar(&s.Debug, "debug", s.Debug, "enable debug logging")
+	fs.StringVar(&s.TimeFormat, "time-format", s.TimeFormat, "format of the output timestamps, one of [rfc3339 rfc1123 datetime date kitchen] or a Go time layout")
+	fs.StringVar(&s.Timezone, "timezone", s.Timezone, "time zone of the output timestamps, an IANA name such as Europe/Lisbon, Local or UTC")
+	fs.StringVar(&s.Units, "units", s.Units, fmt.Sprintf("unit of the output distances, one of %v", output.Units()))
+	fs.StringVar(&s.Progress, "progress", s.Progress, fmt.Sprintf("format of the progress records of long operations, written to the standard error, one of %v", progress.Formats()))
+}
+
+// ProgressReporter returns the reporter of the progress of long operations,
+// writing the records to w.
+func (s *EnvSettings) ProgressReporter(w io.Writer) (*progress.Reporter, error) {
+	format, err := progress.ParseFormat(s.Progress)
+	if err != nil {
+		return nil, err
+	}
+	return progress.New(format, w), nil
+}
+
+// Locale returns the locale of the human-readable output of the settings.
+func (s *EnvSettings) Locale() (output.Locale, error) {
+	return output.NewLocale(s.TimeFormat, s.Timezone, s.Units)
+}
+
+func envOr(name, def string) string {
+	if v, ok := os.LookupEnv(name); ok {
+		return v
+	}
+	return def
+}
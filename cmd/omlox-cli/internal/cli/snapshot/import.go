@@ -0,0 +1,341 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/progress"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/resource"
+)
+
+// ErrConflict is returned when importing a resource already in the Hub with
+// the Fail strategy.
+var ErrConflict = errors.New("resource already exists")
+
+// Strategy resolves the conflicts of the imported resources already in the Hub.
+type Strategy string
+
+// Defines values for Strategy.
+const (
+	// Skip keeps the resource of the Hub.
+	Skip Strategy = "skip"
+
+	// Overwrite replaces the resource of the Hub by the imported one.
+	Overwrite Strategy = "overwrite"
+
+	// Merge updates the resource of the Hub with the fields of the imported one,
+	// deep-merging their properties.
+	Merge Strategy = "merge"
+
+	// Fail stops the import.
+	Fail Strategy = "fail"
+)
+
+// Strategies returns a list of the string representation of the strategies.
+func Strategies() []string {
+	return []string{Skip.String(), Overwrite.String(), Merge.String(), Fail.String()}
+}
+
+// String returns the string representation of the Strategy.
+func (s Strategy) String() string {
+	return string(s)
+}
+
+// ParseStrategy takes a raw string and returns the matching Strategy.
+func ParseStrategy(s string) (Strategy, error) {
+	switch Strategy(s) {
+	case Skip, Overwrite, Merge, Fail:
+		return Strategy(s), nil
+	}
+	return "", fmt.Errorf("invalid conflict strategy %q: expected one of %v", s, Strategies())
+}
+
+// Outcome is the outcome of the import of a resource.
+type Outcome string
+
+// Defines values for Outcome.
+const (
+	Created     Outcome = "created"
+	Skipped     Outcome = "skipped"
+	Overwritten Outcome = "overwritten"
+	Merged      Outcome = "merged"
+	Failed      Outcome = "failed"
+)
+
+// Result is the result of the import of a resource.
+type Result struct {
+	Collection string  `json:"collection"`
+	ID         string  `json:"id"`
+	Name       string  `json:"name,omitempty"`
+	Outcome    Outcome `json:"outcome"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// Importer imports the resources of an export directory to a Hub.
+type Importer struct {
+	Client *omlox.Client
+
+	// Dir is the directory of the export.
+	Dir string
+
+	// OnConflict resolves the conflicts of the resources already in the Hub.
+	// Default: Fail
+	OnConflict Strategy
+
+	// Renames replace the prefixes of the names, and of the ids other than
+	// UUIDs, of the imported resources, so a template dataset can be imported
+	// several times. The first matching prefix applies.
+	Renames []Rename
+
+	// RegenerateIDs gives new ids to the imported resources with UUID ids.
+	RegenerateIDs bool
+
+	// Progress reports the imported resources of each collection, if not nil.
+	Progress *progress.Reporter
+}
+
+// Rename replaces the Old prefix of names and ids by the New one.
+type Rename struct {
+	Old string
+	New string
+}
+
+// ParseRename parses a rename given as old=new.
+func ParseRename(s string) (Rename, error) {
+	prefix, replacement, ok := strings.Cut(s, "=")
+	if !ok || prefix == "" {
+		return Rename{}, fmt.Errorf("invalid prefix rename %q: expected old=new", s)
+	}
+	return Rename{Old: prefix, New: replacement}, nil
+}
+
+// Import imports the collections of the directory, in dependency order,
+// returning the result of each imported resource. The import stops at the
+// first failed resource.
+func (im *Importer) Import(ctx context.Context) ([]Result, error) {
+	collections := make(map[string][]json.RawMessage, len(Collections))
+	for _, name := range Collections {
+		dir := filepath.Join(im.Dir, name)
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			continue
+		}
+
+		loader := resource.Loader[json.RawMessage]{}
+		if err := loader.LoadDir(dir); err != nil {
+			return nil, err
+		}
+		collections[name] = loader.Resources
+	}
+
+	if len(im.Renames) > 0 || im.RegenerateIDs {
+		if err := im.rewrite(collections); err != nil {
+			return nil, err
+		}
+	}
+
+	var results []Result
+	for _, name := range Collections {
+		resources, ok := collections[name]
+		if !ok {
+			continue
+		}
+
+		im.Progress.Start(name, len(resources))
+		for _, body := range resources {
+			r, err := im.importResource(ctx, name, body)
+			im.Progress.Step(err)
+			results = append(results, r)
+			if err != nil {
+				return results, fmt.Errorf("import %s %s: %w", name, r.ID, err)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+func (im *Importer) importResource(ctx context.Context, collection string, body json.RawMessage) (r Result, err error) {
+	var meta struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &meta); err != nil {
+		return Result{Collection: collection, Outcome: Failed, Error: err.Error()}, err
+	}
+
+	r = Result{Collection: collection, ID: meta.ID, Name: meta.Name}
+	defer func() {
+		if err != nil {
+			r.Outcome, r.Error = Failed, err.Error()
+		}
+	}()
+
+	path := "/" + collection
+	if meta.ID == "" {
+		r.Outcome = Created
+		return r, im.Client.Do(ctx, http.MethodPost, path, body, nil)
+	}
+	path += "/" + url.PathEscape(meta.ID)
+
+	var current map[string]any
+	err = im.Client.Do(ctx, http.MethodGet, path, nil, &current)
+	if errors.Is(err, omlox.ErrNotFound) {
+		r.Outcome = Created
+		return r, im.Client.Do(ctx, http.MethodPost, "/"+collection, body, nil)
+	}
+	if err != nil {
+		return r, err
+	}
+
+	switch im.OnConflict {
+	case Skip:
+		r.Outcome = Skipped
+		return r, nil
+	case Overwrite:
+		r.Outcome = Overwritten
+		return r, im.Client.Do(ctx, http.MethodPut, path, body, nil)
+	case Merge:
+		var imported map[string]any
+		if err := json.Unmarshal(body, &imported); err != nil {
+			return r, err
+		}
+
+		r.Outcome = Merged
+		return r, im.Client.Do(ctx, http.MethodPut, path, merge(current, imported), nil)
+	}
+
+	return r, ErrConflict
+}
+
+// rewrite renames the prefixes and regenerates the ids of the resources of all
+// the collections. The ids are replaced wherever they appear in the resources,
+// so the references between the imported resources follow them.
+func (im *Importer) rewrite(collections map[string][]json.RawMessage) error {
+	decoded := make(map[string][]map[string]any, len(collections))
+	ids := make(map[string]string)
+
+	for name, resources := range collections {
+		for _, body := range resources {
+			var r map[string]any
+			if err := json.Unmarshal(body, &r); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			decoded[name] = append(decoded[name], r)
+
+			id, _ := r["id"].(string)
+			if id == "" {
+				continue
+			}
+
+			newID := id
+			if _, err := uuid.Parse(id); err != nil {
+				newID = im.rename(id)
+			} else if im.RegenerateIDs {
+				newID = uuid.NewString()
+			}
+			if newID != id {
+				ids[id] = newID
+			}
+		}
+	}
+
+	for name, resources := range decoded {
+		for i, r := range resources {
+			if n, ok := r["name"].(string); ok {
+				r["name"] = im.rename(n)
+			}
+
+			data, err := json.Marshal(replaceIDs(r, ids))
+			if err != nil {
+				return err
+			}
+			collections[name][i] = data
+		}
+	}
+
+	return nil
+}
+
+// rename replaces the first matching prefix of s.
+func (im *Importer) rename(s string) string {
+	for _, r := range im.Renames {
+		if strings.HasPrefix(s, r.Old) {
+			return r.New + strings.TrimPrefix(s, r.Old)
+		}
+	}
+	return s
+}
+
+// replaceIDs replaces the strings of v which are ids by their new ids, recursively.
+func replaceIDs(v any, ids map[string]string) any {
+	switch v := v.(type) {
+	case string:
+		if id, ok := ids[v]; ok {
+			return id
+		}
+	case []any:
+		for i := range v {
+			v[i] = replaceIDs(v[i], ids)
+		}
+	case map[string]any:
+		for k := range v {
+			v[k] = replaceIDs(v[k], ids)
+		}
+	}
+	return v
+}
+
+// merge returns the current resource updated with the fields of the imported
+// one, the properties being deep-merged: the imported properties are added to
+// or replace the current ones, recursively, and the others are kept.
+func merge(current, imported map[string]any) map[string]any {
+	merged := make(map[string]any, len(current)+len(imported))
+	for k, v := range current {
+		merged[k] = v
+	}
+
+	for k, v := range imported {
+		if k == "properties" {
+			cp, _ := current[k].(map[string]any)
+			if ip, ok := v.(map[string]any); ok && cp != nil {
+				merged[k] = deepMerge(cp, ip)
+				continue
+			}
+		}
+		merged[k] = v
+	}
+
+	return merged
+}
+
+// deepMerge merges the src object into a copy of the dst object, recursively.
+func deepMerge(dst, src map[string]any) map[string]any {
+	merged := make(map[string]any, len(dst)+len(src))
+	for k, v := range dst {
+		merged[k] = v
+	}
+
+	for k, v := range src {
+		d, dok := merged[k].(map[string]any)
+		s, sok := v.(map[string]any)
+		if dok && sok {
+			merged[k] = deepMerge(d, s)
+			continue
+		}
+		merged[k] = v
+	}
+
+	return merged
+}
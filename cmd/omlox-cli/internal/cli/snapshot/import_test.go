@@ -0,0 +1,189 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+// storeHub stores the resources created and updated, by id.
+type storeHub struct {
+	mu         sync.Mutex
+	trackables map[string]map[string]any
+}
+
+func (h *storeHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, id, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	switch r.Method {
+	case http.MethodGet:
+		t, ok := h.trackables[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"type":"not found","code":404,"message":"no trackable"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(t)
+	case http.MethodPost, http.MethodPut:
+		var t map[string]any
+		json.NewDecoder(r.Body).Decode(&t)
+		h.trackables[t["id"].(string)] = t
+		json.NewEncoder(w).Encode(t)
+	}
+}
+
+func TestImport(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "trackables"), 0755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	page := `[
+		{"id": "a", "name": "forklift", "properties": {"team": "night", "specs": {"load": 2}}},
+		{"id": "b", "name": "pallet"}
+	]`
+	if err := os.WriteFile(filepath.Join(dir, "trackables", "page-00001.json"), []byte(page), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	newHub := func() *storeHub {
+		return &storeHub{trackables: map[string]map[string]any{
+			"a": {"id": "a", "name": "old", "radius": 1.0, "properties": map[string]any{"site": "plant-1", "specs": map[string]any{"height": 3.0}}},
+		}}
+	}
+
+	tests := []struct {
+		strategy Strategy
+		outcome  Outcome
+		want     map[string]any
+	}{
+		{
+			strategy: Skip,
+			outcome:  Skipped,
+			want:     map[string]any{"id": "a", "name": "old", "radius": 1.0, "properties": map[string]any{"site": "plant-1", "specs": map[string]any{"height": 3.0}}},
+		},
+		{
+			strategy: Overwrite,
+			outcome:  Overwritten,
+			want:     map[string]any{"id": "a", "name": "forklift", "properties": map[string]any{"team": "night", "specs": map[string]any{"load": 2.0}}},
+		},
+		{
+			strategy: Merge,
+			outcome:  Merged,
+			want: map[string]any{"id": "a", "name": "forklift", "radius": 1.0, "properties": map[string]any{
+				"site": "plant-1", "team": "night", "specs": map[string]any{"height": 3.0, "load": 2.0},
+			}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.strategy.String(), func(t *testing.T) {
+			hub := newHub()
+			srv := httptest.NewServer(hub)
+			defer srv.Close()
+
+			c, err := omlox.New(srv.URL)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			im := &Importer{Client: c, Dir: dir, OnConflict: tc.strategy}
+			results, err := im.Import(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(results) != 2 || results[0].Outcome != tc.outcome || results[1].Outcome != Created {
+				t.Errorf("unexpected results %+v", results)
+			}
+			if got := hub.trackables["a"]; !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+
+	t.Run("fail", func(t *testing.T) {
+		srv := httptest.NewServer(newHub())
+		defer srv.Close()
+
+		c, err := omlox.New(srv.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		results, err := (&Importer{Client: c, Dir: dir}).Import(context.Background())
+		if !errors.Is(err, ErrConflict) {
+			t.Fatalf("expected conflict, got %v", err)
+		}
+		if len(results) != 1 || results[0].Outcome != Failed {
+			t.Errorf("unexpected results %+v", results)
+		}
+	})
+}
+
+func TestImportRewrite(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"providers":  `[{"id": "line1-tag", "name": "line1-tag", "type": "uwb"}]`,
+		"trackables": `[{"id": "d27047bd-1b6b-4656-bb93-2326a4c900e1", "name": "line1-forklift", "location_providers": ["line1-tag"]}]`,
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name, "page-00001.json"), []byte(content), 0644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	hub := &storeHub{trackables: make(map[string]map[string]any)}
+	srv := httptest.NewServer(hub)
+	defer srv.Close()
+
+	c, err := omlox.New(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rename, err := ParseRename("line1-=line2-")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	im := &Importer{Client: c, Dir: dir, Renames: []Rename{rename}, RegenerateIDs: true}
+	results, err := im.Import(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 || results[0].ID != "line2-tag" {
+		t.Fatalf("unexpected results %+v", results)
+	}
+
+	id := results[1].ID
+	if id == "d27047bd-1b6b-4656-bb93-2326a4c900e1" {
+		t.Errorf("expected regenerated trackable id")
+	}
+
+	want := map[string]any{"id": id, "name": "line2-forklift", "location_providers": []any{"line2-tag"}}
+	if got := hub.trackables[id]; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	if _, err := ParseRename("line1-"); err == nil {
+		t.Errorf("expected error without new prefix")
+	}
+}
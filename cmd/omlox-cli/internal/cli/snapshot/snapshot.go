@@ -0,0 +1,290 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package snapshot exports all the resources of a Hub to a directory, page by
+// page, checkpointing the exported pages so an interrupted export is resumed
+// instead of restarted from zero, and imports them back.
+//
+// The resources of each collection are written to a directory named after it,
+// in pages of JSON arrays, so they can also be loaded back with the -f flag of
+// the create commands:
+//
+//	export/
+//	  zones/page-00001.json
+//	  providers/page-00001.json
+//	  ...
+//
+// The checkpoint is kept in the .checkpoint directory until the export completes.
+package snapshot
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/progress"
+	"golang.org/x/sync/errgroup"
+)
+
+// ErrNoCheckpoint is returned when resuming an export without checkpoint.
+var ErrNoCheckpoint = errors.New("no export to resume")
+
+// ErrInProgress is returned when starting an export over an interrupted one.
+var ErrInProgress = errors.New("an interrupted export is in the directory")
+
+// Collections are the exported collections, in dependency order.
+var Collections = []string{"zones", "providers", "fences", "trackables"}
+
+const (
+	defaultPageSize    = 500
+	defaultConcurrency = 4
+
+	checkpointDir  = ".checkpoint"
+	checkpointFile = "checkpoint.json"
+)
+
+// Exporter exports the resources of a Hub to a directory.
+type Exporter struct {
+	Client *omlox.Client
+
+	// Dir is the directory of the export.
+	Dir string
+
+	// PageSize is the number of resources per page. Default: 500
+	PageSize int
+
+	// Concurrency is the number of resources fetched concurrently. Default: 4
+	Concurrency int
+
+	// Progress reports the exported pages of each collection, if not nil.
+	Progress *progress.Reporter
+}
+
+// checkpoint records the exported pages of each collection.
+type checkpoint struct {
+	PageSize    int                            `json:"page_size"`
+	Collections map[string]*collectionProgress `json:"collections"`
+}
+
+type collectionProgress struct {
+	// Listed is true once the ids of the collection are saved.
+	Listed bool `json:"listed"`
+
+	// Pages is the number of pages of the collection.
+	Pages int `json:"pages"`
+
+	// Done is the number of pages exported, in order.
+	Done int `json:"done"`
+}
+
+// Export exports all the collections, resuming the interrupted export of the
+// directory if resume is true.
+func (e *Exporter) Export(ctx context.Context, resume bool) error {
+	cp, err := e.loadCheckpoint()
+	switch {
+	case resume && errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("%w in %s", ErrNoCheckpoint, e.Dir)
+	case !resume && err == nil:
+		return fmt.Errorf("%w %s: resume it or remove it", ErrInProgress, e.Dir)
+	case !resume:
+		cp = &checkpoint{PageSize: e.pageSize(), Collections: make(map[string]*collectionProgress)}
+	case err != nil:
+		return err
+	}
+
+	// resumed exports keep their page size, so their pages stay consistent
+	e.PageSize = cp.PageSize
+
+	for _, name := range Collections {
+		if err := e.exportCollection(ctx, cp, name); err != nil {
+			return fmt.Errorf("export %s: %w", name, err)
+		}
+	}
+
+	return os.RemoveAll(filepath.Join(e.Dir, checkpointDir))
+}
+
+func (e *Exporter) exportCollection(ctx context.Context, cp *checkpoint, name string) error {
+	state := cp.Collections[name]
+	if state == nil {
+		state = &collectionProgress{}
+		cp.Collections[name] = state
+	}
+
+	// the ids are listed once, so the pages are the same when resuming
+	if !state.Listed {
+		var ids []string
+		if err := e.Client.Do(ctx, http.MethodGet, "/"+name, nil, &ids); err != nil {
+			return err
+		}
+		if err := e.saveIDs(name, ids); err != nil {
+			return err
+		}
+
+		state.Listed = true
+		state.Pages = (len(ids) + e.PageSize - 1) / e.PageSize
+		if err := e.saveCheckpoint(cp); err != nil {
+			return err
+		}
+	}
+
+	ids, err := e.loadIDs(name)
+	if err != nil {
+		return err
+	}
+
+	e.Progress.Start(name, state.Pages)
+	for i := 0; i < state.Done; i++ {
+		e.Progress.Step(nil)
+	}
+
+	// the deadline of the export is split across the resources left to fetch, so a
+	// slow resource does not use the time of the others
+	budget := omlox.NewBudget(ctx, len(ids)-min(state.Done*e.PageSize, len(ids)), e.concurrency())
+
+	for state.Done < state.Pages {
+		start := state.Done * e.PageSize
+		end := min(start+e.PageSize, len(ids))
+
+		err := e.exportPage(ctx, budget, name, state.Done+1, ids[start:end])
+		e.Progress.Step(err)
+		if err != nil {
+			return err
+		}
+
+		state.Done++
+		if err := e.saveCheckpoint(cp); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exportPage fetches the resources of a page concurrently, each within its share of
+// the budget, and writes them.
+func (e *Exporter) exportPage(ctx context.Context, budget *omlox.Budget, name string, page int, ids []string) error {
+	resources := make([]json.RawMessage, len(ids))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(e.concurrency())
+
+	for i, id := range ids {
+		i, id := i, id
+		g.Go(func() error {
+			rctx, cancel := budget.Next(gctx)
+			defer cancel()
+
+			err := e.Client.Do(rctx, http.MethodGet, "/"+name+"/"+url.PathEscape(id), nil, &resources[i])
+			// resources deleted since the listing are skipped
+			if errors.Is(err, omlox.ErrNotFound) {
+				return nil
+			}
+			return err
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	items := make([]json.RawMessage, 0, len(resources))
+	for _, r := range resources {
+		if r != nil {
+			items = append(items, r)
+		}
+	}
+
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFile(filepath.Join(e.Dir, name, fmt.Sprintf("page-%05d.json", page)), data)
+}
+
+func (e *Exporter) pageSize() int {
+	if e.PageSize <= 0 {
+		return defaultPageSize
+	}
+	return e.PageSize
+}
+
+func (e *Exporter) concurrency() int {
+	if e.Concurrency <= 0 {
+		return defaultConcurrency
+	}
+	return e.Concurrency
+}
+
+func (e *Exporter) loadCheckpoint() (*checkpoint, error) {
+	data, err := os.ReadFile(filepath.Join(e.Dir, checkpointDir, checkpointFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var cp checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint: %w", err)
+	}
+	if cp.Collections == nil {
+		cp.Collections = make(map[string]*collectionProgress)
+	}
+
+	return &cp, nil
+}
+
+func (e *Exporter) saveCheckpoint(cp *checkpoint) error {
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(e.Dir, checkpointDir, checkpointFile), data)
+}
+
+// saveIDs saves the ids of a collection, one per line.
+func (e *Exporter) saveIDs(name string, ids []string) error {
+	return writeFile(filepath.Join(e.Dir, checkpointDir, name+".ids"), []byte(strings.Join(ids, "\n")))
+}
+
+func (e *Exporter) loadIDs(name string) ([]string, error) {
+	f, err := os.Open(filepath.Join(e.Dir, checkpointDir, name+".ids"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var ids []string
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if id := s.Text(); id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, s.Err()
+}
+
+// writeFile writes a file atomically, so an interrupted export never leaves a
+// truncated page or checkpoint behind.
+func writeFile(name string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, name)
+}
@@ -0,0 +1,108 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+// testHub serves collections of resources with numbered ids, failing the
+// requests of the resource named by fail.
+type testHub struct {
+	mu      sync.Mutex
+	fail    string
+	fetched map[string]int
+}
+
+func (h *testHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if len(parts) == 1 {
+		var ids []string
+		if parts[0] == "trackables" {
+			for i := 0; i < 5; i++ {
+				ids = append(ids, fmt.Sprintf("t%d", i))
+			}
+		}
+		json.NewEncoder(w).Encode(ids)
+		return
+	}
+
+	id := parts[1]
+	if id == h.fail {
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	if id == "t3" {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"type":"not found","code":404,"message":"deleted"}`))
+		return
+	}
+
+	h.fetched[id]++
+	json.NewEncoder(w).Encode(map[string]string{"id": id})
+}
+
+func TestExportResume(t *testing.T) {
+	hub := &testHub{fail: "t4", fetched: make(map[string]int)}
+	srv := httptest.NewServer(hub)
+	defer srv.Close()
+
+	c, err := omlox.New(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dir := t.TempDir()
+	e := &Exporter{Client: c, Dir: dir, PageSize: 2}
+
+	if err := e.Export(context.Background(), false); err == nil {
+		t.Fatalf("expected error of the failing resource")
+	}
+	if err := e.Export(context.Background(), false); !errors.Is(err, ErrInProgress) {
+		t.Fatalf("expected interrupted export, got %v", err)
+	}
+
+	hub.fail = ""
+	if err := e.Export(context.Background(), true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the pages exported before the interruption are not fetched again
+	for _, id := range []string{"t0", "t1", "t2", "t4"} {
+		if hub.fetched[id] != 1 {
+			t.Errorf("expected %s fetched once, got %d", id, hub.fetched[id])
+		}
+	}
+
+	var last []map[string]string
+	data, err := os.ReadFile(filepath.Join(dir, "trackables", "page-00003.json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := json.Unmarshal(data, &last); err != nil || len(last) != 1 || last[0]["id"] != "t4" {
+		t.Errorf("unexpected last page %s (%v)", data, err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, checkpointDir)); !os.IsNotExist(err) {
+		t.Errorf("expected checkpoint removed, got %v", err)
+	}
+	if err := e.Export(context.Background(), true); !errors.Is(err, ErrNoCheckpoint) {
+		t.Errorf("expected no checkpoint, got %v", err)
+	}
+}
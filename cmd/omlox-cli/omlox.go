@@ -33,6 +33,9 @@ func main() {
 	}
 
 	if err := cmd.Execute(); err != nil {
+		if hint := errorHint(err); hint != "" {
+			fmt.Fprintf(os.Stderr, "Hint: %s\n", hint)
+		}
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,60 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/spf13/cobra"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/output"
+)
+
+const planHelp = `
+This command shows the changes the apply command would make to the Omlox Hub
+for a hubfile, without applying them:
+
+  + create  resources of the hubfile missing from the Hub
+  ~ update  resources differing from the Hub, with their differences
+  - delete  resources managed by the hubfile owner missing from the hubfile,
+            with --prune
+`
+
+func newPlanCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	var (
+		opts   hubfileOptions
+		format string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Show the changes of applying a hubfile to the hub",
+		Long:  planHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o, err := output.ParseFormat(format)
+			if err != nil || !slices.Contains(output.Formats(), format) {
+				return output.ErrInvalidFormatType
+			}
+
+			_, plan, err := opts.plan(context.Background(), cmd, &settings)
+			if err != nil {
+				return err
+			}
+
+			return o.Write(out, &output.PlanFormater{Plan: plan, Color: colorEnabled(out, opts.noColor)})
+		},
+	}
+
+	f := cmd.Flags()
+	opts.addFlags(f)
+	f.StringVarP(&format, "output", "o", output.Table.String(), fmt.Sprintf("Output format. One of: %v.", output.Formats()))
+
+	cmd.MarkFlagRequired("file")
+
+	return cmd
+}
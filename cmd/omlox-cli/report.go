@@ -0,0 +1,25 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+)
+
+func newReportCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Report statistics from hub history",
+	}
+
+	cmd.AddCommand(
+		newReportUtilizationCmd(settings, out),
+		newReportMovementCmd(settings, out),
+	)
+
+	return cmd
+}
@@ -0,0 +1,130 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wavecomtech/omlox-client-go/analytics"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/output"
+)
+
+const reportMovementHelp = `
+This command reports the movement statistics of trackables per period,
+computed from the location history and fence events of the Omlox Hub.
+The Hub must support the optional location history API.
+
+For each trackable and period, the distance traveled, the time spent
+moving and idle, and the number of fence crossings (entries and exits) are reported.
+
+Trackables can be selected by their custom properties, for example:
+
+    $ omlox report movement --selector team=forklifts --group-by day
+`
+
+func newReportMovementCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	var (
+		format    string
+		selection string
+		groupBy   string
+		from      string
+		to        string
+		idleSpeed float64
+		maxGap    time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "movement",
+		Short: "Reports trackables movement statistics",
+		Long:  reportMovementHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o, err := output.ParseFormat(format)
+			if err != nil {
+				return err
+			}
+
+			sel, err := parseSelector(selection)
+			if err != nil {
+				return err
+			}
+
+			var period analytics.Period
+			if err := period.FromString(groupBy); err != nil {
+				return err
+			}
+
+			locale, err := settings.Locale()
+			if err != nil {
+				return err
+			}
+
+			begin, end, err := parseTimeRange(from, to, locale.Location)
+			if err != nil {
+				return err
+			}
+
+			c, err := newOmloxClient(&settings)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+
+			trackables, err := c.Trackables.List(ctx)
+			if err != nil {
+				return err
+			}
+
+			events, err := c.History.FenceEvents(ctx, begin, end)
+			if err != nil {
+				return err
+			}
+
+			opts := analytics.MovementOptions{
+				Period:    period,
+				IdleSpeed: idleSpeed,
+				MaxGap:    maxGap,
+				Location:  locale.Location,
+			}
+
+			var movements []analytics.Movement
+			for _, t := range trackables {
+				if !sel.Matches(t.Properties) {
+					continue
+				}
+
+				locations, err := c.History.TrackableLocations(ctx, t.ID, begin, end)
+				if err != nil {
+					return err
+				}
+
+				m, err := analytics.Movements(t, locations, events, opts)
+				if err != nil {
+					return err
+				}
+
+				movements = append(movements, m...)
+			}
+
+			return o.Write(out, &output.MovementFormater{Movements: movements, Locale: locale})
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&format, "output", "o", output.Table.String(), fmt.Sprintf("Output format. One of: %v.", output.TabularFormats()))
+	f.StringVarP(&selection, "selector", "l", "", "Selector on trackable properties to filter on (e.g. team=forklifts,type!=manual)")
+	f.StringVar(&groupBy, "group-by", analytics.PeriodDay.String(), "Period to group statistics by. One of: [hour day week].")
+	f.StringVar(&from, "from", "", "Start of the time interval in RFC3339 format, or local to --timezone (default 24h before --to)")
+	f.StringVar(&to, "to", "", "End of the time interval in RFC3339 format, or local to --timezone (default now)")
+	f.Float64Var(&idleSpeed, "idle-speed", analytics.DefaultIdleSpeed, "Speed in meters per second below which a trackable is idle")
+	f.DurationVar(&maxGap, "max-gap", 5*time.Minute, "Maximum time between locations for a trackable to be considered tracked (0 for no limit)")
+
+	return cmd
+}
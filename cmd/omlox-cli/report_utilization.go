@@ -0,0 +1,98 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/analytics"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/output"
+)
+
+const reportUtilizationHelp = `
+This command reports the occupancy statistics of the fences of a zone,
+computed from the fence events of the Omlox Hub history.
+The Hub must support the optional location history API.
+
+For each fence, the number of visits, the average and maximum number of
+trackables inside the fence, and the distribution of dwell times are reported.
+`
+
+func newReportUtilizationCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	var (
+		format string
+		zone   string
+		from   string
+		to     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "utilization",
+		Short: "Reports fence occupancy statistics of a zone",
+		Long:  reportUtilizationHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o, err := output.ParseFormat(format)
+			if err != nil {
+				return err
+			}
+
+			locale, err := settings.Locale()
+			if err != nil {
+				return err
+			}
+
+			begin, end, err := parseTimeRange(from, to, locale.Location)
+			if err != nil {
+				return err
+			}
+
+			c, err := newOmloxClient(&settings)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+
+			all, err := c.Fences.List(ctx)
+			if err != nil {
+				return err
+			}
+
+			fences := make([]omlox.Fence, 0, len(all))
+			for _, f := range all {
+				if f.ZoneID == zone {
+					fences = append(fences, f)
+				}
+			}
+
+			events, err := c.History.FenceEvents(ctx, begin, end)
+			if err != nil {
+				return err
+			}
+
+			stats, err := analytics.Utilization(fences, events, begin, end)
+			if err != nil {
+				return err
+			}
+
+			return o.Write(out, &output.UtilizationFormater{Stats: stats})
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&format, "output", "o", output.Table.String(), fmt.Sprintf("Output format. One of: %v.", output.TabularFormats()))
+	f.StringVar(&zone, "zone", "", "Zone id of the fences to report")
+	f.StringVar(&from, "from", "", "Start of the time interval in RFC3339 format, or local to --timezone (default 24h before --to)")
+	f.StringVar(&to, "to", "", "End of the time interval in RFC3339 format, or local to --timezone (default now)")
+
+	cmd.MarkFlagRequired("zone")
+
+	return cmd
+}
@@ -4,14 +4,16 @@
 package main
 
 import (
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/wavecomtech/omlox-client-go"
-	"github.com/wavecomtech/omlox-client-go/internal/cli"
-	"github.com/wavecomtech/omlox-client-go/internal/cli/log"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/config"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/log"
 )
 
 var globalUsage = `The Omlox Hub CLI tool
@@ -26,9 +28,16 @@ Common actions for omlox client:
 
 Environment variables:
 
-| Name                 | Description                                                         |
-|----------------------|---------------------------------------------------------------------|
-| OMLOX_HUB_API        | Omlox hub API endpoint.                                             |
+| Name                    | Description                                                         |
+|-------------------------|---------------------------------------------------------------------|
+| OMLOX_HUB_API           | Omlox hub API endpoint.                                             |
+| OMLOX_HUB_TOKEN         | Omlox hub API bearer token.                                         |
+| OMLOX_HUB_TOKEN_URL     | OAuth2 token endpoint issuing the tokens to the client credentials. |
+| OMLOX_HUB_CLIENT_ID     | Client id of the token endpoint.                                    |
+| OMLOX_HUB_CLIENT_SECRET | Client secret of the token endpoint.                                |
+| OMLOX_CONFIG            | Configuration file, holding the hub API endpoint and token.         |
+| OMLOX_CONFIG_PASSPHRASE | Passphrase of the encrypted secrets of the configuration file.      |
+| OMLOX_VERIFY_KEY        | Public key verifying the signature of the applied hubfiles.         |
 `
 
 func newRootCmd(out io.Writer, args []string) (*cobra.Command, error) {
@@ -42,8 +51,14 @@ func newRootCmd(out io.Writer, args []string) (*cobra.Command, error) {
 	flags := cmd.PersistentFlags()
 
 	settings := cli.New()
+	if err := settings.LoadConfig(); err != nil {
+		return nil, err
+	}
 	settings.AddFlags(flags)
 
+	// the flags of the subcommands are parsed later on, by cobra: skip them so
+	// the global flags are parsed wherever they are given.
+	flags.ParseErrorsWhitelist.UnknownFlags = true
 	flags.Parse(args)
 
 	if settings.Debug {
@@ -55,16 +70,34 @@ func newRootCmd(out io.Writer, args []string) (*cobra.Command, error) {
 		newGetCmd(*settings, out),
 		newCreateCmd(*settings, out),
 		newUpdateCmd(*settings, out),
+		newApplyCmd(*settings, out),
+		newPlanCmd(*settings, out),
+		newSignCmd(out),
+		newKeygenCmd(out),
+		newConfigCmd(*settings, out),
+		newAuthCmd(*settings, out),
 		newDeleteCmd(*settings, out),
 		newSubCmd(*settings, out),
+		newWatchCmd(*settings, out),
+		newExportCmd(*settings, out),
+		newImportCmd(*settings, out),
+		newArchiveCmd(out),
+		newReportCmd(*settings, out),
+		newAnchorsCmd(*settings, out),
+		newFencesCmd(*settings, out),
+		newBenchCmd(*settings, out),
+		newFsckCmd(*settings, out),
+		newValidateCmd(*settings, out),
+		newGraphCmd(*settings, out),
+		newSnapshotCmd(*settings, out),
 		newGenCmd(),
 	)
 
 	return cmd, nil
 }
 
-// newOmloxClient sets up a new Omlox client with given settings.
-func newOmloxClient(settings *cli.EnvSettings) (*omlox.Client, error) {
+// newOmloxClient sets up a new Omlox client with given settings and extra options.
+func newOmloxClient(settings *cli.EnvSettings, extra ...omlox.ClientOption) (*omlox.Client, error) {
 	opts := make([]omlox.ClientOption, 0)
 
 	if settings.Debug {
@@ -78,7 +111,28 @@ func newOmloxClient(settings *cli.EnvSettings) (*omlox.Client, error) {
 		opts = append(opts, omlox.WithHTTPClient(httpClient))
 	}
 
-	return omlox.New(settings.OmloxHubAPI, opts...)
+	if settings.TokenURL != "" {
+		secret, err := settings.ClientSecret.Reveal(os.Getenv(config.PassphraseEnv))
+		if err != nil {
+			return nil, fmt.Errorf("hub client secret: %w", err)
+		}
+
+		opts = append(opts, omlox.WithTokenSource(&omlox.ClientCredentials{
+			TokenURL:     settings.TokenURL,
+			ClientID:     settings.ClientID,
+			ClientSecret: secret,
+		}))
+	} else {
+		token, err := settings.Token.Reveal(os.Getenv(config.PassphraseEnv))
+		if err != nil {
+			return nil, fmt.Errorf("hub token: %w", err)
+		}
+		if token != "" {
+			opts = append(opts, omlox.WithToken(token))
+		}
+	}
+
+	return omlox.New(settings.OmloxHubAPI, append(opts, extra...)...)
 }
 
 func setupLogger() {
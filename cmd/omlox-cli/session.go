@@ -0,0 +1,67 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+)
+
+const (
+	// sessionCheckInterval is how often long-running commands check their token,
+	// shorter than the margin the client refreshes tokens ahead of their expiry.
+	sessionCheckInterval = 30 * time.Second
+
+	// expiryWarning is how long before its expiry a token that can't be refreshed
+	// is warned about.
+	expiryWarning = 5 * time.Minute
+)
+
+// keepSessionAlive keeps the session of long-running commands authenticated
+// until ctx is done: tokens of the client credentials are refreshed ahead of
+// their expiry, instead of on the next request, and fixed tokens are warned
+// about before and once they expire.
+func keepSessionAlive(ctx context.Context, c *omlox.Client, settings cli.EnvSettings) {
+	token, err := c.Token(ctx)
+	if err != nil || token == nil {
+		return
+	}
+	if settings.TokenURL == "" && token.Expiry.IsZero() {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(sessionCheckInterval)
+		defer ticker.Stop()
+
+		warned := false
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if settings.TokenURL != "" {
+				if _, err := c.Token(ctx); err != nil && ctx.Err() == nil {
+					warning("could not refresh the hub token: %v", err)
+				}
+				continue
+			}
+
+			left := time.Until(token.Expiry)
+			switch {
+			case left <= 0:
+				warning("the hub token expired at %s: requests will be rejected until the command is restarted with a new token", token.Expiry.Format(time.RFC3339))
+				return
+			case left <= expiryWarning && !warned:
+				warning("the hub token expires in %s, at %s, and can't be refreshed: set a token endpoint (OMLOX_HUB_TOKEN_URL) to refresh it", left.Round(time.Second), token.Expiry.Format(time.RFC3339))
+				warned = true
+			}
+		}
+	}()
+}
@@ -0,0 +1,133 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/wavecomtech/omlox-client-go/clock"
+	"github.com/wavecomtech/omlox-client-go/signing"
+)
+
+// signatureSuffix is the suffix of the signature files of the signed files.
+const signatureSuffix = ".minisig"
+
+const signHelp = `
+This command signs a hubfile with a private key, generated by the keygen
+command, so the apply and plan commands given the public key with --verify-key
+only apply the approved hubfiles.
+
+The signature is written in the minisign format, to the hubfile with the
+.minisig suffix unless --output is given, and can also be verified with:
+
+  minisign -Vm hub.yaml -p omlox.pub
+`
+
+const keygenHelp = `
+This command generates the key pair signing hubfiles: the private key, written
+to the file with the .key suffix, and the public key, in the minisign format,
+written to the file with the .pub suffix.
+
+The private key is not encrypted: keep it off the hosts applying hubfiles.
+`
+
+func newSignCmd(out io.Writer) *cobra.Command {
+	var file, key, output string
+
+	cmd := &cobra.Command{
+		Use:   "sign",
+		Short: "Sign a hubfile",
+		Long:  signHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(key)
+			if err != nil {
+				return err
+			}
+			k, err := signing.ParsePrivateKey(data)
+			if err != nil {
+				return err
+			}
+
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+
+			if output == "" {
+				output = file + signatureSuffix
+			}
+			if err := os.WriteFile(output, signing.Sign(k, content, filepath.Base(file), clock.System), 0644); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(out, "signed: %s with key %s to %s\n", file, k.ID, output)
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&file, "file", "f", "", "The hubfile to sign")
+	f.StringVar(&key, "key", "", "The private key signing the hubfile")
+	f.StringVar(&output, "output", "", "The signature file (default is the hubfile with the .minisig suffix)")
+
+	cmd.MarkFlagRequired("file")
+	cmd.MarkFlagRequired("key")
+
+	return cmd
+}
+
+func newKeygenCmd(out io.Writer) *cobra.Command {
+	var name string
+
+	cmd := &cobra.Command{
+		Use:   "keygen",
+		Short: "Generate a key pair signing hubfiles",
+		Long:  keygenHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			k, err := signing.GenerateKey()
+			if err != nil {
+				return err
+			}
+
+			private, _ := k.MarshalText()
+			public, _ := k.Public().MarshalText()
+
+			// never overwrite an existing key
+			if err := writeNewFile(name+".key", private, 0600); err != nil {
+				return err
+			}
+			if err := writeNewFile(name+".pub", public, 0644); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(out, "generated: key %s in %s.key and %s.pub\n", k.ID, name, name)
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&name, "name", "omlox", "The name of the key files")
+
+	return cmd
+}
+
+// writeNewFile writes a file, failing if it exists.
+func writeNewFile(name string, data []byte, perm os.FileMode) error {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
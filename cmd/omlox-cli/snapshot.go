@@ -0,0 +1,110 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/render"
+)
+
+const snapshotHelp = `
+This command draws the current state of the Omlox Hub as a static map: the
+zones, the fences and the last location of the trackables. No tile service
+is used, so maps are drawn offline, for reports and documentation.
+
+The image format is given by the extension of the --output file, .svg or
+.png. PNG images have no labels. Without --output, an SVG image is written to
+the standard output.
+
+The map is drawn in the local coordinates of a zone when all the fences and
+locations are local to it, and otherwise in WGS84, converting the local
+coordinates with the ground control points of their zone.
+
+    $ omlox snapshot -o map.png
+    $ omlox snapshot --width 2048 -o hall.svg
+`
+
+func newSnapshotCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	var (
+		file     string
+		width    int
+		noLabels bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Draw the zones, fences and trackables of the hub as a map",
+		Long:  snapshotHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ext := strings.ToLower(filepath.Ext(file))
+			if file != "" && ext != ".svg" && ext != ".png" {
+				return fmt.Errorf("unsupported image format %q: expected .svg or .png", ext)
+			}
+
+			c, err := newOmloxClient(&settings)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+
+			res, err := loadHubResources(ctx, c)
+			if err != nil {
+				return err
+			}
+
+			m := &render.Map{Zones: res.zones, Fences: res.fences}
+			for _, t := range res.trackables {
+				loc, err := c.Trackables.GetLocation(ctx, t.ID)
+				// trackables without a recent location are left out
+				if errors.Is(err, omlox.ErrNotFound) {
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				m.Markers = append(m.Markers, render.Marker{Label: labelOr(t.Name, t.ID.String()), Location: *loc})
+			}
+
+			opts := []render.Option{render.WithWidth(width), render.WithLabels(!noLabels)}
+			if file == "" {
+				return m.SVG(out, opts...)
+			}
+
+			f, err := os.Create(file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			if ext == ".png" {
+				err = m.PNG(f, opts...)
+			} else {
+				err = m.SVG(f, opts...)
+			}
+			if err != nil {
+				return err
+			}
+			return f.Close()
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&file, "output", "o", "", "Image file, .svg or .png")
+	f.IntVar(&width, "width", 1024, "Width of the image in pixels")
+	f.BoolVar(&noLabels, "no-labels", false, "Draw the map without the names of the zones, fences and trackables")
+
+	return cmd
+}
@@ -6,6 +6,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"os"
 	"os/signal"
@@ -13,7 +14,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/wavecomtech/omlox-client-go"
-	"github.com/wavecomtech/omlox-client-go/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
 )
 
 const subHelp = `
@@ -29,9 +30,28 @@ The Omlox standard supports a few:
 	- fence_events:geojson
 
 Extra topics can be supported by vendors.
+
+Subscriptions run until interrupted. Tokens issued by a token endpoint
+(OMLOX_HUB_TOKEN_URL) are refreshed before they expire, while a warning is
+printed before a fixed token expires.
+
+With --record, the events are recorded to files of a directory instead,
+rotated by size and age, compressed, and deleted after the retention period,
+so continuous capture on an edge device does not fill its disk:
+
+    $ omlox sub location_updates --record /var/lib/omlox/locations \
+        --compress zstd --rotate-age 1h --retain-age 168h --retain-size 2048
+
+The rotated files can be uploaded to an S3-compatible object storage with
+--upload, as with 'omlox archive upload':
+
+    $ omlox sub location_updates --record /var/lib/omlox/locations \
+        --rotate-age 15m --upload s3://lake/site-1/locations --delete-uploaded
 `
 
 func newSubCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	var record recordOptions
+
 	getCmd := &cobra.Command{
 		Use:     "subscribe",
 		Aliases: []string{"sub"},
@@ -47,11 +67,28 @@ func newSubCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx, _ := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 
+			dst := out
+			if record.dir != "" {
+				r, err := record.open(args[0], cmd.ErrOrStderr())
+				if err != nil {
+					return err
+				}
+				defer func() {
+					if err := r.Close(); err != nil {
+						fmt.Fprintf(cmd.ErrOrStderr(), "record: %v\n", err)
+					}
+				}()
+
+				dst = r
+			}
+
 			c, err := newOmloxClient(&settings)
 			if err != nil {
 				return err
 			}
 
+			keepSessionAlive(ctx, c, settings)
+
 			if err := c.Connect(ctx); err != nil {
 				return err
 			}
@@ -62,7 +99,7 @@ func newSubCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
 				return err
 			}
 
-			e := json.NewEncoder(out)
+			e := json.NewEncoder(dst)
 
 			for updates := range sub.ReceiveRaw() {
 				for _, u := range updates.Payload {
@@ -76,6 +113,8 @@ func newSubCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
 		},
 	}
 
+	record.addFlags(getCmd.Flags())
+
 	return getCmd
 }
 
@@ -7,7 +7,7 @@ import (
 	"io"
 
 	"github.com/spf13/cobra"
-	"github.com/wavecomtech/omlox-client-go/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
 )
 
 func newUpdateCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
@@ -17,9 +17,12 @@ func newUpdateCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
 		Short:   "Update hub resources",
 	}
 
-	cmd.AddCommand(newUpdateTrackablesCmd(settings, out))
-	cmd.AddCommand(newUpdateProvidersCmd(settings, out))
-	cmd.AddCommand(newUpdateProvidersLocationsCmd(settings, out))
+	var res resourceOptions
+	res.addFlags(cmd.PersistentFlags())
+
+	cmd.AddCommand(newUpdateTrackablesCmd(settings, &res, out))
+	cmd.AddCommand(newUpdateProvidersCmd(settings, &res, out))
+	cmd.AddCommand(newUpdateProvidersLocationsCmd(settings, &res, out))
 
 	return cmd
 }
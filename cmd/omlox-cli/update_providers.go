@@ -7,19 +7,18 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/wavecomtech/omlox-client-go"
-	"github.com/wavecomtech/omlox-client-go/internal/cli"
-	"github.com/wavecomtech/omlox-client-go/internal/cli/resource"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/resource"
 )
 
 const updateProviderHelp = `
 This command updates location providers in the Omlox Hub.
 `
 
-func newUpdateProvidersCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+func newUpdateProvidersCmd(settings cli.EnvSettings, res *resourceOptions, out io.Writer) *cobra.Command {
 	var files []string
 
 	cmd := &cobra.Command{
@@ -29,29 +28,11 @@ func newUpdateProvidersCmd(settings cli.EnvSettings, out io.Writer) *cobra.Comma
 		Long:    updateProviderHelp,
 		Args:    cobra.ExactArgs(0),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var in []io.Reader
-
-			if len(files) > 0 {
-				for _, name := range files {
-					f, err := os.OpenFile(name, os.O_RDONLY, 0444)
-					if err != nil {
-						return err
-					}
-					defer f.Close()
-
-					in = append(in, f)
-				}
-			} else {
-				in = append(in, cmd.InOrStdin())
-			}
-
 			loader := resource.Loader[omlox.LocationProvider]{
 				Resources: make([]omlox.LocationProvider, 0),
 			}
-			for _, r := range in {
-				if err := loader.LoadJSON(r); err != nil {
-					return err
-				}
+			if err := loadResources(cmd, files, res, &loader); err != nil {
+				return err
 			}
 
 			c, err := newOmloxClient(&settings)
@@ -59,8 +40,15 @@ func newUpdateProvidersCmd(settings cli.EnvSettings, out io.Writer) *cobra.Comma
 				return err
 			}
 
+			progress, err := settings.ProgressReporter(cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			progress.Start("update providers", len(loader.Resources))
+
 			for _, p := range loader.Resources {
 				err := c.Providers.Update(context.Background(), p, p.ID)
+				progress.Step(err)
 				if err != nil {
 					return err
 				}
@@ -73,7 +61,7 @@ func newUpdateProvidersCmd(settings cli.EnvSettings, out io.Writer) *cobra.Comma
 	}
 
 	f := cmd.Flags()
-	f.StringArrayVarP(&files, "file", "f", []string{}, "The files that contain the location providers to update")
+	f.StringArrayVarP(&files, "file", "f", []string{}, "The files, or directories of json files, that contain the location providers to update")
 
 	return cmd
 }
@@ -7,19 +7,18 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/wavecomtech/omlox-client-go"
-	"github.com/wavecomtech/omlox-client-go/internal/cli"
-	"github.com/wavecomtech/omlox-client-go/internal/cli/resource"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/resource"
 )
 
 const updateTrackableHelp = `
 This command updates trackables in the Omlox Hub.
 `
 
-func newUpdateTrackablesCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+func newUpdateTrackablesCmd(settings cli.EnvSettings, res *resourceOptions, out io.Writer) *cobra.Command {
 	var files []string
 
 	cmd := &cobra.Command{
@@ -28,29 +27,11 @@ func newUpdateTrackablesCmd(settings cli.EnvSettings, out io.Writer) *cobra.Comm
 		Long:  updateTrackableHelp,
 		Args:  cobra.ExactArgs(0),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var in []io.Reader
-
-			if len(files) > 0 {
-				for _, name := range files {
-					f, err := os.OpenFile(name, os.O_RDONLY, 0444)
-					if err != nil {
-						return err
-					}
-					defer f.Close()
-
-					in = append(in, f)
-				}
-			} else {
-				in = append(in, cmd.InOrStdin())
-			}
-
 			loader := resource.Loader[omlox.Trackable]{
 				Resources: make([]omlox.Trackable, 0),
 			}
-			for _, r := range in {
-				if err := loader.LoadJSON(r); err != nil {
-					return err
-				}
+			if err := loadResources(cmd, files, res, &loader); err != nil {
+				return err
 			}
 
 			c, err := newOmloxClient(&settings)
@@ -58,8 +39,15 @@ func newUpdateTrackablesCmd(settings cli.EnvSettings, out io.Writer) *cobra.Comm
 				return err
 			}
 
+			progress, err := settings.ProgressReporter(cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			progress.Start("update trackables", len(loader.Resources))
+
 			for _, t := range loader.Resources {
 				err := c.Trackables.Update(context.Background(), t, t.ID)
+				progress.Step(err)
 				if err != nil {
 					return err
 				}
@@ -72,7 +60,7 @@ func newUpdateTrackablesCmd(settings cli.EnvSettings, out io.Writer) *cobra.Comm
 	}
 
 	f := cmd.Flags()
-	f.StringArrayVarP(&files, "file", "f", []string{}, "The files that contain the trackables to update")
+	f.StringArrayVarP(&files, "file", "f", []string{}, "The files, or directories of json files, that contain the trackables to update")
 
 	return cmd
 }
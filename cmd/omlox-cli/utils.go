@@ -3,6 +3,74 @@
 
 package main
 
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/resource"
+)
+
+// defaultTimeRange is the time range used when no start time is given.
+const defaultTimeRange = 24 * time.Hour
+
+// localTimeLayouts are the layouts of the times given without time zone.
+var localTimeLayouts = []string{"2006-01-02T15:04:05", time.DateTime, time.DateOnly}
+
+// parseTimeRange parses a time interval given in RFC3339 format, or without
+// time zone in the given location, such as "2024-03-01 08:00:00" or "2024-03-01".
+// If to is empty, it defaults to now, and if from is empty it defaults to
+// the default time range before to.
+func parseTimeRange(from, to string, loc *time.Location) (time.Time, time.Time, error) {
+	end := time.Now()
+	if to != "" {
+		t, err := parseTime(to, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid end time: %w", err)
+		}
+		end = t
+	}
+
+	begin := end.Add(-defaultTimeRange)
+	if from != "" {
+		t, err := parseTime(from, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start time: %w", err)
+		}
+		begin = t
+	}
+
+	if !begin.Before(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("start time must be before end time")
+	}
+
+	return begin, end, nil
+}
+
+// parseTime parses a time in RFC3339 format, or without time zone in the location.
+func parseTime(s string, loc *time.Location) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err == nil {
+		return t, nil
+	}
+
+	for _, layout := range localTimeLayouts {
+		if t, lerr := time.ParseInLocation(layout, s, loc); lerr == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, err
+}
+
 // Returns all IDs from 'ids', except those with names matching 'ignoredIDs'
 func filterIDs(ids []string, ignoredIDs []string) []string {
 	if ignoredIDs == nil {
@@ -25,3 +93,184 @@ func filterIDs(ids []string, ignoredIDs []string) []string {
 
 	return filtered
 }
+
+// selector matches resources by their custom properties, in the form
+// 'key1=value1,key2!=value2'. All requirements must match.
+type selector []requirement
+
+type requirement struct {
+	key   string
+	value string
+	equal bool
+}
+
+// parseSelector parses a property selector. An empty selector matches everything.
+func parseSelector(s string) (selector, error) {
+	var sel selector
+	if strings.TrimSpace(s) == "" {
+		return sel, nil
+	}
+
+	for _, term := range strings.Split(s, ",") {
+		r := requirement{equal: true}
+
+		key, value, ok := strings.Cut(term, "!=")
+		if ok {
+			r.equal = false
+		} else if key, value, ok = strings.Cut(term, "="); !ok {
+			return nil, fmt.Errorf("invalid selector requirement '%s': expected key=value or key!=value", term)
+		}
+
+		r.key, r.value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if r.key == "" {
+			return nil, fmt.Errorf("invalid selector requirement '%s': empty key", term)
+		}
+
+		sel = append(sel, r)
+	}
+
+	return sel, nil
+}
+
+// Matches reports whether the given JSON object properties satisfy the selector.
+// String properties are compared by value, others by their JSON representation.
+func (sel selector) Matches(properties json.RawMessage) bool {
+	if len(sel) == 0 {
+		return true
+	}
+
+	var props map[string]json.RawMessage
+	if len(properties) > 0 {
+		if err := json.Unmarshal(properties, &props); err != nil {
+			return false
+		}
+	}
+
+	for _, r := range sel {
+		raw, found := props[r.key]
+
+		value := string(raw)
+		var str string
+		if json.Unmarshal(raw, &str) == nil {
+			value = str
+		}
+
+		if (found && value == r.value) != r.equal {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hubResources are all the trackables, providers, fences and zones of the Hub.
+type hubResources struct {
+	trackables []omlox.Trackable
+	providers  []omlox.LocationProvider
+	fences     []omlox.Fence
+	zones      []omlox.Zone
+}
+
+// loadHubResources lists all the resources of the Hub.
+func loadHubResources(ctx context.Context, c *omlox.Client) (*hubResources, error) {
+	var (
+		r   hubResources
+		err error
+	)
+
+	if r.trackables, err = c.Trackables.List(ctx); err != nil {
+		return nil, err
+	}
+	if r.providers, err = c.Providers.List(ctx); err != nil {
+		return nil, err
+	}
+	if r.fences, err = c.Fences.List(ctx); err != nil {
+		return nil, err
+	}
+	if err = c.Do(ctx, http.MethodGet, "/zones/summary", nil, &r.zones); err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// colorEnabled reports whether the output is a terminal to colorize, unless
+// disabled by flag or by the NO_COLOR environment variable.
+func colorEnabled(out io.Writer, disabled bool) bool {
+	if disabled || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	f, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// varsOptions are the variables substituted in the loaded resource files.
+type varsOptions struct {
+	set []string
+}
+
+func (o *varsOptions) addFlags(f *pflag.FlagSet) {
+	f.StringArrayVar(&o.set, "set", nil, "Set the variable substituted for ${name} in the files, as name=value, overriding the environment")
+}
+
+// variables returns the variables of the flags.
+func (o *varsOptions) variables() (*resource.Variables, error) {
+	return resource.ParseVariables(o.set)
+}
+
+// resourceOptions are the options to load resource files.
+type resourceOptions struct {
+	vars     varsOptions
+	overlays []string
+}
+
+func (o *resourceOptions) addFlags(f *pflag.FlagSet) {
+	o.vars.addFlags(f)
+	f.StringArrayVar(&o.overlays, "overlay", nil, "The files that contain patches merged into the resources with the same id")
+}
+
+// loadResources loads the resources of the files, or of the standard input without
+// files, and merges the overlays into them. Directories load their json files.
+func loadResources[T any](cmd *cobra.Command, files []string, opts *resourceOptions, loader *resource.Loader[T]) error {
+	variables, err := opts.vars.variables()
+	if err != nil {
+		return err
+	}
+	loader.Variables = variables
+
+	if len(files) == 0 {
+		if err := loader.LoadJSON(cmd.InOrStdin()); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range files {
+		info, err := os.Stat(name)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			err = loader.LoadDir(name)
+		} else {
+			err = loader.LoadFile(name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range opts.overlays {
+		if err := loader.PatchFile(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/output"
+	"github.com/wavecomtech/omlox-client-go/hubfile"
+)
+
+const validateHelp = `
+This command validates the configuration of the Omlox Hub, or of a hubfile
+with --file before applying it, and reports the issues found with their
+severity: info, warning or error.
+
+The validation passes are selected by flags, all of them by default:
+
+  --spatial  checks the geometry of the fences, as misconfigured geometries
+             cause phantom fence events:
+
+               self-intersection  polygons whose edges cross each other (error)
+               overlap            fences overlapping each other (warning), or
+                                  nested within each other (info)
+               gap                fences closer than --gap without touching,
+                                  such as neighbours drawn by hand (warning)
+               outside-zone       fences outside the radius of their zone
+                                  (error), partly outside (warning), or of
+                                  unknown zones (error)
+
+             Fences of different floors are not compared.
+
+The command fails when issues of the --fail-on severity or above are found.
+`
+
+func newValidateCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	var (
+		format  string
+		file    string
+		vars    varsOptions
+		spatial bool
+		gap     float64
+		minimum string
+		failOn  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the configuration of the Hub",
+		Long:  validateHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o, err := output.ParseFormat(format)
+			if err != nil {
+				return err
+			}
+
+			var shown, failed omlox.Severity
+			if err := shown.FromString(minimum); err != nil {
+				return err
+			}
+			if err := failed.FromString(failOn); err != nil {
+				return err
+			}
+
+			// all passes without any selected
+			if !cmd.Flags().Changed("spatial") {
+				spatial = true
+			}
+
+			var (
+				zones  []omlox.Zone
+				fences []omlox.Fence
+			)
+			if file != "" {
+				f, err := loadHubfile(file, &vars)
+				if err != nil {
+					return err
+				}
+				zones, fences = f.Zones, f.Fences
+			} else {
+				c, err := newOmloxClient(&settings)
+				if err != nil {
+					return err
+				}
+				res, err := loadHubResources(context.Background(), c)
+				if err != nil {
+					return err
+				}
+				zones, fences = res.zones, res.fences
+			}
+
+			var issues []omlox.LintIssue
+			if spatial {
+				issues = append(issues, omlox.LintFences(fences, zones, omlox.WithGapTolerance(gap))...)
+			}
+
+			var (
+				reported []omlox.LintIssue
+				failures int
+			)
+			for _, i := range issues {
+				if i.Severity >= shown {
+					reported = append(reported, i)
+				}
+				if i.Severity >= failed {
+					failures++
+				}
+			}
+
+			if err := o.Write(out, &output.LintFormater{Issues: reported}); err != nil {
+				return err
+			}
+			if failures > 0 {
+				return fmt.Errorf("%d issues of severity %s or above found", failures, failed)
+			}
+
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&format, "output", "o", output.Table.String(), fmt.Sprintf("Output format. One of: %v.", output.TabularFormats()))
+	f.StringVarP(&file, "file", "f", "", "Validate the hubfile instead of the Hub")
+	vars.addFlags(f)
+	f.BoolVar(&spatial, "spatial", false, "Check the geometry of the fences")
+	f.Float64Var(&gap, "gap", omlox.DefaultGapTolerance, "Distance in meters under which fences not touching each other are reported")
+	f.StringVar(&minimum, "severity", omlox.SeverityInfo.String(), "Minimum severity of the reported issues. One of: [info warning error].")
+	f.StringVar(&failOn, "fail-on", omlox.SeverityError.String(), "Minimum severity of the issues failing the command. One of: [info warning error].")
+
+	return cmd
+}
+
+// loadHubfile loads a hubfile with its variables substituted.
+func loadHubfile(name string, vars *varsOptions) (*hubfile.File, error) {
+	content, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	variables, err := vars.variables()
+	if err != nil {
+		return nil, err
+	}
+	if content, err = variables.Expand(content); err != nil {
+		return nil, fmt.Errorf("hubfile %s: %w", name, err)
+	}
+
+	return hubfile.Load(bytes.NewReader(content))
+}
@@ -0,0 +1,252 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli"
+	"github.com/wavecomtech/omlox-client-go/cmd/omlox-cli/internal/cli/notify"
+	"github.com/wavecomtech/omlox-client-go/fanout"
+)
+
+const watchHelp = `
+This command watches the fence events of the Omlox Hub, printing a line per
+entry or exit of a trackable in a fence.
+
+Events can be restricted to some fences, by id or name, and to entries or exits.
+With --notify, a desktop notification is raised for each event, which is
+enough to watch a few restricted areas without a full alerting stack:
+
+    $ omlox watch --fence "Restricted area" --event region_entry --notify
+
+Notifications use notify-send on Linux, osascript on macOS and PowerShell
+toast notifications on Windows.
+
+With --sse-listen, the watched events are also served to browsers as
+Server-Sent Events, for environments blocking WebSockets. Pages subscribe
+with the topic of the events, and may restrict them to some trackables:
+
+    $ omlox watch --sse-listen :8080
+
+    new EventSource("http://localhost:8080/?topic=fence_events")
+
+WebSocket connections of the omlox websocket protocol are served on the
+same address.
+`
+
+func newWatchCmd(settings cli.EnvSettings, out io.Writer) *cobra.Command {
+	var (
+		fences    []string
+		events    []string
+		notifyOn  bool
+		sseListen string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Watches fence events, with optional desktop notifications",
+		Long:  watchHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			types := make(map[omlox.FenceEventType]bool, len(events))
+			for _, e := range events {
+				var t omlox.FenceEventType
+				if err := t.FromString(e); err != nil {
+					return err
+				}
+				types[t] = true
+			}
+
+			locale, err := settings.Locale()
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			c, err := newOmloxClient(&settings)
+			if err != nil {
+				return err
+			}
+
+			keepSessionAlive(ctx, c, settings)
+
+			w, err := newFenceWatcher(ctx, c, fences)
+			if err != nil {
+				return err
+			}
+
+			if err := c.Connect(ctx); err != nil {
+				return err
+			}
+			defer c.Close()
+
+			sub, err := c.Subscribe(ctx, omlox.TopicFenceEvents)
+			if err != nil {
+				return err
+			}
+
+			var fan *fanout.Server
+			if sseListen != "" {
+				ln, err := net.Listen("tcp", sseListen)
+				if err != nil {
+					return err
+				}
+
+				fan = fanout.NewServer(fanout.WithOriginPatterns("*"))
+				defer fan.Close()
+				fan.Broadcast(&omlox.WrapperObject{Topic: omlox.TopicFenceEvents})
+
+				srv := &http.Server{Handler: fan}
+				defer srv.Close()
+				go func() {
+					if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+						warning("could not serve events: %v", err)
+					}
+				}()
+			}
+
+			for e := range omlox.ReceiveAs[omlox.FenceEvent](sub) {
+				if !types[e.EventType] || !w.selected(e.FenceID) {
+					continue
+				}
+
+				n := w.notification(e)
+				fmt.Fprintf(out, "%s\t%s\t%s\n", locale.Time(w.time(e)), e.EventType, n.Message)
+
+				if notifyOn {
+					go func() {
+						if err := notify.Send(ctx, n); err != nil && ctx.Err() == nil {
+							warning("could not raise notification: %v", err)
+						}
+					}()
+				}
+
+				if fan != nil {
+					payload, err := json.Marshal(e)
+					if err != nil {
+						warning("could not serve event: %v", err)
+						continue
+					}
+					fan.Broadcast(&omlox.WrapperObject{Topic: omlox.TopicFenceEvents, Payload: []json.RawMessage{payload}})
+				}
+			}
+
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringSliceVar(&fences, "fence", nil, "Fences to watch, by id or name (default all)")
+	f.StringSliceVar(&events, "event", []string{omlox.FenceEventTypeRegionEntry.String(), omlox.FenceEventTypeRegionExit.String()}, "Fence events to watch. Any of: [region_entry region_exit].")
+	f.BoolVar(&notifyOn, "notify", false, "Raise a desktop notification for each event")
+	f.StringVar(&sseListen, "sse-listen", "", "Address to serve the events to browsers as Server-Sent Events, such as :8080")
+
+	return cmd
+}
+
+// fenceWatcher describes fence events with the names of the fences and trackables.
+type fenceWatcher struct {
+	fences     map[uuid.UUID]string
+	trackables map[uuid.UUID]string
+
+	// watched fences, nil for all
+	watched map[uuid.UUID]bool
+}
+
+func newFenceWatcher(ctx context.Context, c *omlox.Client, selection []string) (*fenceWatcher, error) {
+	fences, err := c.Fences.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	trackables, err := c.Trackables.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &fenceWatcher{
+		fences:     make(map[uuid.UUID]string, len(fences)),
+		trackables: make(map[uuid.UUID]string, len(trackables)),
+	}
+	for _, f := range fences {
+		w.fences[f.ID] = f.Name
+	}
+	for _, t := range trackables {
+		w.trackables[t.ID] = t.Name
+	}
+
+	if len(selection) == 0 {
+		return w, nil
+	}
+
+	w.watched = make(map[uuid.UUID]bool, len(selection))
+	for _, s := range selection {
+		found := false
+		for _, f := range fences {
+			if f.ID.String() == s || f.Name == s {
+				w.watched[f.ID] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("fence %q not found", s)
+		}
+	}
+
+	return w, nil
+}
+
+func (w *fenceWatcher) selected(id uuid.UUID) bool {
+	return w.watched == nil || w.watched[id]
+}
+
+// time returns the time of the event, or now if the Hub didn't send it.
+func (w *fenceWatcher) time(e *omlox.FenceEvent) time.Time {
+	if t := e.Time(); !t.IsZero() {
+		return t
+	}
+	return time.Now()
+}
+
+// notification describes the event, such as "forklift-3 entered Restricted area".
+func (w *fenceWatcher) notification(e *omlox.FenceEvent) notify.Notification {
+	fence := w.fences[e.FenceID]
+	if fence == "" {
+		fence = e.FenceID.String()
+	}
+
+	who := e.ProviderID
+	if e.TrackableID != nil {
+		who = w.trackableName(*e.TrackableID)
+	} else if len(e.Trackables) > 0 {
+		who = w.trackableName(e.Trackables[0])
+	}
+
+	if e.EventType == omlox.FenceEventTypeRegionExit {
+		return notify.Notification{Title: "Fence exit: " + fence, Message: fmt.Sprintf("%s left %s", who, fence)}
+	}
+	return notify.Notification{Title: "Fence entry: " + fence, Message: fmt.Sprintf("%s entered %s", who, fence)}
+}
+
+func (w *fenceWatcher) trackableName(id uuid.UUID) string {
+	if name := w.trackables[id]; name != "" {
+		return name
+	}
+	return id.String()
+}
@@ -0,0 +1,125 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CollisionEvent defines model for CollisionEvent.
+// Collision events received from subscriptions are validated, other callers call Validate.
+//
+//easyjson:json
+type CollisionEvent struct {
+	// CollisionType is the stage of the collision: start, ongoing or end.
+	CollisionType CollisionType `json:"collision_type"`
+
+	// Collisions are the objects colliding, at least two.
+	Collisions []Collision `json:"collisions"`
+
+	// TimestampGenerated is the time the collision was detected.
+	TimestampGenerated *time.Time `json:"timestamp_generated,omitempty"`
+
+	// TimestampSent is the time the event was sent.
+	TimestampSent *time.Time `json:"timestamp_sent,omitempty"`
+}
+
+// Collision is an object of a collision.
+type Collision struct {
+	// ObjectID is the id of the colliding trackable.
+	ObjectID uuid.UUID `json:"object_id"`
+
+	// Position is the position of the object at the collision, if known.
+	Position *Point `json:"position,omitempty"`
+
+	// Radius is the radius of the object in meters, if known.
+	Radius float64 `json:"radius,omitempty"`
+}
+
+// Trackables returns the ids of the colliding trackables.
+func (e CollisionEvent) Trackables() []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(e.Collisions))
+	for _, c := range e.Collisions {
+		ids = append(ids, c.ObjectID)
+	}
+	return ids
+}
+
+// Validate checks the event has the fields required by the omlox™ schema.
+func (e CollisionEvent) Validate() error {
+	if e.CollisionType.String() == "" {
+		return fmt.Errorf("invalid collision type %d", e.CollisionType)
+	}
+	if len(e.Collisions) < 2 {
+		return fmt.Errorf("collision event with %d objects, expected at least 2", len(e.Collisions))
+	}
+	for i, c := range e.Collisions {
+		if c.ObjectID == uuid.Nil {
+			return fmt.Errorf("collision %d: object id must not be empty", i)
+		}
+	}
+
+	return nil
+}
+
+// CollisionType is the stage of a collision.
+type CollisionType int
+
+// Defines values for CollisionType.
+const (
+	CollisionTypeStart CollisionType = iota
+	CollisionTypeColliding
+	CollisionTypeEnd
+)
+
+// FromString assigs itself from type name.
+func (t *CollisionType) FromString(name string) error {
+	v, ok := map[string]CollisionType{
+		CollisionTypeStart.String():     CollisionTypeStart,
+		CollisionTypeColliding.String(): CollisionTypeColliding,
+		CollisionTypeEnd.String():       CollisionTypeEnd,
+	}[name]
+
+	if !ok {
+		return fmt.Errorf("collision of type %s not supported", name)
+	}
+
+	*t = v
+	return nil
+}
+
+// String return a text representation.
+func (t CollisionType) String() string {
+	types := [...]string{
+		"collision_start",
+		"colliding",
+		"collision_end",
+	}
+
+	if int(t) < 0 || len(types) <= int(t) {
+		return ""
+	}
+
+	return types[t]
+}
+
+// MarshalJSON encodes type in to JSON.
+func (t CollisionType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON decodes type from JSON.
+func (t *CollisionType) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	return t.FromString(s)
+}
@@ -0,0 +1,87 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+var collisionEventsJSONTestCases = []struct {
+	name  string
+	event CollisionEvent
+	json  []byte
+}{
+	{
+		name: "start",
+		event: CollisionEvent{
+			CollisionType: CollisionTypeStart,
+			Collisions: []Collision{
+				{ObjectID: uuid.MustParse("9b59961e-2a6a-4712-86e7-aba5a3e8be1f"), Radius: 1.5},
+				{ObjectID: uuid.MustParse("d27047bd-1b6b-4656-bb93-2326a4c900e1"), Radius: 0.5},
+			},
+			TimestampGenerated: mustParseTime("2023-10-17T11:14:37.206Z"),
+		},
+		json: []byte(`{"collision_type":"collision_start","collisions":[{"object_id":"9b59961e-2a6a-4712-86e7-aba5a3e8be1f","radius":1.5},{"object_id":"d27047bd-1b6b-4656-bb93-2326a4c900e1","radius":0.5}],"timestamp_generated":"2023-10-17T11:14:37.206Z"}`),
+	},
+	{
+		name: "end",
+		event: CollisionEvent{
+			CollisionType: CollisionTypeEnd,
+			Collisions: []Collision{
+				{ObjectID: uuid.MustParse("9b59961e-2a6a-4712-86e7-aba5a3e8be1f")},
+				{ObjectID: uuid.MustParse("d27047bd-1b6b-4656-bb93-2326a4c900e1")},
+				{ObjectID: uuid.MustParse("497f6eca-6276-4993-bfeb-53cbbbba6f08")},
+			},
+		},
+		json: []byte(`{"collision_type":"collision_end","collisions":[{"object_id":"9b59961e-2a6a-4712-86e7-aba5a3e8be1f"},{"object_id":"d27047bd-1b6b-4656-bb93-2326a4c900e1"},{"object_id":"497f6eca-6276-4993-bfeb-53cbbbba6f08"}]}`),
+	},
+}
+
+func TestCollisionEventMarshal(t *testing.T) {
+	for _, tc := range collisionEventsJSONTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			JSONMarshalOK(t, tc.event, tc.json)
+		})
+	}
+}
+
+func TestCollisionEventUnmarshal(t *testing.T) {
+	for _, tc := range collisionEventsJSONTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			JSONUnmarshalOK(t, tc.json, tc.event)
+		})
+	}
+}
+
+func TestCollisionEventValidate(t *testing.T) {
+	testCases := []struct {
+		name string
+		json string
+	}{
+		{name: "single object", json: `{"collision_type":"colliding","collisions":[{"object_id":"9b59961e-2a6a-4712-86e7-aba5a3e8be1f"}]}`},
+		{name: "missing object id", json: `{"collision_type":"colliding","collisions":[{"object_id":"9b59961e-2a6a-4712-86e7-aba5a3e8be1f"},{"radius":1}]}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// events are decoded leniently, and rejected by Validate
+			var e CollisionEvent
+			if err := json.Unmarshal([]byte(tc.json), &e); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := e.Validate(); err == nil {
+				t.Errorf("expected an error, got %+v", e)
+			}
+		})
+	}
+
+	// events of unknown types cannot be decoded
+	var e CollisionEvent
+	if err := json.Unmarshal([]byte(`{"collision_type":"near_miss","collisions":[{"object_id":"9b59961e-2a6a-4712-86e7-aba5a3e8be1f"},{"object_id":"d27047bd-1b6b-4656-bb93-2326a4c900e1"}]}`), &e); err == nil {
+		t.Errorf("expected an error, got %+v", e)
+	}
+}
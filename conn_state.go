@@ -0,0 +1,85 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"log/slog"
+)
+
+// ConnState is the state of the websocket connection of a client.
+type ConnState int
+
+const (
+	// ConnStateConnected is a connection established, with its subscriptions restored
+	// after a reconnection.
+	ConnStateConnected ConnState = iota + 1
+
+	// ConnStateDisconnected is a connection lost.
+	ConnStateDisconnected
+
+	// ConnStateReconnecting is a reconnection attempt, after a connection lost or a
+	// failed attempt.
+	ConnStateReconnecting
+
+	// ConnStateClosed is a connection closed by the client.
+	ConnStateClosed
+)
+
+// String returns the name of the state.
+func (s ConnState) String() string {
+	switch s {
+	case ConnStateConnected:
+		return "connected"
+	case ConnStateDisconnected:
+		return "disconnected"
+	case ConnStateReconnecting:
+		return "reconnecting"
+	case ConnStateClosed:
+		return "closed"
+	default:
+		return ""
+	}
+}
+
+// ConnStateChange is a change of the state of the websocket connection of a client.
+type ConnStateChange struct {
+	// State is the new state of the connection.
+	State ConnState
+
+	// Attempt is the number of the reconnection attempt, starting at 1, for reconnections.
+	Attempt int
+
+	// Err is the error the connection was lost with, or the previous reconnection
+	// attempt failed with, if any.
+	Err error
+}
+
+// ConnState returns the state of the websocket connection, zero if never connected.
+func (c *Client) ConnState() ConnState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.connState
+}
+
+// setConnState records a change of the state of the connection, and reports it to the
+// handler of the configuration, if any.
+func (c *Client) setConnState(ctx context.Context, change ConnStateChange) {
+	c.mu.Lock()
+	c.connState = change.State
+	c.mu.Unlock()
+
+	slog.LogAttrs(ctx, slog.LevelDebug, "connection state changed",
+		slog.String("state", change.State.String()),
+		slog.Int("attempt", change.Attempt),
+		slog.Any("err", change.Err),
+	)
+
+	if h := c.configuration.ConnStateHandler; h != nil {
+		if err := protect("connection state handler", "", func() error { h(change); return nil }); err != nil {
+			c.reportError(ctx, err)
+		}
+	}
+}
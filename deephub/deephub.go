@@ -0,0 +1,24 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package deephub provides the vendor extensions of the DeepHub, an omlox™ Hub
+// implementation, layered over the standard client.
+//
+// The extensions are not part of the omlox™ specification and are not available
+// on other Hubs. Portable code should only depend on the omlox package.
+package deephub
+
+import (
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+// Client is a DeepHub client, giving access to the vendor extensions.
+// The standard APIs remain available through the embedded omlox client.
+type Client struct {
+	*omlox.Client
+}
+
+// New returns a DeepHub client using the given omlox client for requests.
+func New(client *omlox.Client) *Client {
+	return &Client{Client: client}
+}
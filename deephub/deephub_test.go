@@ -0,0 +1,123 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package deephub
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nsf/jsondiff"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+func TestTrackableJSON(t *testing.T) {
+	data := []byte(`{"id":"d27047bd-1b6b-4656-bb93-2326a4c900e1","type":"virtual","name":"forklift","workflow_ids":["8a1a5c0c-fb79-4a1e-a2c4-0a4d3b6d0f0e"],"icon":"forklift"}`)
+
+	var tr Trackable
+	if err := json.Unmarshal(data, &tr); err != nil {
+		t.Fatal(err)
+	}
+
+	if tr.Name != "forklift" || tr.Type != omlox.TrackableTypeVirtual {
+		t.Errorf("unexpected standard fields: %+v", tr.Trackable)
+	}
+	if len(tr.Extensions) != 2 {
+		t.Fatalf("expected 2 extension fields, got %v", tr.Extensions)
+	}
+
+	var icon string
+	if ok, err := tr.Extension("icon", &icon); !ok || err != nil || icon != "forklift" {
+		t.Errorf("unexpected icon extension: %q, %v, %v", icon, ok, err)
+	}
+
+	b, err := json.Marshal(tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := jsondiff.DefaultConsoleOptions()
+	if diff, s := jsondiff.Compare(data, b, &opts); diff != jsondiff.FullMatch {
+		t.Errorf("unexpected JSON: %s", s)
+	}
+}
+
+func TestSetExtensionDoesNotOverrideStandardFields(t *testing.T) {
+	tr := Trackable{Trackable: omlox.Trackable{Name: "forklift"}}
+	if err := tr.SetExtension("name", "other"); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got omlox.Trackable
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "forklift" {
+		t.Errorf("expected standard name to be kept, got %q", got.Name)
+	}
+}
+
+func TestWorkflows(t *testing.T) {
+	id := uuid.MustParse("8a1a5c0c-fb79-4a1e-a2c4-0a4d3b6d0f0e")
+
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		switch r.Method {
+		case http.MethodGet:
+			io.WriteString(w, `[{"id":"`+id.String()+`","name":"notify","enabled":true}]`)
+		case http.MethodPost:
+			w.Write(body)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := omlox.New(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hub := New(c)
+	ctx := context.Background()
+
+	workflows, err := hub.Workflows(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(workflows) != 1 || workflows[0].ID != id || !workflows[0].Enabled {
+		t.Errorf("unexpected workflows: %+v", workflows)
+	}
+
+	created, err := hub.CreateWorkflow(ctx, Workflow{ID: id, Name: "notify"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Name != "notify" {
+		t.Errorf("unexpected created workflow: %+v", created)
+	}
+
+	if err := hub.DeleteWorkflow(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"GET /workflows", "POST /workflows", "DELETE /workflows/" + id.String()}
+	if len(requests) != len(want) {
+		t.Fatalf("unexpected requests: %v", requests)
+	}
+	for i := range want {
+		if requests[i] != want[i] {
+			t.Errorf("expected request %q, got %q", want[i], requests[i])
+		}
+	}
+}
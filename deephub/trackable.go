@@ -0,0 +1,142 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package deephub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+// Trackable is an omlox™ trackable with the vendor specific fields of the DeepHub.
+type Trackable struct {
+	omlox.Trackable
+
+	// Extensions are the fields of the trackable which are not part of the omlox™ Trackable schema.
+	Extensions map[string]json.RawMessage
+}
+
+// Extension decodes the extension field of the given name into v.
+// It reports whether the trackable has the field.
+func (t Trackable) Extension(name string, v any) (bool, error) {
+	raw, ok := t.Extensions[name]
+	if !ok {
+		return false, nil
+	}
+
+	return true, json.Unmarshal(raw, v)
+}
+
+// SetExtension sets the extension field of the given name.
+func (t *Trackable) SetExtension(name string, v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if t.Extensions == nil {
+		t.Extensions = make(map[string]json.RawMessage)
+	}
+	t.Extensions[name] = raw
+
+	return nil
+}
+
+// MarshalJSON encodes the trackable in to JSON, with its extension fields.
+// Extension fields never override the standard ones.
+func (t Trackable) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(t.Trackable)
+	if err != nil || len(t.Extensions) == 0 {
+		return b, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+
+	for name, v := range t.Extensions {
+		if _, ok := standardFields()[name]; !ok {
+			fields[name] = v
+		}
+	}
+
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON decodes the trackable from JSON, keeping the extension fields.
+func (t *Trackable) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &t.Trackable); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+
+	t.Extensions = nil
+	for name, v := range fields {
+		if _, ok := standardFields()[name]; ok {
+			continue
+		}
+		if t.Extensions == nil {
+			t.Extensions = make(map[string]json.RawMessage)
+		}
+		t.Extensions[name] = v
+	}
+
+	return nil
+}
+
+// standardFields returns the JSON field names of the omlox™ Trackable schema.
+var standardFields = sync.OnceValue(func() map[string]struct{} {
+	fields := make(map[string]struct{})
+
+	typ := reflect.TypeOf(omlox.Trackable{})
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = struct{}{}
+		}
+	}
+
+	return fields
+})
+
+// Trackables lists all trackables with their extension fields.
+func (c *Client) Trackables(ctx context.Context) ([]Trackable, error) {
+	var trackables []Trackable
+	err := c.Do(ctx, http.MethodGet, "/trackables/summary", nil, &trackables)
+	return trackables, err
+}
+
+// Trackable gets a trackable with its extension fields.
+func (c *Client) Trackable(ctx context.Context, id uuid.UUID) (*Trackable, error) {
+	var trackable Trackable
+	if err := c.Do(ctx, http.MethodGet, "/trackables/"+id.String(), nil, &trackable); err != nil {
+		return nil, err
+	}
+	return &trackable, nil
+}
+
+// CreateTrackable creates a trackable with its extension fields.
+func (c *Client) CreateTrackable(ctx context.Context, trackable Trackable) (*Trackable, error) {
+	var created Trackable
+	if err := c.Do(ctx, http.MethodPost, "/trackables", trackable, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateTrackable updates a trackable with its extension fields.
+func (c *Client) UpdateTrackable(ctx context.Context, trackable Trackable) error {
+	return c.Do(ctx, http.MethodPut, "/trackables/"+trackable.ID.String(), trackable, nil)
+}
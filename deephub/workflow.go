@@ -0,0 +1,69 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package deephub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// Workflow is a DeepHub automation, running actions when its trigger fires
+// (e.g. sending a notification when a trackable enters a fence).
+type Workflow struct {
+	// The unique identifier of the workflow. A unique id will be generated if it is not provided.
+	ID uuid.UUID `json:"id"`
+
+	// A describing name.
+	Name string `json:"name,omitempty"`
+
+	// Whether the workflow runs when triggered.
+	Enabled bool `json:"enabled"`
+
+	// The event which triggers the workflow.
+	Trigger json.RawMessage `json:"trigger,omitempty"`
+
+	// The actions run, in order, when the workflow is triggered.
+	Actions []json.RawMessage `json:"actions,omitempty"`
+
+	// Any additional application or vendor specific properties.
+	Properties json.RawMessage `json:"properties,omitempty"`
+}
+
+// Workflows lists all workflows.
+func (c *Client) Workflows(ctx context.Context) ([]Workflow, error) {
+	var workflows []Workflow
+	err := c.Do(ctx, http.MethodGet, "/workflows", nil, &workflows)
+	return workflows, err
+}
+
+// Workflow gets a workflow.
+func (c *Client) Workflow(ctx context.Context, id uuid.UUID) (*Workflow, error) {
+	var workflow Workflow
+	if err := c.Do(ctx, http.MethodGet, "/workflows/"+id.String(), nil, &workflow); err != nil {
+		return nil, err
+	}
+	return &workflow, nil
+}
+
+// CreateWorkflow creates a workflow.
+func (c *Client) CreateWorkflow(ctx context.Context, workflow Workflow) (*Workflow, error) {
+	var created Workflow
+	if err := c.Do(ctx, http.MethodPost, "/workflows", workflow, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateWorkflow updates a workflow.
+func (c *Client) UpdateWorkflow(ctx context.Context, workflow Workflow) error {
+	return c.Do(ctx, http.MethodPut, "/workflows/"+workflow.ID.String(), workflow, nil)
+}
+
+// DeleteWorkflow deletes a workflow.
+func (c *Client) DeleteWorkflow(ctx context.Context, id uuid.UUID) error {
+	return c.Do(ctx, http.MethodDelete, "/workflows/"+id.String(), nil, nil)
+}
@@ -0,0 +1,166 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Capability is the availability of an API of the Hub to the client.
+type Capability struct {
+	// Feature is the API.
+	Feature Feature `json:"feature"`
+
+	// Available reports whether the API is available: supported by the Hub version, and
+	// not found missing by the last request.
+	Available bool `json:"available"`
+
+	// Fallback describes what replaces the API while it is missing, if anything: an
+	// alternate endpoint of the Hub, or a client-side computation.
+	Fallback string `json:"fallback,omitempty"`
+
+	// Err is the error the API was found missing with.
+	Err error `json:"-"`
+}
+
+// degradation is an API found missing by a request.
+type degradation struct {
+	err      error
+	fallback string
+}
+
+// Capabilities returns the availability of the optional APIs of the Hub, as known from
+// the Hub information and the responses of the requests so far. APIs found missing are
+// available again once a request to them succeeds.
+//
+// Some features fall back while the APIs they use are missing: the location history to
+// an alternate endpoint, and fence event reports and arrival estimates to client-side
+// computation. Hubs without any location history have no fallback for it.
+func (c *Client) Capabilities() []Capability {
+	c.infoMu.Lock()
+	info := c.info
+	c.infoMu.Unlock()
+
+	c.degradedMu.Lock()
+	defer c.degradedMu.Unlock()
+
+	var caps []Capability
+	for f := Feature(0); f.String() != ""; f++ {
+		capability := Capability{Feature: f, Available: info == nil || info.Supports(f)}
+		if d, ok := c.degraded[f]; ok {
+			capability.Available = false
+			capability.Fallback = d.fallback
+			capability.Err = d.err
+		}
+		caps = append(caps, capability)
+	}
+	return caps
+}
+
+// isUnavailable reports whether the error of a request is the Hub not supporting its API.
+func isUnavailable(err error) bool {
+	return errors.Is(err, ErrNotSupported) || isEndpointUnavailable(err)
+}
+
+// available records a feature as available, after a request to its API succeeded.
+func (c *Client) available(f Feature) {
+	c.degradedMu.Lock()
+	defer c.degradedMu.Unlock()
+
+	delete(c.degraded, f)
+}
+
+// degrade records a feature as missing, replaced by a fallback, and logs the degradation
+// the first time.
+func (c *Client) degrade(ctx context.Context, f Feature, err error, fallback string) {
+	c.degradedMu.Lock()
+	_, known := c.degraded[f]
+	if c.degraded == nil {
+		c.degraded = make(map[Feature]degradation)
+	}
+	c.degraded[f] = degradation{err: err, fallback: fallback}
+	c.degradedMu.Unlock()
+
+	if !known {
+		slog.LogAttrs(ctx, slog.LevelWarn, "hub API unavailable, falling back",
+			slog.String("feature", f.String()),
+			slog.String("fallback", fallback),
+			slog.Any("err", err),
+		)
+	}
+}
+
+// fenceEventsOf derives the fence events of locations, sorted by time: the entries and
+// exits of the fences by the trackables of the locations, or by their provider for
+// locations without trackables. Trackables inside a fence at the first of their
+// locations are entering it.
+func fenceEventsOf(fences []Fence, locations []Location) []FenceEvent {
+	locations = append([]Location(nil), locations...)
+	sort.SliceStable(locations, func(i, j int) bool {
+		return timestamp(locations[i]).Before(timestamp(locations[j]))
+	})
+
+	type key struct {
+		fence uuid.UUID
+		who   string
+	}
+	// entry times of the trackables inside the fences
+	entries := make(map[key]*time.Time)
+
+	var events []FenceEvent
+	for i := range locations {
+		loc := &locations[i]
+		at := timestamp(*loc)
+
+		who := []string{loc.ProviderID}
+		if len(loc.Trackables) > 0 {
+			who = who[:0]
+			for _, id := range loc.Trackables {
+				who = append(who, id.String())
+			}
+		}
+
+		for _, fence := range fences {
+			inside := fence.Contains(*loc)
+			for j, w := range who {
+				k := key{fence.ID, w}
+				entryTime, entered := entries[k]
+				if inside == entered {
+					continue
+				}
+
+				e := FenceEvent{
+					ID:         uuid.NewSHA1(fence.ID, []byte(w+"/"+at.Format(time.RFC3339Nano))),
+					FenceID:    fence.ID,
+					ProviderID: loc.ProviderID,
+					Location:   loc,
+					ForeignID:  fence.ForeignID,
+					EntryTime:  &at,
+				}
+				if len(loc.Trackables) > 0 {
+					e.TrackableID = &loc.Trackables[j]
+					e.Trackables = []uuid.UUID{loc.Trackables[j]}
+				}
+
+				if inside {
+					e.EventType = FenceEventTypeRegionEntry
+					entries[k] = &at
+				} else {
+					e.EventType = FenceEventTypeRegionExit
+					e.EntryTime = entryTime
+					e.ExitTime = &at
+					delete(entries, k)
+				}
+				events = append(events, e)
+			}
+		}
+	}
+	return events
+}
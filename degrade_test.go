@@ -0,0 +1,234 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/tidwall/geojson/geometry"
+)
+
+func TestFenceEventsOf(t *testing.T) {
+	forklift := uuid.MustParse("d27047bd-1b6b-4656-bb93-2326a4c900e1")
+	dock := Fence{ID: uuid.MustParse("2b2fa2f6-49bb-4e6c-8e2e-4a3b8a63c1b1"), Region: NewRegionPoint(geometry.Point{X: 10, Y: 0}), Radius: 2, Crs: CrsLocal}
+
+	start := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	location := func(x float64, seconds int) Location {
+		at := start.Add(time.Duration(seconds) * time.Second)
+		return Location{Position: *NewPoint(geometry.Point{X: x}), Crs: CrsLocal, ProviderID: "a1", Trackables: []uuid.UUID{forklift}, TimestampGenerated: &at}
+	}
+
+	// unordered locations entering the fence at 8s and leaving it at 12s
+	events := fenceEventsOf([]Fence{dock}, []Location{location(11, 8), location(0, 0), location(4, 12), location(9, 10)})
+
+	type event struct {
+		Type        FenceEventType
+		TrackableID uuid.UUID
+		Entry, Exit time.Time
+	}
+	var got []event
+	for _, e := range events {
+		ev := event{Type: e.EventType, TrackableID: *e.TrackableID, Entry: *e.EntryTime}
+		if e.ExitTime != nil {
+			ev.Exit = *e.ExitTime
+		}
+		got = append(got, ev)
+	}
+	want := []event{
+		{Type: FenceEventTypeRegionEntry, TrackableID: forklift, Entry: start.Add(8 * time.Second)},
+		{Type: FenceEventTypeRegionExit, TrackableID: forklift, Entry: start.Add(8 * time.Second), Exit: start.Add(12 * time.Second)},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected events (-want +got):\n%s", diff)
+	}
+	if events[0].ID == events[1].ID {
+		t.Errorf("expected distinct event ids")
+	}
+}
+
+// degradedHub returns a client of a hub without history endpoints, other than the
+// trackable location history if history is set.
+func degradedHub(t *testing.T, history bool) (*Client, *[]string) {
+	t.Helper()
+
+	var requests []string
+	httpClient := &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			requests = append(requests, r.URL.Path)
+
+			body, status := "", http.StatusOK
+			switch {
+			case r.URL.Path == "/v2/trackables":
+				body = `["d27047bd-1b6b-4656-bb93-2326a4c900e1"]`
+			case r.URL.Path == "/v2/fences/summary":
+				body = `[]`
+			case history && strings.HasPrefix(r.URL.Path, "/v2/history/trackables/"):
+				body = `[{"position":{"type":"Point","coordinates":[0,5]},"source":"hall","provider_type":"uwb","provider_id":"a1","crs":"local","timestamp_generated":"2024-03-01T08:00:04Z"}]`
+			default:
+				body, status = `{"type":"not found","code":404,"message":"not found"}`, http.StatusNotFound
+			}
+
+			return &http.Response{
+				StatusCode: status,
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}, nil
+		}),
+	}
+
+	c, err := New("http://localhost:8081/v2", WithHTTPClient(httpClient), WithHubVersion("1.1.0"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return c, &requests
+}
+
+// capability returns the capability of a feature.
+func capability(c *Client, f Feature) Capability {
+	for _, capability := range c.Capabilities() {
+		if capability.Feature == f {
+			return capability
+		}
+	}
+	return Capability{}
+}
+
+func TestHistoryFallback(t *testing.T) {
+	c, requests := degradedHub(t, true)
+	ctx := context.Background()
+
+	if got := capability(c, FeatureHistory); !got.Available {
+		t.Errorf("expected the history to be available, got %+v", got)
+	}
+
+	from := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	events, err := c.History.FenceEvents(ctx, from, from.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("expected no events, got %+v", events)
+	}
+
+	want := []string{
+		"/v2/history/fence_events",
+		"/v2/history/locations",
+		"/v2/trackables",
+		"/v2/history/trackables/d27047bd-1b6b-4656-bb93-2326a4c900e1/locations",
+		"/v2/fences/summary",
+	}
+	if diff := cmp.Diff(want, *requests); diff != "" {
+		t.Errorf("unexpected requests (-want +got):\n%s", diff)
+	}
+
+	for _, f := range []Feature{FeatureHistory, FeatureFenceEventHistory} {
+		if got := capability(c, f); got.Available || got.Fallback == "" || !isEndpointUnavailable(got.Err) {
+			t.Errorf("expected %s to be degraded, got %+v", f, got)
+		}
+	}
+	if got := capability(c, FeatureSensors); !got.Available {
+		t.Errorf("expected the sensors to be available, got %+v", got)
+	}
+}
+
+func TestHistoryLocationsFallback(t *testing.T) {
+	forklift, pallet := "d27047bd-1b6b-4656-bb93-2326a4c900e1", "0b1e6a4d-2c3f-4e5a-8b6c-7d8e9f0a1b22"
+	shared := `{"position":{"type":"Point","coordinates":[0,5]},"source":"hall","provider_type":"uwb","provider_id":"a1","crs":"local","timestamp_generated":"2024-03-01T08:00:04Z"}`
+	own := `{"position":{"type":"Point","coordinates":[1,5]},"source":"hall","provider_type":"uwb","provider_id":"a2","crs":"local","timestamp_generated":"2024-03-01T08:00:04Z"}`
+
+	hub := func(trackablesStatus int) *Client {
+		httpClient := &http.Client{
+			Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				body, status := "", http.StatusOK
+				switch r.URL.Path {
+				case "/v2/trackables":
+					body, status = `["`+forklift+`","`+pallet+`"]`, trackablesStatus
+					if status != http.StatusOK {
+						body = `{"type":"forbidden","code":403,"message":"forbidden"}`
+					}
+				case "/v2/history/trackables/" + forklift + "/locations":
+					body = "[" + shared + "]"
+				case "/v2/history/trackables/" + pallet + "/locations":
+					body = "[" + shared + "," + own + "]"
+				default:
+					body, status = `{"type":"not found","code":404,"message":"not found"}`, http.StatusNotFound
+				}
+				return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}, nil
+			}),
+		}
+		c, err := New("http://localhost:8081/v2", WithHTTPClient(httpClient), WithHubVersion("1.1.0"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return c
+	}
+
+	ctx := context.Background()
+	from := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	locations, err := hub(http.StatusOK).History.Locations(ctx, from, from.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var providers []string
+	for _, l := range locations {
+		providers = append(providers, l.ProviderID)
+	}
+	if diff := cmp.Diff([]string{"a1", "a2"}, providers); diff != "" {
+		t.Errorf("unexpected locations (-want +got):\n%s", diff)
+	}
+
+	// failures of the alternate endpoint are returned instead of the missing endpoint
+	var e *Error
+	if _, err := hub(http.StatusForbidden).History.Locations(ctx, from, from.Add(time.Minute)); !errors.As(err, &e) || e.Code != http.StatusForbidden {
+		t.Errorf("expected a forbidden error, got %v", err)
+	}
+	if _, err := hub(http.StatusForbidden).History.FenceEvents(ctx, from, from.Add(time.Minute)); !errors.As(err, &e) || e.Code != http.StatusForbidden {
+		t.Errorf("expected a forbidden error, got %v", err)
+	}
+}
+
+func TestAnalyticsSpeedWithoutHistory(t *testing.T) {
+	c, _ := degradedHub(t, false)
+	ctx := context.Background()
+
+	trackableID := uuid.MustParse("d27047bd-1b6b-4656-bb93-2326a4c900e1")
+	location := func(x float64, seconds int) Location {
+		at := time.Date(2024, 3, 1, 8, 0, seconds, 0, time.UTC)
+		return Location{Position: *NewPoint(geometry.Point{X: x, Y: 5}), Crs: CrsLocal, TimestampGenerated: &at}
+	}
+
+	// the first estimate has no previous location
+	loc := location(-4, 0)
+	previous := c.Analytics.remember(trackableID, loc)
+	if _, err := c.Analytics.speed(ctx, trackableID, &loc, previous); !isEndpointUnavailable(err) {
+		t.Fatalf("expected an unavailable history error, got %v", err)
+	}
+
+	// older locations are not remembered
+	old := location(-8, -4)
+	if previous := c.Analytics.remember(trackableID, old); previous != nil {
+		t.Errorf("expected no previous location, got %+v", previous)
+	}
+
+	loc = location(0, 4)
+	previous = c.Analytics.remember(trackableID, loc)
+	speed, err := c.Analytics.speed(ctx, trackableID, &loc, previous)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if speed != 1 {
+		t.Errorf("expected speed 1, got %v", speed)
+	}
+	if got := capability(c, FeatureHistory); got.Available || got.Fallback != "client-side: speed estimated from the previous location" {
+		t.Errorf("expected the history to be degraded, got %+v", got)
+	}
+}
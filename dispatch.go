@@ -0,0 +1,278 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxOrderKeys is the number of ordering keys whose last timestamp is remembered to
+// drop out of order events. The least recently seen half is evicted past it.
+const maxOrderKeys = 4096
+
+// dispatcher hands the events of a subscription to a handler on a fixed number of
+// workers, while serializing the events of each trackable: events sharing an ordering
+// key are handled one at a time, in arrival order, and events of different keys
+// concurrently. Events of several trackables have a key per trackable, and are handled
+// after the events dispatched before them for any of their trackables.
+//
+// Each key queues a bounded number of events: dispatch blocks while the queue of one of
+// the keys of the event is full, so slow handlers hold back the websocket reader instead
+// of buffering events without limit.
+//
+// When dropLate is set, events older than the last event dispatched for one of their
+// keys arrived out of order, and are dropped rather than handled after newer ones.
+// Events without timestamp are never dropped.
+type dispatcher struct {
+	handle    func(*TopicEvent)
+	queueSize int
+	dropLate  bool
+
+	mu   sync.Mutex
+	cond *sync.Cond // signaled when events are handled, ready or closed
+	// queues of the events by ordering key, the head being handled or ready
+	queues map[string][]*dispatchItem
+	// events at the head of all of their queues, waiting for a worker
+	ready  []*dispatchItem
+	closed bool
+
+	// timestamps of the last events dispatched by ordering key, when dropping late events
+	last map[string]orderMark
+	seq  uint64
+
+	wg sync.WaitGroup
+}
+
+// dispatchItem is an event queued for the handler.
+type dispatchItem struct {
+	event *TopicEvent
+	keys  []string
+	ready bool
+}
+
+// orderMark is the timestamp of the last event of an ordering key.
+type orderMark struct {
+	at  time.Time
+	seq uint64
+}
+
+// newDispatcher returns a dispatcher handling events with the given number of workers,
+// queuing up to queueSize events by ordering key.
+func newDispatcher(workers, queueSize int, dropLate bool, handle func(*TopicEvent)) *dispatcher {
+	d := &dispatcher{
+		handle:    handle,
+		queueSize: max(queueSize, 1),
+		dropLate:  dropLate,
+		queues:    make(map[string][]*dispatchItem),
+		last:      make(map[string]orderMark),
+	}
+	d.cond = sync.NewCond(&d.mu)
+
+	for i := 0; i < max(workers, 1); i++ {
+		d.wg.Add(1)
+		go d.work()
+	}
+	return d
+}
+
+// dispatch queues an event after the events of its keys, waiting while one of their
+// queues is full. It reports whether the event was queued, or dropped for arriving out
+// of order.
+func (d *dispatcher) dispatch(ctx context.Context, e *TopicEvent, payload json.RawMessage) bool {
+	keys, at := eventOrder(e, payload)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for d.full(keys) {
+		d.cond.Wait()
+	}
+
+	if d.dropLate && !at.IsZero() {
+		if key, last, late := d.late(keys, at); late {
+			slog.LogAttrs(ctx, slog.LevelDebug, "dropped out of order event",
+				slog.String("topic", string(e.Topic)),
+				slog.String("key", key),
+				slog.Time("timestamp", at),
+				slog.Time("last", last),
+			)
+			return false
+		}
+		d.mark(keys, at)
+	}
+
+	item := &dispatchItem{event: e, keys: keys}
+	for _, key := range keys {
+		d.queues[key] = append(d.queues[key], item)
+	}
+	d.promote(item)
+	return true
+}
+
+// full reports whether the queue of one of the keys is full.
+func (d *dispatcher) full(keys []string) bool {
+	for _, key := range keys {
+		if len(d.queues[key]) >= d.queueSize {
+			return true
+		}
+	}
+	return false
+}
+
+// late returns the key whose last event is newer than the given time, if any.
+func (d *dispatcher) late(keys []string, at time.Time) (string, time.Time, bool) {
+	for _, key := range keys {
+		if last, ok := d.last[key]; ok && at.Before(last.at) {
+			return key, last.at, true
+		}
+	}
+	return "", time.Time{}, false
+}
+
+// mark records the time of the last event of the keys, evicting the least recently seen
+// keys when too many are remembered.
+func (d *dispatcher) mark(keys []string, at time.Time) {
+	d.seq++
+	for _, key := range keys {
+		d.last[key] = orderMark{at: at, seq: d.seq}
+	}
+	if len(d.last) <= maxOrderKeys {
+		return
+	}
+
+	seqs := make([]uint64, 0, len(d.last))
+	for _, mark := range d.last {
+		seqs = append(seqs, mark.seq)
+	}
+	slices.Sort(seqs)
+	oldest := seqs[len(seqs)/2]
+	for key, mark := range d.last {
+		if mark.seq < oldest {
+			delete(d.last, key)
+		}
+	}
+}
+
+// promote makes an item ready for a worker once it is at the head of all of its queues.
+func (d *dispatcher) promote(item *dispatchItem) {
+	if item.ready {
+		return
+	}
+	for _, key := range item.keys {
+		if d.queues[key][0] != item {
+			return
+		}
+	}
+	item.ready = true
+	d.ready = append(d.ready, item)
+	d.cond.Broadcast()
+}
+
+// work handles ready events until the dispatcher is closed and drained.
+func (d *dispatcher) work() {
+	defer d.wg.Done()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for {
+		for len(d.ready) == 0 && !(d.closed && len(d.queues) == 0) {
+			d.cond.Wait()
+		}
+		if len(d.ready) == 0 {
+			return
+		}
+
+		item := d.ready[0]
+		d.ready = d.ready[1:]
+
+		d.mu.Unlock()
+		d.handle(item.event)
+		d.mu.Lock()
+
+		for _, key := range item.keys {
+			q := d.queues[key][1:]
+			if len(q) == 0 {
+				delete(d.queues, key)
+				continue
+			}
+			d.queues[key] = q
+			d.promote(q[0])
+		}
+		d.cond.Broadcast()
+	}
+}
+
+// close waits for the events dispatched to be handled, and stops the workers.
+func (d *dispatcher) close() {
+	d.mu.Lock()
+	d.closed = true
+	d.cond.Broadcast()
+	d.mu.Unlock()
+
+	d.wg.Wait()
+}
+
+// eventOrder returns the ordering keys of an event, the ids of its trackables or else
+// the id of its location provider, and the time it is ordered by, zero if unknown.
+// Events without trackables nor provider share the empty key.
+func eventOrder(e *TopicEvent, payload json.RawMessage) ([]string, time.Time) {
+	var (
+		trackables []uuid.UUID
+		provider   string
+		at         time.Time
+	)
+
+	switch v := e.Value.(type) {
+	case *Location:
+		trackables, provider, at = v.Trackables, v.ProviderID, timestamp(*v)
+	case *FenceEvent:
+		trackables, provider, at = v.Trackables, v.ProviderID, v.Time()
+		if v.TrackableID != nil {
+			trackables = append(slices.Clip(trackables), *v.TrackableID)
+		}
+	case *CollisionEvent:
+		trackables = v.Trackables()
+		if v.TimestampGenerated != nil {
+			at = *v.TimestampGenerated
+		}
+	default:
+		var refs filterReferences
+		if err := json.Unmarshal(payload, &refs); err != nil {
+			return []string{""}, time.Time{}
+		}
+
+		trackables, provider = refs.Trackables, refs.ProviderID
+		if refs.TrackableID != nil {
+			trackables = append(trackables, *refs.TrackableID)
+		}
+		if refs.ID != nil && e.Topic == TopicTrackableMotions {
+			trackables = append(trackables, *refs.ID)
+		}
+		if refs.Location != nil {
+			trackables = append(trackables, refs.Location.Trackables...)
+			if provider == "" {
+				provider = refs.Location.ProviderID
+			}
+		}
+	}
+
+	if len(trackables) == 0 {
+		return []string{provider}, at
+	}
+
+	keys := make([]string, 0, len(trackables))
+	for _, id := range trackables {
+		keys = append(keys, id.String())
+	}
+	slices.Sort(keys)
+	return slices.Compact(keys), at
+}
@@ -0,0 +1,260 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func TestDispatcherOrder(t *testing.T) {
+	trackables := []uuid.UUID{
+		uuid.MustParse("d27047bd-1b6b-4656-bb93-2326a4c900e1"),
+		uuid.MustParse("9b8a7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d"),
+		uuid.MustParse("6c5d2f0e-8f6a-4b7e-9a51-0d6f1a2b3c41"),
+	}
+	start := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	event := func(trackable uuid.UUID, seconds int) *TopicEvent {
+		at := start.Add(time.Duration(seconds) * time.Second)
+		return &TopicEvent{Topic: TopicFenceEvents, Value: &FenceEvent{TrackableID: &trackable, EventType: FenceEventTypeRegionEntry, EntryTime: &at}}
+	}
+
+	// the first events of every trackable are handled concurrently
+	var first sync.WaitGroup
+	first.Add(len(trackables))
+
+	var (
+		mu       sync.Mutex
+		got      = make(map[uuid.UUID][]int)
+		inFlight = make(map[uuid.UUID]int)
+	)
+	d := newDispatcher(len(trackables), 64, false, func(e *TopicEvent) {
+		fe := e.Value.(*FenceEvent)
+		id := *fe.TrackableID
+
+		mu.Lock()
+		if inFlight[id]++; inFlight[id] > 1 {
+			t.Errorf("events of %s handled concurrently", id)
+		}
+		n := len(got[id])
+		got[id] = append(got[id], int(fe.EntryTime.Sub(start)/time.Second))
+		mu.Unlock()
+
+		if n == 0 {
+			first.Done()
+			first.Wait()
+		}
+
+		mu.Lock()
+		inFlight[id]--
+		mu.Unlock()
+	})
+
+	ctx := context.Background()
+	want := make(map[uuid.UUID][]int)
+	for i := 0; i < 20; i++ {
+		for _, id := range trackables {
+			d.dispatch(ctx, event(id, i), nil)
+			want[id] = append(want[id], i)
+		}
+	}
+
+	// late events are handled in arrival order by default
+	if !d.dispatch(ctx, event(trackables[0], 10), nil) {
+		t.Errorf("expected the late event to be dispatched")
+	}
+	want[trackables[0]] = append(want[trackables[0]], 10)
+
+	d.close()
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected events (-want +got):\n%s", diff)
+	}
+}
+
+func TestDispatcherDropLate(t *testing.T) {
+	forklift := uuid.MustParse("d27047bd-1b6b-4656-bb93-2326a4c900e1")
+	pallet := uuid.MustParse("9b8a7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d")
+	start := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	event := func(trackable uuid.UUID, seconds int) *TopicEvent {
+		at := start.Add(time.Duration(seconds) * time.Second)
+		return &TopicEvent{Topic: TopicFenceEvents, Value: &FenceEvent{TrackableID: &trackable, EventType: FenceEventTypeRegionEntry, EntryTime: &at}}
+	}
+
+	var (
+		mu  sync.Mutex
+		got = make(map[uuid.UUID][]int)
+	)
+	d := newDispatcher(1, 64, true, func(e *TopicEvent) {
+		fe := e.Value.(*FenceEvent)
+		mu.Lock()
+		got[*fe.TrackableID] = append(got[*fe.TrackableID], int(fe.EntryTime.Sub(start)/time.Second))
+		mu.Unlock()
+	})
+
+	ctx := context.Background()
+	for _, tc := range []struct {
+		trackable uuid.UUID
+		seconds   int
+		want      bool
+	}{
+		{forklift, 10, true},
+		{forklift, 5, false}, // late
+		{forklift, 10, true}, // same time
+		{pallet, 5, true},    // other trackable
+		{forklift, 11, true},
+	} {
+		if ok := d.dispatch(ctx, event(tc.trackable, tc.seconds), nil); ok != tc.want {
+			t.Errorf("dispatch of %s at %d: expected %v, got %v", tc.trackable, tc.seconds, tc.want, ok)
+		}
+	}
+
+	d.close()
+	want := map[uuid.UUID][]int{forklift: {10, 10, 11}, pallet: {5}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected events (-want +got):\n%s", diff)
+	}
+}
+
+func TestDispatcherEvictsOrderKeys(t *testing.T) {
+	d := newDispatcher(1, 1, true, func(*TopicEvent) {})
+	defer d.close()
+
+	at := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	for i := 0; i < 3*maxOrderKeys; i++ {
+		d.dispatch(context.Background(), &TopicEvent{Topic: TopicLocationUpdates, Value: &Location{ProviderID: uuid.NewString(), TimestampGenerated: &at}}, nil)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if n := len(d.last); n > maxOrderKeys {
+		t.Errorf("expected at most %d order keys, got %d", maxOrderKeys, n)
+	}
+}
+
+func TestDispatcherBackpressure(t *testing.T) {
+	forklift := uuid.MustParse("d27047bd-1b6b-4656-bb93-2326a4c900e1")
+	event := &TopicEvent{Topic: TopicLocationUpdates, Value: &Location{ProviderID: "a1", Trackables: []uuid.UUID{forklift}}}
+
+	release := make(chan struct{})
+	d := newDispatcher(1, 2, false, func(*TopicEvent) { <-release })
+
+	// the first event is handled, and the queue of the trackable is full
+	ctx := context.Background()
+	d.dispatch(ctx, event, nil)
+	d.dispatch(ctx, event, nil)
+
+	dispatched := make(chan struct{})
+	go func() {
+		d.dispatch(ctx, event, nil)
+		close(dispatched)
+	}()
+
+	select {
+	case <-dispatched:
+		t.Fatal("expected dispatch to block while the queue is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release <- struct{}{}
+	select {
+	case <-dispatched:
+	case <-time.After(time.Second):
+		t.Fatal("expected dispatch to resume once an event was handled")
+	}
+
+	close(release)
+	d.close()
+}
+
+func TestDispatcherSeveralTrackables(t *testing.T) {
+	forklift := uuid.MustParse("d27047bd-1b6b-4656-bb93-2326a4c900e1")
+	pallet := uuid.MustParse("9b8a7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d")
+	location := func(name string, trackables ...uuid.UUID) *TopicEvent {
+		return &TopicEvent{Topic: TopicLocationUpdates, Value: &Location{ProviderID: name, Trackables: trackables}}
+	}
+
+	var (
+		mu  sync.Mutex
+		got []string
+	)
+	block := make(chan struct{})
+	d := newDispatcher(2, 64, false, func(e *TopicEvent) {
+		name := e.Value.(*Location).ProviderID
+		if name == "pallet" {
+			<-block
+		}
+		mu.Lock()
+		got = append(got, name)
+		mu.Unlock()
+	})
+
+	// the collision of both waits for the slow event of the pallet, even though
+	// the forklift is not the smallest of their ids
+	ctx := context.Background()
+	d.dispatch(ctx, location("pallet", pallet), nil)
+	d.dispatch(ctx, location("forklift", forklift), nil)
+	d.dispatch(ctx, location("both", forklift, pallet), nil)
+	d.dispatch(ctx, location("after", forklift), nil)
+
+	time.Sleep(50 * time.Millisecond)
+	close(block)
+	d.close()
+
+	if diff := cmp.Diff([]string{"forklift", "pallet", "both", "after"}, got); diff != "" {
+		t.Errorf("unexpected events (-want +got):\n%s", diff)
+	}
+}
+
+func TestEventOrder(t *testing.T) {
+	forklift := uuid.MustParse("d27047bd-1b6b-4656-bb93-2326a4c900e1")
+	pallet := uuid.MustParse("9b8a7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d")
+	at := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name    string
+		event   *TopicEvent
+		payload string
+		keys    []string
+		at      time.Time
+	}{
+		{
+			name:  "location",
+			event: &TopicEvent{Topic: TopicLocationUpdates, Value: &Location{ProviderID: "a1", Trackables: []uuid.UUID{forklift}, TimestampGenerated: &at}},
+			keys:  []string{forklift.String()},
+			at:    at,
+		},
+		{
+			name:  "location without trackables",
+			event: &TopicEvent{Topic: TopicLocationUpdates, Value: &Location{ProviderID: "a1"}},
+			keys:  []string{"a1"},
+		},
+		{
+			name:  "collision",
+			event: &TopicEvent{Topic: TopicCollisionEvents, Value: &CollisionEvent{Collisions: []Collision{{ObjectID: forklift}, {ObjectID: pallet}}, TimestampGenerated: &at}},
+			keys:  []string{pallet.String(), forklift.String()},
+			at:    at,
+		},
+		{
+			name:    "trackable motion",
+			event:   &TopicEvent{Topic: TopicTrackableMotions},
+			payload: `{"id":"d27047bd-1b6b-4656-bb93-2326a4c900e1","location":{"provider_id":"a1"}}`,
+			keys:    []string{forklift.String()},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			keys, got := eventOrder(tc.event, []byte(tc.payload))
+			if !slices.Equal(keys, tc.keys) || !got.Equal(tc.at) {
+				t.Errorf("expected %v at %v, got %v at %v", tc.keys, tc.at, keys, got)
+			}
+		})
+	}
+}
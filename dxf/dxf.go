@@ -0,0 +1,298 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package dxf reads the floorplans of facilities exported from CAD software as ASCII
+// DXF files, and creates zones and fences from the closed polylines of their layers.
+//
+// Only the entities needed for floorplans are read: lightweight and legacy polylines,
+// and single and multi-line texts labeling them. Arcs of polylines are read as straight
+// segments. DWG and binary DXF files must be exported as ASCII DXF first.
+package dxf
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+// ErrUnsupportedFormat is returned for DWG and binary DXF files.
+var ErrUnsupportedFormat = errors.New("unsupported drawing format: export the floorplan as ASCII DXF")
+
+// unitScales are the meters of the drawing units, by $INSUNITS code.
+var unitScales = map[int]float64{
+	1:  0.0254,
+	2:  0.3048,
+	4:  0.001,
+	5:  0.01,
+	6:  1,
+	14: 0.1,
+}
+
+// Polyline is a polyline of a drawing.
+type Polyline struct {
+	// Layer of the polyline.
+	Layer string
+
+	// Handle is the unique identifier of the polyline in the drawing.
+	Handle string
+
+	// Closed reports whether the polyline is closed, as the outline of an area.
+	Closed bool
+
+	// Points are the vertices of the polyline, in drawing units.
+	Points []geometry.Point
+}
+
+// Text is a text of a drawing, such as the label of an area.
+type Text struct {
+	// Layer of the text.
+	Layer string
+
+	// Position is the insertion point of the text, in drawing units.
+	Position geometry.Point
+
+	// Value is the text, without formatting.
+	Value string
+}
+
+// Drawing are the polylines and texts of a DXF file.
+type Drawing struct {
+	// Units is the $INSUNITS code of the drawing units, 0 if unitless.
+	Units int
+
+	Polylines []Polyline
+	Texts     []Text
+}
+
+// Scale returns the meters of a drawing unit. Unitless drawings, and drawings of
+// unsupported units, are assumed to be in meters.
+func (d *Drawing) Scale() float64 {
+	if s, ok := unitScales[d.Units]; ok {
+		return s
+	}
+	return 1
+}
+
+// Layers returns the layers of the polylines of the drawing, in order of appearance.
+func (d *Drawing) Layers() []string {
+	var layers []string
+	seen := make(map[string]bool)
+	for _, p := range d.Polylines {
+		if !seen[p.Layer] {
+			seen[p.Layer] = true
+			layers = append(layers, p.Layer)
+		}
+	}
+	return layers
+}
+
+// pair is a group code and its value.
+type pair struct {
+	code  int
+	value string
+}
+
+// Parse reads a drawing from an ASCII DXF file.
+func Parse(r io.Reader) (*Drawing, error) {
+	br := bufio.NewReader(r)
+	if head, _ := br.Peek(22); bytes.HasPrefix(head, []byte("AutoCAD Binary DXF")) || bytes.HasPrefix(head, []byte("AC10")) {
+		return nil, ErrUnsupportedFormat
+	}
+
+	pairs, err := readPairs(br)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Drawing{}
+	var section string
+	for i := 0; i < len(pairs); i++ {
+		p := pairs[i]
+
+		switch {
+		case p.code == 0 && p.value == "SECTION" && i+1 < len(pairs):
+			section = pairs[i+1].value
+			i++
+		case p.code == 0 && p.value == "ENDSEC":
+			section = ""
+		case section == "HEADER" && p.code == 9 && p.value == "$INSUNITS" && i+1 < len(pairs):
+			d.Units, _ = strconv.Atoi(pairs[i+1].value)
+			i++
+		case section == "ENTITIES" && p.code == 0:
+			// the pairs of the entity, up to the next entity
+			end := i + 1
+			for end < len(pairs) && pairs[end].code != 0 {
+				end++
+			}
+			entity := pairs[i+1 : end]
+
+			switch p.value {
+			case "LWPOLYLINE":
+				d.Polylines = append(d.Polylines, lwPolyline(entity))
+			case "POLYLINE":
+				pl, next := polyline(entity, pairs, end)
+				d.Polylines = append(d.Polylines, pl)
+				end = next
+			case "TEXT", "MTEXT":
+				d.Texts = append(d.Texts, text(entity))
+			}
+			i = end - 1
+		}
+	}
+
+	return d, nil
+}
+
+// readPairs reads the group codes and values of a DXF file.
+func readPairs(r *bufio.Reader) ([]pair, error) {
+	var (
+		pairs []pair
+		line  int
+	)
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for sc.Scan() {
+		line++
+		code, err := strconv.Atoi(strings.TrimSpace(sc.Text()))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid group code %q", line, sc.Text())
+		}
+		if !sc.Scan() {
+			return nil, fmt.Errorf("line %d: missing value of group code %d", line, code)
+		}
+		line++
+		pairs = append(pairs, pair{code: code, value: strings.TrimSpace(sc.Text())})
+	}
+
+	return pairs, sc.Err()
+}
+
+// lwPolyline returns the polyline of a LWPOLYLINE entity.
+func lwPolyline(entity []pair) Polyline {
+	var pl Polyline
+	for _, p := range entity {
+		switch p.code {
+		case 8:
+			pl.Layer = p.value
+		case 5:
+			pl.Handle = p.value
+		case 70:
+			flags, _ := strconv.Atoi(p.value)
+			pl.Closed = flags&1 != 0
+		case 10:
+			x, _ := strconv.ParseFloat(p.value, 64)
+			pl.Points = append(pl.Points, geometry.Point{X: x})
+		case 20:
+			if len(pl.Points) > 0 {
+				pl.Points[len(pl.Points)-1].Y, _ = strconv.ParseFloat(p.value, 64)
+			}
+		}
+	}
+	return closeRing(pl)
+}
+
+// polyline returns the polyline of a POLYLINE entity and of the VERTEX entities
+// following it from the pair at start, up to the SEQEND entity, and the pair after them.
+func polyline(entity []pair, pairs []pair, start int) (Polyline, int) {
+	var pl Polyline
+	for _, p := range entity {
+		switch p.code {
+		case 8:
+			pl.Layer = p.value
+		case 5:
+			pl.Handle = p.value
+		case 70:
+			flags, _ := strconv.Atoi(p.value)
+			pl.Closed = flags&1 != 0
+		}
+	}
+
+	i := start
+	for i < len(pairs) && pairs[i].code == 0 && pairs[i].value == "VERTEX" {
+		var v geometry.Point
+		for i++; i < len(pairs) && pairs[i].code != 0; i++ {
+			switch pairs[i].code {
+			case 10:
+				v.X, _ = strconv.ParseFloat(pairs[i].value, 64)
+			case 20:
+				v.Y, _ = strconv.ParseFloat(pairs[i].value, 64)
+			}
+		}
+		pl.Points = append(pl.Points, v)
+	}
+
+	// skip the SEQEND entity
+	if i < len(pairs) && pairs[i].code == 0 && pairs[i].value == "SEQEND" {
+		for i++; i < len(pairs) && pairs[i].code != 0; i++ {
+		}
+	}
+
+	return closeRing(pl), i
+}
+
+// closeRing marks the polylines ending at their first point as closed, without the
+// repeated point.
+func closeRing(pl Polyline) Polyline {
+	if n := len(pl.Points); n > 3 && pl.Points[0] == pl.Points[n-1] {
+		pl.Points = pl.Points[:n-1]
+		pl.Closed = true
+	}
+	return pl
+}
+
+// text returns the text of a TEXT or MTEXT entity.
+func text(entity []pair) Text {
+	var (
+		t     Text
+		value strings.Builder
+	)
+	for _, p := range entity {
+		switch p.code {
+		case 8:
+			t.Layer = p.value
+		case 10:
+			t.Position.X, _ = strconv.ParseFloat(p.value, 64)
+		case 20:
+			t.Position.Y, _ = strconv.ParseFloat(p.value, 64)
+		case 1, 3:
+			// long multi-line texts are split in chunks of group code 3, ending with 1
+			value.WriteString(p.value)
+		}
+	}
+
+	t.Value = plainText(value.String())
+	return t
+}
+
+// plainText returns a text without the formatting codes of multi-line texts.
+func plainText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '{' || c == '}':
+		case c == '\\' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'P', 'n':
+				b.WriteByte(' ')
+			case '\\', '{', '}':
+				b.WriteByte(s[i])
+			default:
+				// formatting codes with a value end with a semicolon
+				if j := strings.IndexByte(s[i:], ';'); j >= 0 && strings.ContainsRune("ACFHQTWfhpc", rune(s[i])) {
+					i += j
+				}
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
@@ -0,0 +1,127 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package dxf
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+// floorplan is a hall of 20x10m, in millimeters, with a labeled storage room and an
+// unlabeled office drawn as a legacy polyline.
+var floorplan = strings.Join([]string{
+	"0", "SECTION", "2", "HEADER",
+	"9", "$INSUNITS", "70", "4",
+	"0", "ENDSEC",
+	"0", "SECTION", "2", "ENTITIES",
+	"0", "LWPOLYLINE", "5", "2A", "8", "Walls", "70", "1",
+	"10", "0", "20", "0",
+	"10", "20000", "20", "0",
+	"10", "20000", "20", "10000",
+	"10", "0", "20", "10000",
+	"0", "LWPOLYLINE", "5", "2B", "8", "Rooms", "70", "1",
+	"10", "1000", "20", "1000",
+	"10", "1000", "20", "4000",
+	"10", "5000", "20", "4000",
+	"10", "5000", "20", "1000",
+	"0", "MTEXT", "8", "Labels", "10", "2000", "20", "2000",
+	"1", `{\fArial|b1;Storage\P A}`,
+	"0", "POLYLINE", "5", "2C", "8", "Rooms", "70", "0",
+	"0", "VERTEX", "8", "Rooms", "10", "10000", "20", "1000",
+	"0", "VERTEX", "8", "Rooms", "10", "14000", "20", "1000",
+	"0", "VERTEX", "8", "Rooms", "10", "14000", "20", "4000",
+	"0", "VERTEX", "8", "Rooms", "10", "10000", "20", "1000",
+	"0", "SEQEND", "8", "Rooms",
+	"0", "LINE", "8", "Rooms", "10", "0", "20", "0", "11", "1", "21", "1",
+	"0", "ENDSEC",
+	"0", "EOF",
+}, "\n")
+
+func parse(t *testing.T) *Drawing {
+	t.Helper()
+
+	d, err := Parse(strings.NewReader(floorplan))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return d
+}
+
+func TestParse(t *testing.T) {
+	d := parse(t)
+
+	if d.Scale() != 0.001 {
+		t.Errorf("expected millimeters, got scale %v", d.Scale())
+	}
+	if layers := d.Layers(); len(layers) != 2 || layers[0] != "Walls" || layers[1] != "Rooms" {
+		t.Errorf("unexpected layers %v", layers)
+	}
+
+	if len(d.Polylines) != 3 {
+		t.Fatalf("expected 3 polylines, got %d", len(d.Polylines))
+	}
+	if pl := d.Polylines[1]; pl.Handle != "2B" || !pl.Closed || len(pl.Points) != 4 || pl.Points[2] != (geometry.Point{X: 5000, Y: 4000}) {
+		t.Errorf("unexpected polyline %+v", pl)
+	}
+	// closed by repeating its first point
+	if pl := d.Polylines[2]; pl.Handle != "2C" || !pl.Closed || len(pl.Points) != 3 {
+		t.Errorf("unexpected legacy polyline %+v", pl)
+	}
+
+	if len(d.Texts) != 1 || d.Texts[0].Value != "Storage A" {
+		t.Errorf("unexpected texts %+v", d.Texts)
+	}
+
+	if _, err := Parse(strings.NewReader("AC1032 drawing")); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("expected ErrUnsupportedFormat for DWG files, got %v", err)
+	}
+	if _, err := Parse(strings.NewReader("0\nSECTION\nfoo\n")); err == nil {
+		t.Error("expected error for invalid group code")
+	}
+}
+
+func TestImport(t *testing.T) {
+	d := parse(t)
+
+	// the origin of the drawing, and 20m east of it
+	g, err := NewGeoreference(d, []omlox.GroundControlPoint{
+		{Local: *omlox.NewPoint(geometry.Point{X: 0, Y: 0}), WGS84: *omlox.NewPoint(geometry.Point{X: 7.8157, Y: 48.1302})},
+		{Local: *omlox.NewPoint(geometry.Point{X: 20000, Y: 0}), WGS84: *omlox.NewPoint(geometry.Point{X: 7.81596918, Y: 48.1302})},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	zones := d.Zones(g, omlox.LocationProviderTypeUwb, "Walls")
+	if len(zones) != 1 {
+		t.Fatalf("expected 1 zone, got %d", len(zones))
+	}
+	z := zones[0]
+	if z.Name != "Walls-1" || z.Radius != 12 || len(z.GroundControlPoints) != 2 {
+		t.Errorf("unexpected zone %+v", z)
+	}
+	if p := z.Position.Base(); math.Abs(p.X-7.81583459) > 1e-6 || math.Abs(p.Y-48.130245) > 1e-6 {
+		t.Errorf("unexpected zone position %v", p)
+	}
+	if local := z.GroundControlPoints[1].Local.Base(); local.X != 20 {
+		t.Errorf("expected local coordinates in meters, got %v", local)
+	}
+	if string(z.Properties) != `{"dxf":{"handle":"2A","layer":"Walls"}}` {
+		t.Errorf("unexpected properties %s", z.Properties)
+	}
+
+	fences := d.Fences(g, "Rooms")
+	if len(fences) != 2 || fences[0].Name != "Storage A" || fences[1].Name != "Rooms-2" || fences[0].Crs != omlox.CrsWGS84 {
+		t.Errorf("unexpected fences %+v", fences)
+	}
+
+	if _, err := NewGeoreference(d, nil); err == nil {
+		t.Error("expected error without ground control points")
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package dxf
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+// Georeference places a drawing on the earth, from ground control points known both in
+// the drawing and in WGS84.
+type Georeference struct {
+	gcps   []omlox.GroundControlPoint
+	georef *omlox.Georeference
+	scale  float64
+}
+
+// NewGeoreference returns the georeference of a drawing from at least two ground control
+// points, with their local coordinates in drawing units.
+func NewGeoreference(d *Drawing, gcps []omlox.GroundControlPoint) (*Georeference, error) {
+	g := &Georeference{scale: d.Scale()}
+
+	// the local coordinates of the zones are the drawing coordinates in meters
+	for _, gcp := range gcps {
+		local := gcp.Local.Base()
+		g.gcps = append(g.gcps, omlox.GroundControlPoint{
+			WGS84: gcp.WGS84,
+			Local: *omlox.NewPoint(geometry.Point{X: local.X * g.scale, Y: local.Y * g.scale}),
+		})
+	}
+
+	var err error
+	if g.georef, err = (omlox.Zone{GroundControlPoints: g.gcps}).Georeference(); err != nil {
+		return nil, fmt.Errorf("invalid georeference of the drawing: %w", err)
+	}
+
+	return g, nil
+}
+
+// toWGS84 converts a point in drawing units to WGS84.
+func (g *Georeference) toWGS84(p geometry.Point) geometry.Point {
+	return g.georef.ToWGS84(geometry.Point{X: p.X * g.scale, Y: p.Y * g.scale})
+}
+
+// sourceProperty is the property of the imported zones and fences with the layer and
+// handle of their polyline.
+const sourceProperty = "dxf"
+
+// area is a closed polyline of the layers of zones or fences, with its name.
+type area struct {
+	Polyline
+	name string
+}
+
+// properties returns the properties of the zone or fence of the area.
+func (a area) properties() json.RawMessage {
+	props, _ := json.Marshal(map[string]any{
+		sourceProperty: map[string]string{"layer": a.Layer, "handle": a.Handle},
+	})
+	return props
+}
+
+// areas returns the closed polylines of the layers, named after the text within them,
+// or else after their layer and number in the layer.
+func (d *Drawing) areas(layers []string) []area {
+	// texts label the smallest closed polyline around them, such as a room rather than
+	// the hall around it
+	labels := make(map[int]string)
+	for _, t := range d.Texts {
+		if t.Value == "" {
+			continue
+		}
+		label, smallest := -1, math.Inf(1)
+		for i, pl := range d.Polylines {
+			if a := math.Abs(signedArea(pl.Points)); pl.Closed && a < smallest && contains(pl.Points, t.Position) {
+				label, smallest = i, a
+			}
+		}
+		if _, ok := labels[label]; !ok && label >= 0 {
+			labels[label] = t.Value
+		}
+	}
+
+	var (
+		areas []area
+		count = make(map[string]int)
+	)
+	for i, pl := range d.Polylines {
+		if !pl.Closed || len(pl.Points) < 3 || !slices.Contains(layers, pl.Layer) {
+			continue
+		}
+		count[pl.Layer]++
+
+		a := area{Polyline: pl, name: fmt.Sprintf("%s-%d", pl.Layer, count[pl.Layer])}
+		if label, ok := labels[i]; ok {
+			a.name = label
+		}
+		areas = append(areas, a)
+	}
+	return areas
+}
+
+// Zones returns the zones of the closed polylines of the layers, of the type of location
+// providers. The zones have the ground control points of the georeference, so their local
+// coordinates are the drawing coordinates in meters, and the position and radius of the
+// circle around the polyline. The layer and handle of the polyline are kept in the "dxf"
+// property.
+//
+// Polylines are named after the text within them, such as a room name, or else after
+// their layer and number in the layer, such as hall-1.
+func (d *Drawing) Zones(g *Georeference, typ omlox.LocationProviderType, layers ...string) []omlox.Zone {
+	var zones []omlox.Zone
+	for _, a := range d.areas(layers) {
+		minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+		for _, p := range a.Points {
+			minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+			minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+		}
+		center := geometry.Point{X: (minX + maxX) / 2, Y: (minY + maxY) / 2}
+
+		radius := 0.0
+		for _, p := range a.Points {
+			radius = math.Max(radius, math.Hypot(p.X-center.X, p.Y-center.Y)*g.scale)
+		}
+
+		zones = append(zones, omlox.Zone{
+			Type:                typ,
+			Name:                a.name,
+			Position:            omlox.NewPoint(g.toWGS84(center)),
+			Radius:              math.Ceil(radius),
+			GroundControlPoints: slices.Clone(g.gcps),
+			Properties:          a.properties(),
+		})
+	}
+	return zones
+}
+
+// Fences returns the WGS84 fences of the closed polylines of the layers, named as zones.
+func (d *Drawing) Fences(g *Georeference, layers ...string) []omlox.Fence {
+	var fences []omlox.Fence
+	for _, a := range d.areas(layers) {
+		ring := make([]geometry.Point, 0, len(a.Points)+1)
+		for _, p := range a.Points {
+			ring = append(ring, g.toWGS84(p))
+		}
+		ring = append(ring, ring[0])
+
+		// GeoJSON exterior rings are counterclockwise
+		if signedArea(ring) < 0 {
+			slices.Reverse(ring)
+		}
+
+		fences = append(fences, omlox.Fence{
+			Name:       a.name,
+			Region:     omlox.NewRegionPolygon(geometry.NewPoly(ring, nil, nil)),
+			Crs:        omlox.CrsWGS84,
+			Properties: a.properties(),
+		})
+	}
+	return fences
+}
+
+// contains reports whether a point is within a ring, by the even-odd rule.
+func contains(ring []geometry.Point, p geometry.Point) bool {
+	in := false
+	for i, a := range ring {
+		b := ring[(i+1)%len(ring)]
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < a.X+(p.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y) {
+			in = !in
+		}
+	}
+	return in
+}
+
+// signedArea returns the area of a ring, positive if counterclockwise.
+func signedArea(ring []geometry.Point) float64 {
+	area := 0.0
+	for i, a := range ring {
+		b := ring[(i+1)%len(ring)]
+		area += a.X*b.Y - b.X*a.Y
+	}
+	return area / 2
+}
@@ -0,0 +1,98 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"net"
+	"slices"
+	"sync"
+
+	"github.com/mailru/easyjson"
+	"github.com/mailru/easyjson/buffer"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+// minAppendSpace is the free space ensured in a destination buffer before appending
+// an encoding, so typical objects are encoded in place.
+const minAppendSpace = 1024
+
+// maxPooledBuffer is the capacity above which encoding buffers are not kept for reuse,
+// so an occasional large message does not pin memory.
+const maxPooledBuffer = 64 << 10
+
+// encodeBuffers are reusable buffers of encoded messages.
+var encodeBuffers = sync.Pool{
+	New: func() any {
+		b := make([]byte, 0, 4*minAppendSpace)
+		return &b
+	},
+}
+
+func getEncodeBuffer() *[]byte {
+	return encodeBuffers.Get().(*[]byte)
+}
+
+func putEncodeBuffer(b *[]byte) {
+	if cap(*b) > maxPooledBuffer {
+		return
+	}
+	*b = (*b)[:0]
+	encodeBuffers.Put(b)
+}
+
+// appendEasyJSON appends the encoding of a value to the destination buffer.
+func appendEasyJSON[T easyjson.Marshaler](dst []byte, v T) ([]byte, error) {
+	dst = slices.Grow(dst, minAppendSpace)
+
+	w := jwriter.Writer{Buffer: buffer.Buffer{Buf: dst}}
+	v.MarshalEasyJSON(&w)
+	if w.Error != nil {
+		return dst, w.Error
+	}
+
+	// encodings larger than the free space are assembled from chunks
+	return w.Buffer.BuildBytes(), nil
+}
+
+// AppendJSON appends the JSON encoding of the location to the destination buffer,
+// allocating only when the buffer needs to grow.
+func (v *Location) AppendJSON(dst []byte) ([]byte, error) {
+	dst = slices.Grow(dst, minAppendSpace)
+
+	w := jwriter.Writer{Buffer: buffer.Buffer{Buf: dst}}
+	v.MarshalEasyJSON(&w)
+	if w.Error != nil {
+		return dst, w.Error
+	}
+
+	return w.Buffer.BuildBytes(), nil
+}
+
+// PublishLocations publishes location updates to the Hub, encoding them in a reused buffer.
+func (c *Client) PublishLocations(ctx context.Context, locations ...Location) error {
+	if c.isClosed() {
+		return net.ErrClosed
+	}
+
+	buf := getEncodeBuffer()
+
+	b := append(*buf, `{"event":"`+string(EventMsg)+`","topic":"`+string(TopicLocationUpdates)+`","payload":[`...)
+	for i := range locations {
+		if i > 0 {
+			b = append(b, ',')
+		}
+
+		var err error
+		if b, err = locations[i].AppendJSON(b); err != nil {
+			*buf = b
+			putEncodeBuffer(buf)
+			return err
+		}
+	}
+	b = append(b, "]}"...)
+	*buf = b
+
+	return c.write(ctx, buf)
+}
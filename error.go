@@ -9,13 +9,37 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 
 	"log/slog"
 )
 
+// ErrNotSupported is returned, wrapped, by requests the Hub is known not to
+// support according to its version. See Client.Supports.
+var ErrNotSupported = errors.New("not supported")
+
+// ErrUnauthorized is matched by the errors of the requests the Hub rejected for
+// missing or invalid credentials (HTTP 401).
+var ErrUnauthorized = errors.New("unauthorized")
+
+// ErrForbidden is matched by the errors of the requests the Hub rejected as not
+// permitted for the credentials (HTTP 403).
+var ErrForbidden = errors.New("forbidden")
+
+// ErrNotFound is matched by the errors of the requests of resources missing
+// from the Hub (HTTP 404).
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict is matched by the errors of the requests conflicting with the state
+// of the Hub, such as the creation of a resource with an id already taken (HTTP 409).
+var ErrConflict = errors.New("conflict")
+
 // Error is the error returned when Omlox Hub responds with an HTTP status
 // code outside of the 200 - 399 range.  If a request fails due to a
 // network error, a different error message will be returned.
+//
+// Errors match ErrUnauthorized, ErrForbidden, ErrNotFound and ErrConflict with
+// errors.Is, by the HTTP status code of the response.
 type Error struct {
 	// A text representation of the error type (required).
 	Type string `json:"type"`
@@ -25,8 +49,21 @@ type Error struct {
 
 	// A human readable error message which may give a hint to what went wrong (optional).
 	Message string `json:"message"`
+
+	// StatusCode is the HTTP status code of the response, which may differ from the
+	// code of its body.
+	StatusCode int `json:"-"`
+
+	// Method is the HTTP method of the failed request.
+	Method string `json:"-"`
+
+	// URL is the URL of the failed request, without credentials nor query values.
+	URL string `json:"-"`
 }
 
+// APIError is an alias of Error.
+type APIError = Error
+
 // isResponseError determines if this is a response error based on the response
 // status code. If it is determined to be an error, the function consumes the
 // response body without closing it and parses the underlying error messages.
@@ -45,15 +82,70 @@ func isResponseError(r *http.Response) error {
 
 	var responseError Error
 	if err := json.Unmarshal(responseBody, &responseError); err != nil {
-		// return the raw response body
-		return errors.New(string(responseBody))
+		// keep the raw response body as the message
+		responseError = Error{
+			Type:    http.StatusText(r.StatusCode),
+			Code:    r.StatusCode,
+			Message: string(responseBody),
+		}
+	}
+
+	// some gateways answer with other JSON bodies
+	if responseError.Code == 0 {
+		responseError.Code = r.StatusCode
+	}
+	responseError.StatusCode = r.StatusCode
+
+	if r.Request != nil {
+		responseError.Method = r.Request.Method
+		responseError.URL = redactURL(r.Request.URL)
 	}
 
 	return &responseError
 }
 
+// redactURL returns a URL without the password of its user info nor the values of its
+// query, which may carry tokens or API keys.
+func redactURL(u *url.URL) string {
+	redacted := *u
+	if redacted.RawQuery != "" {
+		query := redacted.Query()
+		for k := range query {
+			query[k] = []string{"xxxxx"}
+		}
+		redacted.RawQuery = query.Encode()
+	}
+	return redacted.Redacted()
+}
+
 func (err Error) Error() string {
-	return fmt.Sprintf("%s (code %d): %s", err.Type, err.Code, err.Message)
+	msg := fmt.Sprintf("%s (code %d): %s", err.Type, err.Code, err.Message)
+	if err.Method != "" {
+		msg = err.Method + " " + err.URL + ": " + msg
+	}
+	return msg
+}
+
+// Is reports whether the error matches ErrUnauthorized, ErrForbidden, ErrNotFound
+// or ErrConflict, by the HTTP status code of the response, or by its code for errors
+// without response.
+func (err Error) Is(target error) bool {
+	status := err.StatusCode
+	if status == 0 {
+		status = err.Code
+	}
+
+	switch target {
+	case ErrUnauthorized:
+		return status == http.StatusUnauthorized
+	case ErrForbidden:
+		return status == http.StatusForbidden
+	case ErrNotFound:
+		return status == http.StatusNotFound
+	case ErrConflict:
+		return status == http.StatusConflict
+	}
+	return false
 }
 
 // LogValue implements [slog.LogValuer] to convert itself into a Value for logging.
@@ -61,6 +153,9 @@ func (err Error) LogValue() slog.Value {
 	return slog.GroupValue(
 		slog.String("type", err.Type),
 		slog.Int("code", err.Code),
+		slog.Int("status", err.StatusCode),
 		slog.String("msg", err.Message),
+		slog.String("method", err.Method),
+		slog.String("url", err.URL),
 	)
 }
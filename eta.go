@@ -0,0 +1,293 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tidwall/geojson"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go/internal/geo"
+)
+
+// speedWindow is the location history used to estimate the speed of trackables
+// whose location does not report it.
+const speedWindow = time.Minute
+
+// AnalyticsAPI computes higher level information from the Hub data, such as arrival estimates.
+type AnalyticsAPI struct {
+	client *Client
+
+	// last locations of the trackables of the estimates, estimating their speed
+	// without location history
+	mu     sync.Mutex
+	recent map[uuid.UUID]recentLocations
+}
+
+// recentLocations are the last two locations of a trackable, by time.
+type recentLocations struct {
+	previous, last *Location
+}
+
+// ETA is the estimated time of arrival of a trackable at a fence.
+type ETA struct {
+	// TrackableID is the id of the trackable.
+	TrackableID uuid.UUID `json:"trackable_id"`
+
+	// FenceID is the id of the destination fence.
+	FenceID uuid.UUID `json:"fence_id"`
+
+	// Distance is the remaining distance in meters to the fence.
+	Distance float64 `json:"distance"`
+
+	// Speed is the speed in meters per second at which the trackable approaches the fence.
+	Speed float64 `json:"speed"`
+
+	// Duration is the estimated time until the trackable arrives at the fence.
+	// It is zero when the trackable is inside the fence or not approaching it.
+	Duration time.Duration `json:"duration"`
+
+	// Arrival is the estimated arrival time, from the time of the last trackable location.
+	// It is the zero time when the trackable is not approaching the fence.
+	Arrival time.Time `json:"arrival,omitempty"`
+
+	// Inside is set when the trackable is already inside the fence.
+	Inside bool `json:"inside,omitempty"`
+
+	// Approaching is set when the trackable is moving towards the fence.
+	Approaching bool `json:"approaching"`
+}
+
+// ETAOption configures the estimation of arrival times.
+type ETAOption func(*etaOptions)
+
+type etaOptions struct {
+	routeDistance func(geometry.Point) float64
+}
+
+// WithRouteDistance estimates the remaining distance to the fence along a route, such as
+// the remaining length of an expected path, instead of the straight-line distance.
+// The remaining distance is computed from the trackable position, in the crs of its location.
+func WithRouteDistance(distance func(from geometry.Point) float64) ETAOption {
+	return func(o *etaOptions) {
+		o.routeDistance = distance
+	}
+}
+
+// ETA estimates the arrival time of a trackable at a fence from its most recent location.
+//
+// The trackable is assumed to keep its current speed. Its course, when known, is used to
+// only account the speed towards the fence: trackables moving away are not approaching.
+// Locations without speed have it estimated from the recent location history or, if the Hub
+// does not support it, from the location of the previous estimate of the trackable. The
+// location and the fence must share the same crs.
+func (c *AnalyticsAPI) ETA(ctx context.Context, trackableID uuid.UUID, fenceID uuid.UUID, opts ...ETAOption) (*ETA, error) {
+	var o etaOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	loc, err := c.client.Trackables.GetLocation(ctx, trackableID)
+	if err != nil {
+		return nil, err
+	}
+	if loc == nil {
+		return nil, fmt.Errorf("trackable %s has no location", trackableID)
+	}
+	previous := c.remember(trackableID, *loc)
+
+	fence, err := c.client.Fences.service.Get(ctx, fenceID.String())
+	if err != nil {
+		return nil, err
+	}
+	if fence == nil || fence.Region == nil {
+		return nil, fmt.Errorf("fence %s has no region", fenceID)
+	}
+
+	locCrs, fenceCrs := loc.Crs, fence.Crs
+	if locCrs == "" {
+		locCrs = CrsLocal
+	}
+	if fenceCrs == "" {
+		fenceCrs = CrsWGS84
+	}
+	if locCrs != fenceCrs {
+		return nil, fmt.Errorf("trackable %s location crs %s differs from fence %s crs %s", trackableID, locCrs, fenceID, fenceCrs)
+	}
+
+	pos := loc.Position.Base()
+	distance, target, inside := fenceDistance(pos, fence, locCrs)
+
+	eta := &ETA{
+		TrackableID: trackableID,
+		FenceID:     fenceID,
+		Distance:    distance,
+		Inside:      inside,
+	}
+	if inside {
+		eta.Approaching = true
+		return eta, nil
+	}
+
+	if o.routeDistance != nil {
+		eta.Distance = o.routeDistance(pos)
+	}
+
+	speed, err := c.speed(ctx, trackableID, loc, previous)
+	if err != nil {
+		return nil, err
+	}
+
+	// only the speed component towards the fence, unless following a route
+	if course := loc.Course; course != nil && o.routeDistance == nil && *course >= 0 {
+		speed *= math.Cos((*course - bearing(pos, target, locCrs)) * math.Pi / 180)
+	}
+
+	eta.Speed = math.Max(0, speed)
+	if eta.Speed <= 0 {
+		return eta, nil
+	}
+
+	at := c.client.timeSource().Now()
+	if loc.TimestampGenerated != nil {
+		at = *loc.TimestampGenerated
+	}
+
+	eta.Approaching = true
+	eta.Duration = time.Duration(eta.Distance / eta.Speed * float64(time.Second))
+	eta.Arrival = at.Add(eta.Duration)
+
+	return eta, nil
+}
+
+// remember records the location of a trackable and returns its location before, if any.
+func (c *AnalyticsAPI) remember(trackableID uuid.UUID, loc Location) *Location {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.recent == nil {
+		c.recent = make(map[uuid.UUID]recentLocations)
+	}
+	r := c.recent[trackableID]
+	if r.last == nil || timestamp(loc).After(timestamp(*r.last)) {
+		r.previous, r.last = r.last, &loc
+		c.recent[trackableID] = r
+	}
+	return r.previous
+}
+
+// speed returns the speed of a trackable, as reported by its location or
+// estimated from its recent location history, or from its previous location
+// without location history.
+func (c *AnalyticsAPI) speed(ctx context.Context, trackableID uuid.UUID, loc *Location, previous *Location) (float64, error) {
+	if loc.Speed != nil {
+		return *loc.Speed, nil
+	}
+
+	to := c.client.timeSource().Now()
+	if loc.TimestampGenerated != nil {
+		to = *loc.TimestampGenerated
+	}
+
+	history, err := c.client.History.TrackableLocations(ctx, trackableID, to.Add(-speedWindow), to)
+	if isUnavailable(err) {
+		if previous == nil || previous.TimestampGenerated == nil || to.Sub(*previous.TimestampGenerated) > speedWindow {
+			return 0, fmt.Errorf("speed of trackable %s unknown: %w", trackableID, err)
+		}
+		c.client.degrade(ctx, FeatureHistory, err, "client-side: speed estimated from the previous location")
+		history = []Location{*previous, *loc}
+	} else if err != nil {
+		return 0, err
+	}
+
+	var first, last *Location
+	for i := range history {
+		l := &history[i]
+		if l.TimestampGenerated == nil || l.Crs != loc.Crs {
+			continue
+		}
+		if first == nil || l.TimestampGenerated.Before(*first.TimestampGenerated) {
+			first = l
+		}
+		if last == nil || l.TimestampGenerated.After(*last.TimestampGenerated) {
+			last = l
+		}
+	}
+
+	if first == nil || first == last {
+		return 0, fmt.Errorf("speed of trackable %s unknown: not enough recent locations", trackableID)
+	}
+
+	crs := loc.Crs
+	if crs == "" {
+		crs = CrsLocal
+	}
+
+	a, b := first.Position.Base(), last.Position.Base()
+	sx, sy := metricScale(a, crs)
+	d := math.Hypot((b.X-a.X)*sx, (b.Y-a.Y)*sy)
+
+	return d / last.TimestampGenerated.Sub(*first.TimestampGenerated).Seconds(), nil
+}
+
+// metricScale returns the scale of the axis of a crs to meters around a position.
+// WGS84 degrees are scaled with an equirectangular approximation, other crs are assumed in meters.
+func metricScale(p geometry.Point, crs string) (float64, float64) {
+	if crs != CrsWGS84 {
+		return 1, 1
+	}
+	return geo.Scale(p.Y)
+}
+
+// fenceDistance returns the distance in meters from a position to the region of a fence,
+// the closest point of the region, and whether the position is inside the fence.
+func fenceDistance(p geometry.Point, fence *Fence, crs string) (float64, geometry.Point, bool) {
+	sx, sy := metricScale(p, crs)
+
+	switch region := fence.Region.Object.(type) {
+	case *geojson.Point:
+		c := region.Base()
+		d := math.Hypot((c.X-p.X)*sx, (c.Y-p.Y)*sy) - fence.Radius
+		return math.Max(0, d), c, d <= 0
+	case *geojson.Polygon:
+		poly := region.Base()
+		if poly.ContainsPoint(p) {
+			return 0, p, true
+		}
+
+		best, target := math.Inf(1), p
+		ring := poly.Exterior
+		for i := 0; i < ring.NumSegments(); i++ {
+			seg := ring.SegmentAt(i)
+			ax, ay := (seg.A.X-p.X)*sx, (seg.A.Y-p.Y)*sy
+			dx, dy := (seg.B.X-seg.A.X)*sx, (seg.B.Y-seg.A.Y)*sy
+
+			t := 0.0
+			if l := dx*dx + dy*dy; l > 0 {
+				t = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/l))
+			}
+
+			if d := math.Hypot(ax+t*dx, ay+t*dy); d < best {
+				best = d
+				target = geometry.Point{X: seg.A.X + t*(seg.B.X-seg.A.X), Y: seg.A.Y + t*(seg.B.Y-seg.A.Y)}
+			}
+		}
+		return best, target, false
+	}
+
+	return math.Inf(1), p, false
+}
+
+// bearing returns the compass direction in degrees from a position to another.
+// Local coordinates are assumed to have the y axis pointing north.
+func bearing(from geometry.Point, to geometry.Point, crs string) float64 {
+	sx, sy := metricScale(from, crs)
+	deg := math.Atan2((to.X-from.X)*sx, (to.Y-from.Y)*sy) * 180 / math.Pi
+	return math.Mod(deg+360, 360)
+}
@@ -0,0 +1,110 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tidwall/geojson/geometry"
+)
+
+func TestAnalyticsETA(t *testing.T) {
+	trackableID := uuid.MustParse("d27047bd-1b6b-4656-bb93-2326a4c900e1")
+	fenceID := uuid.MustParse("2b2fa2f6-49bb-4e6c-8e2e-4a3b8a63c1b1")
+
+	// square fence from x=10 to x=20, trackable at x=0
+	fence := `{"id":"` + fenceID.String() + `","crs":"local","region":{"type":"Polygon","coordinates":[[[10,0],[20,0],[20,10],[10,10],[10,0]]]}}`
+	history := `[
+		{"position":{"type":"Point","coordinates":[-4,5]},"source":"hall","provider_type":"uwb","provider_id":"a1","crs":"local","timestamp_generated":"2024-03-01T08:00:00Z"},
+		{"position":{"type":"Point","coordinates":[0,5]},"source":"hall","provider_type":"uwb","provider_id":"a1","crs":"local","timestamp_generated":"2024-03-01T08:00:04Z"}
+	]`
+
+	tests := []struct {
+		name        string
+		location    string
+		opts        []ETAOption
+		approaching bool
+		inside      bool
+		duration    time.Duration
+	}{
+		{
+			name:        "heading to fence",
+			location:    `{"position":{"type":"Point","coordinates":[0,5]},"source":"hall","provider_type":"uwb","provider_id":"a1","crs":"local","speed":2,"course":90,"timestamp_generated":"2024-03-01T08:00:04Z"}`,
+			approaching: true,
+			duration:    5 * time.Second,
+		},
+		{
+			name:     "heading away",
+			location: `{"position":{"type":"Point","coordinates":[0,5]},"source":"hall","provider_type":"uwb","provider_id":"a1","crs":"local","speed":2,"course":270,"timestamp_generated":"2024-03-01T08:00:04Z"}`,
+		},
+		{
+			name:        "speed from history",
+			location:    `{"position":{"type":"Point","coordinates":[0,5]},"source":"hall","provider_type":"uwb","provider_id":"a1","crs":"local","timestamp_generated":"2024-03-01T08:00:04Z"}`,
+			approaching: true,
+			duration:    10 * time.Second,
+		},
+		{
+			name:        "route distance",
+			location:    `{"position":{"type":"Point","coordinates":[0,5]},"source":"hall","provider_type":"uwb","provider_id":"a1","crs":"local","speed":2,"course":0,"timestamp_generated":"2024-03-01T08:00:04Z"}`,
+			opts:        []ETAOption{WithRouteDistance(func(geometry.Point) float64 { return 30 })},
+			approaching: true,
+			duration:    15 * time.Second,
+		},
+		{
+			name:        "inside",
+			location:    `{"position":{"type":"Point","coordinates":[15,5]},"source":"hall","provider_type":"uwb","provider_id":"a1","crs":"local","speed":2,"timestamp_generated":"2024-03-01T08:00:04Z"}`,
+			approaching: true,
+			inside:      true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			httpClient := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					body, status := "", http.StatusOK
+					switch {
+					case strings.HasSuffix(r.URL.Path, "/location"):
+						body = tc.location
+					case strings.HasPrefix(r.URL.Path, "/v2/fences/"):
+						body = fence
+					case strings.HasPrefix(r.URL.Path, "/v2/history/"):
+						body = history
+					default:
+						body, status = `{"type":"not found","code":404,"message":"not found"}`, http.StatusNotFound
+					}
+
+					return &http.Response{
+						StatusCode: status,
+						Body:       io.NopCloser(strings.NewReader(body)),
+					}, nil
+				}),
+			}
+
+			c, err := New("http://localhost:8081/v2", WithHTTPClient(httpClient))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			eta, err := c.Analytics.ETA(context.Background(), trackableID, fenceID, tc.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if eta.Approaching != tc.approaching || eta.Inside != tc.inside {
+				t.Errorf("expected approaching %v and inside %v, got %+v", tc.approaching, tc.inside, eta)
+			}
+			if math.Abs(eta.Duration.Seconds()-tc.duration.Seconds()) > 1e-6 {
+				t.Errorf("expected duration %v, got %v", tc.duration, eta.Duration)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package export converts omlox™ location histories into formats
+// used by third-party GIS tooling, such as GPX, KML and GeoJSON.
+package export
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/analytics"
+)
+
+// creator identifies this library in the exported documents.
+const creator = "omlox-client-go"
+
+// Track is the trajectory of a single trackable.
+type Track struct {
+	// Name of the track, usually the trackable name or ID.
+	Name string
+
+	// Locations of the trackable. Locations must be in WGS84 (EPSG:4326).
+	Locations []omlox.Location
+
+	// Tolerance simplifies the track within the tolerance in meters, as long tracks
+	// are slow to render. Zero exports all the locations.
+	Tolerance float64
+
+	// Simplification is the algorithm simplifying the track.
+	// Default: analytics.DouglasPeucker
+	Simplification analytics.Simplification
+}
+
+// uncertaintyConfidence is the confidence level of the exported uncertainty ellipses.
+const uncertaintyConfidence = 0.95
+
+// trackPoint is a WGS84 position of a track at a given time.
+type trackPoint struct {
+	Lon, Lat, Ele float64
+	Time          time.Time
+
+	// Uncertainty is the horizontal uncertainty of the position, if known.
+	Uncertainty *omlox.Ellipse
+}
+
+// points returns the track points in chronological order, simplified within the
+// tolerance of the track.
+func (t Track) points() ([]trackPoint, error) {
+	for _, loc := range t.Locations {
+		if loc.Crs != omlox.CrsWGS84 {
+			return nil, fmt.Errorf("location crs '%s' not supported: must be '%s'", loc.Crs, omlox.CrsWGS84)
+		}
+	}
+
+	locations := slices.Clone(t.Locations)
+	sort.SliceStable(locations, func(i, j int) bool {
+		return timestamp(locations[i]).Before(timestamp(locations[j]))
+	})
+	locations = analytics.Simplify(locations, t.Tolerance, t.Simplification)
+
+	points := make([]trackPoint, 0, len(locations))
+	for _, loc := range locations {
+		p := loc.Position.Base()
+		tp := trackPoint{
+			Lon:  p.X,
+			Lat:  p.Y,
+			Ele:  loc.Position.Z(),
+			Time: timestamp(loc).UTC(),
+		}
+
+		if e, ok := loc.Uncertainty(uncertaintyConfidence); ok {
+			tp.Uncertainty = &e
+		}
+
+		points = append(points, tp)
+	}
+
+	return points, nil
+}
+
+// timestamp returns the generation time of a location, or the zero time if unknown.
+func timestamp(loc omlox.Location) time.Time {
+	if loc.TimestampGenerated == nil {
+		return time.Time{}
+	}
+	return *loc.TimestampGenerated
+}
@@ -0,0 +1,164 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+func location(x, y float64, ts string) omlox.Location {
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		panic(err)
+	}
+
+	return omlox.Location{
+		Position:           *omlox.NewPoint(geometry.Point{X: x, Y: y}),
+		Crs:                omlox.CrsWGS84,
+		TimestampGenerated: &t,
+	}
+}
+
+var track = Track{
+	Name: "Forklift",
+	Locations: []omlox.Location{
+		location(7.815724999999997, 48.13031, "2023-10-17T11:14:38.206Z"),
+		location(7.815694, 48.130216, "2023-10-17T11:14:37.206Z"),
+	},
+}
+
+const expectedGPX = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx xmlns="http://www.topografix.com/GPX/1/1" version="1.1" creator="omlox-client-go">
+  <trk>
+    <name>Forklift</name>
+    <trkseg>
+      <trkpt lat="48.130216" lon="7.815694">
+        <time>2023-10-17T11:14:37.206Z</time>
+      </trkpt>
+      <trkpt lat="48.13031" lon="7.815724999999997">
+        <time>2023-10-17T11:14:38.206Z</time>
+      </trkpt>
+    </trkseg>
+  </trk>
+</gpx>
+`
+
+const expectedKML = `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2" xmlns:gx="http://www.google.com/kml/ext/2.2">
+  <Document>
+    <name>omlox-client-go</name>
+    <Placemark>
+      <name>Forklift</name>
+      <gx:Track>
+        <when>2023-10-17T11:14:37.206Z</when>
+        <when>2023-10-17T11:14:38.206Z</when>
+        <gx:coord>7.815694 48.130216 0</gx:coord>
+        <gx:coord>7.815724999999997 48.13031 0</gx:coord>
+      </gx:Track>
+    </Placemark>
+  </Document>
+</kml>
+`
+
+const expectedGeoJSON = `{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"LineString","coordinates":[[7.815694,48.130216,0],[7.815724999999997,48.13031,0]]},"properties":{"name":"Forklift"}}]}
+`
+
+func TestWrite(t *testing.T) {
+	cases := []struct {
+		name     string
+		write    func(*bytes.Buffer, ...Track) error
+		expected string
+	}{
+		{"gpx", func(b *bytes.Buffer, t ...Track) error { return WriteGPX(b, t...) }, expectedGPX},
+		{"kml", func(b *bytes.Buffer, t ...Track) error { return WriteKML(b, t...) }, expectedKML},
+		{"geojson", func(b *bytes.Buffer, t ...Track) error { return WriteGeoJSON(b, t...) }, expectedGeoJSON},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tc.write(&buf, track); err != nil {
+				t.Fatal(err)
+			}
+
+			if buf.String() != tc.expected {
+				t.Errorf("mismatch! wanted:\n%s\ngot:\n%s", tc.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestWriteGeoJSONUncertainty(t *testing.T) {
+	loc := location(7.815694, 48.130216, "2023-10-17T11:14:37.206Z")
+	accuracy := 2.0
+	loc.Accuracy = &accuracy
+
+	var buf bytes.Buffer
+	if err := WriteGeoJSON(&buf, Track{Name: "Forklift", Locations: []omlox.Location{loc}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc geoJSONDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(doc.Features) != 2 {
+		t.Fatalf("expected track and uncertainty features, got %d features", len(doc.Features))
+	}
+
+	ellipse := doc.Features[1]
+	if ellipse.Geometry.Type != "Polygon" {
+		t.Errorf("expected uncertainty polygon, got %s", ellipse.Geometry.Type)
+	}
+	if ellipse.Properties["semi_major"] != accuracy {
+		t.Errorf("expected accuracy circle of radius %v, got %v", accuracy, ellipse.Properties["semi_major"])
+	}
+}
+
+func TestWriteUnsupportedCrs(t *testing.T) {
+	local := Track{
+		Name:      "Forklift",
+		Locations: []omlox.Location{{Crs: omlox.CrsLocal}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteGPX(&buf, local); err == nil {
+		t.Error("expected error for local coordinates")
+	}
+}
+
+func TestWriteSimplified(t *testing.T) {
+	// a straight line with a point about 0.1m off it
+	simplified := Track{
+		Name: "Forklift",
+		Locations: []omlox.Location{
+			location(7.8157, 48.1302, "2023-10-17T11:14:37Z"),
+			location(7.8157, 48.1303, "2023-10-17T11:14:38Z"),
+			location(7.815701, 48.1304, "2023-10-17T11:14:39Z"),
+			location(7.8157, 48.1305, "2023-10-17T11:14:40Z"),
+		},
+		Tolerance: 1,
+	}
+
+	var buf bytes.Buffer
+	if err := WriteGeoJSON(&buf, simplified); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc geoJSONDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if coords, _ := doc.Features[0].Geometry.Coordinates.([]any); len(coords) != 2 {
+		t.Errorf("expected 2 simplified positions, got %v", doc.Features[0].Geometry.Coordinates)
+	}
+}
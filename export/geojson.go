@@ -0,0 +1,99 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+// ellipseSegments is the number of segments used to draw uncertainty ellipses.
+const ellipseSegments = 32
+
+type geoJSONGeometry struct {
+	Type        string `json:"type"`
+	Coordinates any    `json:"coordinates"`
+}
+
+type geoJSONFeature struct {
+	Type       string          `json:"type"`
+	Geometry   geoJSONGeometry `json:"geometry"`
+	Properties map[string]any  `json:"properties"`
+}
+
+type geoJSONDocument struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+// WriteGeoJSON writes the tracks as a GeoJSON feature collection to w.
+//
+// Each track is written as a LineString feature. Locations with a known
+// uncertainty are also written as Polygon features, drawing their uncertainty
+// ellipse at a 95% confidence level (or their accuracy circle).
+func WriteGeoJSON(w io.Writer, tracks ...Track) error {
+	doc := geoJSONDocument{
+		Type:     "FeatureCollection",
+		Features: make([]geoJSONFeature, 0, len(tracks)),
+	}
+
+	for _, t := range tracks {
+		points, err := t.points()
+		if err != nil {
+			return err
+		}
+
+		line := make([][]float64, 0, len(points))
+		var ellipses []geoJSONFeature
+
+		for _, p := range points {
+			line = append(line, []float64{p.Lon, p.Lat, p.Ele})
+
+			if p.Uncertainty != nil {
+				ellipses = append(ellipses, ellipseFeature(t.Name, p))
+			}
+		}
+
+		doc.Features = append(doc.Features, geoJSONFeature{
+			Type:       "Feature",
+			Geometry:   geoJSONGeometry{Type: "LineString", Coordinates: line},
+			Properties: map[string]any{"name": t.Name},
+		})
+		doc.Features = append(doc.Features, ellipses...)
+	}
+
+	return json.NewEncoder(w).Encode(doc)
+}
+
+// ellipseFeature returns the uncertainty ellipse of a track point as a GeoJSON feature.
+func ellipseFeature(name string, p trackPoint) geoJSONFeature {
+	e := *p.Uncertainty
+	ring := e.Ring(geometry.Point{X: p.Lon, Y: p.Lat}, omlox.CrsWGS84, ellipseSegments)
+
+	coords := make([][]float64, 0, len(ring))
+	for _, rp := range ring {
+		coords = append(coords, []float64{rp.X, rp.Y})
+	}
+
+	props := map[string]any{
+		"name":        name,
+		"semi_major":  e.SemiMajor,
+		"semi_minor":  e.SemiMinor,
+		"orientation": e.Orientation,
+		"confidence":  uncertaintyConfidence,
+	}
+	if !p.Time.IsZero() {
+		props["time"] = p.Time.Format(time.RFC3339Nano)
+	}
+
+	return geoJSONFeature{
+		Type:       "Feature",
+		Geometry:   geoJSONGeometry{Type: "Polygon", Coordinates: [][][]float64{coords}},
+		Properties: props,
+	}
+}
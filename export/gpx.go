@@ -0,0 +1,86 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package export
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+const gpxNamespace = "http://www.topografix.com/GPX/1/1"
+
+type gpxDocument struct {
+	XMLName xml.Name   `xml:"gpx"`
+	Xmlns   string     `xml:"xmlns,attr"`
+	Version string     `xml:"version,attr"`
+	Creator string     `xml:"creator,attr"`
+	Tracks  []gpxTrack `xml:"trk"`
+}
+
+type gpxTrack struct {
+	Name     string       `xml:"name,omitempty"`
+	Segments []gpxSegment `xml:"trkseg"`
+}
+
+type gpxSegment struct {
+	Points []gpxPoint `xml:"trkpt"`
+}
+
+type gpxPoint struct {
+	Lat  float64 `xml:"lat,attr"`
+	Lon  float64 `xml:"lon,attr"`
+	Ele  float64 `xml:"ele,omitempty"`
+	Time string  `xml:"time,omitempty"`
+}
+
+// WriteGPX writes the tracks as a GPX 1.1 document to w.
+// Each track is written as a GPX track with a single segment.
+func WriteGPX(w io.Writer, tracks ...Track) error {
+	doc := gpxDocument{
+		Xmlns:   gpxNamespace,
+		Version: "1.1",
+		Creator: creator,
+		Tracks:  make([]gpxTrack, 0, len(tracks)),
+	}
+
+	for _, t := range tracks {
+		points, err := t.points()
+		if err != nil {
+			return err
+		}
+
+		seg := gpxSegment{Points: make([]gpxPoint, 0, len(points))}
+		for _, p := range points {
+			gp := gpxPoint{Lat: p.Lat, Lon: p.Lon, Ele: p.Ele}
+			if !p.Time.IsZero() {
+				gp.Time = p.Time.Format(time.RFC3339Nano)
+			}
+			seg.Points = append(seg.Points, gp)
+		}
+
+		doc.Tracks = append(doc.Tracks, gpxTrack{
+			Name:     t.Name,
+			Segments: []gpxSegment{seg},
+		})
+	}
+
+	return writeXML(w, doc)
+}
+
+// writeXML writes an indented XML document with header to w.
+func writeXML(w io.Writer, doc any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	if err := e.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,85 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package export
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	kmlNamespace   = "http://www.opengis.net/kml/2.2"
+	kmlGxNamespace = "http://www.google.com/kml/ext/2.2"
+)
+
+type kmlDocument struct {
+	XMLName  xml.Name      `xml:"kml"`
+	Xmlns    string        `xml:"xmlns,attr"`
+	XmlnsGx  string        `xml:"xmlns:gx,attr"`
+	Document kmlPlacemarks `xml:"Document"`
+}
+
+type kmlPlacemarks struct {
+	Name       string         `xml:"name"`
+	Placemarks []kmlPlacemark `xml:"Placemark"`
+}
+
+type kmlPlacemark struct {
+	Name  string   `xml:"name,omitempty"`
+	Track kmlTrack `xml:"gx:Track"`
+}
+
+// kmlTrack is a KML extension track. Time and coordinates are kept
+// in two parallel lists, as defined by the gx:Track element.
+type kmlTrack struct {
+	AltitudeMode string   `xml:"altitudeMode,omitempty"`
+	When         []string `xml:"when"`
+	Coords       []string `xml:"gx:coord"`
+}
+
+// WriteKML writes the tracks as a KML document to w, suitable for Google Earth.
+// Each track is written as a placemark with a time-stamped gx:Track.
+func WriteKML(w io.Writer, tracks ...Track) error {
+	doc := kmlDocument{
+		Xmlns:   kmlNamespace,
+		XmlnsGx: kmlGxNamespace,
+		Document: kmlPlacemarks{
+			Name:       creator,
+			Placemarks: make([]kmlPlacemark, 0, len(tracks)),
+		},
+	}
+
+	for _, t := range tracks {
+		points, err := t.points()
+		if err != nil {
+			return err
+		}
+
+		track := kmlTrack{
+			When:   make([]string, 0, len(points)),
+			Coords: make([]string, 0, len(points)),
+		}
+
+		for _, p := range points {
+			track.When = append(track.When, p.Time.Format(time.RFC3339Nano))
+			track.Coords = append(track.Coords, formatFloat(p.Lon)+" "+formatFloat(p.Lat)+" "+formatFloat(p.Ele))
+			if p.Ele != 0 {
+				track.AltitudeMode = "absolute"
+			}
+		}
+
+		doc.Document.Placemarks = append(doc.Document.Placemarks, kmlPlacemark{
+			Name:  t.Name,
+			Track: track,
+		})
+	}
+
+	return writeXML(w, doc)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
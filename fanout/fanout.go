@@ -0,0 +1,492 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package fanout re-broadcasts the events of hub subscriptions to many browser clients,
+// so a dashboard with hundreds of viewers opens one subscription of the hub instead of
+// one per viewer.
+//
+// Browsers connect with WebSockets and speak the omlox™ websocket protocol: they
+// subscribe to the topics broadcast by the server, restricting the events to trackables
+// and location providers with the "trackable_ids" and "provider_ids" parameters, and
+// receive the messages of their subscriptions. Connections too slow to keep up with the
+// messages are closed, so they do not hold back the others.
+//
+// Where WebSockets are blocked, browsers receive the messages as Server-Sent Events
+// instead, subscribing with the query of the request:
+//
+//	new EventSource("/events?topic=location_updates&provider_ids=uwb-1,uwb-2")
+//
+// Each event is a message of the websocket protocol, with its topic and subscription id.
+package fanout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wavecomtech/omlox-client-go"
+	"nhooyr.io/websocket"
+)
+
+// Defaults of the server configuration.
+const (
+	DefaultBufferSize   = 256
+	DefaultWriteTimeout = 10 * time.Second
+)
+
+// keepAliveInterval is the interval of the comments sent to idle event streams, so
+// proxies do not close them.
+const keepAliveInterval = 15 * time.Second
+
+// Option is a configuration option of a server.
+type Option func(*Server)
+
+// WithBufferSize sets the number of messages buffered for each connection, above which
+// the connection is closed as too slow.
+// Default: DefaultBufferSize
+func WithBufferSize(n int) Option {
+	return func(s *Server) {
+		s.bufferSize = n
+	}
+}
+
+// WithWriteTimeout sets the timeout of writing a message to a connection.
+// Default: DefaultWriteTimeout
+func WithWriteTimeout(d time.Duration) Option {
+	return func(s *Server) {
+		s.writeTimeout = d
+	}
+}
+
+// WithOriginPatterns sets the host patterns of the origins of the pages allowed to
+// connect, such as "dashboard.example.com" or "*.example.com".
+// Default: only pages of the same origin as the server
+func WithOriginPatterns(patterns ...string) Option {
+	return func(s *Server) {
+		s.originPatterns = patterns
+	}
+}
+
+// Server broadcasts messages to the subscriptions of its connections. It implements
+// http.Handler, serving the WebSocket connections and event streams of browsers. It is
+// safe for concurrent use.
+type Server struct {
+	bufferSize     int
+	writeTimeout   time.Duration
+	originPatterns []string
+
+	mu     sync.RWMutex
+	conns  map[*conn]struct{}
+	topics map[omlox.Topic]struct{}
+}
+
+var _ http.Handler = (*Server)(nil)
+
+// NewServer returns a new server with the given options.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		bufferSize:   DefaultBufferSize,
+		writeTimeout: DefaultWriteTimeout,
+		conns:        make(map[*conn]struct{}),
+		topics:       make(map[omlox.Topic]struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Run broadcasts the messages of a hub subscription until the subscription is closed or
+// the context is done. Connections may subscribe to the topic of the subscription from
+// the start of the run.
+func (s *Server) Run(ctx context.Context, sub *omlox.Subcription) error {
+	s.serve(sub.Topic())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-sub.ReceiveRaw():
+			if !ok {
+				return nil
+			}
+
+			// messages of hubs may not carry their topic
+			if msg.Topic == "" {
+				m := *msg
+				m.Topic = sub.Topic()
+				msg = &m
+			}
+			s.Broadcast(msg)
+		}
+	}
+}
+
+// serve adds a topic to the topics connections may subscribe to.
+func (s *Server) serve(topic omlox.Topic) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.topics[topic] = struct{}{}
+}
+
+// Broadcast sends a message to the subscriptions of its topic, with the payloads
+// matching their filters.
+func (s *Server) Broadcast(msg *omlox.WrapperObject) {
+	s.serve(msg.Topic)
+
+	s.mu.RLock()
+	conns := make([]*conn, 0, len(s.conns))
+	for c := range s.conns {
+		conns = append(conns, c)
+	}
+	s.mu.RUnlock()
+
+	for _, c := range conns {
+		c.deliver(msg)
+	}
+}
+
+// Connections returns the number of open connections.
+func (s *Server) Connections() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.conns)
+}
+
+// Close closes all the connections, telling browsers the server is going away.
+func (s *Server) Close() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for c := range s.conns {
+		c.close(websocket.StatusGoingAway, "server closed")
+	}
+
+	return nil
+}
+
+// ServeHTTP serves the WebSocket connection of a browser, or its event stream when the
+// request is not a WebSocket handshake.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		s.serveEvents(w, r)
+		return
+	}
+
+	ws, err := websocket.Accept(w, r, &websocket.AcceptOptions{OriginPatterns: s.originPatterns})
+	if err != nil {
+		// the handshake error is already written
+		return
+	}
+	defer ws.CloseNow()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	c := newConn(s.bufferSize)
+	s.add(c)
+	defer s.remove(c)
+
+	go func() {
+		defer cancel()
+
+		for {
+			_, data, err := ws.Read(ctx)
+			if err != nil {
+				return
+			}
+
+			var msg omlox.WrapperObject
+			if err := json.Unmarshal(data, &msg); err != nil {
+				c.send(wsError(omlox.ErrCodeUnknown, err.Error()))
+				continue
+			}
+			s.handle(c, &msg)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.done:
+			ws.Close(c.status, c.reason)
+			return
+		case data := <-c.out:
+			wctx, wcancel := context.WithTimeout(ctx, s.writeTimeout)
+			err := ws.Write(wctx, websocket.MessageText, data)
+			wcancel()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// serveEvents serves the event stream of a browser, subscribed to the topics of the
+// "topic" query parameters with the filters of the others.
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+
+	query := r.URL.Query()
+	topics := query["topic"]
+	if len(topics) == 0 {
+		http.Error(w, "missing topic parameter", http.StatusBadRequest)
+		return
+	}
+
+	params := make(omlox.Parameters)
+	for k, v := range query {
+		if k != "topic" {
+			params[k] = strings.Join(v, ",")
+		}
+	}
+	filter, err := omlox.NewSubscriptionFilter(params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c := newConn(s.bufferSize)
+	for _, t := range topics {
+		topic := omlox.Topic(t)
+
+		s.mu.RLock()
+		_, ok := s.topics[topic]
+		s.mu.RUnlock()
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown topic %q", topic), http.StatusNotFound)
+			return
+		}
+		c.subscribe(topic, filter)
+	}
+
+	s.add(c)
+	defer s.remove(c)
+
+	if origin := r.Header.Get("Origin"); s.allowedOrigin(origin) {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Add("Vary", "Origin")
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		var data []byte
+		select {
+		case <-r.Context().Done():
+			return
+		case <-c.done:
+			return
+		case <-keepAlive.C:
+			data = []byte(": keep-alive\n\n")
+		case msg := <-c.out:
+			data = append(append([]byte("data: "), msg...), "\n\n"...)
+		}
+
+		rc.SetWriteDeadline(time.Now().Add(s.writeTimeout))
+		if _, err := w.Write(data); err != nil {
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// allowedOrigin reports whether the pages of an origin may read the event streams of
+// the server, matching the host of the origin with the origin patterns.
+func (s *Server) allowedOrigin(origin string) bool {
+	if origin == "" {
+		return false
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	for _, pattern := range s.originPatterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(u.Host)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// add adds a connection to the server.
+func (s *Server) add(c *conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.conns[c] = struct{}{}
+}
+
+// remove removes a connection from the server.
+func (s *Server) remove(c *conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.conns, c)
+}
+
+// handle handles a message of a connection.
+func (s *Server) handle(c *conn, msg *omlox.WrapperObject) {
+	switch msg.Event {
+	case omlox.EventSubscribe:
+		s.mu.RLock()
+		_, ok := s.topics[msg.Topic]
+		s.mu.RUnlock()
+		if !ok {
+			c.send(wsError(omlox.ErrCodeUnknownTopic, string(msg.Topic)))
+			return
+		}
+
+		filter, err := omlox.NewSubscriptionFilter(msg.Params)
+		if err != nil {
+			c.send(wsError(omlox.ErrCodeSubscription, err.Error()))
+			return
+		}
+
+		sid := c.subscribe(msg.Topic, filter)
+		c.send(omlox.WrapperObject{Event: omlox.EventSubscribed, Topic: msg.Topic, SubscriptionID: sid})
+	case omlox.EventUnsubscribe:
+		if !c.unsubscribe(msg.SubscriptionID) {
+			c.send(wsError(omlox.ErrCodeUnsubscription, "unknown subscription id"))
+			return
+		}
+		c.send(omlox.WrapperObject{Event: omlox.EventUnsubscribed, SubscriptionID: msg.SubscriptionID})
+	default:
+		c.send(wsError(omlox.ErrCodeUnknown, string(msg.Event)))
+	}
+}
+
+// wsError returns an error message of the websocket protocol.
+func wsError(code omlox.ErrCode, description string) any {
+	return struct {
+		Event       omlox.Event   `json:"event"`
+		Code        omlox.ErrCode `json:"code"`
+		Description string        `json:"description,omitempty"`
+	}{
+		Event:       omlox.EventError,
+		Code:        code,
+		Description: description,
+	}
+}
+
+// subscription is a subscription of a connection.
+type subscription struct {
+	topic  omlox.Topic
+	filter *omlox.SubscriptionFilter
+}
+
+// conn is a connection and its subscriptions.
+type conn struct {
+	// encoded messages to write
+	out chan []byte
+
+	mu      sync.Mutex
+	subs    map[int]subscription
+	nextSID int
+
+	// closed with the status and reason to close the connection with
+	done      chan struct{}
+	closeOnce sync.Once
+	status    websocket.StatusCode
+	reason    string
+}
+
+func newConn(bufferSize int) *conn {
+	return &conn{
+		out:  make(chan []byte, bufferSize),
+		subs: make(map[int]subscription),
+		done: make(chan struct{}),
+	}
+}
+
+// subscribe adds a subscription to a topic, and returns its id.
+func (c *conn) subscribe(topic omlox.Topic, filter *omlox.SubscriptionFilter) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.nextSID++
+	c.subs[c.nextSID] = subscription{topic: topic, filter: filter}
+	return c.nextSID
+}
+
+// unsubscribe removes a subscription. It reports whether the subscription existed.
+func (c *conn) unsubscribe(sid int) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.subs[sid]
+	delete(c.subs, sid)
+	return ok
+}
+
+// deliver sends a message to the subscriptions of its topic.
+func (c *conn) deliver(msg *omlox.WrapperObject) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for sid, sub := range c.subs {
+		if sub.topic != msg.Topic {
+			continue
+		}
+
+		filtered := sub.filter.Apply(msg)
+		if filtered == nil {
+			continue
+		}
+
+		m := *filtered
+		m.Event = omlox.EventMsg
+		m.SubscriptionID = sid
+		c.send(m)
+	}
+}
+
+// send queues a message, closing the connection if it is too slow to keep up.
+func (c *conn) send(v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	select {
+	case c.out <- data:
+	default:
+		c.close(websocket.StatusPolicyViolation, "connection too slow to keep up with messages")
+	}
+}
+
+// close closes the connection with a status and reason.
+func (c *conn) close(status websocket.StatusCode, reason string) {
+	c.closeOnce.Do(func() {
+		c.status, c.reason = status, reason
+		close(c.done)
+	})
+}
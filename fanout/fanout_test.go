@@ -0,0 +1,223 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package fanout
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/omloxtest"
+	"nhooyr.io/websocket"
+)
+
+// dial connects a browser to the server.
+func dial(ctx context.Context, t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+
+	ws, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(url, "http"), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { ws.CloseNow() })
+
+	return ws
+}
+
+// exchange sends a message and returns the response.
+func exchange(ctx context.Context, t *testing.T, ws *websocket.Conn, msg string) map[string]any {
+	t.Helper()
+
+	if err := ws.Write(ctx, websocket.MessageText, []byte(msg)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return read(ctx, t, ws)
+}
+
+// read returns the next message of a connection.
+func read(ctx context.Context, t *testing.T, ws *websocket.Conn) map[string]any {
+	t.Helper()
+
+	_, data, err := ws.Read(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var msg map[string]any
+	if err := json.Unmarshal(data, &msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return msg
+}
+
+func TestServer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	hub := omloxtest.NewServer()
+	defer hub.Close()
+
+	c, err := omlox.New(hub.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer c.Close()
+
+	sub, err := c.Subscribe(ctx, omlox.TopicLocationUpdates)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s := NewServer()
+	go s.Run(ctx, sub)
+
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	all := dial(ctx, t, srv.URL)
+	filtered := dial(ctx, t, srv.URL)
+
+	if msg := exchange(ctx, t, all, `{"event":"subscribe","topic":"location_updates"}`); msg["event"] != "subscribed" || msg["subscription_id"] != 1.0 {
+		t.Fatalf("unexpected subscription response %v", msg)
+	}
+	if msg := exchange(ctx, t, filtered, `{"event":"subscribe","topic":"location_updates","params":{"provider_ids":"p2"}}`); msg["event"] != "subscribed" {
+		t.Fatalf("unexpected subscription response %v", msg)
+	}
+
+	if msg := exchange(ctx, t, all, `{"event":"subscribe","topic":"fence_events"}`); msg["event"] != "error" || msg["code"] != float64(omlox.ErrCodeUnknownTopic) {
+		t.Errorf("expected an unknown topic error, got %v", msg)
+	}
+	if msg := exchange(ctx, t, all, `{"event":"subscribe","topic":"location_updates","params":{"trackable_ids":"agv"}}`); msg["event"] != "error" || msg["code"] != float64(omlox.ErrCodeSubscription) {
+		t.Errorf("expected a subscription error, got %v", msg)
+	}
+
+	if n := s.Connections(); n != 2 {
+		t.Errorf("expected 2 connections, got %d", n)
+	}
+
+	hub.Publish(ctx, omlox.TopicLocationUpdates, json.RawMessage(`{"provider_id":"p1"}`), json.RawMessage(`{"provider_id":"p2"}`))
+
+	if msg := read(ctx, t, all); msg["event"] != "message" || len(msg["payload"].([]any)) != 2 {
+		t.Errorf("expected both locations, got %v", msg)
+	}
+	if msg := read(ctx, t, filtered); len(msg["payload"].([]any)) != 1 || msg["topic"] != "location_updates" {
+		t.Errorf("expected the location of p2 only, got %v", msg)
+	}
+
+	if msg := exchange(ctx, t, all, `{"event":"unsubscribe","subscription_id":1}`); msg["event"] != "unsubscribed" {
+		t.Errorf("unexpected unsubscription response %v", msg)
+	}
+	if msg := exchange(ctx, t, all, `{"event":"unsubscribe","subscription_id":1}`); msg["event"] != "error" {
+		t.Errorf("expected an unsubscription error, got %v", msg)
+	}
+}
+
+func TestServerSlowConnection(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	s := NewServer(WithBufferSize(1))
+	s.Broadcast(&omlox.WrapperObject{Topic: omlox.TopicFenceEvents})
+
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	ws := dial(ctx, t, srv.URL)
+	exchange(ctx, t, ws, `{"event":"subscribe","topic":"fence_events"}`)
+
+	// the messages are not read, filling the buffer of the connection
+	for i := 0; i < 1000 && s.Connections() > 0; i++ {
+		s.Broadcast(&omlox.WrapperObject{Topic: omlox.TopicFenceEvents, Payload: []json.RawMessage{json.RawMessage(`{}`)}})
+	}
+
+	for {
+		if _, _, err := ws.Read(ctx); err != nil {
+			if websocket.CloseStatus(err) != websocket.StatusPolicyViolation {
+				t.Errorf("expected the connection closed as too slow, got %v", err)
+			}
+			return
+		}
+	}
+}
+
+func TestServerEvents(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	s := NewServer(WithOriginPatterns("*.example.com"))
+	s.Broadcast(&omlox.WrapperObject{Topic: omlox.TopicLocationUpdates})
+
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	tests := []struct {
+		query  string
+		status int
+	}{
+		{query: "", status: http.StatusBadRequest},
+		{query: "?topic=fence_events", status: http.StatusNotFound},
+		{query: "?topic=location_updates&trackable_ids=agv", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		resp, err := http.Get(srv.URL + tt.query)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.status {
+			t.Errorf("%q: expected status %d, got %d", tt.query, tt.status, resp.StatusCode)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/events?topic=location_updates&provider_ids=p2", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req.Header.Set("Origin", "https://dashboard.example.com")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	if origin := resp.Header.Get("Access-Control-Allow-Origin"); origin != "https://dashboard.example.com" {
+		t.Errorf("unexpected allowed origin %q", origin)
+	}
+
+	s.Broadcast(&omlox.WrapperObject{
+		Topic:   omlox.TopicLocationUpdates,
+		Payload: []json.RawMessage{json.RawMessage(`{"provider_id":"p1"}`), json.RawMessage(`{"provider_id":"p2"}`)},
+	})
+
+	sc := bufio.NewScanner(resp.Body)
+	for sc.Scan() {
+		data, ok := strings.CutPrefix(sc.Text(), "data: ")
+		if !ok {
+			continue
+		}
+
+		var msg omlox.WrapperObject
+		if err := json.Unmarshal([]byte(data), &msg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if msg.Event != omlox.EventMsg || msg.SubscriptionID != 1 || len(msg.Payload) != 1 || string(msg.Payload[0]) != `{"provider_id":"p2"}` {
+			t.Errorf("expected the location of p2 only, got %+v", msg)
+		}
+		return
+	}
+	t.Fatalf("expected an event, got %v", sc.Err())
+}
@@ -0,0 +1,240 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// HubInfo defines model for the Hub information.
+//
+//easyjson:json
+type HubInfo struct {
+	// The name of the Hub implementation.
+	Name string `json:"name,omitempty"`
+
+	// The version of the omlox™ Hub API implemented by the Hub.
+	Version string `json:"version"`
+
+	// The optional APIs implemented by the Hub. When omitted, the Hub is assumed
+	// to implement every optional API available in its version.
+	Extensions []string `json:"extensions,omitempty"`
+}
+
+// Feature is an API of the Hub which is not available in every Hub version or implementation.
+type Feature int
+
+// Defines values for Feature.
+const (
+	// FeatureHistory is the optional location history API.
+	FeatureHistory Feature = iota
+
+	// FeatureFenceEventHistory is the optional fence event history API.
+	FeatureFenceEventHistory
+
+	// FeatureSensors is the optional sensor data API.
+	FeatureSensors
+
+	// FeatureSubscriptionFilters is the optional filtering of websocket subscriptions
+	// by trackable and provider ids.
+	FeatureSubscriptionFilters
+
+	// FeatureProviderTrackables is the optional lookup of the trackables using
+	// a location provider.
+	FeatureProviderTrackables
+
+	// FeatureWebhooks is the registration of webhooks, a vendor extension of the
+	// Hub API.
+	FeatureWebhooks
+)
+
+// String return a text representation.
+func (f Feature) String() string {
+	features := [...]string{
+		"history",
+		"fence_event_history",
+		"sensors",
+		"subscription_filters",
+		"provider_trackables",
+		"webhooks",
+	}
+
+	if int(f) < 0 || len(features) <= int(f) {
+		return ""
+	}
+
+	return features[f]
+}
+
+// compatibility is the feature support matrix, keyed by the first Hub version
+// implementing each feature. Optional features must also be advertised in the
+// Hub information extensions, if the Hub lists them.
+var compatibility = map[Feature]struct {
+	since    hubVersion
+	optional bool
+}{
+	FeatureHistory:             {since: hubVersion{1, 0, 0}, optional: true},
+	FeatureFenceEventHistory:   {since: hubVersion{1, 1, 0}, optional: true},
+	FeatureSensors:             {since: hubVersion{1, 1, 0}, optional: true},
+	FeatureSubscriptionFilters: {since: hubVersion{1, 1, 0}, optional: true},
+	FeatureProviderTrackables:  {since: hubVersion{1, 1, 0}, optional: true},
+	FeatureWebhooks:            {since: hubVersion{1, 0, 0}, optional: true},
+}
+
+// Supports reports whether the Hub described by the information supports the feature.
+// Hubs with an unknown version are assumed to support every feature.
+func (i HubInfo) Supports(f Feature) bool {
+	v, ok := parseHubVersion(i.Version)
+	if !ok {
+		return true
+	}
+
+	c, ok := compatibility[f]
+	if !ok {
+		return true
+	}
+
+	if v.less(c.since) {
+		return false
+	}
+
+	if c.optional && i.Extensions != nil {
+		return slices.Contains(i.Extensions, f.String())
+	}
+
+	return true
+}
+
+// hubVersion is a major, minor and patch version number.
+type hubVersion [3]int
+
+// parseHubVersion parses versions like "1.1", "v1.1.0" or "1.1.0-beta".
+func parseHubVersion(s string) (hubVersion, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexAny(s, "-+"); i >= 0 {
+		s = s[:i]
+	}
+
+	parts := strings.Split(s, ".")
+	if s == "" || len(parts) > 3 {
+		return hubVersion{}, false
+	}
+
+	var v hubVersion
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return hubVersion{}, false
+		}
+		v[i] = n
+	}
+
+	return v, true
+}
+
+func (v hubVersion) less(o hubVersion) bool {
+	for i := range v {
+		if v[i] != o[i] {
+			return v[i] < o[i]
+		}
+	}
+	return false
+}
+
+// Info gets the Hub information. The information is fetched once and cached
+// for the lifetime of the client, unless the Hub version was configured with WithHubVersion.
+func (c *Client) Info(ctx context.Context) (*HubInfo, error) {
+	c.infoMu.Lock()
+	defer c.infoMu.Unlock()
+
+	if c.info != nil {
+		return c.info, nil
+	}
+
+	if c.configuration.HubVersion != "" {
+		c.info = &HubInfo{Version: c.configuration.HubVersion}
+		return c.info, nil
+	}
+
+	info, err := sendRequestParseResponse[HubInfo](
+		ctx,
+		c,
+		http.MethodGet,
+		"/info",
+		nil, // request body
+		nil, // request query parameters
+		nil, // request headers
+	)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		info = &HubInfo{}
+	}
+
+	c.info = info
+	return c.info, nil
+}
+
+// Supports reports whether the Hub supports the feature, according to its version.
+func (c *Client) Supports(ctx context.Context, f Feature) (bool, error) {
+	info, err := c.Info(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return info.Supports(f), nil
+}
+
+// require returns ErrNotSupported if the Hub is known not to support the feature.
+// Hubs not exposing their information are never gated, and failures to fetch it
+// are left to the request itself to report.
+func (c *Client) require(ctx context.Context, f Feature) error {
+	info := c.hubInfo(ctx)
+	if info == nil || info.Supports(f) {
+		return nil
+	}
+
+	return fmt.Errorf("%s: %w by hub version %s", f, ErrNotSupported, info.Version)
+}
+
+// hubInfo returns the Hub information, or nil if it could not be fetched.
+func (c *Client) hubInfo(ctx context.Context) *HubInfo {
+	info, err := c.Info(ctx)
+	if err == nil {
+		return info
+	}
+
+	// transport failures may be transient, the information is asked again next time
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) || ctx.Err() != nil {
+		return nil
+	}
+
+	// the Hub responded, but without information: do not ask again
+	c.infoMu.Lock()
+	defer c.infoMu.Unlock()
+
+	if c.info == nil {
+		c.info = &HubInfo{}
+	}
+
+	return c.info
+}
+
+// resetInfo forgets the cached Hub information, as the Hub may have been upgraded
+// while the client was disconnected.
+func (c *Client) resetInfo() {
+	c.infoMu.Lock()
+	defer c.infoMu.Unlock()
+
+	c.info = nil
+}
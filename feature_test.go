@@ -0,0 +1,139 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseHubVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    hubVersion
+		ok      bool
+	}{
+		{version: "1", want: hubVersion{1, 0, 0}, ok: true},
+		{version: "1.1", want: hubVersion{1, 1, 0}, ok: true},
+		{version: "v1.1.2", want: hubVersion{1, 1, 2}, ok: true},
+		{version: "2.0.0-beta.1", want: hubVersion{2, 0, 0}, ok: true},
+		{version: "1.0.0+build", want: hubVersion{1, 0, 0}, ok: true},
+		{version: "", ok: false},
+		{version: "1.x", ok: false},
+		{version: "1.2.3.4", ok: false},
+	}
+
+	for _, tc := range tests {
+		got, ok := parseHubVersion(tc.version)
+		if ok != tc.ok || got != tc.want {
+			t.Errorf("parseHubVersion(%q) = %v, %v; want %v, %v", tc.version, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestHubInfoSupports(t *testing.T) {
+	tests := []struct {
+		name    string
+		info    HubInfo
+		feature Feature
+		want    bool
+	}{
+		{name: "unknown version", info: HubInfo{}, feature: FeatureSensors, want: true},
+		{name: "older version", info: HubInfo{Version: "1.0"}, feature: FeatureSensors, want: false},
+		{name: "newer version", info: HubInfo{Version: "1.2.0"}, feature: FeatureSensors, want: true},
+		{name: "advertised", info: HubInfo{Version: "1.0", Extensions: []string{"history"}}, feature: FeatureHistory, want: true},
+		{name: "not advertised", info: HubInfo{Version: "1.0", Extensions: []string{}}, feature: FeatureHistory, want: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.info.Supports(tc.feature); got != tc.want {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestFeatureGating(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     *http.Response
+		options  []ClientOption
+		err      error
+		requests []string
+	}{
+		{
+			name: "not supported",
+			info: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"version":"1.0.0","extensions":[]}`)),
+			},
+			err:      ErrNotSupported,
+			requests: []string{"GET /v2/info"},
+		},
+		{
+			name: "supported",
+			info: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"version":"1.0.0","extensions":["history"]}`)),
+			},
+			requests: []string{"GET /v2/info", "GET /v2/history/locations", "GET /v2/history/locations"},
+		},
+		{
+			name: "no hub information",
+			info: &http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       io.NopCloser(strings.NewReader(`404 page not found`)),
+			},
+			requests: []string{"GET /v2/info", "GET /v2/history/locations", "GET /v2/history/locations"},
+		},
+		{
+			name:     "configured version",
+			options:  []ClientOption{WithHubVersion("1.0")},
+			requests: []string{"GET /v2/history/locations", "GET /v2/history/locations"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests []string
+
+			httpClient := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					requests = append(requests, r.Method+" "+r.URL.Path)
+
+					if strings.HasSuffix(r.URL.Path, "/info") {
+						return tc.info, nil
+					}
+
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`[]`)),
+					}, nil
+				}),
+			}
+
+			c, err := New("http://localhost:8081/v2", append(tc.options, WithHTTPClient(httpClient))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := 0; i < 2; i++ {
+				_, err = c.History.Locations(context.Background(), time.Time{}, time.Now())
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("expected error %v, got %v", tc.err, err)
+				}
+			}
+
+			if strings.Join(requests, "\n") != strings.Join(tc.requests, "\n") {
+				t.Errorf("unexpected requests:\n%s\nwanted:\n%s", strings.Join(requests, "\n"), strings.Join(tc.requests, "\n"))
+			}
+		})
+	}
+}
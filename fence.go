@@ -97,3 +97,25 @@ type Fence struct {
 	// custom properties, but it MUST preserve the properties if set.
 	Properties json.RawMessage `json:"properties,omitempty"`
 }
+
+// Contains reports whether a location is inside the region of the fence. Locations with a
+// crs other than the one of the fence are never inside it.
+func (f Fence) Contains(loc Location) bool {
+	if f.Region == nil {
+		return false
+	}
+
+	locCrs, fenceCrs := loc.Crs, f.Crs
+	if locCrs == "" {
+		locCrs = CrsLocal
+	}
+	if fenceCrs == "" {
+		fenceCrs = CrsWGS84
+	}
+	if locCrs != fenceCrs {
+		return false
+	}
+
+	_, _, inside := fenceDistance(loc.Position.Base(), &f, locCrs)
+	return inside
+}
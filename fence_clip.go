@@ -0,0 +1,115 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+//go:build !omlox_minimal
+
+package omlox
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/tidwall/geojson"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go/clip"
+)
+
+// ErrFenceMismatch is returned when combining fences of different crs or zones.
+var ErrFenceMismatch = errors.New("fences must have the same crs and zone")
+
+// Union returns the fences of the area within f or any of the other fences, such as to
+// merge adjacent fences. See Difference for the fences returned.
+func (f Fence) Union(others ...Fence) ([]Fence, error) {
+	return f.combine(others, clip.Union)
+}
+
+// Intersection returns the fences of the area within f and all the other fences.
+// See Difference for the fences returned.
+func (f Fence) Intersection(others ...Fence) ([]Fence, error) {
+	return f.combine(others, clip.Intersection)
+}
+
+// Difference returns the fences of the area within f but not within any of the other
+// fences, such as to cut the overlap of a fence with its neighbours.
+//
+// The fences returned are polygons with the properties of f. The first fence keeps the
+// id of f, and the others, when the area is split in several polygons, have no id to
+// be created as new fences. No fences are returned for an empty area. Point fences are
+// combined as circles of their radius. ErrFenceMismatch is returned for fences of
+// different crs or zones.
+func (f Fence) Difference(others ...Fence) ([]Fence, error) {
+	return f.combine(others, clip.Difference)
+}
+
+// combine returns the fences of an operation on the region of f and the regions of the
+// other fences, in turn.
+func (f Fence) combine(others []Fence, operation func(a, b []clip.Polygon) []clip.Polygon) ([]Fence, error) {
+	crs := fenceCrs(f.Crs)
+
+	p, err := fencePolygon(f, crs)
+	if err != nil {
+		return nil, err
+	}
+	polys := []clip.Polygon{p}
+
+	for _, g := range others {
+		if fenceCrs(g.Crs) != crs || g.ZoneID != f.ZoneID {
+			return nil, ErrFenceMismatch
+		}
+
+		p, err := fencePolygon(g, crs)
+		if err != nil {
+			return nil, err
+		}
+		polys = operation(polys, []clip.Polygon{p})
+	}
+
+	fences := make([]Fence, len(polys))
+	for i, p := range polys {
+		fences[i] = f
+		fences[i].Region = NewRegionPolygon(geometry.NewPoly(p.Exterior, p.Holes, nil))
+		fences[i].Radius = 0
+		if i > 0 {
+			fences[i].ID = uuid.Nil
+		}
+	}
+
+	return fences, nil
+}
+
+// fencePolygon returns the polygon of the region of a fence, as the circle of its radius
+// for point fences.
+func fencePolygon(f Fence, crs string) (clip.Polygon, error) {
+	if f.Region == nil {
+		return clip.Polygon{}, fmt.Errorf("fence %s has no region", f.ID)
+	}
+
+	switch region := f.Region.Object.(type) {
+	case *geojson.Point:
+		if f.Radius <= 0 {
+			return clip.Polygon{}, fmt.Errorf("point fence %s has no radius", f.ID)
+		}
+		circle := Ellipse{SemiMajor: f.Radius, SemiMinor: f.Radius}
+		return clip.Polygon{Exterior: circle.Ring(region.Base(), crs, CircleSegments)}, nil
+	case *geojson.Polygon:
+		poly := region.Base()
+
+		p := clip.Polygon{Exterior: ringPoints(poly.Exterior)}
+		for _, h := range poly.Holes {
+			p.Holes = append(p.Holes, ringPoints(h))
+		}
+		return p, nil
+	}
+
+	return clip.Polygon{}, fmt.Errorf("fence %s has an unsupported region", f.ID)
+}
+
+// ringPoints returns the points of a ring.
+func ringPoints(ring geometry.Ring) []geometry.Point {
+	pts := make([]geometry.Point, ring.NumPoints())
+	for i := range pts {
+		pts[i] = ring.PointAt(i)
+	}
+	return pts
+}
@@ -0,0 +1,59 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+//go:build !omlox_minimal
+
+package omlox
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tidwall/geojson/geometry"
+)
+
+func TestFenceCombine(t *testing.T) {
+	id := uuid.New()
+	a := Fence{ID: id, Name: "dock", Region: NewRegionPoint(geometry.Point{X: 0, Y: 0}), Radius: 1, Crs: CrsLocal, ZoneID: "z1"}
+	b := Fence{ID: uuid.New(), Region: NewRegionPoint(geometry.Point{X: 5, Y: 0}), Radius: 1, Crs: CrsLocal, ZoneID: "z1"}
+
+	fences, err := a.Union(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fences) != 2 {
+		t.Fatalf("expected 2 fences, got %d", len(fences))
+	}
+	if fences[0].ID != id || fences[0].Name != "dock" || fences[0].Radius != 0 || fences[0].ZoneID != "z1" {
+		t.Errorf("expected the first fence with the properties of the fence, got %+v", fences[0])
+	}
+	if fences[1].ID != uuid.Nil || fences[1].Name != "dock" {
+		t.Errorf("expected the other fences without id, got %+v", fences[1])
+	}
+
+	if fences, err := a.Intersection(b); err != nil || len(fences) != 0 {
+		t.Errorf("expected no fences for disjoint circles, got %d fences (%v)", len(fences), err)
+	}
+	if fences, err := a.Difference(b); err != nil || len(fences) != 1 || fences[0].ID != id {
+		t.Errorf("expected the fence unchanged, got %d fences (%v)", len(fences), err)
+	}
+
+	// with a larger circle around the first one
+	c := Fence{Region: NewRegionPoint(geometry.Point{X: 0, Y: 0}), Radius: 2, Crs: CrsLocal, ZoneID: "z1"}
+	if fences, err := a.Union(b, c); err != nil || len(fences) != 2 {
+		t.Errorf("expected 2 fences, got %d fences (%v)", len(fences), err)
+	}
+	if fences, err := a.Difference(b, c); err != nil || len(fences) != 0 {
+		t.Errorf("expected no fences, got %d fences (%v)", len(fences), err)
+	}
+
+	b.ZoneID = "z2"
+	if _, err := a.Union(b); !errors.Is(err, ErrFenceMismatch) {
+		t.Errorf("expected ErrFenceMismatch, got %v", err)
+	}
+
+	if _, err := a.Union(Fence{Region: NewRegionPoint(geometry.Point{}), Crs: CrsLocal, ZoneID: "z1"}); err == nil {
+		t.Error("expected error for point fence without radius")
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FenceEvent defines model for FenceEvent.
+// Fence events received from subscriptions are validated, other callers call Validate.
+//
+//easyjson:json
+type FenceEvent struct {
+	// ID is the unique identifier of the fence event.
+	ID uuid.UUID `json:"id"`
+
+	// FenceID is the id of the fence the event refers to.
+	FenceID uuid.UUID `json:"fence_id"`
+
+	// ProviderID is the id of the location provider which triggered the event.
+	ProviderID string `json:"provider_id,omitempty"`
+
+	// Trackables are the ids of the trackables related to the event.
+	Trackables []uuid.UUID `json:"trackables,omitempty"`
+
+	// TrackableID is the id of the trackable which triggered the event, if any.
+	TrackableID *uuid.UUID `json:"trackable_id,omitempty"`
+
+	// Location is the location which triggered the event.
+	Location *Location `json:"location,omitempty"`
+
+	// EventType is either 'region_entry' or 'region_exit'.
+	EventType FenceEventType `json:"event_type"`
+
+	// EntryTime is the time the fence was entered.
+	EntryTime *time.Time `json:"entry_time,omitempty"`
+
+	// ExitTime is the time the fence was left. Only present on exit events.
+	ExitTime *time.Time `json:"exit_time,omitempty"`
+
+	// ForeignID is the foreign id of the fence, if the event originated from a foreign fence.
+	ForeignID string `json:"foreign_id,omitempty"`
+}
+
+// Validate checks the event has the fields required by the omlox™ schema.
+func (e FenceEvent) Validate() error {
+	if e.ID == uuid.Nil {
+		return fmt.Errorf("fence event id must not be empty")
+	}
+	if e.FenceID == uuid.Nil {
+		return fmt.Errorf("fence event %s: fence id must not be empty", e.ID)
+	}
+	if e.EventType.String() == "" {
+		return fmt.Errorf("fence event %s: invalid event type %d", e.ID, e.EventType)
+	}
+
+	return nil
+}
+
+// Time returns the time at which the event occurred: the exit time for exit
+// events and the entry time for entry events. If not present, the zero time is returned.
+func (e FenceEvent) Time() time.Time {
+	ts := e.EntryTime
+	if e.EventType == FenceEventTypeRegionExit {
+		ts = e.ExitTime
+	}
+
+	if ts == nil {
+		return time.Time{}
+	}
+
+	return *ts
+}
+
+// FenceEventType is the type of a fence event.
+type FenceEventType int
+
+// Defines values for FenceEventType.
+const (
+	FenceEventTypeRegionEntry FenceEventType = iota
+	FenceEventTypeRegionExit
+)
+
+// FromString assigs itself from type name.
+func (t *FenceEventType) FromString(name string) error {
+	v, ok := map[string]FenceEventType{
+		FenceEventTypeRegionEntry.String(): FenceEventTypeRegionEntry,
+		FenceEventTypeRegionExit.String():  FenceEventTypeRegionExit,
+	}[name]
+
+	if !ok {
+		return fmt.Errorf("fence event of type %s not supported", name)
+	}
+
+	*t = v
+	return nil
+}
+
+// String return a text representation.
+func (t FenceEventType) String() string {
+	types := [...]string{
+		"region_entry",
+		"region_exit",
+	}
+
+	if int(t) < 0 || len(types) <= int(t) {
+		return ""
+	}
+
+	return types[t]
+}
+
+// MarshalJSON encodes type in to JSON.
+func (t FenceEventType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON decodes type from JSON.
+func (t *FenceEventType) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	return t.FromString(s)
+}
@@ -0,0 +1,102 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+var fenceEventsJSONTestCases = []struct {
+	name  string
+	event FenceEvent
+	json  []byte
+}{
+	{
+		name: "entry",
+		event: FenceEvent{
+			ID:          uuid.MustParse("0c1a7b8e-4b59-4d6a-9d4b-7c9e0a1f2b3c"),
+			FenceID:     uuid.MustParse("497f6eca-6276-4993-bfeb-53cbbbba6f08"),
+			ProviderID:  "77:4f:34:69:27:40",
+			Trackables:  []uuid.UUID{uuid.MustParse("9b59961e-2a6a-4712-86e7-aba5a3e8be1f")},
+			TrackableID: opt(uuid.MustParse("9b59961e-2a6a-4712-86e7-aba5a3e8be1f")),
+			EventType:   FenceEventTypeRegionEntry,
+			EntryTime:   mustParseTime("2023-10-17T11:14:37.206Z"),
+		},
+		json: []byte(`{"id":"0c1a7b8e-4b59-4d6a-9d4b-7c9e0a1f2b3c","fence_id":"497f6eca-6276-4993-bfeb-53cbbbba6f08","provider_id":"77:4f:34:69:27:40","trackables":["9b59961e-2a6a-4712-86e7-aba5a3e8be1f"],"trackable_id":"9b59961e-2a6a-4712-86e7-aba5a3e8be1f","event_type":"region_entry","entry_time":"2023-10-17T11:14:37.206Z"}`),
+	},
+	{
+		name: "exit",
+		event: FenceEvent{
+			ID:         uuid.MustParse("0c1a7b8e-4b59-4d6a-9d4b-7c9e0a1f2b3c"),
+			FenceID:    uuid.MustParse("497f6eca-6276-4993-bfeb-53cbbbba6f08"),
+			ProviderID: "77:4f:34:69:27:40",
+			EventType:  FenceEventTypeRegionExit,
+			EntryTime:  mustParseTime("2023-10-17T11:14:37.206Z"),
+			ExitTime:   mustParseTime("2023-10-17T11:24:37.206Z"),
+			ForeignID:  "Beacon1C5: Floor 1",
+		},
+		json: []byte(`{"id":"0c1a7b8e-4b59-4d6a-9d4b-7c9e0a1f2b3c","fence_id":"497f6eca-6276-4993-bfeb-53cbbbba6f08","provider_id":"77:4f:34:69:27:40","event_type":"region_exit","entry_time":"2023-10-17T11:14:37.206Z","exit_time":"2023-10-17T11:24:37.206Z","foreign_id":"Beacon1C5: Floor 1"}`),
+	},
+}
+
+func TestFenceEventMarshal(t *testing.T) {
+	for _, tc := range fenceEventsJSONTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			JSONMarshalOK(t, tc.event, tc.json)
+		})
+	}
+}
+
+func TestFenceEventUnmarshal(t *testing.T) {
+	for _, tc := range fenceEventsJSONTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			JSONUnmarshalOK(t, tc.json, tc.event)
+		})
+	}
+}
+
+func TestFenceEventValidate(t *testing.T) {
+	testCases := []struct {
+		name string
+		json string
+	}{
+		{name: "missing id", json: `{"fence_id":"497f6eca-6276-4993-bfeb-53cbbbba6f08","event_type":"region_entry"}`},
+		{name: "missing fence id", json: `{"id":"0c1a7b8e-4b59-4d6a-9d4b-7c9e0a1f2b3c","event_type":"region_entry"}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// events are decoded leniently, and rejected by Validate
+			var e FenceEvent
+			if err := json.Unmarshal([]byte(tc.json), &e); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := e.Validate(); err == nil {
+				t.Errorf("expected an error, got %+v", e)
+			}
+		})
+	}
+
+	// events of unknown types cannot be decoded
+	var e FenceEvent
+	if err := json.Unmarshal([]byte(`{"id":"0c1a7b8e-4b59-4d6a-9d4b-7c9e0a1f2b3c","fence_id":"497f6eca-6276-4993-bfeb-53cbbbba6f08","event_type":"region_stay"}`), &e); err == nil {
+		t.Errorf("expected an error, got %+v", e)
+	}
+}
+
+func TestFenceEventListLenient(t *testing.T) {
+	// a single invalid event does not fail the decoding of the history
+	data := `[{"id":"0c1a7b8e-4b59-4d6a-9d4b-7c9e0a1f2b3c","fence_id":"497f6eca-6276-4993-bfeb-53cbbbba6f08","event_type":"region_entry"},{"event_type":"region_exit"}]`
+
+	var events []FenceEvent
+	if err := json.Unmarshal([]byte(data), &events); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 || events[0].Validate() != nil || events[1].Validate() == nil {
+		t.Errorf("unexpected events: %+v", events)
+	}
+}
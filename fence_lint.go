@@ -0,0 +1,430 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+//go:build !omlox_minimal
+
+package omlox
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+	"github.com/tidwall/geojson"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go/clip"
+)
+
+// DefaultGapTolerance is the default distance in meters under which fences not touching
+// each other are reported as separated by a gap.
+const DefaultGapTolerance = 0.5
+
+// Severity is the severity of a fence lint issue.
+type Severity int
+
+// Defines values for Severity.
+const (
+	// SeverityInfo are configurations likely intended, such as nested fences.
+	SeverityInfo Severity = iota
+
+	// SeverityWarning are configurations likely to cause unexpected fence events, such
+	// as overlapping fences.
+	SeverityWarning
+
+	// SeverityError are invalid configurations, such as self-intersecting polygons,
+	// whose fence events are undefined.
+	SeverityError
+)
+
+// FromString assigs itself from severity name.
+func (s *Severity) FromString(name string) error {
+	v, ok := map[string]Severity{
+		SeverityInfo.String():    SeverityInfo,
+		SeverityWarning.String(): SeverityWarning,
+		SeverityError.String():   SeverityError,
+	}[name]
+
+	if !ok {
+		return fmt.Errorf("severity %s not supported", name)
+	}
+
+	*s = v
+	return nil
+}
+
+// String return a text representation.
+func (s Severity) String() string {
+	severities := [...]string{
+		"info",
+		"warning",
+		"error",
+	}
+
+	if int(s) < 0 || len(severities) <= int(s) {
+		return ""
+	}
+
+	return severities[s]
+}
+
+// MarshalJSON encodes severity in to JSON.
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON decodes severity from JSON.
+func (s *Severity) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err != nil {
+		return err
+	}
+
+	return s.FromString(name)
+}
+
+// Fence lint checks.
+const (
+	// LintSelfIntersection reports polygons whose edges cross each other.
+	LintSelfIntersection = "self-intersection"
+
+	// LintOverlap reports fences overlapping each other, on the same floor.
+	LintOverlap = "overlap"
+
+	// LintGap reports fences closer than the gap tolerance without touching, on the
+	// same floor, such as neighbours drawn by hand.
+	LintGap = "gap"
+
+	// LintOutsideZone reports fences outside the bounds of their zone, or of unknown zones.
+	LintOutsideZone = "outside-zone"
+)
+
+// LintIssue is a spatial misconfiguration of a fence.
+type LintIssue struct {
+	// Check which found the issue (e.g. overlap).
+	Check string `json:"check"`
+
+	// Severity of the issue.
+	Severity Severity `json:"severity"`
+
+	// FenceID and FenceName identify the fence.
+	FenceID   uuid.UUID `json:"fence_id"`
+	FenceName string    `json:"fence_name,omitempty"`
+
+	// OtherID is the other fence of overlaps and gaps.
+	OtherID *uuid.UUID `json:"other_id,omitempty"`
+
+	// Detail describes the issue.
+	Detail string `json:"detail"`
+}
+
+// LintOption is a configuration option of the fence lint.
+type LintOption func(*linter)
+
+// WithGapTolerance sets the distance in meters under which fences not touching each
+// other are reported as separated by a gap.
+// Default: 0.5 meters
+func WithGapTolerance(d float64) LintOption {
+	return func(l *linter) {
+		l.gapTolerance = d
+	}
+}
+
+// LintFences checks the geometry of fences, as misconfigured geometries cause phantom
+// fence events: self-intersecting polygons, fences overlapping each other or separated
+// by small gaps, and fences outside the bounds of their zone.
+//
+// Fences are compared on a plane in meters: WGS84 fences, and local fences of zones with
+// ground control points, are compared together, and other local fences with the fences
+// of the same zone. Fences of different floors are not compared. The bounds of a zone
+// are the circle of its position and radius. Point fences are checked as the circles
+// of their radius.
+func LintFences(fences []Fence, zones []Zone, opts ...LintOption) []LintIssue {
+	l := &linter{
+		gapTolerance: DefaultGapTolerance,
+		zones:        make(map[string]*Zone, len(zones)),
+		georefs:      make(map[string]*Georeference),
+	}
+	for i := range zones {
+		l.zones[zones[i].ID.String()] = &zones[i]
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l.lint(fences)
+}
+
+// linter checks the geometry of fences.
+type linter struct {
+	gapTolerance float64
+
+	zones   map[string]*Zone
+	georefs map[string]*Georeference
+
+	// plane of the WGS84 fences, tangent to the first one
+	plane *Georeference
+
+	issues []LintIssue
+}
+
+// lintFence is a fence on the plane of its frame, in meters.
+type lintFence struct {
+	*Fence
+
+	// frame of the coordinates, CrsWGS84 for the plane of WGS84 fences: fences are only
+	// compared within the same frame
+	frame string
+
+	poly clip.Polygon
+	area float64
+}
+
+func (l *linter) lint(fences []Fence) []LintIssue {
+	var planar []lintFence
+	for i := range fences {
+		f := &fences[i]
+		if f.Region == nil {
+			continue
+		}
+
+		lf, ok := l.project(f)
+		if !ok {
+			continue
+		}
+		if selfIntersectingPolygon(lf.poly) {
+			l.report(f, nil, LintSelfIntersection, SeverityError, "the edges of the polygon cross each other")
+			continue
+		}
+		lf.area = polygonArea(lf.poly)
+
+		l.lintZone(lf)
+		planar = append(planar, lf)
+	}
+
+	for i, a := range planar {
+		for _, b := range planar[i+1:] {
+			if a.frame == b.frame && a.Floor == b.Floor {
+				l.lintPair(a, b)
+			}
+		}
+	}
+
+	return l.issues
+}
+
+// project returns the fence on the plane of its frame, or false if it can't be checked,
+// such as point fences without radius.
+func (l *linter) project(f *Fence) (lintFence, bool) {
+	crs := fenceCrs(f.Crs)
+	lf := lintFence{Fence: f, frame: crs}
+
+	if crs == CrsLocal {
+		z, ok := l.zones[f.ZoneID]
+		if !ok {
+			l.report(f, nil, LintOutsideZone, SeverityError, fmt.Sprintf("unknown zone %q", f.ZoneID))
+			return lintFence{}, false
+		}
+		lf.frame = "zone:" + f.ZoneID
+
+		georef, ok := l.georefs[f.ZoneID]
+		if !ok {
+			georef, _ = z.Georeference()
+			l.georefs[f.ZoneID] = georef
+		}
+		if georef != nil {
+			lf.frame = CrsWGS84
+		}
+	}
+
+	if _, ok := f.Region.Object.(*geojson.Point); ok && f.Radius <= 0 {
+		return lintFence{}, false
+	}
+
+	poly, err := fencePolygon(*f, crs)
+	if err != nil {
+		return lintFence{}, false
+	}
+
+	if lf.frame == CrsWGS84 {
+		poly = mapPolygon(poly, func(p geometry.Point) geometry.Point {
+			if crs == CrsLocal {
+				p = l.georefs[f.ZoneID].ToWGS84(p)
+			}
+			if l.plane == nil {
+				l.plane = &Georeference{lon: p.X, lat: p.Y}
+			}
+			return l.plane.project(p)
+		})
+	}
+	lf.poly = poly
+
+	return lf, true
+}
+
+// lintZone checks the fence is within the bounds of its zone.
+func (l *linter) lintZone(f lintFence) {
+	z, ok := l.zones[f.ZoneID]
+	if !ok || f.frame != CrsWGS84 || z.Position == nil || z.Radius <= 0 {
+		return
+	}
+
+	center := l.plane.project(z.Position.Base())
+	bounds := clip.Polygon{Exterior: Ellipse{SemiMajor: z.Radius, SemiMinor: z.Radius}.Ring(center, CrsLocal, CircleSegments)}
+
+	inside := 0.0
+	for _, p := range clip.Intersection([]clip.Polygon{f.poly}, []clip.Polygon{bounds}) {
+		inside += polygonArea(p)
+	}
+
+	name := zoneName(z)
+	switch outside := 1 - inside/f.area; {
+	case inside == 0:
+		l.report(f.Fence, nil, LintOutsideZone, SeverityError, "outside the bounds of zone "+name)
+	case outside > 1e-6:
+		l.report(f.Fence, nil, LintOutsideZone, SeverityWarning, fmt.Sprintf("%.1f%% outside the bounds of zone %s", outside*100, name))
+	}
+}
+
+// lintPair checks two fences of the same frame and floor for overlaps and gaps.
+func (l *linter) lintPair(a, b lintFence) {
+	overlap := 0.0
+	for _, p := range clip.Intersection([]clip.Polygon{a.poly}, []clip.Polygon{b.poly}) {
+		overlap += polygonArea(p)
+	}
+
+	if overlap > 0 {
+		switch smallest := math.Min(a.area, b.area); {
+		case overlap < smallest*(1-1e-6):
+			l.report(a.Fence, b.Fence, LintOverlap, SeverityWarning, fmt.Sprintf("overlaps fence %s by %.2f m²", fenceName(b.Fence), overlap))
+		case a.area < b.area:
+			l.report(a.Fence, b.Fence, LintOverlap, SeverityInfo, "within fence "+fenceName(b.Fence))
+		default:
+			l.report(a.Fence, b.Fence, LintOverlap, SeverityInfo, "contains fence "+fenceName(b.Fence))
+		}
+		return
+	}
+
+	if d := polygonDistance(a.poly, b.poly); d > 1e-6 && d < l.gapTolerance {
+		l.report(a.Fence, b.Fence, LintGap, SeverityWarning, fmt.Sprintf("gap of %.2f m with fence %s", d, fenceName(b.Fence)))
+	}
+}
+
+func (l *linter) report(f *Fence, other *Fence, check string, severity Severity, detail string) {
+	issue := LintIssue{
+		Check:     check,
+		Severity:  severity,
+		FenceID:   f.ID,
+		FenceName: f.Name,
+		Detail:    detail,
+	}
+	if other != nil {
+		id := other.ID
+		issue.OtherID = &id
+	}
+
+	l.issues = append(l.issues, issue)
+}
+
+// fenceName returns the name of a fence, or its id if it has no name.
+func fenceName(f *Fence) string {
+	if f.Name != "" {
+		return f.Name
+	}
+	return f.ID.String()
+}
+
+// zoneName returns the name of a zone, or its id if it has no name.
+func zoneName(z *Zone) string {
+	if z.Name != "" {
+		return z.Name
+	}
+	return z.ID.String()
+}
+
+// mapPolygon returns the polygon with its points mapped by fn.
+func mapPolygon(p clip.Polygon, fn func(geometry.Point) geometry.Point) clip.Polygon {
+	mapRing := func(ring []geometry.Point) []geometry.Point {
+		mapped := make([]geometry.Point, len(ring))
+		for i, p := range ring {
+			mapped[i] = fn(p)
+		}
+		return mapped
+	}
+
+	m := clip.Polygon{Exterior: mapRing(p.Exterior)}
+	for _, h := range p.Holes {
+		m.Holes = append(m.Holes, mapRing(h))
+	}
+	return m
+}
+
+// closedRings returns the rings of a polygon, closed.
+func closedRings(p clip.Polygon) [][]geometry.Point {
+	var rings [][]geometry.Point
+	for _, r := range append([][]geometry.Point{p.Exterior}, p.Holes...) {
+		if len(r) > 0 && r[0] != r[len(r)-1] {
+			r = append(r[:len(r):len(r)], r[0])
+		}
+		rings = append(rings, r)
+	}
+	return rings
+}
+
+// polygonArea returns the area of a polygon, minus its holes.
+func polygonArea(p clip.Polygon) float64 {
+	rings := closedRings(p)
+
+	area := math.Abs(signedArea(rings[0]))
+	for _, h := range rings[1:] {
+		area -= math.Abs(signedArea(h))
+	}
+	return area
+}
+
+// selfIntersectingPolygon reports whether the edges of a ring of the polygon cross each other.
+func selfIntersectingPolygon(p clip.Polygon) bool {
+	for _, r := range closedRings(p) {
+		if selfIntersecting(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// polygonDistance returns the distance between the boundaries of two polygons.
+func polygonDistance(a, b clip.Polygon) float64 {
+	best := math.Inf(1)
+	for _, ra := range closedRings(a) {
+		for _, rb := range closedRings(b) {
+			for i := 1; i < len(ra); i++ {
+				for j := 1; j < len(rb); j++ {
+					best = math.Min(best, segmentDistance(ra[i-1], ra[i], rb[j-1], rb[j]))
+				}
+			}
+		}
+	}
+	return best
+}
+
+// segmentDistance returns the distance between the segments from a to b and from c to d.
+func segmentDistance(a, b, c, d geometry.Point) float64 {
+	if segmentsCross(a, b, c, d) {
+		return 0
+	}
+
+	pointDistance := func(p, a, b geometry.Point) float64 {
+		dx, dy := b.X-a.X, b.Y-a.Y
+		t := 0.0
+		if l := dx*dx + dy*dy; l > 0 {
+			t = math.Max(0, math.Min(1, ((p.X-a.X)*dx+(p.Y-a.Y)*dy)/l))
+		}
+		return math.Hypot(p.X-a.X-t*dx, p.Y-a.Y-t*dy)
+	}
+
+	return math.Min(
+		math.Min(pointDistance(a, c, d), pointDistance(b, c, d)),
+		math.Min(pointDistance(c, a, b), pointDistance(d, a, b)),
+	)
+}
@@ -0,0 +1,102 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+//go:build !omlox_minimal
+
+package omlox
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go/clip"
+)
+
+func TestLintFences(t *testing.T) {
+	zone := Zone{ID: uuid.New(), Name: "hall", Position: NewPoint(geometry.Point{X: 7.8157, Y: 48.1302}), Radius: 50}
+
+	circle := func(name string, x, y, radius float64) Fence {
+		return Fence{ID: uuid.New(), Name: name, Region: NewRegionPoint(geometry.Point{X: x, Y: y}), Radius: radius, Crs: CrsLocal, ZoneID: zone.ID.String()}
+	}
+	wgs84 := func(name string, lon, lat, radius float64) Fence {
+		return Fence{ID: uuid.New(), Name: name, Region: NewRegionPoint(geometry.Point{X: lon, Y: lat}), Radius: radius, ZoneID: zone.ID.String()}
+	}
+	upstairs := circle("upstairs", 0, 0, 2)
+	upstairs.Floor = 1
+
+	tests := []struct {
+		name     string
+		fences   []Fence
+		check    string
+		severity Severity
+	}{
+		{name: "overlap", fences: []Fence{circle("a", 0, 0, 2), circle("b", 3, 0, 2)}, check: LintOverlap, severity: SeverityWarning},
+		{name: "nested", fences: []Fence{circle("a", 0, 0, 1), circle("b", 1, 0, 5)}, check: LintOverlap, severity: SeverityInfo},
+		{name: "gap", fences: []Fence{circle("a", 0, 0, 1), circle("b", 2.2, 0, 1)}, check: LintGap, severity: SeverityWarning},
+		{name: "apart", fences: []Fence{circle("a", 0, 0, 1), circle("b", 5, 0, 1)}},
+		{name: "different floors", fences: []Fence{circle("a", 0, 0, 2), upstairs}},
+		{name: "unknown zone", fences: []Fence{{ID: uuid.New(), Region: NewRegionPoint(geometry.Point{}), Radius: 1, Crs: CrsLocal, ZoneID: "z2"}}, check: LintOutsideZone, severity: SeverityError},
+		{name: "inside zone", fences: []Fence{wgs84("a", 7.8157, 48.1302, 10)}},
+		// about 45m east of the zone position
+		{name: "partly outside zone", fences: []Fence{wgs84("a", 7.8163, 48.1302, 10)}, check: LintOutsideZone, severity: SeverityWarning},
+		// about 300m east of the zone position
+		{name: "outside zone", fences: []Fence{wgs84("a", 7.8197, 48.1302, 10)}, check: LintOutsideZone, severity: SeverityError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			issues := LintFences(tc.fences, []Zone{zone})
+			if tc.check == "" {
+				if len(issues) > 0 {
+					t.Fatalf("expected no issues, got %+v", issues)
+				}
+				return
+			}
+
+			if len(issues) != 1 {
+				t.Fatalf("expected 1 issue, got %+v", issues)
+			}
+			if issues[0].Check != tc.check || issues[0].Severity != tc.severity {
+				t.Errorf("expected %s %s, got %s %s: %s", tc.severity, tc.check, issues[0].Severity, issues[0].Check, issues[0].Detail)
+			}
+		})
+	}
+
+	issues := LintFences([]Fence{circle("a", 0, 0, 1), circle("b", 2.2, 0, 1)}, []Zone{zone}, WithGapTolerance(0.1))
+	if len(issues) != 0 {
+		t.Errorf("expected no issues within the gap tolerance, got %+v", issues)
+	}
+}
+
+func TestSelfIntersectingPolygon(t *testing.T) {
+	bowtie := clip.Polygon{Exterior: []geometry.Point{{X: 0, Y: 0}, {X: 2, Y: 2}, {X: 2, Y: 0}, {X: 0, Y: 2}}}
+	if !selfIntersectingPolygon(bowtie) {
+		t.Error("expected bowtie to be self-intersecting")
+	}
+
+	square := clip.Polygon{
+		Exterior: []geometry.Point{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}, {X: 0, Y: 0}},
+		Holes:    [][]geometry.Point{{{X: 1, Y: 1}, {X: 1, Y: 2}, {X: 2, Y: 2}, {X: 2, Y: 1}}},
+	}
+	if selfIntersectingPolygon(square) {
+		t.Error("expected square not to be self-intersecting")
+	}
+	if a := polygonArea(square); a != 15 {
+		t.Errorf("expected area 15, got %v", a)
+	}
+}
+
+func TestSeverityFromString(t *testing.T) {
+	for _, s := range []Severity{SeverityInfo, SeverityWarning, SeverityError} {
+		var got Severity
+		if err := got.FromString(s.String()); err != nil || got != s {
+			t.Errorf("expected %v, got %v (%v)", s, got, err)
+		}
+	}
+
+	var s Severity
+	if err := s.FromString("fatal"); err == nil {
+		t.Error("expected error for unknown severity")
+	}
+}
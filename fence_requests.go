@@ -5,25 +5,58 @@ package omlox
 
 import (
 	"context"
-	"net/http"
+
+	"github.com/google/uuid"
 )
 
 // FencesAPI is a simple wrapper around the client for fences requests.
 type FencesAPI struct {
 	client *Client
+
+	service *Service[Fence]
 }
 
 // List lists all fences.
 func (c *FencesAPI) List(ctx context.Context) ([]Fence, error) {
-	requestPath := "/fences/summary"
-
-	return sendRequestParseResponseList[Fence](
-		ctx,
-		c.client,
-		http.MethodGet,
-		requestPath,
-		nil, // request body
-		nil, // request query parameters
-		nil, // request headers
-	)
+	return c.service.List(ctx)
+}
+
+// IDs lists all fence IDs.
+func (c *FencesAPI) IDs(ctx context.Context) ([]uuid.UUID, error) {
+	return listIDs[uuid.UUID](ctx, c.service)
+}
+
+// Create creates a fence.
+func (c *FencesAPI) Create(ctx context.Context, fence Fence) (*Fence, error) {
+	return c.service.Create(ctx, fence)
+}
+
+// DeleteAll deletes all fences.
+func (c *FencesAPI) DeleteAll(ctx context.Context) error {
+	return c.service.DeleteAll(ctx)
+}
+
+// Get gets a fence.
+func (c *FencesAPI) Get(ctx context.Context, id uuid.UUID) (*Fence, error) {
+	return c.service.Get(ctx, id.String())
+}
+
+// GetMany gets the fences of the given ids, in the order of the ids, sharing the
+// deadline of the context across the requests.
+func (c *FencesAPI) GetMany(ctx context.Context, ids []uuid.UUID) ([]Fence, error) {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = id.String()
+	}
+	return c.service.GetMany(ctx, keys)
+}
+
+// Delete deletes a fence.
+func (c *FencesAPI) Delete(ctx context.Context, id uuid.UUID) error {
+	return c.service.Delete(ctx, id.String())
+}
+
+// Update updates a fence.
+func (c *FencesAPI) Update(ctx context.Context, fence Fence, id uuid.UUID) error {
+	return c.service.Update(ctx, fence, id.String())
 }
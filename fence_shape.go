@@ -0,0 +1,235 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+// CircleSegments is the number of segments approximating the circles of fence shapes.
+const CircleSegments = 64
+
+// NewCircleFence returns a fence of the polygon approximating the circle of radius meters
+// around the center, in the crs of the fence. WGS84 (EPSG:4326) centers have the radius
+// converted from meters to degrees, other crs are assumed to be in meters. An empty crs is
+// WGS84, as for fences.
+//
+// Unlike point fences with a radius, the polygon is supported by all fence consumers.
+func NewCircleFence(center geometry.Point, radius float64, crs string) (*Fence, error) {
+	if radius <= 0 {
+		return nil, fmt.Errorf("circle radius must be positive")
+	}
+
+	crs = fenceCrs(crs)
+	ring := Ellipse{SemiMajor: radius, SemiMinor: radius}.Ring(center, crs, CircleSegments)
+
+	return newPolygonFence(ring, crs), nil
+}
+
+// NewRectangleFence returns a fence of the rectangle with the opposite corners a and b, in
+// the crs of the fence. The sides of the rectangle are parallel to the axes of the crs.
+// An empty crs is WGS84, as for fences.
+func NewRectangleFence(a, b geometry.Point, crs string) (*Fence, error) {
+	ring := rectangleRing(a, b)
+	if ring == nil {
+		return nil, fmt.Errorf("rectangle corners must differ in both coordinates")
+	}
+
+	return newPolygonFence(ring, fenceCrs(crs)), nil
+}
+
+// NewBufferFence returns a fence of the area within distance meters of a line, such as a
+// corridor, in the crs of the fence. The ends and outer corners of the line are rounded.
+// WGS84 (EPSG:4326) lines are converted on the plane tangent to their first point, other
+// crs are assumed to be in meters. An empty crs is WGS84, as for fences.
+//
+// An error is returned when the buffer intersects itself, such as for lines with turns
+// closer than the distance; simplify the line or reduce the distance.
+func NewBufferFence(line []geometry.Point, distance float64, crs string) (*Fence, error) {
+	crs = fenceCrs(crs)
+
+	ring, err := bufferRing(line, distance, crs)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPolygonFence(ring, crs), nil
+}
+
+// errSelfIntersecting is returned for buffers intersecting themselves.
+var errSelfIntersecting = errors.New("buffer intersects itself: simplify the line or reduce the distance")
+
+// fenceCrs returns the crs of a fence, WGS84 if empty.
+func fenceCrs(crs string) string {
+	if crs == "" {
+		return CrsWGS84
+	}
+	return crs
+}
+
+// newPolygonFence returns a fence of the ring, oriented counterclockwise as the exterior
+// rings of GeoJSON polygons.
+func newPolygonFence(ring []geometry.Point, crs string) *Fence {
+	if signedArea(ring) < 0 {
+		for i, j := 0, len(ring)-1; i < j; i, j = i+1, j-1 {
+			ring[i], ring[j] = ring[j], ring[i]
+		}
+	}
+
+	return &Fence{
+		Region: NewRegionPolygon(geometry.NewPoly(ring, nil, nil)),
+		Crs:    crs,
+	}
+}
+
+// rectangleRing returns the closed counterclockwise ring of the rectangle with the opposite
+// corners a and b, or nil if the rectangle is empty.
+func rectangleRing(a, b geometry.Point) []geometry.Point {
+	minX, maxX := math.Min(a.X, b.X), math.Max(a.X, b.X)
+	minY, maxY := math.Min(a.Y, b.Y), math.Max(a.Y, b.Y)
+	if minX == maxX || minY == maxY {
+		return nil
+	}
+
+	return []geometry.Point{{X: minX, Y: minY}, {X: maxX, Y: minY}, {X: maxX, Y: maxY}, {X: minX, Y: maxY}, {X: minX, Y: minY}}
+}
+
+// bufferRing returns the closed ring of the area within distance meters of a line.
+func bufferRing(line []geometry.Point, distance float64, crs string) ([]geometry.Point, error) {
+	if distance <= 0 {
+		return nil, fmt.Errorf("buffer distance must be positive")
+	}
+	if len(line) == 0 {
+		return nil, fmt.Errorf("buffer line must have at least two points")
+	}
+
+	// WGS84 lines are buffered on the tangent plane of their first point
+	var plane *Georeference
+	if crs == CrsWGS84 {
+		plane = &Georeference{lon: line[0].X, lat: line[0].Y}
+	}
+
+	var pts []geometry.Point
+	for _, p := range line {
+		if plane != nil {
+			p = plane.project(p)
+		}
+		if len(pts) == 0 || p != pts[len(pts)-1] {
+			pts = append(pts, p)
+		}
+	}
+	if len(pts) < 2 {
+		return nil, fmt.Errorf("buffer line must have at least two distinct points")
+	}
+
+	reversed := make([]geometry.Point, len(pts))
+	for i, p := range pts {
+		reversed[len(pts)-1-i] = p
+	}
+
+	// the left side of the line and of the reversed line, with their end caps, which
+	// end where the other side starts
+	var ring []geometry.Point
+	for _, p := range append(offsetSide(pts, distance), offsetSide(reversed, distance)...) {
+		if len(ring) == 0 || math.Hypot(p.X-ring[len(ring)-1].X, p.Y-ring[len(ring)-1].Y) > 1e-9 {
+			ring = append(ring, p)
+		}
+	}
+	ring[len(ring)-1] = ring[0]
+
+	if selfIntersecting(ring) {
+		return nil, errSelfIntersecting
+	}
+
+	if plane != nil {
+		for i, p := range ring {
+			ring[i] = plane.unproject(p)
+		}
+	}
+
+	return ring, nil
+}
+
+// offsetSide returns the points at distance d on the left of a line, from its start to its
+// end, rounding the outer corners and the end of the line.
+func offsetSide(pts []geometry.Point, d float64) []geometry.Point {
+	step := 2 * math.Pi / CircleSegments
+
+	heading := func(i int) float64 {
+		return math.Atan2(pts[i+1].Y-pts[i].Y, pts[i+1].X-pts[i].X)
+	}
+	offset := func(c geometry.Point, angle float64, r float64) geometry.Point {
+		return geometry.Point{X: c.X + r*math.Cos(angle), Y: c.Y + r*math.Sin(angle)}
+	}
+
+	var side []geometry.Point
+
+	// arc appends the points of the arc around c, clockwise from the angle from to the angle to
+	arc := func(c geometry.Point, from, to float64) {
+		n := max(1, int(math.Ceil((from-to)/step)))
+		for j := 0; j <= n; j++ {
+			side = append(side, offset(c, from-(from-to)*float64(j)/float64(n), d))
+		}
+	}
+
+	side = append(side, offset(pts[0], heading(0)+math.Pi/2, d))
+	for k := 1; k < len(pts)-1; k++ {
+		prev := heading(k - 1)
+		turn := math.Remainder(heading(k)-prev, 2*math.Pi)
+
+		if turn > 0 {
+			// inner corner, where the offset lines of both segments cross
+			side = append(side, offset(pts[k], prev+turn/2+math.Pi/2, d/math.Cos(turn/2)))
+		} else {
+			arc(pts[k], prev+math.Pi/2, prev+turn+math.Pi/2)
+		}
+	}
+
+	last := heading(len(pts) - 2)
+	arc(pts[len(pts)-1], last+math.Pi/2, last-math.Pi/2)
+
+	return side
+}
+
+// signedArea returns the area of a closed ring, positive if counterclockwise.
+func signedArea(ring []geometry.Point) float64 {
+	area := 0.0
+	for i := 1; i < len(ring); i++ {
+		area += ring[i-1].X*ring[i].Y - ring[i].X*ring[i-1].Y
+	}
+	return area / 2
+}
+
+// selfIntersecting reports whether the edges of a closed ring cross each other.
+func selfIntersecting(ring []geometry.Point) bool {
+	n := len(ring) - 1
+	for i := 0; i < n; i++ {
+		for j := i + 2; j < n; j++ {
+			if i == 0 && j == n-1 {
+				continue
+			}
+			if segmentsCross(ring[i], ring[i+1], ring[j], ring[j+1]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// segmentsCross reports whether the segments from a to b and from c to d cross each other
+// at a single point inside both.
+func segmentsCross(a, b, c, d geometry.Point) bool {
+	orient := func(p, q, r geometry.Point) float64 {
+		return (q.X-p.X)*(r.Y-p.Y) - (q.Y-p.Y)*(r.X-p.X)
+	}
+
+	d1, d2 := orient(c, d, a), orient(c, d, b)
+	d3, d4 := orient(a, b, c), orient(a, b, d)
+
+	return ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0))
+}
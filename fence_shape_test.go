@@ -0,0 +1,102 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go/internal/geo"
+)
+
+// lineDistance returns the distance from p to the closest segment of the line.
+func lineDistance(p geometry.Point, line []geometry.Point) float64 {
+	best := math.Inf(1)
+	for i := 1; i < len(line); i++ {
+		a, b := line[i-1], line[i]
+		dx, dy := b.X-a.X, b.Y-a.Y
+		t := math.Max(0, math.Min(1, ((p.X-a.X)*dx+(p.Y-a.Y)*dy)/(dx*dx+dy*dy)))
+		best = math.Min(best, math.Hypot(p.X-a.X-t*dx, p.Y-a.Y-t*dy))
+	}
+	return best
+}
+
+func TestRectangleRing(t *testing.T) {
+	ring := rectangleRing(geometry.Point{X: 4, Y: 3}, geometry.Point{X: 1, Y: 1})
+	if len(ring) != 5 || ring[0] != ring[4] || ring[0] != (geometry.Point{X: 1, Y: 1}) {
+		t.Fatalf("unexpected ring %v", ring)
+	}
+	if a := signedArea(ring); a != 6 {
+		t.Errorf("expected counterclockwise area 6, got %v", a)
+	}
+
+	if rectangleRing(geometry.Point{X: 1, Y: 1}, geometry.Point{X: 1, Y: 5}) != nil {
+		t.Error("expected no ring for an empty rectangle")
+	}
+}
+
+func TestBufferRing(t *testing.T) {
+	tests := []struct {
+		name string
+		line []geometry.Point
+	}{
+		{name: "segment", line: []geometry.Point{{X: 0, Y: 0}, {X: 10, Y: 0}}},
+		{name: "left turn", line: []geometry.Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}}},
+		{name: "right turn", line: []geometry.Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: -10}}},
+		{name: "zigzag", line: []geometry.Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 15, Y: 5}, {X: 20, Y: 5}, {X: 20, Y: 5}}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ring, err := bufferRing(tc.line, 2, CrsLocal)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if ring[0] != ring[len(ring)-1] {
+				t.Error("expected a closed ring")
+			}
+			for _, p := range ring {
+				if d := lineDistance(p, tc.line); math.Abs(d-2) > 1e-9 {
+					t.Errorf("expected vertices 2m away from the line, got %v at %v", d, p)
+				}
+			}
+		})
+	}
+}
+
+func TestBufferRingWGS84(t *testing.T) {
+	line := []geometry.Point{{X: 7.8157, Y: 48.1302}, {X: 7.8167, Y: 48.1302}}
+
+	ring, err := bufferRing(line, 5, CrsWGS84)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 5m north of the line, about 4.5e-5 degrees of latitude
+	north := 0.0
+	for _, p := range ring {
+		north = math.Max(north, p.Y-line[0].Y)
+	}
+	if math.Abs(north*geo.MetersPerDegree-5) > 1e-6 {
+		t.Errorf("expected buffer 5m north of the line, got %v degrees", north)
+	}
+}
+
+func TestBufferRingErrors(t *testing.T) {
+	if _, err := bufferRing([]geometry.Point{{X: 1, Y: 1}, {X: 1, Y: 1}}, 1, CrsLocal); err == nil {
+		t.Error("expected error for a line without length")
+	}
+	if _, err := bufferRing([]geometry.Point{{X: 0, Y: 0}, {X: 1, Y: 0}}, 0, CrsLocal); err == nil {
+		t.Error("expected error for a zero distance")
+	}
+
+	// a hairpin narrower than the buffer
+	hairpin := []geometry.Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 1}, {X: 0, Y: 1}}
+	if _, err := bufferRing(hairpin, 2, CrsLocal); !errors.Is(err, errSelfIntersecting) {
+		t.Errorf("expected self-intersecting buffer, got %v", err)
+	}
+}
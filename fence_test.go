@@ -4,7 +4,11 @@
 package omlox
 
 import (
+	"context"
 	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -68,3 +72,107 @@ func TestFenceUnmarshal(t *testing.T) {
 		})
 	}
 }
+
+func TestFenceContains(t *testing.T) {
+	fence := Fence{Region: NewRegionPoint(geometry.Point{X: 10, Y: 10}), Radius: 5, Crs: CrsLocal}
+
+	tests := []struct {
+		name string
+		loc  Location
+		want bool
+	}{
+		{name: "inside", loc: Location{Position: *NewPoint(geometry.Point{X: 12, Y: 13}), Crs: CrsLocal}, want: true},
+		{name: "outside", loc: Location{Position: *NewPoint(geometry.Point{X: 16, Y: 10}), Crs: CrsLocal}, want: false},
+		{name: "default crs", loc: Location{Position: *NewPoint(geometry.Point{X: 10, Y: 10})}, want: true},
+		{name: "other crs", loc: Location{Position: *NewPoint(geometry.Point{X: 10, Y: 10}), Crs: CrsWGS84}, want: false},
+	}
+	for _, tt := range tests {
+		if got := fence.Contains(tt.loc); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+
+	if (Fence{}).Contains(Location{}) {
+		t.Error("expected no location inside a fence without region")
+	}
+}
+
+func TestFencesAPI(t *testing.T) {
+	const id = "497f6eca-6276-4993-bfeb-53cbbbba6f08"
+	var requests []string
+
+	httpClient := &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+
+			body := `{"id":"` + id + `","radius":10,"timeout":5}`
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/v2/fences":
+				body = `["` + id + `"]`
+			case strings.HasSuffix(r.URL.Path, "/summary"):
+				body = "[" + body + "]"
+			}
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}, nil
+		}),
+	}
+
+	c, err := New("http://localhost:8081/v2", WithHTTPClient(httpClient))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	fences, err := c.Fences.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fences) != 1 || fences[0].Radius != 10 || fences[0].Timeout != NewDuration(5) {
+		t.Errorf("unexpected fences: %+v", fences)
+	}
+
+	ids, err := c.Fences.IDs(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != uuid.MustParse(id) {
+		t.Errorf("unexpected fence ids: %v", ids)
+	}
+
+	fence, err := c.Fences.Get(ctx, uuid.MustParse(id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fence.ID != uuid.MustParse(id) {
+		t.Errorf("unexpected fence id: %v", fence.ID)
+	}
+
+	if _, err := c.Fences.Create(ctx, Fence{ID: fence.ID, Radius: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Fences.Update(ctx, Fence{ID: fence.ID, Radius: 20}, fence.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Fences.Delete(ctx, fence.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Fences.DeleteAll(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"GET /v2/fences/summary",
+		"GET /v2/fences",
+		"GET /v2/fences/" + id,
+		"POST /v2/fences",
+		"PUT /v2/fences/" + id,
+		"DELETE /v2/fences/" + id,
+		"DELETE /v2/fences",
+	}
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected requests:\n%s\nwanted:\n%s", strings.Join(requests, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,288 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+//go:build !omlox_minimal
+
+package omlox
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go/clip"
+)
+
+// DefaultTileNames is the default naming template of tiled fences, such as A-01 for the
+// first cell of the first row.
+const DefaultTileNames = "{ROW}-{col:02}"
+
+// DefaultMinCoverage is the default fraction of a cell within the tiled area under which
+// the cell is skipped.
+const DefaultMinCoverage = 0.5
+
+// Tiling is the shape of the cells of the fences tiling an area.
+type Tiling int
+
+// Defines values for Tiling.
+const (
+	// TilingGrid are rectangular cells, in rows and columns.
+	TilingGrid Tiling = iota
+
+	// TilingHoneycomb are flat-topped hexagonal cells, in columns where every other
+	// column is shifted down by half a cell.
+	TilingHoneycomb
+)
+
+// Tilings returns the supported tilings.
+func Tilings() []Tiling {
+	return []Tiling{TilingGrid, TilingHoneycomb}
+}
+
+// FromString assigs itself from tiling name.
+func (t *Tiling) FromString(name string) error {
+	v, ok := map[string]Tiling{
+		TilingGrid.String():      TilingGrid,
+		TilingHoneycomb.String(): TilingHoneycomb,
+	}[name]
+
+	if !ok {
+		return fmt.Errorf("tiling %s not supported", name)
+	}
+
+	*t = v
+	return nil
+}
+
+// String return a text representation.
+func (t Tiling) String() string {
+	tilings := [...]string{
+		"grid",
+		"honeycomb",
+	}
+
+	if int(t) < 0 || len(tilings) <= int(t) {
+		return ""
+	}
+
+	return tilings[t]
+}
+
+// TileOption is a configuration option of the tiling of fences.
+type TileOption func(*tiler)
+
+// WithTileNames sets the naming template of the tiled fences. The placeholders {row}
+// and {col} are the 1-based row and column of the cell, {n} its number among the
+// fences, and {ROW} and {COL} the row and column as letters (A, B, ... Z, AA, ...).
+// Numbers are padded with zeros to the width given after a colon, as {col:02}.
+// Default: DefaultTileNames
+func WithTileNames(template string) TileOption {
+	return func(t *tiler) {
+		t.names = template
+	}
+}
+
+// WithMinCoverage sets the fraction of a cell within the tiled area under which the cell
+// is skipped, such as the slivers along the edges of the area.
+// Default: 0.5
+func WithMinCoverage(fraction float64) TileOption {
+	return func(t *tiler) {
+		t.minCoverage = fraction
+	}
+}
+
+// tiler tiles an area in cells.
+type tiler struct {
+	tiling        Tiling
+	width, height float64
+	names         string
+	minCoverage   float64
+}
+
+// tile is a cell of a tiling, clipped to the tiled area.
+type tile struct {
+	row, col int
+	poly     clip.Polygon
+}
+
+// TileFence returns the fences tiling the region of an area fence in cells of width and
+// height meters, such as to fence every storage bay of a warehouse. Honeycomb cells are
+// the hexagons inscribed in the width and height, regular when the height is the width
+// times √3/2.
+//
+// Cells start at the minimum coordinates of the area: rows go along the y axis, or
+// north, and columns along the x axis, or east. Cells along the edges of the area are
+// clipped to it, keeping their largest part, and skipped when covering less than the
+// minimum coverage. The fences are named after the naming template, have no id to be
+// created, and have the crs, zone, floor and timeouts of the area fence. WGS84 areas are
+// tiled on the plane tangent to their first point.
+func TileFence(area Fence, tiling Tiling, width, height float64, opts ...TileOption) ([]Fence, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("cell width and height must be positive")
+	}
+
+	t := &tiler{
+		tiling:      tiling,
+		width:       width,
+		height:      height,
+		names:       DefaultTileNames,
+		minCoverage: DefaultMinCoverage,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	if err := validateTileNames(t.names); err != nil {
+		return nil, err
+	}
+
+	crs := fenceCrs(area.Crs)
+	poly, err := fencePolygon(area, crs)
+	if err != nil {
+		return nil, err
+	}
+
+	// WGS84 areas are tiled on the tangent plane of their first point
+	var plane *Georeference
+	if crs == CrsWGS84 {
+		plane = &Georeference{lon: poly.Exterior[0].X, lat: poly.Exterior[0].Y}
+		poly = mapPolygon(poly, plane.project)
+	}
+
+	tiles := t.tile(poly)
+
+	fences := make([]Fence, len(tiles))
+	for i, c := range tiles {
+		if plane != nil {
+			c.poly = mapPolygon(c.poly, plane.unproject)
+		}
+
+		fences[i] = area
+		fences[i].ID = uuid.Nil
+		fences[i].Name = tileName(t.names, c.row, c.col, i+1)
+		fences[i].ForeignID = ""
+		fences[i].Properties = nil
+		fences[i].Region = NewRegionPolygon(geometry.NewPoly(c.poly.Exterior, c.poly.Holes, nil))
+		fences[i].Radius = 0
+	}
+
+	return fences, nil
+}
+
+// tile returns the cells tiling the polygon, clipped to it, by row and column.
+func (t *tiler) tile(poly clip.Polygon) []tile {
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, p := range poly.Exterior {
+		minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+		minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+	}
+
+	w, h := t.width, t.height
+	cols := int(math.Ceil((maxX - minX) / w))
+	rows := int(math.Ceil((maxY - minY) / h))
+	if t.tiling == TilingHoneycomb {
+		// columns overlap by a quarter of a cell, and start a quarter of a cell before
+		// the polygon so the slanted edges of the first column are outside of it
+		cols = int(math.Ceil((maxX-minX-w/2)/(w*3/4))) + 1
+		rows++
+	}
+
+	// cell returns the ring of the cell of a row and column
+	cell := func(row, col int) []geometry.Point {
+		if t.tiling == TilingHoneycomb {
+			cx := minX + w/4 + float64(col)*w*3/4
+			cy := minY + h/2 + float64(row)*h
+			if col%2 == 1 {
+				cy -= h / 2
+			}
+			return []geometry.Point{
+				{X: cx + w/2, Y: cy}, {X: cx + w/4, Y: cy + h/2}, {X: cx - w/4, Y: cy + h/2},
+				{X: cx - w/2, Y: cy}, {X: cx - w/4, Y: cy - h/2}, {X: cx + w/4, Y: cy - h/2},
+			}
+		}
+
+		x, y := minX+float64(col)*w, minY+float64(row)*h
+		return rectangleRing(geometry.Point{X: x, Y: y}, geometry.Point{X: x + w, Y: y + h})
+	}
+
+	var tiles []tile
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			full := clip.Polygon{Exterior: cell(row, col)}
+
+			// the largest part of the cell within the polygon
+			var (
+				part    clip.Polygon
+				covered float64
+			)
+			for _, p := range clip.Intersection([]clip.Polygon{poly}, []clip.Polygon{full}) {
+				if a := polygonArea(p); a > covered {
+					part, covered = p, a
+				}
+			}
+
+			if covered == 0 || covered < t.minCoverage*polygonArea(full) {
+				continue
+			}
+			tiles = append(tiles, tile{row: row + 1, col: col + 1, poly: part})
+		}
+	}
+
+	return tiles
+}
+
+// tilePlaceholder matches the placeholders of tile naming templates.
+var tilePlaceholder = regexp.MustCompile(`\{([^}:]*)(?::(\d+))?\}`)
+
+// validateTileNames returns an error for unknown placeholders of a naming template.
+func validateTileNames(template string) error {
+	for _, m := range tilePlaceholder.FindAllStringSubmatch(template, -1) {
+		switch m[1] {
+		case "row", "col", "n", "ROW", "COL":
+		default:
+			return fmt.Errorf("unknown placeholder %s of the tile names: expected one of {row}, {col}, {n}, {ROW} or {COL}", m[0])
+		}
+	}
+	return nil
+}
+
+// tileName returns the name of the cell of a row and column, the n-th of the fences.
+func tileName(template string, row, col, n int) string {
+	return tilePlaceholder.ReplaceAllStringFunc(template, func(s string) string {
+		m := tilePlaceholder.FindStringSubmatch(s)
+
+		var v int
+		switch strings.ToLower(m[1]) {
+		case "row":
+			v = row
+		case "col":
+			v = col
+		case "n":
+			v = n
+		}
+
+		if m[1] == "ROW" || m[1] == "COL" {
+			return columnLetters(v)
+		}
+
+		s = strconv.Itoa(v)
+		if width, _ := strconv.Atoi(m[2]); len(s) < width {
+			s = strings.Repeat("0", width-len(s)) + s
+		}
+		return s
+	})
+}
+
+// columnLetters returns the letters of a 1-based number, as spreadsheet columns: A, B,
+// ... Z, AA, AB, ...
+func columnLetters(n int) string {
+	var letters []byte
+	for ; n > 0; n = (n - 1) / 26 {
+		letters = append([]byte{byte('A' + (n-1)%26)}, letters...)
+	}
+	return string(letters)
+}
@@ -0,0 +1,107 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+//go:build !omlox_minimal
+
+package omlox
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go/clip"
+)
+
+func TestTile(t *testing.T) {
+	rect := clip.Polygon{Exterior: rectangleRing(geometry.Point{X: 0, Y: 0}, geometry.Point{X: 10, Y: 4})}
+
+	tests := []struct {
+		name          string
+		tiling        Tiling
+		width, height float64
+		minCoverage   float64
+		tiles         int
+	}{
+		{name: "grid", tiling: TilingGrid, width: 2, height: 2, minCoverage: 0.5, tiles: 10},
+		// the last column and row cover a third of a cell
+		{name: "grid with slivers", tiling: TilingGrid, width: 3, height: 3, minCoverage: 0.5, tiles: 3},
+		{name: "grid with all slivers", tiling: TilingGrid, width: 3, height: 3, tiles: 8},
+		{name: "honeycomb", tiling: TilingHoneycomb, width: 2, height: math.Sqrt(3), tiles: 21},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := &tiler{tiling: tc.tiling, width: tc.width, height: tc.height, minCoverage: tc.minCoverage}
+			tiles := tr.tile(rect)
+
+			if len(tiles) != tc.tiles {
+				t.Fatalf("expected %d tiles, got %d", tc.tiles, len(tiles))
+			}
+
+			// without minimum coverage, the cells cover the whole area
+			if tc.minCoverage == 0 {
+				total := 0.0
+				for _, c := range tiles {
+					total += polygonArea(c.poly)
+				}
+				if math.Abs(total-40) > 1e-9 {
+					t.Errorf("expected tiles covering the area, got %v", total)
+				}
+			}
+		})
+	}
+
+	tiles := (&tiler{tiling: TilingGrid, width: 2, height: 2}).tile(rect)
+	if last := tiles[len(tiles)-1]; last.row != 2 || last.col != 5 {
+		t.Errorf("expected the last tile in row 2 and column 5, got %d, %d", last.row, last.col)
+	}
+}
+
+func TestTileName(t *testing.T) {
+	tests := []struct {
+		template    string
+		row, col, n int
+		expected    string
+	}{
+		{template: DefaultTileNames, row: 1, col: 1, n: 1, expected: "A-01"},
+		{template: DefaultTileNames, row: 28, col: 12, n: 100, expected: "AB-12"},
+		{template: "bay-{n:03}", row: 2, col: 3, n: 7, expected: "bay-007"},
+		{template: "{COL}{row}", row: 4, col: 3, n: 12, expected: "C4"},
+	}
+
+	for _, tc := range tests {
+		if name := tileName(tc.template, tc.row, tc.col, tc.n); name != tc.expected {
+			t.Errorf("expected %s, got %s", tc.expected, name)
+		}
+	}
+
+	if err := validateTileNames("{aisle}-{col}"); err == nil {
+		t.Error("expected error for unknown placeholder")
+	}
+}
+
+func TestTileFence(t *testing.T) {
+	area := Fence{ID: uuid.New(), Name: "storage", Region: NewRegionPoint(geometry.Point{X: 0, Y: 0}), Radius: 5, Crs: CrsLocal, ZoneID: "z1", Floor: 2}
+
+	fences, err := TileFence(area, TilingGrid, 2, 2, WithTileNames("bay-{n}"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fences) == 0 {
+		t.Fatal("expected fences")
+	}
+	for i, f := range fences {
+		if f.ID != uuid.Nil || f.ZoneID != "z1" || f.Floor != 2 || f.Radius != 0 {
+			t.Errorf("unexpected fence %+v", f)
+		}
+		if expected := tileName("bay-{n}", 0, 0, i+1); f.Name != expected {
+			t.Errorf("expected name %s, got %s", expected, f.Name)
+		}
+	}
+
+	if _, err := TileFence(area, TilingGrid, 0, 2); err == nil {
+		t.Error("expected error for empty cells")
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+//go:build !omlox_minimal
+
+package omlox
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+// floorplanProperty is the zone property holding the floorplan overlay of the zone.
+const floorplanProperty = "floorplan"
+
+// Floorplan is the image of the floorplan of a zone, georeferenced to the local zone
+// coordinates so user interfaces can overlay it under the positions of the zone.
+type Floorplan struct {
+	// The location of the image, such as a URL.
+	Image string `json:"image,omitempty"`
+
+	// The width and height of the image in pixels.
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
+
+	// The transformation of the pixel coordinates of the image, from its top-left corner
+	// with y pointing down, to local zone coordinates in meters. As a CSS transform of the
+	// image, in zone coordinates, it is matrix(a, d, b, e, c, f).
+	Transform Affine `json:"transform"`
+
+	// The control points the transformation is computed from.
+	ControlPoints []FloorplanControlPoint `json:"control_points,omitempty"`
+}
+
+// FloorplanControlPoint is a point known both in the pixel coordinates of a floorplan
+// image and in local zone coordinates, such as a building corner or a surveyed anchor.
+type FloorplanControlPoint struct {
+	// The pixel coordinates of the point, as [x, y] from the top-left corner of the image.
+	Pixel [2]float64 `json:"pixel"`
+
+	// The local zone coordinates of the point in meters, as [x, y].
+	Local [2]float64 `json:"local"`
+}
+
+// NewFloorplan returns the floorplan of an image of width and height pixels, from at least
+// two control points. Two control points define a similarity, keeping the aspect of the
+// image, while three or more define an affine transformation, correcting the skew of
+// scanned floorplans.
+func NewFloorplan(image string, width, height int, points []FloorplanControlPoint) (*Floorplan, error) {
+	// pixel coordinates are flipped to point up, as zone coordinates, so the similarity
+	// of two control points does not mirror the image
+	src := make([]geometry.Point, len(points))
+	dst := make([]geometry.Point, len(points))
+	for i, p := range points {
+		src[i] = geometry.Point{X: p.Pixel[0], Y: -p.Pixel[1]}
+		dst[i] = geometry.Point{X: p.Local[0], Y: p.Local[1]}
+	}
+
+	t, err := FitAffine(src, dst)
+	if err != nil {
+		return nil, fmt.Errorf("invalid floorplan control points: %w", err)
+	}
+
+	return &Floorplan{
+		Image:         image,
+		Width:         width,
+		Height:        height,
+		Transform:     Affine{t[0], -t[1], t[2], t[3], -t[4], t[5]},
+		ControlPoints: points,
+	}, nil
+}
+
+// ToLocal converts pixel coordinates of the image to local zone coordinates.
+func (f Floorplan) ToLocal(pixel geometry.Point) geometry.Point {
+	return f.Transform.Apply(pixel)
+}
+
+// ToPixel converts local zone coordinates to pixel coordinates of the image.
+func (f Floorplan) ToPixel(local geometry.Point) (geometry.Point, error) {
+	inv, err := f.Transform.Invert()
+	if err != nil {
+		return geometry.Point{}, err
+	}
+	return inv.Apply(local), nil
+}
+
+// Corners returns the local zone coordinates of the corners of the image: top-left,
+// top-right, bottom-right and bottom-left, as map libraries expect image overlays. They
+// are converted to WGS84 with the georeference of the zone.
+func (f Floorplan) Corners() [4]geometry.Point {
+	w, h := float64(f.Width), float64(f.Height)
+	return [4]geometry.Point{
+		f.ToLocal(geometry.Point{X: 0, Y: 0}),
+		f.ToLocal(geometry.Point{X: w, Y: 0}),
+		f.ToLocal(geometry.Point{X: w, Y: h}),
+		f.ToLocal(geometry.Point{X: 0, Y: h}),
+	}
+}
+
+// Zone returns the zone with the floorplan in its properties, updating the given zone.
+// Other properties of the zone are preserved.
+func (f Floorplan) Zone(base Zone) (Zone, error) {
+	props := make(map[string]json.RawMessage)
+	if len(base.Properties) != 0 {
+		if err := json.Unmarshal(base.Properties, &props); err != nil {
+			return base, fmt.Errorf("could not decode properties of zone %s: %w", base.ID, err)
+		}
+	}
+
+	floorplan, err := json.Marshal(f)
+	if err != nil {
+		return base, err
+	}
+	props[floorplanProperty] = floorplan
+
+	base.Properties, err = json.Marshal(props)
+	if err != nil {
+		return base, err
+	}
+
+	return base, nil
+}
+
+// FloorplanOf returns the floorplan of a zone.
+// It reports whether the zone has a floorplan.
+func FloorplanOf(z Zone) (*Floorplan, bool) {
+	var props struct {
+		Floorplan *Floorplan `json:"floorplan"`
+	}
+	if len(z.Properties) == 0 || json.Unmarshal(z.Properties, &props) != nil || props.Floorplan == nil {
+		return nil, false
+	}
+
+	return props.Floorplan, true
+}
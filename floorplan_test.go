@@ -0,0 +1,94 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+//go:build !omlox_minimal
+
+package omlox
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+func TestNewFloorplan(t *testing.T) {
+	// a 1000x500 pixels image of a 50x25m hall, at 20 pixels per meter, with the zone
+	// origin at the bottom-left corner of the image
+	tests := []struct {
+		name   string
+		points []FloorplanControlPoint
+	}{
+		{
+			name: "similarity",
+			points: []FloorplanControlPoint{
+				{Pixel: [2]float64{0, 500}, Local: [2]float64{0, 0}},
+				{Pixel: [2]float64{1000, 500}, Local: [2]float64{50, 0}},
+			},
+		},
+		{
+			name: "affine",
+			points: []FloorplanControlPoint{
+				{Pixel: [2]float64{0, 500}, Local: [2]float64{0, 0}},
+				{Pixel: [2]float64{1000, 500}, Local: [2]float64{50, 0}},
+				{Pixel: [2]float64{0, 0}, Local: [2]float64{0, 25}},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := NewFloorplan("https://example.com/hall.png", 1000, 500, tc.points)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			want := [4]geometry.Point{{X: 0, Y: 25}, {X: 50, Y: 25}, {X: 50, Y: 0}, {X: 0, Y: 0}}
+			for i, c := range f.Corners() {
+				if math.Abs(c.X-want[i].X) > 1e-9 || math.Abs(c.Y-want[i].Y) > 1e-9 {
+					t.Errorf("expected corners %v, got %v", want, f.Corners())
+					break
+				}
+			}
+
+			p, err := f.ToPixel(geometry.Point{X: 10, Y: 5})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if math.Abs(p.X-200) > 1e-9 || math.Abs(p.Y-400) > 1e-9 {
+				t.Errorf("expected pixel (200, 400), got %v", p)
+			}
+		})
+	}
+
+	if _, err := NewFloorplan("hall.png", 1000, 500, nil); err == nil {
+		t.Error("expected error without control points")
+	}
+}
+
+func TestFloorplanZone(t *testing.T) {
+	f := Floorplan{Image: "hall.png", Width: 1000, Height: 500, Transform: Affine{0.05, 0, 0, 0, -0.05, 25}}
+
+	base := Zone{Name: "hall", Properties: json.RawMessage(`{"org.wavecom.whereis":{"eid":"H1"}}`)}
+	z, err := f.Zone(base)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := `{"floorplan":{"image":"hall.png","width":1000,"height":500,"transform":[0.05,0,0,0,-0.05,25]},"org.wavecom.whereis":{"eid":"H1"}}`; string(z.Properties) != want {
+		t.Errorf("expected properties %s, got %s", want, z.Properties)
+	}
+
+	got, ok := FloorplanOf(z)
+	if !ok {
+		t.Fatal("expected zone to have a floorplan")
+	}
+	if got.Image != f.Image || got.Transform != f.Transform {
+		t.Errorf("unexpected floorplan: %+v", got)
+	}
+
+	if _, ok := FloorplanOf(base); ok {
+		t.Error("expected zone without floorplan property not to have a floorplan")
+	}
+}
@@ -0,0 +1,321 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package forward reliably forwards omlox™ subscription events to a sink, such as
+// a message broker or a database.
+//
+// Events are persisted to a write-ahead log before being delivered, and delivered
+// again with backoff until the sink accepts them, so no event is lost when the sink
+// is unavailable or the process restarts. Events of the same trackable are delivered
+// in order, one at a time, while events of different trackables are delivered
+// concurrently.
+package forward
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+// Defaults of the forwarder configuration.
+const (
+	DefaultConcurrency = 16
+	DefaultMinBackoff  = 100 * time.Millisecond
+	DefaultMaxBackoff  = 30 * time.Second
+)
+
+// Event is an event payload of a subscription message.
+type Event struct {
+	// Seq is the sequence number of the event in the WAL.
+	Seq uint64 `json:"seq"`
+
+	// Topic is the topic of the subscription message.
+	Topic omlox.Topic `json:"topic"`
+
+	// Key is the ordering key of the event: the id of its trackable, or of its
+	// location provider for events without trackables.
+	Key string `json:"key,omitempty"`
+
+	// Payload is the event payload.
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Sink receives the forwarded events.
+type Sink interface {
+	// Deliver delivers an event. Events failing to be delivered are delivered again.
+	Deliver(ctx context.Context, e Event) error
+}
+
+// SinkFunc is an adapter to use ordinary functions as sinks.
+type SinkFunc func(ctx context.Context, e Event) error
+
+// Deliver implements Sink.
+func (f SinkFunc) Deliver(ctx context.Context, e Event) error {
+	return f(ctx, e)
+}
+
+// Option is a configuration option of a forwarder.
+type Option func(*Forwarder)
+
+// WithConcurrency sets the maximum number of events delivered at the same time.
+func WithConcurrency(n int) Option {
+	return func(f *Forwarder) {
+		f.concurrency = n
+	}
+}
+
+// WithBackoff sets the bounds of the exponential backoff between deliveries of an event.
+func WithBackoff(min, max time.Duration) Option {
+	return func(f *Forwarder) {
+		f.minBackoff = min
+		f.maxBackoff = max
+	}
+}
+
+// WithMaxAttempts sets the number of deliveries of an event before it is given up
+// and passed to the dead letter hook. Zero, the default, retries forever.
+func WithMaxAttempts(n int) Option {
+	return func(f *Forwarder) {
+		f.maxAttempts = n
+	}
+}
+
+// OnDeadLetter registers a hook called with the events given up after the maximum
+// attempts, and the error of their last delivery.
+func OnDeadLetter(hook func(Event, error)) Option {
+	return func(f *Forwarder) {
+		f.deadLetter = hook
+	}
+}
+
+// WithClock sets the source of time of the delivery backoffs.
+func WithClock(clk clock.Clock) Option {
+	return func(f *Forwarder) {
+		f.clock = clk
+	}
+}
+
+// Forwarder forwards subscription events to a sink through a WAL.
+type Forwarder struct {
+	wal  *WAL
+	sink Sink
+
+	concurrency int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	maxAttempts int
+	deadLetter  func(Event, error)
+	clock       clock.Clock
+
+	sem chan struct{}
+	wg  sync.WaitGroup
+
+	// queues of the events by ordering key, while being delivered
+	mu     sync.Mutex
+	queues map[string][]Event
+}
+
+// New returns a new forwarder of events to the sink through the WAL.
+func New(wal *WAL, sink Sink, opts ...Option) *Forwarder {
+	f := &Forwarder{
+		wal:         wal,
+		sink:        sink,
+		concurrency: DefaultConcurrency,
+		minBackoff:  DefaultMinBackoff,
+		maxBackoff:  DefaultMaxBackoff,
+	}
+
+	for _, opt := range opts {
+		opt(f)
+	}
+	f.clock = clock.Or(f.clock)
+
+	return f
+}
+
+// Run forwards the events of the source, such as Subcription.ReceiveRaw, until the
+// source is closed and all its events are delivered, or the context is done. Events
+// not delivered by a previous run are forwarded first.
+//
+// Run returns the first error persisting the events. Events not delivered when Run
+// returns are kept in the WAL for the next run.
+func (f *Forwarder) Run(ctx context.Context, source <-chan *omlox.WrapperObject) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	f.sem = make(chan struct{}, max(f.concurrency, 1))
+	f.queues = make(map[string][]Event)
+
+	for _, e := range f.wal.Pending() {
+		f.enqueue(ctx, cancel, e)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			f.wg.Wait()
+			return context.Cause(ctx)
+		case msg, ok := <-source:
+			if !ok {
+				f.wg.Wait()
+				return context.Cause(ctx)
+			}
+
+			events := make([]Event, 0, len(msg.Payload))
+			for _, p := range msg.Payload {
+				events = append(events, Event{Topic: msg.Topic, Key: orderingKey(msg.Topic, p), Payload: p})
+			}
+
+			if err := f.wal.Append(events); err != nil {
+				cancel(err)
+				f.wg.Wait()
+				return err
+			}
+
+			for _, e := range events {
+				f.enqueue(ctx, cancel, e)
+			}
+		}
+	}
+}
+
+// enqueue queues an event after the events of the same key, starting the delivery
+// of the queue if it is not already being delivered.
+func (f *Forwarder) enqueue(ctx context.Context, fail context.CancelCauseFunc, e Event) {
+	f.mu.Lock()
+	q, delivering := f.queues[e.Key]
+	f.queues[e.Key] = append(q, e)
+	f.mu.Unlock()
+
+	if !delivering {
+		f.wg.Add(1)
+		go f.drain(ctx, fail, e.Key)
+	}
+}
+
+// drain delivers the events of a key, in order, until its queue is empty.
+func (f *Forwarder) drain(ctx context.Context, fail context.CancelCauseFunc, key string) {
+	defer f.wg.Done()
+
+	for {
+		f.mu.Lock()
+		q := f.queues[key]
+		if len(q) == 0 {
+			delete(f.queues, key)
+			f.mu.Unlock()
+			return
+		}
+		e := q[0]
+		f.mu.Unlock()
+
+		if err := f.deliver(ctx, e); err != nil {
+			if ctx.Err() == nil {
+				fail(err)
+			}
+			return
+		}
+
+		f.mu.Lock()
+		f.queues[key] = f.queues[key][1:]
+		f.mu.Unlock()
+	}
+}
+
+// deliver delivers an event until it is accepted by the sink or given up, and
+// acknowledges it. It only fails if the context is done or the WAL fails.
+func (f *Forwarder) deliver(ctx context.Context, e Event) error {
+	for attempt := 1; ; attempt++ {
+		select {
+		case f.sem <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		err := f.sink.Deliver(ctx, e)
+		<-f.sem
+
+		if err == nil {
+			return f.wal.Ack(e.Seq)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if f.maxAttempts > 0 && attempt >= f.maxAttempts {
+			if f.deadLetter != nil {
+				f.deadLetter(e, err)
+			}
+			return f.wal.Ack(e.Seq)
+		}
+
+		t := f.clock.NewTimer(backoff(f.minBackoff, f.maxBackoff, attempt-1))
+		select {
+		case <-t.C():
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// payloadReferences are the identifiers referenced by the event payloads.
+// Trackable motions are identified by their trackable id.
+type payloadReferences struct {
+	ID          *uuid.UUID  `json:"id"`
+	ProviderID  string      `json:"provider_id"`
+	TrackableID *uuid.UUID  `json:"trackable_id"`
+	Trackables  []uuid.UUID `json:"trackables"`
+	Location    *struct {
+		ProviderID string      `json:"provider_id"`
+		Trackables []uuid.UUID `json:"trackables"`
+	} `json:"location"`
+}
+
+// orderingKey returns the ordering key of an event payload: its smallest trackable
+// id, so events of several trackables have a single key, or its location provider id.
+func orderingKey(topic omlox.Topic, payload json.RawMessage) string {
+	var refs payloadReferences
+	if err := json.Unmarshal(payload, &refs); err != nil {
+		return ""
+	}
+
+	trackables := refs.Trackables
+	if refs.TrackableID != nil {
+		trackables = append(trackables, *refs.TrackableID)
+	}
+	if refs.ID != nil && topic == omlox.TopicTrackableMotions {
+		trackables = append(trackables, *refs.ID)
+	}
+	if refs.Location != nil {
+		trackables = append(trackables, refs.Location.Trackables...)
+	}
+
+	if len(trackables) > 0 {
+		return slices.MinFunc(trackables, func(a, b uuid.UUID) int { return slices.Compare(a[:], b[:]) }).String()
+	}
+
+	if refs.ProviderID == "" && refs.Location != nil {
+		return refs.Location.ProviderID
+	}
+	return refs.ProviderID
+}
+
+// backoff calculates the time to wait based on the number of attempts with
+// exponential backoff strategy and full jitter.
+func backoff(min, max time.Duration, attempt int) time.Duration {
+	d := min * (1 << uint(attempt))
+	if d > max || d <= 0 {
+		d = max
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(d)))
+}
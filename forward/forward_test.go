@@ -0,0 +1,365 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package forward
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/archive"
+)
+
+const (
+	trackableA = "0f5b5a5a-3a8f-4c55-9e1c-6b1d1f1b0a01"
+	trackableB = "0f5b5a5a-3a8f-4c55-9e1c-6b1d1f1b0a02"
+)
+
+func message(payloads ...string) *omlox.WrapperObject {
+	msg := &omlox.WrapperObject{Event: omlox.EventMsg, Topic: omlox.TopicFenceEvents}
+	for _, p := range payloads {
+		msg.Payload = append(msg.Payload, json.RawMessage(p))
+	}
+	return msg
+}
+
+func TestWAL(t *testing.T) {
+	dir := t.TempDir()
+
+	w, err := OpenWAL(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	w.segmentSize = 1 // a segment per append
+
+	for _, key := range []string{"a", "b", "c"} {
+		if err := w.Append([]Event{{Key: key, Payload: json.RawMessage(`{}`)}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	for _, seq := range []uint64{1, 3} {
+		if err := w.Ack(seq); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the first segment is fully acknowledged
+	if _, err := os.Stat(filepath.Join(dir, "00000000000000000001.wal")); !os.IsNotExist(err) {
+		t.Errorf("expected compacted segment, got %v", err)
+	}
+
+	// a torn record of a crash is ignored
+	f, err := os.OpenFile(filepath.Join(dir, "00000000000000000003.wal"), os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f.WriteString(`{"seq":4,"key":"d","pay`)
+	f.Close()
+
+	w, err = OpenWAL(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Close()
+
+	pending := w.Pending()
+	if len(pending) != 1 || pending[0].Seq != 2 || pending[0].Key != "b" {
+		t.Fatalf("unexpected pending events %+v", pending)
+	}
+
+	events := []Event{{Key: "e"}}
+	if err := w.Append(events); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if events[0].Seq != 4 {
+		t.Errorf("expected sequence number 4, got %d", events[0].Seq)
+	}
+}
+
+func TestWALAckBatches(t *testing.T) {
+	w, err := OpenWAL(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Close()
+
+	events := make([]Event, 100)
+	if err := w.Append(events); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// acknowledged events are dropped in batches, and acknowledgments are idempotent
+	for seq := uint64(1); seq <= 60; seq++ {
+		for i := 0; i < 2; i++ {
+			if err := w.Ack(seq); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+	if err := w.Ack(100); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n := len(w.pending); n >= 100 {
+		t.Errorf("expected acknowledged events to be dropped, got %d events", n)
+	}
+	pending := w.Pending()
+	if len(pending) != 39 || pending[0].Seq != 61 || pending[38].Seq != 99 {
+		t.Errorf("unexpected pending events: %d from %d", len(pending), pending[0].Seq)
+	}
+}
+
+func TestForwarder(t *testing.T) {
+	dir := t.TempDir()
+
+	var (
+		mu        sync.Mutex
+		delivered = make(map[string][]string)
+		failures  = 2
+	)
+	sink := SinkFunc(func(ctx context.Context, e Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// the first event of trackable A fails a few times before being accepted
+		if e.Key == trackableA && failures > 0 {
+			failures--
+			return errors.New("sink unavailable")
+		}
+
+		delivered[e.Key] = append(delivered[e.Key], string(e.Payload))
+		return nil
+	})
+
+	w, err := OpenWAL(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Close()
+
+	source := make(chan *omlox.WrapperObject, 3)
+	source <- message(`{"trackable_id":"`+trackableA+`","n":1}`, `{"trackable_id":"`+trackableB+`","n":1}`)
+	source <- message(`{"trackable_id":"` + trackableA + `","n":2}`)
+	source <- message(`{"provider_id":"tag","n":1}`)
+	close(source)
+
+	f := New(w, sink, WithBackoff(time.Millisecond, time.Millisecond))
+	if err := f.Run(context.Background(), source); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string][]string{
+		trackableA: {`{"trackable_id":"` + trackableA + `","n":1}`, `{"trackable_id":"` + trackableA + `","n":2}`},
+		trackableB: {`{"trackable_id":"` + trackableB + `","n":1}`},
+		"tag":      {`{"provider_id":"tag","n":1}`},
+	}
+	for key, events := range want {
+		if len(delivered[key]) != len(events) {
+			t.Fatalf("expected %v delivered for %s, got %v", events, key, delivered[key])
+		}
+		for i := range events {
+			if delivered[key][i] != events[i] {
+				t.Errorf("expected %v delivered for %s, got %v", events, key, delivered[key])
+			}
+		}
+	}
+}
+
+func TestForwarderRestart(t *testing.T) {
+	dir := t.TempDir()
+
+	w, err := OpenWAL(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the sink is down: the events stay in the WAL
+	ctx, cancel := context.WithCancel(context.Background())
+	down := SinkFunc(func(ctx context.Context, e Event) error {
+		cancel()
+		return errors.New("sink unavailable")
+	})
+
+	source := make(chan *omlox.WrapperObject, 1)
+	source <- message(`{"trackable_id":"`+trackableA+`"}`, `{"trackable_id":"`+trackableB+`"}`)
+
+	if err := New(w, down).Run(ctx, source); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled, got %v", err)
+	}
+	w.Close()
+
+	w, err = OpenWAL(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Close()
+
+	var delivered []uint64
+	up := SinkFunc(func(ctx context.Context, e Event) error {
+		delivered = append(delivered, e.Seq)
+		return nil
+	})
+
+	f := New(w, up, WithConcurrency(1))
+	empty := make(chan *omlox.WrapperObject)
+	close(empty)
+	if err := f.Run(context.Background(), empty); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(delivered) != 2 {
+		t.Errorf("expected the 2 events of the previous run, got %v", delivered)
+	}
+}
+
+func TestDeadLetter(t *testing.T) {
+	w, err := OpenWAL(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Close()
+
+	var dead []Event
+	f := New(w,
+		SinkFunc(func(ctx context.Context, e Event) error { return errors.New("rejected") }),
+		WithMaxAttempts(2),
+		WithBackoff(time.Millisecond, time.Millisecond),
+		OnDeadLetter(func(e Event, err error) { dead = append(dead, e) }),
+	)
+
+	source := make(chan *omlox.WrapperObject, 1)
+	source <- message(`{"trackable_id":"` + trackableA + `"}`)
+	close(source)
+
+	if err := f.Run(context.Background(), source); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(dead) != 1 || dead[0].Key != trackableA {
+		t.Errorf("unexpected dead letters %+v", dead)
+	}
+}
+
+func TestWALCompression(t *testing.T) {
+	dir := t.TempDir()
+
+	w, err := OpenWAL(dir, WithSegmentSize(1), WithCompression(archive.Zstd))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, key := range []string{"a", "b"} {
+		if err := w.Append([]Event{{Key: key, Payload: json.RawMessage(`{}`)}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	w.Close()
+
+	// the first segment was compressed when the second one started
+	if _, err := os.Stat(filepath.Join(dir, "00000000000000000001.wal.zst")); err != nil {
+		t.Fatalf("expected compressed segment: %v", err)
+	}
+
+	w, err = OpenWAL(dir, WithSegmentSize(1), WithCompression(archive.Zstd), WithMaxSize(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Close()
+
+	if pending := w.Pending(); len(pending) != 2 || pending[0].Key != "a" {
+		t.Fatalf("unexpected pending events %+v", pending)
+	}
+
+	// segments above the maximum size are deleted, even with events pending
+	if err := w.Append([]Event{{Key: "c"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("expected only the active segment, got %d files", len(entries))
+	}
+}
+
+func TestForwarderCancel(t *testing.T) {
+	w, err := OpenWAL(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Close()
+
+	var (
+		mu        sync.Mutex
+		delivered = make(map[uint64]int)
+	)
+	sink := SinkFunc(func(ctx context.Context, e Event) error {
+		select {
+		case <-time.After(time.Duration(e.Seq%3) * 100 * time.Microsecond):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		delivered[e.Seq]++
+		return nil
+	})
+	f := New(w, sink, WithConcurrency(4), WithBackoff(time.Millisecond, time.Millisecond))
+	goroutines := runtime.NumGoroutine()
+
+	// runs are canceled while events are received and delivered
+	for run := 0; run < 20; run++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(run%4)*time.Millisecond)
+
+		source := make(chan *omlox.WrapperObject)
+		go func() {
+			defer close(source)
+			for i := 0; i < 10; i++ {
+				msg := message(fmt.Sprintf(`{"trackable_id":"%s","n":%d}`, trackableA, i), fmt.Sprintf(`{"trackable_id":"%s","n":%d}`, trackableB, i))
+				select {
+				case source <- msg:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		if err := f.Run(ctx, source); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		cancel()
+	}
+
+	// the events of the canceled runs are delivered by the next one
+	empty := make(chan *omlox.WrapperObject)
+	close(empty)
+	if err := f.Run(context.Background(), empty); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pending := w.Pending(); len(pending) != 0 {
+		t.Fatalf("expected all events delivered, got %d pending", len(pending))
+	}
+
+	mu.Lock()
+	for seq := uint64(1); seq <= w.next; seq++ {
+		if delivered[seq] == 0 {
+			t.Errorf("event %d was not delivered", seq)
+		}
+	}
+	mu.Unlock()
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > goroutines && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > goroutines {
+		t.Errorf("expected %d goroutines after the runs, got %d", goroutines, n)
+	}
+}
@@ -0,0 +1,395 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package forward
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wavecomtech/omlox-client-go/archive"
+)
+
+// DefaultSegmentSize is the size after which the WAL starts a new segment file.
+const DefaultSegmentSize = 16 << 20
+
+// record is a line of a WAL segment: either an event or the acknowledgment of one.
+type record struct {
+	Ack uint64 `json:"ack,omitempty"`
+	*Event
+}
+
+// segment is a file of the WAL, holding the events from its first sequence number.
+type segment struct {
+	first   uint64
+	path    string
+	size    int64
+	pending int
+}
+
+// WALOption is a configuration option of a WAL.
+type WALOption func(*WAL)
+
+// WithSegmentSize sets the size, in bytes, after which the WAL starts a new segment.
+// Default: DefaultSegmentSize
+func WithSegmentSize(n int64) WALOption {
+	return func(w *WAL) {
+		w.segmentSize = n
+	}
+}
+
+// WithSegmentAge sets the age after which the WAL starts a new segment, on the next
+// append. Zero does not rotate the segments by age.
+func WithSegmentAge(d time.Duration) WALOption {
+	return func(w *WAL) {
+		w.segmentAge = d
+	}
+}
+
+// WithCompression compresses the segments once the WAL starts a new one, as they
+// are only read again after a restart.
+// Default: archive.None
+func WithCompression(c archive.Compression) WALOption {
+	return func(w *WAL) {
+		w.compression = c
+	}
+}
+
+// WithMaxSize sets the total size, in bytes, of the segments above which the oldest
+// ones are deleted, even if not all their events are acknowledged, so an unavailable
+// sink does not fill the disk. The events of deleted segments are not forwarded
+// after a restart. Zero, the default, does not limit the size.
+func WithMaxSize(n int64) WALOption {
+	return func(w *WAL) {
+		w.maxSize = n
+	}
+}
+
+// WAL is a write-ahead log of the events to forward, keeping them across restarts
+// until they are acknowledged. It is a directory of append-only segment files of
+// JSON lines, deleted once all their events are acknowledged.
+// It is safe for concurrent use.
+type WAL struct {
+	dir         string
+	segmentSize int64
+	segmentAge  time.Duration
+	compression archive.Compression
+	maxSize     int64
+
+	mu       sync.Mutex
+	f        *os.File
+	size     int64
+	opened   time.Time
+	segments []*segment
+	next     uint64
+	// events by sequence number, acknowledged ones being dropped in batches
+	pending []walEvent
+	acked   int
+}
+
+// walEvent is an event of the WAL, kept until a batch of acknowledged events is dropped.
+type walEvent struct {
+	Event
+	acked bool
+}
+
+// OpenWAL opens the WAL of a directory, creating it if needed.
+// The events not acknowledged by a previous run are available with Pending.
+func OpenWAL(dir string, opts ...WALOption) (*WAL, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	w := &WAL{dir: dir, segmentSize: DefaultSegmentSize, compression: archive.None}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	if err := w.replay(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Pending returns the events of the previous runs, of this process or of previous ones,
+// not acknowledged yet, by sequence number.
+func (w *WAL) Pending() []Event {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	events := make([]Event, 0, len(w.pending)-w.acked)
+	for _, e := range w.pending {
+		if !e.acked {
+			events = append(events, e.Event)
+		}
+	}
+	return events
+}
+
+// Append assigns sequence numbers to the events and persists them.
+func (w *WAL) Append(events []Event) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	if w.f == nil || w.size >= w.segmentSize || (w.segmentAge > 0 && time.Since(w.opened) >= w.segmentAge) {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+
+	var buf []byte
+	for i := range events {
+		w.next++
+		events[i].Seq = w.next
+
+		data, err := json.Marshal(record{Event: &events[i]})
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, data...), '\n')
+	}
+
+	if err := w.write(buf); err != nil {
+		return err
+	}
+	w.segments[len(w.segments)-1].pending += len(events)
+
+	// events of runs canceled before their delivery are forwarded by the next run
+	for _, e := range events {
+		w.pending = append(w.pending, walEvent{Event: e})
+	}
+
+	return nil
+}
+
+// Ack acknowledges an event, so it is not forwarded again after a restart.
+func (w *WAL) Ack(seq uint64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+
+	if err := w.write([]byte(`{"ack":` + strconv.FormatUint(seq, 10) + "}\n")); err != nil {
+		return err
+	}
+
+	if i, ok := slices.BinarySearchFunc(w.pending, seq, func(e walEvent, seq uint64) int { return cmp.Compare(e.Seq, seq) }); ok && !w.pending[i].acked {
+		w.pending[i].acked = true
+		w.acked++
+
+		// acknowledged events are dropped once they are the majority, so that acks
+		// take constant amortized time instead of shifting the pending events
+		if w.acked > len(w.pending)/2 {
+			w.pending = slices.DeleteFunc(w.pending, func(e walEvent) bool { return e.acked })
+			w.acked = 0
+		}
+	}
+
+	i := sort.Search(len(w.segments), func(i int) bool { return w.segments[i].first > seq }) - 1
+	if i >= 0 && w.segments[i].pending > 0 {
+		w.segments[i].pending--
+	}
+
+	return w.compact()
+}
+
+// Close closes the WAL.
+func (w *WAL) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		return nil
+	}
+
+	err := w.f.Close()
+	w.f = nil
+	return err
+}
+
+// write appends data to the active segment and syncs it to disk.
+func (w *WAL) write(data []byte) error {
+	n, err := w.f.Write(data)
+	w.size += int64(n)
+	if err != nil {
+		return err
+	}
+
+	return w.f.Sync()
+}
+
+// rotate starts a new segment from the next sequence number, compressing the
+// previous one.
+func (w *WAL) rotate() error {
+	if w.f != nil {
+		if err := w.seal(); err != nil {
+			return err
+		}
+	}
+
+	s := &segment{first: w.next + 1}
+	s.path = filepath.Join(w.dir, fmt.Sprintf("%020d.wal", s.first))
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	// a segment without events may already exist from the previous run
+	if n := len(w.segments); n == 0 || w.segments[n-1].path != s.path {
+		w.segments = append(w.segments, s)
+	}
+	w.f, w.size, w.opened = f, info.Size(), time.Now()
+
+	// end a line torn by a crash, so it does not corrupt the next record
+	if w.size > 0 {
+		if err := w.write([]byte("\n")); err != nil {
+			return err
+		}
+	}
+
+	return w.compact()
+}
+
+// seal closes the active segment and compresses it.
+func (w *WAL) seal() error {
+	s := w.segments[len(w.segments)-1]
+
+	err := w.f.Close()
+	w.f = nil
+	if err != nil {
+		return err
+	}
+
+	if s.path, err = w.compression.Compress(s.path); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return err
+	}
+	s.size = info.Size()
+
+	return nil
+}
+
+// compact deletes the oldest segments with all their events acknowledged, and
+// those above the maximum size. Segments are deleted in order, as the
+// acknowledgments of their events may be in later ones.
+func (w *WAL) compact() error {
+	size := w.size
+	for _, s := range w.segments[:len(w.segments)-1] {
+		size += s.size
+	}
+
+	for len(w.segments) > 1 && (w.segments[0].pending == 0 || (w.maxSize > 0 && size > w.maxSize)) {
+		if err := os.Remove(w.segments[0].path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		size -= w.segments[0].size
+		w.segments = w.segments[1:]
+	}
+
+	return nil
+}
+
+// replay reads the segments of the directory, collecting the events not acknowledged.
+// A torn last line, from a crash while appending, is ignored.
+func (w *WAL) replay() error {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return err
+	}
+
+	events := make(map[uint64]Event)
+	owner := make(map[uint64]*segment)
+
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(archive.TrimExt(e.Name()), ".wal")
+		if !ok || e.IsDir() {
+			continue
+		}
+
+		first, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			return err
+		}
+
+		s := &segment{first: first, path: filepath.Join(w.dir, e.Name()), size: info.Size()}
+		if err := w.read(s, events, owner); err != nil {
+			return err
+		}
+		w.segments = append(w.segments, s)
+		w.next = max(w.next, first-1)
+	}
+
+	for seq, e := range events {
+		w.pending = append(w.pending, walEvent{Event: e})
+		owner[seq].pending++
+		w.next = max(w.next, seq)
+	}
+	sort.Slice(w.pending, func(i, j int) bool { return w.pending[i].Seq < w.pending[j].Seq })
+
+	return nil
+}
+
+// read reads the records of a segment.
+func (w *WAL) read(s *segment, events map[uint64]Event, owner map[uint64]*segment) error {
+	f, err := archive.Open(s.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var r record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue
+		}
+
+		if r.Ack != 0 {
+			delete(events, r.Ack)
+			w.next = max(w.next, r.Ack)
+			continue
+		}
+		if r.Event != nil && r.Seq != 0 {
+			events[r.Seq] = *r.Event
+			owner[r.Seq] = s
+		}
+	}
+
+	return scanner.Err()
+}
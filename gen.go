@@ -4,5 +4,5 @@
 package omlox
 
 //go:generate easyjson -snake_case -pkg
-//go:generate go run ./cmd/... gen docs --format markdown -o ./docs/cli
+//go:generate go -C cmd/omlox-cli run . gen docs --format markdown -o ../../docs/cli
 //go:generate copywrite headers
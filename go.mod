@@ -6,26 +6,24 @@ require (
 	github.com/google/go-cmp v0.5.9
 	github.com/google/uuid v1.3.0
 	github.com/hashicorp/go-cleanhttp v0.5.2
+	github.com/klauspost/compress v1.17.11
 	github.com/mailru/easyjson v0.7.7
 	github.com/nsf/jsondiff v0.0.0-20230430225905-43f6cf3098c1
-	github.com/spf13/cobra v1.8.0
-	github.com/spf13/pflag v1.0.5
+	github.com/tidwall/geoindex v1.4.4
 	github.com/tidwall/geojson v1.4.3
+	github.com/tidwall/rtree v1.3.1
 	golang.org/x/time v0.4.0
+	gopkg.in/yaml.v3 v3.0.1
 	nhooyr.io/websocket v1.8.10
 )
 
 require (
-	github.com/cpuguy83/go-md2man/v2 v2.0.3 // indirect
-	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/russross/blackfriday/v2 v2.1.0 // indirect
-	github.com/tidwall/geoindex v1.4.4 // indirect
+	github.com/tidwall/cities v0.1.0 // indirect
 	github.com/tidwall/gjson v1.12.1 // indirect
+	github.com/tidwall/lotsa v1.0.2 // indirect
 	github.com/tidwall/match v1.1.1 // indirect
 	github.com/tidwall/pretty v1.2.0 // indirect
-	github.com/tidwall/rtree v1.3.1 // indirect
 	github.com/tidwall/sjson v1.2.4 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 require (
@@ -0,0 +1,144 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package graphql serves a GraphQL facade over the client, for frontends preferring
+// GraphQL to the REST API of the hub: trackables with their nested location and the
+// fences containing them, and fences with the trackables inside them.
+//
+// The resources of a query are loaded from the hub at most once, with the trackables and
+// fences batched in a single listing each, and the locations loaded concurrently, so
+// nested queries do not issue a request per field.
+//
+// Queries are parsed, validated and executed by github.com/graph-gophers/graphql-go, with
+// the resolvers of this package. The package is a module of its own, so programs using the
+// client alone do not depend on the GraphQL library.
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+// DefaultConcurrency is the default number of concurrent requests to the hub of a query.
+const DefaultConcurrency = 8
+
+// maxBodySize is the maximum size of the body of requests.
+const maxBodySize = 1 << 20
+
+// maxDepth is the maximum depth of the selections of queries, such as nesting the
+// trackables of fences in the fences of trackables.
+const maxDepth = 8
+
+// schema is the executable schema, its resolvers taking the loader of a query from its
+// context.
+var schema = graphql.MustParseSchema(Schema, &queryResolver{},
+	graphql.UseStringDescriptions(),
+	graphql.MaxDepth(maxDepth),
+)
+
+// Option is a configuration option of a handler.
+type Option func(*Handler)
+
+// WithConcurrency sets the number of concurrent requests to the hub of a query.
+// Default: DefaultConcurrency
+func WithConcurrency(n int) Option {
+	return func(h *Handler) {
+		h.concurrency = n
+	}
+}
+
+// Handler executes GraphQL queries with a client. It implements http.Handler, serving
+// queries as GET and POST requests of the GraphQL over HTTP conventions. It is safe for
+// concurrent use.
+type Handler struct {
+	client      *omlox.Client
+	concurrency int
+}
+
+var _ http.Handler = (*Handler)(nil)
+
+// NewHandler returns a handler executing queries with a client.
+func NewHandler(client *omlox.Client, opts ...Option) *Handler {
+	h := &Handler{
+		client:      client,
+		concurrency: DefaultConcurrency,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Request is a GraphQL request.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// Response is the response of a GraphQL request. Data is nil if the request could not be
+// executed.
+type Response = graphql.Response
+
+// Execute executes a query. Errors of the fields are reported in the response, leaving
+// the fields null.
+func (h *Handler) Execute(ctx context.Context, req Request) *Response {
+	return execute(ctx, req, newLoader(h.client, h.concurrency))
+}
+
+// execute executes a query with a loader of the resources of the hub.
+func execute(ctx context.Context, req Request, l *loader) *Response {
+	return schema.Exec(withLoader(ctx, l), req.Query, req.OperationName, req.Variables)
+}
+
+// ServeHTTP executes the query of a GET or POST request.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req Request
+
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+		req.Query, req.OperationName = q.Get("query"), q.Get("operationName")
+		if v := q.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				writeError(w, "invalid variables: "+err.Error())
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(&req); err != nil {
+			writeError(w, "invalid request: "+err.Error())
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	resp := h.Execute(r.Context(), req)
+
+	status := http.StatusOK
+	if resp.Data == nil {
+		status = http.StatusBadRequest
+	}
+	writeResponse(w, status, resp)
+}
+
+func writeError(w http.ResponseWriter, message string) {
+	writeResponse(w, http.StatusBadRequest, &Response{Errors: []*gqlerrors.QueryError{{Message: message}}})
+}
+
+func writeResponse(w http.ResponseWriter, status int, resp *Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
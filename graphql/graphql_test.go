@@ -0,0 +1,206 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+var (
+	forklift = uuid.MustParse("6c5d2f0e-8f6a-4b7e-9a51-0d6f1a2b3c41")
+	pallet   = uuid.MustParse("0b1e6a4d-2c3f-4e5a-8b6c-7d8e9f0a1b22")
+	worker   = uuid.MustParse("9f8e7d6c-5b4a-4321-8fed-cba987654303")
+)
+
+// testHub serves the trackables and their locations, counting the requests.
+func testHub(t *testing.T) (*omlox.Client, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		write := func(v string) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(v))
+		}
+
+		switch r.URL.Path {
+		case "/v2/trackables/summary":
+			write(`[
+				{"id":"` + forklift.String() + `","type":"omlox","name":"forklift","location_providers":["uwb-1"]},
+				{"id":"` + pallet.String() + `","type":"omlox","name":"pallet"},
+				{"id":"` + worker.String() + `","type":"omlox","name":"worker"}
+			]`)
+		case "/v2/trackables/" + forklift.String() + "/location":
+			write(`{"position":{"type":"Point","coordinates":[2,3]},"source":"zone","provider_type":"uwb","provider_id":"uwb-1","crs":"local"}`)
+		case "/v2/trackables/" + pallet.String() + "/location":
+			write(`{"position":{"type":"Point","coordinates":[40,50]},"source":"zone","provider_type":"uwb","provider_id":"uwb-2","crs":"local","accuracy":0.5}`)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(omlox.Error{Type: "Not Found", Code: http.StatusNotFound, Message: "not found"})
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := omlox.New(srv.URL + "/v2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return c, &requests
+}
+
+// data returns the JSON encoding of the data of a response, failing on errors.
+func data(t *testing.T, resp *Response) string {
+	t.Helper()
+
+	if len(resp.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", resp.Errors)
+	}
+	b, err := json.Marshal(resp.Data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return string(b)
+}
+
+func TestExecute(t *testing.T) {
+	c, requests := testHub(t)
+	h := NewHandler(c)
+
+	resp := h.Execute(context.Background(), Request{
+		Query: `
+			query Positions($ids: [ID!], $withType: Boolean = false) {
+				trackables(ids: $ids) {
+					...names
+					type @include(if: $withType)
+					location { coordinates accuracy providerId }
+					again: location { source }
+				}
+			}
+
+			fragment names on Trackable { __typename name }
+		`,
+		Variables: map[string]any{"ids": []any{pallet.String(), forklift.String(), worker.String()}},
+	})
+
+	want := `{"trackables":[` +
+		`{"__typename":"Trackable","name":"pallet","location":{"coordinates":[40,50],"accuracy":0.5,"providerId":"uwb-2"},"again":{"source":"zone"}},` +
+		`{"__typename":"Trackable","name":"forklift","location":{"coordinates":[2,3],"accuracy":null,"providerId":"uwb-1"},"again":{"source":"zone"}},` +
+		`{"__typename":"Trackable","name":"worker","location":null,"again":null}]}`
+	if got := data(t, resp); got != want {
+		t.Errorf("unexpected data:\n got %s\nwant %s", got, want)
+	}
+
+	// one listing of the trackables and one request per location
+	if n := requests.Load(); n != 4 {
+		t.Errorf("expected 4 requests to the hub, got %d", n)
+	}
+
+	resp = h.Execute(context.Background(), Request{Query: `{ trackable(id: "` + forklift.String() + `") { id locationProviders } missing: trackable(id: "` + uuid.Nil.String() + `") { id } }`})
+	if got, want := data(t, resp), `{"trackable":{"id":"`+forklift.String()+`","locationProviders":["uwb-1"]},"missing":null}`; got != want {
+		t.Errorf("unexpected data:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	c, _ := testHub(t)
+	h := NewHandler(c)
+
+	tests := []struct {
+		query string
+		err   string
+	}{
+		{query: `{ trackables { id `, err: "syntax error"},
+		{query: `{ zones { id } }`, err: `Cannot query field "zones" on type "Query"`},
+		{query: `{ trackables }`, err: `must have a selection of subfields`},
+		{query: `{ trackables { id { x } } }`, err: `must not have a selection since type "ID!" has no subfields`},
+		{query: `{ trackables(limit: 1) { id } }`, err: `Unknown argument "limit"`},
+		{query: `{ trackables { ...f } } fragment f on Fence { id }`, err: `Fragment "f" cannot be spread here`},
+		{query: `{ trackables { ...f } } fragment f on Trackable { ...f }`, err: `Cannot spread fragment "f" within itself`},
+		{query: `mutation { trackables { id } }`, err: "no mutations are offered by the schema"},
+		{query: `query ($id: ID!) { trackable(id: $id) { id } }`, err: `Variable "id" has invalid value null`},
+		{query: `{ fences { trackables { fences { trackables { fences { trackables { fences { trackables { id } } } } } } } } }`, err: "exceeds max depth"},
+	}
+	for _, tt := range tests {
+		resp := h.Execute(context.Background(), Request{Query: tt.query})
+		if resp.Data != nil || len(resp.Errors) == 0 || !strings.Contains(resp.Errors[0].Message, tt.err) {
+			t.Errorf("%s: expected error %q, got %+v", tt.query, tt.err, resp.Errors)
+		}
+	}
+
+	// field errors leave the field null
+	resp := h.Execute(context.Background(), Request{Query: `{ trackable(id: "forklift") { id } trackables { name } }`})
+	if len(resp.Errors) != 1 || !cmp.Equal(resp.Errors[0].Path, []any{"trackable"}) {
+		t.Errorf("expected an error of the trackable field, got %+v", resp.Errors)
+	}
+	if b, _ := json.Marshal(resp.Data); !strings.HasPrefix(string(b), `{"trackable":null,"trackables":[`) {
+		t.Errorf("unexpected data %s", b)
+	}
+}
+
+func TestExecuteFences(t *testing.T) {
+	c, _ := testHub(t)
+
+	dock := &omlox.Fence{ID: uuid.MustParse("3a1f0c9e-7d2b-4e6f-8a5c-1b2d3e4f5a66"), Name: "dock", Crs: omlox.CrsLocal, Radius: 5, Region: omlox.NewRegionPoint(geometry.Point{X: 0, Y: 0})}
+	yard := &omlox.Fence{ID: uuid.MustParse("5e4d3c2b-1a09-4f8e-9d7c-6b5a4f3e2d77"), Name: "yard", Crs: omlox.CrsLocal, Radius: 100, Region: omlox.NewRegionPoint(geometry.Point{X: 0, Y: 0})}
+
+	l := newLoader(c, DefaultConcurrency)
+	l.fenceList.do(func() ([]*omlox.Fence, error) { return []*omlox.Fence{dock, yard}, nil })
+
+	resp := execute(context.Background(), Request{Query: `{
+		fences { name trackables { name } }
+		trackable(id: "` + forklift.String() + `") { fences { name } }
+	}`}, l)
+
+	want := `{"fences":[{"name":"dock","trackables":[{"name":"forklift"}]},{"name":"yard","trackables":[{"name":"forklift"},{"name":"pallet"}]}],` +
+		`"trackable":{"fences":[{"name":"dock"},{"name":"yard"}]}}`
+	if got := data(t, resp); got != want {
+		t.Errorf("unexpected data:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	c, _ := testHub(t)
+	srv := httptest.NewServer(NewHandler(c))
+	defer srv.Close()
+
+	body, _ := json.Marshal(Request{Query: `query Names { trackables(ids: "` + worker.String() + `") { name } }`})
+	resp, err := http.Post(srv.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var got map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]any{"data": map[string]any{"trackables": []any{map[string]any{"name": "worker"}}}}
+	if resp.StatusCode != http.StatusOK || !cmp.Equal(got, want) {
+		t.Errorf("unexpected response %d %v", resp.StatusCode, got)
+	}
+
+	resp, err = http.Get(srv.URL + "?query=" + "%7B%20fences%20%7D")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, resp.StatusCode)
+	}
+}
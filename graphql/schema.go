@@ -0,0 +1,457 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+// Schema is the GraphQL schema served by handlers, in the schema definition language, for
+// the code generators and tooling of frontends. Handlers also answer introspection queries.
+const Schema = `schema {
+  query: Query
+}
+
+type Query {
+  "All the trackables, or the ones of the given ids."
+  trackables(ids: [ID!]): [Trackable!]!
+  trackable(id: ID!): Trackable
+  "All the fences, or the ones of the given ids."
+  fences(ids: [ID!]): [Fence!]!
+  fence(id: ID!): Fence
+}
+
+type Trackable {
+  id: ID!
+  name: String
+  type: String!
+  radius: Float
+  locationProviders: [String!]!
+  "The last location of the trackable, if recent enough."
+  location: Location
+  "The fences containing the last location of the trackable."
+  fences: [Fence!]!
+}
+
+type Location {
+  providerId: String!
+  providerType: String!
+  source: String
+  crs: String
+  "The x, y and optional z coordinates of the position."
+  coordinates: [Float!]!
+  floor: Float
+  accuracy: Float
+  speed: Float
+  course: Float
+  "RFC 3339 time the location was generated."
+  timestampGenerated: String
+  "RFC 3339 time the location was sent."
+  timestampSent: String
+}
+
+type Fence {
+  id: ID!
+  name: String
+  crs: String
+  radius: Float
+  floor: Float
+  zoneId: String
+  "The trackables whose last location is inside the fence."
+  trackables: [Trackable!]!
+}
+`
+
+// queryResolver resolves the fields of the Query type.
+type queryResolver struct{}
+
+type idsArgs struct {
+	IDs *[]graphql.ID
+}
+
+type idArgs struct {
+	ID graphql.ID
+}
+
+// Trackables returns all the trackables, or the ones of the given ids.
+func (*queryResolver) Trackables(ctx context.Context, args idsArgs) ([]*trackableResolver, error) {
+	ids, err := parseIDs(args.IDs)
+	if err != nil {
+		return nil, err
+	}
+	trackables, err := loaderFrom(ctx).trackables(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return selectByID(trackables, ids, func(t *omlox.Trackable) uuid.UUID { return t.ID }, newTrackableResolver), nil
+}
+
+// Trackable returns a trackable, or nil if not found.
+func (*queryResolver) Trackable(ctx context.Context, args idArgs) (*trackableResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	t, err := loaderFrom(ctx).trackable(ctx, id)
+	if t == nil || err != nil {
+		return nil, err
+	}
+	return newTrackableResolver(t), nil
+}
+
+// Fences returns all the fences, or the ones of the given ids.
+func (*queryResolver) Fences(ctx context.Context, args idsArgs) ([]*fenceResolver, error) {
+	ids, err := parseIDs(args.IDs)
+	if err != nil {
+		return nil, err
+	}
+	fences, err := loaderFrom(ctx).fences(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return selectByID(fences, ids, func(f *omlox.Fence) uuid.UUID { return f.ID }, newFenceResolver), nil
+}
+
+// Fence returns a fence, or nil if not found.
+func (*queryResolver) Fence(ctx context.Context, args idArgs) (*fenceResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	f, err := loaderFrom(ctx).fence(ctx, id)
+	if f == nil || err != nil {
+		return nil, err
+	}
+	return newFenceResolver(f), nil
+}
+
+// trackableResolver resolves the fields of the Trackable type.
+type trackableResolver struct {
+	t *omlox.Trackable
+}
+
+func newTrackableResolver(t *omlox.Trackable) *trackableResolver {
+	return &trackableResolver{t: t}
+}
+
+func (r *trackableResolver) ID() graphql.ID   { return graphql.ID(r.t.ID.String()) }
+func (r *trackableResolver) Name() *string    { return nonZero(r.t.Name) }
+func (r *trackableResolver) Type() string     { return r.t.Type.String() }
+func (r *trackableResolver) Radius() *float64 { return nonZero(r.t.Radius) }
+func (r *trackableResolver) LocationProviders() []string {
+	return append([]string{}, r.t.LocationProviders...)
+}
+
+// Location returns the last location of the trackable, or nil if it has none.
+func (r *trackableResolver) Location(ctx context.Context) (*locationResolver, error) {
+	loc, err := loaderFrom(ctx).location(ctx, r.t.ID)
+	if loc == nil || err != nil {
+		return nil, err
+	}
+	return &locationResolver{l: loc}, nil
+}
+
+// Fences returns the fences containing the last location of the trackable.
+func (r *trackableResolver) Fences(ctx context.Context) ([]*fenceResolver, error) {
+	l := loaderFrom(ctx)
+
+	loc, err := l.location(ctx, r.t.ID)
+	if err != nil || loc == nil {
+		return []*fenceResolver{}, err
+	}
+	fences, err := l.fences(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list := []*fenceResolver{}
+	for _, f := range fences {
+		if f.Contains(*loc) {
+			list = append(list, newFenceResolver(f))
+		}
+	}
+	return list, nil
+}
+
+// locationResolver resolves the fields of the Location type.
+type locationResolver struct {
+	l *omlox.Location
+}
+
+func (r *locationResolver) ProviderID() string          { return r.l.ProviderID }
+func (r *locationResolver) ProviderType() string        { return r.l.ProviderType.String() }
+func (r *locationResolver) Source() *string             { return nonZero(r.l.Source) }
+func (r *locationResolver) Crs() *string                { return nonZero(r.l.Crs) }
+func (r *locationResolver) Floor() *float64             { return nonZero(r.l.Floor) }
+func (r *locationResolver) Accuracy() *float64          { return r.l.Accuracy }
+func (r *locationResolver) Speed() *float64             { return r.l.Speed }
+func (r *locationResolver) Course() *float64            { return r.l.Course }
+func (r *locationResolver) TimestampGenerated() *string { return timestamp(r.l.TimestampGenerated) }
+func (r *locationResolver) TimestampSent() *string      { return timestamp(r.l.TimestampSent) }
+
+// Coordinates returns the x, y and optional z coordinates of the position.
+func (r *locationResolver) Coordinates() []float64 {
+	p := r.l.Position.Base()
+	if z := r.l.Position.Z(); z != 0 {
+		return []float64{p.X, p.Y, z}
+	}
+	return []float64{p.X, p.Y}
+}
+
+// fenceResolver resolves the fields of the Fence type.
+type fenceResolver struct {
+	f *omlox.Fence
+}
+
+func newFenceResolver(f *omlox.Fence) *fenceResolver {
+	return &fenceResolver{f: f}
+}
+
+func (r *fenceResolver) ID() graphql.ID   { return graphql.ID(r.f.ID.String()) }
+func (r *fenceResolver) Name() *string    { return nonZero(r.f.Name) }
+func (r *fenceResolver) Crs() *string     { return nonZero(r.f.Crs) }
+func (r *fenceResolver) Radius() *float64 { return nonZero(r.f.Radius) }
+func (r *fenceResolver) Floor() *float64  { return nonZero(r.f.Floor) }
+func (r *fenceResolver) ZoneID() *string  { return nonZero(r.f.ZoneID) }
+
+// Trackables returns the trackables whose last location is inside the fence.
+func (r *fenceResolver) Trackables(ctx context.Context) ([]*trackableResolver, error) {
+	l := loaderFrom(ctx)
+
+	trackables, err := l.trackables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the locations of the trackables are loaded concurrently
+	inside := make([]bool, len(trackables))
+	errs := make([]error, len(trackables))
+	var wg sync.WaitGroup
+	for i, t := range trackables {
+		wg.Add(1)
+		go func(i int, id uuid.UUID) {
+			defer wg.Done()
+
+			loc, err := l.location(ctx, id)
+			inside[i], errs[i] = err == nil && loc != nil && r.f.Contains(*loc), err
+		}(i, t.ID)
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	list := []*trackableResolver{}
+	for i, t := range trackables {
+		if inside[i] {
+			list = append(list, newTrackableResolver(t))
+		}
+	}
+	return list, nil
+}
+
+// nonZero returns zero values as nil, for nullable fields.
+func nonZero[T comparable](v T) *T {
+	var zero T
+	if v == zero {
+		return nil
+	}
+	return &v
+}
+
+func timestamp(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format(time.RFC3339Nano)
+	return &s
+}
+
+// selectByID returns the resolvers of the resources of the given ids in their order, or
+// of all of them if no ids are given.
+func selectByID[T, R any](resources []*T, ids []uuid.UUID, id func(*T) uuid.UUID, resolver func(*T) R) []R {
+	list := []R{}
+	if ids == nil {
+		for _, res := range resources {
+			list = append(list, resolver(res))
+		}
+		return list
+	}
+
+	byID := make(map[uuid.UUID]*T, len(resources))
+	for _, res := range resources {
+		byID[id(res)] = res
+	}
+	for _, id := range ids {
+		if res, ok := byID[id]; ok {
+			list = append(list, resolver(res))
+		}
+	}
+	return list
+}
+
+// parseID parses an ID argument.
+func parseID(id graphql.ID) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(string(id))
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid id %q: %w", id, err)
+	}
+	return parsed, nil
+}
+
+// parseIDs parses an optional list of IDs argument, nil if not given.
+func parseIDs(ids *[]graphql.ID) ([]uuid.UUID, error) {
+	if ids == nil {
+		return nil, nil
+	}
+
+	parsed := make([]uuid.UUID, 0, len(*ids))
+	for _, id := range *ids {
+		p, err := parseID(id)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, p)
+	}
+	return parsed, nil
+}
+
+// loaderKey is the context key of the loader of a query.
+type loaderKey struct{}
+
+func withLoader(ctx context.Context, l *loader) context.Context {
+	return context.WithValue(ctx, loaderKey{}, l)
+}
+
+func loaderFrom(ctx context.Context) *loader {
+	return ctx.Value(loaderKey{}).(*loader)
+}
+
+// loader loads the resources of a request from the hub, each at most once, batching the
+// loads of trackables and fences in a single listing and limiting the concurrent
+// requests.
+type loader struct {
+	client *omlox.Client
+	sem    chan struct{}
+
+	trackableList call[[]*omlox.Trackable]
+	fenceList     call[[]*omlox.Fence]
+
+	mu        sync.Mutex
+	locations map[uuid.UUID]*call[*omlox.Location]
+}
+
+func newLoader(client *omlox.Client, concurrency int) *loader {
+	return &loader{
+		client:    client,
+		sem:       make(chan struct{}, max(1, concurrency)),
+		locations: make(map[uuid.UUID]*call[*omlox.Location]),
+	}
+}
+
+// call is a load done at most once.
+type call[T any] struct {
+	once sync.Once
+	v    T
+	err  error
+}
+
+func (c *call[T]) do(f func() (T, error)) (T, error) {
+	c.once.Do(func() {
+		c.v, c.err = f()
+	})
+	return c.v, c.err
+}
+
+// limit runs a request to the hub, waiting while too many requests are running.
+func limit[T any](ctx context.Context, l *loader, f func() (T, error)) (T, error) {
+	select {
+	case l.sem <- struct{}{}:
+		defer func() { <-l.sem }()
+		return f()
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// trackables returns all the trackables.
+func (l *loader) trackables(ctx context.Context) ([]*omlox.Trackable, error) {
+	return l.trackableList.do(func() ([]*omlox.Trackable, error) {
+		trackables, err := limit(ctx, l, func() ([]omlox.Trackable, error) { return l.client.Trackables.List(ctx) })
+		return pointers(trackables), err
+	})
+}
+
+// trackable returns a trackable, or nil if not found.
+func (l *loader) trackable(ctx context.Context, id uuid.UUID) (*omlox.Trackable, error) {
+	trackables, err := l.trackables(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range trackables {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return nil, nil
+}
+
+// fences returns all the fences.
+func (l *loader) fences(ctx context.Context) ([]*omlox.Fence, error) {
+	return l.fenceList.do(func() ([]*omlox.Fence, error) {
+		fences, err := limit(ctx, l, func() ([]omlox.Fence, error) { return l.client.Fences.List(ctx) })
+		return pointers(fences), err
+	})
+}
+
+// fence returns a fence, or nil if not found.
+func (l *loader) fence(ctx context.Context, id uuid.UUID) (*omlox.Fence, error) {
+	fences, err := l.fences(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fences {
+		if f.ID == id {
+			return f, nil
+		}
+	}
+	return nil, nil
+}
+
+// location returns the last location of a trackable, or nil if it has none.
+func (l *loader) location(ctx context.Context, id uuid.UUID) (*omlox.Location, error) {
+	l.mu.Lock()
+	c, ok := l.locations[id]
+	if !ok {
+		c = new(call[*omlox.Location])
+		l.locations[id] = c
+	}
+	l.mu.Unlock()
+
+	return c.do(func() (*omlox.Location, error) {
+		loc, err := limit(ctx, l, func() (*omlox.Location, error) { return l.client.Trackables.GetLocation(ctx, id) })
+		if errors.Is(err, omlox.ErrNotFound) {
+			return nil, nil
+		}
+		return loc, err
+	})
+}
+
+func pointers[T any](values []T) []*T {
+	ptrs := make([]*T, len(values))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	return ptrs
+}
@@ -0,0 +1,245 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package group shares the workload of omlox™ subscriptions among the instances
+// of a horizontally scaled service. Events are assigned to instances by consistent
+// hashing on their trackable id, so every event is processed by a single instance
+// and membership changes only move the trackables of the joining or leaving instance.
+//
+// Group membership is provided by the service, for example from the pods of a
+// Kubernetes deployment, and updated with SetMembers.
+package group
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+// Rebalance describes a change of the group membership.
+type Rebalance struct {
+	// Previous are the members before the change, sorted.
+	Previous []string
+
+	// Members are the members after the change, sorted.
+	Members []string
+}
+
+// Option is a configuration option of a group.
+type Option func(*Group)
+
+// WithReplicas sets the number of virtual nodes of each member in the hash ring.
+// More replicas give a more even distribution at the expense of memory.
+func WithReplicas(replicas int) Option {
+	return func(g *Group) {
+		g.replicas = replicas
+	}
+}
+
+// OnRebalance registers a hook called after every change of the group membership,
+// for example to flush the state of the trackables no longer owned by the instance.
+func OnRebalance(hook func(Rebalance)) Option {
+	return func(g *Group) {
+		g.hooks = append(g.hooks, hook)
+	}
+}
+
+// Group is the view of a consumer group from one of its members.
+// It is safe for concurrent use.
+type Group struct {
+	self     string
+	replicas int
+	hooks    []func(Rebalance)
+
+	mu   sync.RWMutex
+	ring *Ring
+}
+
+// New returns the group of members, as seen from the member self.
+func New(self string, members []string, opts ...Option) (*Group, error) {
+	if self == "" {
+		return nil, errors.New("group member name must not be empty")
+	}
+
+	g := &Group{
+		self:     self,
+		replicas: DefaultReplicas,
+	}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	g.ring = NewRing(g.replicas, members...)
+
+	return g, nil
+}
+
+// Self returns the name of the member.
+func (g *Group) Self() string {
+	return g.self
+}
+
+// Members returns the current members of the group, sorted.
+func (g *Group) Members() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.ring.Members()
+}
+
+// SetMembers replaces the members of the group and calls the rebalance hooks if they changed.
+func (g *Group) SetMembers(members ...string) {
+	ring := NewRing(g.replicas, members...)
+
+	g.mu.Lock()
+	previous := g.ring.Members()
+	if slices.Equal(previous, ring.Members()) {
+		g.mu.Unlock()
+		return
+	}
+	g.ring = ring
+	g.mu.Unlock()
+
+	r := Rebalance{Previous: previous, Members: ring.Members()}
+	for _, hook := range g.hooks {
+		hook(r)
+	}
+}
+
+// Owner returns the member owning a trackable.
+func (g *Group) Owner(trackableID uuid.UUID) string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.ring.Owner(trackableID[:])
+}
+
+// Owns reports whether the member owns a trackable.
+func (g *Group) Owns(trackableID uuid.UUID) bool {
+	return g.Owner(trackableID) == g.self
+}
+
+// Owned returns the trackables owned by the member. It can restrict a subscription
+// to the shard of the member with omlox.WithTrackables, when the trackables are known.
+func (g *Group) Owned(trackableIDs ...uuid.UUID) []uuid.UUID {
+	var owned []uuid.UUID
+	for _, id := range trackableIDs {
+		if g.Owns(id) {
+			owned = append(owned, id)
+		}
+	}
+	return owned
+}
+
+// payloadReferences are the identifiers referenced by the event payloads.
+// Trackable motions are identified by their trackable id.
+type payloadReferences struct {
+	ID          *uuid.UUID  `json:"id"`
+	ProviderID  string      `json:"provider_id"`
+	TrackableID *uuid.UUID  `json:"trackable_id"`
+	Trackables  []uuid.UUID `json:"trackables"`
+	Location    *struct {
+		ProviderID string      `json:"provider_id"`
+		Trackables []uuid.UUID `json:"trackables"`
+	} `json:"location"`
+}
+
+// ownsPayload reports whether the member owns an event payload. Events of several
+// trackables are owned by the owner of the smallest trackable id, and events without
+// trackables by the owner of their location provider.
+func (g *Group) ownsPayload(topic omlox.Topic, payload json.RawMessage) bool {
+	var refs payloadReferences
+	if err := json.Unmarshal(payload, &refs); err != nil {
+		return false
+	}
+
+	trackables := refs.Trackables
+	if refs.TrackableID != nil {
+		trackables = append(trackables, *refs.TrackableID)
+	}
+	if refs.ID != nil && topic == omlox.TopicTrackableMotions {
+		trackables = append(trackables, *refs.ID)
+	}
+	if refs.Location != nil {
+		trackables = append(trackables, refs.Location.Trackables...)
+	}
+
+	if len(trackables) > 0 {
+		min := slices.MinFunc(trackables, func(a, b uuid.UUID) int { return slices.Compare(a[:], b[:]) })
+		return g.Owns(min)
+	}
+
+	provider := refs.ProviderID
+	if provider == "" && refs.Location != nil {
+		provider = refs.Location.ProviderID
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.ring.Owner([]byte(provider)) == g.self
+}
+
+// Filter returns the message with only the payloads owned by the member, or nil if none is.
+func (g *Group) Filter(msg *omlox.WrapperObject) *omlox.WrapperObject {
+	payload := make([]json.RawMessage, 0, len(msg.Payload))
+	for _, p := range msg.Payload {
+		if g.ownsPayload(msg.Topic, p) {
+			payload = append(payload, p)
+		}
+	}
+
+	if len(payload) == 0 {
+		return nil
+	}
+
+	filtered := *msg
+	filtered.Payload = payload
+	return &filtered
+}
+
+// Receive returns the messages of a subscription restricted to the payloads owned
+// by the member. Ownership is evaluated on delivery, so rebalancing applies to the
+// next messages without subscribing again.
+func (g *Group) Receive(sub *omlox.Subcription) <-chan *omlox.WrapperObject {
+	out := make(chan *omlox.WrapperObject, cap(sub.ReceiveRaw()))
+
+	go func() {
+		defer close(out)
+
+		for msg := range sub.ReceiveRaw() {
+			if msg = g.Filter(msg); msg != nil {
+				out <- msg
+			}
+		}
+	}()
+
+	return out
+}
+
+// ReceiveAs returns the decoded payloads of a subscription owned by the member.
+func ReceiveAs[T any](g *Group, sub *omlox.Subcription) <-chan *T {
+	out := make(chan *T, cap(sub.ReceiveRaw()))
+
+	go func() {
+		defer close(out)
+
+		for msg := range g.Receive(sub) {
+			for _, payload := range msg.Payload {
+				var v T
+				if err := json.Unmarshal(payload, &v); err != nil {
+					continue
+				}
+
+				out <- &v
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,98 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package group
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+func TestRing(t *testing.T) {
+	ids := make([]uuid.UUID, 1000)
+	for i := range ids {
+		ids[i] = uuid.NewSHA1(uuid.NameSpaceOID, []byte(fmt.Sprint(i)))
+	}
+
+	three := NewRing(0, "a", "b", "c", "c", "")
+	if got := three.Members(); fmt.Sprint(got) != "[a b c]" {
+		t.Fatalf("unexpected members: %v", got)
+	}
+
+	counts := make(map[string]int)
+	for _, id := range ids {
+		counts[three.Owner(id[:])]++
+	}
+	for _, m := range three.Members() {
+		if counts[m] < 200 {
+			t.Errorf("uneven distribution: %v", counts)
+		}
+	}
+
+	// removing a member only moves its own keys
+	two := NewRing(0, "a", "b")
+	for _, id := range ids {
+		before, after := three.Owner(id[:]), two.Owner(id[:])
+		if before != "c" && before != after {
+			t.Fatalf("key %s moved from %s to %s", id, before, after)
+		}
+	}
+
+	if got := NewRing(0).Owner(ids[0][:]); got != "" {
+		t.Errorf("expected no owner in an empty ring, got %q", got)
+	}
+}
+
+func TestGroup(t *testing.T) {
+	var rebalances []Rebalance
+
+	a, err := New("a", []string{"a", "b"}, OnRebalance(func(r Rebalance) { rebalances = append(rebalances, r) }))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := New("b", []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ids := make([]uuid.UUID, 100)
+	for i := range ids {
+		ids[i] = uuid.NewSHA1(uuid.NameSpaceOID, []byte(fmt.Sprint(i)))
+	}
+
+	// every trackable is owned by exactly one member
+	if got := len(a.Owned(ids...)) + len(b.Owned(ids...)); got != len(ids) {
+		t.Errorf("expected %d owned trackables, got %d", len(ids), got)
+	}
+
+	msg := &omlox.WrapperObject{Event: omlox.EventMsg, Topic: omlox.TopicLocationUpdates}
+	for _, id := range ids {
+		msg.Payload = append(msg.Payload, json.RawMessage(`{"provider_id":"p","trackables":["`+id.String()+`"]}`))
+	}
+
+	fa, fb := a.Filter(msg), b.Filter(msg)
+	if fa == nil || fb == nil || len(fa.Payload)+len(fb.Payload) != len(ids) {
+		t.Fatalf("expected payloads split between members, got %v and %v", fa, fb)
+	}
+
+	a.SetMembers("b", "a")
+	if len(rebalances) != 0 {
+		t.Errorf("expected no rebalance for the same members, got %v", rebalances)
+	}
+
+	a.SetMembers("a")
+	if len(rebalances) != 1 || fmt.Sprint(rebalances[0].Previous) != "[a b]" || fmt.Sprint(rebalances[0].Members) != "[a]" {
+		t.Errorf("unexpected rebalances: %v", rebalances)
+	}
+	if got := a.Filter(msg); got == nil || len(got.Payload) != len(ids) {
+		t.Errorf("expected the single member to own every payload")
+	}
+
+	if _, err := New("", nil); err == nil {
+		t.Error("expected an error for an empty member name")
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package group
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// DefaultReplicas is the default number of virtual nodes of each member in the ring.
+const DefaultReplicas = 128
+
+// Ring is a consistent hash ring distributing keys among members.
+// Adding or removing a member only moves the keys of that member.
+// A ring is immutable and safe for concurrent use.
+type Ring struct {
+	members []string
+	hashes  []uint64
+	owners  map[uint64]string
+}
+
+// NewRing returns a ring of the given members with a number of virtual nodes per member.
+// Duplicated and empty members are ignored.
+func NewRing(replicas int, members ...string) *Ring {
+	if replicas <= 0 {
+		replicas = DefaultReplicas
+	}
+
+	r := &Ring{owners: make(map[uint64]string)}
+
+	seen := make(map[string]bool, len(members))
+	for _, m := range members {
+		if m == "" || seen[m] {
+			continue
+		}
+		seen[m] = true
+		r.members = append(r.members, m)
+
+		for i := 0; i < replicas; i++ {
+			h := hash([]byte(m + "#" + strconv.Itoa(i)))
+
+			// on the unlikely collision, the smallest member wins to stay deterministic
+			if owner, ok := r.owners[h]; ok && owner < m {
+				continue
+			}
+			if _, ok := r.owners[h]; !ok {
+				r.hashes = append(r.hashes, h)
+			}
+			r.owners[h] = m
+		}
+	}
+
+	sort.Strings(r.members)
+	sort.Slice(r.hashes, func(i, j int) bool { return r.hashes[i] < r.hashes[j] })
+
+	return r
+}
+
+// Members returns the members of the ring, sorted.
+func (r *Ring) Members() []string {
+	return append([]string(nil), r.members...)
+}
+
+// Owner returns the member owning a key, or an empty string if the ring has no members.
+func (r *Ring) Owner(key []byte) string {
+	if len(r.hashes) == 0 {
+		return ""
+	}
+
+	h := hash(key)
+	i := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	if i == len(r.hashes) {
+		i = 0
+	}
+
+	return r.owners[r.hashes[i]]
+}
+
+// hash returns the FNV-1a hash of the key, mixed with the SplitMix64 finalizer
+// as FNV alone distributes similar keys poorly along the ring.
+func hash(b []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(b)
+
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+
+	return x
+}
@@ -0,0 +1,317 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package handoff forwards omlox™ subscription messages to external processors
+// with at-least-once semantics.
+//
+// Every message is handed out as a Delivery which must be acknowledged once
+// processed. Messages negatively acknowledged, or not acknowledged in time, are
+// delivered again with backoff. With a Store, unacknowledged messages survive
+// restarts of the process and are delivered again on the next run.
+package handoff
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+// Defaults of the handoff configuration.
+const (
+	DefaultAckTimeout = 30 * time.Second
+	DefaultMinBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff = 30 * time.Second
+)
+
+// ErrSettled is returned when acknowledging a delivery which was already acknowledged,
+// negatively acknowledged, or redelivered after its acknowledgment timeout.
+var ErrSettled = errors.New("delivery already settled")
+
+// Store persists the unacknowledged messages of a handoff.
+type Store interface {
+	// Save persists a message under an id.
+	Save(id uint64, msg *omlox.WrapperObject) error
+
+	// Delete removes the message of an id. Deleting an unknown id is not an error.
+	Delete(id uint64) error
+
+	// Load returns the persisted messages by id.
+	Load() (map[uint64]*omlox.WrapperObject, error)
+}
+
+// Option is a configuration option of a handoff.
+type Option func(*Handoff)
+
+// WithAckTimeout sets the time a delivery has to be acknowledged before being delivered again.
+func WithAckTimeout(d time.Duration) Option {
+	return func(h *Handoff) {
+		h.ackTimeout = d
+	}
+}
+
+// WithBackoff sets the bounds of the exponential backoff between redeliveries of a message.
+func WithBackoff(min, max time.Duration) Option {
+	return func(h *Handoff) {
+		h.minBackoff = min
+		h.maxBackoff = max
+	}
+}
+
+// WithMaxAttempts sets the number of deliveries of a message before it is given up
+// and passed to the dead letter hook. Zero, the default, retries forever.
+func WithMaxAttempts(n int) Option {
+	return func(h *Handoff) {
+		h.maxAttempts = n
+	}
+}
+
+// WithStore persists unacknowledged messages, to deliver them again after a restart.
+func WithStore(s Store) Option {
+	return func(h *Handoff) {
+		h.store = s
+	}
+}
+
+// OnDeadLetter registers a hook called with the deliveries given up after the maximum attempts.
+func OnDeadLetter(hook func(*Delivery)) Option {
+	return func(h *Handoff) {
+		h.deadLetter = hook
+	}
+}
+
+// WithClock sets the source of time of the acknowledgment timeouts and the redelivery backoffs.
+func WithClock(clk clock.Clock) Option {
+	return func(h *Handoff) {
+		h.clock = clk
+	}
+}
+
+// Delivery is a message handed out to a processor.
+type Delivery struct {
+	// ID identifies the message across its deliveries.
+	ID uint64
+
+	// Message is the delivered message.
+	Message *omlox.WrapperObject
+
+	// Attempt is the number of the delivery, starting at 1.
+	Attempt int
+
+	h     *Handoff
+	timer clock.Timer
+}
+
+// Ack acknowledges the message was processed, so it is not delivered again.
+func (d *Delivery) Ack() error {
+	if !d.h.settle(d) {
+		return ErrSettled
+	}
+
+	if d.h.store != nil {
+		return d.h.store.Delete(d.ID)
+	}
+
+	return nil
+}
+
+// Nack reports the message could not be processed, to be delivered again after a backoff.
+func (d *Delivery) Nack() error {
+	if !d.h.settle(d) {
+		return ErrSettled
+	}
+
+	return d.h.retry(d)
+}
+
+// Handoff hands out subscription messages to processors until they are acknowledged.
+type Handoff struct {
+	ackTimeout  time.Duration
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	maxAttempts int
+	store       Store
+	deadLetter  func(*Delivery)
+	clock       clock.Clock
+
+	out       chan *Delivery
+	redeliver chan pending
+	stopped   chan struct{}
+
+	mu       sync.Mutex
+	inflight map[*Delivery]bool
+	nextID   uint64
+}
+
+// New returns a new handoff with the given options.
+func New(opts ...Option) *Handoff {
+	h := &Handoff{
+		ackTimeout: DefaultAckTimeout,
+		minBackoff: DefaultMinBackoff,
+		maxBackoff: DefaultMaxBackoff,
+		out:        make(chan *Delivery),
+		redeliver:  make(chan pending),
+		stopped:    make(chan struct{}),
+		inflight:   make(map[*Delivery]bool),
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.clock = clock.Or(h.clock)
+
+	return h
+}
+
+// Deliveries returns the channel of deliveries. It is closed when Run returns.
+func (h *Handoff) Deliveries() <-chan *Delivery {
+	return h.out
+}
+
+// Run hands out the messages of the source, such as Subcription.ReceiveRaw, until the
+// source is closed or the context is done. Messages persisted by a previous run are
+// delivered first.
+func (h *Handoff) Run(ctx context.Context, source <-chan *omlox.WrapperObject) error {
+	defer close(h.out)
+	defer close(h.stopped)
+
+	var queue []pending
+
+	if h.store != nil {
+		msgs, err := h.store.Load()
+		if err != nil {
+			return err
+		}
+
+		for _, id := range sortedIDs(msgs) {
+			queue = append(queue, pending{id: id, msg: msgs[id]})
+			h.nextID = max(h.nextID, id)
+		}
+	}
+
+	for {
+		var (
+			out  chan *Delivery
+			next *Delivery
+		)
+		if len(queue) > 0 {
+			p := queue[0]
+			out, next = h.out, &Delivery{ID: p.id, Message: p.msg, Attempt: p.attempts + 1, h: h}
+			h.track(next)
+		}
+
+		select {
+		case out <- next:
+			queue = queue[1:]
+			continue
+		case <-ctx.Done():
+			h.settle(next)
+			return ctx.Err()
+		case msg, ok := <-source:
+			if !ok {
+				h.settle(next)
+				return nil
+			}
+
+			h.nextID++
+			if h.store != nil {
+				if err := h.store.Save(h.nextID, msg); err != nil {
+					h.settle(next)
+					return err
+				}
+			}
+			queue = append(queue, pending{id: h.nextID, msg: msg})
+		case p := <-h.redeliver:
+			queue = append(queue, p)
+		}
+
+		// the head was not handed out, unless its acknowledgment timeout already rescheduled it
+		if next != nil && !h.settle(next) {
+			queue = queue[1:]
+		}
+	}
+}
+
+// pending is a message waiting to be delivered.
+type pending struct {
+	id       uint64
+	msg      *omlox.WrapperObject
+	attempts int
+}
+
+// track marks a delivery in flight, redelivering it after the acknowledgment timeout.
+func (h *Handoff) track(d *Delivery) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.inflight[d] = true
+	if h.ackTimeout > 0 {
+		d.timer = h.clock.AfterFunc(h.ackTimeout, func() {
+			if h.settle(d) {
+				h.retry(d)
+			}
+		})
+	}
+}
+
+// settle marks a delivery as no longer in flight, reporting whether it was.
+func (h *Handoff) settle(d *Delivery) bool {
+	if d == nil {
+		return false
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.inflight[d] {
+		return false
+	}
+
+	delete(h.inflight, d)
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+
+	return true
+}
+
+// retry schedules a settled delivery to be delivered again, or gives it up after
+// the maximum attempts.
+func (h *Handoff) retry(d *Delivery) error {
+	if h.maxAttempts > 0 && d.Attempt >= h.maxAttempts {
+		if h.deadLetter != nil {
+			h.deadLetter(d)
+		}
+		if h.store != nil {
+			return h.store.Delete(d.ID)
+		}
+		return nil
+	}
+
+	h.clock.AfterFunc(backoff(h.minBackoff, h.maxBackoff, d.Attempt-1), func() {
+		select {
+		case h.redeliver <- pending{id: d.ID, msg: d.Message, attempts: d.Attempt}:
+		case <-h.stopped:
+		}
+	})
+
+	return nil
+}
+
+// backoff calculates the time to wait based on the number of attempts with
+// exponential backoff strategy and full jitter.
+func backoff(min, max time.Duration, attempt int) time.Duration {
+	d := min * (1 << uint(attempt))
+	if d > max || d <= 0 {
+		d = max
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(d)))
+}
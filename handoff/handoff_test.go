@@ -0,0 +1,155 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package handoff
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+func message(payload string) *omlox.WrapperObject {
+	return &omlox.WrapperObject{
+		Event:   omlox.EventMsg,
+		Topic:   omlox.TopicLocationUpdates,
+		Payload: []json.RawMessage{json.RawMessage(payload)},
+	}
+}
+
+// receive returns the next delivery or fails the test.
+func receive(t *testing.T, h *Handoff) *Delivery {
+	t.Helper()
+
+	select {
+	case d := <-h.Deliveries():
+		return d
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for a delivery")
+		return nil
+	}
+}
+
+func TestHandoff(t *testing.T) {
+	var dead []*Delivery
+
+	clk := clock.NewFake(time.Now())
+
+	h := New(
+		WithAckTimeout(50*time.Millisecond),
+		WithBackoff(time.Millisecond, time.Millisecond),
+		WithMaxAttempts(3),
+		OnDeadLetter(func(d *Delivery) { dead = append(dead, d) }),
+		WithClock(clk),
+	)
+
+	source := make(chan *omlox.WrapperObject, 2)
+	source <- message(`{"n":1}`)
+	source <- message(`{"n":2}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() { done <- h.Run(ctx, source) }()
+
+	// acknowledged messages are not delivered again
+	d := receive(t, h)
+	if d.ID != 1 || d.Attempt != 1 {
+		t.Fatalf("unexpected delivery: %+v", d)
+	}
+	if err := d.Ack(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.Ack(); !errors.Is(err, ErrSettled) {
+		t.Errorf("expected ErrSettled, got %v", err)
+	}
+
+	// negatively acknowledged messages are delivered again
+	d = receive(t, h)
+	if err := d.Nack(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	clk.Advance(time.Millisecond) // backoff
+	d = receive(t, h)
+	if d.ID != 2 || d.Attempt != 2 {
+		t.Fatalf("unexpected redelivery: %+v", d)
+	}
+
+	// unacknowledged messages are delivered again after the timeout, and given up after the maximum attempts
+	clk.Advance(50 * time.Millisecond)
+	clk.BlockUntil(1) // backoff scheduled by the timeout
+	clk.Advance(time.Millisecond)
+	d = receive(t, h)
+	if d.ID != 2 || d.Attempt != 3 {
+		t.Fatalf("unexpected redelivery: %+v", d)
+	}
+	if err := d.Nack(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(dead) != 1 || dead[0].ID != 2 {
+		t.Errorf("expected message 2 given up, got %v", dead)
+	}
+
+	close(source)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestHandoffStore(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// first run receives a message and crashes before acknowledging it
+	h := New(WithStore(store))
+	source := make(chan *omlox.WrapperObject, 1)
+	source <- message(`{"n":1}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- h.Run(ctx, source) }()
+
+	if d := receive(t, h); d.ID != 1 {
+		t.Fatalf("unexpected delivery: %+v", d)
+	}
+	cancel()
+	<-done
+
+	// the next run delivers it again
+	h = New(WithStore(store))
+	source = make(chan *omlox.WrapperObject, 1)
+	source <- message(`{"n":2}`)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	go func() { done <- h.Run(ctx, source) }()
+
+	d := receive(t, h)
+	if d.ID != 1 || string(d.Message.Payload[0]) != `{"n":1}` {
+		t.Fatalf("expected the unacknowledged message, got %+v", d)
+	}
+	if err := d.Ack(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// new messages do not reuse the ids of persisted ones
+	if d := receive(t, h); d.ID != 2 {
+		t.Fatalf("unexpected delivery: %+v", d)
+	}
+
+	pending, err := store.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := pending[1]; ok || len(pending) != 1 {
+		t.Errorf("expected only message 2 pending, got %v", pending)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package handoff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+// FileStore is a Store keeping each unacknowledged message in a file of a directory.
+type FileStore struct {
+	dir string
+}
+
+var _ Store = (*FileStore)(nil)
+
+// NewFileStore returns a store in a directory, creating it if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) path(id uint64) string {
+	return filepath.Join(s.dir, strconv.FormatUint(id, 10)+".json")
+}
+
+// Save implements Store. Messages are written atomically.
+func (s *FileStore) Save(id uint64, msg *omlox.WrapperObject) error {
+	data, err := msg.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	tmp := s.path(id) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, s.path(id))
+}
+
+// Delete implements Store.
+func (s *FileStore) Delete(id uint64) error {
+	err := os.Remove(s.path(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Load implements Store.
+func (s *FileStore) Load() (map[uint64]*omlox.WrapperObject, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := make(map[uint64]*omlox.WrapperObject)
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+
+		id, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(s.dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var msg omlox.WrapperObject
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, fmt.Errorf("invalid handoff message '%s': %w", e.Name(), err)
+		}
+		msgs[id] = &msg
+	}
+
+	return msgs, nil
+}
+
+func sortedIDs(msgs map[uint64]*omlox.WrapperObject) []uint64 {
+	ids := make([]uint64, 0, len(msgs))
+	for id := range msgs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
@@ -0,0 +1,94 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+// Playback replays the location history of the given time interval as a
+// location updates subscription. Locations are emitted in the order they were
+// generated, preserving the time between them scaled by speed (e.g. a speed of
+// 2 replays twice as fast). A speed of zero replays without any delay.
+//
+// The returned subscription behaves like a live one, so it can be consumed with
+// ReceiveAs or ReceiveRaw. Its channel is closed once the playback finishes or
+// the context is canceled.
+func (c *HistoryAPI) Playback(ctx context.Context, from time.Time, to time.Time, speed float64) (*Subcription, error) {
+	if speed < 0 {
+		return nil, fmt.Errorf("playback speed must not be negative")
+	}
+
+	locations, err := c.Locations(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &Subcription{
+		topic: TopicLocationUpdates,
+		mch:   make(chan *WrapperObject, 1),
+	}
+
+	go playback(ctx, c.client.timeSource(), sub, locations, speed)
+
+	return sub, nil
+}
+
+// playback emits the locations to the subscription channel and closes it when done.
+func playback(ctx context.Context, clk clock.Clock, sub *Subcription, locations []Location, speed float64) {
+	defer sub.close()
+
+	sort.SliceStable(locations, func(i, j int) bool {
+		return timestamp(locations[i]).Before(timestamp(locations[j]))
+	})
+
+	var prev time.Time
+
+	for _, loc := range locations {
+		ts := timestamp(loc)
+
+		if speed > 0 && !prev.IsZero() && ts.After(prev) {
+			t := clk.NewTimer(time.Duration(float64(ts.Sub(prev)) / speed))
+
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return
+			case <-t.C():
+			}
+		}
+		prev = ts
+
+		payload, err := json.Marshal(loc)
+		if err != nil {
+			continue
+		}
+
+		msg := &WrapperObject{
+			Event:   EventMsg,
+			Topic:   sub.topic,
+			Payload: []json.RawMessage{payload},
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case sub.mch <- msg:
+		}
+	}
+}
+
+// timestamp returns the generation timestamp of a location, if any.
+func timestamp(loc Location) time.Time {
+	if loc.TimestampGenerated == nil {
+		return time.Time{}
+	}
+	return *loc.TimestampGenerated
+}
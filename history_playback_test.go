@@ -0,0 +1,87 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+func TestPlayback(t *testing.T) {
+	locations := []Location{
+		{ProviderID: "c", TimestampGenerated: mustParseTime("2023-10-17T11:14:37.300Z")},
+		{ProviderID: "a", TimestampGenerated: mustParseTime("2023-10-17T11:14:37.100Z")},
+		{ProviderID: "b", TimestampGenerated: mustParseTime("2023-10-17T11:14:37.200Z")},
+	}
+
+	sub := &Subcription{
+		topic: TopicLocationUpdates,
+		mch:   make(chan *WrapperObject, 1),
+	}
+
+	clk := clock.NewFake(time.Now())
+	go playback(context.Background(), clk, sub, locations, 10)
+
+	var order string
+	msgs := ReceiveAs[Location](sub)
+
+	for i := 0; i < len(locations); i++ {
+		if i > 0 {
+			// 100ms between locations at 10x speed
+			clk.BlockUntil(1)
+			clk.Advance(10 * time.Millisecond)
+		}
+
+		loc, ok := <-msgs
+		if !ok {
+			t.Fatalf("subscription closed after %d locations", i)
+		}
+		order += loc.ProviderID
+	}
+
+	if order != "abc" {
+		t.Errorf("expected locations in generation order 'abc', got '%s'", order)
+	}
+
+	if _, ok := <-msgs; ok {
+		t.Errorf("expected subscription to be closed")
+	}
+}
+
+func TestPlaybackCanceled(t *testing.T) {
+	locations := []Location{
+		{ProviderID: "a", TimestampGenerated: mustParseTime("2023-10-17T11:00:00Z")},
+		{ProviderID: "b", TimestampGenerated: mustParseTime("2023-10-17T12:00:00Z")},
+	}
+
+	sub := &Subcription{
+		topic: TopicLocationUpdates,
+		mch:   make(chan *WrapperObject, 1),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	clk := clock.NewFake(time.Now())
+	go playback(ctx, clk, sub, locations, 1)
+
+	msgs := sub.ReceiveRaw()
+	if msg := <-msgs; msg == nil {
+		t.Fatal("expected first location")
+	}
+
+	// the playback waits for the second location, an hour later
+	clk.BlockUntil(1)
+	cancel()
+
+	select {
+	case _, ok := <-msgs:
+		if ok {
+			t.Error("expected subscription to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Error("playback did not stop on context cancellation")
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HistoryAPI is a simple wrapper around the client for location history requests.
+//
+// Location history is an optional API and not every Omlox™ Hub implements it.
+// Requests to Hubs known not to support it fail early with ErrNotSupported,
+// while other Hubs without history support respond with a not found error.
+// Where possible, missing history endpoints are replaced by alternate endpoints or by
+// client-side computation from the APIs available, as reported by Client.Capabilities.
+type HistoryAPI struct {
+	client *Client
+}
+
+// Locations lists the locations generated within the given time interval.
+//
+// If the Hub does not implement the endpoint, the alternate endpoint of the locations of
+// each trackable is used instead, missing the locations of providers without trackables.
+// Locations of several trackables are returned once, by provider and generation time.
+// This is not a client-side computation: Hubs without any history API fail either way.
+func (c *HistoryAPI) Locations(ctx context.Context, from time.Time, to time.Time) ([]Location, error) {
+	if err := c.client.require(ctx, FeatureHistory); err != nil {
+		return nil, err
+	}
+
+	requestPath := "/history/locations"
+
+	locations, err := sendRequestParseResponseList[Location](
+		ctx,
+		c.client,
+		http.MethodGet,
+		requestPath,
+		nil, // request body
+		historyParameters(from, to),
+		nil, // request headers
+	)
+	if !isUnavailable(err) {
+		if err == nil {
+			c.client.available(FeatureHistory)
+		}
+		return locations, err
+	}
+
+	trackables, terr := c.client.Trackables.IDs(ctx)
+	if terr != nil {
+		return nil, terr
+	}
+
+	// locations of several trackables are listed once per trackable
+	type locationKey struct {
+		providerID string
+		generated  time.Time
+	}
+	seen := make(map[locationKey]bool)
+
+	locations = nil
+	budget := NewBudget(ctx, len(trackables), 1)
+	budget.SetClock(c.client.timeSource())
+	for _, id := range trackables {
+		tctx, cancel := budget.Next(ctx)
+		l, terr := c.TrackableLocations(tctx, id, from, to)
+		cancel()
+		if terr != nil {
+			return nil, terr
+		}
+		for _, loc := range l {
+			if loc.TimestampGenerated != nil {
+				key := locationKey{providerID: loc.ProviderID, generated: loc.TimestampGenerated.UTC()}
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+			}
+			locations = append(locations, loc)
+		}
+	}
+
+	c.client.degrade(ctx, FeatureHistory, err, "alternate endpoint: locations listed by trackable")
+	return locations, nil
+}
+
+// TrackableLocations lists the locations of a trackable generated within the given time interval.
+func (c *HistoryAPI) TrackableLocations(ctx context.Context, id uuid.UUID, from time.Time, to time.Time) ([]Location, error) {
+	if err := c.client.require(ctx, FeatureHistory); err != nil {
+		return nil, err
+	}
+
+	requestPath := "/history/trackables/" + id.String() + "/locations"
+
+	return sendRequestParseResponseList[Location](
+		ctx,
+		c.client,
+		http.MethodGet,
+		requestPath,
+		nil, // request body
+		historyParameters(from, to),
+		nil, // request headers
+	)
+}
+
+// FenceEvents lists the fence events that occurred within the given time interval.
+//
+// If the Hub does not support the fence event history, the fence events are derived
+// from the location history and the current fences. Trackables inside a fence at their
+// first location of the interval are then entering it.
+func (c *HistoryAPI) FenceEvents(ctx context.Context, from time.Time, to time.Time) ([]FenceEvent, error) {
+	requestPath := "/history/fence_events"
+
+	err := c.client.require(ctx, FeatureFenceEventHistory)
+	if err == nil {
+		var events []FenceEvent
+		events, err = sendRequestParseResponseList[FenceEvent](
+			ctx,
+			c.client,
+			http.MethodGet,
+			requestPath,
+			nil, // request body
+			historyParameters(from, to),
+			nil, // request headers
+		)
+		if !isUnavailable(err) {
+			if err == nil {
+				c.client.available(FeatureFenceEventHistory)
+			}
+			return events, err
+		}
+	}
+
+	locations, lerr := c.Locations(ctx, from, to)
+	if lerr != nil {
+		return nil, lerr
+	}
+	fences, ferr := c.client.Fences.List(ctx)
+	if ferr != nil {
+		return nil, ferr
+	}
+
+	c.client.degrade(ctx, FeatureFenceEventHistory, err, "client-side: fence events derived from the location history")
+	return fenceEventsOf(fences, locations), nil
+}
+
+// historyParameters returns the query parameters of a history time interval.
+func historyParameters(from time.Time, to time.Time) url.Values {
+	params := make(url.Values)
+	params.Set("from", from.UTC().Format(time.RFC3339Nano))
+	params.Set("to", to.UTC().Format(time.RFC3339Nano))
+	return params
+}
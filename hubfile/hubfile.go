@@ -0,0 +1,368 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package hubfile implements the declarative configuration of an omlox™ Hub:
+// a single YAML document describing its zones, fences, trackables and location
+// providers, applied to the Hub by creating, updating and optionally pruning
+// resources until the Hub matches the document.
+//
+// A hubfile looks like:
+//
+//	version: 1
+//	labels:
+//	  site: plant-1
+//	zones:
+//	  - name: hall
+//	    type: uwb
+//	providers:
+//	  - id: "ac:23:3f:ac:a3:87"
+//	    type: uwb
+//	    name: forklift tag
+//	trackables:
+//	  - name: forklift
+//	    type: virtual
+//	    location_providers: ["ac:23:3f:ac:a3:87"]
+//	    labels:
+//	      team: logistics
+//
+// Resources are written as in the omlox™ API. Zones, fences and trackables without
+// id get a name-based id (see omlox.NameBasedIDs), so applying the same hubfile
+// twice is idempotent; location providers require their id. Labels are stored
+// in the "labels" property of the resources, with the resource labels taking
+// precedence over the labels of the hubfile.
+//
+// Resources applied from a hubfile are marked as managed by its owner in their
+// "managed_by" property, "hubfile" unless the hubfile sets its owner. Pruning only
+// deletes the resources managed by the same owner, never the resources created
+// by operators or by other hubfiles.
+package hubfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go"
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the hubfile format version.
+const Version = 1
+
+// labelsProperty is the property of the resources holding their labels.
+const labelsProperty = "labels"
+
+// managedProperty is the property of the resources holding the owner managing them.
+const managedProperty = "managed_by"
+
+// DefaultOwner is the owner of the hubfiles which don't set one.
+const DefaultOwner = "hubfile"
+
+// File is a hubfile, the desired configuration of a Hub.
+type File struct {
+	// Owner managing the resources of the hubfile. Default: DefaultOwner
+	Owner string
+
+	// Labels of every resource of the hubfile.
+	Labels map[string]string
+
+	Zones      []omlox.Zone
+	Providers  []omlox.LocationProvider
+	Fences     []omlox.Fence
+	Trackables []omlox.Trackable
+}
+
+// document is the YAML layout of a hubfile. Resources are kept generic until
+// their labels are merged into the properties.
+type document struct {
+	Version    int               `yaml:"version"`
+	Owner      string            `yaml:"owner"`
+	Labels     map[string]string `yaml:"labels"`
+	Zones      []map[string]any  `yaml:"zones"`
+	Providers  []map[string]any  `yaml:"providers"`
+	Fences     []map[string]any  `yaml:"fences"`
+	Trackables []map[string]any  `yaml:"trackables"`
+}
+
+// Load reads a hubfile.
+func Load(r io.Reader) (*File, error) {
+	var doc document
+
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid hubfile: %w", err)
+	}
+
+	if doc.Version != Version {
+		return nil, fmt.Errorf("unsupported hubfile version %d: expected %d", doc.Version, Version)
+	}
+
+	f := &File{Owner: doc.Owner, Labels: doc.Labels}
+
+	var err error
+	if f.Zones, err = decodeResources[omlox.Zone](KindZone, doc.Zones, f); err != nil {
+		return nil, err
+	}
+	if f.Providers, err = decodeResources[omlox.LocationProvider](KindProvider, doc.Providers, f); err != nil {
+		return nil, err
+	}
+	if f.Fences, err = decodeResources[omlox.Fence](KindFence, doc.Fences, f); err != nil {
+		return nil, err
+	}
+	if f.Trackables, err = decodeResources[omlox.Trackable](KindTrackable, doc.Trackables, f); err != nil {
+		return nil, err
+	}
+
+	if err := f.assignIDs(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// LoadFile reads a hubfile from the named file.
+func LoadFile(name string) (*File, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Load(file)
+}
+
+// decodeResources decodes the resources of a kind, with the labels and the
+// owner of the hubfile merged into their properties.
+func decodeResources[T any](kind Kind, items []map[string]any, f *File) ([]T, error) {
+	resources := make([]T, 0, len(items))
+
+	for i, item := range items {
+		if err := mergeProperties(item, f); err != nil {
+			return nil, fmt.Errorf("%s %d: %w", kind, i, err)
+		}
+
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, fmt.Errorf("%s %d: %w", kind, i, err)
+		}
+
+		var r T
+		if err := json.Unmarshal(data, &r); err != nil {
+			return nil, fmt.Errorf("%s %d: %w", kind, i, err)
+		}
+
+		resources = append(resources, r)
+	}
+
+	return resources, nil
+}
+
+// mergeProperties moves the labels of a resource into its properties, along with
+// the labels of the hubfile it doesn't override.
+func mergeProperties(item map[string]any, f *File) error {
+	merged := make(map[string]any, len(f.Labels))
+	for k, v := range f.Labels {
+		merged[k] = v
+	}
+
+	if own, ok := item[labelsProperty]; ok {
+		m, ok := own.(map[string]any)
+		if !ok {
+			return fmt.Errorf("labels must be a mapping")
+		}
+		for k, v := range m {
+			merged[k] = v
+		}
+		delete(item, labelsProperty)
+	}
+
+	if len(merged) == 0 {
+		return nil
+	}
+
+	props, _ := item["properties"].(map[string]any)
+	if props == nil {
+		if _, ok := item["properties"]; ok {
+			return fmt.Errorf("properties must be a mapping")
+		}
+		props = make(map[string]any)
+	}
+	props[labelsProperty] = merged
+	item["properties"] = props
+
+	return nil
+}
+
+// assignIDs sets the name-based ids of the resources without id, and checks
+// that ids are unique for each kind.
+func (f *File) assignIDs() error {
+	ids := omlox.NameBasedIDs(omlox.IDNamespace)
+
+	assign := func(kind Kind, id *uuid.UUID, name string) error {
+		if *id != uuid.Nil {
+			return nil
+		}
+		generated, err := ids(name)
+		if err != nil {
+			return fmt.Errorf("%s without id: %w", kind, err)
+		}
+		*id = generated
+		return nil
+	}
+
+	for i := range f.Zones {
+		if err := assign(KindZone, &f.Zones[i].ID, f.Zones[i].Name); err != nil {
+			return err
+		}
+	}
+	for i := range f.Fences {
+		if err := assign(KindFence, &f.Fences[i].ID, f.Fences[i].Name); err != nil {
+			return err
+		}
+	}
+	for i := range f.Trackables {
+		if err := assign(KindTrackable, &f.Trackables[i].ID, f.Trackables[i].Name); err != nil {
+			return err
+		}
+	}
+	for _, p := range f.Providers {
+		if p.ID == "" {
+			return fmt.Errorf("%s %q without id: provider ids are required", KindProvider, p.Name)
+		}
+	}
+
+	resources, err := f.resources()
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[Kind]map[string]bool)
+	for _, r := range resources {
+		if seen[r.Kind] == nil {
+			seen[r.Kind] = make(map[string]bool)
+		}
+		if seen[r.Kind][r.ID] {
+			return fmt.Errorf("duplicate %s %s", r.Kind, r.ID)
+		}
+		seen[r.Kind][r.ID] = true
+	}
+
+	return nil
+}
+
+// owner returns the owner managing the resources of the hubfile.
+func (f *File) owner() string {
+	if f.Owner == "" {
+		return DefaultOwner
+	}
+	return f.Owner
+}
+
+// resources returns the resources of the hubfile, in dependency order, marked
+// as managed by the owner of the hubfile.
+func (f *File) resources() ([]Resource, error) {
+	var resources []Resource
+
+	owner := f.owner()
+	add := func(kind Kind, id, name string, v any) error {
+		body, err := withOwner(v, owner)
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", kind, id, err)
+		}
+		resources = append(resources, Resource{Kind: kind, ID: id, Name: name, Owner: owner, Body: body})
+		return nil
+	}
+
+	for _, z := range f.Zones {
+		if err := add(KindZone, z.ID.String(), z.Name, z); err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range f.Providers {
+		if err := add(KindProvider, p.ID, p.Name, p); err != nil {
+			return nil, err
+		}
+	}
+	for _, fe := range f.Fences {
+		if err := add(KindFence, fe.ID.String(), fe.Name, fe); err != nil {
+			return nil, err
+		}
+	}
+	for _, t := range f.Trackables {
+		if err := add(KindTrackable, t.ID.String(), t.Name, t); err != nil {
+			return nil, err
+		}
+	}
+
+	return resources, nil
+}
+
+// normalize re-encodes a resource body through its type, so bodies given by
+// the Hub and by the hubfile compare equal when they describe the same resource.
+// Properties are re-encoded too, with sorted keys.
+func normalize[T any](body json.RawMessage) (json.RawMessage, error) {
+	var r T
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(v)
+}
+
+// withOwner encodes a resource, with its "managed_by" property set to the owner.
+func withOwner(v any, owner string) (json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var r map[string]json.RawMessage
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+
+	props := make(map[string]json.RawMessage)
+	if raw, ok := r["properties"]; ok && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &props); err != nil {
+			return nil, fmt.Errorf("properties must be an object: %w", err)
+		}
+	}
+
+	if props[managedProperty], err = json.Marshal(owner); err != nil {
+		return nil, err
+	}
+	if r["properties"], err = json.Marshal(props); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(r)
+}
+
+// ManagedBy returns the owner managing a resource, given its properties, or an
+// empty string for resources not applied from a hubfile.
+func ManagedBy(properties json.RawMessage) string {
+	var props struct {
+		ManagedBy string `json:"managed_by"`
+	}
+	if len(properties) == 0 || json.Unmarshal(properties, &props) != nil {
+		return ""
+	}
+	return props.ManagedBy
+}
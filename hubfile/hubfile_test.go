@@ -0,0 +1,113 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package hubfile
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+func TestLoadFile(t *testing.T) {
+	f, err := LoadFile("testdata/hub.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(f.Zones) != 1 || len(f.Providers) != 1 || len(f.Fences) != 1 || len(f.Trackables) != 1 {
+		t.Fatalf("unexpected resources: %+v", f)
+	}
+
+	// resources without id get name-based ids
+	want, _ := omlox.NameBasedIDs(omlox.IDNamespace)("hall")
+	if f.Zones[0].ID != want {
+		t.Errorf("expected zone id %v, got %v", want, f.Zones[0].ID)
+	}
+	if f.Trackables[0].ID != uuid.MustParse("2b5f4a7c-1d1e-4c7e-9d27-5f3b2f1a0001") {
+		t.Errorf("expected given trackable id, got %v", f.Trackables[0].ID)
+	}
+	if f.Fences[0].Region == nil {
+		t.Errorf("expected fence region")
+	}
+
+	// resource labels override the hubfile labels, and keep the other properties
+	var props struct {
+		Asset  string            `json:"asset"`
+		Labels map[string]string `json:"labels"`
+	}
+	if err := json.Unmarshal(f.Trackables[0].Properties, &props); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if props.Asset != "A-1001" || props.Labels["site"] != "plant-2" || props.Labels["team"] != "logistics" {
+		t.Errorf("unexpected trackable properties %s", f.Trackables[0].Properties)
+	}
+	if string(f.Zones[0].Properties) != `{"labels":{"site":"plant-1"}}` {
+		t.Errorf("unexpected zone properties %s", f.Zones[0].Properties)
+	}
+
+	// applied resources are marked as managed by the owner of the hubfile
+	resources, err := f.resources()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, r := range resources {
+		var body struct {
+			Properties json.RawMessage `json:"properties"`
+		}
+		if err := json.Unmarshal(r.Body, &body); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if owner := ManagedBy(body.Properties); owner != DefaultOwner {
+			t.Errorf("expected %s %s to be managed by %q, got %q", r.Kind, r.ID, DefaultOwner, owner)
+		}
+	}
+}
+
+func TestLoadOwner(t *testing.T) {
+	f, err := Load(strings.NewReader("version: 1\nowner: plant-1\nzones:\n  - {name: hall, type: uwb}"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resources, err := f.resources()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var body struct {
+		Properties json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(resources[0].Body, &body); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if owner := ManagedBy(body.Properties); owner != "plant-1" || resources[0].Owner != "plant-1" {
+		t.Errorf("expected resource managed by plant-1, got %s", resources[0].Body)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "missing version", input: "zones: []"},
+		{name: "unknown version", input: "version: 2"},
+		{name: "unknown field", input: "version: 1\nanchors: []"},
+		{name: "provider without id", input: "version: 1\nproviders:\n  - type: uwb"},
+		{name: "zone without id and name", input: "version: 1\nzones:\n  - type: uwb"},
+		{name: "duplicate names", input: "version: 1\nzones:\n  - {name: hall, type: uwb}\n  - {name: hall, type: uwb}"},
+		{name: "invalid labels", input: "version: 1\nzones:\n  - {name: hall, type: uwb, labels: [a]}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(strings.NewReader(tt.input)); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
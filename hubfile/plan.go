@@ -0,0 +1,243 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package hubfile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+// Kind is a kind of Hub resource.
+type Kind string
+
+// Defines values for Kind, in dependency order.
+const (
+	KindZone      Kind = "zone"
+	KindProvider  Kind = "provider"
+	KindFence     Kind = "fence"
+	KindTrackable Kind = "trackable"
+)
+
+// kinds are the resource kinds in dependency order: resources are created
+// in this order, and deleted in the reverse order.
+var kinds = []Kind{KindZone, KindProvider, KindFence, KindTrackable}
+
+// path returns the collection path of the kind.
+func (k Kind) path() string {
+	return "/" + string(k) + "s"
+}
+
+// normalize re-encodes a resource body of the kind.
+func (k Kind) normalize(body json.RawMessage) (json.RawMessage, error) {
+	switch k {
+	case KindZone:
+		return normalize[omlox.Zone](body)
+	case KindProvider:
+		return normalize[omlox.LocationProvider](body)
+	case KindFence:
+		return normalize[omlox.Fence](body)
+	case KindTrackable:
+		return normalize[omlox.Trackable](body)
+	}
+	return nil, fmt.Errorf("unknown resource kind %q", k)
+}
+
+// Resource is a Hub resource of any kind.
+type Resource struct {
+	Kind Kind
+	ID   string
+	Name string
+
+	// Owner managing the resource, if applied from a hubfile.
+	Owner string
+
+	// Body is the JSON encoding of the resource.
+	Body json.RawMessage
+}
+
+// Action is a change applied to a resource.
+type Action string
+
+// Defines values for Action.
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Change is a change of a resource to match the hubfile.
+type Change struct {
+	Action Action
+	Kind   Kind
+	ID     string
+	Name   string
+
+	// Current is the resource in the Hub, nil for creations.
+	Current json.RawMessage
+
+	// Desired is the resource in the hubfile, nil for deletions.
+	Desired json.RawMessage
+}
+
+// Plan is the ordered list of changes making the Hub match a hubfile.
+type Plan struct {
+	Changes []Change
+}
+
+// Options configure how a plan is made.
+type Options struct {
+	// Prune deletes the resources of the Hub missing from the hubfile, among
+	// the resources managed by the owner of the hubfile.
+	Prune bool
+}
+
+// Fetch lists the resources of the Hub, in dependency order.
+func Fetch(ctx context.Context, c *omlox.Client) ([]Resource, error) {
+	var resources []Resource
+
+	for _, kind := range kinds {
+		var bodies []json.RawMessage
+		if err := c.Do(ctx, http.MethodGet, kind.path()+"/summary", nil, &bodies); err != nil {
+			return nil, fmt.Errorf("list %ss: %w", kind, err)
+		}
+
+		for _, body := range bodies {
+			r, err := newResource(kind, body)
+			if err != nil {
+				return nil, err
+			}
+			resources = append(resources, r)
+		}
+	}
+
+	return resources, nil
+}
+
+// newResource returns the resource of a kind with the given body.
+func newResource(kind Kind, body json.RawMessage) (Resource, error) {
+	var meta struct {
+		ID         string          `json:"id"`
+		Name       string          `json:"name"`
+		Properties json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(body, &meta); err != nil {
+		return Resource{}, fmt.Errorf("invalid %s: %w", kind, err)
+	}
+
+	normalized, err := kind.normalize(body)
+	if err != nil {
+		return Resource{}, fmt.Errorf("invalid %s %s: %w", kind, meta.ID, err)
+	}
+
+	return Resource{Kind: kind, ID: meta.ID, Name: meta.Name, Owner: ManagedBy(meta.Properties), Body: normalized}, nil
+}
+
+// Diff returns the plan changing the current resources into the resources of
+// the hubfile. Creations and updates come first, in dependency order, followed
+// by deletions in the reverse order. Resources of the hubfile created by other
+// means are updated, and managed from then on.
+func Diff(current []Resource, f *File, opts Options) (*Plan, error) {
+	desired, err := f.resources()
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		kind Kind
+		id   string
+	}
+
+	owner := f.owner()
+
+	existing := make(map[key]Resource, len(current))
+	for _, r := range current {
+		existing[key{r.Kind, r.ID}] = r
+	}
+
+	wanted := make(map[key]bool, len(desired))
+	plan := &Plan{}
+
+	for _, kind := range kinds {
+		for _, d := range desired {
+			if d.Kind != kind {
+				continue
+			}
+
+			body, err := kind.normalize(d.Body)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", kind, d.ID, err)
+			}
+
+			k := key{kind, d.ID}
+			wanted[k] = true
+
+			cur, ok := existing[k]
+			switch {
+			case !ok:
+				plan.Changes = append(plan.Changes, Change{Action: ActionCreate, Kind: kind, ID: d.ID, Name: d.Name, Desired: body})
+			case string(cur.Body) != string(body):
+				plan.Changes = append(plan.Changes, Change{Action: ActionUpdate, Kind: kind, ID: d.ID, Name: d.Name, Current: cur.Body, Desired: body})
+			}
+		}
+	}
+
+	if opts.Prune {
+		for i := len(kinds) - 1; i >= 0; i-- {
+			for _, cur := range current {
+				if cur.Kind != kinds[i] || cur.Owner != owner || wanted[key{cur.Kind, cur.ID}] {
+					continue
+				}
+				plan.Changes = append(plan.Changes, Change{Action: ActionDelete, Kind: cur.Kind, ID: cur.ID, Name: cur.Name, Current: cur.Body})
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// Make fetches the resources of the Hub and returns the plan making it match the hubfile.
+func Make(ctx context.Context, c *omlox.Client, f *File, opts Options) (*Plan, error) {
+	current, err := Fetch(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	return Diff(current, f, opts)
+}
+
+// Empty reports whether the Hub already matches the hubfile.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Apply applies the changes in order, stopping at the first failure.
+// The applied callback, if not nil, is called after each applied change.
+func (p *Plan) Apply(ctx context.Context, c *omlox.Client, applied func(Change)) error {
+	for _, change := range p.Changes {
+		if err := change.apply(ctx, c); err != nil {
+			return fmt.Errorf("%s %s %s: %w", change.Action, change.Kind, change.ID, err)
+		}
+		if applied != nil {
+			applied(change)
+		}
+	}
+
+	return nil
+}
+
+func (ch Change) apply(ctx context.Context, c *omlox.Client) error {
+	switch ch.Action {
+	case ActionCreate:
+		return c.Do(ctx, http.MethodPost, ch.Kind.path(), ch.Desired, nil)
+	case ActionUpdate:
+		return c.Do(ctx, http.MethodPut, ch.Kind.path()+"/"+ch.ID, ch.Desired, nil)
+	case ActionDelete:
+		return c.Do(ctx, http.MethodDelete, ch.Kind.path()+"/"+ch.ID, nil, nil)
+	}
+	return fmt.Errorf("unknown action %q", ch.Action)
+}
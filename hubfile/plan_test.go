@@ -0,0 +1,127 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package hubfile
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/omloxtest"
+)
+
+// summary is a compact representation of the changes of a plan.
+func summary(p *Plan) string {
+	var s []string
+	for _, c := range p.Changes {
+		s = append(s, string(c.Action)+" "+string(c.Kind)+" "+labelOf(c))
+	}
+	return strings.Join(s, ", ")
+}
+
+func labelOf(c Change) string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.ID
+}
+
+func TestApply(t *testing.T) {
+	srv := omloxtest.NewServer()
+	defer srv.Close()
+
+	c, err := omlox.New(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// a resource of the hub missing from the hubfile
+	if _, err := c.Trackables.Create(ctx, omlox.Trackable{Name: "legacy", Type: omlox.TrackableTypeVirtual}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	f, err := LoadFile("testdata/hub.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	plan, err := Make(ctx, c, f, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// creations follow the dependency order, and nothing is deleted without prune
+	want := "create zone hall, create provider forklift tag, create fence dock, create trackable forklift"
+	if got := summary(plan); got != want {
+		t.Fatalf("expected plan %q, got %q", want, got)
+	}
+
+	var applied int
+	if err := plan.Apply(ctx, c, func(Change) { applied++ }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if applied != len(plan.Changes) {
+		t.Errorf("expected %d applied changes, got %d", len(plan.Changes), applied)
+	}
+
+	// the hub matches the hubfile, except for the resources to prune
+	if plan, err = Make(ctx, c, f, Options{}); err != nil || !plan.Empty() {
+		t.Fatalf("expected empty plan, got %q (%v)", summary(plan), err)
+	}
+
+	// resources removed from the hubfile are pruned, but not the resources it doesn't manage
+	f.Trackables[0].Name = "forklift-01"
+	f.Providers = nil
+	if plan, err = Make(ctx, c, f, Options{Prune: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := summary(plan), "update trackable forklift-01, delete provider forklift tag"; got != want {
+		t.Fatalf("expected plan %q, got %q", want, got)
+	}
+	if plan.Changes[0].Current == nil || plan.Changes[0].Desired == nil {
+		t.Errorf("expected both versions of the updated resource")
+	}
+
+	if err := plan.Apply(ctx, c, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan, err = Make(ctx, c, f, Options{Prune: true}); err != nil || !plan.Empty() {
+		t.Fatalf("expected empty plan after prune, got %q (%v)", summary(plan), err)
+	}
+
+	trackables, err := c.Trackables.List(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(trackables) != 2 {
+		t.Errorf("expected the unmanaged trackable to be kept, got %d trackables", len(trackables))
+	}
+}
+
+func TestDiffPruneOrder(t *testing.T) {
+	current := []Resource{
+		{Kind: KindZone, ID: "z1", Owner: DefaultOwner, Body: []byte(`{}`)},
+		{Kind: KindFence, ID: "f1", Owner: DefaultOwner, Body: []byte(`{}`)},
+		{Kind: KindTrackable, ID: "t1", Owner: DefaultOwner, Body: []byte(`{}`)},
+		{Kind: KindProvider, ID: "p1", Owner: DefaultOwner, Body: []byte(`{}`)},
+
+		// resources created by operators or other hubfiles are never pruned
+		{Kind: KindTrackable, ID: "t2", Body: []byte(`{}`)},
+		{Kind: KindTrackable, ID: "t3", Owner: "other", Body: []byte(`{}`)},
+	}
+
+	plan, err := Diff(current, &File{}, Options{Prune: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// dependent resources are deleted first
+	want := "delete trackable t1, delete fence f1, delete provider p1, delete zone z1"
+	if got := summary(plan); got != want {
+		t.Errorf("expected plan %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+// IDStrategy generates the id of a resource created without one, given the
+// name of the resource.
+type IDStrategy func(name string) (uuid.UUID, error)
+
+// IDNamespace is the default namespace of name-based ids.
+var IDNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://github.com/wavecomtech/omlox-client-go"))
+
+// ErrIDRequiresName is returned by name-based strategies for resources without name.
+var ErrIDRequiresName = errors.New("name-based id requires a resource name")
+
+// RandomIDs generates random ids (UUID version 4).
+func RandomIDs() IDStrategy {
+	return func(string) (uuid.UUID, error) {
+		return uuid.NewRandom()
+	}
+}
+
+// TimeOrderedIDs generates ids ordered by their creation time, with millisecond
+// precision, taken from the clock (UUID version 7). A nil clock is the system clock.
+//
+// Time-ordered ids keep resources sorted by creation when listed by id.
+func TimeOrderedIDs(clk clock.Clock) IDStrategy {
+	clk = clock.Or(clk)
+
+	return func(string) (uuid.UUID, error) {
+		var id uuid.UUID
+		if _, err := rand.Read(id[6:]); err != nil {
+			return uuid.Nil, err
+		}
+
+		// 48 bits of unix milliseconds, followed by the version and variant bits
+		var ts [8]byte
+		binary.BigEndian.PutUint64(ts[:], uint64(clk.Now().UnixMilli()))
+		copy(id[:6], ts[2:])
+
+		id[6] = id[6]&0x0f | 0x70
+		id[8] = id[8]&0x3f | 0x80
+
+		return id, nil
+	}
+}
+
+// NameBasedIDs generates ids from the name of the resources in the namespace
+// (UUID version 5), so the same name always gets the same id. A nil namespace
+// is IDNamespace. Resources without name fail with ErrIDRequiresName.
+func NameBasedIDs(namespace uuid.UUID) IDStrategy {
+	if namespace == uuid.Nil {
+		namespace = IDNamespace
+	}
+
+	return func(name string) (uuid.UUID, error) {
+		if name == "" {
+			return uuid.Nil, ErrIDRequiresName
+		}
+		return uuid.NewSHA1(namespace, []byte(name)), nil
+	}
+}
+
+// ParseIDStrategy returns the strategy of a name: "v4" or "random", "v7" or
+// "time", and "v5" or "name" in the namespace. An empty name, or "none",
+// returns a nil strategy, leaving id generation to the Hub.
+func ParseIDStrategy(name string, namespace uuid.UUID, clk clock.Clock) (IDStrategy, error) {
+	switch name {
+	case "", "none":
+		return nil, nil
+	case "v4", "random":
+		return RandomIDs(), nil
+	case "v7", "time":
+		return TimeOrderedIDs(clk), nil
+	case "v5", "name":
+		return NameBasedIDs(namespace), nil
+	default:
+		return nil, fmt.Errorf("unknown id strategy %q: expected none, v4, v7 or v5", name)
+	}
+}
+
+// generateID sets the id of a resource created without one, using the id strategy
+// of the client. It reports whether an id was generated.
+// Location provider ids are set to the string form of the generated UUID.
+func (c *Client) generateID(resource any) (bool, error) {
+	strategy := c.configuration.IDStrategy
+	if strategy == nil {
+		return false, nil
+	}
+
+	switch r := resource.(type) {
+	case *Trackable:
+		if r.ID != uuid.Nil {
+			return false, nil
+		}
+		id, err := strategy(r.Name)
+		if err != nil {
+			return false, err
+		}
+		r.ID = id
+	case *Fence:
+		if r.ID != uuid.Nil {
+			return false, nil
+		}
+		id, err := strategy(r.Name)
+		if err != nil {
+			return false, err
+		}
+		r.ID = id
+	case *LocationProvider:
+		if r.ID != "" {
+			return false, nil
+		}
+		id, err := strategy(r.Name)
+		if err != nil {
+			return false, err
+		}
+		r.ID = id.String()
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
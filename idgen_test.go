@@ -0,0 +1,152 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+func TestIDStrategies(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name     string
+		strategy IDStrategy
+		version  uuid.Version
+	}{
+		{name: "random", strategy: RandomIDs(), version: 4},
+		{name: "time ordered", strategy: TimeOrderedIDs(clk), version: 7},
+		{name: "name based", strategy: NameBasedIDs(uuid.Nil), version: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := tt.strategy("forklift")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if id.Version() != tt.version || id.Variant() != uuid.RFC4122 {
+				t.Errorf("expected version %d RFC 4122 id, got %v (version %d, variant %v)", tt.version, id, id.Version(), id.Variant())
+			}
+		})
+	}
+}
+
+func TestTimeOrderedIDs(t *testing.T) {
+	epoch := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(epoch)
+	strategy := TimeOrderedIDs(clk)
+
+	first, _ := strategy("")
+	clk.Advance(time.Millisecond)
+	second, _ := strategy("")
+
+	if first.String() >= second.String() {
+		t.Errorf("expected ids ordered by time, got %v >= %v", first, second)
+	}
+
+	// the first 48 bits are the unix milliseconds
+	ms := int64(first[0])<<40 | int64(first[1])<<32 | int64(first[2])<<24 | int64(first[3])<<16 | int64(first[4])<<8 | int64(first[5])
+	if ms != epoch.UnixMilli() {
+		t.Errorf("expected timestamp %d, got %d", epoch.UnixMilli(), ms)
+	}
+}
+
+func TestNameBasedIDs(t *testing.T) {
+	strategy := NameBasedIDs(uuid.Nil)
+
+	a, _ := strategy("forklift")
+	b, _ := strategy("forklift")
+	if a != b {
+		t.Errorf("expected the same id for the same name, got %v and %v", a, b)
+	}
+
+	other, _ := NameBasedIDs(uuid.NameSpaceDNS)("forklift")
+	if a == other {
+		t.Errorf("expected namespaces to give different ids")
+	}
+
+	if _, err := strategy(""); !errors.Is(err, ErrIDRequiresName) {
+		t.Errorf("expected ErrIDRequiresName, got %v", err)
+	}
+}
+
+func TestParseIDStrategy(t *testing.T) {
+	for _, name := range []string{"", "none"} {
+		if s, err := ParseIDStrategy(name, uuid.Nil, nil); err != nil || s != nil {
+			t.Errorf("ParseIDStrategy(%q) = %v, %v, expected no strategy", name, s, err)
+		}
+	}
+	for _, name := range []string{"v4", "random", "v7", "time", "v5", "name"} {
+		if s, err := ParseIDStrategy(name, uuid.Nil, nil); err != nil || s == nil {
+			t.Errorf("ParseIDStrategy(%q) = %v, %v, expected a strategy", name, s, err)
+		}
+	}
+	if _, err := ParseIDStrategy("v1", uuid.Nil, nil); err == nil {
+		t.Errorf("expected error on unknown strategy")
+	}
+}
+
+func TestCreateGeneratesID(t *testing.T) {
+	var created []Trackable
+
+	httpClient := &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			var trackable Trackable
+			if err := json.NewDecoder(r.Body).Decode(&trackable); err != nil {
+				return nil, err
+			}
+			created = append(created, trackable)
+
+			body, _ := json.Marshal(trackable)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(string(body))),
+			}, nil
+		}),
+	}
+
+	c, err := New("http://localhost:8081/v2", WithHTTPClient(httpClient), WithIDStrategy(NameBasedIDs(uuid.Nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	want, _ := NameBasedIDs(uuid.Nil)("forklift")
+
+	rt, err := c.Trackables.Create(ctx, Trackable{Name: "forklift", Type: TrackableTypeOmlox})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rt.ID != want || created[0].ID != want {
+		t.Errorf("expected generated id %v, got %v", want, rt.ID)
+	}
+
+	// given ids are kept
+	given := uuid.New()
+	if rt, err = c.Trackables.Create(ctx, Trackable{ID: given, Name: "forklift", Type: TrackableTypeOmlox}); err != nil {
+		t.Fatal(err)
+	}
+	if rt.ID != given {
+		t.Errorf("expected given id %v, got %v", given, rt.ID)
+	}
+
+	// strategy errors fail before sending the request
+	if _, err := c.Trackables.Create(ctx, Trackable{Type: TrackableTypeOmlox}); !errors.Is(err, ErrIDRequiresName) {
+		t.Errorf("expected ErrIDRequiresName, got %v", err)
+	}
+	if len(created) != 2 {
+		t.Errorf("expected 2 requests, got %d", len(created))
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package geo holds the WGS84 approximations shared by the packages of the module,
+// so the same positions are the same distance apart in all of them.
+package geo
+
+import (
+	"math"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+// EarthRadius is the WGS84 semi-major axis in meters.
+const EarthRadius = 6378137
+
+// MetersPerDegree is the length in meters of a degree of latitude, and of a degree
+// of longitude at the equator.
+const MetersPerDegree = EarthRadius * math.Pi / 180
+
+// Scale returns the length in meters of a degree of longitude and of latitude around
+// the given latitude, with an equirectangular approximation.
+func Scale(lat float64) (float64, float64) {
+	return MetersPerDegree * math.Cos(lat*math.Pi/180), MetersPerDegree
+}
+
+// Project projects a (longitude, latitude) point to the plane tangent at the origin,
+// in meters east and north of it.
+func Project(origin, p geometry.Point) geometry.Point {
+	sx, sy := Scale(origin.Y)
+	return geometry.Point{X: (p.X - origin.X) * sx, Y: (p.Y - origin.Y) * sy}
+}
+
+// Unproject converts a point of the plane tangent at the origin back to
+// (longitude, latitude).
+func Unproject(origin, p geometry.Point) geometry.Point {
+	sx, sy := Scale(origin.Y)
+	return geometry.Point{X: origin.X + p.X/sx, Y: origin.Y + p.Y/sy}
+}
+
+// Distance returns the great-circle distance in meters between two
+// (longitude, latitude) points.
+func Distance(a, b geometry.Point) float64 {
+	rad := math.Pi / 180
+	lat1, lat2 := a.Y*rad, b.Y*rad
+	dlat, dlon := (b.Y-a.Y)*rad, (b.X-a.X)*rad
+
+	h := math.Sin(dlat/2)*math.Sin(dlat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dlon/2)*math.Sin(dlon/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
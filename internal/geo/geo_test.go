@@ -0,0 +1,35 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package geo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+func TestProject(t *testing.T) {
+	origin := geometry.Point{X: 7.8, Y: 48}
+	p := geometry.Point{X: 7.801, Y: 48.001}
+
+	q := Project(origin, p)
+	if got := Unproject(origin, q); math.Abs(got.X-p.X) > 1e-12 || math.Abs(got.Y-p.Y) > 1e-12 {
+		t.Errorf("expected %v, got %v", p, got)
+	}
+
+	// the projection and the great-circle distance agree at the scale of a site
+	if d := Distance(origin, p); math.Abs(math.Hypot(q.X, q.Y)-d) > 0.01 {
+		t.Errorf("expected a projected distance of %v, got %v", d, math.Hypot(q.X, q.Y))
+	}
+}
+
+func TestDistance(t *testing.T) {
+	a := geometry.Point{X: 7.8, Y: 48}
+	b := geometry.Point{X: 7.8, Y: 49}
+
+	if d := Distance(a, b); math.Abs(d-MetersPerDegree) > 1e-6 {
+		t.Errorf("expected a degree of latitude to be %v, got %v", MetersPerDegree, d)
+	}
+}
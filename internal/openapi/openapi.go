@@ -0,0 +1,80 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package openapi holds the models and endpoint stubs generated from the
+// omlox™ Hub OpenAPI specification, on top of which the client is hand-written.
+//
+// The specification at api/omlox-hub.openapi.yaml is maintained by hand after the
+// omlox™ Hub specification, and covers the endpoints of the client. The requests of
+// the client are checked against it by tests. Run 'go generate ./internal/openapi'
+// after changing it.
+package openapi
+
+//go:generate go run ../tools/openapigen -spec ../../api/omlox-hub.openapi.yaml -pkg openapi -o zz_generated.go
+
+import (
+	"strings"
+)
+
+// Operation is an endpoint stub of the specification.
+type Operation struct {
+	// ID is the operation id, if any.
+	ID string
+
+	// Method is the HTTP method of the endpoint.
+	Method string
+
+	// Path is the path template of the endpoint, e.g. "/trackables/{trackableId}".
+	Path string
+
+	// Summary is a short description of the operation.
+	Summary string
+}
+
+// Expand returns the operation path with its parameters replaced by the given values.
+func (o Operation) Expand(params map[string]string) string {
+	path := o.Path
+	for k, v := range params {
+		path = strings.ReplaceAll(path, "{"+k+"}", v)
+	}
+	return path
+}
+
+// Lookup returns the operation of the given id.
+func Lookup(id string) (Operation, bool) {
+	for _, op := range Operations {
+		if op.ID == id {
+			return op, true
+		}
+	}
+	return Operation{}, false
+}
+
+// Match returns the operation of the given method whose path template matches the
+// request path, such as "/trackables/{trackableId}" for "/trackables/d27047bd-...".
+func Match(method, path string) (Operation, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	for _, op := range Operations {
+		if op.Method != method {
+			continue
+		}
+
+		template := strings.Split(strings.Trim(op.Path, "/"), "/")
+		if len(template) != len(segments) {
+			continue
+		}
+
+		matches := true
+		for i, t := range template {
+			if !strings.HasPrefix(t, "{") && t != segments[i] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return op, true
+		}
+	}
+	return Operation{}, false
+}
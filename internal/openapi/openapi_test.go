@@ -0,0 +1,50 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package openapi
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	op, ok := Lookup("getTrackableLocation")
+	if !ok {
+		t.Fatal("expected the operation to exist")
+	}
+	if op.Method != http.MethodGet {
+		t.Errorf("expected method %s, got %s", http.MethodGet, op.Method)
+	}
+
+	want := "/trackables/d27047bd-1b6b-4656-bb93-2326a4c900e1/location"
+	if got := op.Expand(map[string]string{"trackableId": "d27047bd-1b6b-4656-bb93-2326a4c900e1"}); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	if _, ok := Lookup("unknown"); ok {
+		t.Error("expected unknown operation not to exist")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{method: http.MethodGet, path: "/trackables", want: "getTrackableIds"},
+		{method: http.MethodGet, path: "/trackables/summary", want: "getTrackables"},
+		{method: http.MethodGet, path: "/trackables/d27047bd-1b6b-4656-bb93-2326a4c900e1", want: "getTrackable"},
+		{method: http.MethodPut, path: "/providers/77:4f:34:69:27:40/location", want: "updateProviderLocation"},
+		{method: http.MethodPatch, path: "/trackables"},
+		{method: http.MethodGet, path: "/unknown"},
+	}
+
+	for _, tc := range tests {
+		op, ok := Match(tc.method, tc.path)
+		if ok != (tc.want != "") || op.ID != tc.want {
+			t.Errorf("%s %s: expected operation %q, got %q", tc.method, tc.path, tc.want, op.ID)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// document is the subset of an OpenAPI 3 document used by the generator.
+type document struct {
+	Info struct {
+		Title   string `yaml:"title"`
+		Version string `yaml:"version"`
+	} `yaml:"info"`
+
+	// path items are decoded lazily, as they mix operations with other fields
+	Paths map[string]map[string]yaml.Node `yaml:"paths"`
+
+	Components struct {
+		Schemas map[string]*schema `yaml:"schemas"`
+	} `yaml:"components"`
+}
+
+type operation struct {
+	OperationID string `yaml:"operationId"`
+	Summary     string `yaml:"summary"`
+}
+
+type schema struct {
+	Ref         string             `yaml:"$ref"`
+	Type        string             `yaml:"type"`
+	Format      string             `yaml:"format"`
+	Description string             `yaml:"description"`
+	Properties  map[string]*schema `yaml:"properties"`
+	Required    []string           `yaml:"required"`
+	Items       *schema            `yaml:"items"`
+	Enum        []any              `yaml:"enum"`
+	AllOf       []*schema          `yaml:"allOf"`
+}
+
+// httpMethods are the path item keys which are operations.
+var httpMethods = map[string]string{
+	"get":     http.MethodGet,
+	"put":     http.MethodPut,
+	"post":    http.MethodPost,
+	"delete":  http.MethodDelete,
+	"patch":   http.MethodPatch,
+	"head":    http.MethodHead,
+	"options": http.MethodOptions,
+}
+
+// parse parses an OpenAPI document. JSON documents are valid YAML.
+func parse(data []byte) (*document, error) {
+	var doc document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// generator accumulates the generated source.
+type generator struct {
+	buf     bytes.Buffer
+	imports map[string]bool
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// generate returns the formatted Go source of the models and endpoint stubs of the document.
+func generate(doc *document, pkg string) ([]byte, error) {
+	g := &generator{imports: make(map[string]bool)}
+
+	for _, name := range sortedKeys(doc.Components.Schemas) {
+		g.schema(goName(name), doc.Components.Schemas[name])
+	}
+
+	if err := g.operations(doc); err != nil {
+		return nil, err
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by openapigen from %s %s. DO NOT EDIT.\n\n", doc.Info.Title, doc.Info.Version)
+	fmt.Fprintf(&src, "package %s\n\n", pkg)
+
+	if len(g.imports) > 0 {
+		// standard library imports are grouped ahead of the others
+		var std, other []string
+		for _, imp := range sortedKeys(g.imports) {
+			if strings.Contains(strings.Split(imp, "/")[0], ".") {
+				other = append(other, imp)
+				continue
+			}
+			std = append(std, imp)
+		}
+
+		src.WriteString("import (\n")
+		for _, imp := range std {
+			fmt.Fprintf(&src, "\t%q\n", imp)
+		}
+		if len(std) > 0 && len(other) > 0 {
+			src.WriteString("\n")
+		}
+		for _, imp := range other {
+			fmt.Fprintf(&src, "\t%q\n", imp)
+		}
+		src.WriteString(")\n\n")
+	}
+
+	src.Write(g.buf.Bytes())
+
+	return format.Source(src.Bytes())
+}
+
+// schema generates the type declaration of a named schema.
+func (g *generator) schema(name string, s *schema) {
+	g.comment(name, s.Description)
+
+	switch {
+	case len(s.Enum) > 0 && s.Type == "string":
+		g.printf("type %s string\n\n", name)
+		g.printf("// Defines values for %s.\nconst (\n", name)
+		for _, v := range s.Enum {
+			value := fmt.Sprint(v)
+			g.printf("\t%s%s %s = %q\n", name, goName(value), name, value)
+		}
+		g.printf(")\n\n")
+	case s.Type == "object" || len(s.Properties) > 0 || len(s.AllOf) > 0:
+		g.printf("type %s struct {\n", name)
+		g.fields(s)
+		g.printf("}\n\n")
+	default:
+		g.printf("type %s %s\n\n", name, g.typeOf(s, true))
+	}
+}
+
+// fields generates the struct fields of an object schema, including the
+// fields of the schemas it is composed of.
+func (g *generator) fields(s *schema) {
+	for _, part := range s.AllOf {
+		if part.Ref != "" {
+			g.printf("\t%s\n", refName(part.Ref))
+			continue
+		}
+		g.fields(part)
+	}
+
+	required := make(map[string]bool, len(s.Required))
+	for _, r := range s.Required {
+		required[r] = true
+	}
+
+	for _, prop := range sortedKeys(s.Properties) {
+		p := s.Properties[prop]
+
+		if p.Description != "" {
+			for _, line := range strings.Split(strings.TrimSpace(p.Description), "\n") {
+				g.printf("\t// %s\n", strings.TrimSpace(line))
+			}
+		}
+
+		tag := prop
+		if !required[prop] {
+			tag += ",omitempty"
+		}
+
+		g.printf("\t%s %s `json:\"%s\"`\n", goName(prop), g.typeOf(p, required[prop]), tag)
+	}
+}
+
+// typeOf returns the Go type of a schema. Optional values with a meaningful
+// zero value are pointers.
+func (g *generator) typeOf(s *schema, required bool) string {
+	ptr := ""
+	if !required {
+		ptr = "*"
+	}
+
+	switch {
+	case s.Ref != "":
+		return ptr + refName(s.Ref)
+	case s.Type == "array":
+		if s.Items == nil {
+			return "[]any"
+		}
+		return "[]" + g.typeOf(s.Items, true)
+	case s.Type == "string" && s.Format == "date-time":
+		g.imports["time"] = true
+		return ptr + "time.Time"
+	case s.Type == "string" && s.Format == "uuid":
+		g.imports["github.com/google/uuid"] = true
+		return ptr + "uuid.UUID"
+	case s.Type == "string":
+		return "string"
+	case s.Type == "integer":
+		return ptr + "int64"
+	case s.Type == "number":
+		return ptr + "float64"
+	case s.Type == "boolean":
+		return ptr + "bool"
+	default:
+		g.imports["encoding/json"] = true
+		return "json.RawMessage"
+	}
+}
+
+// operations generates the endpoint stubs of the document paths.
+func (g *generator) operations(doc *document) error {
+	g.printf("// Operations are the endpoints of the specification, sorted by path and method.\n")
+	g.printf("var Operations = []Operation{\n")
+
+	for _, path := range sortedKeys(doc.Paths) {
+		item := doc.Paths[path]
+		for _, key := range sortedKeys(item) {
+			method, ok := httpMethods[strings.ToLower(key)]
+			if !ok {
+				continue
+			}
+
+			var op operation
+			node := item[key]
+			if err := node.Decode(&op); err != nil {
+				return fmt.Errorf("invalid operation '%s %s': %w", method, path, err)
+			}
+
+			g.printf("\t{ID: %q, Method: %q, Path: %q, Summary: %q},\n", op.OperationID, method, path, strings.TrimSpace(op.Summary))
+		}
+	}
+
+	g.printf("}\n")
+
+	return nil
+}
+
+// comment writes the doc comment of a declaration.
+func (g *generator) comment(name string, description string) {
+	description = strings.TrimSpace(description)
+	if description == "" {
+		g.printf("// %s defines model for %s.\n", name, name)
+		return
+	}
+
+	for i, line := range strings.Split(description, "\n") {
+		if i == 0 {
+			g.printf("// %s %s\n", name, strings.TrimSpace(line))
+			continue
+		}
+		g.printf("// %s\n", strings.TrimSpace(line))
+	}
+}
+
+// refName returns the Go type name of a local schema reference.
+func refName(ref string) string {
+	return goName(ref[strings.LastIndex(ref, "/")+1:])
+}
+
+// commonInitialisms are upper cased in Go names.
+var commonInitialisms = map[string]bool{
+	"id": true, "url": true, "uuid": true, "crs": true, "json": true, "api": true, "uwb": true, "ble": true, "gps": true, "wifi": true, "rfid": true,
+}
+
+// goName converts a schema, property or enum value name into an exported Go name.
+func goName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for _, w := range words {
+		if commonInitialisms[strings.ToLower(w)] {
+			b.WriteString(strings.ToUpper(w))
+			continue
+		}
+
+		runes := []rune(w)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+
+	if b.Len() == 0 || unicode.IsDigit([]rune(b.String())[0]) {
+		return "X" + b.String()
+	}
+
+	return b.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
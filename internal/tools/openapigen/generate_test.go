@@ -0,0 +1,63 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "hub.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := generate(doc, "openapi")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ignore the alignment of the generated source
+	normalized := strings.Join(strings.Fields(string(src)), " ")
+
+	for _, want := range []string{
+		"// Code generated by openapigen from Test Hub 1.0.0. DO NOT EDIT.",
+		"// Trackable An object which can be tracked.",
+		"ID                uuid.UUID       `json:\"id\"`",
+		"LocationProviders []string        `json:\"location_providers,omitempty\"`",
+		"Name              string          `json:\"name,omitempty\"`",
+		"Properties        json.RawMessage `json:\"properties,omitempty\"`",
+		"Radius            *float64        `json:\"radius,omitempty\"`",
+		"Type              TrackableType   `json:\"type\"`",
+		"TrackableTypeOmlox   TrackableType = \"omlox\"",
+		"{ID: \"getTrackable\", Method: \"GET\", Path: \"/trackables/{trackableId}\", Summary: \"Get a trackable\"},",
+	} {
+		if !strings.Contains(normalized, strings.Join(strings.Fields(want), " ")) {
+			t.Errorf("expected generated source to contain %q, got:\n%s", want, src)
+		}
+	}
+}
+
+func TestRunOptional(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "zz_generated.go")
+
+	if err := run(filepath.Join("testdata", "missing.yaml"), "openapi", out, true); err != nil {
+		t.Fatalf("expected missing optional document to be skipped, got %v", err)
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Errorf("expected no output to be written")
+	}
+
+	if err := run(filepath.Join("testdata", "missing.yaml"), "openapi", out, false); err == nil {
+		t.Errorf("expected error for missing document")
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Command openapigen generates model structs and endpoint stubs from an
+// OpenAPI 3 document, such as the omlox™ Hub specification.
+//
+// Usage:
+//
+//	openapigen -spec omlox-hub.openapi.yaml -pkg openapi -o zz_generated.go
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+)
+
+func main() {
+	var (
+		spec     = flag.String("spec", "", "Path of the OpenAPI document (JSON or YAML)")
+		pkg      = flag.String("pkg", "openapi", "Package name of the generated code")
+		out      = flag.String("o", "zz_generated.go", "Output file")
+		optional = flag.Bool("optional", false, "Keep the output as is when the document does not exist")
+	)
+	flag.Parse()
+
+	if err := run(*spec, *pkg, *out, *optional); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(spec, pkg, out string, optional bool) error {
+	data, err := os.ReadFile(spec)
+	if errors.Is(err, fs.ErrNotExist) && optional {
+		log.Printf("openapigen: %s not found, skipping generation", spec)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	doc, err := parse(data)
+	if err != nil {
+		return fmt.Errorf("could not parse '%s': %w", spec, err)
+	}
+
+	src, err := generate(doc, pkg)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(out, src, 0o644)
+}
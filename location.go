@@ -11,6 +11,15 @@ import (
 	"github.com/google/uuid"
 )
 
+// Well-known coordinate reference systems.
+const (
+	// CrsLocal is the projection of coordinates relative to the floor plan of a zone.
+	CrsLocal = "local"
+
+	// CrsWGS84 is the World Geodetic System 1984 projection (EPSG:4326), as used by GPS.
+	CrsWGS84 = "EPSG:4326"
+)
+
 // Location defines model for Location.
 //
 //easyjson:json
@@ -59,6 +68,11 @@ type Location struct {
 	// The horizontal accuracy of the location update in meters.
 	Accuracy *float64 `json:"accuracy,omitempty"`
 
+	// The covariance matrix of the position in square meters, as provided by some vendors extending the omlox™ model.
+	// Rows and columns are ordered as the position components (x, y and optionally z).
+	// Use Uncertainty to get the horizontal uncertainty ellipse.
+	Covariance [][]float64 `json:"covariance,omitempty"`
+
 	// A logical and non-localized representation for a building floor. Floor 0 represents the floor designated as 'ground'.
 	// Negative numbers designate floors below the ground floor and positive to indicate floors above the ground floor.
 	// When implemented the floor value MUST match described logical numbering scheme, which can be different from any numbering used
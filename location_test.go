@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/mailru/easyjson"
 	"github.com/tidwall/geojson"
 	"github.com/tidwall/geojson/geometry"
 )
@@ -42,6 +43,8 @@ var locationJSONTestCases = []struct {
 			TimestampSent:      mustParseTime("2023-10-17T11:14:37.213Z"),
 			Crs:                "local",
 			Associated:         true,
+			Accuracy:           opt(0.5),
+			Covariance:         [][]float64{{0.04, 0.01}, {0.01, 0.09}},
 			Floor:              1.2,
 			TrueHeading:        opt(-1.0),
 			MagneticHeading:    opt(1.234),
@@ -51,7 +54,7 @@ var locationJSONTestCases = []struct {
 			Course:             opt(104.76595042882053),
 			Properties:         json.RawMessage(`{"org.wavecom.temp":24.3}`),
 		},
-		json: []byte(`{"position":{"type":"Point","coordinates":[7.815694,48.13021599999995,1.2]},"source":"f4c05a2b-afd3-41a0-88e2-46f69bdb192e","provider_type":"ibeacon","provider_id":"ac:23:3f:af:f3:90","trackables":["9d3b2ee3-791f-444d-a0f2-caf52820f561","a5865271-2e84-40d0-8f8f-e6f7ea15d103"],"timestamp_generated":"2023-10-17T11:14:37.206Z","timestamp_sent":"2023-10-17T11:14:37.213Z","crs":"local","associated":true,"floor":1.2,"true_heading":-1,"magnetic_heading":1.234,"heading_accuracy":1.12,"elevation_ref":"wgs84","speed":0.814870001487674,"course":104.76595042882053,"properties":{"org.wavecom.temp":24.3}}`),
+		json: []byte(`{"position":{"type":"Point","coordinates":[7.815694,48.13021599999995,1.2]},"source":"f4c05a2b-afd3-41a0-88e2-46f69bdb192e","provider_type":"ibeacon","provider_id":"ac:23:3f:af:f3:90","trackables":["9d3b2ee3-791f-444d-a0f2-caf52820f561","a5865271-2e84-40d0-8f8f-e6f7ea15d103"],"timestamp_generated":"2023-10-17T11:14:37.206Z","timestamp_sent":"2023-10-17T11:14:37.213Z","crs":"local","associated":true,"accuracy":0.5,"covariance":[[0.04,0.01],[0.01,0.09]],"floor":1.2,"true_heading":-1,"magnetic_heading":1.234,"heading_accuracy":1.12,"elevation_ref":"wgs84","speed":0.814870001487674,"course":104.76595042882053,"properties":{"org.wavecom.temp":24.3}}`),
 	},
 }
 
@@ -70,3 +73,91 @@ func TestLocationUnmarshal(t *testing.T) {
 		})
 	}
 }
+
+// benchmarkLocation is a typical location update pushed by a gateway.
+var benchmarkLocation = []byte(`{"position":{"type":"Point","coordinates":[5.23,4.18]},"source":"fdb6df62-bce8-6c23-e342-80bd5c938774","provider_type":"uwb","provider_id":"77:4f:34:69:27:40","trackables":["30c4e3a6-2ae8-4f7b-8a7e-3d1e1e7a6f1a"],"timestamp_generated":"2024-03-01T08:00:00.123Z","timestamp_sent":"2024-03-01T08:00:00.125Z","crs":"local","accuracy":0.3,"speed":1.2,"course":90,"floor":1}`)
+
+func BenchmarkLocationMarshal(b *testing.B) {
+	var loc Location
+	if err := json.Unmarshal(benchmarkLocation, &loc); err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("encoding/json", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := json.Marshal(&loc); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("append", func(b *testing.B) {
+		buf := make([]byte, 0, 4096)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var err error
+			if buf, err = loc.AppendJSON(buf[:0]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkLocationUnmarshal(b *testing.B) {
+	b.Run("encoding/json", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var loc Location
+			if err := json.Unmarshal(benchmarkLocation, &loc); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("easyjson", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var loc Location
+			if err := easyjson.Unmarshal(benchmarkLocation, &loc); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestLocationAppendJSON(t *testing.T) {
+	var loc Location
+	if err := easyjson.Unmarshal(benchmarkLocation, &loc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want, err := json.Marshal(&loc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := loc.AppendJSON([]byte("prefix:"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(got) != "prefix:"+string(want) {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	// encodings larger than the free space of the buffer are complete
+	loc.Trackables = make([]uuid.UUID, 200)
+	want, _ = json.Marshal(&loc)
+	if got, _ := loc.AppendJSON(nil); string(got) != string(want) {
+		t.Errorf("expected %d bytes, got %d", len(want), len(got))
+	}
+
+	// points with foreign members are decoded by the GeoJSON parser
+	var p Point
+	if err := easyjson.Unmarshal([]byte(`{"type":"Point","coordinates":[1,2,3],"bbox":[1,2,1,2]}`), &p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Base() != (geometry.Point{X: 1, Y: 2}) || p.Z() != 3 {
+		t.Errorf("unexpected point %s", p.JSON())
+	}
+}
@@ -0,0 +1,362 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+// DefaultTopicPrefix is the default prefix of the topics of a bridge.
+const DefaultTopicPrefix = "omlox"
+
+// DefaultDiscoveryPrefix is the discovery prefix of Home Assistant.
+const DefaultDiscoveryPrefix = "homeassistant"
+
+// States of trackables, as expected by Home Assistant.
+const (
+	StateHome    = "home"
+	StateNotHome = "not_home"
+)
+
+// Payloads of the status topic of a bridge.
+const (
+	StatusOnline  = "online"
+	StatusOffline = "offline"
+)
+
+// StatusTopic returns the topic of the status of the bridges of a topic prefix.
+func StatusTopic(prefix string) string {
+	return prefix + "/status"
+}
+
+// OfflineWill returns the option setting the will of a client to the offline status
+// of the bridges of a topic prefix, marking trackables unavailable in Home Assistant
+// when the connection is lost.
+func OfflineWill(prefix string) ClientOption {
+	return WithWill(Message{
+		Topic:   StatusTopic(prefix),
+		Payload: []byte(StatusOffline),
+		QoS:     1,
+		Retain:  true,
+	})
+}
+
+// Bridge publishes the positions and fence memberships of the trackables of a tracker
+// to an MQTT broker. It is safe for concurrent use.
+//
+// Each trackable has the retained topics <prefix>/trackables/<id>/state, holding the
+// name of a fence it is inside or "not_home", and <prefix>/trackables/<id>/attributes,
+// holding a JSON object of its position. Messages are only published on changes.
+type Bridge struct {
+	client    *Client
+	tracker   *omlox.Tracker
+	prefix    string
+	qos       byte
+	discovery string
+
+	mu        sync.Mutex
+	names     map[uuid.UUID]string // of trackables
+	fences    map[uuid.UUID]string // of fences
+	published map[string]string    // payloads by topic
+	tracked   map[uuid.UUID]bool
+}
+
+// BridgeOption is a functional option of a bridge.
+type BridgeOption func(*Bridge)
+
+// WithTopicPrefix sets the prefix of the topics of the bridge.
+//
+// Default: DefaultTopicPrefix
+func WithTopicPrefix(prefix string) BridgeOption {
+	return func(b *Bridge) {
+		b.prefix = prefix
+	}
+}
+
+// WithQoS sets the quality of service of the messages, 0 or 1.
+//
+// Default: 0
+func WithQoS(qos byte) BridgeOption {
+	return func(b *Bridge) {
+		b.qos = qos
+	}
+}
+
+// WithHomeAssistant enables the Home Assistant discovery messages, publishing a
+// device_tracker entity per trackable under a discovery prefix, usually
+// DefaultDiscoveryPrefix.
+func WithHomeAssistant(discoveryPrefix string) BridgeOption {
+	return func(b *Bridge) {
+		b.discovery = discoveryPrefix
+	}
+}
+
+// NewBridge returns a bridge publishing the positions and fence memberships of a tracker
+// with a client. Messages are published by Sync or Run.
+func NewBridge(client *Client, tracker *omlox.Tracker, opts ...BridgeOption) *Bridge {
+	b := &Bridge{
+		client:    client,
+		tracker:   tracker,
+		prefix:    DefaultTopicPrefix,
+		names:     make(map[uuid.UUID]string),
+		fences:    make(map[uuid.UUID]string),
+		published: make(map[string]string),
+		tracked:   make(map[uuid.UUID]bool),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// SetTrackables sets the trackables to name the entities of their positions with.
+// Other trackables are named by id.
+func (b *Bridge) SetTrackables(trackables []omlox.Trackable) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	clear(b.names)
+	for _, t := range trackables {
+		if t.Name != "" {
+			b.names[t.ID] = t.Name
+		}
+	}
+}
+
+// SetFences sets the fences to name the states of trackables with. Other fences are
+// named by id.
+func (b *Bridge) SetFences(fences []omlox.Fence) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	clear(b.fences)
+	for _, f := range fences {
+		if f.Name != "" {
+			b.fences[f.ID] = f.Name
+		}
+	}
+}
+
+// TrackableTopic returns the topic of a trackable under the prefix of the bridge, such
+// as "state" or "attributes".
+func (b *Bridge) TrackableTopic(id uuid.UUID, topic string) string {
+	return b.prefix + "/trackables/" + id.String() + "/" + topic
+}
+
+// discoveryTopic returns the discovery topic of the entity of a trackable.
+func (b *Bridge) discoveryTopic(id uuid.UUID) string {
+	return b.discovery + "/device_tracker/" + objectID(id) + "/config"
+}
+
+func objectID(id uuid.UUID) string {
+	return "omlox_" + id.String()
+}
+
+// attributes are the attributes of the position of a trackable.
+type attributes struct {
+	X          float64   `json:"x"`
+	Y          float64   `json:"y"`
+	Z          float64   `json:"z"`
+	Floor      float64   `json:"floor"`
+	Crs        string    `json:"crs"`
+	ProviderID string    `json:"provider_id"`
+	Timestamp  time.Time `json:"timestamp"`
+	Fences     []string  `json:"fences"`
+
+	// GPS attributes of WGS84 positions, placing trackables on the map of Home Assistant.
+	Latitude    *float64 `json:"latitude,omitempty"`
+	Longitude   *float64 `json:"longitude,omitempty"`
+	GPSAccuracy *float64 `json:"gps_accuracy,omitempty"`
+}
+
+// discoveryConfig is the discovery message of a device_tracker entity.
+type discoveryConfig struct {
+	Name                string          `json:"name"`
+	UniqueID            string          `json:"unique_id"`
+	ObjectID            string          `json:"object_id"`
+	StateTopic          string          `json:"state_topic"`
+	JSONAttributesTopic string          `json:"json_attributes_topic"`
+	AvailabilityTopic   string          `json:"availability_topic"`
+	PayloadHome         string          `json:"payload_home"`
+	PayloadNotHome      string          `json:"payload_not_home"`
+	SourceType          string          `json:"source_type"`
+	Device              discoveryDevice `json:"device"`
+}
+
+type discoveryDevice struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer"`
+}
+
+// Sync publishes the changes of the positions and fence memberships of the tracker.
+// The topics of trackables forgotten by the tracker are cleared. Failed messages are
+// published again by the next sync.
+func (b *Bridge) Sync(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var errs []error
+	publish := func(topic, payload string) {
+		if prev, ok := b.published[topic]; ok && prev == payload {
+			return
+		}
+		if err := b.client.Publish(ctx, Message{Topic: topic, Payload: []byte(payload), QoS: b.qos, Retain: true}); err != nil {
+			errs = append(errs, fmt.Errorf("publish %s: %w", topic, err))
+			return
+		}
+		if payload == "" {
+			delete(b.published, topic)
+		} else {
+			b.published[topic] = payload
+		}
+	}
+
+	publish(StatusTopic(b.prefix), StatusOnline)
+
+	positions := b.tracker.Positions()
+	tracked := make(map[uuid.UUID]bool, len(positions))
+
+	for _, p := range positions {
+		id := p.TrackableID
+		tracked[id] = true
+		b.tracked[id] = true
+
+		if b.discovery != "" {
+			config, err := json.Marshal(b.discoveryConfig(id))
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			publish(b.discoveryTopic(id), string(config))
+		}
+
+		memberships := b.tracker.InFence(id)
+		fences := make([]string, len(memberships))
+		for i, m := range memberships {
+			fences[i] = m.FenceID.String()
+			if name, ok := b.fences[m.FenceID]; ok {
+				fences[i] = name
+			}
+		}
+		slices.Sort(fences)
+
+		state := StateNotHome
+		if len(fences) > 0 {
+			state = fences[0]
+		}
+
+		attrs, err := json.Marshal(b.attributes(p, fences))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		publish(b.TrackableTopic(id, "attributes"), string(attrs))
+		publish(b.TrackableTopic(id, "state"), state)
+	}
+
+	for id := range b.tracked {
+		if tracked[id] {
+			continue
+		}
+		publish(b.TrackableTopic(id, "state"), "")
+		publish(b.TrackableTopic(id, "attributes"), "")
+		if b.discovery != "" {
+			publish(b.discoveryTopic(id), "")
+		}
+		delete(b.tracked, id)
+	}
+
+	return errors.Join(errs...)
+}
+
+// discoveryConfig returns the discovery message of a trackable. The lock must be held.
+func (b *Bridge) discoveryConfig(id uuid.UUID) discoveryConfig {
+	name, ok := b.names[id]
+	if !ok {
+		name = id.String()
+	}
+
+	return discoveryConfig{
+		Name:                name,
+		UniqueID:            objectID(id),
+		ObjectID:            objectID(id),
+		StateTopic:          b.TrackableTopic(id, "state"),
+		JSONAttributesTopic: b.TrackableTopic(id, "attributes"),
+		AvailabilityTopic:   StatusTopic(b.prefix),
+		PayloadHome:         StateHome,
+		PayloadNotHome:      StateNotHome,
+		SourceType:          "gps",
+		Device: discoveryDevice{
+			Identifiers:  []string{objectID(id)},
+			Name:         name,
+			Manufacturer: "omlox",
+		},
+	}
+}
+
+func (b *Bridge) attributes(p omlox.TrackedPosition, fences []string) attributes {
+	loc := p.Location
+	base := loc.Position.Base()
+
+	a := attributes{
+		X:          base.X,
+		Y:          base.Y,
+		Z:          loc.Position.Z(),
+		Floor:      loc.Floor,
+		Crs:        loc.Crs,
+		ProviderID: loc.ProviderID,
+		Timestamp:  p.At,
+		Fences:     fences,
+	}
+	if a.Crs == "" {
+		a.Crs = omlox.CrsLocal
+	}
+	if a.Crs == omlox.CrsWGS84 {
+		a.Latitude = &base.Y
+		a.Longitude = &base.X
+		a.GPSAccuracy = loc.Accuracy
+	}
+	return a
+}
+
+// Run syncs at each interval until the context is done. Sync errors are returned.
+func (b *Bridge) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := b.Sync(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close publishes the offline status of the bridge. It does not close the client.
+func (b *Bridge) Close(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	err := b.client.Publish(ctx, Message{Topic: StatusTopic(b.prefix), Payload: []byte(StatusOffline), QoS: b.qos, Retain: true})
+	delete(b.published, StatusTopic(b.prefix))
+	return err
+}
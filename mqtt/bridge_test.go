@@ -0,0 +1,134 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+var (
+	forklift = uuid.MustParse("6c5d2f0e-8f6a-4b7e-9a51-0d6f1a2b3c41")
+	dock     = uuid.MustParse("3a1f0c9e-7d2b-4e6f-8a5c-1b2d3e4f5a66")
+)
+
+// position returns a location of the forklift.
+func position(x, y float64, crs string) omlox.Location {
+	return omlox.Location{
+		Position:   *omlox.NewPoint(geometry.Point{X: x, Y: y}),
+		Crs:        crs,
+		ProviderID: "uwb-1",
+		Trackables: []uuid.UUID{forklift},
+	}
+}
+
+func TestBridge(t *testing.T) {
+	broker := newBroker(t)
+	ctx := context.Background()
+
+	c, err := Dial(ctx, broker.URL())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer c.Close()
+
+	tracker := omlox.NewTracker()
+	tracker.Update(position(2, 3, ""))
+	tracker.ObserveFenceEvents(omlox.FenceEvent{FenceID: dock, TrackableID: &forklift, EventType: omlox.FenceEventTypeRegionEntry})
+
+	b := NewBridge(c, tracker, WithQoS(1), WithHomeAssistant(DefaultDiscoveryPrefix))
+	b.SetTrackables([]omlox.Trackable{{ID: forklift, Name: "forklift"}})
+	b.SetFences([]omlox.Fence{{ID: dock, Name: "dock"}})
+
+	if err := b.Sync(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if status, _ := broker.Retained("omlox/status"); status != StatusOnline {
+		t.Errorf("expected status %q, got %q", StatusOnline, status)
+	}
+	if state, _ := broker.Retained(b.TrackableTopic(forklift, "state")); state != "dock" {
+		t.Errorf("expected state %q, got %q", "dock", state)
+	}
+
+	payload, _ := broker.Retained("homeassistant/device_tracker/omlox_" + forklift.String() + "/config")
+	var config map[string]any
+	if err := json.Unmarshal([]byte(payload), &config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]any{
+		"name":                  "forklift",
+		"unique_id":             "omlox_" + forklift.String(),
+		"object_id":             "omlox_" + forklift.String(),
+		"state_topic":           "omlox/trackables/" + forklift.String() + "/state",
+		"json_attributes_topic": "omlox/trackables/" + forklift.String() + "/attributes",
+		"availability_topic":    "omlox/status",
+		"payload_home":          "home",
+		"payload_not_home":      "not_home",
+		"source_type":           "gps",
+		"device": map[string]any{
+			"identifiers":  []any{"omlox_" + forklift.String()},
+			"name":         "forklift",
+			"manufacturer": "omlox",
+		},
+	}
+	if diff := cmp.Diff(want, config); diff != "" {
+		t.Errorf("unexpected discovery config (-want +got):\n%s", diff)
+	}
+
+	// unchanged positions are not published again
+	broker.Messages()
+	if err := b.Sync(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if messages := broker.Messages(); len(messages) != 0 {
+		t.Errorf("expected no messages, got %d", len(messages))
+	}
+
+	tracker.Update(position(2.35, 48.85, omlox.CrsWGS84))
+	tracker.ObserveFenceEvents(omlox.FenceEvent{FenceID: dock, TrackableID: &forklift, EventType: omlox.FenceEventTypeRegionExit})
+	if err := b.Sync(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if state, _ := broker.Retained(b.TrackableTopic(forklift, "state")); state != StateNotHome {
+		t.Errorf("expected state %q, got %q", StateNotHome, state)
+	}
+	payload, _ = broker.Retained(b.TrackableTopic(forklift, "attributes"))
+	var attrs map[string]any
+	if err := json.Unmarshal([]byte(payload), &attrs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attrs["latitude"] != 48.85 || attrs["longitude"] != 2.35 || attrs["crs"] != omlox.CrsWGS84 {
+		t.Errorf("unexpected attributes: %v", attrs)
+	}
+
+	// forgotten trackables are removed from Home Assistant
+	tracker.Forget(forklift)
+	if err := b.Sync(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, topic := range []string{
+		b.TrackableTopic(forklift, "state"),
+		b.TrackableTopic(forklift, "attributes"),
+		"homeassistant/device_tracker/omlox_" + forklift.String() + "/config",
+	} {
+		if _, ok := broker.Retained(topic); ok {
+			t.Errorf("expected %s to be cleared", topic)
+		}
+	}
+
+	if err := b.Close(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status, _ := broker.Retained("omlox/status"); status != StatusOffline {
+		t.Errorf("expected status %q, got %q", StatusOffline, status)
+	}
+}
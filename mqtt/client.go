@@ -0,0 +1,175 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package mqtt publishes the positions of trackables to an MQTT broker, with
+// optional Home Assistant discovery messages.
+//
+// Messages are published with the Eclipse Paho MQTT client. The package is a module
+// of its own, so the client of the hub does not depend on it.
+package mqtt
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+)
+
+// Defaults of the client configuration.
+const (
+	DefaultPort      = "1883"
+	DefaultTLSPort   = "8883"
+	DefaultKeepAlive = 30 * time.Second
+)
+
+// ErrClientClosed is returned by the publications of a closed client.
+var ErrClientClosed = errors.New("mqtt: client closed")
+
+// Message is a message published to a topic.
+type Message struct {
+	Topic   string
+	Payload []byte
+
+	// QoS is the quality of service of the message, 0, 1 or 2.
+	QoS byte
+
+	// Retain asks the broker to keep the message for future subscribers.
+	Retain bool
+}
+
+// ClientOption is a configuration option of a client.
+type ClientOption func(*paho.ClientOptions)
+
+// WithClientID sets the client identifier. Empty identifiers let the broker assign one.
+func WithClientID(id string) ClientOption {
+	return func(o *paho.ClientOptions) {
+		o.SetClientID(id)
+	}
+}
+
+// WithCredentials sets the user name and password of the connection.
+func WithCredentials(username, password string) ClientOption {
+	return func(o *paho.ClientOptions) {
+		o.SetUsername(username)
+		o.SetPassword(password)
+	}
+}
+
+// WithKeepAlive sets the maximum interval between the packets sent to the broker.
+//
+// Default: DefaultKeepAlive
+func WithKeepAlive(d time.Duration) ClientOption {
+	return func(o *paho.ClientOptions) {
+		o.SetKeepAlive(d)
+	}
+}
+
+// WithWill sets the message published by the broker when the connection is lost.
+func WithWill(m Message) ClientOption {
+	return func(o *paho.ClientOptions) {
+		o.SetBinaryWill(m.Topic, m.Payload, m.QoS, m.Retain)
+	}
+}
+
+// WithTLSConfig sets the TLS configuration of mqtts connections.
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return func(o *paho.ClientOptions) {
+		o.SetTLSConfig(config)
+	}
+}
+
+// Client publishes messages to a broker, reconnecting when the connection is lost.
+// It is safe for concurrent use.
+type Client struct {
+	client paho.Client
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// Dial connects to a broker, such as "mqtt://broker.local:1883" or
+// "mqtts://broker.local".
+func Dial(ctx context.Context, broker string, opts ...ClientOption) (*Client, error) {
+	u, err := url.Parse(broker)
+	if err != nil {
+		return nil, err
+	}
+
+	var port string
+	switch u.Scheme {
+	case "mqtt", "tcp":
+		port = DefaultPort
+	case "mqtts", "ssl", "tls":
+		port = DefaultTLSPort
+	default:
+		return nil, fmt.Errorf("unsupported broker %q: expected an mqtt or mqtts url", broker)
+	}
+
+	o := paho.NewClientOptions()
+	o.SetKeepAlive(DefaultKeepAlive)
+	o.SetAutoReconnect(true)
+	if u.User != nil {
+		password, _ := u.User.Password()
+		o.SetUsername(u.User.Username())
+		o.SetPassword(password)
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	o.AddBroker((&url.URL{Scheme: u.Scheme, Host: hostPort(u, port)}).String())
+
+	c := paho.NewClient(o)
+	if err := wait(ctx, c.Connect()); err != nil {
+		c.Disconnect(0)
+		return nil, fmt.Errorf("mqtt: could not connect to %s: %w", u.Redacted(), err)
+	}
+	return &Client{client: c}, nil
+}
+
+func hostPort(u *url.URL, port string) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// wait waits for the completion of a token, or for the end of the context.
+func wait(ctx context.Context, t paho.Token) error {
+	select {
+	case <-t.Done():
+		return t.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Publish publishes a message. Messages of QoS 1 and 2 are returned once acknowledged
+// by the broker.
+func (c *Client) Publish(ctx context.Context, m Message) error {
+	c.mu.Lock()
+	closed := c.closed
+	c.mu.Unlock()
+	if closed {
+		return ErrClientClosed
+	}
+
+	return wait(ctx, c.client.Publish(m.Topic, m.QoS, m.Retain, m.Payload))
+}
+
+// Close disconnects from the broker, waiting a moment for the publications in flight.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.closed {
+		c.closed = true
+		c.client.Disconnect(250)
+	}
+	return nil
+}
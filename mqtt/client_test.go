@@ -0,0 +1,247 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Packet types of the fake broker.
+const (
+	packetConnect    = 0x10
+	packetConnAck    = 0x20
+	packetPublish    = 0x30
+	packetPubAck     = 0x40
+	packetPingReq    = 0xC0
+	packetPingResp   = 0xD0
+	packetDisconnect = 0xE0
+)
+
+// packet returns a packet of a type with its remaining length.
+func packet(typ byte, body []byte) []byte {
+	b := []byte{typ}
+	n := len(body)
+	for {
+		digit := byte(n % 128)
+		if n /= 128; n > 0 {
+			digit |= 0x80
+		}
+		b = append(b, digit)
+		if n == 0 {
+			break
+		}
+	}
+	return append(b, body...)
+}
+
+// readPacket reads a packet, returning its first byte and body.
+func readPacket(r *bufio.Reader) (byte, []byte, error) {
+	typ, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var n, shift int
+	for {
+		digit, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		n |= int(digit&0x7f) << shift
+		if digit&0x80 == 0 {
+			break
+		}
+		if shift += 7; shift > 21 {
+			return 0, nil, errors.New("malformed packet length")
+		}
+	}
+
+	body := make([]byte, n)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return typ, body, nil
+}
+
+// broker is a fake broker recording the connections and retained messages of clients.
+type broker struct {
+	l    net.Listener
+	code byte // of connection acknowledgements
+
+	mu       sync.Mutex
+	connects [][]byte
+	retained map[string]string
+	messages []Message
+	changed  chan struct{}
+}
+
+func newBroker(t *testing.T) *broker {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b := &broker{l: l, retained: make(map[string]string), changed: make(chan struct{}, 1)}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go b.serve(conn)
+		}
+	}()
+	return b
+}
+
+func (b *broker) URL() string {
+	return "mqtt://" + b.l.Addr().String()
+}
+
+func (b *broker) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	for {
+		typ, body, err := readPacket(r)
+		if err != nil {
+			return
+		}
+
+		switch typ & 0xF0 {
+		case packetConnect:
+			b.mu.Lock()
+			b.connects = append(b.connects, body)
+			code := b.code
+			b.mu.Unlock()
+			conn.Write(packet(packetConnAck, []byte{0, code}))
+		case packetPingReq:
+			conn.Write(packet(packetPingResp, nil))
+		case packetDisconnect:
+			return
+		case packetPublish:
+			n := int(binary.BigEndian.Uint16(body))
+			m := Message{Topic: string(body[2 : 2+n]), QoS: typ >> 1 & 0x03, Retain: typ&0x01 != 0}
+			body = body[2+n:]
+			if m.QoS > 0 {
+				conn.Write(packet(packetPubAck, body[:2]))
+				body = body[2:]
+			}
+			m.Payload = body
+
+			b.mu.Lock()
+			b.messages = append(b.messages, m)
+			if m.Retain && len(m.Payload) == 0 {
+				delete(b.retained, m.Topic)
+			} else if m.Retain {
+				b.retained[m.Topic] = string(m.Payload)
+			}
+			b.mu.Unlock()
+
+			select {
+			case b.changed <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// Retained returns the retained payload of a topic.
+func (b *broker) Retained(topic string) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	payload, ok := b.retained[topic]
+	return payload, ok
+}
+
+// Messages returns and clears the messages received.
+func (b *broker) Messages() []Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	messages := b.messages
+	b.messages = nil
+	return messages
+}
+
+// WaitMessages waits for n messages, and returns them.
+func (b *broker) WaitMessages(t *testing.T, n int) []Message {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+	var messages []Message
+	for len(messages) < n {
+		select {
+		case <-b.changed:
+			messages = append(messages, b.Messages()...)
+		case <-timeout:
+			t.Fatalf("expected %d messages, got %d", n, len(messages))
+		}
+	}
+	return messages
+}
+
+func TestPublish(t *testing.T) {
+	b := newBroker(t)
+
+	c, err := Dial(context.Background(), b.URL(), WithClientID("test"), WithCredentials("user", "secret"), OfflineWill("omlox"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer c.Close()
+
+	if err := c.Publish(context.Background(), Message{Topic: "a/b", Payload: []byte("1"), QoS: 1, Retain: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Publish(context.Background(), Message{Topic: "a/c", Payload: []byte("2")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	messages := b.WaitMessages(t, 2)
+	if messages[0].Topic != "a/b" || string(messages[0].Payload) != "1" || messages[0].QoS != 1 || !messages[0].Retain {
+		t.Errorf("unexpected first message: %+v", messages[0])
+	}
+	if messages[1].Topic != "a/c" || string(messages[1].Payload) != "2" || messages[1].QoS != 0 || messages[1].Retain {
+		t.Errorf("unexpected second message: %+v", messages[1])
+	}
+
+	b.mu.Lock()
+	connect := b.connects[0]
+	b.mu.Unlock()
+	if flags := connect[7]; flags != 0x02|0x04|0x08|0x20|0x40|0x80 {
+		t.Errorf("unexpected connect flags: %08b", flags)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Publish(context.Background(), Message{Topic: "a/b"}); !errors.Is(err, ErrClientClosed) {
+		t.Fatalf("expected ErrClientClosed, got %v", err)
+	}
+}
+
+func TestDialRefused(t *testing.T) {
+	b := newBroker(t)
+	b.code = 5
+
+	if _, err := Dial(context.Background(), b.URL()); err == nil {
+		t.Fatalf("expected a not authorized error")
+	}
+
+	if _, err := Dial(context.Background(), "http://"+b.l.Addr().String()); err == nil {
+		t.Fatalf("expected an unsupported broker error")
+	}
+}
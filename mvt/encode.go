@@ -0,0 +1,185 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package mvt
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// Geometry types of the features of vector tiles.
+const (
+	pointType   = 1
+	polygonType = 3
+)
+
+// Geometry commands of vector tiles.
+const (
+	moveTo    = 1
+	lineTo    = 2
+	closePath = 7
+)
+
+// Protocol buffers wire types.
+const (
+	varintWire = 0
+	fixed64    = 1
+	bytesWire  = 2
+)
+
+// buffer is a protocol buffers message being encoded.
+type buffer []byte
+
+func (b *buffer) varint(v uint64) {
+	*b = binary.AppendUvarint(*b, v)
+}
+
+func (b *buffer) key(field, wire int) {
+	b.varint(uint64(field)<<3 | uint64(wire))
+}
+
+func (b *buffer) uint(field int, v uint64) {
+	b.key(field, varintWire)
+	b.varint(v)
+}
+
+func (b *buffer) bytes(field int, p []byte) {
+	b.key(field, bytesWire)
+	b.varint(uint64(len(p)))
+	*b = append(*b, p...)
+}
+
+func (b *buffer) string(field int, s string) {
+	b.bytes(field, []byte(s))
+}
+
+func (b *buffer) double(field int, v float64) {
+	b.key(field, fixed64)
+	*b = binary.LittleEndian.AppendUint64(*b, math.Float64bits(v))
+}
+
+// packed encodes repeated unsigned integers.
+func (b *buffer) packed(field int, vs []uint32) {
+	var p buffer
+	for _, v := range vs {
+		p.varint(uint64(v))
+	}
+	b.bytes(field, p)
+}
+
+// zigzag encodes a signed integer as an unsigned one.
+func zigzag(v int32) uint32 {
+	return uint32((v << 1) ^ (v >> 31))
+}
+
+// command returns the command integer of a geometry command repeated count times.
+func command(id, count int) uint32 {
+	return uint32(id&0x7) | uint32(count)<<3
+}
+
+// feature is a feature of a layer, in tile coordinates.
+type feature struct {
+	typ  int
+	tags []uint32
+
+	// a point, or the closed rings of a polygon, without their repeated last point
+	geometry [][][2]int32
+}
+
+// encode returns the geometry of the feature as commands.
+func (f feature) encode() []uint32 {
+	var (
+		cmds []uint32
+		cur  [2]int32
+	)
+	for _, ring := range f.geometry {
+		for i, p := range ring {
+			switch {
+			case i == 0:
+				cmds = append(cmds, command(moveTo, 1))
+			case i == 1:
+				cmds = append(cmds, command(lineTo, len(ring)-1))
+			}
+			cmds = append(cmds, zigzag(p[0]-cur[0]), zigzag(p[1]-cur[1]))
+			cur = p
+		}
+		if f.typ == polygonType {
+			cmds = append(cmds, command(closePath, 1))
+		}
+	}
+	return cmds
+}
+
+// layer is a layer of a vector tile, with its keys and values shared by the features.
+type layer struct {
+	name     string
+	extent   int
+	features []feature
+
+	keys   []string
+	values []any
+	index  map[any]uint32
+}
+
+// newLayer returns a new layer.
+func newLayer(name string, extent int) *layer {
+	return &layer{name: name, extent: extent, index: make(map[any]uint32)}
+}
+
+// tag returns the tags of a key and value, adding them to the layer.
+func (l *layer) tag(key string, value any) []uint32 {
+	k, ok := l.index[key]
+	if !ok {
+		k = uint32(len(l.keys))
+		l.keys = append(l.keys, key)
+		l.index[key] = k
+	}
+
+	// values are indexed apart from the keys of the same string
+	type valueKey struct{ v any }
+	v, ok := l.index[valueKey{value}]
+	if !ok {
+		v = uint32(len(l.values))
+		l.values = append(l.values, value)
+		l.index[valueKey{value}] = v
+	}
+
+	return []uint32{k, v}
+}
+
+// encode returns the layer message.
+func (l *layer) encode() []byte {
+	var b buffer
+	b.uint(15, 2) // version
+	b.string(1, l.name)
+
+	for _, f := range l.features {
+		var fb buffer
+		fb.packed(2, f.tags)
+		fb.uint(3, uint64(f.typ))
+		fb.packed(4, f.encode())
+		b.bytes(2, fb)
+	}
+
+	for _, k := range l.keys {
+		b.string(3, k)
+	}
+
+	for _, v := range l.values {
+		var vb buffer
+		switch v := v.(type) {
+		case string:
+			vb.string(1, v)
+		case float64:
+			vb.double(3, v)
+		case int64:
+			vb.key(6, varintWire)
+			vb.varint(uint64(v<<1) ^ uint64(v>>63))
+		}
+		b.bytes(4, vb)
+	}
+
+	b.uint(5, uint64(l.extent))
+	return b
+}
@@ -0,0 +1,304 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+//go:build !omlox_minimal
+
+// Package mvt serves the live positions of the trackables of a tracker, and the fences,
+// as Mapbox Vector Tiles, so web maps render high-density deployments of tens of
+// thousands of tags efficiently.
+//
+// Tiles are in the Web Mercator tiling scheme of web maps, and have a "trackables"
+// layer of the WGS84 positions of the trackables, and a "fences" layer of the WGS84
+// fences. Positions and fences in local coordinates are left out.
+package mvt
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/tidwall/geojson"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+// ContentType is the media type of vector tiles.
+const ContentType = "application/vnd.mapbox-vector-tile"
+
+// Layer names of the tiles.
+const (
+	TrackablesLayer = "trackables"
+	FencesLayer     = "fences"
+)
+
+// ErrInvalidTile is returned for tile coordinates out of the tiling scheme.
+var ErrInvalidTile = errors.New("invalid tile coordinates")
+
+// maxZoom is the maximum zoom level of the tiles.
+const maxZoom = 24
+
+// Option is a configuration option of a tiler.
+type Option func(*Tiler)
+
+// WithExtent sets the size of the tiles in tile coordinates.
+// Default: 4096
+func WithExtent(extent int) Option {
+	return func(t *Tiler) {
+		t.extent = extent
+	}
+}
+
+// WithBuffer sets the size of the buffer around the tiles in tile coordinates, in which
+// positions are included so markers on tile edges are not cut.
+// Default: 64
+func WithBuffer(buffer int) Option {
+	return func(t *Tiler) {
+		t.buffer = buffer
+	}
+}
+
+// Tiler renders the positions of a tracker and the fences as vector tiles. It is safe for
+// concurrent use.
+type Tiler struct {
+	tracker *omlox.Tracker
+	extent  int
+	buffer  int
+
+	mu     sync.RWMutex
+	fences []omlox.Fence
+}
+
+// NewTiler returns a tiler of the positions of a tracker. Create the tracker
+// WithSpatialIndex(omlox.CrsWGS84) to find the positions of tiles without scanning all
+// the positions.
+func NewTiler(tracker *omlox.Tracker, opts ...Option) *Tiler {
+	t := &Tiler{
+		tracker: tracker,
+		extent:  4096,
+		buffer:  64,
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// SetFences sets the fences of the tiles.
+func (t *Tiler) SetFences(fences []omlox.Fence) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.fences = fences
+}
+
+// tile is the position of a tile in the tiling scheme.
+type tile struct {
+	z, x, y int
+}
+
+// project returns the coordinates of a WGS84 point in the tile.
+func (t *Tiler) project(tl tile, p geometry.Point) [2]int32 {
+	n := math.Exp2(float64(tl.z))
+	lat := p.Y * math.Pi / 180
+
+	x := (p.X + 180) / 360 * n
+	y := (1 - math.Log(math.Tan(lat)+1/math.Cos(lat))/math.Pi) / 2 * n
+
+	return [2]int32{
+		int32(math.Round((x - float64(tl.x)) * float64(t.extent))),
+		int32(math.Round((y - float64(tl.y)) * float64(t.extent))),
+	}
+}
+
+// bounds returns the minimum and maximum WGS84 coordinates of the tile with its buffer.
+func (t *Tiler) bounds(tl tile) (geometry.Point, geometry.Point) {
+	n := math.Exp2(float64(tl.z))
+	buffer := float64(t.buffer) / float64(t.extent)
+
+	lon := func(x float64) float64 { return x/n*360 - 180 }
+	lat := func(y float64) float64 { return math.Atan(math.Sinh(math.Pi*(1-2*y/n))) * 180 / math.Pi }
+
+	return geometry.Point{X: lon(float64(tl.x) - buffer), Y: lat(float64(tl.y+1) + buffer)},
+		geometry.Point{X: lon(float64(tl.x+1) + buffer), Y: lat(float64(tl.y) - buffer)}
+}
+
+// Tile returns the vector tile of zoom level z, column x and row y.
+func (t *Tiler) Tile(z, x, y int) ([]byte, error) {
+	if z < 0 || z > maxZoom || x < 0 || y < 0 || x >= 1<<z || y >= 1<<z {
+		return nil, fmt.Errorf("%w: %d/%d/%d", ErrInvalidTile, z, x, y)
+	}
+	tl := tile{z: z, x: x, y: y}
+	min, max := t.bounds(tl)
+
+	trackables := newLayer(TrackablesLayer, t.extent)
+	for _, pos := range t.tracker.WithinBounds(omlox.CrsWGS84, min, max) {
+		loc := pos.Location
+
+		tags := trackables.tag("trackable_id", pos.TrackableID.String())
+		tags = append(tags, trackables.tag("provider_id", loc.ProviderID)...)
+		tags = append(tags, trackables.tag("provider_type", loc.ProviderType.String())...)
+		tags = append(tags, trackables.tag("at", pos.At.UnixMilli())...)
+		if loc.Floor != 0 {
+			tags = append(tags, trackables.tag("floor", loc.Floor)...)
+		}
+		if loc.Accuracy != nil {
+			tags = append(tags, trackables.tag("accuracy", *loc.Accuracy)...)
+		}
+
+		trackables.features = append(trackables.features, feature{
+			typ:      pointType,
+			tags:     tags,
+			geometry: [][][2]int32{{t.project(tl, loc.Position.Base())}},
+		})
+	}
+
+	fences := newLayer(FencesLayer, t.extent)
+	t.mu.RLock()
+	for _, f := range t.fences {
+		rings := fenceRings(f)
+		if len(rings) == 0 || !overlaps(rings[0], min, max) {
+			continue
+		}
+
+		feat := feature{typ: polygonType, tags: fences.tag("fence_id", f.ID.String())}
+		if f.Name != "" {
+			feat.tags = append(feat.tags, fences.tag("name", f.Name)...)
+		}
+		for i, ring := range rings {
+			if r := t.ring(tl, ring, i == 0); r != nil {
+				feat.geometry = append(feat.geometry, r)
+			} else if i == 0 {
+				break
+			}
+		}
+		if len(feat.geometry) > 0 {
+			fences.features = append(fences.features, feat)
+		}
+	}
+	t.mu.RUnlock()
+
+	var b buffer
+	for _, l := range []*layer{trackables, fences} {
+		if len(l.features) > 0 {
+			b.bytes(3, l.encode())
+		}
+	}
+
+	return b, nil
+}
+
+// ring returns a ring in tile coordinates, without repeated points and wound as vector
+// tiles expect: clockwise exterior rings and counterclockwise holes, with y pointing
+// down. Rings collapsing at the zoom level are nil.
+func (t *Tiler) ring(tl tile, ring []geometry.Point, exterior bool) [][2]int32 {
+	var r [][2]int32
+	for _, p := range ring {
+		q := t.project(tl, p)
+		if len(r) == 0 || q != r[len(r)-1] {
+			r = append(r, q)
+		}
+	}
+	if len(r) > 1 && r[0] == r[len(r)-1] {
+		r = r[:len(r)-1]
+	}
+
+	area := 0.0
+	for i, a := range r {
+		b := r[(i+1)%len(r)]
+		area += float64(a[0])*float64(b[1]) - float64(b[0])*float64(a[1])
+	}
+	if len(r) < 3 || area == 0 {
+		return nil
+	}
+
+	if (area > 0) != exterior {
+		for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+			r[i], r[j] = r[j], r[i]
+		}
+	}
+	return r
+}
+
+// fenceRings returns the WGS84 rings of a fence, the first one being the exterior, and
+// point fences as the circles of their radius.
+func fenceRings(f omlox.Fence) [][]geometry.Point {
+	if f.Region == nil || (f.Crs != "" && f.Crs != omlox.CrsWGS84) {
+		return nil
+	}
+
+	switch region := f.Region.Object.(type) {
+	case *geojson.Point:
+		if f.Radius <= 0 {
+			return nil
+		}
+		circle := omlox.Ellipse{SemiMajor: f.Radius, SemiMinor: f.Radius}
+		return [][]geometry.Point{circle.Ring(region.Base(), omlox.CrsWGS84, omlox.CircleSegments)}
+	case *geojson.Polygon:
+		poly := region.Base()
+
+		var rings [][]geometry.Point
+		for _, ring := range append([]geometry.Ring{poly.Exterior}, poly.Holes...) {
+			pts := make([]geometry.Point, ring.NumPoints())
+			for i := range pts {
+				pts[i] = ring.PointAt(i)
+			}
+			rings = append(rings, pts)
+		}
+		return rings
+	}
+
+	return nil
+}
+
+// overlaps reports whether the bounding box of a ring overlaps the minimum and maximum
+// coordinates.
+func overlaps(ring []geometry.Point, min, max geometry.Point) bool {
+	lo, hi := geometry.Point{X: math.Inf(1), Y: math.Inf(1)}, geometry.Point{X: math.Inf(-1), Y: math.Inf(-1)}
+	for _, p := range ring {
+		lo.X, lo.Y = math.Min(lo.X, p.X), math.Min(lo.Y, p.Y)
+		hi.X, hi.Y = math.Max(hi.X, p.X), math.Max(hi.Y, p.Y)
+	}
+	return lo.X <= max.X && hi.X >= min.X && lo.Y <= max.Y && hi.Y >= min.Y
+}
+
+// ServeHTTP serves the tiles of the z/x/y path, with an optional .mvt or .pbf extension.
+// Mount the tiler with http.StripPrefix to serve it under a path prefix.
+func (t *Tiler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSuffix(strings.TrimSuffix(strings.Trim(r.URL.Path, "/"), ".mvt"), ".pbf")
+
+	parts := strings.Split(path, "/")
+	if len(parts) != 3 {
+		http.NotFound(w, r)
+		return
+	}
+
+	var zxy [3]int
+	for i, s := range parts {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		zxy[i] = v
+	}
+
+	data, err := t.Tile(zxy[0], zxy[1], zxy[2])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("Cache-Control", "no-store")
+	if len(data) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Write(data)
+}
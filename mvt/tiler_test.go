@@ -0,0 +1,172 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+//go:build !omlox_minimal
+
+package mvt
+
+import (
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+// message is a decoded protocol buffers message, by field number.
+type message map[int][][]byte
+
+// decode decodes a message, with varints as their bytes.
+func decode(t *testing.T, b []byte) message {
+	t.Helper()
+
+	m := make(message)
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		b = b[n:]
+
+		field, wire := int(key>>3), key&0x7
+		switch wire {
+		case varintWire:
+			_, n = binary.Uvarint(b)
+		case fixed64:
+			n = 8
+		case bytesWire:
+			l, ln := binary.Uvarint(b)
+			b = b[ln:]
+			n = int(l)
+		default:
+			t.Fatalf("unexpected wire type %d", wire)
+		}
+		m[field] = append(m[field], b[:n])
+		b = b[n:]
+	}
+	return m
+}
+
+// varints decodes packed or single varints.
+func varints(b []byte) []uint64 {
+	var vs []uint64
+	for len(b) > 0 {
+		v, n := binary.Uvarint(b)
+		vs = append(vs, v)
+		b = b[n:]
+	}
+	return vs
+}
+
+func TestTile(t *testing.T) {
+	id := uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+	at := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	tr := omlox.NewTracker(omlox.WithSpatialIndex(omlox.CrsWGS84))
+	tr.Update(
+		// the center of tile 1/1/0
+		omlox.Location{Position: *omlox.NewPoint(geometry.Point{X: 90, Y: 66.51326}), Crs: omlox.CrsWGS84, ProviderID: "tag-1", ProviderType: omlox.LocationProviderTypeUwb, Trackables: []uuid.UUID{id}, TimestampGenerated: &at},
+		omlox.Location{Position: *omlox.NewPoint(geometry.Point{X: -90, Y: -66.5}), Crs: omlox.CrsWGS84, Trackables: []uuid.UUID{uuid.New()}},
+	)
+
+	tiler := NewTiler(tr)
+	tiler.SetFences([]omlox.Fence{
+		{ID: uuid.New(), Name: "dock", Region: omlox.NewRegionPoint(geometry.Point{X: 90, Y: 66.5}), Radius: 300000, Crs: omlox.CrsWGS84},
+		{ID: uuid.New(), Name: "far", Region: omlox.NewRegionPoint(geometry.Point{X: -90, Y: -66.5}), Radius: 1000, Crs: omlox.CrsWGS84},
+		{ID: uuid.New(), Name: "local", Region: omlox.NewRegionPoint(geometry.Point{X: 90, Y: 66.5}), Radius: 1, Crs: omlox.CrsLocal},
+	})
+
+	data, err := tiler.Tile(1, 1, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	layers := decode(t, data)[3]
+	if len(layers) != 2 {
+		t.Fatalf("expected 2 layers, got %d", len(layers))
+	}
+
+	trackables := decode(t, layers[0])
+	if name := string(trackables[1][0]); name != TrackablesLayer {
+		t.Errorf("expected the trackables layer, got %s", name)
+	}
+	if len(trackables[2]) != 1 {
+		t.Fatalf("expected 1 trackable, got %d", len(trackables[2]))
+	}
+	point := decode(t, trackables[2][0])
+	if geom := varints(point[4][0]); len(geom) != 3 || geom[0] != uint64(command(moveTo, 1)) || geom[1] != 4096 || geom[2] != 4096 {
+		t.Errorf("expected a point at the center of the tile, got %v", geom)
+	}
+	if keys := trackables[3]; len(keys) != 4 || string(keys[0]) != "trackable_id" || string(keys[3]) != "at" {
+		t.Errorf("unexpected keys %q", keys)
+	}
+	if values := trackables[4]; string(decode(t, values[0])[1][0]) != id.String() || varints(decode(t, values[3])[6][0])[0] != uint64(at.UnixMilli())<<1 {
+		t.Errorf("unexpected values %q", values)
+	}
+
+	fences := decode(t, layers[1])
+	if len(fences[2]) != 1 {
+		t.Fatalf("expected 1 fence, got %d", len(fences[2]))
+	}
+	if geom := varints(decode(t, fences[2][0])[4][0]); geom[3] != uint64(command(lineTo, omlox.CircleSegments-1)) || geom[len(geom)-1] != uint64(command(closePath, 1)) {
+		t.Errorf("unexpected polygon %v", geom)
+	}
+
+	if _, err := tiler.Tile(1, 2, 0); !errors.Is(err, ErrInvalidTile) {
+		t.Errorf("expected ErrInvalidTile, got %v", err)
+	}
+}
+
+func TestRingWinding(t *testing.T) {
+	tiler := NewTiler(omlox.NewTracker())
+	tl := tile{z: 10, x: 512, y: 511}
+
+	// a counterclockwise GeoJSON ring, clockwise with y pointing down
+	ccw := []geometry.Point{{X: 0.01, Y: 0.01}, {X: 0.2, Y: 0.01}, {X: 0.2, Y: 0.2}, {X: 0.01, Y: 0.2}, {X: 0.01, Y: 0.01}}
+
+	exterior := tiler.ring(tl, ccw, true)
+	hole := tiler.ring(tl, ccw, false)
+	if len(exterior) != 4 || exterior[0] != hole[3] || exterior[1] != hole[2] {
+		t.Errorf("expected the hole reversed, got %v and %v", exterior, hole)
+	}
+
+	tiny := []geometry.Point{{X: 0.01, Y: 0.01}, {X: 0.02, Y: 0.01}, {X: 0.02, Y: 0.02}}
+	if tiny := tiler.ring(tile{z: 0}, tiny, true); tiny != nil {
+		t.Errorf("expected the ring to collapse at zoom 0, got %v", tiny)
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	tr := omlox.NewTracker()
+	tr.Update(omlox.Location{Position: *omlox.NewPoint(geometry.Point{X: 8.68, Y: 50.11}), Crs: omlox.CrsWGS84, Trackables: []uuid.UUID{uuid.New()}})
+
+	srv := httptest.NewServer(http.StripPrefix("/tiles", NewTiler(tr)))
+	defer srv.Close()
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{path: "/tiles/0/0/0.mvt", status: http.StatusOK},
+		{path: "/tiles/1/0/1.pbf", status: http.StatusNoContent},
+		{path: "/tiles/1/0/2", status: http.StatusNotFound},
+		{path: "/tiles/0/0", status: http.StatusNotFound},
+	}
+
+	for _, tc := range tests {
+		resp, err := http.Get(srv.URL + tc.path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != tc.status {
+			t.Errorf("%s: expected status %d, got %d", tc.path, tc.status, resp.StatusCode)
+		}
+		if tc.status == http.StatusOK && resp.Header.Get("Content-Type") != ContentType {
+			t.Errorf("%s: unexpected content type %s", tc.path, resp.Header.Get("Content-Type"))
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package nats publishes omlox™ events to NATS, such as the events forwarded by the
+// forward package to the microservices of a facility.
+//
+// Events are published on the connections of the nats.go client, and acknowledged
+// by JetStream streams with its jetstream package. The package is a module of its
+// own, so the client of the hub does not depend on nats.go.
+package nats
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/forward"
+)
+
+// DefaultSubject is the default subject template of a sink.
+const DefaultSubject = "omlox.{topic}.{key}"
+
+// DefaultTimeout is the timeout of the deliveries without context deadline.
+const DefaultTimeout = 5 * time.Second
+
+// placeholders of subject templates
+var placeholder = regexp.MustCompile(`\{[^}]*\}`)
+
+// tokenReplacer replaces the characters not allowed in the tokens of subjects.
+var tokenReplacer = strings.NewReplacer(".", "_", "*", "_", ">", "_", " ", "_", "\t", "_", "\r", "_", "\n", "_")
+
+// Subject is a subject template, such as "omlox.{topic}.{key}". The {topic} placeholder
+// is replaced by the topic of an event, and {key} by its ordering key: the id of its
+// trackable, or of its location provider.
+type Subject string
+
+// validate checks the placeholders of a template.
+func (s Subject) validate() error {
+	if s == "" {
+		return fmt.Errorf("subject template must not be empty")
+	}
+	for _, p := range placeholder.FindAllString(string(s), -1) {
+		if p != "{topic}" && p != "{key}" {
+			return fmt.Errorf("subject template '%s' has unknown placeholder %s", s, p)
+		}
+	}
+	return nil
+}
+
+// Expand returns the subject of an event.
+func (s Subject) Expand(e forward.Event) string {
+	key := e.Key
+	if key == "" {
+		key = "_"
+	}
+	return strings.NewReplacer(
+		"{topic}", tokenReplacer.Replace(string(e.Topic)),
+		"{key}", tokenReplacer.Replace(key),
+	).Replace(string(s))
+}
+
+// SinkOption is a configuration option of a sink.
+type SinkOption func(*Sink)
+
+// WithTopicSubject sets the subject template of the events of a topic, overriding the
+// subject of the sink.
+func WithTopicSubject(topic omlox.Topic, subject Subject) SinkOption {
+	return func(s *Sink) {
+		s.topics[topic] = subject
+	}
+}
+
+// WithJetStream publishes the events as requests acknowledged by the JetStream streams
+// of their subjects. Events are deduplicated by their sequence number, so events
+// delivered again by the forwarder are only stored once.
+func WithJetStream() SinkOption {
+	return func(s *Sink) {
+		s.jetStream = true
+	}
+}
+
+// Sink is a sink of the forward package, publishing events to NATS subjects.
+//
+// Without JetStream, events are published and flushed, so a delivery succeeds once the
+// server has received the event.
+type Sink struct {
+	conn      *nats.Conn
+	js        jetstream.JetStream
+	subject   Subject
+	topics    map[omlox.Topic]Subject
+	jetStream bool
+}
+
+var _ forward.Sink = (*Sink)(nil)
+
+// NewSink returns a sink publishing events on a connection to the subjects of a
+// template, such as DefaultSubject.
+func NewSink(conn *nats.Conn, subject Subject, opts ...SinkOption) (*Sink, error) {
+	s := &Sink{
+		conn:    conn,
+		subject: subject,
+		topics:  make(map[omlox.Topic]Subject),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if err := s.subject.validate(); err != nil {
+		return nil, err
+	}
+	for _, subject := range s.topics {
+		if err := subject.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.jetStream {
+		js, err := jetstream.New(conn)
+		if err != nil {
+			return nil, err
+		}
+		s.js = js
+	}
+	return s, nil
+}
+
+// Deliver implements forward.Sink.
+func (s *Sink) Deliver(ctx context.Context, e forward.Event) error {
+	subject, ok := s.topics[e.Topic]
+	if !ok {
+		subject = s.subject
+	}
+	m := &nats.Msg{Subject: subject.Expand(e), Data: e.Payload}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultTimeout)
+		defer cancel()
+	}
+
+	if s.js == nil {
+		if err := s.conn.PublishMsg(m); err != nil {
+			return fmt.Errorf("publish %s: %w", m.Subject, err)
+		}
+		return s.conn.FlushWithContext(ctx)
+	}
+
+	if _, err := s.js.PublishMsg(ctx, m, jetstream.WithMsgID("omlox-"+strconv.FormatUint(e.Seq, 10))); err != nil {
+		return fmt.Errorf("publish %s: %w", m.Subject, err)
+	}
+	return nil
+}
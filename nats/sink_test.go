@@ -0,0 +1,244 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package nats
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/forward"
+)
+
+// message is a message published to the fake server.
+type message struct {
+	Subject string
+	Header  textproto.MIMEHeader
+	Data    []byte
+}
+
+// server is a fake NATS server. Requests to subjects starting with "js." are
+// acknowledged as by a JetStream stream, deduplicating message ids.
+type server struct {
+	l net.Listener
+
+	mu       sync.Mutex
+	messages []message
+	ids      map[string]bool
+}
+
+func newServer(t *testing.T) *server {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s := &server{l: l, ids: make(map[string]bool)}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *server) URL() string {
+	return "nats://" + s.l.Addr().String()
+}
+
+// Messages returns the messages published.
+func (s *server) Messages() []message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]message(nil), s.messages...)
+}
+
+func (s *server) serve(conn net.Conn) {
+	defer conn.Close()
+	fmt.Fprintf(conn, "INFO {\"server_id\":\"test\",\"proto\":1,\"headers\":true,\"max_payload\":1024}\r\n")
+
+	// subscriptions of the client, by sid
+	subs := make(map[string]string)
+
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		op, args, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+		fields := strings.Fields(args)
+
+		switch op {
+		case "PING":
+			io.WriteString(conn, "PONG\r\n")
+		case "SUB":
+			subs[fields[len(fields)-1]] = fields[0]
+		case "PUB", "HPUB":
+			m := message{Subject: fields[0]}
+			size, _ := strconv.Atoi(fields[len(fields)-1])
+			hsize := 0
+			if op == "HPUB" {
+				hsize, _ = strconv.Atoi(fields[len(fields)-2])
+			}
+			data := make([]byte, size+2)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return
+			}
+			if hsize > 0 {
+				_, header, _ := bytes.Cut(data[:hsize], []byte("\r\n"))
+				m.Header, _ = textproto.NewReader(bufio.NewReader(bytes.NewReader(header))).ReadMIMEHeader()
+			}
+			m.Data = data[hsize:size]
+
+			n := 2 // PUB <subject> [reply-to] <#bytes>
+			if op == "HPUB" {
+				n++
+			}
+			var reply string
+			if len(fields) > n {
+				reply = fields[1]
+			}
+			s.publish(conn, m, reply, subs)
+		}
+	}
+}
+
+// publish stores a message, replying to requests on the subscription of their reply subject.
+func (s *server) publish(conn net.Conn, m message, reply string, subs map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if reply == "" {
+		s.messages = append(s.messages, m)
+		return
+	}
+
+	var sid string
+	for id, subject := range subs {
+		if prefix, ok := strings.CutSuffix(subject, "*"); ok && strings.HasPrefix(reply, prefix) || subject == reply {
+			sid = id
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(m.Subject, "js."):
+		id := m.Header.Get(jetstream.MsgIDHeader)
+		ack := fmt.Sprintf(`{"stream":"OMLOX","seq":%d,"duplicate":%t}`, len(s.messages)+1, s.ids[id])
+		if !s.ids[id] {
+			s.ids[id] = true
+			s.messages = append(s.messages, m)
+		}
+		fmt.Fprintf(conn, "MSG %s %s %d\r\n%s\r\n", reply, sid, len(ack), ack)
+	case strings.HasPrefix(m.Subject, "full."):
+		ack := `{"error":{"code":503,"err_code":10077,"description":"maximum messages exceeded"}}`
+		fmt.Fprintf(conn, "MSG %s %s %d\r\n%s\r\n", reply, sid, len(ack), ack)
+	default:
+		header := "NATS/1.0 503\r\n\r\n"
+		fmt.Fprintf(conn, "HMSG %s %s %d %d\r\n%s\r\n", reply, sid, len(header), len(header), header)
+	}
+}
+
+func TestSubjectExpand(t *testing.T) {
+	tests := []struct {
+		subject Subject
+		event   forward.Event
+		want    string
+	}{
+		{DefaultSubject, forward.Event{Topic: omlox.TopicLocationUpdates, Key: "6c5d2f0e-8f6a-4b7e-9a51-0d6f1a2b3c41"}, "omlox.location_updates.6c5d2f0e-8f6a-4b7e-9a51-0d6f1a2b3c41"},
+		{DefaultSubject, forward.Event{Topic: omlox.TopicFenceEvents}, "omlox.fence_events._"},
+		{"fences.{key}", forward.Event{Topic: omlox.TopicFenceEvents, Key: "uwb.tag 1"}, "fences.uwb_tag_1"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.subject.Expand(tt.event); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.subject, tt.want, got)
+		}
+	}
+
+	if _, err := NewSink(nil, "omlox.{trackable}"); err == nil {
+		t.Errorf("expected an unknown placeholder error")
+	}
+	if _, err := NewSink(nil, DefaultSubject, WithTopicSubject(omlox.TopicFenceEvents, "")); err == nil {
+		t.Errorf("expected an empty subject error")
+	}
+}
+
+func TestSink(t *testing.T) {
+	srv := newServer(t)
+	ctx := context.Background()
+
+	c, err := nats.Connect(srv.URL())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer c.Close()
+
+	sink, err := NewSink(c, DefaultSubject, WithTopicSubject(omlox.TopicFenceEvents, "js.fences.{key}"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	location := forward.Event{Seq: 1, Topic: omlox.TopicLocationUpdates, Key: "tag-1", Payload: json.RawMessage(`{"provider_id":"tag-1"}`)}
+	if err := sink.Deliver(ctx, location); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	jetStream, err := NewSink(c, "js.{topic}.{key}", WithJetStream())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fence := forward.Event{Seq: 2, Topic: omlox.TopicFenceEvents, Key: "tag-1", Payload: json.RawMessage(`{}`)}
+	for i := 0; i < 2; i++ {
+		if err := jetStream.Deliver(ctx, fence); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	messages := srv.Messages()
+	if len(messages) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(messages))
+	}
+	if messages[0].Subject != "omlox.location_updates.tag-1" || string(messages[0].Data) != `{"provider_id":"tag-1"}` {
+		t.Errorf("unexpected message: %+v", messages[0])
+	}
+	if messages[1].Subject != "js.fence_events.tag-1" || messages[1].Header.Get(jetstream.MsgIDHeader) != "omlox-2" {
+		t.Errorf("unexpected message: %+v", messages[1])
+	}
+
+	full, err := NewSink(c, "full.{key}", WithJetStream())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := full.Deliver(ctx, fence); err == nil {
+		t.Errorf("expected a jetstream error")
+	}
+	missing, err := NewSink(c, "missing.{key}", WithJetStream())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := missing.Deliver(ctx, fence); !errors.Is(err, jetstream.ErrNoStreamResponse) {
+		t.Errorf("expected a no responders error, got %v", err)
+	}
+}
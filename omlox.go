@@ -9,4 +9,18 @@
 // from different providers.
 //
 // See more at https://omlox.com/.
+//
+// The omlox_minimal build tag leaves out the optional subsystems, such as the
+// in-memory Tracker, the fence geometry tools and floorplans, along with the
+// vector tile bridge built on them. The core client then compiles without cgo
+// into a small static binary for embedded gateways. The geojson geometries of the
+// models, along with the spatial indexes they depend on, are always linked:
+//
+//	CGO_ENABLED=0 GOARCH=arm64 go build -tags omlox_minimal
+//
+// The client also compiles to WebAssembly for browser tools, with REST requests
+// sent through the fetch API and the websocket interface opened with the WebSocket
+// API of the browser, which sends no custom headers with the handshake:
+//
+//	GOOS=js GOARCH=wasm go build
 package omlox
@@ -19,7 +19,202 @@ var (
 	_ easyjson.Marshaler
 )
 
-func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo(in *jlexer.Lexer, out *WrapperObject) {
+func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo(in *jlexer.Lexer, out *Zone) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			if data := in.UnsafeBytes(); in.Ok() {
+				in.AddError((out.ID).UnmarshalText(data))
+			}
+		case "type":
+			(out.Type).UnmarshalEasyJSON(in)
+		case "foreign_id":
+			out.ForeignID = string(in.String())
+		case "name":
+			out.Name = string(in.String())
+		case "description":
+			out.Description = string(in.String())
+		case "floor":
+			out.Floor = float64(in.Float64())
+		case "position":
+			if in.IsNull() {
+				in.Skip()
+				out.Position = nil
+			} else {
+				if out.Position == nil {
+					out.Position = new(Point)
+				}
+				(*out.Position).UnmarshalEasyJSON(in)
+			}
+		case "radius":
+			out.Radius = float64(in.Float64())
+		case "ground_control_points":
+			if in.IsNull() {
+				in.Skip()
+				out.GroundControlPoints = nil
+			} else {
+				in.Delim('[')
+				if out.GroundControlPoints == nil {
+					if !in.IsDelim(']') {
+						out.GroundControlPoints = make([]GroundControlPoint, 0, 1)
+					} else {
+						out.GroundControlPoints = []GroundControlPoint{}
+					}
+				} else {
+					out.GroundControlPoints = (out.GroundControlPoints)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 GroundControlPoint
+					(v1).UnmarshalEasyJSON(in)
+					out.GroundControlPoints = append(out.GroundControlPoints, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "incomplete_configuration":
+			out.IncompleteConfiguration = bool(in.Bool())
+		case "measurement_timestamp":
+			if in.IsNull() {
+				in.Skip()
+				out.MeasurementTimestamp = nil
+			} else {
+				if out.MeasurementTimestamp == nil {
+					out.MeasurementTimestamp = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.MeasurementTimestamp).UnmarshalJSON(data))
+				}
+			}
+		case "properties":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Properties).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo(out *jwriter.Writer, in Zone) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.ID).MarshalText())
+	}
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix)
+		(in.Type).MarshalEasyJSON(out)
+	}
+	if in.ForeignID != "" {
+		const prefix string = ",\"foreign_id\":"
+		out.RawString(prefix)
+		out.String(string(in.ForeignID))
+	}
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
+		out.RawString(prefix)
+		out.String(string(in.Name))
+	}
+	if in.Description != "" {
+		const prefix string = ",\"description\":"
+		out.RawString(prefix)
+		out.String(string(in.Description))
+	}
+	if in.Floor != 0 {
+		const prefix string = ",\"floor\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Floor))
+	}
+	if in.Position != nil {
+		const prefix string = ",\"position\":"
+		out.RawString(prefix)
+		(*in.Position).MarshalEasyJSON(out)
+	}
+	if in.Radius != 0 {
+		const prefix string = ",\"radius\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Radius))
+	}
+	if len(in.GroundControlPoints) != 0 {
+		const prefix string = ",\"ground_control_points\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v2, v3 := range in.GroundControlPoints {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				(v3).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.IncompleteConfiguration {
+		const prefix string = ",\"incomplete_configuration\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.IncompleteConfiguration))
+	}
+	if in.MeasurementTimestamp != nil {
+		const prefix string = ",\"measurement_timestamp\":"
+		out.RawString(prefix)
+		out.Raw((*in.MeasurementTimestamp).MarshalJSON())
+	}
+	if len(in.Properties) != 0 {
+		const prefix string = ",\"properties\":"
+		out.RawString(prefix)
+		out.Raw((in.Properties).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Zone) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Zone) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Zone) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Zone) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo(l, v)
+}
+func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo1(in *jlexer.Lexer, out *WrapperObject) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -62,11 +257,11 @@ func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo(in *jlexer.Lexer, o
 					out.Payload = (out.Payload)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v1 json.RawMessage
+					var v4 json.RawMessage
 					if data := in.Raw(); in.Ok() {
-						in.AddError((v1).UnmarshalJSON(data))
+						in.AddError((v4).UnmarshalJSON(data))
 					}
-					out.Payload = append(out.Payload, v1)
+					out.Payload = append(out.Payload, v4)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -84,9 +279,9 @@ func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo(in *jlexer.Lexer, o
 				for !in.IsDelim('}') {
 					key := string(in.String())
 					in.WantColon()
-					var v2 string
-					v2 = string(in.String())
-					(out.Params)[key] = v2
+					var v5 string
+					v5 = string(in.String())
+					(out.Params)[key] = v5
 					in.WantComma()
 				}
 				in.Delim('}')
@@ -101,7 +296,7 @@ func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo(in *jlexer.Lexer, o
 		in.Consumed()
 	}
 }
-func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo(out *jwriter.Writer, in WrapperObject) {
+func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo1(out *jwriter.Writer, in WrapperObject) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -125,11 +320,11 @@ func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo(out *jwriter.Writer
 		out.RawString(prefix)
 		{
 			out.RawByte('[')
-			for v3, v4 := range in.Payload {
-				if v3 > 0 {
+			for v6, v7 := range in.Payload {
+				if v6 > 0 {
 					out.RawByte(',')
 				}
-				out.Raw((v4).MarshalJSON())
+				out.Raw((v7).MarshalJSON())
 			}
 			out.RawByte(']')
 		}
@@ -139,16 +334,16 @@ func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo(out *jwriter.Writer
 		out.RawString(prefix)
 		{
 			out.RawByte('{')
-			v5First := true
-			for v5Name, v5Value := range in.Params {
-				if v5First {
-					v5First = false
+			v8First := true
+			for v8Name, v8Value := range in.Params {
+				if v8First {
+					v8First = false
 				} else {
 					out.RawByte(',')
 				}
-				out.String(string(v5Name))
+				out.String(string(v8Name))
 				out.RawByte(':')
-				out.String(string(v5Value))
+				out.String(string(v8Value))
 			}
 			out.RawByte('}')
 		}
@@ -159,27 +354,27 @@ func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo(out *jwriter.Writer
 // MarshalJSON supports json.Marshaler interface
 func (v WrapperObject) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo(&w, v)
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo1(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v WrapperObject) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo(w, v)
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo1(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *WrapperObject) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo(&r, v)
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo1(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *WrapperObject) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo(l, v)
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo1(l, v)
 }
-func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo1(in *jlexer.Lexer, out *WebsocketError) {
+func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo2(in *jlexer.Lexer, out *WebsocketError) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -212,7 +407,7 @@ func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo1(in *jlexer.Lexer,
 		in.Consumed()
 	}
 }
-func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo1(out *jwriter.Writer, in WebsocketError) {
+func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo2(out *jwriter.Writer, in WebsocketError) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -238,27 +433,27 @@ func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo1(out *jwriter.Write
 // MarshalJSON supports json.Marshaler interface
 func (v WebsocketError) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo1(&w, v)
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo2(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v WebsocketError) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo1(w, v)
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo2(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *WebsocketError) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo1(&r, v)
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo2(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *WebsocketError) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo1(l, v)
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo2(l, v)
 }
-func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo2(in *jlexer.Lexer, out *Trackable) {
+func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo3(in *jlexer.Lexer, out *Webhook) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -281,85 +476,56 @@ func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo2(in *jlexer.Lexer,
 			if data := in.UnsafeBytes(); in.Ok() {
 				in.AddError((out.ID).UnmarshalText(data))
 			}
-		case "type":
-			if data := in.Raw(); in.Ok() {
-				in.AddError((out.Type).UnmarshalJSON(data))
-			}
-		case "name":
-			out.Name = string(in.String())
-		case "geometry":
-			if in.IsNull() {
-				in.Skip()
-				out.Geometry = nil
-			} else {
-				if out.Geometry == nil {
-					out.Geometry = new(Polygon)
-				}
-				if data := in.Raw(); in.Ok() {
-					in.AddError((*out.Geometry).UnmarshalJSON(data))
-				}
-			}
-		case "extrusion":
-			out.Extrusion = float64(in.Float64())
-		case "location_providers":
+		case "url":
+			out.URL = string(in.String())
+		case "topics":
 			if in.IsNull() {
 				in.Skip()
-				out.LocationProviders = nil
+				out.Topics = nil
 			} else {
 				in.Delim('[')
-				if out.LocationProviders == nil {
+				if out.Topics == nil {
 					if !in.IsDelim(']') {
-						out.LocationProviders = make([]string, 0, 4)
+						out.Topics = make([]Topic, 0, 4)
 					} else {
-						out.LocationProviders = []string{}
+						out.Topics = []Topic{}
 					}
 				} else {
-					out.LocationProviders = (out.LocationProviders)[:0]
+					out.Topics = (out.Topics)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v6 string
-					v6 = string(in.String())
-					out.LocationProviders = append(out.LocationProviders, v6)
+					var v9 Topic
+					v9 = Topic(in.String())
+					out.Topics = append(out.Topics, v9)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
-		case "fence_timeout":
-			(out.FenceTimeout).UnmarshalEasyJSON(in)
-		case "exit_tolerance":
-			out.ExitTolerance = float64(in.Float64())
-		case "tolerance_timeout":
-			(out.ToleranceTimeout).UnmarshalEasyJSON(in)
-		case "exit_delay":
-			(out.ExitDelay).UnmarshalEasyJSON(in)
-		case "radius":
-			out.Radius = float64(in.Float64())
-		case "properties":
-			if data := in.Raw(); in.Ok() {
-				in.AddError((out.Properties).UnmarshalJSON(data))
-			}
-		case "locating_rules":
+		case "params":
 			if in.IsNull() {
 				in.Skip()
-				out.LocatingRules = nil
 			} else {
-				in.Delim('[')
-				if out.LocatingRules == nil {
-					if !in.IsDelim(']') {
-						out.LocatingRules = make([]LocatingRule, 0, 2)
-					} else {
-						out.LocatingRules = []LocatingRule{}
-					}
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Params = make(Parameters)
 				} else {
-					out.LocatingRules = (out.LocatingRules)[:0]
+					out.Params = nil
 				}
-				for !in.IsDelim(']') {
-					var v7 LocatingRule
-					easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo3(in, &v7)
-					out.LocatingRules = append(out.LocatingRules, v7)
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v10 string
+					v10 = string(in.String())
+					(out.Params)[key] = v10
 					in.WantComma()
 				}
-				in.Delim(']')
+				in.Delim('}')
+			}
+		case "secret":
+			out.Secret = string(in.String())
+		case "properties":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Properties).UnmarshalJSON(data))
 			}
 		default:
 			in.SkipRecursive()
@@ -371,7 +537,7 @@ func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo2(in *jlexer.Lexer,
 		in.Consumed()
 	}
 }
-func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo2(out *jwriter.Writer, in Trackable) {
+func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo3(out *jwriter.Writer, in Webhook) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -381,21 +547,219 @@ func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo2(out *jwriter.Write
 		out.RawText((in.ID).MarshalText())
 	}
 	{
-		const prefix string = ",\"type\":"
+		const prefix string = ",\"url\":"
 		out.RawString(prefix)
-		out.Raw((in.Type).MarshalJSON())
+		out.String(string(in.URL))
 	}
-	if in.Name != "" {
-		const prefix string = ",\"name\":"
+	{
+		const prefix string = ",\"topics\":"
 		out.RawString(prefix)
-		out.String(string(in.Name))
+		if in.Topics == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v11, v12 := range in.Topics {
+				if v11 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v12))
+			}
+			out.RawByte(']')
+		}
 	}
-	if in.Geometry != nil {
-		const prefix string = ",\"geometry\":"
+	if len(in.Params) != 0 {
+		const prefix string = ",\"params\":"
 		out.RawString(prefix)
-		out.Raw((*in.Geometry).MarshalJSON())
-	}
-	if in.Extrusion != 0 {
+		{
+			out.RawByte('{')
+			v13First := true
+			for v13Name, v13Value := range in.Params {
+				if v13First {
+					v13First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v13Name))
+				out.RawByte(':')
+				out.String(string(v13Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	if in.Secret != "" {
+		const prefix string = ",\"secret\":"
+		out.RawString(prefix)
+		out.String(string(in.Secret))
+	}
+	if len(in.Properties) != 0 {
+		const prefix string = ",\"properties\":"
+		out.RawString(prefix)
+		out.Raw((in.Properties).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Webhook) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo3(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Webhook) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo3(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Webhook) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo3(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Webhook) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo3(l, v)
+}
+func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo4(in *jlexer.Lexer, out *Trackable) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			if data := in.UnsafeBytes(); in.Ok() {
+				in.AddError((out.ID).UnmarshalText(data))
+			}
+		case "type":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Type).UnmarshalJSON(data))
+			}
+		case "name":
+			out.Name = string(in.String())
+		case "geometry":
+			if in.IsNull() {
+				in.Skip()
+				out.Geometry = nil
+			} else {
+				if out.Geometry == nil {
+					out.Geometry = new(Polygon)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.Geometry).UnmarshalJSON(data))
+				}
+			}
+		case "extrusion":
+			out.Extrusion = float64(in.Float64())
+		case "location_providers":
+			if in.IsNull() {
+				in.Skip()
+				out.LocationProviders = nil
+			} else {
+				in.Delim('[')
+				if out.LocationProviders == nil {
+					if !in.IsDelim(']') {
+						out.LocationProviders = make([]string, 0, 4)
+					} else {
+						out.LocationProviders = []string{}
+					}
+				} else {
+					out.LocationProviders = (out.LocationProviders)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v14 string
+					v14 = string(in.String())
+					out.LocationProviders = append(out.LocationProviders, v14)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "fence_timeout":
+			(out.FenceTimeout).UnmarshalEasyJSON(in)
+		case "exit_tolerance":
+			out.ExitTolerance = float64(in.Float64())
+		case "tolerance_timeout":
+			(out.ToleranceTimeout).UnmarshalEasyJSON(in)
+		case "exit_delay":
+			(out.ExitDelay).UnmarshalEasyJSON(in)
+		case "radius":
+			out.Radius = float64(in.Float64())
+		case "properties":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Properties).UnmarshalJSON(data))
+			}
+		case "locating_rules":
+			if in.IsNull() {
+				in.Skip()
+				out.LocatingRules = nil
+			} else {
+				in.Delim('[')
+				if out.LocatingRules == nil {
+					if !in.IsDelim(']') {
+						out.LocatingRules = make([]LocatingRule, 0, 2)
+					} else {
+						out.LocatingRules = []LocatingRule{}
+					}
+				} else {
+					out.LocatingRules = (out.LocatingRules)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v15 LocatingRule
+					easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo5(in, &v15)
+					out.LocatingRules = append(out.LocatingRules, v15)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo4(out *jwriter.Writer, in Trackable) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.ID).MarshalText())
+	}
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix)
+		out.Raw((in.Type).MarshalJSON())
+	}
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
+		out.RawString(prefix)
+		out.String(string(in.Name))
+	}
+	if in.Geometry != nil {
+		const prefix string = ",\"geometry\":"
+		out.RawString(prefix)
+		out.Raw((*in.Geometry).MarshalJSON())
+	}
+	if in.Extrusion != 0 {
 		const prefix string = ",\"extrusion\":"
 		out.RawString(prefix)
 		out.Float64(float64(in.Extrusion))
@@ -405,11 +769,11 @@ func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo2(out *jwriter.Write
 		out.RawString(prefix)
 		{
 			out.RawByte('[')
-			for v8, v9 := range in.LocationProviders {
-				if v8 > 0 {
+			for v16, v17 := range in.LocationProviders {
+				if v16 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v9))
+				out.String(string(v17))
 			}
 			out.RawByte(']')
 		}
@@ -449,11 +813,11 @@ func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo2(out *jwriter.Write
 		out.RawString(prefix)
 		{
 			out.RawByte('[')
-			for v10, v11 := range in.LocatingRules {
-				if v10 > 0 {
+			for v18, v19 := range in.LocatingRules {
+				if v18 > 0 {
 					out.RawByte(',')
 				}
-				easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo3(out, v11)
+				easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo5(out, v19)
 			}
 			out.RawByte(']')
 		}
@@ -464,27 +828,27 @@ func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo2(out *jwriter.Write
 // MarshalJSON supports json.Marshaler interface
 func (v Trackable) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo2(&w, v)
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo4(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v Trackable) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo2(w, v)
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo4(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *Trackable) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo2(&r, v)
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo4(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *Trackable) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo2(l, v)
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo4(l, v)
 }
-func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo3(in *jlexer.Lexer, out *LocatingRule) {
+func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo5(in *jlexer.Lexer, out *LocatingRule) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -517,7 +881,7 @@ func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo3(in *jlexer.Lexer,
 		in.Consumed()
 	}
 }
-func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo3(out *jwriter.Writer, in LocatingRule) {
+func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo5(out *jwriter.Writer, in LocatingRule) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -533,7 +897,7 @@ func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo3(out *jwriter.Write
 	}
 	out.RawByte('}')
 }
-func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo4(in *jlexer.Lexer, out *LocationProvider) {
+func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo6(in *jlexer.Lexer, out *SensorReading) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -552,33 +916,31 @@ func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo4(in *jlexer.Lexer,
 			continue
 		}
 		switch key {
-		case "id":
-			out.ID = string(in.String())
 		case "type":
 			if data := in.Raw(); in.Ok() {
 				in.AddError((out.Type).UnmarshalJSON(data))
 			}
 		case "name":
 			out.Name = string(in.String())
-		case "sensors":
-			if m, ok := out.Sensors.(easyjson.Unmarshaler); ok {
-				m.UnmarshalEasyJSON(in)
-			} else if m, ok := out.Sensors.(json.Unmarshaler); ok {
-				_ = m.UnmarshalJSON(in.Raw())
+		case "value":
+			out.Value = float64(in.Float64())
+		case "unit":
+			out.Unit = string(in.String())
+		case "timestamp":
+			if in.IsNull() {
+				in.Skip()
+				out.Timestamp = nil
 			} else {
-				out.Sensors = in.Interface()
+				if out.Timestamp == nil {
+					out.Timestamp = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.Timestamp).UnmarshalJSON(data))
+				}
 			}
-		case "fence_timeout":
-			(out.FenceTimeout).UnmarshalEasyJSON(in)
-		case "exit_tolerance":
-			out.ExitTolerance = float64(in.Float64())
-		case "tolerance_timeout":
-			(out.ToleranceTimeout).UnmarshalEasyJSON(in)
-		case "exit_delay":
-			(out.ExitDelay).UnmarshalEasyJSON(in)
-		case "properties":
+		case "raw":
 			if data := in.Raw(); in.Ok() {
-				in.AddError((out.Properties).UnmarshalJSON(data))
+				in.AddError((out.Raw).UnmarshalJSON(data))
 			}
 		default:
 			in.SkipRecursive()
@@ -590,18 +952,13 @@ func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo4(in *jlexer.Lexer,
 		in.Consumed()
 	}
 }
-func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo4(out *jwriter.Writer, in LocationProvider) {
+func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo6(out *jwriter.Writer, in SensorReading) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	{
-		const prefix string = ",\"id\":"
-		out.RawString(prefix[1:])
-		out.String(string(in.ID))
-	}
 	{
 		const prefix string = ",\"type\":"
-		out.RawString(prefix)
+		out.RawString(prefix[1:])
 		out.Raw((in.Type).MarshalJSON())
 	}
 	if in.Name != "" {
@@ -609,69 +966,53 @@ func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo4(out *jwriter.Write
 		out.RawString(prefix)
 		out.String(string(in.Name))
 	}
-	if in.Sensors != nil {
-		const prefix string = ",\"sensors\":"
-		out.RawString(prefix)
-		if m, ok := in.Sensors.(easyjson.Marshaler); ok {
-			m.MarshalEasyJSON(out)
-		} else if m, ok := in.Sensors.(json.Marshaler); ok {
-			out.Raw(m.MarshalJSON())
-		} else {
-			out.Raw(json.Marshal(in.Sensors))
-		}
-	}
-	if (in.FenceTimeout).IsDefined() {
-		const prefix string = ",\"fence_timeout\":"
-		out.RawString(prefix)
-		(in.FenceTimeout).MarshalEasyJSON(out)
-	}
-	if in.ExitTolerance != 0 {
-		const prefix string = ",\"exit_tolerance\":"
+	{
+		const prefix string = ",\"value\":"
 		out.RawString(prefix)
-		out.Float64(float64(in.ExitTolerance))
+		out.Float64(float64(in.Value))
 	}
-	if (in.ToleranceTimeout).IsDefined() {
-		const prefix string = ",\"tolerance_timeout\":"
+	if in.Unit != "" {
+		const prefix string = ",\"unit\":"
 		out.RawString(prefix)
-		(in.ToleranceTimeout).MarshalEasyJSON(out)
+		out.String(string(in.Unit))
 	}
-	if (in.ExitDelay).IsDefined() {
-		const prefix string = ",\"exit_delay\":"
+	if in.Timestamp != nil {
+		const prefix string = ",\"timestamp\":"
 		out.RawString(prefix)
-		(in.ExitDelay).MarshalEasyJSON(out)
+		out.Raw((*in.Timestamp).MarshalJSON())
 	}
-	if len(in.Properties) != 0 {
-		const prefix string = ",\"properties\":"
+	if len(in.Raw) != 0 {
+		const prefix string = ",\"raw\":"
 		out.RawString(prefix)
-		out.Raw((in.Properties).MarshalJSON())
+		out.Raw((in.Raw).MarshalJSON())
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v LocationProvider) MarshalJSON() ([]byte, error) {
+func (v SensorReading) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo4(&w, v)
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo6(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v LocationProvider) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo4(w, v)
+func (v SensorReading) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo6(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *LocationProvider) UnmarshalJSON(data []byte) error {
+func (v *SensorReading) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo4(&r, v)
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo6(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *LocationProvider) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo4(l, v)
+func (v *SensorReading) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo6(l, v)
 }
-func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo5(in *jlexer.Lexer, out *Location) {
+func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo7(in *jlexer.Lexer, out *SensorData) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -690,39 +1031,27 @@ func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo5(in *jlexer.Lexer,
 			continue
 		}
 		switch key {
-		case "position":
-			if data := in.Raw(); in.Ok() {
-				in.AddError((out.Position).UnmarshalJSON(data))
-			}
-		case "source":
-			out.Source = string(in.String())
-		case "provider_type":
-			if data := in.Raw(); in.Ok() {
-				in.AddError((out.ProviderType).UnmarshalJSON(data))
-			}
 		case "provider_id":
 			out.ProviderID = string(in.String())
-		case "trackables":
+		case "values":
 			if in.IsNull() {
 				in.Skip()
-				out.Trackables = nil
+				out.Values = nil
 			} else {
 				in.Delim('[')
-				if out.Trackables == nil {
+				if out.Values == nil {
 					if !in.IsDelim(']') {
-						out.Trackables = make([]uuid.UUID, 0, 4)
+						out.Values = make([]SensorReading, 0, 0)
 					} else {
-						out.Trackables = []uuid.UUID{}
+						out.Values = []SensorReading{}
 					}
 				} else {
-					out.Trackables = (out.Trackables)[:0]
+					out.Values = (out.Values)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v12 uuid.UUID
-					if data := in.UnsafeBytes(); in.Ok() {
-						in.AddError((v12).UnmarshalText(data))
-					}
-					out.Trackables = append(out.Trackables, v12)
+					var v20 SensorReading
+					(v20).UnmarshalEasyJSON(in)
+					out.Values = append(out.Values, v20)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -751,84 +1080,6 @@ func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo5(in *jlexer.Lexer,
 					in.AddError((*out.TimestampSent).UnmarshalJSON(data))
 				}
 			}
-		case "crs":
-			out.Crs = string(in.String())
-		case "associated":
-			out.Associated = bool(in.Bool())
-		case "accuracy":
-			if in.IsNull() {
-				in.Skip()
-				out.Accuracy = nil
-			} else {
-				if out.Accuracy == nil {
-					out.Accuracy = new(float64)
-				}
-				*out.Accuracy = float64(in.Float64())
-			}
-		case "floor":
-			out.Floor = float64(in.Float64())
-		case "true_heading":
-			if in.IsNull() {
-				in.Skip()
-				out.TrueHeading = nil
-			} else {
-				if out.TrueHeading == nil {
-					out.TrueHeading = new(float64)
-				}
-				*out.TrueHeading = float64(in.Float64())
-			}
-		case "magnetic_heading":
-			if in.IsNull() {
-				in.Skip()
-				out.MagneticHeading = nil
-			} else {
-				if out.MagneticHeading == nil {
-					out.MagneticHeading = new(float64)
-				}
-				*out.MagneticHeading = float64(in.Float64())
-			}
-		case "heading_accuracy":
-			if in.IsNull() {
-				in.Skip()
-				out.HeadingAccuracy = nil
-			} else {
-				if out.HeadingAccuracy == nil {
-					out.HeadingAccuracy = new(float64)
-				}
-				*out.HeadingAccuracy = float64(in.Float64())
-			}
-		case "elevation_ref":
-			if in.IsNull() {
-				in.Skip()
-				out.ElevationRef = nil
-			} else {
-				if out.ElevationRef == nil {
-					out.ElevationRef = new(ElevationRefType)
-				}
-				if data := in.Raw(); in.Ok() {
-					in.AddError((*out.ElevationRef).UnmarshalJSON(data))
-				}
-			}
-		case "speed":
-			if in.IsNull() {
-				in.Skip()
-				out.Speed = nil
-			} else {
-				if out.Speed == nil {
-					out.Speed = new(float64)
-				}
-				*out.Speed = float64(in.Float64())
-			}
-		case "course":
-			if in.IsNull() {
-				in.Skip()
-				out.Course = nil
-			} else {
-				if out.Course == nil {
-					out.Course = new(float64)
-				}
-				*out.Course = float64(in.Float64())
-			}
 		case "properties":
 			if data := in.Raw(); in.Ok() {
 				in.AddError((out.Properties).UnmarshalJSON(data))
@@ -843,40 +1094,27 @@ func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo5(in *jlexer.Lexer,
 		in.Consumed()
 	}
 }
-func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo5(out *jwriter.Writer, in Location) {
+func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo7(out *jwriter.Writer, in SensorData) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	{
-		const prefix string = ",\"position\":"
-		out.RawString(prefix[1:])
-		out.Raw((in.Position).MarshalJSON())
-	}
-	{
-		const prefix string = ",\"source\":"
-		out.RawString(prefix)
-		out.String(string(in.Source))
-	}
-	{
-		const prefix string = ",\"provider_type\":"
-		out.RawString(prefix)
-		out.Raw((in.ProviderType).MarshalJSON())
-	}
 	{
 		const prefix string = ",\"provider_id\":"
-		out.RawString(prefix)
+		out.RawString(prefix[1:])
 		out.String(string(in.ProviderID))
 	}
-	if len(in.Trackables) != 0 {
-		const prefix string = ",\"trackables\":"
+	{
+		const prefix string = ",\"values\":"
 		out.RawString(prefix)
-		{
+		if in.Values == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
 			out.RawByte('[')
-			for v13, v14 := range in.Trackables {
-				if v13 > 0 {
+			for v21, v22 := range in.Values {
+				if v21 > 0 {
 					out.RawByte(',')
 				}
-				out.RawText((v14).MarshalText())
+				(v22).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
@@ -891,56 +1129,6 @@ func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo5(out *jwriter.Write
 		out.RawString(prefix)
 		out.Raw((*in.TimestampSent).MarshalJSON())
 	}
-	if in.Crs != "" {
-		const prefix string = ",\"crs\":"
-		out.RawString(prefix)
-		out.String(string(in.Crs))
-	}
-	if in.Associated {
-		const prefix string = ",\"associated\":"
-		out.RawString(prefix)
-		out.Bool(bool(in.Associated))
-	}
-	if in.Accuracy != nil {
-		const prefix string = ",\"accuracy\":"
-		out.RawString(prefix)
-		out.Float64(float64(*in.Accuracy))
-	}
-	if in.Floor != 0 {
-		const prefix string = ",\"floor\":"
-		out.RawString(prefix)
-		out.Float64(float64(in.Floor))
-	}
-	if in.TrueHeading != nil {
-		const prefix string = ",\"true_heading\":"
-		out.RawString(prefix)
-		out.Float64(float64(*in.TrueHeading))
-	}
-	if in.MagneticHeading != nil {
-		const prefix string = ",\"magnetic_heading\":"
-		out.RawString(prefix)
-		out.Float64(float64(*in.MagneticHeading))
-	}
-	if in.HeadingAccuracy != nil {
-		const prefix string = ",\"heading_accuracy\":"
-		out.RawString(prefix)
-		out.Float64(float64(*in.HeadingAccuracy))
-	}
-	if in.ElevationRef != nil {
-		const prefix string = ",\"elevation_ref\":"
-		out.RawString(prefix)
-		out.Raw((*in.ElevationRef).MarshalJSON())
-	}
-	if in.Speed != nil {
-		const prefix string = ",\"speed\":"
-		out.RawString(prefix)
-		out.Float64(float64(*in.Speed))
-	}
-	if in.Course != nil {
-		const prefix string = ",\"course\":"
-		out.RawString(prefix)
-		out.Float64(float64(*in.Course))
-	}
 	if len(in.Properties) != 0 {
 		const prefix string = ",\"properties\":"
 		out.RawString(prefix)
@@ -950,29 +1138,29 @@ func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo5(out *jwriter.Write
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Location) MarshalJSON() ([]byte, error) {
+func (v SensorData) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo5(&w, v)
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo7(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Location) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo5(w, v)
+func (v SensorData) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo7(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Location) UnmarshalJSON(data []byte) error {
+func (v *SensorData) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo5(&r, v)
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo7(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Location) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo5(l, v)
+func (v *SensorData) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo7(l, v)
 }
-func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo6(in *jlexer.Lexer, out *Fence) {
+func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo8(in *jlexer.Lexer, out *LocationProvider) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -992,55 +1180,27 @@ func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo6(in *jlexer.Lexer,
 		}
 		switch key {
 		case "id":
-			if data := in.UnsafeBytes(); in.Ok() {
-				in.AddError((out.ID).UnmarshalText(data))
-			}
-		case "region":
-			if in.IsNull() {
-				in.Skip()
-				out.Region = nil
-			} else {
-				if out.Region == nil {
-					out.Region = new(Region)
-				}
-				if data := in.Raw(); in.Ok() {
-					in.AddError((*out.Region).UnmarshalJSON(data))
-				}
-			}
-		case "radius":
-			out.Radius = float64(in.Float64())
-		case "extrusion":
-			out.Extrusion = float64(in.Float64())
-		case "floor":
-			out.Floor = float64(in.Float64())
-		case "foreign_id":
-			out.ForeignID = string(in.String())
+			out.ID = string(in.String())
+		case "type":
+			(out.Type).UnmarshalEasyJSON(in)
 		case "name":
 			out.Name = string(in.String())
-		case "timeout":
-			(out.Timeout).UnmarshalEasyJSON(in)
+		case "sensors":
+			if m, ok := out.Sensors.(easyjson.Unmarshaler); ok {
+				m.UnmarshalEasyJSON(in)
+			} else if m, ok := out.Sensors.(json.Unmarshaler); ok {
+				_ = m.UnmarshalJSON(in.Raw())
+			} else {
+				out.Sensors = in.Interface()
+			}
+		case "fence_timeout":
+			(out.FenceTimeout).UnmarshalEasyJSON(in)
 		case "exit_tolerance":
 			out.ExitTolerance = float64(in.Float64())
 		case "tolerance_timeout":
 			(out.ToleranceTimeout).UnmarshalEasyJSON(in)
 		case "exit_delay":
 			(out.ExitDelay).UnmarshalEasyJSON(in)
-		case "crs":
-			out.Crs = string(in.String())
-		case "zone_id":
-			out.ZoneID = string(in.String())
-		case "elevation_ref":
-			if in.IsNull() {
-				in.Skip()
-				out.ElevationRef = nil
-			} else {
-				if out.ElevationRef == nil {
-					out.ElevationRef = new(ElevationRefType)
-				}
-				if data := in.Raw(); in.Ok() {
-					in.AddError((*out.ElevationRef).UnmarshalJSON(data))
-				}
-			}
 		case "properties":
 			if data := in.Raw(); in.Ok() {
 				in.AddError((out.Properties).UnmarshalJSON(data))
@@ -1055,53 +1215,40 @@ func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo6(in *jlexer.Lexer,
 		in.Consumed()
 	}
 }
-func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo6(out *jwriter.Writer, in Fence) {
+func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo8(out *jwriter.Writer, in LocationProvider) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
 		const prefix string = ",\"id\":"
 		out.RawString(prefix[1:])
-		out.RawText((in.ID).MarshalText())
+		out.String(string(in.ID))
 	}
 	{
-		const prefix string = ",\"region\":"
-		out.RawString(prefix)
-		if in.Region == nil {
-			out.RawString("null")
-		} else {
-			out.Raw((*in.Region).MarshalJSON())
-		}
-	}
-	if in.Radius != 0 {
-		const prefix string = ",\"radius\":"
-		out.RawString(prefix)
-		out.Float64(float64(in.Radius))
-	}
-	if in.Extrusion != 0 {
-		const prefix string = ",\"extrusion\":"
-		out.RawString(prefix)
-		out.Float64(float64(in.Extrusion))
-	}
-	if in.Floor != 0 {
-		const prefix string = ",\"floor\":"
-		out.RawString(prefix)
-		out.Float64(float64(in.Floor))
-	}
-	if in.ForeignID != "" {
-		const prefix string = ",\"foreign_id\":"
+		const prefix string = ",\"type\":"
 		out.RawString(prefix)
-		out.String(string(in.ForeignID))
+		(in.Type).MarshalEasyJSON(out)
 	}
 	if in.Name != "" {
 		const prefix string = ",\"name\":"
 		out.RawString(prefix)
 		out.String(string(in.Name))
 	}
-	if (in.Timeout).IsDefined() {
-		const prefix string = ",\"timeout\":"
+	if in.Sensors != nil {
+		const prefix string = ",\"sensors\":"
 		out.RawString(prefix)
-		(in.Timeout).MarshalEasyJSON(out)
+		if m, ok := in.Sensors.(easyjson.Marshaler); ok {
+			m.MarshalEasyJSON(out)
+		} else if m, ok := in.Sensors.(json.Marshaler); ok {
+			out.Raw(m.MarshalJSON())
+		} else {
+			out.Raw(json.Marshal(in.Sensors))
+		}
+	}
+	if (in.FenceTimeout).IsDefined() {
+		const prefix string = ",\"fence_timeout\":"
+		out.RawString(prefix)
+		(in.FenceTimeout).MarshalEasyJSON(out)
 	}
 	if in.ExitTolerance != 0 {
 		const prefix string = ",\"exit_tolerance\":"
@@ -1118,6 +1265,1258 @@ func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo6(out *jwriter.Write
 		out.RawString(prefix)
 		(in.ExitDelay).MarshalEasyJSON(out)
 	}
+	if len(in.Properties) != 0 {
+		const prefix string = ",\"properties\":"
+		out.RawString(prefix)
+		out.Raw((in.Properties).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v LocationProvider) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo8(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v LocationProvider) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo8(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *LocationProvider) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo8(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *LocationProvider) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo8(l, v)
+}
+func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo9(in *jlexer.Lexer, out *Location) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "position":
+			(out.Position).UnmarshalEasyJSON(in)
+		case "source":
+			out.Source = string(in.String())
+		case "provider_type":
+			(out.ProviderType).UnmarshalEasyJSON(in)
+		case "provider_id":
+			out.ProviderID = string(in.String())
+		case "trackables":
+			if in.IsNull() {
+				in.Skip()
+				out.Trackables = nil
+			} else {
+				in.Delim('[')
+				if out.Trackables == nil {
+					if !in.IsDelim(']') {
+						out.Trackables = make([]uuid.UUID, 0, 4)
+					} else {
+						out.Trackables = []uuid.UUID{}
+					}
+				} else {
+					out.Trackables = (out.Trackables)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v23 uuid.UUID
+					if data := in.UnsafeBytes(); in.Ok() {
+						in.AddError((v23).UnmarshalText(data))
+					}
+					out.Trackables = append(out.Trackables, v23)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "timestamp_generated":
+			if in.IsNull() {
+				in.Skip()
+				out.TimestampGenerated = nil
+			} else {
+				if out.TimestampGenerated == nil {
+					out.TimestampGenerated = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.TimestampGenerated).UnmarshalJSON(data))
+				}
+			}
+		case "timestamp_sent":
+			if in.IsNull() {
+				in.Skip()
+				out.TimestampSent = nil
+			} else {
+				if out.TimestampSent == nil {
+					out.TimestampSent = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.TimestampSent).UnmarshalJSON(data))
+				}
+			}
+		case "crs":
+			out.Crs = string(in.String())
+		case "associated":
+			out.Associated = bool(in.Bool())
+		case "accuracy":
+			if in.IsNull() {
+				in.Skip()
+				out.Accuracy = nil
+			} else {
+				if out.Accuracy == nil {
+					out.Accuracy = new(float64)
+				}
+				*out.Accuracy = float64(in.Float64())
+			}
+		case "covariance":
+			if in.IsNull() {
+				in.Skip()
+				out.Covariance = nil
+			} else {
+				in.Delim('[')
+				if out.Covariance == nil {
+					if !in.IsDelim(']') {
+						out.Covariance = make([][]float64, 0, 2)
+					} else {
+						out.Covariance = [][]float64{}
+					}
+				} else {
+					out.Covariance = (out.Covariance)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v24 []float64
+					if in.IsNull() {
+						in.Skip()
+						v24 = nil
+					} else {
+						in.Delim('[')
+						if v24 == nil {
+							if !in.IsDelim(']') {
+								v24 = make([]float64, 0, 8)
+							} else {
+								v24 = []float64{}
+							}
+						} else {
+							v24 = (v24)[:0]
+						}
+						for !in.IsDelim(']') {
+							var v25 float64
+							v25 = float64(in.Float64())
+							v24 = append(v24, v25)
+							in.WantComma()
+						}
+						in.Delim(']')
+					}
+					out.Covariance = append(out.Covariance, v24)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "floor":
+			out.Floor = float64(in.Float64())
+		case "true_heading":
+			if in.IsNull() {
+				in.Skip()
+				out.TrueHeading = nil
+			} else {
+				if out.TrueHeading == nil {
+					out.TrueHeading = new(float64)
+				}
+				*out.TrueHeading = float64(in.Float64())
+			}
+		case "magnetic_heading":
+			if in.IsNull() {
+				in.Skip()
+				out.MagneticHeading = nil
+			} else {
+				if out.MagneticHeading == nil {
+					out.MagneticHeading = new(float64)
+				}
+				*out.MagneticHeading = float64(in.Float64())
+			}
+		case "heading_accuracy":
+			if in.IsNull() {
+				in.Skip()
+				out.HeadingAccuracy = nil
+			} else {
+				if out.HeadingAccuracy == nil {
+					out.HeadingAccuracy = new(float64)
+				}
+				*out.HeadingAccuracy = float64(in.Float64())
+			}
+		case "elevation_ref":
+			if in.IsNull() {
+				in.Skip()
+				out.ElevationRef = nil
+			} else {
+				if out.ElevationRef == nil {
+					out.ElevationRef = new(ElevationRefType)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.ElevationRef).UnmarshalJSON(data))
+				}
+			}
+		case "speed":
+			if in.IsNull() {
+				in.Skip()
+				out.Speed = nil
+			} else {
+				if out.Speed == nil {
+					out.Speed = new(float64)
+				}
+				*out.Speed = float64(in.Float64())
+			}
+		case "course":
+			if in.IsNull() {
+				in.Skip()
+				out.Course = nil
+			} else {
+				if out.Course == nil {
+					out.Course = new(float64)
+				}
+				*out.Course = float64(in.Float64())
+			}
+		case "properties":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Properties).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo9(out *jwriter.Writer, in Location) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"position\":"
+		out.RawString(prefix[1:])
+		(in.Position).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"source\":"
+		out.RawString(prefix)
+		out.String(string(in.Source))
+	}
+	{
+		const prefix string = ",\"provider_type\":"
+		out.RawString(prefix)
+		(in.ProviderType).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"provider_id\":"
+		out.RawString(prefix)
+		out.String(string(in.ProviderID))
+	}
+	if len(in.Trackables) != 0 {
+		const prefix string = ",\"trackables\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v26, v27 := range in.Trackables {
+				if v26 > 0 {
+					out.RawByte(',')
+				}
+				out.RawText((v27).MarshalText())
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.TimestampGenerated != nil {
+		const prefix string = ",\"timestamp_generated\":"
+		out.RawString(prefix)
+		out.Raw((*in.TimestampGenerated).MarshalJSON())
+	}
+	if in.TimestampSent != nil {
+		const prefix string = ",\"timestamp_sent\":"
+		out.RawString(prefix)
+		out.Raw((*in.TimestampSent).MarshalJSON())
+	}
+	if in.Crs != "" {
+		const prefix string = ",\"crs\":"
+		out.RawString(prefix)
+		out.String(string(in.Crs))
+	}
+	if in.Associated {
+		const prefix string = ",\"associated\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Associated))
+	}
+	if in.Accuracy != nil {
+		const prefix string = ",\"accuracy\":"
+		out.RawString(prefix)
+		out.Float64(float64(*in.Accuracy))
+	}
+	if len(in.Covariance) != 0 {
+		const prefix string = ",\"covariance\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v28, v29 := range in.Covariance {
+				if v28 > 0 {
+					out.RawByte(',')
+				}
+				if v29 == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+					out.RawString("null")
+				} else {
+					out.RawByte('[')
+					for v30, v31 := range v29 {
+						if v30 > 0 {
+							out.RawByte(',')
+						}
+						out.Float64(float64(v31))
+					}
+					out.RawByte(']')
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Floor != 0 {
+		const prefix string = ",\"floor\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Floor))
+	}
+	if in.TrueHeading != nil {
+		const prefix string = ",\"true_heading\":"
+		out.RawString(prefix)
+		out.Float64(float64(*in.TrueHeading))
+	}
+	if in.MagneticHeading != nil {
+		const prefix string = ",\"magnetic_heading\":"
+		out.RawString(prefix)
+		out.Float64(float64(*in.MagneticHeading))
+	}
+	if in.HeadingAccuracy != nil {
+		const prefix string = ",\"heading_accuracy\":"
+		out.RawString(prefix)
+		out.Float64(float64(*in.HeadingAccuracy))
+	}
+	if in.ElevationRef != nil {
+		const prefix string = ",\"elevation_ref\":"
+		out.RawString(prefix)
+		out.Raw((*in.ElevationRef).MarshalJSON())
+	}
+	if in.Speed != nil {
+		const prefix string = ",\"speed\":"
+		out.RawString(prefix)
+		out.Float64(float64(*in.Speed))
+	}
+	if in.Course != nil {
+		const prefix string = ",\"course\":"
+		out.RawString(prefix)
+		out.Float64(float64(*in.Course))
+	}
+	if len(in.Properties) != 0 {
+		const prefix string = ",\"properties\":"
+		out.RawString(prefix)
+		out.Raw((in.Properties).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Location) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo9(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Location) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo9(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Location) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo9(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Location) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo9(l, v)
+}
+func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo10(in *jlexer.Lexer, out *HubInfo) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "name":
+			out.Name = string(in.String())
+		case "version":
+			out.Version = string(in.String())
+		case "extensions":
+			if in.IsNull() {
+				in.Skip()
+				out.Extensions = nil
+			} else {
+				in.Delim('[')
+				if out.Extensions == nil {
+					if !in.IsDelim(']') {
+						out.Extensions = make([]string, 0, 4)
+					} else {
+						out.Extensions = []string{}
+					}
+				} else {
+					out.Extensions = (out.Extensions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v32 string
+					v32 = string(in.String())
+					out.Extensions = append(out.Extensions, v32)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo10(out *jwriter.Writer, in HubInfo) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"version\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Version))
+	}
+	if len(in.Extensions) != 0 {
+		const prefix string = ",\"extensions\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v33, v34 := range in.Extensions {
+				if v33 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v34))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v HubInfo) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo10(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v HubInfo) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo10(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *HubInfo) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo10(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *HubInfo) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo10(l, v)
+}
+func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo11(in *jlexer.Lexer, out *GroundControlPoint) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "wgs84":
+			(out.WGS84).UnmarshalEasyJSON(in)
+		case "local":
+			(out.Local).UnmarshalEasyJSON(in)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo11(out *jwriter.Writer, in GroundControlPoint) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"wgs84\":"
+		out.RawString(prefix[1:])
+		(in.WGS84).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"local\":"
+		out.RawString(prefix)
+		(in.Local).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v GroundControlPoint) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo11(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GroundControlPoint) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo11(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *GroundControlPoint) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo11(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GroundControlPoint) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo11(l, v)
+}
+func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo12(in *jlexer.Lexer, out *FenceEvent) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			if data := in.UnsafeBytes(); in.Ok() {
+				in.AddError((out.ID).UnmarshalText(data))
+			}
+		case "fence_id":
+			if data := in.UnsafeBytes(); in.Ok() {
+				in.AddError((out.FenceID).UnmarshalText(data))
+			}
+		case "provider_id":
+			out.ProviderID = string(in.String())
+		case "trackables":
+			if in.IsNull() {
+				in.Skip()
+				out.Trackables = nil
+			} else {
+				in.Delim('[')
+				if out.Trackables == nil {
+					if !in.IsDelim(']') {
+						out.Trackables = make([]uuid.UUID, 0, 4)
+					} else {
+						out.Trackables = []uuid.UUID{}
+					}
+				} else {
+					out.Trackables = (out.Trackables)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v35 uuid.UUID
+					if data := in.UnsafeBytes(); in.Ok() {
+						in.AddError((v35).UnmarshalText(data))
+					}
+					out.Trackables = append(out.Trackables, v35)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "trackable_id":
+			if in.IsNull() {
+				in.Skip()
+				out.TrackableID = nil
+			} else {
+				if out.TrackableID == nil {
+					out.TrackableID = new(uuid.UUID)
+				}
+				if data := in.UnsafeBytes(); in.Ok() {
+					in.AddError((*out.TrackableID).UnmarshalText(data))
+				}
+			}
+		case "location":
+			if in.IsNull() {
+				in.Skip()
+				out.Location = nil
+			} else {
+				if out.Location == nil {
+					out.Location = new(Location)
+				}
+				(*out.Location).UnmarshalEasyJSON(in)
+			}
+		case "event_type":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.EventType).UnmarshalJSON(data))
+			}
+		case "entry_time":
+			if in.IsNull() {
+				in.Skip()
+				out.EntryTime = nil
+			} else {
+				if out.EntryTime == nil {
+					out.EntryTime = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.EntryTime).UnmarshalJSON(data))
+				}
+			}
+		case "exit_time":
+			if in.IsNull() {
+				in.Skip()
+				out.ExitTime = nil
+			} else {
+				if out.ExitTime == nil {
+					out.ExitTime = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.ExitTime).UnmarshalJSON(data))
+				}
+			}
+		case "foreign_id":
+			out.ForeignID = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo12(out *jwriter.Writer, in FenceEvent) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.ID).MarshalText())
+	}
+	{
+		const prefix string = ",\"fence_id\":"
+		out.RawString(prefix)
+		out.RawText((in.FenceID).MarshalText())
+	}
+	if in.ProviderID != "" {
+		const prefix string = ",\"provider_id\":"
+		out.RawString(prefix)
+		out.String(string(in.ProviderID))
+	}
+	if len(in.Trackables) != 0 {
+		const prefix string = ",\"trackables\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v36, v37 := range in.Trackables {
+				if v36 > 0 {
+					out.RawByte(',')
+				}
+				out.RawText((v37).MarshalText())
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.TrackableID != nil {
+		const prefix string = ",\"trackable_id\":"
+		out.RawString(prefix)
+		out.RawText((*in.TrackableID).MarshalText())
+	}
+	if in.Location != nil {
+		const prefix string = ",\"location\":"
+		out.RawString(prefix)
+		(*in.Location).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"event_type\":"
+		out.RawString(prefix)
+		out.Raw((in.EventType).MarshalJSON())
+	}
+	if in.EntryTime != nil {
+		const prefix string = ",\"entry_time\":"
+		out.RawString(prefix)
+		out.Raw((*in.EntryTime).MarshalJSON())
+	}
+	if in.ExitTime != nil {
+		const prefix string = ",\"exit_time\":"
+		out.RawString(prefix)
+		out.Raw((*in.ExitTime).MarshalJSON())
+	}
+	if in.ForeignID != "" {
+		const prefix string = ",\"foreign_id\":"
+		out.RawString(prefix)
+		out.String(string(in.ForeignID))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v FenceEvent) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo12(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v FenceEvent) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo12(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *FenceEvent) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo12(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *FenceEvent) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo12(l, v)
+}
+func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo13(in *jlexer.Lexer, out *Fence) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			if data := in.UnsafeBytes(); in.Ok() {
+				in.AddError((out.ID).UnmarshalText(data))
+			}
+		case "region":
+			if in.IsNull() {
+				in.Skip()
+				out.Region = nil
+			} else {
+				if out.Region == nil {
+					out.Region = new(Region)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.Region).UnmarshalJSON(data))
+				}
+			}
+		case "radius":
+			out.Radius = float64(in.Float64())
+		case "extrusion":
+			out.Extrusion = float64(in.Float64())
+		case "floor":
+			out.Floor = float64(in.Float64())
+		case "foreign_id":
+			out.ForeignID = string(in.String())
+		case "name":
+			out.Name = string(in.String())
+		case "timeout":
+			(out.Timeout).UnmarshalEasyJSON(in)
+		case "exit_tolerance":
+			out.ExitTolerance = float64(in.Float64())
+		case "tolerance_timeout":
+			(out.ToleranceTimeout).UnmarshalEasyJSON(in)
+		case "exit_delay":
+			(out.ExitDelay).UnmarshalEasyJSON(in)
+		case "crs":
+			out.Crs = string(in.String())
+		case "zone_id":
+			out.ZoneID = string(in.String())
+		case "elevation_ref":
+			if in.IsNull() {
+				in.Skip()
+				out.ElevationRef = nil
+			} else {
+				if out.ElevationRef == nil {
+					out.ElevationRef = new(ElevationRefType)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.ElevationRef).UnmarshalJSON(data))
+				}
+			}
+		case "properties":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Properties).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo13(out *jwriter.Writer, in Fence) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.ID).MarshalText())
+	}
+	{
+		const prefix string = ",\"region\":"
+		out.RawString(prefix)
+		if in.Region == nil {
+			out.RawString("null")
+		} else {
+			out.Raw((*in.Region).MarshalJSON())
+		}
+	}
+	if in.Radius != 0 {
+		const prefix string = ",\"radius\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Radius))
+	}
+	if in.Extrusion != 0 {
+		const prefix string = ",\"extrusion\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Extrusion))
+	}
+	if in.Floor != 0 {
+		const prefix string = ",\"floor\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Floor))
+	}
+	if in.ForeignID != "" {
+		const prefix string = ",\"foreign_id\":"
+		out.RawString(prefix)
+		out.String(string(in.ForeignID))
+	}
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
+		out.RawString(prefix)
+		out.String(string(in.Name))
+	}
+	if (in.Timeout).IsDefined() {
+		const prefix string = ",\"timeout\":"
+		out.RawString(prefix)
+		(in.Timeout).MarshalEasyJSON(out)
+	}
+	if in.ExitTolerance != 0 {
+		const prefix string = ",\"exit_tolerance\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.ExitTolerance))
+	}
+	if (in.ToleranceTimeout).IsDefined() {
+		const prefix string = ",\"tolerance_timeout\":"
+		out.RawString(prefix)
+		(in.ToleranceTimeout).MarshalEasyJSON(out)
+	}
+	if (in.ExitDelay).IsDefined() {
+		const prefix string = ",\"exit_delay\":"
+		out.RawString(prefix)
+		(in.ExitDelay).MarshalEasyJSON(out)
+	}
+	if in.Crs != "" {
+		const prefix string = ",\"crs\":"
+		out.RawString(prefix)
+		out.String(string(in.Crs))
+	}
+	if in.ZoneID != "" {
+		const prefix string = ",\"zone_id\":"
+		out.RawString(prefix)
+		out.String(string(in.ZoneID))
+	}
+	if in.ElevationRef != nil {
+		const prefix string = ",\"elevation_ref\":"
+		out.RawString(prefix)
+		out.Raw((*in.ElevationRef).MarshalJSON())
+	}
+	if len(in.Properties) != 0 {
+		const prefix string = ",\"properties\":"
+		out.RawString(prefix)
+		out.Raw((in.Properties).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Fence) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo13(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Fence) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo13(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Fence) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo13(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Fence) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo13(l, v)
+}
+func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo14(in *jlexer.Lexer, out *CollisionEvent) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "collision_type":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.CollisionType).UnmarshalJSON(data))
+			}
+		case "collisions":
+			if in.IsNull() {
+				in.Skip()
+				out.Collisions = nil
+			} else {
+				in.Delim('[')
+				if out.Collisions == nil {
+					if !in.IsDelim(']') {
+						out.Collisions = make([]Collision, 0, 2)
+					} else {
+						out.Collisions = []Collision{}
+					}
+				} else {
+					out.Collisions = (out.Collisions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v38 Collision
+					easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo15(in, &v38)
+					out.Collisions = append(out.Collisions, v38)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "timestamp_generated":
+			if in.IsNull() {
+				in.Skip()
+				out.TimestampGenerated = nil
+			} else {
+				if out.TimestampGenerated == nil {
+					out.TimestampGenerated = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.TimestampGenerated).UnmarshalJSON(data))
+				}
+			}
+		case "timestamp_sent":
+			if in.IsNull() {
+				in.Skip()
+				out.TimestampSent = nil
+			} else {
+				if out.TimestampSent == nil {
+					out.TimestampSent = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.TimestampSent).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo14(out *jwriter.Writer, in CollisionEvent) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"collision_type\":"
+		out.RawString(prefix[1:])
+		out.Raw((in.CollisionType).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"collisions\":"
+		out.RawString(prefix)
+		if in.Collisions == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v39, v40 := range in.Collisions {
+				if v39 > 0 {
+					out.RawByte(',')
+				}
+				easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo15(out, v40)
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.TimestampGenerated != nil {
+		const prefix string = ",\"timestamp_generated\":"
+		out.RawString(prefix)
+		out.Raw((*in.TimestampGenerated).MarshalJSON())
+	}
+	if in.TimestampSent != nil {
+		const prefix string = ",\"timestamp_sent\":"
+		out.RawString(prefix)
+		out.Raw((*in.TimestampSent).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v CollisionEvent) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo14(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v CollisionEvent) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo14(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *CollisionEvent) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo14(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *CollisionEvent) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo14(l, v)
+}
+func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo15(in *jlexer.Lexer, out *Collision) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "object_id":
+			if data := in.UnsafeBytes(); in.Ok() {
+				in.AddError((out.ObjectID).UnmarshalText(data))
+			}
+		case "position":
+			if in.IsNull() {
+				in.Skip()
+				out.Position = nil
+			} else {
+				if out.Position == nil {
+					out.Position = new(Point)
+				}
+				(*out.Position).UnmarshalEasyJSON(in)
+			}
+		case "radius":
+			out.Radius = float64(in.Float64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo15(out *jwriter.Writer, in Collision) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"object_id\":"
+		out.RawString(prefix[1:])
+		out.RawText((in.ObjectID).MarshalText())
+	}
+	if in.Position != nil {
+		const prefix string = ",\"position\":"
+		out.RawString(prefix)
+		(*in.Position).MarshalEasyJSON(out)
+	}
+	if in.Radius != 0 {
+		const prefix string = ",\"radius\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Radius))
+	}
+	out.RawByte('}')
+}
+func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo16(in *jlexer.Lexer, out *Anchor) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
+		case "name":
+			out.Name = string(in.String())
+		case "position":
+			(out.Position).UnmarshalEasyJSON(in)
+		case "crs":
+			out.Crs = string(in.String())
+		case "zone_id":
+			out.ZoneID = string(in.String())
+		case "floor":
+			out.Floor = float64(in.Float64())
+		case "air_interface":
+			(out.AirInterface).UnmarshalEasyJSON(in)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo16(out *jwriter.Writer, in Anchor) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
+		out.RawString(prefix)
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"position\":"
+		out.RawString(prefix)
+		(in.Position).MarshalEasyJSON(out)
+	}
 	if in.Crs != "" {
 		const prefix string = ",\"crs\":"
 		out.RawString(prefix)
@@ -1128,39 +2527,174 @@ func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo6(out *jwriter.Write
 		out.RawString(prefix)
 		out.String(string(in.ZoneID))
 	}
-	if in.ElevationRef != nil {
-		const prefix string = ",\"elevation_ref\":"
+	if in.Floor != 0 {
+		const prefix string = ",\"floor\":"
 		out.RawString(prefix)
-		out.Raw((*in.ElevationRef).MarshalJSON())
+		out.Float64(float64(in.Floor))
 	}
-	if len(in.Properties) != 0 {
-		const prefix string = ",\"properties\":"
+	{
+		const prefix string = ",\"air_interface\":"
 		out.RawString(prefix)
-		out.Raw((in.Properties).MarshalJSON())
+		(in.AirInterface).MarshalEasyJSON(out)
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Fence) MarshalJSON() ([]byte, error) {
+func (v Anchor) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo6(&w, v)
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo16(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Fence) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo6(w, v)
+func (v Anchor) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo16(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Fence) UnmarshalJSON(data []byte) error {
+func (v *Anchor) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo6(&r, v)
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo16(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Fence) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo6(l, v)
+func (v *Anchor) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo16(l, v)
+}
+func easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo17(in *jlexer.Lexer, out *AirInterface) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "channel":
+			out.Channel = int(in.Int())
+		case "prf":
+			out.PRF = int(in.Int())
+		case "preamble_length":
+			out.PreambleLength = int(in.Int())
+		case "preamble_code":
+			out.PreambleCode = int(in.Int())
+		case "data_rate":
+			out.DataRate = int(in.Int())
+		case "tx_power":
+			if in.IsNull() {
+				in.Skip()
+				out.TxPower = nil
+			} else {
+				if out.TxPower == nil {
+					out.TxPower = new(float64)
+				}
+				*out.TxPower = float64(in.Float64())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo17(out *jwriter.Writer, in AirInterface) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Channel != 0 {
+		const prefix string = ",\"channel\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.Int(int(in.Channel))
+	}
+	if in.PRF != 0 {
+		const prefix string = ",\"prf\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.PRF))
+	}
+	if in.PreambleLength != 0 {
+		const prefix string = ",\"preamble_length\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.PreambleLength))
+	}
+	if in.PreambleCode != 0 {
+		const prefix string = ",\"preamble_code\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.PreambleCode))
+	}
+	if in.DataRate != 0 {
+		const prefix string = ",\"data_rate\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.DataRate))
+	}
+	if in.TxPower != nil {
+		const prefix string = ",\"tx_power\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Float64(float64(*in.TxPower))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v AirInterface) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo17(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v AirInterface) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonF70c4027EncodeGithubComWavecomtechOmloxClientGo17(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *AirInterface) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo17(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *AirInterface) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonF70c4027DecodeGithubComWavecomtechOmloxClientGo17(l, v)
 }
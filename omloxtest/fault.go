@@ -0,0 +1,320 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omloxtest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wavecomtech/omlox-client-go"
+	"nhooyr.io/websocket"
+)
+
+// Fault is a failure injected by the fake hub on the requests, or websocket
+// messages, matching an endpoint.
+//
+// Faults are injected deterministically: the first After matches are served
+// normally, then every Every-th match is faulted, up to Times faults.
+// For example, a burst of three 503 responses after the tenth request is
+// Fault{After: 10, Times: 3, Status: 503}.
+type Fault struct {
+	// Latency added before serving.
+	Latency time.Duration
+
+	// Status code of the error response returned instead of serving the request.
+	// Not applicable to websocket messages.
+	Status int
+
+	// Drop closes the connection without responding. For websocket messages,
+	// the socket of the subscriber is closed instead of sending the message.
+	Drop bool
+
+	// Malformed responds with, or sends, a payload which is not valid JSON.
+	Malformed bool
+
+	// Number of matches served normally before injecting faults.
+	After int
+
+	// Inject the fault on every n-th match. Zero or one faults every match.
+	Every int
+
+	// Maximum number of faults injected. Zero is unlimited.
+	Times int
+}
+
+// malformedPayload is sent by faults injecting malformed payloads.
+const malformedPayload = `{"event":"message","payload":[{"position":`
+
+// wsEndpointPrefix is the method of the endpoints matching the websocket
+// messages of a topic (e.g. "WS location_updates").
+const wsEndpointPrefix = "WS"
+
+// faultRule is a fault registered for an endpoint.
+type faultRule struct {
+	method   string
+	segments []string
+	fault    Fault
+
+	// number of matches and faults injected so far
+	matches  int
+	injected int
+}
+
+// match reports whether the rule applies to the method and the path segments.
+func (r *faultRule) match(method string, segments []string) bool {
+	if r.method != "" && r.method != method {
+		return false
+	}
+	if len(r.segments) != len(segments) {
+		return false
+	}
+	for i, s := range r.segments {
+		if s != "*" && s != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// next counts a match, reporting whether the fault is injected on it.
+func (r *faultRule) next() bool {
+	r.matches++
+
+	f := r.fault
+	if r.matches <= f.After {
+		return false
+	}
+	if f.Times > 0 && r.injected >= f.Times {
+		return false
+	}
+	if f.Every > 1 && (r.matches-f.After)%f.Every != 0 {
+		return false
+	}
+
+	r.injected++
+	return true
+}
+
+// InjectFault registers a fault on an endpoint.
+//
+// REST endpoints are given as "METHOD /path", relative to the API prefix, where
+// the method is optional and path segments can be "*" wildcards
+// (e.g. "PUT /providers/*/location"). The websocket handshake is "GET /ws/socket".
+// Websocket messages sent to subscribers of a topic are given as "WS <topic>"
+// (e.g. "WS location_updates").
+//
+// When several faults are injected on the same match, the first registered is used.
+func (h *Hub) InjectFault(endpoint string, f Fault) error {
+	method, path, ok := strings.Cut(strings.TrimSpace(endpoint), " ")
+	if !ok {
+		method, path = "", method
+	}
+
+	var segments []string
+	if method == wsEndpointPrefix {
+		if path == "" {
+			return fmt.Errorf("websocket endpoint %q without topic", endpoint)
+		}
+		segments = []string{path}
+	} else {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("endpoint %q path must be absolute", endpoint)
+		}
+		segments = strings.Split(strings.Trim(path, "/"), "/")
+	}
+
+	h.faultsMu.Lock()
+	h.faults = append(h.faults, &faultRule{
+		method:   strings.ToUpper(method),
+		segments: segments,
+		fault:    f,
+	})
+	h.faultsMu.Unlock()
+
+	return nil
+}
+
+// ClearFaults removes all the registered faults.
+func (h *Hub) ClearFaults() {
+	h.faultsMu.Lock()
+	h.faults = nil
+	h.faultsMu.Unlock()
+}
+
+// Injected returns the number of faults injected on an endpoint, as given to InjectFault.
+func (h *Hub) Injected(endpoint string) int {
+	method, path, ok := strings.Cut(strings.TrimSpace(endpoint), " ")
+	if !ok {
+		method, path = "", method
+	}
+
+	h.faultsMu.Lock()
+	defer h.faultsMu.Unlock()
+
+	n := 0
+	for _, r := range h.faults {
+		if r.method == strings.ToUpper(method) && strings.Join(r.segments, "/") == strings.Trim(path, "/") {
+			n += r.injected
+		}
+	}
+
+	return n
+}
+
+// fault returns the fault to inject on a request or message, if any.
+func (h *Hub) fault(method string, segments []string) *Fault {
+	h.faultsMu.Lock()
+	defer h.faultsMu.Unlock()
+
+	var injected *Fault
+	for _, r := range h.faults {
+		// every matching rule counts the match, even if another one is injected
+		if r.match(method, segments) && r.next() && injected == nil {
+			f := r.fault
+			injected = &f
+		}
+	}
+
+	return injected
+}
+
+// serveFault injects a fault on a request.
+// It reports whether the request was handled by the fault.
+func serveFault(w http.ResponseWriter, r *http.Request, f *Fault) bool {
+	if !sleep(r.Context(), f.Latency) {
+		return true
+	}
+
+	switch {
+	case f.Drop:
+		if hj, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hj.Hijack(); err == nil {
+				conn.Close()
+				return true
+			}
+		}
+		// abort the handler, which makes the server drop the connection
+		panic(http.ErrAbortHandler)
+	case f.Status != 0:
+		writeError(w, f.Status, "injected fault")
+		return true
+	case f.Malformed:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(malformedPayload))
+		return true
+	}
+
+	return false
+}
+
+// sendFault injects a fault on a websocket message sent to a subscriber.
+// It reports whether the message was handled by the fault.
+func (c *conn) sendFault(ctx context.Context, f *Fault) bool {
+	if !sleep(ctx, f.Latency) {
+		return true
+	}
+
+	switch {
+	case f.Drop:
+		c.ws.CloseNow()
+		return true
+	case f.Malformed:
+		c.mu.Lock()
+		c.ws.Write(ctx, websocket.MessageText, []byte(malformedPayload))
+		c.mu.Unlock()
+		return true
+	}
+
+	return false
+}
+
+// DropConnections abruptly closes every websocket connection, as a network
+// failure would.
+func (h *Hub) DropConnections() {
+	h.connsMu.Lock()
+	conns := make([]*conn, 0, len(h.conns))
+	for c := range h.conns {
+		conns = append(conns, c)
+	}
+	h.connsMu.Unlock()
+
+	for _, c := range conns {
+		c.ws.CloseNow()
+	}
+}
+
+// sleep waits for the duration, reporting false if ctx is done first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// ParseFault parses a fault given as "<endpoint>:<key>=<value>,...", as used by
+// the CLI. The keys are the lower case Fault fields, with durations in Go syntax
+// and booleans optionally without value (e.g. "PUT /providers/*/location:status=503,every=10"
+// or "WS location_updates:drop,after=100,times=1").
+func ParseFault(s string) (endpoint string, f Fault, err error) {
+	// topics may contain colons, the spec follows the last one
+	i := strings.LastIndex(s, ":")
+	if i < 0 || strings.TrimSpace(s[:i]) == "" {
+		return "", Fault{}, fmt.Errorf("invalid fault %q: expected <endpoint>:<key>=<value>,...", s)
+	}
+	endpoint, spec := s[:i], s[i+1:]
+
+	for _, kv := range strings.Split(spec, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(kv), "=")
+
+		switch key {
+		case "latency":
+			f.Latency, err = time.ParseDuration(value)
+		case "status":
+			f.Status, err = strconv.Atoi(value)
+		case "drop":
+			f.Drop, err = parseFaultBool(value)
+		case "malformed":
+			f.Malformed, err = parseFaultBool(value)
+		case "after":
+			f.After, err = strconv.Atoi(value)
+		case "every":
+			f.Every, err = strconv.Atoi(value)
+		case "times":
+			f.Times, err = strconv.Atoi(value)
+		default:
+			err = fmt.Errorf("unknown key %q", key)
+		}
+		if err != nil {
+			return "", Fault{}, fmt.Errorf("invalid fault %q: %w", s, err)
+		}
+	}
+
+	return strings.TrimSpace(endpoint), f, nil
+}
+
+func parseFaultBool(s string) (bool, error) {
+	if s == "" {
+		return true, nil
+	}
+	return strconv.ParseBool(s)
+}
+
+// wsSegments are the endpoint segments of the websocket messages of a topic.
+func wsSegments(topic omlox.Topic) []string {
+	return []string{string(topic)}
+}
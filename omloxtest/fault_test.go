@@ -0,0 +1,231 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omloxtest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+func TestParseFault(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		endpoint string
+		fault    Fault
+		wantErr  bool
+	}{
+		{
+			name:     "status burst",
+			input:    "PUT /providers/*/location:status=503,after=10,times=3",
+			endpoint: "PUT /providers/*/location",
+			fault:    Fault{Status: 503, After: 10, Times: 3},
+		},
+		{
+			name:     "topic with colon",
+			input:    "WS location_updates:geojson:drop,every=5",
+			endpoint: "WS location_updates:geojson",
+			fault:    Fault{Drop: true, Every: 5},
+		},
+		{
+			name:     "latency and malformed",
+			input:    "GET /trackables/summary:latency=20ms,malformed=true",
+			endpoint: "GET /trackables/summary",
+			fault:    Fault{Latency: 20 * time.Millisecond, Malformed: true},
+		},
+		{
+			name:    "missing spec",
+			input:   "GET /trackables",
+			wantErr: true,
+		},
+		{
+			name:    "unknown key",
+			input:   "GET /trackables:explode",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint, fault, err := ParseFault(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFault() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if endpoint != tt.endpoint || fault != tt.fault {
+				t.Fatalf("ParseFault() = %q %+v, want %q %+v", endpoint, fault, tt.endpoint, tt.fault)
+			}
+		})
+	}
+}
+
+func TestFaultStatusBurst(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	if err := srv.InjectFault("GET /providers", Fault{Status: 503, After: 2, Times: 3}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c, err := omlox.New(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []int
+	for i := 0; i < 7; i++ {
+		code := 200
+		if _, err := c.Providers.IDs(context.Background()); err != nil {
+			var oerr *omlox.Error
+			if !errors.As(err, &oerr) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			code = oerr.Code
+		}
+		got = append(got, code)
+	}
+
+	want := []int{200, 200, 503, 503, 503, 200, 200}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected status codes %v, got %v", want, got)
+		}
+	}
+
+	if n := srv.Injected("GET /providers"); n != 3 {
+		t.Fatalf("expected 3 injected faults, got %d", n)
+	}
+}
+
+func TestFaultMalformedAndDrop(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.InjectFault("GET /trackables/summary", Fault{Malformed: true})
+	srv.InjectFault("/fences/summary", Fault{Drop: true})
+
+	c, err := omlox.New(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+
+	if _, err := c.Trackables.List(ctx); err == nil {
+		t.Fatalf("expected error on malformed response")
+	}
+	if _, err := c.Fences.List(ctx); err == nil {
+		t.Fatalf("expected error on dropped connection")
+	}
+
+	srv.ClearFaults()
+
+	if _, err := c.Trackables.List(ctx); err != nil {
+		t.Fatalf("unexpected error after clearing faults: %v", err)
+	}
+}
+
+func TestFaultLatency(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.InjectFault("GET /info", Fault{Latency: 50 * time.Millisecond})
+
+	c, err := omlox.New(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	start := time.Now()
+	if _, err := c.Info(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := time.Since(start); d < 50*time.Millisecond {
+		t.Fatalf("expected injected latency, request took %v", d)
+	}
+}
+
+func TestFaultWebsocketReconnect(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	// the second location update drops the subscriber socket
+	srv.InjectFault("WS location_updates", Fault{Drop: true, After: 1, Times: 1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var (
+		mu     sync.Mutex
+		states []omlox.ConnState
+	)
+	handler := func(change omlox.ConnStateChange) {
+		mu.Lock()
+		defer mu.Unlock()
+		states = append(states, change.State)
+	}
+
+	c, err := omlox.New(srv.URL, omlox.WithReconnect(10*time.Millisecond, 50*time.Millisecond), omlox.WithConnStateHandler(handler))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sub, err := c.Subscribe(ctx, omlox.TopicLocationUpdates)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	msgs := sub.ReceiveRaw()
+
+	publish := func(id string) {
+		srv.Publish(ctx, omlox.TopicLocationUpdates, []byte(`{"provider_id":"`+id+`"}`))
+	}
+
+	publish("p1")
+	select {
+	case <-msgs:
+	case <-ctx.Done():
+		t.Fatalf("location update was not received")
+	}
+
+	publish("p2")
+
+	// once resubscribed, updates are received again
+resubscribed:
+	for {
+		publish("p3")
+
+		select {
+		case msg := <-msgs:
+			if string(msg.Payload[0]) != `{"provider_id":"p3"}` {
+				t.Fatalf("unexpected message %s", msg.Payload[0])
+			}
+			break resubscribed
+		case <-time.After(20 * time.Millisecond):
+		case <-ctx.Done():
+			t.Fatalf("subscription was not restored after the dropped socket")
+		}
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state := c.ConnState(); state != omlox.ConnStateClosed {
+		t.Errorf("expected the closed state, got %s", state)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []omlox.ConnState{omlox.ConnStateConnected, omlox.ConnStateDisconnected, omlox.ConnStateReconnecting, omlox.ConnStateConnected, omlox.ConnStateClosed}
+	if diff := cmp.Diff(want, states); diff != "" {
+		t.Errorf("unexpected connection states (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,536 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package omloxtest provides an in-memory fake Omlox™ Hub for tests, demos and
+// load testing of the client without a running hub.
+//
+// The fake hub implements the subset of the REST and websocket APIs used by the
+// client: the resource collections (trackables, providers, fences and zones),
+// provider location updates and the websocket publish/subscribe protocol.
+// Like DeepHub, broadcasted websocket messages do not carry a subscription id.
+package omloxtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go"
+	"nhooyr.io/websocket"
+)
+
+// Version reported by the fake hub on the info endpoint.
+const Version = "1.1.0"
+
+// collections served by the fake hub, keyed by their path segment.
+var collections = []string{"trackables", "providers", "fences", "zones"}
+
+// Hub is an in-memory fake Omlox™ Hub. It implements http.Handler, serving the
+// API under the "/v2" prefix. The zero value is not usable, use NewHub.
+type Hub struct {
+	mu        sync.RWMutex
+	resources map[string]map[string]json.RawMessage
+	locations map[string]json.RawMessage
+
+	// websocket connections and their subscriptions
+	connsMu sync.Mutex
+	conns   map[*conn]struct{}
+	nextSID int
+
+	// injected faults
+	faultsMu sync.Mutex
+	faults   []*faultRule
+}
+
+var _ http.Handler = (*Hub)(nil)
+
+// NewHub returns an empty fake hub.
+func NewHub() *Hub {
+	h := &Hub{
+		resources: make(map[string]map[string]json.RawMessage, len(collections)),
+		locations: make(map[string]json.RawMessage),
+		conns:     make(map[*conn]struct{}),
+	}
+
+	for _, c := range collections {
+		h.resources[c] = make(map[string]json.RawMessage)
+	}
+
+	return h
+}
+
+// Server is a fake hub listening on a local loopback address.
+type Server struct {
+	*Hub
+
+	// URL of the hub API, suitable for omlox.New.
+	URL string
+
+	srv *httptest.Server
+}
+
+// NewServer starts and returns a new fake hub server.
+// The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	hub := NewHub()
+	srv := httptest.NewServer(hub)
+
+	return &Server{
+		Hub: hub,
+		URL: srv.URL + "/v2",
+		srv: srv,
+	}
+}
+
+// Close closes the websocket connections and shuts down the server.
+func (s *Server) Close() {
+	s.Hub.closeConns()
+	s.srv.Close()
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path, ok := strings.CutPrefix(r.URL.Path, "/v2/")
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	if f := h.fault(r.Method, segments); f != nil && serveFault(w, r, f) {
+		return
+	}
+
+	switch {
+	case path == "info" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, omlox.HubInfo{Name: "omloxtest", Version: Version})
+	case path == "ws/socket":
+		h.serveWebsocket(w, r)
+	case len(segments) == 3 && segments[0] == "providers" && segments[2] == "location":
+		h.serveLocation(w, r, segments[1])
+	case h.isCollection(segments[0]):
+		h.serveCollection(w, r, segments)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (h *Hub) isCollection(name string) bool {
+	_, ok := h.resources[name]
+	return ok
+}
+
+// serveCollection serves the CRUD endpoints of a resource collection.
+func (h *Hub) serveCollection(w http.ResponseWriter, r *http.Request, segments []string) {
+	name := segments[0]
+
+	switch {
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, h.ids(name))
+	case len(segments) == 1 && r.Method == http.MethodPost:
+		h.create(w, r, name)
+	case len(segments) == 1 && r.Method == http.MethodDelete:
+		h.mu.Lock()
+		h.resources[name] = make(map[string]json.RawMessage)
+		h.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	case len(segments) == 2 && segments[1] == "summary" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, h.List(name))
+	case len(segments) == 2 && r.Method == http.MethodGet:
+		res, ok := h.Get(name, segments[1])
+		if !ok {
+			writeError(w, http.StatusNotFound, "resource not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	case len(segments) == 2 && r.Method == http.MethodPut:
+		h.update(w, r, name, segments[1])
+	case len(segments) == 2 && r.Method == http.MethodDelete:
+		h.mu.Lock()
+		_, ok := h.resources[name][segments[1]]
+		delete(h.resources[name], segments[1])
+		h.mu.Unlock()
+		if !ok {
+			writeError(w, http.StatusNotFound, "resource not found")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (h *Hub) create(w http.ResponseWriter, r *http.Request, name string) {
+	var res map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// the client sends the nil uuid for trackables and fences without id
+	id, _ := res["id"].(string)
+	if id == "" || id == uuid.Nil.String() {
+		if name == "providers" {
+			writeError(w, http.StatusBadRequest, "provider id is required")
+			return
+		}
+		id = uuid.NewString()
+		res["id"] = id
+	}
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.mu.Lock()
+	_, exists := h.resources[name][id]
+	if !exists {
+		h.resources[name][id] = data
+	}
+	h.mu.Unlock()
+
+	if exists {
+		writeError(w, http.StatusConflict, "resource already exists")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, json.RawMessage(data))
+}
+
+func (h *Hub) update(w http.ResponseWriter, r *http.Request, name, id string) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil || !json.Valid(data) {
+		writeError(w, http.StatusBadRequest, "invalid resource")
+		return
+	}
+
+	h.mu.Lock()
+	_, ok := h.resources[name][id]
+	if ok {
+		h.resources[name][id] = data
+	}
+	h.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "resource not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serveLocation serves the location endpoints of a provider.
+func (h *Hub) serveLocation(w http.ResponseWriter, r *http.Request, id string) {
+	switch r.Method {
+	case http.MethodGet:
+		h.mu.RLock()
+		loc, ok := h.locations[id]
+		h.mu.RUnlock()
+		if !ok {
+			writeError(w, http.StatusNotFound, "location not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, loc)
+	case http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil || !json.Valid(data) {
+			writeError(w, http.StatusBadRequest, "invalid location")
+			return
+		}
+		h.storeLocation(r.Context(), id, data)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// storeLocation keeps the last location of a provider and broadcasts it to the
+// location_updates subscribers.
+func (h *Hub) storeLocation(ctx context.Context, providerID string, data json.RawMessage) {
+	h.mu.Lock()
+	h.locations[providerID] = data
+	h.mu.Unlock()
+
+	h.Publish(ctx, omlox.TopicLocationUpdates, data)
+}
+
+// ids returns the ids of the resources of a collection.
+func (h *Hub) ids(name string) []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	ids := make([]string, 0, len(h.resources[name]))
+	for id := range h.resources[name] {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+// List returns the resources of a collection (e.g. "trackables").
+func (h *Hub) List(collection string) []json.RawMessage {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	res := make([]json.RawMessage, 0, len(h.resources[collection]))
+	for _, r := range h.resources[collection] {
+		res = append(res, r)
+	}
+
+	return res
+}
+
+// Get returns a resource of a collection by id.
+func (h *Hub) Get(collection, id string) (json.RawMessage, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	r, ok := h.resources[collection][id]
+	return r, ok
+}
+
+// Put stores a resource in a collection, replacing any existing one.
+func (h *Hub) Put(collection, id string, resource any) error {
+	if !h.isCollection(collection) {
+		return fmt.Errorf("unknown collection %q", collection)
+	}
+
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	h.resources[collection][id] = data
+	h.mu.Unlock()
+
+	return nil
+}
+
+// Location returns the last location received for a provider.
+func (h *Hub) Location(providerID string) (json.RawMessage, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	loc, ok := h.locations[providerID]
+	return loc, ok
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, omlox.Error{
+		Type:    http.StatusText(code),
+		Code:    code,
+		Message: msg,
+	})
+}
+
+// conn is a websocket connection to the fake hub.
+type conn struct {
+	ws *websocket.Conn
+
+	// writes to a websocket connection must not be concurrent
+	mu sync.Mutex
+
+	// subscription ids by topic
+	subs map[omlox.Topic]int
+}
+
+func (c *conn) write(ctx context.Context, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.ws.Write(ctx, websocket.MessageText, data)
+}
+
+// serveWebsocket upgrades the request and serves the publish/subscribe protocol.
+func (h *Hub) serveWebsocket(w http.ResponseWriter, r *http.Request) {
+	ws, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer ws.CloseNow()
+
+	c := &conn{
+		ws:   ws,
+		subs: make(map[omlox.Topic]int),
+	}
+
+	h.connsMu.Lock()
+	h.conns[c] = struct{}{}
+	h.connsMu.Unlock()
+
+	defer func() {
+		h.connsMu.Lock()
+		delete(h.conns, c)
+		h.connsMu.Unlock()
+	}()
+
+	ctx := r.Context()
+
+	for {
+		_, data, err := ws.Read(ctx)
+		if err != nil {
+			return
+		}
+
+		var msg omlox.WrapperObject
+		if err := json.Unmarshal(data, &msg); err != nil {
+			c.write(ctx, wsError(omlox.ErrCodeUnknown, err.Error()))
+			continue
+		}
+
+		h.handle(ctx, c, &msg)
+	}
+}
+
+// handle a websocket message received from a client.
+func (h *Hub) handle(ctx context.Context, c *conn, msg *omlox.WrapperObject) {
+	switch msg.Event {
+	case omlox.EventSubscribe:
+		if !knownTopic(msg.Topic) {
+			c.write(ctx, wsError(omlox.ErrCodeUnknownTopic, string(msg.Topic)))
+			return
+		}
+
+		h.connsMu.Lock()
+		h.nextSID++
+		sid := h.nextSID
+		c.subs[msg.Topic] = sid
+		h.connsMu.Unlock()
+
+		c.write(ctx, omlox.WrapperObject{
+			Event:          omlox.EventSubscribed,
+			Topic:          msg.Topic,
+			SubscriptionID: sid,
+		})
+	case omlox.EventUnsubscribe:
+		h.connsMu.Lock()
+		sid := c.subs[msg.Topic]
+		delete(c.subs, msg.Topic)
+		h.connsMu.Unlock()
+
+		c.write(ctx, omlox.WrapperObject{
+			Event:          omlox.EventUnsubscribed,
+			Topic:          msg.Topic,
+			SubscriptionID: sid,
+		})
+	case omlox.EventMsg:
+		if msg.Topic != omlox.TopicLocationUpdates {
+			h.Publish(ctx, msg.Topic, msg.Payload...)
+			return
+		}
+
+		for _, p := range msg.Payload {
+			var loc struct {
+				ProviderID string `json:"provider_id"`
+			}
+			if err := json.Unmarshal(p, &loc); err != nil || loc.ProviderID == "" {
+				c.write(ctx, wsError(omlox.ErrCodeInvalid, "location without provider_id"))
+				continue
+			}
+			h.storeLocation(ctx, loc.ProviderID, p)
+		}
+	default:
+		c.write(ctx, wsError(omlox.ErrCodeUnknown, string(msg.Event)))
+	}
+}
+
+// Publish sends a message with the payloads to every subscriber of the topic.
+func (h *Hub) Publish(ctx context.Context, topic omlox.Topic, payload ...json.RawMessage) {
+	h.connsMu.Lock()
+	subscribers := make([]*conn, 0, len(h.conns))
+	for c := range h.conns {
+		if _, ok := c.subs[topic]; ok {
+			subscribers = append(subscribers, c)
+		}
+	}
+	h.connsMu.Unlock()
+
+	// as DeepHub, messages are sent without the subscription id
+	msg := omlox.WrapperObject{
+		Event:   omlox.EventMsg,
+		Topic:   topic,
+		Payload: payload,
+	}
+
+	for _, c := range subscribers {
+		if f := h.fault(wsEndpointPrefix, wsSegments(topic)); f != nil && c.sendFault(ctx, f) {
+			continue
+		}
+		c.write(ctx, msg)
+	}
+}
+
+// Subscribers returns the number of websocket connections subscribed to the topic.
+func (h *Hub) Subscribers(topic omlox.Topic) int {
+	h.connsMu.Lock()
+	defer h.connsMu.Unlock()
+
+	n := 0
+	for c := range h.conns {
+		if _, ok := c.subs[topic]; ok {
+			n++
+		}
+	}
+	return n
+}
+
+// closeConns closes all websocket connections.
+func (h *Hub) closeConns() {
+	h.connsMu.Lock()
+	conns := make([]*conn, 0, len(h.conns))
+	for c := range h.conns {
+		conns = append(conns, c)
+	}
+	h.connsMu.Unlock()
+
+	for _, c := range conns {
+		c.ws.Close(websocket.StatusGoingAway, "server closing")
+	}
+}
+
+func knownTopic(t omlox.Topic) bool {
+	switch t {
+	case omlox.TopicLocationUpdates,
+		omlox.TopicLocationUpdatesGeoJSON,
+		omlox.TopicCollisionEvents,
+		omlox.TopicFenceEvents,
+		omlox.TopicFenceEventsGeoJSON,
+		omlox.TopicTrackableMotions:
+		return true
+	}
+	return false
+}
+
+// wsError is the wire representation of a websocket error event.
+func wsError(code omlox.ErrCode, description string) any {
+	return struct {
+		Event       omlox.Event   `json:"event"`
+		Code        omlox.ErrCode `json:"code"`
+		Description string        `json:"description,omitempty"`
+	}{
+		Event:       omlox.EventError,
+		Code:        code,
+		Description: description,
+	}
+}
@@ -0,0 +1,395 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omloxtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+func TestServerResources(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	c, err := omlox.New(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+
+	tr, err := c.Trackables.Create(ctx, omlox.Trackable{Name: "forklift", Type: omlox.TrackableTypeOmlox})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := c.Trackables.Get(ctx, tr.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Name != "forklift" {
+		t.Fatalf("expected trackable name forklift, got %q", got.Name)
+	}
+
+	if _, err := c.Providers.Create(ctx, omlox.LocationProvider{ID: "p1", Type: omlox.LocationProviderTypeVirtual}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := c.Providers.Create(ctx, omlox.LocationProvider{ID: "p1", Type: omlox.LocationProviderTypeVirtual}); err == nil {
+		t.Fatalf("expected conflict creating a duplicated provider")
+	}
+
+	providers, err := c.Providers.List(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(providers) != 1 {
+		t.Fatalf("expected 1 provider, got %d", len(providers))
+	}
+
+	if err := c.Trackables.Delete(ctx, tr.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var oerr *omlox.Error
+	if _, err := c.Trackables.Get(ctx, tr.ID); !errors.As(err, &oerr) || oerr.Code != 404 {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestServerLocationUpdates(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sub, err := omlox.New(srv.URL, omlox.WithProviderQuality())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := sub.Connect(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer sub.Close()
+
+	s, err := sub.Subscribe(ctx, omlox.TopicLocationUpdates)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := sub.Subscribe(ctx, omlox.Topic("unknown")); err == nil {
+		t.Fatalf("expected error subscribing to an unknown topic")
+	}
+
+	pub, err := omlox.New(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loc := omlox.Location{
+		Position:     *omlox.NewPoint(geometry.Point{X: 1, Y: 2}),
+		Source:       "zone",
+		ProviderType: omlox.LocationProviderTypeUwb,
+		ProviderID:   "p1",
+	}
+
+	// a REST update is broadcasted to the subscribers
+	if err := pub.Providers.UpdateLocation(ctx, loc, loc.ProviderID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	locations := omlox.ReceiveAs[omlox.Location](s)
+
+	select {
+	case got := <-locations:
+		if got.ProviderID != "p1" {
+			t.Fatalf("expected location of provider p1, got %q", got.ProviderID)
+		}
+	case <-ctx.Done():
+		t.Fatalf("location update was not received")
+	}
+
+	// as well as the ones published through the websocket
+	loc.ProviderID = "p2"
+	if err := pub.Connect(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer pub.Close()
+
+	if err := pub.PublishLocations(ctx, loc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case got := <-locations:
+		if got.ProviderID != "p2" {
+			t.Fatalf("expected location of provider p2, got %q", got.ProviderID)
+		}
+	case <-ctx.Done():
+		t.Fatalf("location update was not received")
+	}
+
+	if _, ok := srv.Location("p2"); !ok {
+		t.Fatalf("expected the hub to keep the last location of p2")
+	}
+
+	// the received locations are accounted in the provider quality metrics
+	qs, err := sub.Quality.Providers(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(qs) != 2 || qs[0].ProviderID != "p1" || qs[0].Updates != 1 || qs[1].ProviderID != "p2" || qs[1].Updates != 1 {
+		t.Errorf("unexpected provider quality: %+v", qs)
+	}
+}
+
+func TestServerSubscribeTopics(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := omlox.New(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := c.SubscribeTopics(ctx, omlox.TopicLocationUpdates, omlox.Topic("unknown")); err == nil {
+		t.Fatalf("expected error subscribing to an unknown topic")
+	}
+
+	// the subscriptions made before the failure are ended on the hub
+	for srv.Subscribers(omlox.TopicLocationUpdates) != 0 {
+		select {
+		case <-time.After(10 * time.Millisecond):
+		case <-ctx.Done():
+			t.Fatalf("location_updates subscription was not ended")
+		}
+	}
+
+	events, err := c.SubscribeTopics(ctx, omlox.TopicLocationUpdates, omlox.TopicFenceEvents, omlox.TopicCollisionEvents)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// messages are sent without subscription id, and routed by topic
+	srv.Publish(ctx, omlox.TopicFenceEvents, json.RawMessage(`{"id":"c5ce0e4e-2a0c-4f6c-9b1d-98a7b1e0f1a1","fence_id":"2b2fa2f6-49bb-4e6c-8e2e-4a3b8a63c1b1","event_type":"region_entry","provider_id":"p1"}`))
+	srv.Publish(ctx, omlox.TopicLocationUpdates, json.RawMessage(`{"position":{"type":"Point","coordinates":[1,2]},"source":"zone","provider_type":"uwb","provider_id":"p1"}`))
+	srv.Publish(ctx, omlox.TopicCollisionEvents, json.RawMessage(`{"collision_type":"collision_start","collisions":[{"object_id":"d27047bd-1b6b-4656-bb93-2326a4c900e1"},{"object_id":"9b8a7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d"}]}`))
+
+	got := make(map[omlox.Topic]any)
+	for len(got) < 3 {
+		select {
+		case e := <-events:
+			got[e.Topic] = e.Value
+		case <-ctx.Done():
+			t.Fatalf("events were not received, got %v", got)
+		}
+	}
+
+	if e, ok := got[omlox.TopicFenceEvents].(*omlox.FenceEvent); !ok || e.EventType != omlox.FenceEventTypeRegionEntry {
+		t.Errorf("unexpected fence event: %+v", got[omlox.TopicFenceEvents])
+	}
+	if loc, ok := got[omlox.TopicLocationUpdates].(*omlox.Location); !ok || loc.ProviderID != "p1" {
+		t.Errorf("unexpected location: %+v", got[omlox.TopicLocationUpdates])
+	}
+	if e, ok := got[omlox.TopicCollisionEvents].(*omlox.CollisionEvent); !ok || e.CollisionType != omlox.CollisionTypeStart || len(e.Collisions) != 2 {
+		t.Errorf("unexpected collision event: %+v", got[omlox.TopicCollisionEvents])
+	}
+
+	// the channel is closed with the client
+	if err := c.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for range events {
+	}
+}
+
+func TestServerSubscribeFunc(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	errs := make(chan error, 8)
+	c, err := omlox.New(srv.URL,
+		omlox.WithErrorHandler(func(err error) { errs <- err }),
+		omlox.WithConnStateHandler(func(omlox.ConnStateChange) { panic("buggy state handler") }),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var perr *omlox.PanicError
+	if err := <-errs; !errors.As(err, &perr) || perr.Callback != "connection state handler" {
+		t.Fatalf("expected a panic of the connection state handler, got %v", err)
+	}
+
+	handled := make(chan omlox.FenceEvent, 3)
+	_, err = c.SubscribeFunc(ctx, omlox.TopicFenceEvents, func(e *omlox.TopicEvent) error {
+		event := e.Value.(*omlox.FenceEvent)
+		switch event.ProviderID {
+		case "panic":
+			panic("buggy handler")
+		case "error":
+			return errors.New("handler failed")
+		}
+		handled <- *event
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sub, err := c.Subscribe(ctx, omlox.TopicFenceEvents)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	others := omlox.ReceiveAs[omlox.FenceEvent](sub)
+
+	for _, provider := range []string{"panic", "error", "ok"} {
+		srv.Publish(ctx, omlox.TopicFenceEvents, json.RawMessage(`{"id":"c5ce0e4e-2a0c-4f6c-9b1d-98a7b1e0f1a1","fence_id":"2b2fa2f6-49bb-4e6c-8e2e-4a3b8a63c1b1","event_type":"region_entry","provider_id":"`+provider+`"}`))
+	}
+
+	// the handler keeps handling events after its panic, as do the other subscribers
+	select {
+	case e := <-handled:
+		if e.ProviderID != "ok" {
+			t.Errorf("unexpected event: %+v", e)
+		}
+	case <-ctx.Done():
+		t.Fatalf("the event was not handled")
+	}
+	for i := 0; i < 3; i++ {
+		select {
+		case <-others:
+		case <-ctx.Done():
+			t.Fatalf("the other subscriber received %d events", i)
+		}
+	}
+
+	// events of different providers are handled in any order
+	var panicked, failed bool
+	for i := 0; i < 2; i++ {
+		switch err := <-errs; {
+		case errors.As(err, &perr) && perr.Callback == "event handler" && perr.Topic == omlox.TopicFenceEvents && perr.Value == "buggy handler":
+			panicked = true
+		case err.Error() == "event handler of fence_events: handler failed":
+			failed = true
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if !panicked || !failed {
+		t.Errorf("expected the panic and the error of the event handler to be reported")
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestServerPublishCancel(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sub, err := omlox.New(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := sub.Connect(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer sub.Close()
+
+	s, err := sub.Subscribe(ctx, omlox.TopicFenceEvents)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	msgs := s.ReceiveRaw()
+
+	goroutines := runtime.NumGoroutine()
+
+	pub, err := omlox.New(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := pub.Connect(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// publishes canceled before or while writing do not break the connection
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			pctx, cancel := context.WithTimeout(ctx, time.Duration(i%3)*time.Microsecond)
+			defer cancel()
+
+			err := pub.Publish(pctx, omlox.TopicFenceEvents, json.RawMessage(`{"n":`+strconv.Itoa(i)+`}`))
+			if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if err := pub.Publish(ctx, omlox.TopicFenceEvents, json.RawMessage(`{"n":"last"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for {
+		select {
+		case msg := <-msgs:
+			if string(msg.Payload[0]) != `{"n":"last"}` {
+				continue
+			}
+		case <-ctx.Done():
+			t.Fatalf("the last message was not received")
+		}
+		break
+	}
+
+	// publishes racing with Close fail cleanly, and leave no goroutine behind
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// the connection may be closed before or while writing
+			_ = pub.Publish(ctx, omlox.TopicFenceEvents, json.RawMessage(`{}`))
+		}()
+	}
+	if err := pub.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wg.Wait()
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > goroutines && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > goroutines {
+		t.Errorf("expected %d goroutines after Close, got %d", goroutines, n)
+	}
+}
@@ -10,6 +10,7 @@ buildGoModule (finalAttrs: {
   inherit version;
 
   src = ./.;
+  modRoot = "cmd/omlox-cli";
 
   vendorHash = "sha256-8pNpJ9brmL88CVbh9vlm/Cd4QSstZTIWYn5nFqfe2Xw=";
 
@@ -0,0 +1,64 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// invalidBody is the body of the permission probes, rejected by the Hub once the
+// permissions are checked.
+const invalidBody = "{"
+
+// Can reports whether the credentials of the client are permitted to send requests
+// with the method to the path, such as "POST /trackables", without side effects.
+//
+// Requests with a body are sent with an invalid one, rejected by the Hub after
+// checking the permissions, so only GET requests should target existing resources.
+// Requests the Hub rejects for missing or invalid credentials return an error
+// matching ErrUnauthorized.
+func (c *Client) Can(ctx context.Context, method, path string) (bool, error) {
+	if c.configuration.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.configuration.RequestTimeout)
+		defer cancel()
+	}
+
+	var headers http.Header
+	body := strings.NewReader("")
+	if method != http.MethodGet && method != http.MethodHead && method != http.MethodDelete {
+		headers = http.Header{"Content-Type": []string{"application/json"}}
+		body = strings.NewReader(invalidBody)
+	}
+
+	req, err := c.newRequest(ctx, method, path, body, nil, headers)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	err = isResponseError(resp)
+
+	var oerr *Error
+	switch {
+	case errors.Is(err, ErrForbidden):
+		return false, nil
+	case errors.Is(err, ErrUnauthorized):
+		return false, err
+	case errors.As(err, &oerr) && oerr.Code >= http.StatusInternalServerError:
+		return false, err
+	}
+
+	// other outcomes, such as an invalid body or a missing resource, follow the
+	// permission checks
+	return true, nil
+}
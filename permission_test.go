@@ -0,0 +1,53 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCan(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Header.Get("Authorization") != "Bearer secret":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte("missing token"))
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"type":"forbidden","code":403,"message":"operator role required"}`))
+		default:
+			// probes never carry valid bodies
+			if b, _ := io.ReadAll(r.Body); r.Method == http.MethodPost && string(b) != invalidBody {
+				t.Errorf("unexpected probe body %q", b)
+			}
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL, WithToken("secret"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ok, err := c.Can(context.Background(), http.MethodPost, "/trackables"); !ok || err != nil {
+		t.Errorf("expected create permitted, got %v (%v)", ok, err)
+	}
+	if ok, err := c.Can(context.Background(), http.MethodDelete, "/trackables/x"); ok || err != nil {
+		t.Errorf("expected delete forbidden, got %v (%v)", ok, err)
+	}
+
+	c, err = New(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := c.Can(context.Background(), http.MethodGet, "/trackables"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected unauthorized, got %v", err)
+	}
+}
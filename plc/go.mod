@@ -0,0 +1,30 @@
+module github.com/wavecomtech/omlox-client-go/plc
+
+go 1.21
+
+require (
+	github.com/google/uuid v1.3.0
+	github.com/grid-x/modbus v1.5.1
+	github.com/wavecomtech/omlox-client-go v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/grid-x/serial v0.0.0-20211107191517-583c7356b3aa // indirect
+	github.com/hashicorp/go-cleanhttp v0.5.2 // indirect
+	github.com/josharian/intern v1.0.0 // indirect
+	github.com/mailru/easyjson v0.7.7 // indirect
+	github.com/tidwall/cities v0.1.0 // indirect
+	github.com/tidwall/geoindex v1.4.4 // indirect
+	github.com/tidwall/geojson v1.4.3 // indirect
+	github.com/tidwall/gjson v1.12.1 // indirect
+	github.com/tidwall/lotsa v1.0.2 // indirect
+	github.com/tidwall/match v1.1.1 // indirect
+	github.com/tidwall/pretty v1.2.0 // indirect
+	github.com/tidwall/rtree v1.3.1 // indirect
+	github.com/tidwall/sjson v1.2.4 // indirect
+	golang.org/x/sync v0.2.0 // indirect
+	golang.org/x/time v0.4.0 // indirect
+	nhooyr.io/websocket v1.8.10 // indirect
+)
+
+replace github.com/wavecomtech/omlox-client-go => ../
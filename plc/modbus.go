@@ -0,0 +1,131 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package plc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/grid-x/modbus"
+)
+
+// DefaultModbusPort is the port of Modbus TCP addresses without port.
+const DefaultModbusPort = "502"
+
+// DefaultModbusTimeout is the timeout of the connection and requests to Modbus devices.
+const DefaultModbusTimeout = 5 * time.Second
+
+// ModbusException is an exception code returned by a Modbus device.
+type ModbusException byte
+
+// Exception codes of Modbus devices.
+const (
+	ModbusIllegalFunction    ModbusException = 0x01
+	ModbusIllegalAddress     ModbusException = 0x02
+	ModbusIllegalValue       ModbusException = 0x03
+	ModbusDeviceFailure      ModbusException = 0x04
+	ModbusDeviceBusy         ModbusException = 0x06
+	ModbusGatewayUnavailable ModbusException = 0x0A
+	ModbusGatewayNoResponse  ModbusException = 0x0B
+)
+
+var modbusExceptions = map[ModbusException]string{
+	ModbusIllegalFunction:    "illegal function",
+	ModbusIllegalAddress:     "illegal data address",
+	ModbusIllegalValue:       "illegal data value",
+	ModbusDeviceFailure:      "server device failure",
+	ModbusDeviceBusy:         "server device busy",
+	ModbusGatewayUnavailable: "gateway path unavailable",
+	ModbusGatewayNoResponse:  "gateway target device failed to respond",
+}
+
+// Error implements error.
+func (e ModbusException) Error() string {
+	if name, ok := modbusExceptions[e]; ok {
+		return "modbus: " + name
+	}
+	return fmt.Sprintf("modbus: exception 0x%02X", byte(e))
+}
+
+// Modbus is a Modbus TCP client writing the coils and holding registers of a device,
+// built on the grid-x/modbus client. It connects on the first write, and reconnects
+// when the device closes the connection. It is safe for concurrent use, requests
+// being sent one at a time.
+type Modbus struct {
+	handler *modbus.TCPClientHandler
+	client  modbus.Client
+}
+
+// NewModbus returns a client of the device of a unit id at an address, such as
+// "plc.local:502".
+func NewModbus(addr string, unit byte) *Modbus {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, DefaultModbusPort)
+	}
+
+	h := modbus.NewTCPClientHandler(addr)
+	h.SlaveID = unit
+	h.Timeout = DefaultModbusTimeout
+	return &Modbus{handler: h, client: modbus.NewClient(h)}
+}
+
+// WriteCoil sets or clears a coil.
+func (m *Modbus) WriteCoil(ctx context.Context, address uint16, on bool) error {
+	var value uint16
+	if on {
+		value = 0xFF00
+	}
+	return m.write(func() error {
+		_, err := m.client.WriteSingleCoil(ctx, address, value)
+		return err
+	})
+}
+
+// WriteRegister writes a holding register.
+func (m *Modbus) WriteRegister(ctx context.Context, address uint16, value uint16) error {
+	return m.write(func() error {
+		_, err := m.client.WriteSingleRegister(ctx, address, value)
+		return err
+	})
+}
+
+// Coil returns the target of a coil, set by non-zero values.
+func (m *Modbus) Coil(address uint16) Target {
+	return TargetFunc(func(ctx context.Context, value uint16) error {
+		return m.WriteCoil(ctx, address, value != 0)
+	})
+}
+
+// Register returns the target of a holding register.
+func (m *Modbus) Register(address uint16) Target {
+	return TargetFunc(func(ctx context.Context, value uint16) error {
+		return m.WriteRegister(ctx, address, value)
+	})
+}
+
+// Close closes the connection to the device.
+func (m *Modbus) Close() error {
+	return m.handler.Close()
+}
+
+// write sends a write request, sending it again on a new connection when the
+// device closed the connection. Writing a single coil or register is idempotent.
+// Exceptions of the device are returned as ModbusException errors.
+func (m *Modbus) write(request func() error) error {
+	err := request()
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		err = request()
+	}
+
+	var e *modbus.Error
+	if errors.As(err, &e) {
+		return ModbusException(e.ExceptionCode)
+	}
+	return err
+}
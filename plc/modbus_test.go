@@ -0,0 +1,118 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package plc
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/grid-x/modbus"
+)
+
+// device is a fake Modbus TCP device with coils and holding registers at addresses
+// below 100.
+type device struct {
+	mu        sync.Mutex
+	coils     map[uint16]bool
+	registers map[uint16]uint16
+}
+
+func (d *device) serve(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		var header [7]byte
+		if _, err := io.ReadFull(conn, header[:]); err != nil {
+			return
+		}
+		pdu := make([]byte, binary.BigEndian.Uint16(header[4:])-1)
+		if _, err := io.ReadFull(conn, pdu); err != nil {
+			return
+		}
+
+		address, value := binary.BigEndian.Uint16(pdu[1:]), binary.BigEndian.Uint16(pdu[3:])
+		resp := pdu
+		d.mu.Lock()
+		switch {
+		case address >= 100:
+			resp = []byte{pdu[0] | 0x80, byte(ModbusIllegalAddress)}
+		case pdu[0] == modbus.FuncCodeWriteSingleCoil:
+			d.coils[address] = value == 0xFF00
+		case pdu[0] == modbus.FuncCodeWriteSingleRegister:
+			d.registers[address] = value
+		default:
+			resp = []byte{pdu[0] | 0x80, byte(ModbusIllegalFunction)}
+		}
+		d.mu.Unlock()
+
+		binary.BigEndian.PutUint16(header[4:], uint16(1+len(resp)))
+		conn.Write(append(header[:], resp...))
+	}
+}
+
+func TestModbus(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer l.Close()
+
+	d := &device{coils: make(map[uint16]bool), registers: make(map[uint16]uint16)}
+	var conns []net.Conn
+	var mu sync.Mutex
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			conns = append(conns, conn)
+			mu.Unlock()
+			go d.serve(conn)
+		}
+	}()
+
+	ctx := context.Background()
+	m := NewModbus(l.Addr().String(), 1)
+	defer m.Close()
+
+	if err := m.Coil(3).Write(ctx, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := m.Register(7).Write(ctx, 42); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	d.mu.Lock()
+	if !d.coils[3] || d.registers[7] != 42 {
+		t.Errorf("unexpected device state %v %v", d.coils, d.registers)
+	}
+	d.mu.Unlock()
+
+	if err := m.WriteCoil(ctx, 100, true); !errors.Is(err, ModbusIllegalAddress) {
+		t.Errorf("expected error %v, got %v", ModbusIllegalAddress, err)
+	}
+
+	// writes reconnect after the device closes the connection
+	mu.Lock()
+	for _, conn := range conns {
+		conn.Close()
+	}
+	mu.Unlock()
+
+	if err := m.WriteCoil(ctx, 3, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d.mu.Lock()
+	if d.coils[3] {
+		t.Error("expected the coil to be cleared")
+	}
+	d.mu.Unlock()
+}
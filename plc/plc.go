@@ -0,0 +1,217 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package plc writes simple values to PLCs when alert rules fire, such as a coil
+// opening a gate when an AGV arrives in front of it.
+//
+// Values are written to targets: the coils and holding registers of Modbus TCP
+// devices, or the REST endpoints of PLCs. Bindings map the rules of the alert
+// package to the targets and the values to write: 1 for presence, or the number of
+// trackables inside the fence of the alert for occupancy.
+//
+// Modbus requests are sent with the grid-x/modbus client. The package is a module
+// of its own, so the client of the hub does not depend on it.
+package plc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go/alert"
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+// DefaultWriteTimeout is the default timeout of the writes releasing pulses.
+const DefaultWriteTimeout = 5 * time.Second
+
+// Target is a value of a PLC, such as a Modbus coil or holding register.
+type Target interface {
+	// Write writes a value to the target. Boolean targets are set by non-zero values.
+	Write(ctx context.Context, value uint16) error
+}
+
+// TargetFunc is an adapter to use ordinary functions as targets.
+type TargetFunc func(ctx context.Context, value uint16) error
+
+// Write implements Target.
+func (f TargetFunc) Write(ctx context.Context, value uint16) error {
+	return f(ctx, value)
+}
+
+// Value is the kind of value written when a rule fires.
+type Value int
+
+// Defines values for Value.
+const (
+	// ValuePresent writes 1, such as a trackable present or a fence occupied.
+	ValuePresent Value = iota
+
+	// ValueOccupancy writes the number of trackables inside the fence of the alert.
+	ValueOccupancy
+)
+
+// Binding binds the alerts of a rule to a target.
+type Binding struct {
+	// Rule is the name of the rule.
+	Rule string
+
+	// Target is the value of the PLC written when the rule fires.
+	Target Target
+
+	// Value is the kind of value written.
+	Value Value
+
+	// Pulse, if positive, writes 0 to the target after the duration, such as to
+	// release the command opening a gate. Alerts during the pulse extend it.
+	Pulse time.Duration
+}
+
+// Notifier writes to the targets of the rules of alerts. It is safe for concurrent use.
+type Notifier struct {
+	bindings     []Binding
+	occupancy    func(fenceID uuid.UUID) int
+	onError      func(alert.Alert, error)
+	writeTimeout time.Duration
+	clock        clock.Clock
+
+	mu     sync.Mutex
+	pulses map[int]clock.Timer // by binding index
+}
+
+// Option is a configuration option of a notifier.
+type Option func(*Notifier)
+
+// WithOccupancy sets the function returning the number of trackables inside a fence,
+// such as from the memberships of a tracker. It is required by occupancy bindings.
+func WithOccupancy(occupancy func(fenceID uuid.UUID) int) Option {
+	return func(n *Notifier) {
+		n.occupancy = occupancy
+	}
+}
+
+// OnError registers a hook called with the errors of the writes of Run and of the
+// writes releasing pulses.
+func OnError(hook func(alert.Alert, error)) Option {
+	return func(n *Notifier) {
+		n.onError = hook
+	}
+}
+
+// WithWriteTimeout sets the timeout of the writes releasing pulses.
+//
+// Default: DefaultWriteTimeout
+func WithWriteTimeout(d time.Duration) Option {
+	return func(n *Notifier) {
+		n.writeTimeout = d
+	}
+}
+
+// WithClock sets the source of time of the pulses.
+func WithClock(clk clock.Clock) Option {
+	return func(n *Notifier) {
+		n.clock = clk
+	}
+}
+
+// NewNotifier returns a notifier writing the values of the bindings.
+func NewNotifier(bindings []Binding, opts ...Option) (*Notifier, error) {
+	n := &Notifier{
+		bindings:     bindings,
+		writeTimeout: DefaultWriteTimeout,
+		pulses:       make(map[int]clock.Timer),
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	n.clock = clock.Or(n.clock)
+
+	for _, b := range bindings {
+		if b.Rule == "" {
+			return nil, errors.New("binding must have a rule")
+		}
+		if b.Target == nil {
+			return nil, fmt.Errorf("binding of rule '%s' must have a target", b.Rule)
+		}
+		if b.Value == ValueOccupancy && n.occupancy == nil {
+			return nil, fmt.Errorf("occupancy binding of rule '%s' requires an occupancy source", b.Rule)
+		}
+	}
+
+	return n, nil
+}
+
+// Notify writes the values of the bindings of the rule of an alert.
+func (n *Notifier) Notify(ctx context.Context, a alert.Alert) error {
+	var errs []error
+	for i, b := range n.bindings {
+		if b.Rule != a.Rule {
+			continue
+		}
+
+		var value uint16 = 1
+		if b.Value == ValueOccupancy {
+			value = uint16(min(max(n.occupancy(a.FenceID), 0), math.MaxUint16))
+		}
+
+		if err := b.Target.Write(ctx, value); err != nil {
+			errs = append(errs, fmt.Errorf("rule '%s': %w", b.Rule, err))
+			continue
+		}
+		if b.Pulse > 0 {
+			n.pulse(i, a)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pulse schedules the release of the target of a binding, or extends it.
+func (n *Notifier) pulse(i int, a alert.Alert) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if t, ok := n.pulses[i]; ok && t.Stop() {
+		t.Reset(n.bindings[i].Pulse)
+		return
+	}
+
+	var t clock.Timer
+	t = n.clock.AfterFunc(n.bindings[i].Pulse, func() {
+		n.mu.Lock()
+		if n.pulses[i] == t {
+			delete(n.pulses, i)
+		}
+		n.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), n.writeTimeout)
+		defer cancel()
+
+		if err := n.bindings[i].Target.Write(ctx, 0); err != nil && n.onError != nil {
+			n.onError(a, fmt.Errorf("rule '%s': release: %w", a.Rule, err))
+		}
+	})
+	n.pulses[i] = t
+}
+
+// Run notifies the alerts of a channel, such as the one returned by alert.Dwell.Watch,
+// until the channel is closed or the context is done. Errors are passed to the hook
+// registered with OnError.
+func (n *Notifier) Run(ctx context.Context, alerts <-chan alert.Alert) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case a, ok := <-alerts:
+			if !ok {
+				return nil
+			}
+			if err := n.Notify(ctx, a); err != nil && n.onError != nil {
+				n.onError(a, err)
+			}
+		}
+	}
+}
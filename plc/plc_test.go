@@ -0,0 +1,172 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package plc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go/alert"
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+var (
+	agv  = uuid.MustParse("6c5d2f0e-8f6a-4b7e-9a51-0d6f1a2b3c41")
+	gate = uuid.MustParse("3a1f0c9e-7d2b-4e6f-8a5c-1b2d3e4f5a66")
+)
+
+// recorder returns a target sending its writes on a channel.
+func recorder() (Target, <-chan uint16) {
+	writes := make(chan uint16, 16)
+	return TargetFunc(func(ctx context.Context, value uint16) error {
+		writes <- value
+		return nil
+	}), writes
+}
+
+func expectWrite(t *testing.T, writes <-chan uint16, want uint16) {
+	t.Helper()
+
+	select {
+	case got := <-writes:
+		if got != want {
+			t.Errorf("expected write %d, got %d", want, got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected write %d", want)
+	}
+}
+
+func TestNotifier(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC))
+	opener, opens := recorder()
+	counter, counts := recorder()
+
+	n, err := NewNotifier([]Binding{
+		{Rule: "agv-at-gate", Target: opener, Pulse: 2 * time.Second},
+		{Rule: "agv-at-gate", Target: counter, Value: ValueOccupancy},
+	}, WithClock(clk), WithOccupancy(func(fenceID uuid.UUID) int {
+		if fenceID == gate {
+			return 3
+		}
+		return 0
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+	a := alert.Alert{Rule: "agv-at-gate", TrackableID: agv, FenceID: gate}
+
+	if err := n.Notify(ctx, a); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectWrite(t, opens, 1)
+	expectWrite(t, counts, 3)
+
+	// alerts during the pulse extend it
+	clk.Advance(time.Second)
+	if err := n.Notify(ctx, a); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectWrite(t, opens, 1)
+	expectWrite(t, counts, 3)
+
+	clk.Advance(1500 * time.Millisecond)
+	select {
+	case v := <-opens:
+		t.Fatalf("unexpected write %d during the pulse", v)
+	default:
+	}
+
+	clk.Advance(time.Second)
+	expectWrite(t, opens, 0)
+
+	// other rules are ignored
+	if err := n.Notify(ctx, alert.Alert{Rule: "speeding", TrackableID: agv}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(opens) != 0 || len(counts) != 0 {
+		t.Error("unexpected writes of another rule")
+	}
+}
+
+func TestNewNotifierErrors(t *testing.T) {
+	target, _ := recorder()
+
+	tests := [][]Binding{
+		{{Target: target}},
+		{{Rule: "agv-at-gate"}},
+		{{Rule: "agv-at-gate", Target: target, Value: ValueOccupancy}},
+	}
+	for _, bindings := range tests {
+		if _, err := NewNotifier(bindings); err == nil {
+			t.Errorf("expected an error for %+v", bindings)
+		}
+	}
+}
+
+func TestNotifierRun(t *testing.T) {
+	failing := TargetFunc(func(ctx context.Context, value uint16) error { return errors.New("unreachable") })
+
+	var failed []string
+	n, err := NewNotifier([]Binding{{Rule: "agv-at-gate", Target: failing}}, OnError(func(a alert.Alert, err error) {
+		failed = append(failed, a.Rule+": "+err.Error())
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	alerts := make(chan alert.Alert, 1)
+	alerts <- alert.Alert{Rule: "agv-at-gate"}
+	close(alerts)
+
+	if err := n.Run(context.Background(), alerts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "agv-at-gate: rule 'agv-at-gate': unreachable"; len(failed) != 1 || failed[0] != want {
+		t.Errorf("expected error %q, got %q", want, failed)
+	}
+}
+
+func TestREST(t *testing.T) {
+	var method, path, body, auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		method, path, body, auth = r.Method, r.URL.Path, string(b), r.Header.Get("Authorization")
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+
+	target := &REST{URL: srv.URL + "/gates/1", Header: http.Header{"Authorization": {"Bearer secret"}}}
+	if err := target.Write(ctx, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if method != http.MethodPut || path != "/gates/1" || body != `{"value":1}` || auth != "Bearer secret" {
+		t.Errorf("unexpected request %s %s %s %q", method, path, body, auth)
+	}
+
+	target = &REST{URL: srv.URL + "/io/{value}", Method: http.MethodPost, Body: "state={value}"}
+	if err := target.Write(ctx, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if method != http.MethodPost || path != "/io/0" || body != "state=0" {
+		t.Errorf("unexpected request %s %s %s", method, path, body)
+	}
+
+	target = &REST{URL: srv.URL + "/broken"}
+	if err := target.Write(ctx, 1); err == nil {
+		t.Error("expected an error")
+	}
+}
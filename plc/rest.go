@@ -0,0 +1,77 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package plc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// REST is a target writing values with HTTP requests to the REST endpoint of a PLC.
+type REST struct {
+	// URL is the url of the endpoint. Occurrences of {value} are replaced by the value.
+	URL string
+
+	// Method is the method of the requests. Defaults to PUT.
+	Method string
+
+	// Body is the body of the requests, with occurrences of {value} replaced by the
+	// value. Defaults to {"value":{value}}.
+	Body string
+
+	// Header holds the headers of the requests, such as an authorization.
+	// The content type defaults to application/json.
+	Header http.Header
+
+	// Client is the client sending the requests. Defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+var _ Target = (*REST)(nil)
+
+// Write implements Target. Responses without a 2xx status are errors.
+func (r *REST) Write(ctx context.Context, value uint16) error {
+	v := strconv.Itoa(int(value))
+
+	method := r.Method
+	if method == "" {
+		method = http.MethodPut
+	}
+	body := r.Body
+	if body == "" {
+		body = `{"value":{value}}`
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.ReplaceAll(r.URL, "{value}", v), strings.NewReader(strings.ReplaceAll(body, "{value}", v)))
+	if err != nil {
+		return err
+	}
+	for k, values := range r.Header {
+		req.Header[k] = values
+	}
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("plc: unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -6,6 +6,8 @@ package omlox
 import (
 	"errors"
 
+	"github.com/mailru/easyjson/jlexer"
+	"github.com/mailru/easyjson/jwriter"
 	"github.com/tidwall/geojson"
 	"github.com/tidwall/geojson/geometry"
 )
@@ -26,6 +28,77 @@ func (p Point) MarshalJSON() ([]byte, error) {
 	return p.Point.MarshalJSON()
 }
 
+// MarshalEasyJSON appends the point to the writer buffer without intermediate allocations.
+func (p Point) MarshalEasyJSON(w *jwriter.Writer) {
+	w.Buffer.EnsureSpace(96)
+	w.Buffer.Buf = p.Point.AppendJSON(w.Buffer.Buf)
+}
+
+// UnmarshalEasyJSON decodes plain points directly, and points with foreign members
+// or bounding boxes through the GeoJSON parser.
+func (p *Point) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	data := l.Raw()
+	if !l.Ok() {
+		return
+	}
+
+	if ok := p.unmarshalPlain(data); !ok {
+		if err := p.UnmarshalJSON(data); err != nil {
+			l.AddError(err)
+		}
+	}
+}
+
+// unmarshalPlain decodes a point with only a type and coordinates, reporting
+// whether the point was one.
+func (p *Point) unmarshalPlain(data []byte) bool {
+	var (
+		in     = jlexer.Lexer{Data: data}
+		coords [3]float64
+		n      int
+		typed  bool
+	)
+
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+
+		switch key {
+		case "type":
+			typed = in.UnsafeString() == "Point"
+		case "coordinates":
+			in.Delim('[')
+			for !in.IsDelim(']') {
+				if n == len(coords) {
+					return false
+				}
+				coords[n] = in.Float64()
+				n++
+				in.WantComma()
+			}
+			in.Delim(']')
+		default:
+			return false
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+
+	if !in.Ok() || !typed || n < 2 {
+		return false
+	}
+
+	point := geometry.Point{X: coords[0], Y: coords[1]}
+	if n == 3 {
+		*p = *NewPointZ(point, coords[2])
+		return true
+	}
+
+	*p = *NewPoint(point)
+	return true
+}
+
 func (p *Point) UnmarshalJSON(data []byte) error {
 	o, err := geojson.Parse(string(data), geojson.DefaultParseOptions)
 	if err != nil {
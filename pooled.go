@@ -0,0 +1,81 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"sync"
+)
+
+// Pooled is an event recycled once released by its consumer.
+type Pooled[T any] struct {
+	// Value is the decoded event. It must not be used after Release.
+	Value T
+
+	// SchemaVersion is the omlox™ Hub API version of the Hub, empty if unknown.
+	SchemaVersion string
+
+	pool     *sync.Pool
+	released bool
+}
+
+// Release returns the event to its pool, to be reused by a later event.
+// Neither the event nor any value referenced by it may be used afterwards.
+// Releasing an event more than once has no effect.
+func (p *Pooled[T]) Release() {
+	if p.released {
+		return
+	}
+	p.released = true
+
+	// types implementing Reset may keep allocated memory, such as the capacity of slices
+	if r, ok := any(&p.Value).(interface{ Reset() }); ok {
+		r.Reset()
+	} else {
+		var zero T
+		p.Value = zero
+	}
+	p.SchemaVersion = ""
+
+	p.pool.Put(p)
+}
+
+// ReceivePooled is like ReceiveVersioned, but recycles the events released by the consumer
+// to reduce the garbage collection pressure of high-throughput pipelines. Events which
+// are not released are garbage collected as usual.
+func ReceivePooled[T any](sub *Subcription) <-chan *Pooled[T] {
+	out := make(chan *Pooled[T], receiveChanSize)
+
+	pool := &sync.Pool{}
+	pool.New = func() any {
+		return &Pooled[T]{pool: pool}
+	}
+
+	go func() {
+		defer close(out)
+
+		for msg := range sub.mch {
+			for _, payload := range msg.Payload {
+				p := pool.Get().(*Pooled[T])
+				p.released = false
+
+				version, err := decodePayloadInto(sub, payload, &p.Value)
+				if err != nil {
+					p.Release()
+					continue
+				}
+				p.SchemaVersion = version
+
+				out <- p
+			}
+		}
+	}()
+
+	return out
+}
+
+// Reset clears the location, keeping the capacity of its trackables for reuse.
+func (l *Location) Reset() {
+	trackables := l.Trackables[:0]
+	*l = Location{Trackables: trackables}
+}
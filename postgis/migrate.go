@@ -0,0 +1,121 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package postgis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// migrations are the schema migrations, in order. Migrations are never changed once
+// released: changes are new migrations. {schema} is replaced by the quoted schema.
+var migrations = [][]string{
+	// 1: trackables, location history, last known positions and fence events
+	{
+		`CREATE EXTENSION IF NOT EXISTS postgis`,
+		`CREATE TABLE {schema}.trackables (
+			id uuid PRIMARY KEY,
+			type text NOT NULL,
+			name text NOT NULL DEFAULT '',
+			location_providers jsonb NOT NULL DEFAULT '[]',
+			properties jsonb,
+			updated_at timestamptz NOT NULL DEFAULT now()
+		)`,
+		`CREATE TABLE {schema}.locations (
+			id bigserial PRIMARY KEY,
+			trackable_id uuid,
+			provider_id text NOT NULL,
+			provider_type text NOT NULL,
+			crs text NOT NULL,
+			position geometry NOT NULL,
+			floor double precision NOT NULL DEFAULT 0,
+			accuracy double precision,
+			generated_at timestamptz NOT NULL
+		)`,
+		`CREATE UNIQUE INDEX locations_key ON {schema}.locations (provider_id, COALESCE(trackable_id, '00000000-0000-0000-0000-000000000000'), generated_at)`,
+		`CREATE INDEX locations_trackable ON {schema}.locations (trackable_id, generated_at)`,
+		`CREATE INDEX locations_position ON {schema}.locations USING gist (position)`,
+		`CREATE TABLE {schema}.positions (
+			key text PRIMARY KEY,
+			trackable_id uuid,
+			provider_id text NOT NULL,
+			provider_type text NOT NULL,
+			crs text NOT NULL,
+			position geometry NOT NULL,
+			floor double precision NOT NULL DEFAULT 0,
+			accuracy double precision,
+			generated_at timestamptz NOT NULL
+		)`,
+		`CREATE INDEX positions_position ON {schema}.positions USING gist (position)`,
+		`CREATE TABLE {schema}.fence_events (
+			id uuid PRIMARY KEY,
+			fence_id uuid NOT NULL,
+			trackable_id uuid,
+			provider_id text NOT NULL DEFAULT '',
+			event_type text NOT NULL,
+			entry_time timestamptz,
+			exit_time timestamptz,
+			foreign_id text NOT NULL DEFAULT '',
+			position geometry
+		)`,
+		`CREATE INDEX fence_events_fence ON {schema}.fence_events (fence_id, entry_time)`,
+		`CREATE INDEX fence_events_trackable ON {schema}.fence_events (trackable_id, entry_time)`,
+	},
+}
+
+// quoteIdentifier quotes an identifier, such as the name of a schema.
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// Migrate creates or upgrades the schema of the tables, applying the migrations not
+// applied yet in a transaction. Concurrent migrations of the same database wait for
+// each other.
+func (s *Store) Migrate(ctx context.Context) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range []string{
+		`SELECT pg_advisory_xact_lock(hashtext('omlox-client-go:postgis'))`,
+		`CREATE SCHEMA IF NOT EXISTS {schema}`,
+		`CREATE TABLE IF NOT EXISTS {schema}.schema_migrations (version integer PRIMARY KEY, applied_at timestamptz NOT NULL DEFAULT now())`,
+	} {
+		if _, err := tx.ExecContext(ctx, s.sql(stmt)); err != nil {
+			return err
+		}
+	}
+
+	var version int
+	if err := tx.QueryRowContext(ctx, s.sql(`SELECT COALESCE(MAX(version), 0) FROM {schema}.schema_migrations`)).Scan(&version); err != nil {
+		return err
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("schema version %d is newer than the supported version %d", version, len(migrations))
+	}
+
+	for i := version; i < len(migrations); i++ {
+		for _, stmt := range migrations[i] {
+			if _, err := tx.ExecContext(ctx, s.sql(stmt)); err != nil {
+				return fmt.Errorf("migration %d: %w", i+1, err)
+			}
+		}
+		if _, err := tx.ExecContext(ctx, s.sql(`INSERT INTO {schema}.schema_migrations (version) VALUES ($1)`), i+1); err != nil {
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Version returns the version of the schema of the tables. It fails if the tables were
+// never migrated.
+func (s *Store) Version(ctx context.Context) (int, error) {
+	var version int
+	err := s.db.QueryRowContext(ctx, s.sql(`SELECT COALESCE(MAX(version), 0) FROM {schema}.schema_migrations`)).Scan(&version)
+	return version, err
+}
@@ -0,0 +1,315 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package postgis persists trackables, locations and fence events into PostgreSQL
+// tables with PostGIS geometries, for SQL analytics joining location data with
+// business data.
+//
+// The store uses a database/sql handle, opened with a PostgreSQL driver registered by
+// the application, such as github.com/jackc/pgx/v5/stdlib:
+//
+//	db, err := sql.Open("pgx", "postgres://omlox@db.local/facility")
+//
+// Writes are upserts, so events delivered again, such as by the forward package, are
+// stored once.
+package postgis
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/cdc"
+	"github.com/wavecomtech/omlox-client-go/forward"
+)
+
+// DefaultSchema is the default schema of the tables.
+const DefaultSchema = "omlox"
+
+// Option is a configuration option of a store.
+type Option func(*Store)
+
+// WithSchema sets the schema of the tables.
+//
+// Default: DefaultSchema
+func WithSchema(schema string) Option {
+	return func(s *Store) {
+		s.schema = schema
+	}
+}
+
+// Store persists omlox™ data into the PostGIS tables of a schema:
+//
+//   - trackables: the trackables, by id.
+//   - locations: the history of the locations, by trackable, or by provider for
+//     locations without trackables.
+//   - positions: the last known position of each trackable or provider.
+//   - fence_events: the fence events, by id, updated with their exit time.
+//
+// Positions are geometries in the SRID of the crs of their location, such as 4326 for
+// EPSG:4326, or 0 for local coordinates. The tables are created by Migrate.
+//
+// Store is a sink of the forward package, persisting the location updates and fence
+// events forwarded and ignoring other events. It is also a sink of the cdc package,
+// keeping the trackables in sync with the hub.
+type Store struct {
+	db     *sql.DB
+	schema string
+}
+
+var (
+	_ forward.Sink = (*Store)(nil)
+	_ cdc.Sink     = (*Store)(nil)
+)
+
+// New returns a store of a database.
+func New(db *sql.DB, opts ...Option) *Store {
+	s := &Store{db: db, schema: DefaultSchema}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// sql returns a statement with the quoted schema.
+func (s *Store) sql(stmt string) string {
+	return strings.ReplaceAll(stmt, "{schema}", quoteIdentifier(s.schema))
+}
+
+// srid returns the SRID of a crs: the code of EPSG identifiers, or 0 for local
+// coordinates.
+func srid(crs string) (int, error) {
+	if crs == "" || crs == omlox.CrsLocal {
+		return 0, nil
+	}
+	code, ok := strings.CutPrefix(crs, "EPSG:")
+	if !ok {
+		return 0, fmt.Errorf("unsupported crs '%s'", crs)
+	}
+	return strconv.Atoi(code)
+}
+
+// point returns the arguments of the point of a location.
+func point(loc *omlox.Location) ([]any, error) {
+	srid, err := srid(loc.Crs)
+	if err != nil {
+		return nil, err
+	}
+	base := loc.Position.Base()
+	return []any{base.X, base.Y, loc.Position.Z(), srid}, nil
+}
+
+// nullUUID returns the argument of an optional uuid.
+func nullUUID(id *uuid.UUID) any {
+	if id == nil {
+		return nil
+	}
+	return id.String()
+}
+
+// Deliver implements forward.Sink.
+func (s *Store) Deliver(ctx context.Context, e forward.Event) error {
+	switch e.Topic {
+	case omlox.TopicLocationUpdates:
+		var loc omlox.Location
+		if err := json.Unmarshal(e.Payload, &loc); err != nil {
+			return fmt.Errorf("invalid location update: %w", err)
+		}
+		return s.InsertLocations(ctx, loc)
+	case omlox.TopicFenceEvents:
+		var event omlox.FenceEvent
+		if err := json.Unmarshal(e.Payload, &event); err != nil {
+			return fmt.Errorf("invalid fence event: %w", err)
+		}
+		return s.UpsertFenceEvents(ctx, event)
+	}
+	return nil
+}
+
+// Apply implements cdc.Sink, upserting and deleting trackables and ignoring the changes
+// of other resources.
+func (s *Store) Apply(ctx context.Context, c cdc.Change) error {
+	if c.Resource != cdc.ResourceTrackables {
+		return nil
+	}
+
+	if c.Op == cdc.OpDelete {
+		return s.DeleteTrackables(ctx, c.ID)
+	}
+	var t omlox.Trackable
+	if err := c.Decode(&t); err != nil {
+		return fmt.Errorf("invalid trackable: %w", err)
+	}
+	return s.UpsertTrackables(ctx, t)
+}
+
+// DeleteTrackables deletes trackables by id. Their locations and fence events are kept.
+func (s *Store) DeleteTrackables(ctx context.Context, ids ...string) error {
+	stmt := s.sql(`DELETE FROM {schema}.trackables WHERE id = $1`)
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		for _, id := range ids {
+			if _, err := tx.ExecContext(ctx, stmt, id); err != nil {
+				return fmt.Errorf("trackable %s: %w", id, err)
+			}
+		}
+		return nil
+	})
+}
+
+// UpsertTrackables inserts or updates trackables.
+func (s *Store) UpsertTrackables(ctx context.Context, trackables ...omlox.Trackable) error {
+	stmt := s.sql(`INSERT INTO {schema}.trackables (id, type, name, location_providers, properties, updated_at)
+		VALUES ($1, $2, $3, $4, $5, now())
+		ON CONFLICT (id) DO UPDATE SET type = EXCLUDED.type, name = EXCLUDED.name,
+			location_providers = EXCLUDED.location_providers, properties = EXCLUDED.properties, updated_at = now()`)
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		for _, t := range trackables {
+			providers, err := json.Marshal(append([]string{}, t.LocationProviders...))
+			if err != nil {
+				return err
+			}
+			var properties any
+			if len(t.Properties) > 0 {
+				properties = string(t.Properties)
+			}
+
+			if _, err := tx.ExecContext(ctx, stmt, t.ID.String(), t.Type.String(), t.Name, string(providers), properties); err != nil {
+				return fmt.Errorf("trackable %s: %w", t.ID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// InsertLocations inserts locations into the history, once per trackable, and updates
+// the last known positions. Locations already inserted are ignored, and locations older
+// than the last known position do not replace it.
+func (s *Store) InsertLocations(ctx context.Context, locations ...omlox.Location) error {
+	insert := s.sql(`INSERT INTO {schema}.locations (trackable_id, provider_id, provider_type, crs, position, floor, accuracy, generated_at)
+		VALUES ($1, $2, $3, $4, ST_SetSRID(ST_MakePoint($5, $6, $7), $8), $9, $10, $11)
+		ON CONFLICT (provider_id, COALESCE(trackable_id, '00000000-0000-0000-0000-000000000000'), generated_at) DO NOTHING`)
+	upsert := s.sql(`INSERT INTO {schema}.positions AS p (key, trackable_id, provider_id, provider_type, crs, position, floor, accuracy, generated_at)
+		VALUES ($1, $2, $3, $4, $5, ST_SetSRID(ST_MakePoint($6, $7, $8), $9), $10, $11, $12)
+		ON CONFLICT (key) DO UPDATE SET trackable_id = EXCLUDED.trackable_id, provider_id = EXCLUDED.provider_id,
+			provider_type = EXCLUDED.provider_type, crs = EXCLUDED.crs, position = EXCLUDED.position, floor = EXCLUDED.floor,
+			accuracy = EXCLUDED.accuracy, generated_at = EXCLUDED.generated_at
+		WHERE p.generated_at <= EXCLUDED.generated_at`)
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		for _, loc := range locations {
+			p, err := point(&loc)
+			if err != nil {
+				return fmt.Errorf("provider %s: %w", loc.ProviderID, err)
+			}
+
+			crs := loc.Crs
+			if crs == "" {
+				crs = omlox.CrsLocal
+			}
+			at := time.Now()
+			if loc.TimestampGenerated != nil {
+				at = *loc.TimestampGenerated
+			}
+			var accuracy any
+			if loc.Accuracy != nil {
+				accuracy = *loc.Accuracy
+			}
+
+			trackables := make([]*uuid.UUID, 0, len(loc.Trackables))
+			for i := range loc.Trackables {
+				trackables = append(trackables, &loc.Trackables[i])
+			}
+			if len(trackables) == 0 {
+				trackables = append(trackables, nil)
+			}
+
+			for _, id := range trackables {
+				key := loc.ProviderID
+				if id != nil {
+					key = id.String()
+				}
+
+				args := append([]any{nullUUID(id), loc.ProviderID, loc.ProviderType.String(), crs}, p...)
+				if _, err := tx.ExecContext(ctx, insert, append(args, loc.Floor, accuracy, at)...); err != nil {
+					return fmt.Errorf("provider %s: %w", loc.ProviderID, err)
+				}
+				if _, err := tx.ExecContext(ctx, upsert, append(append([]any{key}, args...), loc.Floor, accuracy, at)...); err != nil {
+					return fmt.Errorf("provider %s: %w", loc.ProviderID, err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// UpsertFenceEvents inserts fence events, or updates their exit time.
+func (s *Store) UpsertFenceEvents(ctx context.Context, events ...omlox.FenceEvent) error {
+	stmt := s.sql(`INSERT INTO {schema}.fence_events AS e (id, fence_id, trackable_id, provider_id, event_type, entry_time, exit_time, foreign_id, position)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, CASE WHEN $9::boolean THEN ST_SetSRID(ST_MakePoint($10, $11, $12), $13) END)
+		ON CONFLICT (id) DO UPDATE SET event_type = EXCLUDED.event_type,
+			entry_time = COALESCE(EXCLUDED.entry_time, e.entry_time),
+			exit_time = COALESCE(EXCLUDED.exit_time, e.exit_time),
+			position = COALESCE(EXCLUDED.position, e.position)`)
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		for _, e := range events {
+			if e.ID == uuid.Nil {
+				return errors.New("fence event must have an id")
+			}
+
+			p := []any{false, 0.0, 0.0, 0.0, 0}
+			if e.Location != nil {
+				lp, err := point(e.Location)
+				if err != nil {
+					return fmt.Errorf("fence event %s: %w", e.ID, err)
+				}
+				p = append([]any{true}, lp...)
+			}
+
+			trackable := e.TrackableID
+			if trackable == nil && len(e.Trackables) > 0 {
+				trackable = &e.Trackables[0]
+			}
+
+			args := append([]any{
+				e.ID.String(), e.FenceID.String(), nullUUID(trackable), e.ProviderID, e.EventType.String(),
+				nullTime(e.EntryTime), nullTime(e.ExitTime), e.ForeignID,
+			}, p...)
+			if _, err := tx.ExecContext(ctx, stmt, args...); err != nil {
+				return fmt.Errorf("fence event %s: %w", e.ID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// nullTime returns the argument of an optional time.
+func nullTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
+
+// inTx runs a function in a transaction, committed if it succeeds.
+func (s *Store) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
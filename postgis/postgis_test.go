@@ -0,0 +1,250 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package postgis
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/cdc"
+	"github.com/wavecomtech/omlox-client-go/forward"
+)
+
+var (
+	forklift = uuid.MustParse("6c5d2f0e-8f6a-4b7e-9a51-0d6f1a2b3c41")
+	pallet   = uuid.MustParse("9b8a7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d")
+	dock     = uuid.MustParse("3a1f0c9e-7d2b-4e6f-8a5c-1b2d3e4f5a66")
+)
+
+// statement is a statement executed by the fake driver.
+type statement struct {
+	query string
+	args  []driver.Value
+}
+
+// fakeDB is a fake database recording the statements of committed transactions.
+type fakeDB struct {
+	mu         sync.Mutex
+	version    int64
+	statements []statement
+}
+
+var _ driver.Connector = (*fakeDB)(nil)
+
+func (db *fakeDB) Connect(context.Context) (driver.Conn, error) { return &fakeConn{db: db}, nil }
+func (db *fakeDB) Driver() driver.Driver                        { return nil }
+
+// Statements returns and clears the statements committed.
+func (db *fakeDB) Statements() []statement {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	statements := db.statements
+	db.statements = nil
+	return statements
+}
+
+type fakeConn struct {
+	db      *fakeDB
+	pending []statement
+	version int64
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.db.mu.Lock()
+	c.version = c.db.version
+	c.db.mu.Unlock()
+	c.pending = nil
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	c.db.statements = append(c.db.statements, c.pending...)
+	c.db.version = c.version
+	c.pending = nil
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	c.pending = nil
+	return nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	s := statement{query: query}
+	for _, arg := range args {
+		s.args = append(s.args, arg.Value)
+	}
+	if strings.Contains(query, "schema_migrations (version)") {
+		c.version = args[0].Value.(int64)
+	}
+	c.pending = append(c.pending, s)
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if !strings.Contains(query, "MAX(version)") {
+		return nil, errors.New("unexpected query")
+	}
+	return &versionRows{version: c.version}, nil
+}
+
+type versionRows struct {
+	version int64
+	done    bool
+}
+
+func (r *versionRows) Columns() []string { return []string{"version"} }
+func (r *versionRows) Close() error      { return nil }
+func (r *versionRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.version
+	return nil
+}
+
+func testStore(t *testing.T) (*fakeDB, *Store) {
+	t.Helper()
+
+	fake := &fakeDB{}
+	db := sql.OpenDB(fake)
+	t.Cleanup(func() { db.Close() })
+
+	return fake, New(db, WithSchema("site\"1"))
+}
+
+func TestMigrate(t *testing.T) {
+	fake, s := testStore(t)
+	ctx := context.Background()
+
+	if err := s.Migrate(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	statements := fake.Statements()
+	if want := 3 + len(migrations[0]) + 1; len(statements) != want {
+		t.Fatalf("expected %d statements, got %d", want, len(statements))
+	}
+	if !strings.Contains(statements[1].query, `CREATE SCHEMA IF NOT EXISTS "site""1"`) {
+		t.Errorf("expected the quoted schema, got %s", statements[1].query)
+	}
+
+	version, err := s.Version(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if version != len(migrations) {
+		t.Errorf("expected version %d, got %d", len(migrations), version)
+	}
+
+	// migrations are applied once
+	if err := s.Migrate(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if statements := fake.Statements(); len(statements) != 3 {
+		t.Errorf("expected 3 statements, got %d", len(statements))
+	}
+}
+
+func TestStoreDeliver(t *testing.T) {
+	fake, s := testStore(t)
+	ctx := context.Background()
+
+	payload := json.RawMessage(`{"position":{"type":"Point","coordinates":[8.5,47.25]},"crs":"EPSG:4326","provider_id":"tag-1","provider_type":"uwb","source":"tag-1","trackables":["` + forklift.String() + `","` + pallet.String() + `"],"accuracy":2,"timestamp_generated":"2024-05-01T10:00:00Z"}`)
+	if err := s.Deliver(ctx, forward.Event{Topic: omlox.TopicLocationUpdates, Payload: payload}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	statements := fake.Statements()
+	if len(statements) != 4 {
+		t.Fatalf("expected 4 statements, got %d", len(statements))
+	}
+	wantLocation := []driver.Value{forklift.String(), "tag-1", "uwb", "EPSG:4326", 8.5, 47.25, 0.0, int64(4326), 0.0, 2.0, at}
+	if diff := cmp.Diff(wantLocation, statements[0].args); diff != "" {
+		t.Errorf("unexpected location arguments (-want +got):\n%s", diff)
+	}
+	wantPosition := append([]driver.Value{pallet.String(), pallet.String()}, wantLocation[1:]...)
+	if diff := cmp.Diff(wantPosition, statements[3].args); diff != "" {
+		t.Errorf("unexpected position arguments (-want +got):\n%s", diff)
+	}
+
+	event := omlox.FenceEvent{
+		ID:         uuid.MustParse("0e1d2c3b-4a59-4867-9a5b-6c7d8e9f0a1b"),
+		FenceID:    dock,
+		Trackables: []uuid.UUID{forklift},
+		ProviderID: "tag-1",
+		EventType:  omlox.FenceEventTypeRegionExit,
+		EntryTime:  &at,
+		ExitTime:   &at,
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Deliver(ctx, forward.Event{Topic: omlox.TopicFenceEvents, Payload: payload}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	statements = fake.Statements()
+	wantEvent := []driver.Value{event.ID.String(), dock.String(), forklift.String(), "tag-1", "region_exit", at, at, "", false, 0.0, 0.0, 0.0, int64(0)}
+	if diff := cmp.Diff(wantEvent, statements[0].args); diff != "" {
+		t.Errorf("unexpected fence event arguments (-want +got):\n%s", diff)
+	}
+
+	unsupported := omlox.Location{Crs: "urn:ogc:def:crs:OGC::CRS84", ProviderID: "tag-1"}
+	if err := s.InsertLocations(ctx, unsupported); err == nil {
+		t.Errorf("expected an unsupported crs error")
+	}
+	if statements := fake.Statements(); len(statements) != 0 {
+		t.Errorf("expected the transaction to be rolled back, got %d statements", len(statements))
+	}
+}
+
+func TestStoreApply(t *testing.T) {
+	fake, s := testStore(t)
+	ctx := context.Background()
+
+	payload, err := json.Marshal(omlox.Trackable{ID: forklift, Type: omlox.TrackableTypeVirtual, Name: "forklift"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	changes := []cdc.Change{
+		{Seq: 1, Resource: cdc.ResourceTrackables, Op: cdc.OpUpsert, ID: forklift.String(), Payload: payload},
+		{Seq: 2, Resource: cdc.ResourceFences, Op: cdc.OpDelete, ID: dock.String()},
+		{Seq: 3, Resource: cdc.ResourceTrackables, Op: cdc.OpDelete, ID: pallet.String()},
+	}
+	for _, c := range changes {
+		if err := s.Apply(ctx, c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	statements := fake.Statements()
+	if len(statements) != 2 {
+		t.Fatalf("expected 2 statements, got %d", len(statements))
+	}
+	if diff := cmp.Diff([]driver.Value{forklift.String(), "virtual", "forklift", "[]", nil}, statements[0].args); diff != "" {
+		t.Errorf("unexpected trackable arguments (-want +got):\n%s", diff)
+	}
+	if !strings.HasPrefix(statements[1].query, `DELETE FROM "site""1".trackables`) || statements[1].args[0] != pallet.String() {
+		t.Errorf("unexpected delete: %+v", statements[1])
+	}
+}
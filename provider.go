@@ -6,6 +6,9 @@ package omlox
 import (
 	"encoding/json"
 	"fmt"
+
+	"github.com/mailru/easyjson/jlexer"
+	"github.com/mailru/easyjson/jwriter"
 )
 
 // LocationProvider defines model for LocationProvider.
@@ -119,6 +122,24 @@ func (t LocationProviderType) MarshalJSON() ([]byte, error) {
 	return json.Marshal(t.String())
 }
 
+// MarshalEasyJSON encodes type to JSON without intermediate allocations.
+func (t LocationProviderType) MarshalEasyJSON(w *jwriter.Writer) {
+	w.String(t.String())
+}
+
+// UnmarshalEasyJSON decodes type from JSON without intermediate allocations.
+func (t *LocationProviderType) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	name := l.UnsafeString()
+	for v := LocationProviderTypeUnknown; v.String() != ""; v++ {
+		if v.String() == name {
+			*t = v
+			return
+		}
+	}
+
+	l.AddError(t.FromString(name))
+}
+
 // UnmarshalJSON decodes type from JSON.
 func (t *LocationProviderType) UnmarshalJSON(b []byte) error {
 	var s string
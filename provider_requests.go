@@ -5,127 +5,75 @@ package omlox
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"slices"
 )
 
 // ProvidersAPI is a simple wrapper around the client for location provider requests.
 type ProvidersAPI struct {
 	client *Client
+
+	service *Service[LocationProvider]
 }
 
 // List lists all location providers.
 func (c *ProvidersAPI) List(ctx context.Context) ([]LocationProvider, error) {
-	requestPath := "/providers/summary"
-
-	return sendRequestParseResponseList[LocationProvider](
-		ctx,
-		c.client,
-		http.MethodGet,
-		requestPath,
-		nil, // request body
-		nil, // request query parameters
-		nil, // request headers
-	)
+	return c.service.List(ctx)
 }
 
 // IDs lists all location providers IDs.
 func (c *ProvidersAPI) IDs(ctx context.Context) ([]string, error) {
-	requestPath := "/providers"
-
-	return sendRequestParseResponseList[string](
-		ctx,
-		c.client,
-		http.MethodGet,
-		requestPath,
-		nil, // request body
-		nil, // request query parameters
-		nil, // request headers
-	)
+	return listIDs[string](ctx, c.service)
 }
 
 // Create creates a location provider.
 func (c *ProvidersAPI) Create(ctx context.Context, provider LocationProvider) (*LocationProvider, error) {
-	requestPath := "/providers"
-
-	return sendStructuredRequestParseResponse[LocationProvider](
-		ctx,
-		c.client,
-		http.MethodPost,
-		requestPath,
-		provider,
-		nil, // request query parameters
-		nil, // request headers
-	)
+	return c.service.Create(ctx, provider)
 }
 
 // DeleteAll deletes all location providers.
 func (c *ProvidersAPI) DeleteAll(ctx context.Context) error {
-	requestPath := "/providers"
-
-	_, err := sendRequestParseResponse[struct{}](
-		ctx,
-		c.client,
-		http.MethodDelete,
-		requestPath,
-		nil, // request body
-		nil, // request query parameters
-		nil, // request headers
-	)
-
-	return err
+	return c.service.DeleteAll(ctx)
 }
 
 // Get gets a location provider.
 func (c *ProvidersAPI) Get(ctx context.Context, id string) (*LocationProvider, error) {
-	requestPath := "/providers/" + id
-
-	return sendRequestParseResponse[LocationProvider](
-		ctx,
-		c.client,
-		http.MethodGet,
-		requestPath,
-		nil, // request body
-		nil, // request query parameters
-		nil, // request headers
-	)
+	return c.service.Get(ctx, id)
 }
 
 // Update updates a location provider.
 func (c *ProvidersAPI) Update(ctx context.Context, provider LocationProvider, id string) error {
-	requestPath := "/providers/" + id
-
-	_, err := sendStructuredRequestParseResponse[struct{}](
-		ctx,
-		c.client,
-		http.MethodPut,
-		requestPath,
-		provider,
-		nil, // request query parameters
-		nil, // request headers
-	)
-
-	return err
+	return c.service.Update(ctx, provider, id)
 }
 
 // Delete deletes a location provider.
 func (c *ProvidersAPI) Delete(ctx context.Context, id string) error {
-	requestPath := "/providers/" + id
+	return c.service.Delete(ctx, id)
+}
+
+// GetLocation gets the last location of a location provider.
+func (c *ProvidersAPI) GetLocation(ctx context.Context, id string) (*Location, error) {
+	requestPath := "/providers/" + id + "/location"
 
-	_, err := sendRequestParseResponse[struct{}](
+	return sendRequestParseResponse[Location](
 		ctx,
 		c.client,
-		http.MethodDelete,
+		http.MethodGet,
 		requestPath,
 		nil, // request body
 		nil, // request query parameters
 		nil, // request headers
 	)
-
-	return err
 }
 
 // UpdateLocation updates the location of a location provider.
 func (c *ProvidersAPI) UpdateLocation(ctx context.Context, location Location, id string) error {
+	return c.SetLocation(ctx, location, id)
+}
+
+// SetLocation sets the location of a location provider, pushing a position into the Hub.
+func (c *ProvidersAPI) SetLocation(ctx context.Context, location Location, id string) error {
 	requestPath := "/providers/" + id + "/location"
 
 	_, err := sendStructuredRequestParseResponse[struct{}](
@@ -140,3 +88,57 @@ func (c *ProvidersAPI) UpdateLocation(ctx context.Context, location Location, id
 
 	return err
 }
+
+// GetTrackables gets the trackables using a location provider, such as the
+// trackables to update when a tag is decommissioned.
+//
+// The Hub endpoint is used where available. Otherwise, the trackables are listed
+// and indexed client-side with TrackablesByProvider, which also finds trackables
+// still referencing a provider already deleted.
+func (c *ProvidersAPI) GetTrackables(ctx context.Context, id string) ([]Trackable, error) {
+	if c.client.require(ctx, FeatureProviderTrackables) == nil {
+		trackables, err := sendRequestParseResponseList[Trackable](
+			ctx,
+			c.client,
+			http.MethodGet,
+			"/providers/"+id+"/trackables",
+			nil, // request body
+			nil, // request query parameters
+			nil, // request headers
+		)
+		if !isEndpointUnavailable(err) {
+			return trackables, err
+		}
+	}
+
+	trackables, err := c.client.Trackables.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return TrackablesByProvider(trackables)[id], nil
+}
+
+// TrackablesByProvider indexes the trackables by the ids of their location providers.
+// Trackables using several providers are indexed under each of them.
+func TrackablesByProvider(trackables []Trackable) map[string][]Trackable {
+	index := make(map[string][]Trackable)
+	for _, t := range trackables {
+		for _, id := range t.LocationProviders {
+			index[id] = append(index[id], t)
+		}
+	}
+
+	return index
+}
+
+// isEndpointUnavailable reports whether the error is the response of a Hub
+// not implementing the requested endpoint.
+func isEndpointUnavailable(err error) bool {
+	var e *Error
+	if !errors.As(err, &e) {
+		return false
+	}
+
+	return slices.Contains([]int{http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented}, e.Code)
+}
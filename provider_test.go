@@ -4,7 +4,11 @@
 package omlox
 
 import (
+	"context"
 	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -53,3 +57,118 @@ func TestProviderUnmarshal(t *testing.T) {
 		})
 	}
 }
+
+func TestProvidersGetTrackables(t *testing.T) {
+	const trackables = `[
+		{"id":"2b5f4a7c-1d1e-4c7e-9d27-5f3b2f1a0001","type":"virtual","location_providers":["ac:23:3f:ac:a3:87","ac:23:3f:ac:a3:88"]},
+		{"id":"2b5f4a7c-1d1e-4c7e-9d27-5f3b2f1a0002","type":"virtual","location_providers":["ac:23:3f:ac:a3:87"]},
+		{"id":"2b5f4a7c-1d1e-4c7e-9d27-5f3b2f1a0003","type":"virtual","location_providers":["ac:23:3f:ac:a3:89"]}
+	]`
+
+	// trackables of the provider, as served by the hub endpoint
+	const providerTrackables = `[
+		{"id":"2b5f4a7c-1d1e-4c7e-9d27-5f3b2f1a0001","type":"virtual","location_providers":["ac:23:3f:ac:a3:87","ac:23:3f:ac:a3:88"]},
+		{"id":"2b5f4a7c-1d1e-4c7e-9d27-5f3b2f1a0002","type":"virtual","location_providers":["ac:23:3f:ac:a3:87"]}
+	]`
+
+	tests := []struct {
+		name     string
+		endpoint bool
+		version  string
+		requests []string
+	}{
+		{
+			name:     "hub endpoint",
+			endpoint: true,
+			requests: []string{"/v2/providers/ac:23:3f:ac:a3:87/trackables"},
+		},
+		{
+			name:     "endpoint not found",
+			requests: []string{"/v2/providers/ac:23:3f:ac:a3:87/trackables", "/v2/trackables/summary"},
+		},
+		{
+			name:     "unsupported hub version",
+			version:  "1.0.0",
+			requests: []string{"/v2/trackables/summary"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+
+			httpClient := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					requests = append(requests, r.URL.Path)
+
+					status, body := http.StatusOK, trackables
+					if strings.HasSuffix(r.URL.Path, "/trackables") {
+						body = providerTrackables
+						if !tt.endpoint {
+							status, body = http.StatusNotFound, `{"type":"not_found","code":404}`
+						}
+					}
+
+					return &http.Response{
+						StatusCode: status,
+						Body:       io.NopCloser(strings.NewReader(body)),
+					}, nil
+				}),
+			}
+
+			opts := []ClientOption{WithHTTPClient(httpClient), WithHubVersion("1.1.0")}
+			if tt.version != "" {
+				opts = append(opts, WithHubVersion(tt.version))
+			}
+
+			c, err := New("http://localhost:8081/v2", opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := c.Providers.GetTrackables(context.Background(), "ac:23:3f:ac:a3:87")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != 2 || got[0].ID.String() != "2b5f4a7c-1d1e-4c7e-9d27-5f3b2f1a0001" || got[1].ID.String() != "2b5f4a7c-1d1e-4c7e-9d27-5f3b2f1a0002" {
+				t.Errorf("unexpected trackables: %+v", got)
+			}
+
+			if strings.Join(requests, " ") != strings.Join(tt.requests, " ") {
+				t.Errorf("expected requests %v, got %v", tt.requests, requests)
+			}
+		})
+	}
+}
+
+func TestProvidersGetLocation(t *testing.T) {
+	var requests []string
+
+	httpClient := &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+
+			body := `{"position":{"type":"Point","coordinates":[3,4]},"provider_id":"ac:23:3f:ac:a3:87","provider_type":"uwb","source":"ac:23:3f:ac:a3:87"}`
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}, nil
+		}),
+	}
+
+	c, err := New("http://localhost:8081/v2", WithHTTPClient(httpClient))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loc, err := c.Providers.GetLocation(context.Background(), "ac:23:3f:ac:a3:87")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc.ProviderID != "ac:23:3f:ac:a3:87" || loc.ProviderType != LocationProviderTypeUwb {
+		t.Errorf("unexpected location: %+v", loc)
+	}
+	if want := "GET /v2/providers/ac:23:3f:ac:a3:87/location"; len(requests) != 1 || requests[0] != want {
+		t.Errorf("expected request %s, got %v", want, requests)
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// withProxy returns a copy of the HTTP client sending its requests through a proxy.
+func withProxy(client *http.Client, proxy *url.URL) (*http.Client, error) {
+	transport, err := httpTransport(client)
+	if err != nil {
+		return nil, err
+	}
+
+	transport.Proxy = http.ProxyURL(proxy)
+
+	c := *client
+	c.Transport = transport
+	return &c, nil
+}
+
+// httpTransport returns a clone of the transport of an HTTP client.
+func httpTransport(client *http.Client) (*http.Transport, error) {
+	switch t := client.Transport.(type) {
+	case nil:
+		return http.DefaultTransport.(*http.Transport).Clone(), nil
+	case *http.Transport:
+		return t.Clone(), nil
+	default:
+		return nil, fmt.Errorf("the HTTP client transport must be an *http.Transport, got %T", client.Transport)
+	}
+}
+
+// websocketClient returns the HTTP client dialing the websocket interface at the address.
+//
+// HTTP transports tunnel TLS connections through proxies with CONNECT and dial SOCKS5
+// proxies themselves, but send plain HTTP requests to proxies as is, which proxies
+// do not upgrade to websockets. Plain websocket connections are tunneled with CONNECT instead.
+func (c *Client) websocketClient(addr *url.URL) (*http.Client, error) {
+	transport, ok := c.client.Transport.(*http.Transport)
+	if c.client.Transport == nil {
+		transport, ok = http.DefaultTransport.(*http.Transport), true
+	}
+	if !ok || transport.Proxy == nil || addr.Scheme != httpScheme {
+		return c.client, nil
+	}
+
+	proxy, err := transport.Proxy(&http.Request{Method: http.MethodGet, URL: addr, Header: make(http.Header)})
+	if err != nil {
+		return nil, err
+	}
+	if proxy == nil || (proxy.Scheme != httpScheme && proxy.Scheme != httpSchemeTLS) {
+		return c.client, nil
+	}
+
+	tunnel := transport.Clone()
+	tunnel.Proxy = nil
+	tunnel.DialContext = connectDialer(proxy, transport.DialContext, transport.TLSClientConfig)
+
+	client := *c.client
+	client.Transport = tunnel
+	return &client, nil
+}
+
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// connectDialer returns a dialer tunneling connections through an HTTP proxy with CONNECT,
+// authenticating with the user information of the proxy URL, if any.
+func connectDialer(proxy *url.URL, dial dialFunc, tlsConfig *tls.Config) dialFunc {
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		proxyAddr := proxy.Host
+		if proxy.Port() == "" {
+			port := "80"
+			if proxy.Scheme == httpSchemeTLS {
+				port = "443"
+			}
+			proxyAddr = net.JoinHostPort(proxy.Hostname(), port)
+		}
+
+		conn, err := dial(ctx, network, proxyAddr)
+		if err != nil {
+			return nil, err
+		}
+
+		if proxy.Scheme == httpSchemeTLS {
+			cfg := tlsConfig.Clone()
+			if cfg == nil {
+				cfg = &tls.Config{}
+			}
+			if cfg.ServerName == "" {
+				cfg.ServerName = proxy.Hostname()
+			}
+
+			tlsConn := tls.Client(conn, cfg)
+			if err := tlsConn.HandshakeContext(ctx); err != nil {
+				conn.Close()
+				return nil, err
+			}
+			conn = tlsConn
+		}
+
+		// abort the handshake with the proxy if the context is done
+		stop := context.AfterFunc(ctx, func() { conn.Close() })
+
+		tunnel, err := connect(conn, proxy, addr)
+		if !stop() {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		return tunnel, nil
+	}
+}
+
+// connect requests a tunnel to the address through a proxy connection.
+func connect(conn net.Conn, proxy *url.URL, addr string) (net.Conn, error) {
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+
+	if u := proxy.User; u != nil {
+		password, _ := u.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(u.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	if err := req.Write(conn); err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("proxy connect to '%s' failed: %s", addr, resp.Status)
+	}
+
+	// the proxy may have sent data of the tunnel along with its response
+	if br.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, r: br}, nil
+	}
+
+	return conn, nil
+}
+
+// bufferedConn is a connection with data already read into a buffer.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
@@ -0,0 +1,310 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dropoutFactor is how many times the expected update interval a gap between
+// two updates of a provider must exceed to be considered a dropout.
+const dropoutFactor = 3
+
+// intervalSmoothing is the weight of the latest update interval in the
+// exponential moving average of the expected interval.
+const intervalSmoothing = 0.1
+
+// maxQualityProviders is the number of location providers whose metrics are kept.
+// The least recently seen half is evicted past it.
+const maxQualityProviders = 4096
+
+// QualityAPI computes location provider data quality metrics from the
+// location updates received through websocket subscriptions, when enabled with
+// WithProviderQuality, or observed explicitly.
+type QualityAPI struct {
+	client *Client
+
+	stats *qualityStats
+}
+
+// ProviderQuality holds the data quality metrics of a location provider.
+type ProviderQuality struct {
+	// ProviderID is the id of the location provider.
+	ProviderID string `json:"provider_id"`
+
+	// Name is the name of the location provider, if known by the hub.
+	Name string `json:"name,omitempty"`
+
+	// Updates is the number of location updates received.
+	Updates int `json:"updates"`
+
+	// UpdateRate is the average number of updates per second.
+	UpdateRate float64 `json:"update_rate"`
+
+	// Interval is the expected time between updates, as a moving average of
+	// the observed intervals excluding dropouts.
+	Interval time.Duration `json:"interval"`
+
+	// Accuracy is the mean reported horizontal accuracy in meters, if any was reported.
+	Accuracy *float64 `json:"accuracy,omitempty"`
+
+	// Dropouts is the number of gaps between updates longer than expected.
+	Dropouts int `json:"dropouts"`
+
+	// DropoutRate is the ratio of update intervals that were dropouts.
+	DropoutRate float64 `json:"dropout_rate"`
+
+	// FirstSeen and LastSeen are the times of the first and last updates.
+	FirstSeen time.Time `json:"first_seen,omitempty"`
+	LastSeen  time.Time `json:"last_seen,omitempty"`
+}
+
+// providerStats accumulates the observations of a location provider.
+type providerStats struct {
+	updates   int
+	interval  float64 // expected interval in seconds
+	dropouts  int
+	accuracy  float64 // sum of reported accuracies
+	accurate  int     // number of updates reporting accuracy
+	firstSeen time.Time
+	lastSeen  time.Time
+	seq       uint64 // order of the last observation, for eviction
+}
+
+// qualityStats holds the observations of all location providers.
+type qualityStats struct {
+	mu        sync.Mutex
+	providers map[string]*providerStats
+	seq       uint64
+}
+
+func newQualityStats() *qualityStats {
+	return &qualityStats{providers: make(map[string]*providerStats)}
+}
+
+// Observe accounts location updates in the quality metrics of their providers.
+// Location updates decoded by subscriptions are observed automatically with
+// WithProviderQuality. Locations without generation timestamp are accounted at the
+// current time, and locations generated at the same time as the last one of their
+// provider, such as those received by several subscriptions, are accounted once.
+func (q *QualityAPI) Observe(locations ...Location) {
+	q.stats.mu.Lock()
+	defer q.stats.mu.Unlock()
+
+	now := q.client.timeSource().Now()
+	for i := range locations {
+		q.stats.observe(&locations[i], now)
+	}
+}
+
+// observe accounts a location update decoded by a subscription.
+func (q *QualityAPI) observe(loc *Location) {
+	q.stats.mu.Lock()
+	defer q.stats.mu.Unlock()
+
+	q.stats.observe(loc, q.client.timeSource().Now())
+}
+
+// observe accounts a single location update, generated now if it has no generation
+// timestamp. Must be called with the lock held.
+func (s *qualityStats) observe(loc *Location, now time.Time) {
+	at := now
+	if loc.TimestampGenerated != nil {
+		at = *loc.TimestampGenerated
+	}
+
+	s.seq++
+	p, ok := s.providers[loc.ProviderID]
+	if !ok {
+		p = &providerStats{firstSeen: at, seq: s.seq}
+		s.providers[loc.ProviderID] = p
+		s.evict()
+	}
+	p.seq = s.seq
+
+	// the same update received again
+	if p.updates > 0 && at.Equal(p.lastSeen) {
+		return
+	}
+
+	if loc.Accuracy != nil {
+		p.accuracy += *loc.Accuracy
+		p.accurate++
+	}
+
+	p.updates++
+	if p.updates == 1 {
+		p.lastSeen = at
+		return
+	}
+
+	// out of order updates do not contribute to the interval metrics
+	if !at.After(p.lastSeen) {
+		return
+	}
+
+	gap := at.Sub(p.lastSeen).Seconds()
+	p.lastSeen = at
+
+	switch {
+	case p.interval == 0:
+		p.interval = gap
+	case gap > dropoutFactor*p.interval:
+		p.dropouts++
+	default:
+		p.interval += intervalSmoothing * (gap - p.interval)
+	}
+}
+
+// evict drops the least recently seen half of the providers once there are too many.
+// Must be called with the lock held.
+func (s *qualityStats) evict() {
+	if len(s.providers) <= maxQualityProviders {
+		return
+	}
+
+	seqs := make([]uint64, 0, len(s.providers))
+	for _, p := range s.providers {
+		seqs = append(seqs, p.seq)
+	}
+	slices.Sort(seqs)
+	oldest := seqs[len(seqs)/2]
+	for id, p := range s.providers {
+		if p.seq < oldest {
+			delete(s.providers, id)
+		}
+	}
+}
+
+// quality returns the metrics of a provider. Must be called with the lock held.
+func (p *providerStats) quality(providerID string) ProviderQuality {
+	pq := ProviderQuality{
+		ProviderID: providerID,
+		Updates:    p.updates,
+		Interval:   time.Duration(p.interval * float64(time.Second)),
+		Dropouts:   p.dropouts,
+		FirstSeen:  p.firstSeen,
+		LastSeen:   p.lastSeen,
+	}
+
+	if span := p.lastSeen.Sub(p.firstSeen).Seconds(); span > 0 {
+		pq.UpdateRate = float64(p.updates-1) / span
+	}
+	if p.updates > 1 {
+		pq.DropoutRate = float64(p.dropouts) / float64(p.updates-1)
+	}
+	if p.accurate > 0 {
+		accuracy := p.accuracy / float64(p.accurate)
+		pq.Accuracy = &accuracy
+	}
+
+	return pq
+}
+
+// snapshot returns the metrics of all observed providers sorted by id.
+func (q *QualityAPI) snapshot() []ProviderQuality {
+	q.stats.mu.Lock()
+	defer q.stats.mu.Unlock()
+
+	qs := make([]ProviderQuality, 0, len(q.stats.providers))
+	for id, p := range q.stats.providers {
+		qs = append(qs, p.quality(id))
+	}
+
+	sort.Slice(qs, func(i, j int) bool { return qs[i].ProviderID < qs[j].ProviderID })
+
+	return qs
+}
+
+// Providers returns the data quality metrics of all location providers known by
+// the hub, sorted by id. Providers without any observed location update are
+// included with zeroed metrics, which helps spotting failing providers.
+func (q *QualityAPI) Providers(ctx context.Context) ([]ProviderQuality, error) {
+	providers, err := q.client.Providers.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	observed := q.snapshot()
+
+	byID := make(map[string]int, len(observed))
+	for i, pq := range observed {
+		byID[pq.ProviderID] = i
+	}
+
+	for _, p := range providers {
+		i, ok := byID[p.ID]
+		if !ok {
+			observed = append(observed, ProviderQuality{ProviderID: p.ID, Name: p.Name})
+			continue
+		}
+		observed[i].Name = p.Name
+	}
+
+	sort.Slice(observed, func(i, j int) bool { return observed[i].ProviderID < observed[j].ProviderID })
+
+	return observed, nil
+}
+
+// WritePrometheus writes the data quality metrics of the observed location
+// providers in the Prometheus text exposition format.
+func (q *QualityAPI) WritePrometheus(w io.Writer) error {
+	qs := q.snapshot()
+	bw := bufio.NewWriter(w)
+
+	metric := func(name, typ, help string, value func(pq ProviderQuality) (float64, bool)) {
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+		for _, pq := range qs {
+			if v, ok := value(pq); ok {
+				fmt.Fprintf(bw, "%s{provider_id=\"%s\"} %s\n", name, labelEscaper.Replace(pq.ProviderID), strconv.FormatFloat(v, 'g', -1, 64))
+			}
+		}
+	}
+
+	metric("omlox_provider_updates_total", "counter", "Number of location updates received from the provider.",
+		func(pq ProviderQuality) (float64, bool) { return float64(pq.Updates), true })
+	metric("omlox_provider_update_rate_hertz", "gauge", "Average number of location updates per second.",
+		func(pq ProviderQuality) (float64, bool) { return pq.UpdateRate, true })
+	metric("omlox_provider_update_interval_seconds", "gauge", "Expected time between location updates.",
+		func(pq ProviderQuality) (float64, bool) { return pq.Interval.Seconds(), true })
+	metric("omlox_provider_accuracy_meters", "gauge", "Mean reported horizontal accuracy.",
+		func(pq ProviderQuality) (float64, bool) {
+			if pq.Accuracy == nil {
+				return 0, false
+			}
+			return *pq.Accuracy, true
+		})
+	metric("omlox_provider_dropouts_total", "counter", "Number of gaps between location updates longer than expected.",
+		func(pq ProviderQuality) (float64, bool) { return float64(pq.Dropouts), true })
+	metric("omlox_provider_dropout_ratio", "gauge", "Ratio of location update intervals that were dropouts.",
+		func(pq ProviderQuality) (float64, bool) { return pq.DropoutRate, true })
+	metric("omlox_provider_last_seen_timestamp_seconds", "gauge", "Time of the last location update.",
+		func(pq ProviderQuality) (float64, bool) { return float64(pq.LastSeen.UnixMilli()) / 1000, true })
+
+	return bw.Flush()
+}
+
+// labelEscaper escapes Prometheus label values.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// PrometheusHandler returns an HTTP handler exposing the data quality metrics
+// of the observed location providers to be scraped by Prometheus.
+func (q *QualityAPI) PrometheusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := q.WritePrometheus(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,101 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQualityObserve(t *testing.T) {
+	q := QualityAPI{stats: newQualityStats()}
+
+	at := func(ms int) *time.Time {
+		ts := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC).Add(time.Duration(ms) * time.Millisecond)
+		return &ts
+	}
+
+	// one update per second, with a gap of 5 seconds
+	q.Observe(
+		Location{ProviderID: "a", TimestampGenerated: at(0), Accuracy: opt(1.0)},
+		Location{ProviderID: "a", TimestampGenerated: at(1000), Accuracy: opt(3.0)},
+		Location{ProviderID: "a", TimestampGenerated: at(2000)},
+		Location{ProviderID: "a", TimestampGenerated: at(7000)},
+		Location{ProviderID: "a", TimestampGenerated: at(8000)},
+	)
+
+	// location updates decoded by subscriptions, accounted once when received twice
+	loc := Location{ProviderID: "b", TimestampGenerated: at(0)}
+	q.observe(&loc)
+	q.observe(&loc)
+
+	qs := q.snapshot()
+	if len(qs) != 2 {
+		t.Fatalf("expected 2 providers, got %d", len(qs))
+	}
+
+	a := qs[0]
+	if a.ProviderID != "a" || a.Updates != 5 {
+		t.Errorf("expected 5 updates of provider 'a', got %d of '%s'", a.Updates, a.ProviderID)
+	}
+	if a.Dropouts != 1 || a.DropoutRate != 0.25 {
+		t.Errorf("expected 1 dropout in 4 intervals, got %d (%v)", a.Dropouts, a.DropoutRate)
+	}
+	if a.Interval != time.Second {
+		t.Errorf("expected 1s update interval, got %v", a.Interval)
+	}
+	if a.UpdateRate != 0.5 {
+		t.Errorf("expected 0.5Hz update rate, got %v", a.UpdateRate)
+	}
+	if a.Accuracy == nil || *a.Accuracy != 2 {
+		t.Errorf("expected mean accuracy of 2m, got %v", a.Accuracy)
+	}
+
+	if b := qs[1]; b.ProviderID != "b" || b.Updates != 1 {
+		t.Errorf("expected 1 update of provider 'b', got %d of '%s'", b.Updates, b.ProviderID)
+	}
+}
+
+func TestQualityEvict(t *testing.T) {
+	q := QualityAPI{stats: newQualityStats()}
+
+	for i := 0; i <= maxQualityProviders; i++ {
+		q.Observe(Location{ProviderID: strconv.Itoa(i)})
+	}
+
+	qs := q.snapshot()
+	if len(qs) > maxQualityProviders/2+1 {
+		t.Fatalf("expected the least recently seen providers to be evicted, got %d providers", len(qs))
+	}
+	if _, ok := q.stats.providers[strconv.Itoa(maxQualityProviders)]; !ok {
+		t.Errorf("expected the last seen provider to be kept")
+	}
+}
+
+func TestQualityWritePrometheus(t *testing.T) {
+	q := QualityAPI{stats: newQualityStats()}
+	q.Observe(Location{ProviderID: `tag"1`, TimestampGenerated: mustParseTime("2024-01-01T08:00:00Z")})
+
+	var buf bytes.Buffer
+	if err := q.WritePrometheus(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{
+		"# TYPE omlox_provider_updates_total counter",
+		`omlox_provider_updates_total{provider_id="tag\"1"} 1`,
+		`omlox_provider_last_seen_timestamp_seconds{provider_id="tag\"1"} 1.704096e+09`,
+	} {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("expected line %q in output:\n%s", line, buf.String())
+		}
+	}
+
+	if strings.Contains(buf.String(), "omlox_provider_accuracy_meters{") {
+		t.Errorf("expected no accuracy metric for providers without accuracy")
+	}
+}
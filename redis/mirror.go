@@ -0,0 +1,268 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package redis mirrors the last known positions of trackables into Redis, for
+// services querying Redis GEO sets, such as with GEOSEARCH, to consume location data
+// without changes.
+//
+// Positions are written with the go-redis client. The package is a module of its own,
+// so the client of the hub does not depend on go-redis.
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/forward"
+)
+
+// Defaults of the mirror configuration.
+const (
+	DefaultKeyPrefix = "omlox"
+	DefaultTTL       = 5 * time.Minute
+)
+
+// bounds of the coordinates accepted by GEOADD
+const maxLatitude = 85.05112878
+
+// MirrorOption is a configuration option of a mirror.
+type MirrorOption func(*Mirror)
+
+// WithKeyPrefix sets the prefix of the keys of the mirror.
+//
+// Default: DefaultKeyPrefix
+func WithKeyPrefix(prefix string) MirrorOption {
+	return func(m *Mirror) {
+		m.prefix = prefix
+	}
+}
+
+// WithTTL sets the time after which positions without updates expire. Zero keeps the
+// positions until they are replaced.
+//
+// Default: DefaultTTL
+func WithTTL(ttl time.Duration) MirrorOption {
+	return func(m *Mirror) {
+		m.ttl = ttl
+	}
+}
+
+// WithGeoreference sets the georeference converting local positions to WGS84, so they
+// are added to the GEO set. Without georeference, only WGS84 positions are.
+func WithGeoreference(g *omlox.Georeference) MirrorOption {
+	return func(m *Mirror) {
+		m.georeference = g
+	}
+}
+
+// Mirror mirrors the last known positions of trackables into Redis. It is safe for
+// concurrent use.
+//
+// Positions are the members of the GEO set <prefix>:positions, named by trackable id,
+// or by provider id for locations without trackables. Their details are the fields of
+// the hashes <prefix>:position:<member>: x, y, z, lon and lat, floor, crs, provider_id,
+// provider_type, accuracy and timestamp. Hashes expire after the TTL, and the members
+// of expired positions are removed from the GEO set by Prune.
+//
+// Mirror is a sink of the forward package, mirroring the location updates forwarded
+// and ignoring other events.
+type Mirror struct {
+	client       redis.UniversalClient
+	prefix       string
+	ttl          time.Duration
+	georeference *omlox.Georeference
+}
+
+var _ forward.Sink = (*Mirror)(nil)
+
+// NewMirror returns a mirror of positions with a client, such as a *redis.Client.
+func NewMirror(client redis.UniversalClient, opts ...MirrorOption) *Mirror {
+	m := &Mirror{
+		client: client,
+		prefix: DefaultKeyPrefix,
+		ttl:    DefaultTTL,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// GeoKey returns the key of the GEO set of the positions.
+func (m *Mirror) GeoKey() string {
+	return m.prefix + ":positions"
+}
+
+// PositionKey returns the key of the hash of the position of a member.
+func (m *Mirror) PositionKey(member string) string {
+	return m.prefix + ":position:" + member
+}
+
+// seenKey returns the key of the sorted set of the times members were updated.
+func (m *Mirror) seenKey() string {
+	return m.prefix + ":seen"
+}
+
+// Deliver implements forward.Sink.
+func (m *Mirror) Deliver(ctx context.Context, e forward.Event) error {
+	if e.Topic != omlox.TopicLocationUpdates {
+		return nil
+	}
+
+	var loc omlox.Location
+	if err := json.Unmarshal(e.Payload, &loc); err != nil {
+		return fmt.Errorf("invalid location update: %w", err)
+	}
+	return m.Update(ctx, loc)
+}
+
+// Update mirrors the positions of locations, in a transaction per location.
+func (m *Mirror) Update(ctx context.Context, locations ...omlox.Location) error {
+	var errs []error
+	for _, loc := range locations {
+		if err := m.update(ctx, loc); err != nil {
+			errs = append(errs, fmt.Errorf("provider %s: %w", loc.ProviderID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *Mirror) update(ctx context.Context, loc omlox.Location) error {
+	members := make([]string, 0, len(loc.Trackables))
+	for _, id := range loc.Trackables {
+		members = append(members, id.String())
+	}
+	if len(members) == 0 {
+		members = append(members, loc.ProviderID)
+	}
+
+	base := loc.Position.Base()
+	crs := loc.Crs
+	if crs == "" {
+		crs = omlox.CrsLocal
+	}
+
+	at := time.Now()
+	if loc.TimestampGenerated != nil {
+		at = *loc.TimestampGenerated
+	}
+
+	fields := []any{
+		"x", base.X,
+		"y", base.Y,
+		"z", loc.Position.Z(),
+		"floor", loc.Floor,
+		"crs", crs,
+		"provider_id", loc.ProviderID,
+		"provider_type", loc.ProviderType.String(),
+		"timestamp", at.UTC().Format(time.RFC3339Nano),
+	}
+	if loc.Accuracy != nil {
+		fields = append(fields, "accuracy", *loc.Accuracy)
+	}
+
+	lon, lat, geo := m.wgs84(loc)
+	if geo {
+		fields = append(fields, "lon", lon, "lat", lat)
+	}
+
+	_, err := m.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, member := range members {
+			key := m.PositionKey(member)
+			pipe.Del(ctx, key)
+			pipe.HSet(ctx, key, fields...)
+			if m.ttl > 0 {
+				pipe.PExpire(ctx, key, m.ttl)
+			}
+			if geo {
+				pipe.GeoAdd(ctx, m.GeoKey(), &redis.GeoLocation{Name: member, Longitude: lon, Latitude: lat})
+			} else {
+				pipe.ZRem(ctx, m.GeoKey(), member)
+			}
+			pipe.ZAdd(ctx, m.seenKey(), redis.Z{Score: float64(time.Now().UnixMilli()), Member: member})
+		}
+		return nil
+	})
+	return err
+}
+
+// wgs84 returns the WGS84 coordinates of a location, if it has any.
+func (m *Mirror) wgs84(loc omlox.Location) (lon, lat float64, ok bool) {
+	p := loc.Position.Base()
+	switch {
+	case loc.Crs == omlox.CrsWGS84:
+	case (loc.Crs == "" || loc.Crs == omlox.CrsLocal) && m.georeference != nil:
+		p = m.georeference.ToWGS84(p)
+	default:
+		return 0, 0, false
+	}
+
+	if p.X < -180 || p.X > 180 || p.Y < -maxLatitude || p.Y > maxLatitude {
+		return 0, 0, false
+	}
+	return p.X, p.Y, true
+}
+
+// Prune removes the members of expired positions from the GEO set.
+func (m *Mirror) Prune(ctx context.Context) error {
+	if m.ttl <= 0 {
+		return nil
+	}
+
+	cutoff := time.Now().Add(-m.ttl).UnixMilli()
+	expired, err := m.client.ZRangeByScore(ctx, m.seenKey(), &redis.ZRangeBy{Min: "-inf", Max: strconv.FormatInt(cutoff, 10)}).Result()
+	if err != nil || len(expired) == 0 {
+		return err
+	}
+
+	// positions updated since the range have a hash and are kept
+	exists := make([]*redis.IntCmd, len(expired))
+	if _, err := m.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, member := range expired {
+			exists[i] = pipe.Exists(ctx, m.PositionKey(member))
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	var stale []any
+	for i, member := range expired {
+		if exists[i].Val() == 0 {
+			stale = append(stale, member)
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+
+	_, err = m.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, m.GeoKey(), stale...)
+		pipe.ZRem(ctx, m.seenKey(), stale...)
+		return nil
+	})
+	return err
+}
+
+// RunPrune prunes the expired positions at each interval until the context is done.
+func (m *Mirror) RunPrune(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := m.Prune(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
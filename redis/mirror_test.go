@@ -0,0 +1,110 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/forward"
+)
+
+var forklift = uuid.MustParse("6c5d2f0e-8f6a-4b7e-9a51-0d6f1a2b3c41")
+
+// newClient returns a client of an in-memory Redis server.
+func newClient(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+
+	srv := miniredis.RunT(t)
+	c := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	t.Cleanup(func() { c.Close() })
+	return srv, c
+}
+
+func TestMirror(t *testing.T) {
+	srv, c := newClient(t)
+	ctx := context.Background()
+
+	m := NewMirror(c, WithTTL(time.Minute))
+
+	payload := json.RawMessage(`{"position":{"type":"Point","coordinates":[8.5,47.25]},"crs":"EPSG:4326","provider_id":"tag-1","provider_type":"uwb","source":"tag-1","trackables":["` + forklift.String() + `"],"accuracy":2,"timestamp_generated":"2024-05-01T10:00:00Z"}`)
+	if err := m.Deliver(ctx, forward.Event{Topic: omlox.TopicLocationUpdates, Payload: payload}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := m.Deliver(ctx, forward.Event{Topic: omlox.TopicFenceEvents, Payload: json.RawMessage(`{}`)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	hash := c.HGetAll(ctx, "omlox:position:"+forklift.String()).Val()
+	geo := c.GeoPos(ctx, "omlox:positions", forklift.String()).Val()
+	ttl := srv.TTL("omlox:position:" + forklift.String())
+
+	want := map[string]string{
+		"x":             "8.5",
+		"y":             "47.25",
+		"z":             "0",
+		"floor":         "0",
+		"crs":           "EPSG:4326",
+		"provider_id":   "tag-1",
+		"provider_type": "uwb",
+		"timestamp":     "2024-05-01T10:00:00Z",
+		"accuracy":      "2",
+		"lon":           "8.5",
+		"lat":           "47.25",
+	}
+	if diff := cmp.Diff(want, hash); diff != "" {
+		t.Errorf("unexpected hash (-want +got):\n%s", diff)
+	}
+	if len(geo) != 1 || geo[0] == nil || math.Abs(geo[0].Longitude-8.5) > 1e-5 || math.Abs(geo[0].Latitude-47.25) > 1e-5 {
+		t.Errorf("unexpected geo position: %v", geo)
+	}
+	if ttl != time.Minute {
+		t.Errorf("unexpected ttl: %d", ttl)
+	}
+
+	// local positions without georeference leave the GEO set
+	local := omlox.Location{Position: *omlox.NewPoint(geometry.Point{X: 3, Y: 4}), ProviderID: "tag-1", Trackables: []uuid.UUID{forklift}}
+	if err := m.Update(ctx, local); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	members, _ := srv.ZMembers("omlox:positions")
+	lon := srv.HGet("omlox:position:"+forklift.String(), "lon")
+	if len(members) != 0 || lon != "" {
+		t.Errorf("expected the local position to leave the GEO set")
+	}
+}
+
+func TestMirrorPrune(t *testing.T) {
+	srv, c := newClient(t)
+	ctx := context.Background()
+
+	m := NewMirror(c, WithTTL(time.Millisecond))
+	wgs84 := func(provider string) omlox.Location {
+		return omlox.Location{Position: *omlox.NewPoint(geometry.Point{X: 8.5, Y: 47.25}), Crs: omlox.CrsWGS84, ProviderID: provider}
+	}
+	if err := m.Update(ctx, wgs84("expired"), wgs84("kept")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the server only expires keys when fast forwarded
+	time.Sleep(5 * time.Millisecond)
+	srv.Del("omlox:position:expired")
+
+	if err := m.Prune(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if members, _ := srv.ZMembers("omlox:positions"); !cmp.Equal(members, []string{"kept"}) {
+		t.Errorf("expected the expired position to be pruned, got %v", members)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+// png draws the shapes as a PNG image, without labels.
+func (r *renderer) png(w io.Writer) error {
+	img := image.NewRGBA(image.Rect(0, 0, r.width, r.height))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	for _, s := range r.shapes {
+		style := styles[s.kind]
+
+		var rings [][]geometry.Point
+		switch {
+		case s.kind == markerShape:
+			rings = [][]geometry.Point{circle(r.pixel(s.center), 5)}
+		case s.rings != nil:
+			for _, ring := range s.rings {
+				px := make([]geometry.Point, len(ring))
+				for i, p := range ring {
+					px[i] = r.pixel(p)
+				}
+				rings = append(rings, px)
+			}
+		default:
+			rings = [][]geometry.Point{circle(r.pixel(s.center), s.radius*r.scale)}
+		}
+
+		fill(img, rings, style.fill)
+		for _, ring := range rings {
+			for i, a := range ring {
+				line(img, a, ring[(i+1)%len(ring)], style.stroke)
+			}
+		}
+	}
+
+	// scale bar in the bottom-left corner
+	y := float64(r.height - margin/2)
+	for dy := 0.0; dy < 2; dy++ {
+		line(img, geometry.Point{X: margin, Y: y + dy}, geometry.Point{X: margin + r.scaleBar()*r.scale, Y: y + dy}, color.NRGBA{A: 255})
+	}
+
+	return png.Encode(w, img)
+}
+
+// circle returns the ring of a circle of pixel coordinates.
+func circle(center geometry.Point, radius float64) []geometry.Point {
+	ring := make([]geometry.Point, circleSegments)
+	for i := range ring {
+		a := 2 * math.Pi * float64(i) / circleSegments
+		ring[i] = geometry.Point{X: center.X + radius*math.Cos(a), Y: center.Y + radius*math.Sin(a)}
+	}
+	return ring
+}
+
+// fill fills the rings of a polygon by the even-odd rule, sampling the pixels at their
+// centers.
+func fill(img *image.RGBA, rings [][]geometry.Point, c color.NRGBA) {
+	src := image.NewUniform(c)
+	bounds := img.Bounds()
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		cy := float64(y) + 0.5
+
+		var xs []float64
+		for _, ring := range rings {
+			for i, a := range ring {
+				b := ring[(i+1)%len(ring)]
+				if (a.Y > cy) != (b.Y > cy) {
+					xs = append(xs, a.X+(cy-a.Y)*(b.X-a.X)/(b.Y-a.Y))
+				}
+			}
+		}
+		sort.Float64s(xs)
+
+		for i := 0; i+1 < len(xs); i += 2 {
+			x0 := max(int(math.Ceil(xs[i]-0.5)), bounds.Min.X)
+			x1 := min(int(math.Ceil(xs[i+1]-0.5)), bounds.Max.X)
+			if x0 < x1 {
+				draw.Draw(img, image.Rect(x0, y, x1, y+1), src, image.Point{}, draw.Over)
+			}
+		}
+	}
+}
+
+// line draws a line of one pixel between two points.
+func line(img *image.RGBA, a, b geometry.Point, c color.NRGBA) {
+	src := image.NewUniform(c)
+
+	steps := int(math.Ceil(math.Max(math.Abs(b.X-a.X), math.Abs(b.Y-a.Y))))
+	for i := 0; i <= steps; i++ {
+		t := 0.0
+		if steps > 0 {
+			t = float64(i) / float64(steps)
+		}
+		x := int(math.Floor(a.X + (b.X-a.X)*t))
+		y := int(math.Floor(a.Y + (b.Y-a.Y)*t))
+		if (image.Point{X: x, Y: y}).In(img.Bounds()) {
+			draw.Draw(img, image.Rect(x, y, x+1, y+1), src, image.Point{}, draw.Over)
+		}
+	}
+}
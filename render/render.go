@@ -0,0 +1,342 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package render draws static maps of the zones, fences and trackable positions of a
+// hub as SVG or PNG images, without external tile services, for reports and offline
+// documentation.
+//
+// Maps are drawn in the local coordinates of a zone when all the fences and positions
+// are local to it, and otherwise on the plane tangent to the WGS84 coordinates, local
+// coordinates being converted with the georeference of their zone. North, or the y axis
+// of the zone, is up. Geometry of unknown coordinates, such as the local positions of a
+// zone without ground control points on a WGS84 map, is left out.
+package render
+
+import (
+	"errors"
+	"image/color"
+	"io"
+	"math"
+
+	"github.com/tidwall/geojson"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/internal/geo"
+)
+
+// ErrEmpty is returned when there is no geometry of known coordinates to draw.
+var ErrEmpty = errors.New("nothing to render: no zones, fences or positions of known coordinates")
+
+// circleSegments is the number of segments of the circles drawn on PNG images.
+const circleSegments = 64
+
+// Marker is a position drawn on a map, such as the location of a trackable.
+type Marker struct {
+	// Label of the marker, such as the name of the trackable.
+	Label string
+
+	// Location of the marker.
+	Location omlox.Location
+}
+
+// Map is the state of a hub to draw.
+type Map struct {
+	// Zones are drawn as the circles of their position and radius.
+	Zones []omlox.Zone
+
+	// Fences are drawn as their polygons, or as the circles of their point and radius.
+	Fences []omlox.Fence
+
+	// Markers are drawn as dots, over the zones and fences.
+	Markers []Marker
+}
+
+// Option is a configuration option of the rendering of maps.
+type Option func(*renderer)
+
+// WithWidth sets the width of the image in pixels. The height follows the aspect of the
+// map.
+// Default: 1024
+func WithWidth(pixels int) Option {
+	return func(r *renderer) {
+		r.width = pixels
+	}
+}
+
+// WithLabels sets whether the zones, fences and markers are labeled with their names.
+// Labels are only drawn on SVG images.
+// Default: true
+func WithLabels(labels bool) Option {
+	return func(r *renderer) {
+		r.labels = labels
+	}
+}
+
+// SVG draws the map as an SVG image to w.
+func (m *Map) SVG(w io.Writer, opts ...Option) error {
+	r, err := m.renderer(opts)
+	if err != nil {
+		return err
+	}
+	return r.svg(w)
+}
+
+// PNG draws the map as a PNG image to w.
+func (m *Map) PNG(w io.Writer, opts ...Option) error {
+	r, err := m.renderer(opts)
+	if err != nil {
+		return err
+	}
+	return r.png(w)
+}
+
+// kind is the kind of a shape.
+type kind int
+
+const (
+	zoneShape kind = iota
+	fenceShape
+	markerShape
+)
+
+// styles are the fill and stroke colors of the kinds of shapes.
+var styles = [...]struct{ fill, stroke color.NRGBA }{
+	zoneShape:   {fill: color.NRGBA{R: 128, G: 128, B: 128, A: 32}, stroke: color.NRGBA{R: 96, G: 96, B: 96, A: 255}},
+	fenceShape:  {fill: color.NRGBA{R: 31, G: 119, B: 180, A: 64}, stroke: color.NRGBA{R: 31, G: 119, B: 180, A: 255}},
+	markerShape: {fill: color.NRGBA{R: 214, G: 39, B: 40, A: 255}, stroke: color.NRGBA{R: 255, G: 255, B: 255, A: 255}},
+}
+
+// shape is a zone, fence or marker in the coordinates of the map, in meters.
+type shape struct {
+	kind  kind
+	label string
+
+	// rings of polygons, the first one being the exterior
+	rings [][]geometry.Point
+
+	// center and radius of circles, and position of markers
+	center geometry.Point
+	radius float64
+}
+
+// bounds returns the minimum and maximum coordinates of the shape.
+func (s shape) bounds() (geometry.Point, geometry.Point) {
+	if s.rings == nil {
+		return geometry.Point{X: s.center.X - s.radius, Y: s.center.Y - s.radius},
+			geometry.Point{X: s.center.X + s.radius, Y: s.center.Y + s.radius}
+	}
+
+	lo, hi := geometry.Point{X: math.Inf(1), Y: math.Inf(1)}, geometry.Point{X: math.Inf(-1), Y: math.Inf(-1)}
+	for _, p := range s.rings[0] {
+		lo.X, lo.Y = math.Min(lo.X, p.X), math.Min(lo.Y, p.Y)
+		hi.X, hi.Y = math.Max(hi.X, p.X), math.Max(hi.Y, p.Y)
+	}
+	return lo, hi
+}
+
+// frame places the coordinates of the zones, fences and markers on the map.
+type frame struct {
+	// zone of the local coordinates of the map, empty for the tangent plane of WGS84
+	zone string
+
+	// origin of the tangent plane, set by the first WGS84 point
+	origin *geometry.Point
+
+	// georeferences of the zones, by id and foreign id
+	georefs map[string]*omlox.Georeference
+}
+
+// newFrame returns the frame of the map: the local coordinates of a zone if all the
+// fences and markers are local to it, or else the tangent plane of WGS84.
+func (m *Map) newFrame() *frame {
+	f := &frame{georefs: make(map[string]*omlox.Georeference)}
+	for _, z := range m.Zones {
+		if g, err := z.Georeference(); err == nil {
+			f.georefs[z.ID.String()] = g
+			if z.ForeignID != "" {
+				f.georefs[z.ForeignID] = g
+			}
+		}
+	}
+
+	var zones []string
+	for _, fence := range m.Fences {
+		if fence.Crs != omlox.CrsLocal {
+			return f
+		}
+		zones = append(zones, fence.ZoneID)
+	}
+	for _, mk := range m.Markers {
+		if mk.Location.Crs != "" && mk.Location.Crs != omlox.CrsLocal {
+			return f
+		}
+		zones = append(zones, mk.Location.Source)
+	}
+
+	for _, z := range zones {
+		if z != zones[0] {
+			return f
+		}
+	}
+	if len(zones) > 0 {
+		f.zone = zones[0]
+	}
+	return f
+}
+
+// place returns the coordinates on the map of a point, in WGS84 or local to a zone. It
+// reports whether the coordinates are known.
+func (f *frame) place(p geometry.Point, wgs84 bool, zone string) (geometry.Point, bool) {
+	if f.zone != "" {
+		switch {
+		case !wgs84 && zone == f.zone:
+			return p, true
+		case wgs84 && f.georefs[f.zone] != nil:
+			return f.georefs[f.zone].ToLocal(p), true
+		}
+		return geometry.Point{}, false
+	}
+
+	if !wgs84 {
+		g := f.georefs[zone]
+		if g == nil {
+			return geometry.Point{}, false
+		}
+		p = g.ToWGS84(p)
+	}
+
+	if f.origin == nil {
+		f.origin = &p
+	}
+	return geo.Project(*f.origin, p), true
+}
+
+// shapes returns the shapes of the map, in drawing order.
+func (m *Map) shapes() []shape {
+	f := m.newFrame()
+
+	var shapes []shape
+	for _, z := range m.Zones {
+		if z.Position == nil || z.Radius <= 0 {
+			continue
+		}
+		if c, ok := f.place(z.Position.Base(), true, ""); ok {
+			shapes = append(shapes, shape{kind: zoneShape, label: z.Name, center: c, radius: z.Radius})
+		}
+	}
+
+	for _, fence := range m.Fences {
+		if fence.Region == nil {
+			continue
+		}
+		wgs84 := fence.Crs != omlox.CrsLocal
+
+		switch region := fence.Region.Object.(type) {
+		case *geojson.Point:
+			if c, ok := f.place(region.Base(), wgs84, fence.ZoneID); ok && fence.Radius > 0 {
+				shapes = append(shapes, shape{kind: fenceShape, label: fence.Name, center: c, radius: fence.Radius})
+			}
+		case *geojson.Polygon:
+			poly := region.Base()
+			s := shape{kind: fenceShape, label: fence.Name}
+			for _, ring := range append([]geometry.Ring{poly.Exterior}, poly.Holes...) {
+				pts := make([]geometry.Point, 0, ring.NumPoints())
+				for i := 0; i < ring.NumPoints(); i++ {
+					if p, ok := f.place(ring.PointAt(i), wgs84, fence.ZoneID); ok {
+						pts = append(pts, p)
+					}
+				}
+				if len(pts) != ring.NumPoints() {
+					break
+				}
+				s.rings = append(s.rings, pts)
+			}
+			if len(s.rings) == 1+len(poly.Holes) && len(s.rings[0]) >= 3 {
+				shapes = append(shapes, s)
+			}
+		}
+	}
+
+	for _, mk := range m.Markers {
+		wgs84 := mk.Location.Crs != "" && mk.Location.Crs != omlox.CrsLocal
+		if c, ok := f.place(mk.Location.Position.Base(), wgs84, mk.Location.Source); ok {
+			shapes = append(shapes, shape{kind: markerShape, label: mk.Label, center: c})
+		}
+	}
+
+	return shapes
+}
+
+// renderer draws shapes on an image.
+type renderer struct {
+	width  int
+	labels bool
+
+	shapes []shape
+
+	// pixels per meter, and the bounds of the map in meters
+	scale  float64
+	lo, hi geometry.Point
+	height int
+}
+
+// margin is the margin around the map in pixels.
+const margin = 16
+
+// renderer returns the renderer of the map.
+func (m *Map) renderer(opts []Option) (*renderer, error) {
+	r := &renderer{width: 1024, labels: true}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.width <= 2*margin {
+		return nil, errors.New("image width must be larger than its margins")
+	}
+
+	r.shapes = m.shapes()
+	if len(r.shapes) == 0 {
+		return nil, ErrEmpty
+	}
+
+	r.lo, r.hi = r.shapes[0].bounds()
+	for _, s := range r.shapes[1:] {
+		lo, hi := s.bounds()
+		r.lo.X, r.lo.Y = math.Min(r.lo.X, lo.X), math.Min(r.lo.Y, lo.Y)
+		r.hi.X, r.hi.Y = math.Max(r.hi.X, hi.X), math.Max(r.hi.Y, hi.Y)
+	}
+
+	// flat maps, such as of a single marker, are widened to a square of at least 10m
+	size := math.Max(math.Max(r.hi.X-r.lo.X, r.hi.Y-r.lo.Y), 10)
+	if r.hi.X-r.lo.X < size/100 {
+		r.lo.X, r.hi.X = r.lo.X-size/2, r.hi.X+size/2
+	}
+	if r.hi.Y-r.lo.Y < size/100 {
+		r.lo.Y, r.hi.Y = r.lo.Y-size/2, r.hi.Y+size/2
+	}
+
+	r.scale = float64(r.width-2*margin) / (r.hi.X - r.lo.X)
+	r.height = int(math.Ceil((r.hi.Y-r.lo.Y)*r.scale)) + 2*margin
+
+	return r, nil
+}
+
+// pixel returns the pixel coordinates of a point of the map.
+func (r *renderer) pixel(p geometry.Point) geometry.Point {
+	return geometry.Point{
+		X: margin + (p.X-r.lo.X)*r.scale,
+		Y: margin + (r.hi.Y-p.Y)*r.scale,
+	}
+}
+
+// scaleBar returns the length in meters of the scale bar of the map, a round length of
+// at most a fifth of the width.
+func (r *renderer) scaleBar() float64 {
+	limit := float64(r.width-2*margin) / r.scale / 5
+	unit := math.Pow(10, math.Floor(math.Log10(limit)))
+	for _, n := range []float64{5, 2, 1} {
+		if n*unit <= limit {
+			return n * unit
+		}
+	}
+	return unit
+}
@@ -0,0 +1,119 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package render
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+// hall is a map of a forklift next to a 10m dock fence, in the local coordinates of the
+// hall.
+var hall = Map{
+	Fences: []omlox.Fence{
+		{Name: "Dock", Region: omlox.NewRegionPoint(geometry.Point{X: 20, Y: 10}), Radius: 10, Crs: omlox.CrsLocal, ZoneID: "hall"},
+	},
+	Markers: []Marker{
+		{Label: "Forklift <1>", Location: omlox.Location{Position: *omlox.NewPoint(geometry.Point{X: 35, Y: 10}), Source: "hall"}},
+	},
+}
+
+func TestSVG(t *testing.T) {
+	var b bytes.Buffer
+	if err := hall.SVG(&b, WithWidth(432)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	svg := b.String()
+
+	// the map spans 10 to 35m, at 16 pixels per meter
+	for _, want := range []string{
+		`width="432" height="352"`,
+		`<circle cx="176.0" cy="176.0" r="160.0"`,
+		`<circle cx="416.0" cy="176.0" r="5"`,
+		`>Dock</text>`,
+		`>Forklift &lt;1&gt;</text>`,
+		`>5 m</text>`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("expected %s in the svg:\n%s", want, svg)
+		}
+	}
+
+	b.Reset()
+	if err := hall.SVG(&b, WithLabels(false)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(b.String(), "Dock") {
+		t.Error("expected no labels")
+	}
+}
+
+func TestPNG(t *testing.T) {
+	var b bytes.Buffer
+	if err := hall.PNG(&b, WithWidth(432)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	img, err := png.Decode(&b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if size := img.Bounds().Size(); size.X != 432 || size.Y != 352 {
+		t.Errorf("expected 432x352 image, got %v", size)
+	}
+
+	// the marker is red, within the fence tinted in blue, on a white background
+	if r, g, _, _ := img.At(416, 176).RGBA(); r>>8 != 214 || g>>8 != 39 {
+		t.Errorf("expected the marker at its position, got %v", img.At(416, 176))
+	}
+	if r, _, b, _ := img.At(176, 176).RGBA(); r >= b {
+		t.Errorf("expected the fence at its position, got %v", img.At(176, 176))
+	}
+	if r, g, b, _ := img.At(2, 2).RGBA(); r != 0xffff || g != 0xffff || b != 0xffff {
+		t.Errorf("expected a white background, got %v", img.At(2, 2))
+	}
+}
+
+func TestGeoreferenced(t *testing.T) {
+	// the hall is 1km east of the WGS84 marker
+	m := hall
+	m.Zones = []omlox.Zone{{
+		Name:      "Hall",
+		ForeignID: "hall",
+		Position:  omlox.NewPoint(geometry.Point{X: 7.8157, Y: 48.1302}),
+		Radius:    50,
+		GroundControlPoints: []omlox.GroundControlPoint{
+			{Local: *omlox.NewPoint(geometry.Point{X: 0, Y: 0}), WGS84: *omlox.NewPoint(geometry.Point{X: 7.8157, Y: 48.1302})},
+			{Local: *omlox.NewPoint(geometry.Point{X: 100, Y: 0}), WGS84: *omlox.NewPoint(geometry.Point{X: 7.8170459, Y: 48.1302})},
+		},
+	}}
+	m.Markers = append(m.Markers, Marker{Label: "Truck", Location: omlox.Location{Position: *omlox.NewPoint(geometry.Point{X: 7.80224, Y: 48.1302}), Crs: omlox.CrsWGS84}})
+
+	r, err := m.renderer(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(r.shapes) != 4 {
+		t.Fatalf("expected 4 shapes, got %d", len(r.shapes))
+	}
+	if w := r.hi.X - r.lo.X; w < 1049 || w > 1051 {
+		t.Errorf("expected the map to span 1050m, got %v", w)
+	}
+
+	// without the georeference of the hall, only the truck is drawn
+	m.Zones = nil
+	if r, err = m.renderer(nil); err != nil || len(r.shapes) != 1 {
+		t.Errorf("expected the truck only, got %v (%v)", r, err)
+	}
+
+	if err := (&Map{}).SVG(&bytes.Buffer{}); !errors.Is(err, ErrEmpty) {
+		t.Errorf("expected ErrEmpty, got %v", err)
+	}
+}
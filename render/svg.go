@@ -0,0 +1,97 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package render
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+)
+
+// svg draws the shapes as an SVG image.
+func (r *renderer) svg(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n",
+		r.width, r.height, r.width, r.height)
+	fmt.Fprintf(bw, `<rect width="%d" height="%d" fill="white"/>`+"\n", r.width, r.height)
+
+	for _, s := range r.shapes {
+		style := styles[s.kind]
+		c := r.pixel(s.center)
+
+		switch {
+		case s.kind == markerShape:
+			fmt.Fprintf(bw, `<circle cx="%.1f" cy="%.1f" r="5" %s/>`+"\n", c.X, c.Y, paint(style.fill, style.stroke))
+		case s.rings != nil:
+			var d strings.Builder
+			for _, ring := range s.rings {
+				for i, p := range ring {
+					p = r.pixel(p)
+					if i == 0 {
+						fmt.Fprintf(&d, "M%.1f %.1f", p.X, p.Y)
+					} else {
+						fmt.Fprintf(&d, "L%.1f %.1f", p.X, p.Y)
+					}
+				}
+				d.WriteString("Z")
+			}
+			fmt.Fprintf(bw, `<path d="%s" fill-rule="evenodd" %s/>`+"\n", d.String(), paint(style.fill, style.stroke))
+		default:
+			fmt.Fprintf(bw, `<circle cx="%.1f" cy="%.1f" r="%.1f" %s/>`+"\n", c.X, c.Y, s.radius*r.scale, paint(style.fill, style.stroke))
+		}
+	}
+
+	if r.labels {
+		for _, s := range r.shapes {
+			if s.label == "" {
+				continue
+			}
+
+			// markers are labeled on their right, and areas at their center
+			var x, y float64
+			anchor := "middle"
+			if s.kind == markerShape {
+				p := r.pixel(s.center)
+				x, y, anchor = p.X+8, p.Y+4, "start"
+			} else {
+				lo, hi := s.bounds()
+				a, b := r.pixel(lo), r.pixel(hi)
+				x, y = (a.X+b.X)/2, (a.Y+b.Y)/2+4
+			}
+
+			// markers are labeled in their fill color, and areas in their stroke color
+			textColor := styles[s.kind].stroke
+			if s.kind == markerShape {
+				textColor = styles[s.kind].fill
+			}
+
+			fmt.Fprintf(bw, `<text x="%.1f" y="%.1f" text-anchor="%s" fill="%s">`, x, y, anchor, hex(textColor))
+			xml.EscapeText(bw, []byte(s.label))
+			bw.WriteString("</text>\n")
+		}
+	}
+
+	// scale bar in the bottom-left corner
+	length := r.scaleBar()
+	y := float64(r.height - margin/2)
+	fmt.Fprintf(bw, `<path d="M%d %.1fh%.1f" stroke="black" stroke-width="2"/>`+"\n", margin, y, length*r.scale)
+	fmt.Fprintf(bw, `<text x="%.1f" y="%.1f" fill="black">%g m</text>`+"\n", float64(margin)+length*r.scale+6, y+4, length)
+
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+// paint returns the fill and stroke attributes of colors.
+func paint(fill, stroke color.NRGBA) string {
+	return fmt.Sprintf(`fill="%s" fill-opacity="%.2f" stroke="%s" stroke-width="1.5"`, hex(fill), float64(fill.A)/255, hex(stroke))
+}
+
+// hex returns the hexadecimal notation of a color, without its alpha.
+func hex(c color.NRGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
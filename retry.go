@@ -0,0 +1,302 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures the retries of the API requests failing with transient errors,
+// such as rate limiting (429) or an unavailable Hub behind a gateway (502, 503, 504).
+//
+// Each attempt of a request gets the request timeout of the client, within the deadline
+// of the caller, as given by a Budget. Requests with non-idempotent methods, POST and
+// PATCH, are not retried unless opted in, as the Hub may have processed them before
+// failing.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts of a request, including the first.
+	// Requests are not retried with less than 2 attempts.
+	MaxAttempts int
+
+	// MinWait is the wait before the first retry, doubled after each retry.
+	MinWait time.Duration
+
+	// MaxWait is the maximum wait before a retry.
+	MaxWait time.Duration
+
+	// Jitter randomizes the waits between 0 and their value, so clients failing together
+	// do not retry together.
+	Jitter bool
+
+	// Statuses are the HTTP status codes retried.
+	Statuses []int
+
+	// RetryNonIdempotent retries the requests with non-idempotent methods too.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns a retry policy of 3 attempts, waiting from 100ms up to 5s
+// with jitter, of the requests failing with a 429, 502, 503 or 504 status or a network error.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		MinWait:     100 * time.Millisecond,
+		MaxWait:     5 * time.Second,
+		Jitter:      true,
+		Statuses: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// validate checks the bounds of the policy.
+func (p RetryPolicy) validate() error {
+	if p.MaxAttempts < 0 {
+		return fmt.Errorf("retry attempts must not be negative")
+	}
+	if p.MinWait < 0 || p.MaxWait < p.MinWait {
+		return fmt.Errorf("invalid retry waits: min %v, max %v", p.MinWait, p.MaxWait)
+	}
+	return nil
+}
+
+// retryKey is the context key of the retry policy of a request.
+type retryKey struct{}
+
+// ContextWithRetry returns a context overriding the retry policy of the client for the
+// requests made with it. A policy of a single attempt disables the retries.
+func ContextWithRetry(ctx context.Context, policy RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryKey{}, policy)
+}
+
+// retryPolicy returns the retry policy of a request, nil if it is not retried.
+func (c *Client) retryPolicy(ctx context.Context, method string) *RetryPolicy {
+	policy := c.configuration.Retry
+	if p, ok := ctx.Value(retryKey{}).(RetryPolicy); ok {
+		policy = &p
+	}
+
+	if policy == nil || policy.MaxAttempts < 2 {
+		return nil
+	}
+	if !policy.RetryNonIdempotent && !idempotent(method) {
+		return nil
+	}
+	return policy
+}
+
+// idempotent reports whether requests of a method can be sent several times with the
+// same effect.
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryable reports whether the outcome of an attempt is a transient failure, and the
+// wait requested by the Hub with Retry-After, if any. Responses are set for the errors of
+// the Hub responses.
+func (p *RetryPolicy) retryable(ctx context.Context, resp *http.Response, err error, now time.Time) (bool, time.Duration) {
+	switch {
+	case err == nil:
+		return false, 0
+	case resp == nil:
+		// network errors, and attempts running out of their share of the deadline
+		return ctx.Err() == nil && transient(err), 0
+	case !slices.Contains(p.Statuses, resp.StatusCode):
+		return false, 0
+	default:
+		return true, retryAfter(resp.Header.Get("Retry-After"), now)
+	}
+}
+
+// transient reports whether the error of a request sent without response is a network
+// error or a timeout. Errors building the request, such as those of the token source,
+// fail again when retried.
+func transient(err error) bool {
+	var uerr *url.Error
+	if !errors.As(err, &uerr) {
+		return false
+	}
+
+	var nerr net.Error
+	return errors.As(uerr.Err, &nerr) || errors.Is(uerr.Err, io.EOF) || errors.Is(uerr.Err, io.ErrUnexpectedEOF)
+}
+
+// wait returns the wait before the retry following the given attempt, starting at 1.
+func (p *RetryPolicy) wait(attempt int, after time.Duration) time.Duration {
+	d := p.MinWait * (1 << uint(attempt-1))
+	if d > p.MaxWait || d < 0 {
+		d = p.MaxWait
+	}
+	if p.Jitter && d > 0 {
+		d = time.Duration(rand.Int63n(int64(d)))
+	}
+	return max(d, after)
+}
+
+// retryAfter parses the Retry-After header, either a number of seconds or an HTTP date.
+func retryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(at.Sub(now), 0)
+	}
+	return 0
+}
+
+// do sends a request, retried according to the retry policy, and returns its response,
+// or the error of the response of its last attempt. Requests rejected as unauthorized are
+// sent once more with a refreshed token. The body of the response must be closed.
+func (c *Client) do(
+	ctx context.Context,
+	method string,
+	path string,
+	body io.Reader,
+	parameters url.Values,
+	headers http.Header,
+) (*http.Response, error) {
+	policy := c.retryPolicy(ctx, method)
+	attempts := 1
+	if policy != nil {
+		attempts = policy.MaxAttempts
+	}
+
+	// each attempt gets the request timeout, within the deadline of the caller
+	budget := NewBudget(ctx, attempts, 1)
+	budget.SetClock(c.timeSource())
+	budget.SetRequestTimeout(c.configuration.RequestTimeout)
+
+	if policy == nil && c.tokens == nil {
+		actx, cancel := budget.Next(ctx)
+		resp, err := c.attempt(actx, method, path, body, parameters, headers)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		resp.Body = cancelOnClose{resp.Body, cancel}
+		return resp, nil
+	}
+
+	// the body is sent again by the retries, and with a refreshed token
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = io.ReadAll(body); err != nil {
+			return nil, fmt.Errorf("could not read request body: %w", err)
+		}
+	}
+
+	refreshed := false
+	for attempt := 1; ; attempt++ {
+		actx, cancel := budget.Next(ctx)
+		resp, err := c.attempt(actx, method, path, bytes.NewReader(payload), parameters, headers)
+		if err == nil {
+			// the response body outlives the attempt
+			resp.Body = cancelOnClose{resp.Body, cancel}
+			return resp, nil
+		}
+		cancel()
+
+		// tokens rejected before their expiry are refreshed once, without counting
+		// as an attempt
+		if !refreshed && resp != nil && resp.StatusCode == http.StatusUnauthorized &&
+			resp.Request != nil && c.refreshAuthorization(headers, resp.Request.Header) {
+			refreshed = true
+			attempt--
+			continue
+		}
+
+		if policy == nil || attempt >= policy.MaxAttempts {
+			return nil, err
+		}
+		retry, after := policy.retryable(ctx, resp, err, c.timeSource().Now())
+		if !retry {
+			return nil, err
+		}
+
+		wait := policy.wait(attempt, after)
+		if deadline, ok := ctx.Deadline(); ok && c.timeSource().Now().Add(wait).After(deadline) {
+			// the Hub asks to retry too late
+			return nil, err
+		}
+
+		slog.LogAttrs(ctx, slog.LevelDebug, "retrying request",
+			slog.String("method", method),
+			slog.String("path", path),
+			slog.Int("attempt", attempt),
+			slog.Duration("wait", wait),
+			slog.Any("err", err),
+		)
+
+		t := c.timeSource().NewTimer(wait)
+		select {
+		case <-t.C():
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// attempt sends a request once, and returns its response, or its error. The response is
+// also returned, with its body closed, for the errors of the Hub responses.
+func (c *Client) attempt(
+	ctx context.Context,
+	method string,
+	path string,
+	body io.Reader,
+	parameters url.Values,
+	headers http.Header,
+) (*http.Response, error) {
+	req, err := c.newRequest(ctx, method, path, body, parameters, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := isResponseError(resp); err != nil {
+		resp.Body.Close()
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// cancelOnClose is a response body canceling the context of its request once closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelOnClose) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,181 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	var (
+		requests atomic.Int32
+		failures atomic.Int32
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if body, _ := io.ReadAll(r.Body); r.Method == http.MethodPost && string(body) != "{\"name\":\"dock\"}\n" {
+			t.Errorf("unexpected body of attempt %d: %q", requests.Load(), body)
+		}
+
+		if failures.Add(-1) >= 0 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.MinWait, policy.MaxWait = time.Millisecond, 10*time.Millisecond
+
+	c, err := New(srv.URL, WithRetry(policy))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	body := map[string]string{"name": "dock"}
+
+	testCases := []struct {
+		name     string
+		ctx      context.Context
+		method   string
+		failures int32
+		requests int32
+		status   int
+	}{
+		{name: "transient failures", ctx: ctx, method: http.MethodGet, failures: 2, requests: 3},
+		{name: "attempts exhausted", ctx: ctx, method: http.MethodGet, failures: 3, requests: 3, status: http.StatusServiceUnavailable},
+		{name: "non-idempotent", ctx: ctx, method: http.MethodPost, failures: 1, requests: 1, status: http.StatusServiceUnavailable},
+		{name: "non-idempotent opted in", ctx: ContextWithRetry(ctx, RetryPolicy{MaxAttempts: 2, Statuses: []int{http.StatusServiceUnavailable}, RetryNonIdempotent: true}), method: http.MethodPost, failures: 1, requests: 2},
+		{name: "disabled per request", ctx: ContextWithRetry(ctx, RetryPolicy{MaxAttempts: 1}), method: http.MethodGet, failures: 1, requests: 1, status: http.StatusServiceUnavailable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests.Store(0)
+			failures.Store(tc.failures)
+
+			var b any
+			if tc.method == http.MethodPost {
+				b = body
+			}
+			err := c.Do(tc.ctx, tc.method, "/trackables", b, nil)
+
+			var oerr *Error
+			switch {
+			case tc.status == 0 && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tc.status != 0 && (!errors.As(err, &oerr) || oerr.Code != tc.status):
+				t.Fatalf("expected a %d error, got %v", tc.status, err)
+			}
+			if n := requests.Load(); n != tc.requests {
+				t.Errorf("expected %d requests, got %d", tc.requests, n)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		header string
+		want   time.Duration
+	}{
+		{header: "", want: 0},
+		{header: "3", want: 3 * time.Second},
+		{header: "-1", want: 0},
+		{header: "Fri, 01 Mar 2024 08:00:30 GMT", want: 30 * time.Second},
+		{header: "Fri, 01 Mar 2024 07:00:00 GMT", want: 0},
+		{header: "soon", want: 0},
+	}
+
+	for _, tc := range testCases {
+		if got := retryAfter(tc.header, now); got != tc.want {
+			t.Errorf("expected %v for %q, got %v", tc.want, tc.header, got)
+		}
+	}
+
+	// the Hub wait takes precedence over shorter backoffs
+	policy := RetryPolicy{MinWait: time.Second, MaxWait: 4 * time.Second}
+	if got := policy.wait(5, 0); got != 4*time.Second {
+		t.Errorf("expected the maximum wait, got %v", got)
+	}
+	if got := policy.wait(1, 3*time.Second); got != 3*time.Second {
+		t.Errorf("expected the Retry-After wait, got %v", got)
+	}
+}
+
+func TestRetryTransient(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	policy.MinWait, policy.MaxWait = time.Millisecond, time.Millisecond
+
+	// the errors of the token source are not retried
+	var tokens atomic.Int32
+	c, err := New("http://127.0.0.1:1", WithRetry(policy), WithTokenSource(TokenSourceFunc(func(context.Context) (*Token, error) {
+		tokens.Add(1)
+		return nil, errors.New("invalid client secret")
+	})))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Do(context.Background(), http.MethodGet, "/trackables", nil, nil); err == nil {
+		t.Fatal("expected an error")
+	}
+	if n := tokens.Load(); n != 1 {
+		t.Errorf("expected a single attempt, got %d", n)
+	}
+
+	// connections refused are
+	srv := httptest.NewServer(http.NotFoundHandler())
+	addr := srv.URL
+	srv.Close()
+
+	var dials atomic.Int32
+	transport := &http.Transport{DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+		dials.Add(1)
+		return (&net.Dialer{}).DialContext(ctx, network, address)
+	}}
+	c, err = New(addr, WithRetry(policy), WithHTTPClient(&http.Client{Transport: transport}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Do(context.Background(), http.MethodGet, "/trackables", nil, nil); err == nil {
+		t.Fatal("expected an error")
+	}
+	if n := dials.Load(); n != int32(policy.MaxAttempts) {
+		t.Errorf("expected %d attempts, got %d", policy.MaxAttempts, n)
+	}
+}
+
+func TestRetrySlowAttempt(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		time.Sleep(150 * time.Millisecond)
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	// a slow attempt fitting the request timeout is not cut short for the retries
+	c, err := New(srv.URL, WithRetry(DefaultRetryPolicy()), WithRequestTimeout(300*time.Millisecond))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Do(context.Background(), http.MethodGet, "/trackables", nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("expected a single request, got %d", n)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package route
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+// RouteDeviation is emitted when a trackable leaves the corridor of its route,
+// and again when it returns to it.
+type RouteDeviation struct {
+	// RouteID is the id of the route the trackable is assigned to.
+	RouteID string `json:"route_id"`
+
+	// TrackableID is the trackable deviating from the route.
+	TrackableID uuid.UUID `json:"trackable_id"`
+
+	// Location is the location update which triggered the event.
+	Location omlox.Location `json:"location"`
+
+	// Distance is the distance in meters from the location to the route path.
+	Distance float64 `json:"distance"`
+
+	// Progress is the distance in meters along the route of the closest point of the path.
+	Progress float64 `json:"progress"`
+
+	// Returned is set when the trackable returns to the corridor after a deviation.
+	Returned bool `json:"returned,omitempty"`
+
+	// At is the time of the location update.
+	At time.Time `json:"at"`
+}
+
+// Monitor detects trackables deviating from their assigned routes.
+//
+// Location updates must be fed through Observe. A deviation is reported once when a
+// trackable leaves the corridor of its route, and once more when it returns to it.
+type Monitor struct {
+	mu sync.Mutex
+
+	routes      map[string]Route
+	assignments map[uuid.UUID]string
+	deviating   map[uuid.UUID]bool
+
+	clock clock.Clock
+}
+
+// NewMonitor returns a new route monitor for the given routes.
+func NewMonitor(routes ...Route) (*Monitor, error) {
+	m := &Monitor{
+		routes:      make(map[string]Route, len(routes)),
+		assignments: make(map[uuid.UUID]string),
+		deviating:   make(map[uuid.UUID]bool),
+		clock:       clock.System,
+	}
+
+	for _, r := range routes {
+		if err := r.Validate(); err != nil {
+			return nil, err
+		}
+		if _, ok := m.routes[r.ID]; ok {
+			return nil, fmt.Errorf("duplicated route '%s'", r.ID)
+		}
+		m.routes[r.ID] = r
+	}
+
+	return m, nil
+}
+
+// SetClock sets the source of time of the locations without timestamp.
+func (m *Monitor) SetClock(clk clock.Clock) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.clock = clock.Or(clk)
+}
+
+// Routes returns the routes of the monitor sorted by id.
+func (m *Monitor) Routes() []Route {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	routes := make([]Route, 0, len(m.routes))
+	for _, r := range m.routes {
+		routes = append(routes, r)
+	}
+
+	sort.Slice(routes, func(i, j int) bool { return routes[i].ID < routes[j].ID })
+
+	return routes
+}
+
+// Assign assigns a trackable to a route, replacing any previous assignment.
+func (m *Monitor) Assign(trackableID uuid.UUID, routeID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.routes[routeID]; !ok {
+		return fmt.Errorf("route '%s' not found", routeID)
+	}
+
+	m.assignments[trackableID] = routeID
+	delete(m.deviating, trackableID)
+
+	return nil
+}
+
+// Unassign removes the route assignment of a trackable.
+func (m *Monitor) Unassign(trackableID uuid.UUID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.assignments, trackableID)
+	delete(m.deviating, trackableID)
+}
+
+// Deviating reports whether a trackable is currently outside the corridor of its route.
+func (m *Monitor) Deviating(trackableID uuid.UUID) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.deviating[trackableID]
+}
+
+// Observe evaluates a location update against the routes of its trackables, and returns
+// the deviations found. Locations in a different crs than the route are ignored.
+// Locations without timestamp are evaluated at the current time.
+func (m *Monitor) Observe(loc omlox.Location) []RouteDeviation {
+	m.mu.Lock()
+	at := m.clock.Now()
+	m.mu.Unlock()
+
+	if loc.TimestampGenerated != nil {
+		at = *loc.TimestampGenerated
+	}
+
+	crs := loc.Crs
+	if crs == "" {
+		crs = omlox.CrsLocal
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var events []RouteDeviation
+	for _, id := range loc.Trackables {
+		routeID, ok := m.assignments[id]
+		if !ok {
+			continue
+		}
+
+		r := m.routes[routeID]
+		if routeCrs := r.Crs; routeCrs != crs && !(routeCrs == "" && crs == omlox.CrsLocal) {
+			continue
+		}
+
+		distance, progress := r.Distance(loc.Position.Base())
+		outside := distance > r.Corridor
+
+		if outside == m.deviating[id] {
+			continue
+		}
+		m.deviating[id] = outside
+
+		events = append(events, RouteDeviation{
+			RouteID:     routeID,
+			TrackableID: id,
+			Location:    loc,
+			Distance:    distance,
+			Progress:    progress,
+			Returned:    !outside,
+			At:          at,
+		})
+	}
+
+	return events
+}
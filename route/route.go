@@ -0,0 +1,165 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package route defines expected routes, such as the paths of automated guided
+// vehicles (AGVs), and detects trackables deviating from them.
+package route
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/internal/geo"
+)
+
+// Route is an expected path with a corridor around it.
+type Route struct {
+	// ID identifies the route in emitted events.
+	ID string
+
+	// Name is an optional textual representation of the route.
+	Name string
+
+	// Path are the vertices of the route line, in order.
+	Path []geometry.Point
+
+	// Crs is the coordinate reference system of the path, either a valid EPSG identifier or 'local'.
+	// WGS84 (EPSG:4326) paths are in longitude and latitude, other systems are assumed to be in meters.
+	Crs string
+
+	// Corridor is the maximum distance in meters a trackable may be from the path.
+	Corridor float64
+}
+
+// Validate checks the route definition.
+func (r Route) Validate() error {
+	if r.ID == "" {
+		return fmt.Errorf("route must have an id")
+	}
+	if len(r.Path) < 2 {
+		return fmt.Errorf("route '%s' must have at least two points", r.ID)
+	}
+	if r.Corridor <= 0 {
+		return fmt.Errorf("route '%s' corridor must be positive", r.ID)
+	}
+	return nil
+}
+
+// Distance returns the distance in meters from a position to the route path, and the
+// progress along the route in meters of the closest point of the path.
+// The position must be in the crs of the route.
+func (r Route) Distance(p geometry.Point) (distance float64, progress float64) {
+	// WGS84 coordinates are scaled to meters around the position
+	sx, sy := 1.0, 1.0
+	if r.Crs == omlox.CrsWGS84 {
+		sx, sy = geo.Scale(p.Y)
+	}
+
+	distance = math.Inf(1)
+	walked := 0.0
+
+	for i := 1; i < len(r.Path); i++ {
+		ax, ay := (r.Path[i-1].X-p.X)*sx, (r.Path[i-1].Y-p.Y)*sy
+		bx, by := (r.Path[i].X-p.X)*sx, (r.Path[i].Y-p.Y)*sy
+
+		dx, dy := bx-ax, by-ay
+		length := math.Hypot(dx, dy)
+
+		// projection of the position, the origin, on the segment
+		t := 0.0
+		if length > 0 {
+			t = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/(length*length)))
+		}
+
+		if d := math.Hypot(ax+t*dx, ay+t*dy); d < distance {
+			distance = d
+			progress = walked + t*length
+		}
+
+		walked += length
+	}
+
+	return distance, progress
+}
+
+// Remaining returns the distance in meters left to the end of the route from a position:
+// the distance to the path and the length of the path after the closest point.
+// It can be used to estimate arrival times along the route with omlox.WithRouteDistance.
+func (r Route) Remaining(p geometry.Point) float64 {
+	distance, progress := r.Distance(p)
+	_, length := r.Distance(r.Path[len(r.Path)-1])
+
+	return distance + math.Max(0, length-progress)
+}
+
+// FromGeoJSON returns the routes of a GeoJSON feature collection, from its LineString features.
+// The "id", "name" and "corridor" feature properties define the route, with the feature id used
+// when there is no "id" property. Routes are in WGS84, as GeoJSON coordinates are in longitude and latitude.
+func FromGeoJSON(data []byte, defaultCorridor float64) ([]Route, error) {
+	type feature struct {
+		ID       any `json:"id"`
+		Geometry struct {
+			Type        string          `json:"type"`
+			Coordinates json.RawMessage `json:"coordinates"`
+		} `json:"geometry"`
+		Properties struct {
+			ID       string   `json:"id"`
+			Name     string   `json:"name"`
+			Corridor *float64 `json:"corridor"`
+		} `json:"properties"`
+	}
+
+	var doc struct {
+		Type     string    `json:"type"`
+		Features []feature `json:"features"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("not a GeoJSON feature collection")
+	}
+
+	var routes []Route
+	for _, f := range doc.Features {
+		if f.Geometry.Type != "LineString" {
+			continue
+		}
+
+		r := Route{
+			ID:       f.Properties.ID,
+			Name:     f.Properties.Name,
+			Crs:      omlox.CrsWGS84,
+			Corridor: defaultCorridor,
+		}
+		if r.ID == "" && f.ID != nil {
+			r.ID = fmt.Sprint(f.ID)
+		}
+		if f.Properties.Corridor != nil {
+			r.Corridor = *f.Properties.Corridor
+		}
+
+		var coordinates [][]float64
+		if err := json.Unmarshal(f.Geometry.Coordinates, &coordinates); err != nil {
+			return nil, fmt.Errorf("route '%s' has invalid coordinates: %w", r.ID, err)
+		}
+
+		for _, c := range coordinates {
+			if len(c) < 2 {
+				return nil, fmt.Errorf("route '%s' has an invalid position", r.ID)
+			}
+			r.Path = append(r.Path, geometry.Point{X: c[0], Y: c[1]})
+		}
+
+		if err := r.Validate(); err != nil {
+			return nil, err
+		}
+
+		routes = append(routes, r)
+	}
+
+	return routes, nil
+}
@@ -0,0 +1,135 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package route
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+)
+
+// lShaped is a route going 10m east and then 10m north.
+var lShaped = Route{
+	ID:       "agv-loop",
+	Path:     []geometry.Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}},
+	Crs:      omlox.CrsLocal,
+	Corridor: 1,
+}
+
+func TestRouteDistance(t *testing.T) {
+	tests := []struct {
+		name     string
+		at       geometry.Point
+		distance float64
+		progress float64
+	}{
+		{name: "on path", at: geometry.Point{X: 5, Y: 0}, distance: 0, progress: 5},
+		{name: "beside first segment", at: geometry.Point{X: 5, Y: -2}, distance: 2, progress: 5},
+		{name: "beside second segment", at: geometry.Point{X: 11, Y: 6}, distance: 1, progress: 16},
+		{name: "before start", at: geometry.Point{X: -3, Y: -4}, distance: 5, progress: 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d, p := lShaped.Distance(tc.at)
+			if math.Abs(d-tc.distance) > 1e-9 || math.Abs(p-tc.progress) > 1e-9 {
+				t.Errorf("expected distance %v and progress %v, got %v and %v", tc.distance, tc.progress, d, p)
+			}
+		})
+	}
+}
+
+func TestRouteRemaining(t *testing.T) {
+	if got := lShaped.Remaining(geometry.Point{X: 5, Y: -2}); math.Abs(got-17) > 1e-9 {
+		t.Errorf("expected 17m remaining, got %v", got)
+	}
+	if got := lShaped.Remaining(geometry.Point{X: 10, Y: 10}); got != 0 {
+		t.Errorf("expected nothing remaining at the end, got %v", got)
+	}
+}
+
+func TestMonitor(t *testing.T) {
+	m, err := NewMonitor(lShaped)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := uuid.New()
+	if err := m.Assign(id, "agv-loop"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Assign(id, "unknown"); err == nil {
+		t.Error("expected error assigning an unknown route")
+	}
+
+	start := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	location := func(x, y float64, sec int) omlox.Location {
+		at := start.Add(time.Duration(sec) * time.Second)
+		return omlox.Location{
+			Position:           *omlox.NewPoint(geometry.Point{X: x, Y: y}),
+			Trackables:         []uuid.UUID{id},
+			TimestampGenerated: &at,
+		}
+	}
+
+	steps := []struct {
+		loc      omlox.Location
+		events   int
+		returned bool
+	}{
+		{loc: location(2, 0.5, 0)},
+		{loc: location(5, 3, 1), events: 1},
+		{loc: location(6, 4, 2)},
+		{loc: location(10.5, 5, 3), events: 1, returned: true},
+	}
+
+	for i, s := range steps {
+		events := m.Observe(s.loc)
+		if len(events) != s.events {
+			t.Fatalf("step %d: expected %d events, got %+v", i, s.events, events)
+		}
+		if s.events > 0 && events[0].Returned != s.returned {
+			t.Errorf("step %d: expected returned %v, got %v", i, s.returned, events[0].Returned)
+		}
+	}
+
+	// locations in another crs are not comparable with the route
+	wgs84 := location(50, 50, 4)
+	wgs84.Crs = omlox.CrsWGS84
+	if events := m.Observe(wgs84); len(events) != 0 {
+		t.Errorf("expected no events for another crs, got %+v", events)
+	}
+}
+
+func TestFromGeoJSON(t *testing.T) {
+	data := []byte(`{"type":"FeatureCollection","features":[
+		{"type":"Feature","id":"a","geometry":{"type":"LineString","coordinates":[[-8.6291,41.1579],[-8.6280,41.1579]]},"properties":{"name":"aisle 1"}},
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[-8.6291,41.1579]},"properties":{}},
+		{"type":"Feature","geometry":{"type":"LineString","coordinates":[[-8.6291,41.1580],[-8.6280,41.1580]]},"properties":{"id":"b","corridor":0.5}}
+	]}`)
+
+	routes, err := FromGeoJSON(data, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(routes) != 2 {
+		t.Fatalf("expected 2 routes, got %d", len(routes))
+	}
+	if routes[0].ID != "a" || routes[0].Name != "aisle 1" || routes[0].Corridor != 2 {
+		t.Errorf("unexpected first route: %+v", routes[0])
+	}
+	if routes[1].ID != "b" || routes[1].Corridor != 0.5 {
+		t.Errorf("unexpected second route: %+v", routes[1])
+	}
+
+	// about 11m north of the first route
+	if d, _ := routes[0].Distance(geometry.Point{X: -8.6285, Y: 41.1580}); math.Abs(d-11.13) > 0.1 {
+		t.Errorf("expected distance of about 11.13m, got %v", d)
+	}
+}
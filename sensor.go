@@ -0,0 +1,144 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SensorData defines model for SensorData.
+//
+//easyjson:json
+type SensorData struct {
+	// The location provider unique identifier which measured the sensor values.
+	ProviderID string `json:"provider_id"`
+
+	// The sensor readings.
+	Values []SensorReading `json:"values"`
+
+	// The timestamp when the sensor values were measured.
+	TimestampGenerated *time.Time `json:"timestamp_generated,omitempty"`
+
+	// The timestamp when the sensor values were sent over the network.
+	TimestampSent *time.Time `json:"timestamp_sent,omitempty"`
+
+	// Any additional application or vendor specific properties. An application implementing this object is not required to interpret
+	// any of the custom properties, but it MUST preserve the properties if set.
+	Properties json.RawMessage `json:"properties,omitempty"`
+}
+
+// Reading returns the first reading of the given type.
+func (d SensorData) Reading(t SensorType) (SensorReading, bool) {
+	for _, r := range d.Values {
+		if r.Type == t {
+			return r, true
+		}
+	}
+	return SensorReading{}, false
+}
+
+// SensorReading defines model for a single sensor reading.
+//
+//easyjson:json
+type SensorReading struct {
+	// The type of the sensor reading.
+	Type SensorType `json:"type"`
+
+	// The name of the reading. Only meaningful for vendor readings.
+	Name string `json:"name,omitempty"`
+
+	// The measured value, in the unit of the reading type.
+	Value float64 `json:"value"`
+
+	// The unit of the measured value.
+	Unit string `json:"unit,omitempty"`
+
+	// The timestamp of the reading, if different from the sensor data timestamp.
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+
+	// Raw vendor specific reading data, preserved as is.
+	Raw json.RawMessage `json:"raw,omitempty"`
+}
+
+// BatteryReading returns a battery level reading in percent.
+func BatteryReading(percent float64) SensorReading {
+	return SensorReading{Type: SensorTypeBattery, Value: percent, Unit: "%"}
+}
+
+// TemperatureReading returns a temperature reading in degrees Celsius.
+func TemperatureReading(celsius float64) SensorReading {
+	return SensorReading{Type: SensorTypeTemperature, Value: celsius, Unit: "°C"}
+}
+
+// HumidityReading returns a relative humidity reading in percent.
+func HumidityReading(percent float64) SensorReading {
+	return SensorReading{Type: SensorTypeHumidity, Value: percent, Unit: "%"}
+}
+
+// VendorReading returns a vendor specific reading with the given name and raw data.
+func VendorReading(name string, raw json.RawMessage) SensorReading {
+	return SensorReading{Type: SensorTypeVendor, Name: name, Raw: raw}
+}
+
+// SensorType is the type of a sensor reading.
+type SensorType int
+
+// Defines values for SensorType.
+const (
+	SensorTypeBattery SensorType = iota
+	SensorTypeTemperature
+	SensorTypeHumidity
+	SensorTypeVendor
+)
+
+// FromString assigs itself from type name.
+func (t *SensorType) FromString(name string) error {
+	v, ok := map[string]SensorType{
+		SensorTypeBattery.String():     SensorTypeBattery,
+		SensorTypeTemperature.String(): SensorTypeTemperature,
+		SensorTypeHumidity.String():    SensorTypeHumidity,
+		SensorTypeVendor.String():      SensorTypeVendor,
+	}[name]
+
+	if !ok {
+		return fmt.Errorf("sensor of type %s not supported", name)
+	}
+
+	*t = v
+	return nil
+}
+
+// String return a text representation.
+func (t SensorType) String() string {
+	types := [...]string{
+		"battery",
+		"temperature",
+		"humidity",
+		"vendor",
+	}
+
+	if int(t) < 0 || len(types) <= int(t) {
+		return ""
+	}
+
+	return types[t]
+}
+
+// MarshalJSON encodes type in to JSON.
+func (t SensorType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON decodes type from JSON.
+func (t *SensorType) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	return t.FromString(s)
+}
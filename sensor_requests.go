@@ -0,0 +1,62 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"net/http"
+)
+
+// SensorsAPI is a simple wrapper around the client for sensor data requests.
+// Sensor data is only available on Hubs accepting sensor telemetry alongside positions.
+type SensorsAPI struct {
+	client *Client
+}
+
+// Push sends sensor readings measured by a location provider.
+func (c *SensorsAPI) Push(ctx context.Context, providerID string, readings []SensorReading) error {
+	if err := c.client.require(ctx, FeatureSensors); err != nil {
+		return err
+	}
+
+	requestPath := "/providers/" + providerID + "/sensors"
+
+	now := c.client.timeSource().Now().UTC()
+	data := SensorData{
+		ProviderID:    providerID,
+		Values:        readings,
+		TimestampSent: &now,
+	}
+
+	_, err := sendStructuredRequestParseResponse[struct{}](
+		ctx,
+		c.client,
+		http.MethodPut,
+		requestPath,
+		data,
+		nil, // request query parameters
+		nil, // request headers
+	)
+
+	return err
+}
+
+// Get gets the latest sensor data of a location provider.
+func (c *SensorsAPI) Get(ctx context.Context, providerID string) (*SensorData, error) {
+	if err := c.client.require(ctx, FeatureSensors); err != nil {
+		return nil, err
+	}
+
+	requestPath := "/providers/" + providerID + "/sensors"
+
+	return sendRequestParseResponse[SensorData](
+		ctx,
+		c.client,
+		http.MethodGet,
+		requestPath,
+		nil, // request body
+		nil, // request query parameters
+		nil, // request headers
+	)
+}
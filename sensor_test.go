@@ -0,0 +1,69 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+var sensorDataJSONTestCases = []struct {
+	name string
+	data SensorData
+	json []byte
+}{
+	{
+		name: "typed",
+		data: SensorData{
+			ProviderID: "ac:23:3f:af:f3:90",
+			Values: []SensorReading{
+				BatteryReading(87),
+				TemperatureReading(21.5),
+				HumidityReading(0),
+			},
+			TimestampGenerated: mustParseTime("2023-10-17T11:14:37.206Z"),
+		},
+		json: []byte(`{"provider_id":"ac:23:3f:af:f3:90","values":[{"type":"battery","value":87,"unit":"%"},{"type":"temperature","value":21.5,"unit":"°C"},{"type":"humidity","value":0,"unit":"%"}],"timestamp_generated":"2023-10-17T11:14:37.206Z"}`),
+	},
+	{
+		name: "vendor",
+		data: SensorData{
+			ProviderID: "ac:23:3f:af:f3:90",
+			Values: []SensorReading{
+				VendorReading("org.wavecom.accel", json.RawMessage(`{"x":0.1,"y":0,"z":9.8}`)),
+			},
+			Properties: json.RawMessage(`{"firmware":"1.2.3"}`),
+		},
+		json: []byte(`{"provider_id":"ac:23:3f:af:f3:90","values":[{"type":"vendor","name":"org.wavecom.accel","value":0,"raw":{"x":0.1,"y":0,"z":9.8}}],"properties":{"firmware":"1.2.3"}}`),
+	},
+}
+
+func TestSensorDataMarshal(t *testing.T) {
+	for _, tc := range sensorDataJSONTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			JSONMarshalOK(t, tc.data, tc.json)
+		})
+	}
+}
+
+func TestSensorDataUnmarshal(t *testing.T) {
+	for _, tc := range sensorDataJSONTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			JSONUnmarshalOK(t, tc.json, tc.data)
+		})
+	}
+}
+
+func TestSensorDataReading(t *testing.T) {
+	data := sensorDataJSONTestCases[0].data
+
+	r, ok := data.Reading(SensorTypeTemperature)
+	if !ok || r.Value != 21.5 {
+		t.Errorf("expected temperature reading of 21.5, got %v (%v)", r.Value, ok)
+	}
+
+	if _, ok := data.Reading(SensorTypeVendor); ok {
+		t.Errorf("expected no vendor reading")
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// getManyConcurrency is the number of resources fetched concurrently by GetMany.
+const getManyConcurrency = 8
+
+// Resource is a Hub resource with list, get, create, update and delete endpoints.
+type Resource interface {
+	Trackable | LocationProvider | Fence | Zone | Webhook
+}
+
+// Service implements the requests shared by all Hub resources, so resource APIs
+// behave consistently. Resources are managed under a collection path (e.g. "/trackables"),
+// with each resource available at the collection path followed by its id.
+type Service[T Resource] struct {
+	client *Client
+
+	// collection path of the resource
+	path string
+}
+
+// NewService returns a new service for the resources under the given collection path.
+func NewService[T Resource](client *Client, path string) *Service[T] {
+	return &Service[T]{
+		client: client,
+		path:   path,
+	}
+}
+
+// List lists all resources.
+func (s *Service[T]) List(ctx context.Context) ([]T, error) {
+	requestPath := s.path + "/summary"
+
+	return sendRequestParseResponseList[T](
+		ctx,
+		s.client,
+		http.MethodGet,
+		requestPath,
+		nil, // request body
+		nil, // request query parameters
+		nil, // request headers
+	)
+}
+
+// Create creates a resource.
+// Resources without id get one from the id strategy of the client, if any.
+func (s *Service[T]) Create(ctx context.Context, resource T) (*T, error) {
+	if _, err := s.client.generateID(&resource); err != nil {
+		return nil, err
+	}
+
+	return sendStructuredRequestParseResponse[T](
+		ctx,
+		s.client,
+		http.MethodPost,
+		s.path,
+		resource,
+		nil, // request query parameters
+		nil, // request headers
+	)
+}
+
+// DeleteAll deletes all resources.
+func (s *Service[T]) DeleteAll(ctx context.Context) error {
+	_, err := sendRequestParseResponse[struct{}](
+		ctx,
+		s.client,
+		http.MethodDelete,
+		s.path,
+		nil, // request body
+		nil, // request query parameters
+		nil, // request headers
+	)
+
+	return err
+}
+
+// Get gets a resource.
+func (s *Service[T]) Get(ctx context.Context, id string) (*T, error) {
+	requestPath := s.path + "/" + id
+
+	return sendRequestParseResponse[T](
+		ctx,
+		s.client,
+		http.MethodGet,
+		requestPath,
+		nil, // request body
+		nil, // request query parameters
+		nil, // request headers
+	)
+}
+
+// GetMany gets the resources of the given ids concurrently, in the order of the ids.
+// The deadline of the context is split across the requests with a Budget, so it bounds
+// the whole operation. It fails on the first error, such as a resource not found.
+func (s *Service[T]) GetMany(ctx context.Context, ids []string) ([]T, error) {
+	resources := make([]T, len(ids))
+	budget := NewBudget(ctx, len(ids), getManyConcurrency)
+	budget.SetClock(s.client.timeSource())
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(getManyConcurrency)
+
+	for i, id := range ids {
+		i, id := i, id
+		g.Go(func() error {
+			rctx, cancel := budget.Next(gctx)
+			defer cancel()
+
+			r, err := s.Get(rctx, id)
+			if err != nil {
+				return fmt.Errorf("get %s: %w", id, err)
+			}
+			if r != nil {
+				resources[i] = *r
+			}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+// Update updates a resource.
+func (s *Service[T]) Update(ctx context.Context, resource T, id string) error {
+	requestPath := s.path + "/" + id
+
+	_, err := sendStructuredRequestParseResponse[struct{}](
+		ctx,
+		s.client,
+		http.MethodPut,
+		requestPath,
+		resource,
+		nil, // request query parameters
+		nil, // request headers
+	)
+
+	return err
+}
+
+// Delete deletes a resource.
+func (s *Service[T]) Delete(ctx context.Context, id string) error {
+	requestPath := s.path + "/" + id
+
+	_, err := sendRequestParseResponse[struct{}](
+		ctx,
+		s.client,
+		http.MethodDelete,
+		requestPath,
+		nil, // request body
+		nil, // request query parameters
+		nil, // request headers
+	)
+
+	return err
+}
+
+// listIDs lists the ids of all resources of a service.
+// Go does not support type parameters on methods, so the id type is given here.
+func listIDs[ID any, T Resource](ctx context.Context, s *Service[T]) ([]ID, error) {
+	return sendRequestParseResponseList[ID](
+		ctx,
+		s.client,
+		http.MethodGet,
+		s.path,
+		nil, // request body
+		nil, // request query parameters
+		nil, // request headers
+	)
+}
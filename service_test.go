@@ -0,0 +1,153 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// roundTripFunc is an http.RoundTripper stub.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestService(t *testing.T) {
+	var requests []string
+
+	httpClient := &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+
+			body := `{"id":"ac:23:3f:af:f3:90","type":"uwb"}`
+			if strings.HasSuffix(r.URL.Path, "/summary") {
+				body = "[" + body + "]"
+			}
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}, nil
+		}),
+	}
+
+	c, err := New("http://localhost:8081/v2", WithHTTPClient(httpClient))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	s := NewService[LocationProvider](c, "/providers")
+
+	providers, err := s.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(providers) != 1 || providers[0].ID != "ac:23:3f:af:f3:90" {
+		t.Errorf("unexpected providers: %+v", providers)
+	}
+
+	p, err := s.Get(ctx, "ac:23:3f:af:f3:90")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Type != LocationProviderTypeUwb {
+		t.Errorf("expected uwb provider, got %v", p.Type)
+	}
+
+	if _, err := s.Create(ctx, *p); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Update(ctx, *p, p.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(ctx, p.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteAll(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"GET /v2/providers/summary",
+		"GET /v2/providers/ac:23:3f:af:f3:90",
+		"POST /v2/providers",
+		"PUT /v2/providers/ac:23:3f:af:f3:90",
+		"DELETE /v2/providers/ac:23:3f:af:f3:90",
+		"DELETE /v2/providers",
+	}
+
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected requests:\n%s\nwanted:\n%s", strings.Join(requests, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestServiceGetMany(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		shared int
+	)
+	httpClient := &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+			if id == "missing" {
+				return &http.Response{
+					StatusCode: http.StatusNotFound,
+					Body:       io.NopCloser(strings.NewReader(`{"type":"not_found","code":404}`)),
+				}, nil
+			}
+
+			// requests of the first round get half of the deadline of the operation
+			if deadline, ok := r.Context().Deadline(); ok && time.Until(deadline) <= 30*time.Second {
+				mu.Lock()
+				shared++
+				mu.Unlock()
+			}
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"id":"` + id + `","type":"uwb"}`)),
+			}, nil
+		}),
+	}
+
+	c, err := New("http://localhost:8081/v2", WithHTTPClient(httpClient))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	s := NewService[LocationProvider](c, "/providers")
+
+	ids := make([]string, 2*getManyConcurrency)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("tag-%d", i)
+	}
+	providers, err := s.GetMany(ctx, ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range providers {
+		if p.ID != ids[i] {
+			t.Errorf("expected provider %s at %d, got %s", ids[i], i, p.ID)
+		}
+	}
+	if shared != getManyConcurrency {
+		t.Errorf("expected %d requests with a share of the deadline, got %d", getManyConcurrency, shared)
+	}
+
+	if _, err := s.GetMany(ctx, []string{"tag-1", "missing"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package signing signs and verifies resource bundles, such as hubfiles, so
+// only the configurations approved by the holder of a key are applied to a Hub.
+//
+// Public keys and signatures use the minisign format, with Ed25519 signatures
+// of the whole content (the legacy "Ed" algorithm), so the signatures can also
+// be verified with minisign:
+//
+//	minisign -Vm hub.yaml -p omlox.pub
+//
+// Private keys are stored in the same layout, unencrypted, and are specific to
+// this package: they must be kept off the hosts applying the bundles.
+package signing
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+// ErrInvalidSignature is returned when a signature doesn't match the content or the key.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// algorithm identifies Ed25519 signatures of the whole content.
+var algorithm = [2]byte{'E', 'd'}
+
+const (
+	untrustedPrefix = "untrusted comment: "
+	trustedPrefix   = "trusted comment: "
+)
+
+// KeyID identifies the key of a signature.
+type KeyID [8]byte
+
+// String returns the key id as minisign prints it.
+func (id KeyID) String() string {
+	// minisign stores the id little-endian and prints it as a number.
+	var b [8]byte
+	for i := range id {
+		b[i] = id[len(id)-1-i]
+	}
+	return strings.ToUpper(hex.EncodeToString(b[:]))
+}
+
+// PublicKey verifies the signatures of a private key.
+type PublicKey struct {
+	ID  KeyID
+	Key ed25519.PublicKey
+}
+
+// PrivateKey signs resource bundles.
+type PrivateKey struct {
+	ID  KeyID
+	Key ed25519.PrivateKey
+}
+
+// GenerateKey generates a private key with a random id.
+func GenerateKey() (*PrivateKey, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	k := &PrivateKey{Key: key}
+	if _, err := io.ReadFull(rand.Reader, k.ID[:]); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+// Public returns the public key of the private key.
+func (k *PrivateKey) Public() *PublicKey {
+	return &PublicKey{ID: k.ID, Key: k.Key.Public().(ed25519.PublicKey)}
+}
+
+// MarshalText encodes the public key as a minisign public key file.
+func (k *PublicKey) MarshalText() ([]byte, error) {
+	return encode(fmt.Sprintf("minisign public key %s", k.ID), k.ID, k.Key), nil
+}
+
+// MarshalText encodes the private key.
+func (k *PrivateKey) MarshalText() ([]byte, error) {
+	return encode(fmt.Sprintf("omlox private key %s", k.ID), k.ID, k.Key.Seed()), nil
+}
+
+// ParsePublicKey parses a minisign public key file.
+func ParsePublicKey(data []byte) (*PublicKey, error) {
+	id, key, err := decode(data, ed25519.PublicKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+
+	return &PublicKey{ID: id, Key: ed25519.PublicKey(key)}, nil
+}
+
+// ParsePrivateKey parses a private key encoded by PrivateKey.MarshalText.
+func ParsePrivateKey(data []byte) (*PrivateKey, error) {
+	id, seed, err := decode(data, ed25519.SeedSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	return &PrivateKey{ID: id, Key: ed25519.NewKeyFromSeed(seed)}, nil
+}
+
+// Sign signs the content, returning a minisign signature file. The name of the
+// signed file and the time of the clock, clock.System if nil, are recorded in the
+// trusted comment of the signature.
+func Sign(k *PrivateKey, content []byte, name string, clk clock.Clock) []byte {
+	sig := ed25519.Sign(k.Key, content)
+
+	trusted := fmt.Sprintf("timestamp:%d\tfile:%s", clock.Or(clk).Now().Unix(), name)
+	global := ed25519.Sign(k.Key, append(append([]byte{}, sig...), trusted...))
+
+	var buf bytes.Buffer
+	buf.Write(encode("signature from omlox private key "+k.ID.String(), k.ID, sig))
+	fmt.Fprintf(&buf, "%s%s\n", trustedPrefix, trusted)
+	fmt.Fprintf(&buf, "%s\n", base64.StdEncoding.EncodeToString(global))
+
+	return buf.Bytes()
+}
+
+// Verify verifies the minisign signature of the content, returning its trusted comment.
+func Verify(k *PublicKey, content, signature []byte) (string, error) {
+	lines := readLines(signature)
+	if len(lines) < 4 || !strings.HasPrefix(lines[2], trustedPrefix) {
+		return "", fmt.Errorf("invalid signature file")
+	}
+
+	id, sig, err := decode([]byte(lines[0]+"\n"+lines[1]), ed25519.SignatureSize)
+	if err != nil {
+		return "", fmt.Errorf("invalid signature file: %w", err)
+	}
+	if id != k.ID {
+		return "", fmt.Errorf("%w: signed by key %s, expected %s", ErrInvalidSignature, id, k.ID)
+	}
+	if !ed25519.Verify(k.Key, content, sig) {
+		return "", ErrInvalidSignature
+	}
+
+	trusted := strings.TrimPrefix(lines[2], trustedPrefix)
+	global, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil {
+		return "", fmt.Errorf("invalid signature file: %w", err)
+	}
+	if !ed25519.Verify(k.Key, append(append([]byte{}, sig...), trusted...), global) {
+		return "", fmt.Errorf("%w: altered trusted comment", ErrInvalidSignature)
+	}
+
+	return trusted, nil
+}
+
+// encode encodes a key or signature with its untrusted comment.
+func encode(comment string, id KeyID, data []byte) []byte {
+	raw := make([]byte, 0, len(algorithm)+len(id)+len(data))
+	raw = append(raw, algorithm[:]...)
+	raw = append(raw, id[:]...)
+	raw = append(raw, data...)
+
+	return []byte(untrustedPrefix + comment + "\n" + base64.StdEncoding.EncodeToString(raw) + "\n")
+}
+
+// decode decodes a key or signature encoded by encode.
+func decode(data []byte, size int) (KeyID, []byte, error) {
+	var id KeyID
+
+	lines := readLines(data)
+	if len(lines) < 2 || !strings.HasPrefix(lines[0], untrustedPrefix) {
+		return id, nil, fmt.Errorf("missing untrusted comment")
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil {
+		return id, nil, err
+	}
+	if len(raw) != len(algorithm)+len(id)+size {
+		return id, nil, fmt.Errorf("unexpected length %d", len(raw))
+	}
+	if !bytes.Equal(raw[:len(algorithm)], algorithm[:]) {
+		return id, nil, fmt.Errorf("unsupported algorithm %q", raw[:len(algorithm)])
+	}
+
+	copy(id[:], raw[len(algorithm):])
+	return id, raw[len(algorithm)+len(id):], nil
+}
+
+func readLines(data []byte) []string {
+	var lines []string
+
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		lines = append(lines, strings.TrimRight(s.Text(), "\r"))
+	}
+
+	return lines
+}
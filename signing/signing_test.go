@@ -0,0 +1,75 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package signing
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+func TestSignVerify(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// keys survive their encoding
+	text, _ := key.MarshalText()
+	if key, err = ParsePrivateKey(text); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text, _ = key.Public().MarshalText()
+	pub, err := ParsePublicKey(text)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content := []byte("version: 1\n")
+	clk := clock.NewFake(time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC))
+	sig := Sign(key, content, "hub.yaml", clk)
+
+	trusted, err := Verify(pub, content, sig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if trusted != "timestamp:1709280000\tfile:hub.yaml" {
+		t.Errorf("unexpected trusted comment %q", trusted)
+	}
+
+	// altered content
+	if _, err := Verify(pub, []byte("version: 2\n"), sig); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected invalid signature, got %v", err)
+	}
+
+	// altered trusted comment
+	altered := bytes.Replace(sig, []byte("file:hub.yaml"), []byte("file:other.yaml"), 1)
+	if _, err := Verify(pub, content, altered); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected invalid signature, got %v", err)
+	}
+
+	// other key
+	other, _ := GenerateKey()
+	if _, err := Verify(other.Public(), content, sig); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected invalid signature, got %v", err)
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	// public key in the minisign format
+	pub, err := ParsePublicKey([]byte("untrusted comment: minisign public key 4D9B7F6A5C3E1F2A\nRWQqHz5can+bTQABAgMEBQYHCAkKCwwNDg8QERITFBUWFxgZGhscHR4f\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pub.ID.String() != "4D9B7F6A5C3E1F2A" {
+		t.Errorf("unexpected key id %s", pub.ID)
+	}
+
+	if _, err := ParsePublicKey([]byte("RWQqHz5can+bTQABAgMEBQYHCAkKCwwNDg8QERITFBUWFxgZGhscHR4f\n")); err == nil {
+		t.Errorf("expected error without untrusted comment")
+	}
+}
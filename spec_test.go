@@ -0,0 +1,121 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go/internal/openapi"
+)
+
+// TestRequestsInSpec checks that the requests of the client are endpoints of the
+// specification at api/omlox-hub.openapi.yaml, with lists of resources requested on
+// their summary endpoint and lists of ids on the collection endpoint.
+func TestRequestsInSpec(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL, WithHubVersion("2.0.0"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+	id := uuid.MustParse("d27047bd-1b6b-4656-bb93-2326a4c900e1")
+	now := time.Now()
+
+	// responses are not valid for every request, only the requests are checked
+	calls := map[string]func(){
+		"getTrackables":               func() { c.Trackables.List(ctx) },
+		"getTrackableIds":             func() { c.Trackables.IDs(ctx) },
+		"createTrackable":             func() { c.Trackables.Create(ctx, Trackable{ID: id}) },
+		"deleteTrackables":            func() { c.Trackables.DeleteAll(ctx) },
+		"getTrackable":                func() { c.Trackables.Get(ctx, id) },
+		"updateTrackable":             func() { c.Trackables.Update(ctx, Trackable{ID: id}, id) },
+		"deleteTrackable":             func() { c.Trackables.Delete(ctx, id) },
+		"getTrackableLocation":        func() { c.Trackables.GetLocation(ctx, id) },
+		"getProviders":                func() { c.Providers.List(ctx) },
+		"getProviderIds":              func() { c.Providers.IDs(ctx) },
+		"createProvider":              func() { c.Providers.Create(ctx, LocationProvider{ID: "p1"}) },
+		"deleteProviders":             func() { c.Providers.DeleteAll(ctx) },
+		"getProvider":                 func() { c.Providers.Get(ctx, "p1") },
+		"updateProvider":              func() { c.Providers.Update(ctx, LocationProvider{ID: "p1"}, "p1") },
+		"deleteProvider":              func() { c.Providers.Delete(ctx, "p1") },
+		"getProviderLocation":         func() { c.Providers.GetLocation(ctx, "p1") },
+		"updateProviderLocation":      func() { c.Providers.SetLocation(ctx, Location{ProviderID: "p1"}, "p1") },
+		"getProviderTrackables":       func() { c.Providers.GetTrackables(ctx, "p1") },
+		"getFences":                   func() { c.Fences.List(ctx) },
+		"getFenceIds":                 func() { c.Fences.IDs(ctx) },
+		"createFence":                 func() { c.Fences.Create(ctx, Fence{ID: id}) },
+		"deleteFences":                func() { c.Fences.DeleteAll(ctx) },
+		"getFence":                    func() { c.Fences.Get(ctx, id) },
+		"updateFence":                 func() { c.Fences.Update(ctx, Fence{ID: id}, id) },
+		"deleteFence":                 func() { c.Fences.Delete(ctx, id) },
+		"getZones":                    func() { c.Zones.List(ctx) },
+		"getZoneIds":                  func() { c.Zones.IDs(ctx) },
+		"createZone":                  func() { c.Zones.Create(ctx, Zone{ID: id}) },
+		"deleteZones":                 func() { c.Zones.DeleteAll(ctx) },
+		"getZone":                     func() { c.Zones.Get(ctx, id) },
+		"updateZone":                  func() { c.Zones.Update(ctx, Zone{ID: id}, id) },
+		"deleteZone":                  func() { c.Zones.Delete(ctx, id) },
+		"getZoneTransform":            func() { c.Zones.GetTransform(ctx, id) },
+		"getLocationHistory":          func() { c.History.Locations(ctx, now, now) },
+		"getTrackableLocationHistory": func() { c.History.TrackableLocations(ctx, id, now, now) },
+		"getFenceEventHistory":        func() { c.History.FenceEvents(ctx, now, now) },
+	}
+
+	for want, call := range calls {
+		mu.Lock()
+		requests = requests[:0]
+		mu.Unlock()
+
+		call()
+
+		mu.Lock()
+		got := append([]string(nil), requests...)
+		mu.Unlock()
+
+		if len(got) == 0 {
+			t.Errorf("%s: no request sent", want)
+			continue
+		}
+
+		// the first request is the one of the call, fallbacks may follow on failures
+		method, path, _ := strings.Cut(got[0], " ")
+		op, ok := openapi.Match(method, path)
+		if !ok {
+			t.Errorf("%s: request %s is not in the specification", want, got[0])
+			continue
+		}
+		if op.ID != want {
+			t.Errorf("%s: request %s is the %s operation", want, got[0], op.ID)
+		}
+	}
+
+	// the calls cover the whole specification
+	for _, op := range openapi.Operations {
+		if _, ok := calls[op.ID]; !ok {
+			t.Errorf("operation %s is not requested by the client", op.ID)
+		}
+	}
+}
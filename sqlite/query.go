@@ -0,0 +1,193 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tidwall/geojson"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/internal/geo"
+)
+
+// Position is a stored position of a trackable, or of a provider without trackables.
+type Position struct {
+	// Key is the trackable id, or the provider id.
+	Key string
+
+	// Location is the location of the position.
+	Location omlox.Location
+
+	// At is the time the location was generated.
+	At time.Time
+}
+
+// Event is a stored event.
+type Event struct {
+	Topic   omlox.Topic
+	Key     string
+	At      time.Time
+	Payload json.RawMessage
+}
+
+const positionColumns = `p.key, p.provider_id, p.crs, p.x, p.y, p.z, p.floor, p.accuracy, p.at`
+
+// latestPositions selects the last position of each key since a time.
+const latestPositions = `SELECT ` + positionColumns + ` FROM positions p
+	JOIN (SELECT key, MAX(id) AS id FROM positions WHERE at >= ? GROUP BY key) l ON p.id = l.id`
+
+// scanPositions scans the positions of rows.
+func scanPositions(rows *sql.Rows, err error) ([]Position, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var positions []Position
+	for rows.Next() {
+		var p Position
+		var x, y, z float64
+		var accuracy sql.NullFloat64
+		var at int64
+		if err := rows.Scan(&p.Key, &p.Location.ProviderID, &p.Location.Crs, &x, &y, &z, &p.Location.Floor, &accuracy, &at); err != nil {
+			return nil, err
+		}
+
+		p.At = time.UnixMilli(at).UTC()
+		p.Location.Position = *omlox.NewPointZ(geometry.Point{X: x, Y: y}, z)
+		p.Location.TimestampGenerated = &p.At
+		if accuracy.Valid {
+			p.Location.Accuracy = &accuracy.Float64
+		}
+		if id, err := uuid.Parse(p.Key); err == nil && p.Key != p.Location.ProviderID {
+			p.Location.Trackables = []uuid.UUID{id}
+		}
+		positions = append(positions, p)
+	}
+	return positions, rows.Err()
+}
+
+// Track returns the positions of a trackable, or of a provider without trackables,
+// between two times, in order.
+func (s *Store) Track(ctx context.Context, key string, from, to time.Time) ([]Position, error) {
+	return scanPositions(s.db.QueryContext(ctx, `SELECT `+positionColumns+` FROM positions p
+		WHERE p.key = ? AND p.at >= ? AND p.at < ? ORDER BY p.id`, key, from.UnixMilli(), to.UnixMilli()))
+}
+
+// Latest returns the last position of each trackable updated since a time.
+func (s *Store) Latest(ctx context.Context, since time.Time) ([]Position, error) {
+	return scanPositions(s.db.QueryContext(ctx, latestPositions+` ORDER BY p.key`, since.UnixMilli()))
+}
+
+// within returns the last positions since a time inside a bounding box of a crs.
+func (s *Store) within(ctx context.Context, crs string, box geometry.Rect, since time.Time) ([]Position, error) {
+	return scanPositions(s.db.QueryContext(ctx, latestPositions+`
+		WHERE p.crs = ? AND p.x BETWEEN ? AND ? AND p.y BETWEEN ? AND ?`,
+		since.UnixMilli(), crs, box.Min.X, box.Max.X, box.Min.Y, box.Max.Y))
+}
+
+// metricScale returns the meters per unit of the coordinates of a crs near a point.
+func metricScale(p geometry.Point, crs string) (float64, float64) {
+	if crs != omlox.CrsWGS84 {
+		return 1, 1
+	}
+	return geo.Scale(p.Y)
+}
+
+// around returns the bounding box of a circle, with a radius in meters.
+func around(center geometry.Point, radius float64, crs string) geometry.Rect {
+	sx, sy := metricScale(center, crs)
+	dx, dy := radius/sx, radius/sy
+	return geometry.Rect{
+		Min: geometry.Point{X: center.X - dx, Y: center.Y - dy},
+		Max: geometry.Point{X: center.X + dx, Y: center.Y + dy},
+	}
+}
+
+// Nearby returns the last positions since a time within a radius in meters of a point
+// of a crs, from the closest.
+func (s *Store) Nearby(ctx context.Context, crs string, center geometry.Point, radius float64, since time.Time) ([]Position, error) {
+	if crs == "" {
+		crs = omlox.CrsLocal
+	}
+
+	candidates, err := s.within(ctx, crs, around(center, radius, crs), since)
+	if err != nil {
+		return nil, err
+	}
+
+	sx, sy := metricScale(center, crs)
+	distance := func(p Position) float64 {
+		b := p.Location.Position.Base()
+		return math.Hypot((b.X-center.X)*sx, (b.Y-center.Y)*sy)
+	}
+
+	positions := slices.DeleteFunc(candidates, func(p Position) bool { return distance(p) > radius })
+	slices.SortStableFunc(positions, func(a, b Position) int {
+		switch da, db := distance(a), distance(b); {
+		case da < db:
+			return -1
+		case da > db:
+			return 1
+		}
+		return 0
+	})
+	return positions, nil
+}
+
+// InFence returns the last positions since a time inside a fence.
+func (s *Store) InFence(ctx context.Context, fence omlox.Fence, since time.Time) ([]Position, error) {
+	if fence.Region == nil {
+		return nil, nil
+	}
+	crs := fence.Crs
+	if crs == "" {
+		crs = omlox.CrsWGS84
+	}
+
+	var box geometry.Rect
+	switch region := fence.Region.Object.(type) {
+	case *geojson.Point:
+		box = around(region.Base(), fence.Radius, crs)
+	default:
+		box = region.Rect()
+	}
+
+	candidates, err := s.within(ctx, crs, box, since)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(candidates, func(p Position) bool { return !fence.Contains(p.Location) }), nil
+}
+
+// Events returns the events of a topic between two times, in order.
+func (s *Store) Events(ctx context.Context, topic omlox.Topic, from, to time.Time) ([]Event, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT topic, key, at, payload FROM events
+		WHERE topic = ? AND at >= ? AND at < ? ORDER BY id`, string(topic), from.UnixMilli(), to.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		var e Event
+		var at int64
+		var payload string
+		if err := rows.Scan(&e.Topic, &e.Key, &at, &payload); err != nil {
+			return nil, err
+		}
+		e.At = time.UnixMilli(at).UTC()
+		e.Payload = json.RawMessage(payload)
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,238 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package sqlite stores recent positions and events in an embedded SQLite database,
+// for analytics on edge deployments shipped as a single binary.
+//
+// Positions are kept in a ring buffer per trackable, holding the last positions of each
+// trackable, and events up to a maximum number. Spatial helpers query the positions
+// near a point or inside a fence without a spatial extension.
+//
+// The store uses a database/sql handle, opened with a SQLite driver registered by the
+// application, such as modernc.org/sqlite, which needs no cgo:
+//
+//	db, err := sql.Open("sqlite", "file:/var/lib/omlox/edge.db?_pragma=journal_mode(WAL)")
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/forward"
+)
+
+// Defaults of the store configuration.
+const (
+	DefaultCapacity  = 1000
+	DefaultMaxEvents = 100000
+)
+
+// migrations are the schema migrations, in order, tracked by the user_version pragma.
+var migrations = [][]string{
+	// 1: positions and events
+	{
+		`CREATE TABLE positions (
+			id INTEGER PRIMARY KEY,
+			key TEXT NOT NULL,
+			provider_id TEXT NOT NULL,
+			crs TEXT NOT NULL,
+			x REAL NOT NULL,
+			y REAL NOT NULL,
+			z REAL NOT NULL,
+			floor REAL NOT NULL,
+			accuracy REAL,
+			at INTEGER NOT NULL
+		)`,
+		`CREATE INDEX positions_key ON positions (key, id)`,
+		`CREATE INDEX positions_xy ON positions (crs, x, y)`,
+		`CREATE TABLE events (
+			id INTEGER PRIMARY KEY,
+			event_id TEXT UNIQUE,
+			topic TEXT NOT NULL,
+			key TEXT NOT NULL,
+			at INTEGER NOT NULL,
+			payload TEXT NOT NULL
+		)`,
+		`CREATE INDEX events_topic ON events (topic, at)`,
+	},
+}
+
+// Option is a configuration option of a store.
+type Option func(*Store)
+
+// WithCapacity sets the number of positions kept per trackable.
+//
+// Default: DefaultCapacity
+func WithCapacity(n int) Option {
+	return func(s *Store) {
+		s.capacity = n
+	}
+}
+
+// WithMaxEvents sets the number of events kept.
+//
+// Default: DefaultMaxEvents
+func WithMaxEvents(n int) Option {
+	return func(s *Store) {
+		s.maxEvents = n
+	}
+}
+
+// Store stores the positions of trackables and events in a SQLite database.
+//
+// Positions are keyed by trackable id, or by provider id for locations without
+// trackables. Store is a sink of the forward package, storing the location updates
+// forwarded as positions and other events as events.
+type Store struct {
+	db        *sql.DB
+	capacity  int
+	maxEvents int
+}
+
+var _ forward.Sink = (*Store)(nil)
+
+// New returns a store of a database.
+func New(db *sql.DB, opts ...Option) *Store {
+	s := &Store{
+		db:        db,
+		capacity:  DefaultCapacity,
+		maxEvents: DefaultMaxEvents,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Migrate creates or upgrades the tables, applying the migrations not applied yet in a
+// transaction.
+func (s *Store) Migrate(ctx context.Context) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var version int
+	if err := tx.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&version); err != nil {
+		return err
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("schema version %d is newer than the supported version %d", version, len(migrations))
+	}
+
+	for i := version; i < len(migrations); i++ {
+		for _, stmt := range migrations[i] {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				return fmt.Errorf("migration %d: %w", i+1, err)
+			}
+		}
+	}
+	// pragmas do not take parameters
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`PRAGMA user_version = %d`, len(migrations))); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Deliver implements forward.Sink.
+func (s *Store) Deliver(ctx context.Context, e forward.Event) error {
+	if e.Topic == omlox.TopicLocationUpdates {
+		var loc omlox.Location
+		if err := json.Unmarshal(e.Payload, &loc); err != nil {
+			return fmt.Errorf("invalid location update: %w", err)
+		}
+		return s.Insert(ctx, loc)
+	}
+
+	var ref struct {
+		ID string `json:"id"`
+	}
+	json.Unmarshal(e.Payload, &ref)
+	return s.InsertEvent(ctx, e.Topic, e.Key, ref.ID, time.Now(), e.Payload)
+}
+
+// Insert inserts the positions of locations, once per trackable, and removes the oldest
+// positions of the trackables beyond the capacity.
+func (s *Store) Insert(ctx context.Context, locations ...omlox.Location) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, loc := range locations {
+		crs := loc.Crs
+		if crs == "" {
+			crs = omlox.CrsLocal
+		}
+		at := time.Now()
+		if loc.TimestampGenerated != nil {
+			at = *loc.TimestampGenerated
+		}
+		var accuracy any
+		if loc.Accuracy != nil {
+			accuracy = *loc.Accuracy
+		}
+		base := loc.Position.Base()
+
+		for _, key := range keys(loc) {
+			if _, err := tx.ExecContext(ctx, `INSERT INTO positions (key, provider_id, crs, x, y, z, floor, accuracy, at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				key, loc.ProviderID, crs, base.X, base.Y, loc.Position.Z(), loc.Floor, accuracy, at.UnixMilli()); err != nil {
+				return fmt.Errorf("provider %s: %w", loc.ProviderID, err)
+			}
+			if s.capacity > 0 {
+				if _, err := tx.ExecContext(ctx, `DELETE FROM positions WHERE key = ? AND id <= (SELECT id FROM positions WHERE key = ? ORDER BY id DESC LIMIT 1 OFFSET ?)`,
+					key, key, s.capacity); err != nil {
+					return fmt.Errorf("provider %s: %w", loc.ProviderID, err)
+				}
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// keys returns the keys of the positions of a location.
+func keys(loc omlox.Location) []string {
+	if len(loc.Trackables) == 0 {
+		return []string{loc.ProviderID}
+	}
+	keys := make([]string, len(loc.Trackables))
+	for i, id := range loc.Trackables {
+		keys[i] = id.String()
+	}
+	return keys
+}
+
+// InsertEvent inserts an event of a topic, such as a fence event, with the key of its
+// trackable and its id, if any. Events with the id of an event already stored are
+// ignored. The oldest events beyond the maximum are removed.
+func (s *Store) InsertEvent(ctx context.Context, topic omlox.Topic, key, id string, at time.Time, payload json.RawMessage) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var eventID any
+	if id != "" {
+		eventID = id
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO events (event_id, topic, key, at, payload) VALUES (?, ?, ?, ?, ?) ON CONFLICT (event_id) DO NOTHING`,
+		eventID, string(topic), key, at.UnixMilli(), string(payload)); err != nil {
+		return err
+	}
+	if s.maxEvents > 0 {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM events WHERE id <= (SELECT MAX(id) FROM events) - ?`, s.maxEvents); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,212 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go"
+	"github.com/wavecomtech/omlox-client-go/forward"
+)
+
+var forklift = uuid.MustParse("6c5d2f0e-8f6a-4b7e-9a51-0d6f1a2b3c41")
+
+// statement is a statement run by the fake driver.
+type statement struct {
+	query string
+	args  []driver.Value
+}
+
+// fakeDB is a fake database recording statements, and answering queries with the rows
+// set by the test.
+type fakeDB struct {
+	mu         sync.Mutex
+	statements []statement
+	queries    []statement
+	rows       [][]driver.Value
+	version    int64
+}
+
+var _ driver.Connector = (*fakeDB)(nil)
+
+func (db *fakeDB) Connect(context.Context) (driver.Conn, error) { return &fakeConn{db: db}, nil }
+func (db *fakeDB) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ db *fakeDB }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return c, nil }
+func (c *fakeConn) Commit() error                       { return nil }
+func (c *fakeConn) Rollback() error                     { return nil }
+
+func values(args []driver.NamedValue) []driver.Value {
+	var v []driver.Value
+	for _, arg := range args {
+		v = append(v, arg.Value)
+	}
+	return v
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	if strings.HasPrefix(query, "PRAGMA user_version = ") {
+		c.db.version = 1
+	}
+	c.db.statements = append(c.db.statements, statement{query, values(args)})
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	if query == "PRAGMA user_version" {
+		return &fakeRows{columns: 1, rows: [][]driver.Value{{c.db.version}}}, nil
+	}
+	c.db.queries = append(c.db.queries, statement{query, values(args)})
+	return &fakeRows{columns: 9, rows: c.db.rows}, nil
+}
+
+type fakeRows struct {
+	columns int
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return make([]string, r.columns) }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func testStore(t *testing.T, opts ...Option) (*fakeDB, *Store) {
+	t.Helper()
+
+	fake := &fakeDB{}
+	db := sql.OpenDB(fake)
+	t.Cleanup(func() { db.Close() })
+
+	return fake, New(db, opts...)
+}
+
+func TestMigrate(t *testing.T) {
+	fake, s := testStore(t)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := s.Migrate(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// the second migration only sets the version
+	if want := len(migrations[0]) + 2; len(fake.statements) != want {
+		t.Fatalf("expected %d statements, got %d", want, len(fake.statements))
+	}
+}
+
+func TestStoreDeliver(t *testing.T) {
+	fake, s := testStore(t, WithCapacity(10), WithMaxEvents(50))
+	ctx := context.Background()
+
+	payload := json.RawMessage(`{"position":{"type":"Point","coordinates":[3,4]},"provider_id":"tag-1","provider_type":"uwb","source":"tag-1","trackables":["` + forklift.String() + `"],"floor":1,"timestamp_generated":"2024-05-01T10:00:00Z"}`)
+	if err := s.Deliver(ctx, forward.Event{Topic: omlox.TopicLocationUpdates, Key: forklift.String(), Payload: payload}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	event := json.RawMessage(`{"id":"0e1d2c3b-4a59-4867-9a5b-6c7d8e9f0a1b","fence_id":"3a1f0c9e-7d2b-4e6f-8a5c-1b2d3e4f5a66","event_type":"region_entry"}`)
+	if err := s.Deliver(ctx, forward.Event{Topic: omlox.TopicFenceEvents, Key: forklift.String(), Payload: event}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC).UnixMilli()
+	want := [][]driver.Value{
+		{forklift.String(), "tag-1", omlox.CrsLocal, 3.0, 4.0, 0.0, 1.0, nil, at},
+		{forklift.String(), forklift.String(), int64(10)},
+		{"0e1d2c3b-4a59-4867-9a5b-6c7d8e9f0a1b", "fence_events", forklift.String()},
+		{int64(50)},
+	}
+	if len(fake.statements) != len(want) {
+		t.Fatalf("expected %d statements, got %d", len(want), len(fake.statements))
+	}
+	for i, w := range want {
+		got := fake.statements[i].args[:len(w)]
+		if diff := cmp.Diff(w, got); diff != "" {
+			t.Errorf("unexpected arguments of statement %d (-want +got):\n%s", i, diff)
+		}
+	}
+}
+
+func TestNearby(t *testing.T) {
+	fake, s := testStore(t)
+	ctx := context.Background()
+
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	fake.rows = [][]driver.Value{
+		{"far", "far", omlox.CrsLocal, 7.0, 7.0, 0.0, 0.0, nil, at.UnixMilli()},
+		{forklift.String(), "tag-1", omlox.CrsLocal, 3.0, 4.0, 0.0, 0.0, 0.5, at.UnixMilli()},
+		{"near", "near", omlox.CrsLocal, 1.0, 1.0, 0.0, 0.0, nil, at.UnixMilli()},
+	}
+
+	positions, err := s.Nearby(ctx, "", geometry.Point{}, 5, at)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var keys []string
+	for _, p := range positions {
+		keys = append(keys, p.Key)
+	}
+	if diff := cmp.Diff([]string{"near", forklift.String()}, keys); diff != "" {
+		t.Errorf("unexpected positions (-want +got):\n%s", diff)
+	}
+	if got := positions[1].Location; len(got.Trackables) != 1 || got.Trackables[0] != forklift || *got.Accuracy != 0.5 {
+		t.Errorf("unexpected location: %+v", got)
+	}
+
+	if diff := cmp.Diff([]driver.Value{at.UnixMilli(), omlox.CrsLocal, -5.0, 5.0, -5.0, 5.0}, fake.queries[0].args); diff != "" {
+		t.Errorf("unexpected bounding box (-want +got):\n%s", diff)
+	}
+}
+
+func TestInFence(t *testing.T) {
+	fake, s := testStore(t)
+	ctx := context.Background()
+
+	fence := omlox.Fence{Region: omlox.NewRegionPoint(geometry.Point{X: 8.5, Y: 47.25}), Radius: 100}
+
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	fake.rows = [][]driver.Value{
+		{"inside", "inside", omlox.CrsWGS84, 8.5005, 47.2501, 0.0, 0.0, nil, at.UnixMilli()},
+		{"outside", "outside", omlox.CrsWGS84, 8.5012, 47.2508, 0.0, 0.0, nil, at.UnixMilli()},
+	}
+
+	positions, err := s.InFence(ctx, fence, at)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(positions) != 1 || positions[0].Key != "inside" {
+		t.Errorf("expected the inside position, got %+v", positions)
+	}
+	if crs := fake.queries[0].args[1]; crs != omlox.CrsWGS84 {
+		t.Errorf("expected a WGS84 query, got %v", crs)
+	}
+}
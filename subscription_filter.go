@@ -0,0 +1,209 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Subscription parameters restricting the events to trackables and location providers.
+const (
+	paramTrackableIDs = "trackable_ids"
+	paramProviderIDs  = "provider_ids"
+)
+
+// WithTrackables restricts a subscription to events of the given trackables.
+// The filter is sent to the Hub when it supports it, and applied by the client otherwise.
+func WithTrackables(ids ...uuid.UUID) Parameter {
+	return func(_ Topic, params Parameters) error {
+		if len(ids) == 0 {
+			return errors.New("trackable filter requires at least one trackable id")
+		}
+
+		values := make([]string, 0, len(ids))
+		for _, id := range ids {
+			values = append(values, id.String())
+		}
+
+		params[paramTrackableIDs] = joinParam(params[paramTrackableIDs], values)
+		return nil
+	}
+}
+
+// WithProviders restricts a subscription to events of the given location providers.
+// The filter is sent to the Hub when it supports it, and applied by the client otherwise.
+func WithProviders(ids ...string) Parameter {
+	return func(_ Topic, params Parameters) error {
+		if len(ids) == 0 {
+			return errors.New("provider filter requires at least one provider id")
+		}
+
+		for _, id := range ids {
+			if id == "" || strings.Contains(id, ",") {
+				return errors.New("invalid provider id in provider filter")
+			}
+		}
+
+		params[paramProviderIDs] = joinParam(params[paramProviderIDs], ids)
+		return nil
+	}
+}
+
+func joinParam(current string, values []string) string {
+	if current != "" {
+		values = append([]string{current}, values...)
+	}
+	return strings.Join(values, ",")
+}
+
+// SubscriptionFilter restricts the payloads delivered to a subscription to the
+// trackables and location providers of its parameters, such as to filter the events
+// re-broadcast to the subscribers of a proxy. A payload must match every configured filter.
+type SubscriptionFilter struct {
+	trackables map[uuid.UUID]struct{}
+	providers  map[string]struct{}
+}
+
+// NewSubscriptionFilter returns the filter configured in the parameters, or nil if none.
+func NewSubscriptionFilter(params Parameters) (*SubscriptionFilter, error) {
+	var f SubscriptionFilter
+
+	if v, ok := params[paramTrackableIDs]; ok {
+		f.trackables = make(map[uuid.UUID]struct{})
+		for _, s := range strings.Split(v, ",") {
+			id, err := uuid.Parse(s)
+			if err != nil {
+				return nil, err
+			}
+			f.trackables[id] = struct{}{}
+		}
+	}
+
+	if v, ok := params[paramProviderIDs]; ok {
+		f.providers = make(map[string]struct{})
+		for _, id := range strings.Split(v, ",") {
+			f.providers[id] = struct{}{}
+		}
+	}
+
+	if f.trackables == nil && f.providers == nil {
+		return nil, nil
+	}
+
+	return &f, nil
+}
+
+// filterReferences are the identifiers referenced by the event payloads of the
+// subscription topics. Trackable motions are identified by their trackable id.
+type filterReferences struct {
+	ID          *uuid.UUID  `json:"id"`
+	ProviderID  string      `json:"provider_id"`
+	TrackableID *uuid.UUID  `json:"trackable_id"`
+	Trackables  []uuid.UUID `json:"trackables"`
+	Location    *struct {
+		ProviderID string      `json:"provider_id"`
+		Trackables []uuid.UUID `json:"trackables"`
+	} `json:"location"`
+}
+
+// match reports whether a payload of a topic passes the filter.
+// Payloads which do not reference any of the filtered ids never match.
+func (f *SubscriptionFilter) match(topic Topic, payload json.RawMessage) bool {
+	var refs filterReferences
+	if err := json.Unmarshal(payload, &refs); err != nil {
+		return false
+	}
+
+	if f.providers != nil {
+		providers := []string{refs.ProviderID}
+		if refs.Location != nil {
+			providers = append(providers, refs.Location.ProviderID)
+		}
+		if !containsAny(f.providers, providers) {
+			return false
+		}
+	}
+
+	if f.trackables != nil {
+		trackables := refs.Trackables
+		if refs.TrackableID != nil {
+			trackables = append(trackables, *refs.TrackableID)
+		}
+		if refs.ID != nil && topic == TopicTrackableMotions {
+			trackables = append(trackables, *refs.ID)
+		}
+		if refs.Location != nil {
+			trackables = append(trackables, refs.Location.Trackables...)
+		}
+		if !containsAny(f.trackables, trackables) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Apply returns the message with only the matching payloads, or nil if none matches.
+// A nil filter matches every payload.
+func (f *SubscriptionFilter) Apply(msg *WrapperObject) *WrapperObject {
+	if f == nil || len(msg.Payload) == 0 {
+		return msg
+	}
+
+	payload := make([]json.RawMessage, 0, len(msg.Payload))
+	for _, p := range msg.Payload {
+		if f.match(msg.Topic, p) {
+			payload = append(payload, p)
+		}
+	}
+
+	switch len(payload) {
+	case 0:
+		return nil
+	case len(msg.Payload):
+		return msg
+	}
+
+	filtered := *msg
+	filtered.Payload = payload
+	return &filtered
+}
+
+func containsAny[K comparable](set map[K]struct{}, keys []K) bool {
+	for _, k := range keys {
+		if _, ok := set[k]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// hubParameters returns the parameters to send to the Hub for a subscription,
+// leaving out the filters the Hub does not support.
+func (c *Client) hubParameters(ctx context.Context, params Parameters) Parameters {
+	_, trackables := params[paramTrackableIDs]
+	_, providers := params[paramProviderIDs]
+	if !trackables && !providers {
+		return params
+	}
+
+	// filters are only sent to Hubs known to support them, as others may reject the subscription
+	if info := c.hubInfo(ctx); info != nil && info.Version != "" && info.Supports(FeatureSubscriptionFilters) {
+		return params
+	}
+
+	hubParams := make(Parameters, len(params))
+	for k, v := range params {
+		if k != paramTrackableIDs && k != paramProviderIDs {
+			hubParams[k] = v
+		}
+	}
+
+	return hubParams
+}
@@ -0,0 +1,156 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestSubscriptionFilter(t *testing.T) {
+	agv := uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+	tag := uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+
+	tests := []struct {
+		name    string
+		params  []Parameter
+		topic   Topic
+		payload []string
+		want    []string
+	}{
+		{
+			name:    "trackables",
+			params:  []Parameter{WithTrackables(agv)},
+			topic:   TopicLocationUpdates,
+			payload: []string{`{"provider_id":"p1","trackables":["` + agv.String() + `"]}`, `{"provider_id":"p2","trackables":["` + tag.String() + `"]}`},
+			want:    []string{`{"provider_id":"p1","trackables":["` + agv.String() + `"]}`},
+		},
+		{
+			name:    "providers",
+			params:  []Parameter{WithProviders("p2")},
+			topic:   TopicLocationUpdates,
+			payload: []string{`{"provider_id":"p1"}`, `{"provider_id":"p2"}`},
+			want:    []string{`{"provider_id":"p2"}`},
+		},
+		{
+			name:    "trackables and providers",
+			params:  []Parameter{WithTrackables(agv), WithProviders("p2")},
+			topic:   TopicLocationUpdates,
+			payload: []string{`{"provider_id":"p1","trackables":["` + agv.String() + `"]}`, `{"provider_id":"p2","trackables":["` + agv.String() + `"]}`},
+			want:    []string{`{"provider_id":"p2","trackables":["` + agv.String() + `"]}`},
+		},
+		{
+			name:    "fence event trackable",
+			params:  []Parameter{WithTrackables(agv)},
+			topic:   TopicFenceEvents,
+			payload: []string{`{"trackable_id":"` + agv.String() + `","event_type":"region_entry"}`},
+			want:    []string{`{"trackable_id":"` + agv.String() + `","event_type":"region_entry"}`},
+		},
+		{
+			name:    "trackable motion",
+			params:  []Parameter{WithTrackables(tag)},
+			topic:   TopicTrackableMotions,
+			payload: []string{`{"id":"` + agv.String() + `"}`, `{"id":"` + tag.String() + `","location":{"provider_id":"p1"}}`},
+			want:    []string{`{"id":"` + tag.String() + `","location":{"provider_id":"p1"}}`},
+		},
+		{
+			name:    "nothing matches",
+			params:  []Parameter{WithProviders("p3")},
+			topic:   TopicLocationUpdates,
+			payload: []string{`{"provider_id":"p1"}`},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			params := make(Parameters)
+			for _, p := range tc.params {
+				if err := p(tc.topic, params); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			f, err := NewSubscriptionFilter(params)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			msg := &WrapperObject{Event: EventMsg, Topic: tc.topic}
+			for _, p := range tc.payload {
+				msg.Payload = append(msg.Payload, json.RawMessage(p))
+			}
+
+			got := f.Apply(msg)
+			if tc.want == nil {
+				if got != nil {
+					t.Fatalf("expected message to be dropped, got %+v", got)
+				}
+				return
+			}
+
+			if got == nil || len(got.Payload) != len(tc.want) {
+				t.Fatalf("expected %d payloads, got %+v", len(tc.want), got)
+			}
+			for i, p := range got.Payload {
+				if string(p) != tc.want[i] {
+					t.Errorf("payload %d: expected %s, got %s", i, tc.want[i], p)
+				}
+			}
+		})
+	}
+}
+
+func TestSubscriptionFilterHubParameters(t *testing.T) {
+	tests := []struct {
+		name string
+		info string
+		want bool
+	}{
+		{name: "supported", info: `{"version":"1.1.0"}`, want: true},
+		{name: "not advertised", info: `{"version":"1.1.0","extensions":["history"]}`},
+		{name: "no information"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			httpClient := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					status := http.StatusOK
+					if tc.info == "" {
+						status = http.StatusNotFound
+					}
+					return &http.Response{
+						StatusCode: status,
+						Header:     http.Header{"Content-Type": []string{"application/json"}},
+						Body:       io.NopCloser(strings.NewReader(tc.info)),
+					}, nil
+				}),
+			}
+
+			c, err := New("http://localhost:8081/v2", WithHTTPClient(httpClient))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			params := Parameters{"crs": "local"}
+			if err := WithProviders("p1")(TopicLocationUpdates, params); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := c.hubParameters(context.Background(), params)
+			if _, ok := got[paramProviderIDs]; ok != tc.want {
+				t.Errorf("expected provider filter sent to the hub: %v, got %v", tc.want, got)
+			}
+			if got["crs"] != "local" {
+				t.Errorf("expected other parameters to be kept, got %v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+// Package tiny is a reduced omlox™ client for microcontroller-class gateways, such as
+// boards programmed with TinyGo, feeding the positions they compute to the Hub.
+//
+// It only pushes location updates over the REST API, with the minimal models they need.
+// Unlike the omlox package, it depends on the standard library alone, encodes the models
+// by hand rather than by reflection, and starts no goroutines, so it compiles under the
+// TinyGo constraints. Requests are sent with the net/http client of the target, such as
+// one dialing with the netdev drivers of TinyGo.
+//
+// A Client reuses its encoding buffer, and is not safe for concurrent use.
+package tiny
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Well-known coordinate reference systems.
+const (
+	// CrsLocal is the projection of coordinates relative to the floor plan of a zone.
+	CrsLocal = "local"
+
+	// CrsWGS84 is the World Geodetic System 1984 projection (EPSG:4326), as used by GPS.
+	CrsWGS84 = "EPSG:4326"
+)
+
+// ProviderType is the type of a location provider.
+type ProviderType string
+
+// Defines values for ProviderType.
+const (
+	ProviderTypeUnknown ProviderType = "unknown"
+	ProviderTypeUWB     ProviderType = "uwb"
+	ProviderTypeGPS     ProviderType = "gps"
+	ProviderTypeWiFi    ProviderType = "wifi"
+	ProviderTypeRFID    ProviderType = "rfid"
+	ProviderTypeIBeacon ProviderType = "ibeacon"
+	ProviderTypeVirtual ProviderType = "virtual"
+)
+
+// Point is a position in 2 or 3 dimensions, in the coordinate reference system of its
+// location: x, y and z, or longitude, latitude and elevation.
+type Point struct {
+	X, Y float64
+
+	// Z is the elevation, sent if HasZ is set.
+	Z    float64
+	HasZ bool
+}
+
+// Location is a location update of a location provider, the subset of the omlox™
+// Location model sent by gateways.
+type Location struct {
+	// Position of the provider.
+	Position Point
+
+	// Source is the id of the RTLS system (zone_id or foreign_id) which generated the
+	// location, or the id of a self-localizing provider.
+	Source string
+
+	// ProviderType is the type of the provider.
+	ProviderType ProviderType
+
+	// ProviderID is the unique identifier of the provider, e.g. the mac address of a UWB tag.
+	ProviderID string
+
+	// Crs is the projection of the position. Default: local
+	Crs string
+
+	// Accuracy is the horizontal accuracy of the location in meters, sent if positive.
+	Accuracy float64
+
+	// Floor is the logical floor of the location, sent if not zero.
+	Floor float64
+
+	// TimestampGenerated is the time the location was calculated, sent if not zero.
+	TimestampGenerated time.Time
+}
+
+// ErrInvalidNumber is returned for locations with infinite or NaN coordinates, which
+// are not representable in JSON.
+var ErrInvalidNumber = errors.New("invalid number")
+
+// AppendJSON appends the JSON encoding of the location to the buffer.
+func (l Location) AppendJSON(b []byte) ([]byte, error) {
+	b = append(b, `{"position":{"type":"Point","coordinates":[`...)
+	b, err := appendFloat(b, l.Position.X)
+	if err != nil {
+		return nil, err
+	}
+	b = append(b, ',')
+	if b, err = appendFloat(b, l.Position.Y); err != nil {
+		return nil, err
+	}
+	if l.Position.HasZ {
+		b = append(b, ',')
+		if b, err = appendFloat(b, l.Position.Z); err != nil {
+			return nil, err
+		}
+	}
+	b = append(b, "]}"...)
+
+	b = appendField(b, "source", l.Source)
+	providerType := l.ProviderType
+	if providerType == "" {
+		providerType = ProviderTypeUnknown
+	}
+	b = appendField(b, "provider_type", string(providerType))
+	b = appendField(b, "provider_id", l.ProviderID)
+
+	if !l.TimestampGenerated.IsZero() {
+		b = append(b, `,"timestamp_generated":"`...)
+		b = l.TimestampGenerated.UTC().AppendFormat(b, "2006-01-02T15:04:05.000Z07:00")
+		b = append(b, '"')
+	}
+	if l.Crs != "" {
+		b = appendField(b, "crs", l.Crs)
+	}
+	if l.Accuracy > 0 {
+		b = append(b, `,"accuracy":`...)
+		if b, err = appendFloat(b, l.Accuracy); err != nil {
+			return nil, err
+		}
+	}
+	if l.Floor != 0 {
+		b = append(b, `,"floor":`...)
+		if b, err = appendFloat(b, l.Floor); err != nil {
+			return nil, err
+		}
+	}
+
+	return append(b, '}'), nil
+}
+
+// appendField appends a string field of an object, after a previous field.
+func appendField(b []byte, name, value string) []byte {
+	b = append(b, ',', '"')
+	b = append(b, name...)
+	b = append(b, '"', ':')
+	return appendString(b, value)
+}
+
+// appendString appends a JSON string.
+func appendString(b []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+
+	b = append(b, '"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' || c == '\\':
+			b = append(b, '\\', c)
+		case c < 0x20:
+			b = append(b, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+		default:
+			b = append(b, c)
+		}
+	}
+	return append(b, '"')
+}
+
+// appendFloat appends a JSON number.
+func appendFloat(b []byte, f float64) ([]byte, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, ErrInvalidNumber
+	}
+	return strconv.AppendFloat(b, f, 'f', -1, 64), nil
+}
+
+// Error is the error returned when the Hub responds with an HTTP status code outside of
+// the 200 - 399 range.
+type Error struct {
+	// Code is the HTTP status code.
+	Code int
+
+	// Message is the beginning of the response body.
+	Message string
+}
+
+func (err *Error) Error() string {
+	return "omlox hub responded with status " + strconv.Itoa(err.Code) + ": " + err.Message
+}
+
+// maxErrorMessage is the length of the response bodies kept in errors.
+const maxErrorMessage = 256
+
+// Option is a configuration option of a client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client sending the requests.
+//
+// Default: http.DefaultClient
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.http = client
+	}
+}
+
+// WithToken sets the bearer token authenticating the requests with the Hub.
+//
+// Default: ""
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// Client pushes location updates to an omlox™ Hub.
+type Client struct {
+	base  string
+	http  *http.Client
+	token string
+
+	// buffer of the request bodies, reused across requests
+	buf []byte
+}
+
+// New returns a client of the Hub at the address, such as "http://hub:8081/v2".
+func New(addr string, opts ...Option) *Client {
+	c := &Client{
+		base: strings.TrimSuffix(addr, "/"),
+		http: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// PushLocation sets the location of its location provider.
+func (c *Client) PushLocation(ctx context.Context, location Location) error {
+	var err error
+	if c.buf, err = location.AppendJSON(c.buf[:0]); err != nil {
+		return err
+	}
+
+	return c.put(ctx, "/providers/"+url.PathEscape(location.ProviderID)+"/location")
+}
+
+// PushLocations sets the locations of several location providers with a single request.
+func (c *Client) PushLocations(ctx context.Context, locations ...Location) error {
+	var err error
+	c.buf = append(c.buf[:0], '[')
+	for i, l := range locations {
+		if i > 0 {
+			c.buf = append(c.buf, ',')
+		}
+		if c.buf, err = l.AppendJSON(c.buf); err != nil {
+			return err
+		}
+	}
+	c.buf = append(c.buf, ']')
+
+	return c.put(ctx, "/providers/locations")
+}
+
+// put sends the buffer to the path of the Hub.
+func (c *Client) put(ctx context.Context, path string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.base+path, bytes.NewReader(c.buf))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 399 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorMessage))
+		return &Error{Code: resp.StatusCode, Message: string(msg)}
+	}
+
+	// drain the body, so the connection is reused
+	_, err = io.Copy(io.Discard, resp.Body)
+	return err
+}
@@ -0,0 +1,117 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package tiny
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLocationAppendJSON(t *testing.T) {
+	testCases := []struct {
+		name     string
+		location Location
+		want     string
+	}{
+		{
+			name:     "minimal",
+			location: Location{Position: Point{X: 1.5, Y: -2}, Source: "hall", ProviderID: "a1"},
+			want:     `{"position":{"type":"Point","coordinates":[1.5,-2]},"source":"hall","provider_type":"unknown","provider_id":"a1"}`,
+		},
+		{
+			name: "complete",
+			location: Location{
+				Position:           Point{X: 8.5, Y: 47.25, Z: 410, HasZ: true},
+				Source:             "gw-\"north\"\n",
+				ProviderType:       ProviderTypeGPS,
+				ProviderID:         "77:4f:34:69:27:40",
+				Crs:                CrsWGS84,
+				Accuracy:           2.5,
+				Floor:              -1,
+				TimestampGenerated: time.Date(2024, 3, 1, 8, 0, 0, 123e6, time.FixedZone("CET", 3600)),
+			},
+			want: `{"position":{"type":"Point","coordinates":[8.5,47.25,410]},"source":"gw-\"north\"\u000a","provider_type":"gps","provider_id":"77:4f:34:69:27:40","timestamp_generated":"2024-03-01T07:00:00.123Z","crs":"EPSG:4326","accuracy":2.5,"floor":-1}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.location.AppendJSON(nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("expected %s, got %s", tc.want, got)
+			}
+			if !json.Valid(got) {
+				t.Errorf("invalid JSON: %s", got)
+			}
+		})
+	}
+
+	if _, err := (Location{Position: Point{X: math.NaN()}}).AppendJSON(nil); !errors.Is(err, ErrInvalidNumber) {
+		t.Errorf("expected an invalid number error, got %v", err)
+	}
+}
+
+func TestPushLocations(t *testing.T) {
+	type request struct {
+		method, path, auth string
+		body               []map[string]any
+	}
+	var got []request
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected content type %q", r.Header.Get("Content-Type"))
+		}
+
+		req := request{method: r.Method, path: r.URL.EscapedPath(), auth: r.Header.Get("Authorization")}
+		if body[0] != '[' {
+			body = append(append([]byte{'['}, body...), ']')
+		}
+		if err := json.Unmarshal(body, &req.body); err != nil {
+			t.Errorf("invalid body %s: %v", body, err)
+		}
+		got = append(got, req)
+
+		if r.URL.Path == "/v2/providers/unknown/location" {
+			http.Error(w, `{"type":"not found","code":404,"message":"unknown provider"}`, http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL+"/v2/", WithToken("secret"))
+	ctx := context.Background()
+
+	if err := c.PushLocation(ctx, Location{Position: Point{X: 1, Y: 2}, Source: "hall", ProviderID: "a/1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.PushLocations(ctx, Location{ProviderID: "a1"}, Location{ProviderID: "a2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var herr *Error
+	if err := c.PushLocation(ctx, Location{ProviderID: "unknown"}); !errors.As(err, &herr) || herr.Code != http.StatusNotFound {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(got))
+	}
+	if r := got[0]; r.method != http.MethodPut || r.path != "/v2/providers/a%2F1/location" || r.auth != "Bearer secret" || r.body[0]["provider_id"] != "a/1" {
+		t.Errorf("unexpected request: %+v", r)
+	}
+	if r := got[1]; r.path != "/v2/providers/locations" || len(r.body) != 2 || r.body[1]["provider_id"] != "a2" {
+		t.Errorf("unexpected request: %+v", r)
+	}
+}
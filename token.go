@@ -0,0 +1,254 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+// tokenRefreshMargin is how long before their expiry tokens are refreshed.
+const tokenRefreshMargin = time.Minute
+
+// Token is a bearer token authenticating with the Hub.
+type Token struct {
+	// Value of the token, sent in the Authorization header.
+	Value string
+
+	// Expiry of the token, zero if it doesn't expire or is unknown.
+	Expiry time.Time
+}
+
+// TokenSource supplies the tokens authenticating with the Hub.
+type TokenSource interface {
+	// Token returns a valid token, obtaining a new one if needed.
+	Token(ctx context.Context) (*Token, error)
+}
+
+// TokenSourceFunc is an adapter to use ordinary functions as token sources, such as
+// a function returning the tokens of an oauth2.TokenSource.
+type TokenSourceFunc func(ctx context.Context) (*Token, error)
+
+// Token implements TokenSource.
+func (f TokenSourceFunc) Token(ctx context.Context) (*Token, error) {
+	return f(ctx)
+}
+
+// StaticToken returns a source of a fixed token, which can't be refreshed. The
+// expiry of JWT tokens is read from their "exp" claim, without verifying them.
+func StaticToken(value string) TokenSource {
+	return staticToken{Value: value, Expiry: jwtExpiry(value)}
+}
+
+type staticToken Token
+
+func (t staticToken) Token(context.Context) (*Token, error) {
+	token := Token(t)
+	return &token, nil
+}
+
+// jwtExpiry returns the expiry of a JWT token, zero if it is not a JWT or has no expiry.
+func jwtExpiry(value string) time.Time {
+	parts := strings.Split(value, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+
+	var claims struct {
+		Exp float64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(int64(claims.Exp), 0)
+}
+
+// ClientCredentials is a source of tokens obtained from an OAuth2 token endpoint
+// with the client credentials grant (RFC 6749, section 4.4).
+type ClientCredentials struct {
+	// TokenURL is the token endpoint.
+	TokenURL string
+
+	ClientID     string
+	ClientSecret string
+
+	// Scopes requested, if any.
+	Scopes []string
+
+	// HTTPClient requesting the tokens. Default: http.DefaultClient
+	HTTPClient *http.Client
+}
+
+// Token requests a new token from the token endpoint.
+func (cc *ClientCredentials) Token(ctx context.Context) (*Token, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(cc.Scopes) > 0 {
+		form.Set("scope", strings.Join(cc.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cc.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(cc.ClientID), url.QueryEscape(cc.ClientSecret))
+
+	client := cc.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+		Error       string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid token response (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || body.AccessToken == "" {
+		return nil, fmt.Errorf("token request failed (status %d): %s", resp.StatusCode, body.Error)
+	}
+
+	token := &Token{Value: body.AccessToken}
+	if body.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(body.ExpiresIn) * time.Second)
+	}
+
+	return token, nil
+}
+
+// cachedTokenSource reuses the tokens of a source until shortly before their
+// expiry, so tokens are refreshed before requests fail with them.
+type cachedTokenSource struct {
+	src   TokenSource
+	clock clock.Clock
+
+	mu    sync.Mutex
+	token *Token
+}
+
+func (c *cachedTokenSource) Token(ctx context.Context) (*Token, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != nil && (c.token.Expiry.IsZero() || c.clock.Now().Add(tokenRefreshMargin).Before(c.token.Expiry)) {
+		return c.token, nil
+	}
+
+	token, err := c.src.Token(ctx)
+	if err != nil {
+		// keep using a token still valid, as the source may recover
+		if c.token != nil && c.clock.Now().Before(c.token.Expiry) {
+			return c.token, nil
+		}
+		return nil, err
+	}
+	c.token = token
+
+	return token, nil
+}
+
+// invalidate drops the cached token if it is the given one, rejected by the Hub before
+// its expiry, so the next token is obtained from the source. It reports whether the
+// source can supply another token.
+func (c *cachedTokenSource) invalidate(value string) bool {
+	if _, static := c.src.(staticToken); static {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != nil && c.token.Value == value {
+		c.token = nil
+	}
+	return true
+}
+
+// Token returns the token authenticating the client, refreshing it when close to
+// its expiry, or nil if the client is not authenticated. Long-running programs
+// call it periodically to refresh the token ahead of the requests, and to warn
+// about tokens expiring.
+func (c *Client) Token(ctx context.Context) (*Token, error) {
+	if c.tokens == nil {
+		return nil, nil
+	}
+	return c.tokens.Token(ctx)
+}
+
+// authorization returns the Authorization header value of the requests, if any.
+func (c *Client) authorization(ctx context.Context) (string, error) {
+	if u := c.configuration.BasicAuth; u != nil {
+		password, _ := u.Password()
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(u.Username()+":"+password)), nil
+	}
+
+	token, err := c.Token(ctx)
+	if err != nil {
+		return "", fmt.Errorf("could not get token: %w", err)
+	}
+	if token == nil || token.Value == "" {
+		return "", nil
+	}
+	return "Bearer " + token.Value, nil
+}
+
+// authenticate sets the credentials of the client on the headers of a request or of the
+// websocket handshake, unless already set.
+func (c *Client) authenticate(ctx context.Context, header http.Header) error {
+	if key := c.configuration.APIKey; key != "" && header.Get(c.configuration.APIKeyHeader) == "" {
+		header.Set(c.configuration.APIKeyHeader, key)
+	}
+
+	if header.Get("Authorization") != "" {
+		return nil
+	}
+
+	auth, err := c.authorization(ctx)
+	if err != nil {
+		return err
+	}
+	if auth != "" {
+		header.Set("Authorization", auth)
+	}
+	return nil
+}
+
+// refreshAuthorization drops the token of a request rejected as unauthorized, given the
+// headers set by the caller and the headers sent, and reports whether the request can be
+// sent again with a new token. Authorization headers set by the caller, in the headers of
+// the request or the base headers, do not come from the token source and are not refreshed.
+func (c *Client) refreshAuthorization(headers, sent http.Header) bool {
+	if headers.Get("Authorization") != "" || c.configuration.BaseHeaders.Get("Authorization") != "" {
+		return false
+	}
+
+	value, ok := strings.CutPrefix(sent.Get("Authorization"), "Bearer ")
+	if c.tokens == nil || !ok {
+		return false
+	}
+	return c.tokens.invalidate(value)
+}
@@ -0,0 +1,250 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/wavecomtech/omlox-client-go/clock"
+	"nhooyr.io/websocket"
+)
+
+func TestStaticTokenExpiry(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"cli","exp":1700000000}`))
+
+	token, err := StaticToken("e30." + payload + ".sig").Token(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !token.Expiry.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("expected expiry from exp claim, got %v", token.Expiry)
+	}
+
+	token, _ = StaticToken("opaque").Token(context.Background())
+	if !token.Expiry.IsZero() {
+		t.Errorf("expected no expiry for opaque token, got %v", token.Expiry)
+	}
+}
+
+type countingTokenSource struct {
+	calls  int
+	expiry time.Duration
+	clock  clock.Clock
+	err    error
+}
+
+func (s *countingTokenSource) Token(context.Context) (*Token, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &Token{Value: fmt.Sprintf("token-%d", s.calls), Expiry: s.clock.Now().Add(s.expiry)}, nil
+}
+
+func TestCachedTokenSource(t *testing.T) {
+	clk := clock.NewFake(time.Unix(0, 0))
+	src := &countingTokenSource{expiry: 10 * time.Minute, clock: clk}
+	cached := &cachedTokenSource{src: src, clock: clk}
+
+	ctx := context.Background()
+	token := func() string {
+		t.Helper()
+		tok, err := cached.Token(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return tok.Value
+	}
+
+	if got := token(); got != "token-1" {
+		t.Errorf("expected token-1, got %q", got)
+	}
+
+	clk.Advance(5 * time.Minute)
+	if got := token(); got != "token-1" {
+		t.Errorf("expected cached token-1, got %q", got)
+	}
+
+	// within the refresh margin of the expiry
+	clk.Advance(4*time.Minute + 30*time.Second)
+	if got := token(); got != "token-2" {
+		t.Errorf("expected refreshed token-2, got %q", got)
+	}
+
+	// a failed refresh keeps the token until it expires
+	src.err = errors.New("unavailable")
+	clk.Advance(9*time.Minute + 30*time.Second)
+	if got := token(); got != "token-2" {
+		t.Errorf("expected token-2 while still valid, got %q", got)
+	}
+
+	clk.Advance(time.Minute)
+	if _, err := cached.Token(ctx); err == nil {
+		t.Errorf("expected error after expiry")
+	}
+}
+
+func TestClientCredentials(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, _ := r.BasicAuth()
+		if r.FormValue("grant_type") != "client_credentials" || id != "cli" || secret != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		w.Write([]byte(`{"access_token":"abc","token_type":"Bearer","expires_in":300}`))
+	}))
+	defer srv.Close()
+
+	cc := &ClientCredentials{TokenURL: srv.URL, ClientID: "cli", ClientSecret: "s3cret"}
+	token, err := cc.Token(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token.Value != "abc" {
+		t.Errorf("expected access token, got %q", token.Value)
+	}
+	if d := time.Until(token.Expiry); d <= 4*time.Minute || d > 5*time.Minute {
+		t.Errorf("unexpected expiry in %v", d)
+	}
+
+	cc.ClientSecret = "wrong"
+	if _, err := cc.Token(context.Background()); err == nil {
+		t.Errorf("expected error with invalid credentials")
+	}
+}
+
+func TestUnauthorizedRefresh(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Header.Get("Authorization")+" "+string(body))
+
+		// the first token is revoked before its expiry
+		if r.Header.Get("Authorization") == "Bearer token-1" || r.URL.Path == "/restricted" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"type":"unauthorized","code":401,"message":"token revoked"}`))
+			return
+		}
+		if r.URL.Path == "/ws/socket" {
+			conn, err := websocket.Accept(w, r, nil)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			conn.Close(websocket.StatusNormalClosure, "")
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	src := &countingTokenSource{expiry: time.Hour, clock: clock.System}
+	c, err := New(srv.URL, WithTokenSource(src))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	// requests rejected as unauthorized are sent again with a new token, with their body
+	if err := c.Do(ctx, http.MethodPost, "/trackables", map[string]string{"name": "dock"}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"Bearer token-1 {\"name\":\"dock\"}\n", "Bearer token-2 {\"name\":\"dock\"}\n"}
+	if diff := cmp.Diff(want, requests); diff != "" {
+		t.Errorf("unexpected requests (-want +got):\n%s", diff)
+	}
+
+	// tokens are refreshed once per request
+	requests = nil
+	if err := c.Do(ctx, http.MethodGet, "/restricted", nil, nil); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected an unauthorized error, got %v", err)
+	}
+	want = []string{"Bearer token-2 ", "Bearer token-3 "}
+	if diff := cmp.Diff(want, requests); diff != "" {
+		t.Errorf("unexpected requests (-want +got):\n%s", diff)
+	}
+
+	// fixed tokens are not refreshed
+	requests = nil
+	c, err = New(srv.URL, WithToken("token-1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Do(ctx, http.MethodGet, "/info", nil, nil); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected an unauthorized error, got %v", err)
+	}
+	if len(requests) != 1 {
+		t.Errorf("expected a single request, got %v", requests)
+	}
+
+	// nor are the tokens set by the caller
+	requests = nil
+	c, err = New(srv.URL, WithTokenSource(&countingTokenSource{expiry: time.Hour, clock: clock.System}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := c.do(ctx, http.MethodGet, "/info", nil, nil, http.Header{"Authorization": []string{"Bearer token-1"}})
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected an unauthorized error, got %v", err)
+	}
+	if resp != nil {
+		resp.Body.Close()
+	}
+	if len(requests) != 1 {
+		t.Errorf("expected a single request, got %v", requests)
+	}
+
+	// the token of the websocket handshake is refreshed too
+	src = &countingTokenSource{expiry: time.Hour, clock: clock.System}
+	c, err = New(srv.URL, WithTokenSource(src))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.Close()
+	if src.calls != 2 {
+		t.Errorf("expected 2 tokens, got %d", src.calls)
+	}
+}
+
+func TestAuthOptions(t *testing.T) {
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL, WithBasicAuth("gateway", "s3cret"), WithAPIKey("x-api-key", "k3y"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Do(context.Background(), http.MethodGet, "/info", nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r := http.Request{Header: header}
+	if user, password, ok := r.BasicAuth(); !ok || user != "gateway" || password != "s3cret" {
+		t.Errorf("unexpected basic auth %q", header.Get("Authorization"))
+	}
+	if key := header.Get("X-Api-Key"); key != "k3y" {
+		t.Errorf("expected the api key, got %q", key)
+	}
+
+	if _, err := New(srv.URL, WithBasicAuth("gateway", "s3cret"), WithToken("abc")); err == nil {
+		t.Errorf("expected an error with both basic auth and a token")
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrTrackableNotFound is returned by lookups without matching trackable.
+	ErrTrackableNotFound = errors.New("trackable not found")
+
+	// ErrAmbiguousTrackable is returned by lookups matching several trackables.
+	ErrAmbiguousTrackable = errors.New("several trackables match")
+)
+
+// GetByName gets the trackable with the given name.
+// Names are not unique in the Hub: ErrAmbiguousTrackable is returned, wrapped,
+// when several trackables share the name.
+func (c *TrackablesAPI) GetByName(ctx context.Context, name string) (*Trackable, error) {
+	trackables, err := c.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *Trackable
+	for i := range trackables {
+		if trackables[i].Name != name {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("trackable name %q: %w", name, ErrAmbiguousTrackable)
+		}
+		found = &trackables[i]
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("trackable name %q: %w", name, ErrTrackableNotFound)
+	}
+
+	return found, nil
+}
+
+// TrackableIndex maps the external references of trackables, such as ERP asset
+// numbers, to their Hub ids. References are stored in a property of the trackables,
+// either as a single value or as a list of aliases (e.g. {"asset": "A-1001"} or
+// {"asset": ["A-1001", "LEGACY-7"]}).
+//
+// The index is built from a single listing of the trackables and cached for its
+// time to live, so lookups do not scan the Hub. It is safe for concurrent use.
+type TrackableIndex struct {
+	api      *TrackablesAPI
+	property string
+	ttl      time.Duration
+
+	mu sync.Mutex
+	// hub ids by reference, with uuid.Nil for references of several trackables
+	ids map[string]uuid.UUID
+	// time of the last refresh
+	loaded time.Time
+}
+
+// Index returns an index of the trackables by the external references stored in
+// the given property. The index is refreshed on lookups once older than ttl;
+// a non-positive ttl keeps it until refreshed or invalidated.
+func (c *TrackablesAPI) Index(property string, ttl time.Duration) *TrackableIndex {
+	return &TrackableIndex{
+		api:      c,
+		property: property,
+		ttl:      ttl,
+	}
+}
+
+// Lookup returns the id of the trackable with the external reference.
+// Unknown references return ErrTrackableNotFound and references shared by
+// several trackables ErrAmbiguousTrackable, both wrapped.
+func (x *TrackableIndex) Lookup(ctx context.Context, ref string) (uuid.UUID, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if x.stale() {
+		if err := x.refresh(ctx); err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	id, ok := x.ids[ref]
+	switch {
+	case !ok:
+		return uuid.Nil, fmt.Errorf("trackable %s %q: %w", x.property, ref, ErrTrackableNotFound)
+	case id == uuid.Nil:
+		return uuid.Nil, fmt.Errorf("trackable %s %q: %w", x.property, ref, ErrAmbiguousTrackable)
+	}
+
+	return id, nil
+}
+
+// Refresh rebuilds the index from the trackables of the Hub.
+func (x *TrackableIndex) Refresh(ctx context.Context) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	return x.refresh(ctx)
+}
+
+// Invalidate drops the cached index, so the next lookup refreshes it.
+func (x *TrackableIndex) Invalidate() {
+	x.mu.Lock()
+	x.ids = nil
+	x.mu.Unlock()
+}
+
+// Len returns the number of indexed references.
+func (x *TrackableIndex) Len() int {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	return len(x.ids)
+}
+
+func (x *TrackableIndex) stale() bool {
+	if x.ids == nil {
+		return true
+	}
+	return x.ttl > 0 && x.api.client.timeSource().Now().Sub(x.loaded) >= x.ttl
+}
+
+func (x *TrackableIndex) refresh(ctx context.Context) error {
+	trackables, err := x.api.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	ids := make(map[string]uuid.UUID, len(trackables))
+	for _, t := range trackables {
+		for _, ref := range externalRefs(t.Properties, x.property) {
+			if id, ok := ids[ref]; ok && id != t.ID {
+				ids[ref] = uuid.Nil
+				continue
+			}
+			ids[ref] = t.ID
+		}
+	}
+
+	x.ids = ids
+	x.loaded = x.api.client.timeSource().Now()
+
+	return nil
+}
+
+// externalRefs returns the references stored in a property, given as a string,
+// a number, or a list of them. Other values are ignored.
+func externalRefs(properties json.RawMessage, property string) []string {
+	if len(properties) == 0 {
+		return nil
+	}
+
+	var props map[string]json.RawMessage
+	if err := json.Unmarshal(properties, &props); err != nil {
+		return nil
+	}
+
+	value, ok := props[property]
+	if !ok {
+		return nil
+	}
+
+	var values []json.RawMessage
+	if err := json.Unmarshal(value, &values); err != nil {
+		values = []json.RawMessage{value}
+	}
+
+	refs := make([]string, 0, len(values))
+	for _, v := range values {
+		var s string
+		if err := json.Unmarshal(v, &s); err == nil {
+			refs = append(refs, s)
+			continue
+		}
+
+		var n json.Number
+		if err := json.Unmarshal(v, &n); err == nil {
+			refs = append(refs, n.String())
+		}
+	}
+
+	return refs
+}
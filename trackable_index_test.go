@@ -0,0 +1,125 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+const indexedTrackables = `[
+	{"id":"2b5f4a7c-1d1e-4c7e-9d27-5f3b2f1a0001","type":"virtual","name":"forklift","properties":{"asset":"A-1001"}},
+	{"id":"2b5f4a7c-1d1e-4c7e-9d27-5f3b2f1a0002","type":"virtual","name":"pallet","properties":{"asset":["A-1002","LEGACY-7"]}},
+	{"id":"2b5f4a7c-1d1e-4c7e-9d27-5f3b2f1a0003","type":"virtual","name":"pallet","properties":{"asset":1003}},
+	{"id":"2b5f4a7c-1d1e-4c7e-9d27-5f3b2f1a0004","type":"virtual","name":"cart","properties":{"asset":"LEGACY-7"}},
+	{"id":"2b5f4a7c-1d1e-4c7e-9d27-5f3b2f1a0005","type":"virtual","name":"tag"}
+]`
+
+// newIndexClient returns a client serving the indexed trackables, counting the list requests.
+func newIndexClient(t *testing.T, requests *int, opts ...ClientOption) *Client {
+	t.Helper()
+
+	httpClient := &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			*requests++
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(indexedTrackables)),
+			}, nil
+		}),
+	}
+
+	c, err := New("http://localhost:8081/v2", append([]ClientOption{WithHTTPClient(httpClient)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestTrackablesGetByName(t *testing.T) {
+	var requests int
+	c := newIndexClient(t, &requests)
+	ctx := context.Background()
+
+	trackable, err := c.Trackables.GetByName(ctx, "forklift")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trackable.ID != uuid.MustParse("2b5f4a7c-1d1e-4c7e-9d27-5f3b2f1a0001") {
+		t.Errorf("unexpected trackable %v", trackable.ID)
+	}
+
+	if _, err := c.Trackables.GetByName(ctx, "pallet"); !errors.Is(err, ErrAmbiguousTrackable) {
+		t.Errorf("expected ErrAmbiguousTrackable, got %v", err)
+	}
+	if _, err := c.Trackables.GetByName(ctx, "crane"); !errors.Is(err, ErrTrackableNotFound) {
+		t.Errorf("expected ErrTrackableNotFound, got %v", err)
+	}
+}
+
+func TestTrackableIndex(t *testing.T) {
+	var requests int
+	clk := clock.NewFake(time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC))
+	c := newIndexClient(t, &requests, WithClock(clk))
+	ctx := context.Background()
+
+	index := c.Trackables.Index("asset", time.Minute)
+
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr error
+	}{
+		{ref: "A-1001", want: "2b5f4a7c-1d1e-4c7e-9d27-5f3b2f1a0001"},
+		{ref: "A-1002", want: "2b5f4a7c-1d1e-4c7e-9d27-5f3b2f1a0002"},
+		{ref: "1003", want: "2b5f4a7c-1d1e-4c7e-9d27-5f3b2f1a0003"},
+		{ref: "LEGACY-7", wantErr: ErrAmbiguousTrackable},
+		{ref: "A-9999", wantErr: ErrTrackableNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			id, err := index.Lookup(ctx, tt.ref)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Lookup() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && id != uuid.MustParse(tt.want) {
+				t.Errorf("Lookup() = %v, want %v", id, tt.want)
+			}
+		})
+	}
+
+	if requests != 1 {
+		t.Errorf("expected lookups to be served from a single listing, got %d requests", requests)
+	}
+	if n := index.Len(); n != 4 {
+		t.Errorf("expected 4 indexed references, got %d", n)
+	}
+
+	// expired indexes are refreshed
+	clk.Advance(time.Minute)
+	if _, err := index.Lookup(ctx, "A-1001"); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 {
+		t.Errorf("expected the expired index to be refreshed, got %d requests", requests)
+	}
+
+	index.Invalidate()
+	if _, err := index.Lookup(ctx, "A-1001"); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 3 {
+		t.Errorf("expected the invalidated index to be refreshed, got %d requests", requests)
+	}
+}
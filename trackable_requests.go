@@ -13,117 +13,53 @@ import (
 // TrackablesAPI is a simple wrapper around the client for trackables requests.
 type TrackablesAPI struct {
 	client *Client
+
+	service *Service[Trackable]
 }
 
 // List lists all trackables.
 func (c *TrackablesAPI) List(ctx context.Context) ([]Trackable, error) {
-	requestPath := "/trackables/summary"
-
-	return sendRequestParseResponseList[Trackable](
-		ctx,
-		c.client,
-		http.MethodGet,
-		requestPath,
-		nil, // request body
-		nil, // request query parameters
-		nil, // request headers
-	)
+	return c.service.List(ctx)
 }
 
 // IDs lists all trackable IDs.
 func (c *TrackablesAPI) IDs(ctx context.Context) ([]uuid.UUID, error) {
-	requestPath := "/trackables"
-
-	return sendRequestParseResponseList[uuid.UUID](
-		ctx,
-		c.client,
-		http.MethodGet,
-		requestPath,
-		nil, // request body
-		nil, // request query parameters
-		nil, // request headers
-	)
+	return listIDs[uuid.UUID](ctx, c.service)
 }
 
 // Create creates a trackable.
 func (c *TrackablesAPI) Create(ctx context.Context, trackable Trackable) (*Trackable, error) {
-	requestPath := "/trackables"
-
-	return sendStructuredRequestParseResponse[Trackable](
-		ctx,
-		c.client,
-		http.MethodPost,
-		requestPath,
-		trackable,
-		nil, // request query parameters
-		nil, // request headers
-	)
+	return c.service.Create(ctx, trackable)
 }
 
 // DeleteAll deletes all trackables.
 func (c *TrackablesAPI) DeleteAll(ctx context.Context) error {
-	requestPath := "/trackables"
-
-	_, err := sendRequestParseResponse[struct{}](
-		ctx,
-		c.client,
-		http.MethodDelete,
-		requestPath,
-		nil, // request body
-		nil, // request query parameters
-		nil, // request headers
-	)
-
-	return err
+	return c.service.DeleteAll(ctx)
 }
 
 // Get gets a trackable.
 func (c *TrackablesAPI) Get(ctx context.Context, id uuid.UUID) (*Trackable, error) {
-	requestPath := "/trackables/" + id.String()
+	return c.service.Get(ctx, id.String())
+}
 
-	return sendRequestParseResponse[Trackable](
-		ctx,
-		c.client,
-		http.MethodGet,
-		requestPath,
-		nil, // request body
-		nil, // request query parameters
-		nil, // request headers
-	)
+// GetMany gets the trackables of the given ids, in the order of the ids, sharing the
+// deadline of the context across the requests.
+func (c *TrackablesAPI) GetMany(ctx context.Context, ids []uuid.UUID) ([]Trackable, error) {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = id.String()
+	}
+	return c.service.GetMany(ctx, keys)
 }
 
 // Delete deletes a trackable.
 func (c *TrackablesAPI) Delete(ctx context.Context, id uuid.UUID) error {
-	requestPath := "/trackables/" + id.String()
-
-	_, err := sendRequestParseResponse[struct{}](
-		ctx,
-		c.client,
-		http.MethodDelete,
-		requestPath,
-		nil, // request body
-		nil, // request query parameters
-		nil, // request headers
-	)
-
-	return err
+	return c.service.Delete(ctx, id.String())
 }
 
 // Update updates a trackable.
 func (c *TrackablesAPI) Update(ctx context.Context, trackable Trackable, id uuid.UUID) error {
-	requestPath := "/trackables/" + id.String()
-
-	_, err := sendStructuredRequestParseResponse[struct{}](
-		ctx,
-		c.client,
-		http.MethodPut,
-		requestPath,
-		trackable,
-		nil, // request query parameters
-		nil, // request headers
-	)
-
-	return err
+	return c.service.Update(ctx, trackable, id.String())
 }
 
 // GetLocation gets the last most recent location for a trackable.
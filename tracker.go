@@ -0,0 +1,434 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+//go:build !omlox_minimal
+
+package omlox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tidwall/geoindex"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/tidwall/rtree"
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+// TrackedPosition is the last known location of a trackable.
+type TrackedPosition struct {
+	// TrackableID is the id of the trackable.
+	TrackableID uuid.UUID `json:"trackable_id"`
+
+	// Location is the most recent location of the trackable.
+	Location Location `json:"location"`
+
+	// At is the time of the location, or the time it was received if it has no timestamp.
+	At time.Time `json:"at"`
+}
+
+// Neighbor is a trackable found by a spatial query.
+type Neighbor struct {
+	TrackedPosition
+
+	// Distance is the distance in meters from the query position.
+	Distance float64 `json:"distance"`
+}
+
+// Tracker keeps the last known positions of trackables and the fences they are in,
+// fed by location updates and fence events. It is safe for concurrent use.
+type Tracker struct {
+	mu sync.RWMutex
+
+	positions map[uuid.UUID]*TrackedPosition
+
+	// fence membership of trackables, including exited fences to order late events
+	memberships map[fenceMembershipKey]*fenceMembership
+
+	// optional spatial index of the positions in the index crs
+	index    *geoindex.Index
+	indexCrs string
+
+	clock clock.Clock
+}
+
+// TrackerOption is a configuration option of a tracker.
+type TrackerOption func(*Tracker)
+
+// WithSpatialIndex maintains an in-memory R-tree of the positions in the given crs,
+// answering Nearest and WithinRadius queries. Positions in other crs are not indexed.
+// WGS84 (EPSG:4326) positions are indexed in longitude and latitude, other crs are
+// assumed to be in meters.
+func WithSpatialIndex(crs string) TrackerOption {
+	return func(t *Tracker) {
+		t.index = geoindex.Wrap(&rtree.RTree{})
+		t.indexCrs = crs
+	}
+}
+
+// WithTrackerClock sets the source of time of the updates without timestamp,
+// the snapshots and the persistence interval.
+//
+// Default: clock.System
+func WithTrackerClock(clk clock.Clock) TrackerOption {
+	return func(t *Tracker) {
+		t.clock = clk
+	}
+}
+
+// NewTracker returns a new tracker with the given options.
+func NewTracker(opts ...TrackerOption) *Tracker {
+	t := &Tracker{
+		positions:   make(map[uuid.UUID]*TrackedPosition),
+		memberships: make(map[fenceMembershipKey]*fenceMembership),
+		clock:       clock.System,
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+	t.clock = clock.Or(t.clock)
+
+	return t
+}
+
+// Update records the locations of their trackables.
+// Locations older than the known position of a trackable are ignored.
+func (t *Tracker) Update(locations ...Location) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.clock.Now()
+	for _, loc := range locations {
+		at := now
+		if loc.TimestampGenerated != nil {
+			at = *loc.TimestampGenerated
+		}
+
+		for _, id := range loc.Trackables {
+			t.update(id, loc, at)
+		}
+	}
+}
+
+// update records the location of a trackable. Must be called with the lock held.
+func (t *Tracker) update(id uuid.UUID, loc Location, at time.Time) {
+	old, ok := t.positions[id]
+	if ok && at.Before(old.At) {
+		return
+	}
+
+	if ok && t.indexed(old.Location) {
+		p := old.Location.Position.Base()
+		t.index.Delete([2]float64{p.X, p.Y}, [2]float64{p.X, p.Y}, id)
+	}
+
+	t.positions[id] = &TrackedPosition{
+		TrackableID: id,
+		Location:    loc,
+		At:          at,
+	}
+
+	if t.indexed(loc) {
+		p := loc.Position.Base()
+		t.index.Insert([2]float64{p.X, p.Y}, [2]float64{p.X, p.Y}, id)
+	}
+}
+
+// indexed reports whether a location is part of the spatial index.
+func (t *Tracker) indexed(loc Location) bool {
+	if t.index == nil {
+		return false
+	}
+
+	crs := loc.Crs
+	if crs == "" {
+		crs = CrsLocal
+	}
+
+	return crs == t.indexCrs
+}
+
+// Run feeds the tracker with a stream of location updates, such as the channel returned
+// by ReceiveAs[Location], until the channel is closed or the context is done.
+func (t *Tracker) Run(ctx context.Context, locations <-chan *Location) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case loc, ok := <-locations:
+			if !ok {
+				return nil
+			}
+			if loc != nil {
+				t.Update(*loc)
+			}
+		}
+	}
+}
+
+// Position returns the last known position of a trackable.
+func (t *Tracker) Position(id uuid.UUID) (TrackedPosition, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	p, ok := t.positions[id]
+	if !ok {
+		return TrackedPosition{}, false
+	}
+
+	return *p, true
+}
+
+// Positions returns the last known positions of all trackables, sorted by trackable id.
+func (t *Tracker) Positions() []TrackedPosition {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.sortedPositions()
+}
+
+// sortedPositions returns the positions sorted by trackable id. Must be called with the lock held.
+func (t *Tracker) sortedPositions() []TrackedPosition {
+	positions := make([]TrackedPosition, 0, len(t.positions))
+	for _, p := range t.positions {
+		positions = append(positions, *p)
+	}
+
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].TrackableID.String() < positions[j].TrackableID.String()
+	})
+
+	return positions
+}
+
+// Forget removes the position and fence membership of a trackable.
+func (t *Tracker) Forget(id uuid.UUID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for k := range t.memberships {
+		if k.trackableID == id {
+			delete(t.memberships, k)
+		}
+	}
+
+	old, ok := t.positions[id]
+	if !ok {
+		return
+	}
+
+	if t.indexed(old.Location) {
+		p := old.Location.Position.Base()
+		t.index.Delete([2]float64{p.X, p.Y}, [2]float64{p.X, p.Y}, id)
+	}
+
+	delete(t.positions, id)
+}
+
+// Nearest returns the k trackables closest to a position in the spatial index crs,
+// sorted by distance. It requires the tracker to be created WithSpatialIndex.
+func (t *Tracker) Nearest(p geometry.Point, k int) []Neighbor {
+	if k <= 0 {
+		return nil
+	}
+
+	return t.nearby(p, func(n []Neighbor, d float64) bool { return len(n) < k })
+}
+
+// WithinRadius returns the trackables within r meters of a position in the spatial
+// index crs, sorted by distance. It requires the tracker to be created WithSpatialIndex.
+func (t *Tracker) WithinRadius(p geometry.Point, r float64) []Neighbor {
+	return t.nearby(p, func(n []Neighbor, d float64) bool { return d <= r })
+}
+
+// WithinBounds returns the trackables within the minimum and maximum coordinates of a
+// crs, sorted by trackable id. The spatial index of a tracker created WithSpatialIndex of
+// the crs is used, otherwise all the positions are scanned.
+func (t *Tracker) WithinBounds(crs string, min, max geometry.Point) []TrackedPosition {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var found []TrackedPosition
+	if t.index != nil && t.indexCrs == crs {
+		t.index.Search([2]float64{min.X, min.Y}, [2]float64{max.X, max.Y}, func(_, _ [2]float64, data interface{}) bool {
+			found = append(found, *t.positions[data.(uuid.UUID)])
+			return true
+		})
+	} else {
+		for _, pos := range t.positions {
+			locCrs := pos.Location.Crs
+			if locCrs == "" {
+				locCrs = CrsLocal
+			}
+
+			p := pos.Location.Position.Base()
+			if locCrs == crs && p.X >= min.X && p.X <= max.X && p.Y >= min.Y && p.Y <= max.Y {
+				found = append(found, *pos)
+			}
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].TrackableID.String() < found[j].TrackableID.String()
+	})
+
+	return found
+}
+
+// nearby returns the indexed trackables from the closest to the position, while accept holds.
+func (t *Tracker) nearby(p geometry.Point, accept func(found []Neighbor, dist float64) bool) []Neighbor {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.index == nil {
+		return nil
+	}
+
+	sx, sy := metricScale(p, t.indexCrs)
+
+	// distance from the position to a box, a lower bound of the distance to its items
+	dist := func(min, max [2]float64, data interface{}, item bool) float64 {
+		dx := math.Max(0, math.Max(min[0]-p.X, p.X-max[0])) * sx
+		dy := math.Max(0, math.Max(min[1]-p.Y, p.Y-max[1])) * sy
+		return math.Hypot(dx, dy)
+	}
+
+	var found []Neighbor
+	t.index.Nearby(dist, func(min, max [2]float64, data interface{}, d float64) bool {
+		if !accept(found, d) {
+			return false
+		}
+
+		found = append(found, Neighbor{
+			TrackedPosition: *t.positions[data.(uuid.UUID)],
+			Distance:        d,
+		})
+		return true
+	})
+
+	return found
+}
+
+// TrackerSnapshot is the persisted state of a tracker.
+type TrackerSnapshot struct {
+	// TakenAt is the time the snapshot was taken.
+	TakenAt time.Time `json:"taken_at"`
+
+	// Positions are the last known positions of the trackables, sorted by trackable id.
+	Positions []TrackedPosition `json:"positions"`
+
+	// Fences are the fences the trackables are in, sorted by fence and trackable id.
+	Fences []FenceMembership `json:"fences,omitempty"`
+}
+
+// Snapshot returns a consistent snapshot of the tracker state.
+func (t *Tracker) Snapshot() TrackerSnapshot {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return TrackerSnapshot{
+		TakenAt:   t.clock.Now(),
+		Positions: t.sortedPositions(),
+		Fences:    t.sortedMemberships(func(fenceMembershipKey) bool { return true }),
+	}
+}
+
+// Restore merges a snapshot into the tracker state. Positions and fence memberships older
+// than the ones already known by the tracker are ignored, so restoring after updates were received is safe.
+func (t *Tracker) Restore(s TrackerSnapshot) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, p := range s.Positions {
+		t.update(p.TrackableID, p.Location, p.At)
+	}
+
+	for _, m := range s.Fences {
+		t.enter(m.FenceID, m.TrackableID, m.EntryTime)
+	}
+}
+
+// Save writes a snapshot of the tracker state to a file. The file is replaced
+// atomically, so a crash while saving never leaves a truncated snapshot behind.
+func (t *Tracker) Save(path string) error {
+	data, err := json.Marshal(t.Snapshot())
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Load restores the tracker state from a snapshot file written by Save.
+// A missing file is not an error, the tracker then starts empty.
+func (t *Tracker) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var s TrackerSnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid tracker snapshot '%s': %w", path, err)
+	}
+
+	t.Restore(s)
+	return nil
+}
+
+// Persist saves the tracker state to a file at every interval, and once more when
+// the context is done. It returns the first error saving the state.
+func (t *Tracker) Persist(ctx context.Context, path string, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid tracker persist interval: %v", interval)
+	}
+
+	ticker := t.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := t.Save(path); err != nil {
+				return err
+			}
+			return ctx.Err()
+		case <-ticker.C():
+			if err := t.Save(path); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+//go:build !omlox_minimal
+
+package omlox
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FenceMembership is the presence of a trackable in a fence.
+type FenceMembership struct {
+	// FenceID is the id of the fence.
+	FenceID uuid.UUID `json:"fence_id"`
+
+	// TrackableID is the id of the trackable inside the fence.
+	TrackableID uuid.UUID `json:"trackable_id"`
+
+	// EntryTime is the time the trackable entered the fence.
+	EntryTime time.Time `json:"entry_time"`
+}
+
+type fenceMembershipKey struct {
+	fenceID     uuid.UUID
+	trackableID uuid.UUID
+}
+
+// fenceMembership is the state of a trackable in a fence.
+type fenceMembership struct {
+	inside    bool
+	entryTime time.Time
+
+	// at is the time of the last event applied
+	at time.Time
+}
+
+// ObserveFenceEvents updates the fence membership of the trackables of the events.
+// Events older than the last event applied to a trackable and fence are ignored.
+// Events without time are applied at the current time.
+func (t *Tracker) ObserveFenceEvents(events ...FenceEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.clock.Now()
+	for _, e := range events {
+		at := e.Time()
+		if at.IsZero() {
+			at = now
+		}
+
+		trackables := e.Trackables
+		if e.TrackableID != nil {
+			trackables = append([]uuid.UUID{*e.TrackableID}, trackables...)
+		}
+
+		for _, id := range trackables {
+			switch e.EventType {
+			case FenceEventTypeRegionEntry:
+				t.enter(e.FenceID, id, at)
+			case FenceEventTypeRegionExit:
+				t.exit(e.FenceID, id, at)
+			}
+		}
+	}
+}
+
+// enter records a trackable entering a fence. Must be called with the lock held.
+func (t *Tracker) enter(fenceID, trackableID uuid.UUID, at time.Time) {
+	k := fenceMembershipKey{fenceID: fenceID, trackableID: trackableID}
+
+	m, ok := t.memberships[k]
+	if ok && at.Before(m.at) {
+		return
+	}
+
+	// repeated entries keep the time of the first one
+	if ok && m.inside {
+		m.at = at
+		return
+	}
+
+	t.memberships[k] = &fenceMembership{inside: true, entryTime: at, at: at}
+}
+
+// exit records a trackable leaving a fence. Must be called with the lock held.
+func (t *Tracker) exit(fenceID, trackableID uuid.UUID, at time.Time) {
+	k := fenceMembershipKey{fenceID: fenceID, trackableID: trackableID}
+
+	m, ok := t.memberships[k]
+	if ok && at.Before(m.at) {
+		return
+	}
+
+	t.memberships[k] = &fenceMembership{at: at}
+}
+
+// RunFenceEvents feeds the tracker with a stream of fence events, such as the channel
+// returned by ReceiveAs[FenceEvent], until the channel is closed or the context is done.
+func (t *Tracker) RunFenceEvents(ctx context.Context, events <-chan *FenceEvent) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if e != nil {
+				t.ObserveFenceEvents(*e)
+			}
+		}
+	}
+}
+
+// InFence returns the fences a trackable is in, sorted by fence id.
+func (t *Tracker) InFence(trackableID uuid.UUID) []FenceMembership {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.sortedMemberships(func(k fenceMembershipKey) bool { return k.trackableID == trackableID })
+}
+
+// FenceOccupancy returns the trackables inside a fence, sorted by trackable id.
+func (t *Tracker) FenceOccupancy(fenceID uuid.UUID) []FenceMembership {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.sortedMemberships(func(k fenceMembershipKey) bool { return k.fenceID == fenceID })
+}
+
+// sortedMemberships returns the selected memberships of trackables inside fences,
+// sorted by fence and trackable id. Must be called with the lock held.
+func (t *Tracker) sortedMemberships(selected func(fenceMembershipKey) bool) []FenceMembership {
+	var memberships []FenceMembership
+	for k, m := range t.memberships {
+		if !m.inside || !selected(k) {
+			continue
+		}
+
+		memberships = append(memberships, FenceMembership{
+			FenceID:     k.fenceID,
+			TrackableID: k.trackableID,
+			EntryTime:   m.entryTime,
+		})
+	}
+
+	sort.Slice(memberships, func(i, j int) bool {
+		if memberships[i].FenceID != memberships[j].FenceID {
+			return memberships[i].FenceID.String() < memberships[j].FenceID.String()
+		}
+		return memberships[i].TrackableID.String() < memberships[j].TrackableID.String()
+	})
+
+	return memberships
+}
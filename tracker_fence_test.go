@@ -0,0 +1,69 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+//go:build !omlox_minimal
+
+package omlox
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestTrackerFenceMembership(t *testing.T) {
+	dock := uuid.MustParse("00000000-0000-0000-0000-0000000000f1")
+	aisle := uuid.MustParse("00000000-0000-0000-0000-0000000000f2")
+	a := uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+	b := uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+
+	event := func(fenceID uuid.UUID, typ FenceEventType, at string, trackables ...uuid.UUID) FenceEvent {
+		e := FenceEvent{FenceID: fenceID, EventType: typ, Trackables: trackables}
+		if typ == FenceEventTypeRegionEntry {
+			e.EntryTime = mustParseTime(at)
+		} else {
+			e.ExitTime = mustParseTime(at)
+		}
+		return e
+	}
+
+	tr := NewTracker()
+	tr.ObserveFenceEvents(
+		event(dock, FenceEventTypeRegionEntry, "2024-03-01T08:00:00Z", a, b),
+		event(aisle, FenceEventTypeRegionEntry, "2024-03-01T08:00:01Z", a),
+		event(dock, FenceEventTypeRegionEntry, "2024-03-01T08:00:02Z", a),
+		event(dock, FenceEventTypeRegionExit, "2024-03-01T08:00:05Z", b),
+
+		// late entry, already superseded by the exit
+		event(dock, FenceEventTypeRegionEntry, "2024-03-01T08:00:03Z", b),
+	)
+
+	inFence := tr.InFence(a)
+	if len(inFence) != 2 || inFence[0].FenceID != dock || inFence[1].FenceID != aisle {
+		t.Fatalf("unexpected fences of a: %+v", inFence)
+	}
+	if !inFence[0].EntryTime.Equal(*mustParseTime("2024-03-01T08:00:00Z")) {
+		t.Errorf("expected repeated entries to keep the first entry time, got %v", inFence[0].EntryTime)
+	}
+
+	if got := tr.InFence(b); len(got) != 0 {
+		t.Errorf("expected b in no fence, got %+v", got)
+	}
+
+	occupancy := tr.FenceOccupancy(dock)
+	if len(occupancy) != 1 || occupancy[0].TrackableID != a {
+		t.Errorf("unexpected dock occupancy: %+v", occupancy)
+	}
+
+	// memberships are part of snapshots
+	restored := NewTracker()
+	restored.Restore(tr.Snapshot())
+	if got := restored.FenceOccupancy(aisle); len(got) != 1 || got[0].TrackableID != a {
+		t.Errorf("unexpected restored aisle occupancy: %+v", got)
+	}
+
+	tr.Forget(a)
+	if got := tr.FenceOccupancy(dock); len(got) != 0 {
+		t.Errorf("expected empty dock after forgetting a, got %+v", got)
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+//go:build !omlox_minimal
+
+package omlox
+
+import (
+	"context"
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go/clock"
+)
+
+func trackedLocation(id uuid.UUID, crs string, x, y float64, at string) Location {
+	return Location{
+		Position:           *NewPoint(geometry.Point{X: x, Y: y}),
+		Crs:                crs,
+		ProviderID:         "p-" + id.String()[:4],
+		Trackables:         []uuid.UUID{id},
+		TimestampGenerated: mustParseTime(at),
+	}
+}
+
+func TestTrackerUpdate(t *testing.T) {
+	id := uuid.MustParse("d27047bd-1b6b-4656-bb93-2326a4c900e1")
+
+	tr := NewTracker()
+	tr.Update(trackedLocation(id, CrsLocal, 1, 1, "2024-03-01T08:00:02Z"))
+	tr.Update(trackedLocation(id, CrsLocal, 5, 5, "2024-03-01T08:00:01Z"))
+
+	p, ok := tr.Position(id)
+	if !ok {
+		t.Fatal("expected a position")
+	}
+	if got := p.Location.Position.Base(); got != (geometry.Point{X: 1, Y: 1}) {
+		t.Errorf("out of order update applied, got position %v", got)
+	}
+
+	tr.Forget(id)
+	if _, ok := tr.Position(id); ok {
+		t.Error("expected position to be forgotten")
+	}
+	if n := len(tr.Positions()); n != 0 {
+		t.Errorf("expected no positions, got %d", n)
+	}
+}
+
+func TestTrackerRun(t *testing.T) {
+	id := uuid.MustParse("d27047bd-1b6b-4656-bb93-2326a4c900e1")
+
+	locations := make(chan *Location, 1)
+	loc := trackedLocation(id, CrsLocal, 1, 1, "2024-03-01T08:00:00Z")
+	locations <- &loc
+	close(locations)
+
+	tr := NewTracker()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := tr.Run(ctx, locations); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := tr.Position(id); !ok {
+		t.Error("expected a position")
+	}
+}
+
+func TestTrackerSpatialQueries(t *testing.T) {
+	a := uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+	b := uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+	c := uuid.MustParse("00000000-0000-0000-0000-00000000000c")
+	gps := uuid.MustParse("00000000-0000-0000-0000-00000000000d")
+
+	tr := NewTracker(WithSpatialIndex(CrsLocal))
+	tr.Update(
+		trackedLocation(a, CrsLocal, 1, 0, "2024-03-01T08:00:00Z"),
+		trackedLocation(b, "", 0, 3, "2024-03-01T08:00:00Z"),
+		trackedLocation(c, CrsLocal, 10, 10, "2024-03-01T08:00:00Z"),
+		trackedLocation(gps, CrsWGS84, 0, 0, "2024-03-01T08:00:00Z"),
+	)
+
+	// moving a trackable updates the index
+	tr.Update(trackedLocation(c, CrsLocal, 4, 0, "2024-03-01T08:00:01Z"))
+
+	tests := []struct {
+		name  string
+		query func() []Neighbor
+		want  []uuid.UUID
+		dists []float64
+	}{
+		{
+			name:  "nearest",
+			query: func() []Neighbor { return tr.Nearest(geometry.Point{}, 2) },
+			want:  []uuid.UUID{a, b},
+			dists: []float64{1, 3},
+		},
+		{
+			name:  "nearest more than indexed",
+			query: func() []Neighbor { return tr.Nearest(geometry.Point{}, 10) },
+			want:  []uuid.UUID{a, b, c},
+			dists: []float64{1, 3, 4},
+		},
+		{
+			name:  "within radius",
+			query: func() []Neighbor { return tr.WithinRadius(geometry.Point{X: 3}, 1.5) },
+			want:  []uuid.UUID{c},
+			dists: []float64{1},
+		},
+		{
+			name:  "within empty radius",
+			query: func() []Neighbor { return tr.WithinRadius(geometry.Point{X: 20}, 1) },
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.query()
+			if len(got) != len(tc.want) {
+				t.Fatalf("expected %d neighbors, got %d: %+v", len(tc.want), len(got), got)
+			}
+			for i, n := range got {
+				if n.TrackableID != tc.want[i] {
+					t.Errorf("neighbor %d: expected %s, got %s", i, tc.want[i], n.TrackableID)
+				}
+				if math.Abs(n.Distance-tc.dists[i]) > 1e-9 {
+					t.Errorf("neighbor %d: expected distance %v, got %v", i, tc.dists[i], n.Distance)
+				}
+			}
+		})
+	}
+}
+
+func TestTrackerSpatialQueriesWGS84(t *testing.T) {
+	near := uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+	far := uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+
+	tr := NewTracker(WithSpatialIndex(CrsWGS84))
+	tr.Update(
+		trackedLocation(near, CrsWGS84, 8.6821, 50.1110, "2024-03-01T08:00:00Z"),
+		trackedLocation(far, CrsWGS84, 8.6821, 50.1210, "2024-03-01T08:00:00Z"),
+	)
+
+	got := tr.WithinRadius(geometry.Point{X: 8.6821, Y: 50.1109}, 50)
+	if len(got) != 1 || got[0].TrackableID != near {
+		t.Fatalf("expected only the near trackable, got %+v", got)
+	}
+	if d := got[0].Distance; math.Abs(d-11.1) > 0.5 {
+		t.Errorf("expected a distance of about 11m, got %v", d)
+	}
+}
+
+func TestTrackerWithinBounds(t *testing.T) {
+	a := uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+	b := uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+	c := uuid.MustParse("00000000-0000-0000-0000-00000000000c")
+
+	for _, opts := range [][]TrackerOption{nil, {WithSpatialIndex(CrsWGS84)}} {
+		tr := NewTracker(opts...)
+		tr.Update(
+			trackedLocation(b, CrsWGS84, 8.6821, 50.1110, "2024-03-01T08:00:00Z"),
+			trackedLocation(a, CrsWGS84, 8.6831, 50.1115, "2024-03-01T08:00:00Z"),
+			trackedLocation(c, CrsWGS84, 8.7, 50.2, "2024-03-01T08:00:00Z"),
+			trackedLocation(uuid.New(), CrsLocal, 8.6821, 50.1110, "2024-03-01T08:00:00Z"),
+		)
+
+		got := tr.WithinBounds(CrsWGS84, geometry.Point{X: 8.68, Y: 50.11}, geometry.Point{X: 8.69, Y: 50.12})
+		if len(got) != 2 || got[0].TrackableID != a || got[1].TrackableID != b {
+			t.Errorf("expected trackables a and b, got %+v", got)
+		}
+	}
+}
+
+func TestTrackerWithoutSpatialIndex(t *testing.T) {
+	tr := NewTracker()
+	tr.Update(trackedLocation(uuid.New(), CrsLocal, 0, 0, "2024-03-01T08:00:00Z"))
+
+	if got := tr.Nearest(geometry.Point{}, 1); got != nil {
+		t.Errorf("expected no neighbors, got %+v", got)
+	}
+}
+
+func TestTrackerSaveLoad(t *testing.T) {
+	id := uuid.MustParse("d27047bd-1b6b-4656-bb93-2326a4c900e1")
+	path := filepath.Join(t.TempDir(), "tracker.json")
+
+	tr := NewTracker()
+	tr.Update(trackedLocation(id, CrsLocal, 3, 4, "2024-03-01T08:00:00Z"))
+	if err := tr.Save(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	restored := NewTracker(WithSpatialIndex(CrsLocal))
+
+	// newer updates received before loading are kept
+	other := uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+	restored.Update(trackedLocation(other, CrsLocal, 1, 1, "2024-03-01T08:00:00Z"))
+
+	if err := restored.Load(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	p, ok := restored.Position(id)
+	if !ok {
+		t.Fatal("expected the position to be restored")
+	}
+	if !p.At.Equal(*mustParseTime("2024-03-01T08:00:00Z")) || p.Location.ProviderID != "p-d270" {
+		t.Errorf("unexpected restored position: %+v", p)
+	}
+
+	// restored positions are indexed
+	got := restored.Nearest(geometry.Point{}, 2)
+	if len(got) != 2 || got[0].TrackableID != other || got[1].TrackableID != id || got[1].Distance != 5 {
+		t.Errorf("unexpected neighbors: %+v", got)
+	}
+
+	if err := NewTracker().Load(filepath.Join(t.TempDir(), "missing.json")); err != nil {
+		t.Errorf("expected a missing snapshot to be ignored, got %v", err)
+	}
+}
+
+func TestTrackerClock(t *testing.T) {
+	id := uuid.New()
+	clk := clock.NewFake(*mustParseTime("2024-03-01T08:00:00Z"))
+
+	tr := NewTracker(WithTrackerClock(clk))
+
+	// locations without timestamp are recorded at the time of the clock
+	loc := trackedLocation(id, CrsLocal, 1, 1, "2024-03-01T08:00:00Z")
+	loc.TimestampGenerated = nil
+
+	clk.Advance(time.Minute)
+	tr.Update(loc)
+
+	p, _ := tr.Position(id)
+	if want := *mustParseTime("2024-03-01T08:01:00Z"); !p.At.Equal(want) {
+		t.Errorf("expected position at %v, got %v", want, p.At)
+	}
+
+	clk.Advance(time.Hour)
+	if got, want := tr.Snapshot().TakenAt, *mustParseTime("2024-03-01T09:01:00Z"); !got.Equal(want) {
+		t.Errorf("expected snapshot taken at %v, got %v", want, got)
+	}
+}
+
+func TestTrackerPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tracker.json")
+
+	tr := NewTracker()
+	tr.Update(trackedLocation(uuid.New(), CrsLocal, 0, 0, "2024-03-01T08:00:00Z"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := tr.Persist(ctx, path, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled, got %v", err)
+	}
+
+	// the state is saved when the context is done
+	restored := NewTracker()
+	if err := restored.Load(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(restored.Positions()); n != 1 {
+		t.Errorf("expected 1 restored position, got %d", n)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"math"
+
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go/internal/geo"
+)
+
+// Ellipse is a horizontal uncertainty ellipse around a position.
+type Ellipse struct {
+	// SemiMajor is the length of the semi-major axis in meters.
+	SemiMajor float64 `json:"semi_major"`
+
+	// SemiMinor is the length of the semi-minor axis in meters.
+	SemiMinor float64 `json:"semi_minor"`
+
+	// Orientation is the angle of the semi-major axis in degrees, counterclockwise from the x axis
+	// (east for WGS84 positions).
+	Orientation float64 `json:"orientation"`
+}
+
+// Uncertainty returns the horizontal uncertainty ellipse of the location for the given
+// confidence level (e.g. 0.95), between 0 and 1 exclusive.
+//
+// The ellipse is computed from the covariance matrix if present. Otherwise, the accuracy
+// is used as the radius of a circle, regardless of the confidence level. If the location
+// has neither, false is returned.
+func (l Location) Uncertainty(confidence float64) (Ellipse, bool) {
+	if confidence <= 0 || confidence >= 1 {
+		return Ellipse{}, false
+	}
+
+	if c := l.Covariance; len(c) >= 2 && len(c[0]) >= 2 && len(c[1]) >= 2 {
+		xx, xy, yy := c[0][0], (c[0][1]+c[1][0])/2, c[1][1]
+
+		// eigenvalues of the symmetric 2x2 covariance matrix
+		mean := (xx + yy) / 2
+		diff := math.Hypot((xx-yy)/2, xy)
+		major, minor := mean+diff, mean-diff
+		if minor < 0 || math.IsNaN(major) {
+			return Ellipse{}, false
+		}
+
+		// scale of the 1-sigma ellipse for the confidence level (chi-squared with 2 degrees of freedom)
+		k := math.Sqrt(-2 * math.Log(1-confidence))
+
+		return Ellipse{
+			SemiMajor:   k * math.Sqrt(major),
+			SemiMinor:   k * math.Sqrt(minor),
+			Orientation: math.Atan2(2*xy, xx-yy) / 2 * 180 / math.Pi,
+		}, true
+	}
+
+	if l.Accuracy != nil && *l.Accuracy >= 0 {
+		return Ellipse{SemiMajor: *l.Accuracy, SemiMinor: *l.Accuracy}, true
+	}
+
+	return Ellipse{}, false
+}
+
+// Ring returns the closed ring of the ellipse centered on the given position, approximated
+// with the given number of segments. Positions in WGS84 (EPSG:4326) have the ellipse axes
+// converted from meters to degrees, other crs are assumed to be in meters.
+func (e Ellipse) Ring(center geometry.Point, crs string, segments int) []geometry.Point {
+	segments = max(segments, 3)
+
+	sx, sy := 1.0, 1.0
+	if crs == CrsWGS84 {
+		sy = 1 / geo.MetersPerDegree
+		sx = sy
+		if cos := math.Cos(center.Y * math.Pi / 180); cos > 1e-9 {
+			sx = sy / cos
+		}
+	}
+
+	theta := e.Orientation * math.Pi / 180
+	sin, cos := math.Sin(theta), math.Cos(theta)
+
+	ring := make([]geometry.Point, 0, segments+1)
+	for i := 0; i < segments; i++ {
+		a := 2 * math.Pi * float64(i) / float64(segments)
+		u, v := e.SemiMajor*math.Cos(a), e.SemiMinor*math.Sin(a)
+
+		ring = append(ring, geometry.Point{
+			X: center.X + (u*cos-v*sin)*sx,
+			Y: center.Y + (u*sin+v*cos)*sy,
+		})
+	}
+
+	return append(ring, ring[0])
+}
+
+// Polygon returns the ellipse centered on the given position as a polygon.
+// See Ring for details.
+func (e Ellipse) Polygon(center geometry.Point, crs string, segments int) *Polygon {
+	return NewPolygon(geometry.NewPoly(e.Ring(center, crs, segments), nil, nil))
+}
@@ -0,0 +1,79 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+func TestLocationUncertainty(t *testing.T) {
+	// 95% confidence scale for 2 degrees of freedom
+	k := math.Sqrt(-2 * math.Log(0.05))
+
+	tests := []struct {
+		name     string
+		location Location
+		want     Ellipse
+		ok       bool
+	}{
+		{
+			name: "none",
+		},
+		{
+			name:     "accuracy",
+			location: Location{Accuracy: opt(3.0)},
+			want:     Ellipse{SemiMajor: 3, SemiMinor: 3},
+			ok:       true,
+		},
+		{
+			name:     "axis-aligned-covariance",
+			location: Location{Accuracy: opt(3.0), Covariance: [][]float64{{1, 0}, {0, 4}}},
+			want:     Ellipse{SemiMajor: 2 * k, SemiMinor: k, Orientation: 90},
+			ok:       true,
+		},
+		{
+			name:     "rotated-covariance",
+			location: Location{Covariance: [][]float64{{2, 1, 0}, {1, 2, 0}, {0, 0, 1}}},
+			want:     Ellipse{SemiMajor: math.Sqrt(3) * k, SemiMinor: k, Orientation: 45},
+			ok:       true,
+		},
+		{
+			name:     "invalid-covariance",
+			location: Location{Covariance: [][]float64{{1, 2}, {2, 1}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.location.Uncertainty(0.95)
+			if ok != tt.ok {
+				t.Fatalf("expected ok=%v, got %v", tt.ok, ok)
+			}
+
+			const eps = 1e-9
+			if math.Abs(got.SemiMajor-tt.want.SemiMajor) > eps ||
+				math.Abs(got.SemiMinor-tt.want.SemiMinor) > eps ||
+				math.Abs(got.Orientation-tt.want.Orientation) > eps {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEllipseRing(t *testing.T) {
+	e := Ellipse{SemiMajor: 2, SemiMinor: 1, Orientation: 90}
+	ring := e.Ring(geometry.Point{X: 10, Y: 10}, CrsLocal, 4)
+
+	if len(ring) != 5 || ring[0] != ring[4] {
+		t.Fatalf("expected closed ring of 5 points, got %v", ring)
+	}
+
+	// the semi-major axis points north
+	if math.Abs(ring[0].X-10) > 1e-9 || math.Abs(ring[0].Y-12) > 1e-9 {
+		t.Errorf("expected first point at (10, 12), got %v", ring[0])
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// withTLSSessionCache returns a copy of the HTTP client resuming TLS sessions from a cache of the given size.
+func withTLSSessionCache(client *http.Client, size int) (*http.Client, error) {
+	transport, err := httpTransport(client)
+	if err != nil {
+		return nil, err
+	}
+
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.ClientSessionCache = tls.NewLRUClientSessionCache(size)
+
+	c := *client
+	c.Transport = transport
+	return &c, nil
+}
+
+// withTLSConfig returns a copy of the HTTP client with the TLS configuration.
+func withTLSConfig(client *http.Client, config *tls.Config) (*http.Client, error) {
+	transport, err := httpTransport(client)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = config.Clone()
+
+	c := *client
+	c.Transport = transport
+	return &c, nil
+}
+
+// Warm pre-establishes n connections to the Hub, kept idle in the HTTP client pool,
+// so the next requests do not pay the connection and TLS handshake latency.
+// Hubs speaking HTTP/2 multiplex the requests over a single connection.
+func (c *Client) Warm(ctx context.Context, n int) error {
+	if n <= 0 {
+		return fmt.Errorf("invalid number of connections to warm: %d", n)
+	}
+
+	if t, ok := c.client.Transport.(*http.Transport); ok && t.MaxIdleConnsPerHost > 0 && t.MaxIdleConnsPerHost < n {
+		return fmt.Errorf("cannot keep %d idle connections, the transport keeps at most %d per host", n, t.MaxIdleConnsPerHost)
+	}
+
+	// concurrent requests open a connection each, returned to the pool once done
+	errg, ctx := errgroup.WithContext(ctx)
+	for i := 0; i < n; i++ {
+		errg.Go(func() error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodHead, c.baseAddress.String(), nil)
+			if err != nil {
+				return err
+			}
+
+			resp, err := c.client.Do(req)
+			if err != nil {
+				return err
+			}
+
+			// the response must be read to the end for the connection to be reused
+			io.Copy(io.Discard, resp.Body)
+			return resp.Body.Close()
+		})
+	}
+
+	return errg.Wait()
+}
+
+// KeepWarm warms n connections to the Hub at every interval, which must be shorter
+// than the idle connection timeout of the transport, until the context is done.
+func (c *Client) KeepWarm(ctx context.Context, n int, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid keep warm interval: %v", interval)
+	}
+
+	ticker := c.timeSource().NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// failures are transient, the connections are warmed again on the next tick
+		_ = c.Warm(ctx, n)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWarm(t *testing.T) {
+	var (
+		conns   atomic.Int32
+		resumed atomic.Bool
+	)
+
+	// requests are held until every warming request arrived, forcing a connection each
+	var wg sync.WaitGroup
+	wg.Add(3)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			wg.Done()
+			wg.Wait()
+		}
+		if r.TLS != nil && r.TLS.DidResume {
+			resumed.Store(true)
+		}
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	c, err := New(srv.URL, WithHTTPClient(srv.Client()), WithTLSSessionResumption(8))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := c.Warm(context.Background(), 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := conns.Load(); n != 3 {
+		t.Fatalf("expected 3 warm connections, got %d", n)
+	}
+
+	// later connections resume the TLS sessions
+	c.client.Transport.(*http.Transport).CloseIdleConnections()
+
+	resp, err := c.client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if !resumed.Load() {
+		t.Error("expected the TLS session to be resumed")
+	}
+
+	if err := c.Warm(context.Background(), 0); err == nil {
+		t.Error("expected an error warming no connections")
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// Webhook defines model for a callback URL registered to the Hub, to which the
+// Hub posts the events of the subscribed topics.
+//
+// Webhooks are not part of the omlox™ Hub API: they are only available on Hubs
+// advertising the webhooks extension.
+//
+//easyjson:json
+type Webhook struct {
+	// The webhook unique identifier. When registering a webhook, a unique id
+	// will be generated by the Hub if it is not provided.
+	ID uuid.UUID `json:"id"`
+
+	// The callback URL the events are posted to.
+	URL string `json:"url"`
+
+	// The topics of the posted events, such as fence_events.
+	Topics []Topic `json:"topics"`
+
+	// The topic parameters, filtering the posted events (e.g. by fence_id).
+	Params Parameters `json:"params,omitempty"`
+
+	// An optional secret, with which the Hub signs the posted events.
+	Secret string `json:"secret,omitempty"`
+
+	// Any additional application or vendor specific properties. An application implementing this object is not required to interpret
+	// any of the custom properties, but it MUST preserve the properties if set.
+	Properties json.RawMessage `json:"properties,omitempty"`
+}
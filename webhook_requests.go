@@ -0,0 +1,89 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// WebhooksAPI is a simple wrapper around the client for webhooks requests.
+// Webhooks are only available on Hubs advertising the webhooks extension.
+type WebhooksAPI struct {
+	client *Client
+
+	service *Service[Webhook]
+}
+
+// List lists all webhooks.
+func (c *WebhooksAPI) List(ctx context.Context) ([]Webhook, error) {
+	if err := c.client.require(ctx, FeatureWebhooks); err != nil {
+		return nil, err
+	}
+
+	return c.service.List(ctx)
+}
+
+// Create creates a webhook.
+func (c *WebhooksAPI) Create(ctx context.Context, webhook Webhook) (*Webhook, error) {
+	if err := c.client.require(ctx, FeatureWebhooks); err != nil {
+		return nil, err
+	}
+
+	return c.service.Create(ctx, webhook)
+}
+
+// Get gets a webhook.
+func (c *WebhooksAPI) Get(ctx context.Context, id uuid.UUID) (*Webhook, error) {
+	if err := c.client.require(ctx, FeatureWebhooks); err != nil {
+		return nil, err
+	}
+
+	return c.service.Get(ctx, id.String())
+}
+
+// Update updates a webhook.
+func (c *WebhooksAPI) Update(ctx context.Context, webhook Webhook, id uuid.UUID) error {
+	if err := c.client.require(ctx, FeatureWebhooks); err != nil {
+		return err
+	}
+
+	return c.service.Update(ctx, webhook, id.String())
+}
+
+// Delete deletes a webhook.
+func (c *WebhooksAPI) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := c.client.require(ctx, FeatureWebhooks); err != nil {
+		return err
+	}
+
+	return c.service.Delete(ctx, id.String())
+}
+
+// Register registers the webhook of a service at startup. A webhook already
+// registered with the same URL, such as by a previous run of the service which
+// did not clean up, is updated instead of registered twice.
+//
+// The returned webhook is meant to be deleted on shutdown.
+func (c *WebhooksAPI) Register(ctx context.Context, webhook Webhook) (*Webhook, error) {
+	webhooks, err := c.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, w := range webhooks {
+		if w.URL != webhook.URL {
+			continue
+		}
+
+		webhook.ID = w.ID
+		if err := c.service.Update(ctx, webhook, w.ID.String()); err != nil {
+			return nil, err
+		}
+		return &webhook, nil
+	}
+
+	return c.service.Create(ctx, webhook)
+}
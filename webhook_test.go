@@ -0,0 +1,106 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestWebhooksRegister(t *testing.T) {
+	tests := []struct {
+		name       string
+		registered string
+		id         uuid.UUID
+		requests   []string
+	}{
+		{
+			name:       "new",
+			registered: `[]`,
+			requests:   []string{"GET /v2/info", "GET /v2/webhooks/summary", "POST /v2/webhooks"},
+		},
+		{
+			name:       "already registered",
+			registered: `[{"id":"5a8a8ca4-4a5a-4b0f-9d5a-0d1a8b2ff0d1","url":"http://svc/events","topics":["location_updates"]}]`,
+			id:         uuid.MustParse("5a8a8ca4-4a5a-4b0f-9d5a-0d1a8b2ff0d1"),
+			requests:   []string{"GET /v2/info", "GET /v2/webhooks/summary", "PUT /v2/webhooks/5a8a8ca4-4a5a-4b0f-9d5a-0d1a8b2ff0d1"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests []string
+
+			httpClient := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					requests = append(requests, r.Method+" "+r.URL.Path)
+
+					body := `{"version":"1.1.0","extensions":["webhooks"]}`
+					switch r.Method {
+					case http.MethodGet:
+						if strings.HasSuffix(r.URL.Path, "/summary") {
+							body = tc.registered
+						}
+					case http.MethodPost:
+						b, _ := io.ReadAll(r.Body)
+						body = string(b)
+					default:
+						body = ``
+					}
+
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(body)),
+					}, nil
+				}),
+			}
+
+			c, err := New("http://localhost:8081/v2", WithHTTPClient(httpClient))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			w, err := c.Webhooks.Register(context.Background(), Webhook{
+				URL:    "http://svc/events",
+				Topics: []Topic{TopicFenceEvents},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if w.ID != tc.id || w.URL != "http://svc/events" {
+				t.Errorf("unexpected webhook %+v", w)
+			}
+
+			if strings.Join(requests, "\n") != strings.Join(tc.requests, "\n") {
+				t.Errorf("unexpected requests:\n%s\nwanted:\n%s", strings.Join(requests, "\n"), strings.Join(tc.requests, "\n"))
+			}
+		})
+	}
+}
+
+func TestWebhooksNotSupported(t *testing.T) {
+	httpClient := &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"version":"1.1.0","extensions":["history"]}`)),
+			}, nil
+		}),
+	}
+
+	c, err := New("http://localhost:8081/v2", WithHTTPClient(httpClient))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Webhooks.List(context.Background()); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("expected %v, got %v", ErrNotSupported, err)
+	}
+}
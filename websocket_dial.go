@@ -0,0 +1,22 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+
+package omlox
+
+import (
+	"context"
+	"net/http"
+
+	"nhooyr.io/websocket"
+)
+
+// dialWebsocket opens a websocket connection with the HTTP client, sending the header
+// with the handshake.
+func dialWebsocket(ctx context.Context, url string, httpClient *http.Client, header http.Header) (*websocket.Conn, *http.Response, error) {
+	return websocket.Dial(ctx, url, &websocket.DialOptions{
+		HTTPClient: httpClient,
+		HTTPHeader: header,
+	})
+}
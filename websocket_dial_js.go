@@ -0,0 +1,22 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+//go:build js
+
+package omlox
+
+import (
+	"context"
+	"net/http"
+
+	"nhooyr.io/websocket"
+)
+
+// dialWebsocket opens a websocket connection with the WebSocket API of the browser.
+//
+// Browsers neither let the handshake through custom HTTP clients nor send custom headers
+// with it, so the HTTP client and the header are ignored: the connection is authenticated
+// by the cookies of the Hub origin, if any, such as the session of a reverse proxy.
+func dialWebsocket(ctx context.Context, url string, _ *http.Client, _ http.Header) (*websocket.Conn, *http.Response, error) {
+	return websocket.Dial(ctx, url, nil)
+}
@@ -0,0 +1,166 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/wavecomtech/omlox-client-go/internal/geo"
+)
+
+// Zone defines model for Zone.
+//
+//easyjson:json
+type Zone struct {
+	// Must be a UUID. When creating a zone, a unique id will be generated if it is not provided.
+	ID uuid.UUID `json:"id"`
+
+	// The type of the zone, matching the location providers of the RTLS system (e.g. uwb).
+	Type LocationProviderType `json:"type"`
+
+	// A foreign unique identifier of the zone, such as the id of the zone in the RTLS system.
+	ForeignID string `json:"foreign_id,omitempty"`
+
+	// A describing name.
+	Name string `json:"name,omitempty"`
+
+	// A description of the zone.
+	Description string `json:"description,omitempty"`
+
+	// The building floor of the zone. Floor 0 represents the floor designated as 'ground'.
+	Floor float64 `json:"floor,omitempty"`
+
+	// The position of the zone, as a GeoJson Point in WGS84.
+	Position *Point `json:"position,omitempty"`
+
+	// The radius of the zone around its position in meters.
+	Radius float64 `json:"radius,omitempty"`
+
+	// The ground control points relating the local coordinates of the zone to WGS84 coordinates.
+	GroundControlPoints []GroundControlPoint `json:"ground_control_points,omitempty"`
+
+	// Whether the zone configuration, such as the ground control points, is incomplete.
+	IncompleteConfiguration bool `json:"incomplete_configuration,omitempty"`
+
+	// The timestamp when the ground control points were measured.
+	MeasurementTimestamp *time.Time `json:"measurement_timestamp,omitempty"`
+
+	// Any additional application or vendor specific properties.
+	// An application implementing this object is not required to interpret any of the custom properties,
+	// but it MUST preserve the properties if set.
+	Properties json.RawMessage `json:"properties,omitempty"`
+}
+
+// GroundControlPoint defines model for a point known both in WGS84 and local zone coordinates.
+//
+//easyjson:json
+type GroundControlPoint struct {
+	// The position of the point in WGS84 (longitude, latitude).
+	WGS84 Point `json:"wgs84"`
+
+	// The position of the point in local zone coordinates in meters.
+	Local Point `json:"local"`
+}
+
+// Georeference converts between local zone coordinates and WGS84 coordinates.
+type Georeference struct {
+	// origin of the local tangent plane the WGS84 coordinates are projected to
+	lon, lat float64
+
+	toPlane   Affine
+	fromPlane Affine
+}
+
+// Georeference returns the conversion between the local and WGS84 coordinates of the zone,
+// computed from its ground control points. At least two ground control points are required.
+//
+// WGS84 coordinates are projected to a plane tangent to the zone, which is accurate for the
+// extent of a building or site.
+func (z Zone) Georeference() (*Georeference, error) {
+	if len(z.GroundControlPoints) < 2 {
+		return nil, fmt.Errorf("zone %s requires at least two ground control points, got %d", z.ID, len(z.GroundControlPoints))
+	}
+
+	g := &Georeference{}
+	for _, gcp := range z.GroundControlPoints {
+		p := gcp.WGS84.Base()
+		g.lon += p.X / float64(len(z.GroundControlPoints))
+		g.lat += p.Y / float64(len(z.GroundControlPoints))
+	}
+
+	local := make([]geometry.Point, len(z.GroundControlPoints))
+	plane := make([]geometry.Point, len(z.GroundControlPoints))
+	for i, gcp := range z.GroundControlPoints {
+		local[i] = gcp.Local.Base()
+		plane[i] = g.project(gcp.WGS84.Base())
+	}
+
+	var err error
+	if g.toPlane, err = FitAffine(local, plane); err != nil {
+		return nil, fmt.Errorf("invalid ground control points of zone %s: %w", z.ID, err)
+	}
+	if g.fromPlane, err = g.toPlane.Invert(); err != nil {
+		return nil, fmt.Errorf("invalid ground control points of zone %s: %w", z.ID, err)
+	}
+
+	return g, nil
+}
+
+// ZoneTransform is the transformation between the local coordinates of a zone and WGS84,
+// as an affine transformation of the local coordinates to a plane tangent to the zone.
+type ZoneTransform struct {
+	// The longitude of the origin of the tangent plane in WGS84.
+	Longitude float64 `json:"longitude"`
+
+	// The latitude of the origin of the tangent plane in WGS84.
+	Latitude float64 `json:"latitude"`
+
+	// The affine transformation of the local coordinates to the tangent plane, in meters east
+	// and north of the origin.
+	Matrix Affine `json:"matrix"`
+}
+
+// Transform returns the transformation between the local and WGS84 coordinates of the zone,
+// computed from its ground control points as by Georeference.
+func (z Zone) Transform() (*ZoneTransform, error) {
+	g, err := z.Georeference()
+	if err != nil {
+		return nil, err
+	}
+	return &ZoneTransform{Longitude: g.lon, Latitude: g.lat, Matrix: g.toPlane}, nil
+}
+
+// Georeference returns the conversion between the local and WGS84 coordinates of the
+// transformation.
+func (t ZoneTransform) Georeference() (*Georeference, error) {
+	fromPlane, err := t.Matrix.Invert()
+	if err != nil {
+		return nil, fmt.Errorf("invalid zone transformation: %w", err)
+	}
+	return &Georeference{lon: t.Longitude, lat: t.Latitude, toPlane: t.Matrix, fromPlane: fromPlane}, nil
+}
+
+// project projects WGS84 coordinates to the local tangent plane, in meters east and north of the origin.
+func (g *Georeference) project(p geometry.Point) geometry.Point {
+	return geo.Project(geometry.Point{X: g.lon, Y: g.lat}, p)
+}
+
+// unproject converts coordinates of the local tangent plane back to WGS84.
+func (g *Georeference) unproject(p geometry.Point) geometry.Point {
+	return geo.Unproject(geometry.Point{X: g.lon, Y: g.lat}, p)
+}
+
+// ToWGS84 converts local zone coordinates to WGS84 (longitude, latitude).
+func (g *Georeference) ToWGS84(p geometry.Point) geometry.Point {
+	return g.unproject(g.toPlane.Apply(p))
+}
+
+// ToLocal converts WGS84 (longitude, latitude) coordinates to local zone coordinates.
+func (g *Georeference) ToLocal(p geometry.Point) geometry.Point {
+	return g.fromPlane.Apply(g.project(p))
+}
@@ -0,0 +1,89 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// ZonesAPI is a simple wrapper around the client for zones requests.
+type ZonesAPI struct {
+	client *Client
+
+	service *Service[Zone]
+}
+
+// List lists all zones.
+func (c *ZonesAPI) List(ctx context.Context) ([]Zone, error) {
+	return c.service.List(ctx)
+}
+
+// IDs lists all zone IDs.
+func (c *ZonesAPI) IDs(ctx context.Context) ([]uuid.UUID, error) {
+	return listIDs[uuid.UUID](ctx, c.service)
+}
+
+// Create creates a zone.
+func (c *ZonesAPI) Create(ctx context.Context, zone Zone) (*Zone, error) {
+	return c.service.Create(ctx, zone)
+}
+
+// DeleteAll deletes all zones.
+func (c *ZonesAPI) DeleteAll(ctx context.Context) error {
+	return c.service.DeleteAll(ctx)
+}
+
+// Get gets a zone.
+func (c *ZonesAPI) Get(ctx context.Context, id uuid.UUID) (*Zone, error) {
+	return c.service.Get(ctx, id.String())
+}
+
+// Delete deletes a zone.
+func (c *ZonesAPI) Delete(ctx context.Context, id uuid.UUID) error {
+	return c.service.Delete(ctx, id.String())
+}
+
+// Update updates a zone.
+func (c *ZonesAPI) Update(ctx context.Context, zone Zone, id uuid.UUID) error {
+	return c.service.Update(ctx, zone, id.String())
+}
+
+// GetTransform gets the transformation between the local coordinates of a zone and WGS84,
+// as computed by the Hub from the ground control points of the zone.
+func (c *ZonesAPI) GetTransform(ctx context.Context, id uuid.UUID) (*ZoneTransform, error) {
+	requestPath := "/zones/" + id.String() + "/transform"
+
+	return sendRequestParseResponse[ZoneTransform](
+		ctx,
+		c.client,
+		http.MethodGet,
+		requestPath,
+		nil, // request body
+		nil, // request query parameters
+		nil, // request headers
+	)
+}
+
+// Georeference gets the conversion between the local coordinates of a zone and WGS84.
+//
+// The transformation of the Hub is used where available. Otherwise, the zone is fetched
+// and the conversion is computed client-side from its ground control points.
+func (c *ZonesAPI) Georeference(ctx context.Context, id uuid.UUID) (*Georeference, error) {
+	transform, err := c.GetTransform(ctx, id)
+	if err == nil {
+		return transform.Georeference()
+	}
+	if !isEndpointUnavailable(err) {
+		return nil, err
+	}
+
+	zone, err := c.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return zone.Georeference()
+}
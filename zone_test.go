@@ -0,0 +1,133 @@
+// Copyright (c) Omlox Client Go Contributors
+// SPDX-License-Identifier: MIT
+
+package omlox
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/tidwall/geojson/geometry"
+)
+
+const zoneJSON = `{
+		"id": "2b2fa2f6-49bb-4e6c-8e2e-4a3b8a63c1b1",
+		"type": "uwb",
+		"name": "hall",
+		"ground_control_points": [
+			{"local": {"type": "Point", "coordinates": [0, 0]}, "wgs84": {"type": "Point", "coordinates": [-8.6291, 41.1579]}},
+			{"local": {"type": "Point", "coordinates": [100, 0]}, "wgs84": {"type": "Point", "coordinates": [-8.627906, 41.1579]}},
+			{"local": {"type": "Point", "coordinates": [0, 100]}, "wgs84": {"type": "Point", "coordinates": [-8.6291, 41.158798]}}
+		]
+	}`
+
+func TestZoneGeoreference(t *testing.T) {
+	var zone Zone
+	err := json.Unmarshal([]byte(zoneJSON), &zone)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := zone.Georeference()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, gcp := range zone.GroundControlPoints {
+		got := g.ToWGS84(gcp.Local.Base())
+		if want := gcp.WGS84.Base(); math.Abs(got.X-want.X) > 1e-6 || math.Abs(got.Y-want.Y) > 1e-6 {
+			t.Errorf("expected %v to convert to %v, got %v", gcp.Local.Base(), want, got)
+		}
+	}
+
+	p := geometry.Point{X: 42, Y: 17}
+	if q := g.ToLocal(g.ToWGS84(p)); math.Abs(q.X-p.X) > 1e-6 || math.Abs(q.Y-p.Y) > 1e-6 {
+		t.Errorf("expected %v to convert back, got %v", p, q)
+	}
+
+	zone.GroundControlPoints = zone.GroundControlPoints[:1]
+	if _, err := zone.Georeference(); err == nil {
+		t.Error("expected error for a single ground control point")
+	}
+}
+
+func TestZonesGeoreference(t *testing.T) {
+	var zone Zone
+	if err := json.Unmarshal([]byte(zoneJSON), &zone); err != nil {
+		t.Fatal(err)
+	}
+	transform, err := zone.Transform()
+	if err != nil {
+		t.Fatal(err)
+	}
+	transformJSON, err := json.Marshal(transform)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		endpoint bool
+		requests []string
+	}{
+		{
+			name:     "hub transform",
+			endpoint: true,
+			requests: []string{"/v2/zones/2b2fa2f6-49bb-4e6c-8e2e-4a3b8a63c1b1/transform"},
+		},
+		{
+			name:     "endpoint not found",
+			requests: []string{"/v2/zones/2b2fa2f6-49bb-4e6c-8e2e-4a3b8a63c1b1/transform", "/v2/zones/2b2fa2f6-49bb-4e6c-8e2e-4a3b8a63c1b1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+
+			httpClient := &http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					requests = append(requests, r.URL.Path)
+
+					status, body := http.StatusOK, zoneJSON
+					if strings.HasSuffix(r.URL.Path, "/transform") {
+						body = string(transformJSON)
+						if !tt.endpoint {
+							status, body = http.StatusNotFound, `{"type":"not_found","code":404}`
+						}
+					}
+
+					return &http.Response{
+						StatusCode: status,
+						Body:       io.NopCloser(strings.NewReader(body)),
+					}, nil
+				}),
+			}
+
+			c, err := New("http://localhost:8081/v2", WithHTTPClient(httpClient))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			g, err := c.Zones.Georeference(context.Background(), zone.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, gcp := range zone.GroundControlPoints {
+				got := g.ToWGS84(gcp.Local.Base())
+				if want := gcp.WGS84.Base(); math.Abs(got.X-want.X) > 1e-6 || math.Abs(got.Y-want.Y) > 1e-6 {
+					t.Errorf("expected %v to convert to %v, got %v", gcp.Local.Base(), want, got)
+				}
+			}
+
+			if strings.Join(requests, " ") != strings.Join(tt.requests, " ") {
+				t.Errorf("expected requests %v, got %v", tt.requests, requests)
+			}
+		})
+	}
+}